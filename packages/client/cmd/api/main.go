@@ -13,6 +13,7 @@ import (
 
 	"github.com/dnd-mcp/client/internal/api"
 	"github.com/dnd-mcp/client/internal/api/handler"
+	"github.com/dnd-mcp/client/internal/auth"
 	"github.com/dnd-mcp/client/internal/llm"
 	"github.com/dnd-mcp/client/internal/mcp"
 	"github.com/dnd-mcp/client/internal/models"
@@ -22,6 +23,7 @@ import (
 	"github.com/dnd-mcp/client/internal/service"
 	"github.com/dnd-mcp/client/internal/store/postgres"
 	"github.com/dnd-mcp/client/internal/store/redis"
+	"github.com/dnd-mcp/client/internal/ws"
 	"github.com/dnd-mcp/client/pkg/config"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -139,13 +141,28 @@ func main() {
 	// 注意：adminHandler 目前未在路由中使用，但保留以备将来使用
 	_ = adminHandler
 
-	// 初始化 LLM 和 MCP 客户端
-	llmClient, err := llm.NewClient(&cfg.LLM)
+	// 初始化 LLM 和 MCP 客户端（战役可以在设置中选择 LLM_PROVIDERS 列出的其他 provider）
+	llmPool := llm.NewClientPool(llm.DefaultRegistry, &cfg.LLM)
+
+	// 按战役限流，并把每次调用的 token 用量和费用计入系统统计
+	prices := make(map[string]monitor.ModelPrice, len(cfg.LLM.Prices))
+	for model, price := range cfg.LLM.Prices {
+		prices[model] = monitor.ModelPrice{Prompt: price.Prompt, Completion: price.Completion}
+	}
+	usageTracker := monitor.NewUsageTracker(prices)
+	statsMonitor.Register(usageTracker)
+	if cfg.LLM.CampaignRateLimit > 0 {
+		rateLimiter := llm.NewRateLimiter(cfg.LLM.CampaignRateLimit, cfg.LLM.CampaignRateBurst, time.Duration(cfg.LLM.Timeout)*time.Second)
+		llmPool.Use(llm.WithRateLimit(rateLimiter))
+	}
+	llmPool.Use(llm.WithUsageRecorder(usageTracker))
+
+	llmClient, err := llmPool.Get(cfg.LLM.Provider)
 	if err != nil {
 		log.Printf("⚠ 初始化 LLM 客户端失败: %v", err)
 		llmClient = nil
 	} else {
-		log.Println("✓ LLM 客户端初始化成功")
+		log.Printf("✓ LLM 客户端初始化成功 (provider: %s)", cfg.LLM.Provider)
 	}
 
 	mcpClient, err := mcp.NewClient(&cfg.MCP)
@@ -193,10 +210,53 @@ func main() {
 		log.Println("⚠ ContextBuilder 未初始化（Server 客户端缺失）")
 	}
 
+	// 创建 WebSocket Hub，并将 MCP Server 的游戏事件转发给会话连接
+	hub := ws.NewHub()
+	if mcpClient != nil {
+		hub.SetSessionObserver(mcp.NewEventRelay(context.Background(), mcpClient, hub))
+		log.Println("✓ 游戏事件转发已启用")
+	}
+
+	// 战役用量超过预算时暂停战役
+	budgetGuard := service.NewBudgetGuard(usageTracker, sessionService, service.UsageBudget{
+		MaxTokens: cfg.LLM.CampaignTokenBudget,
+		MaxCost:   cfg.LLM.CampaignCostBudget,
+	})
+	budgetGuard.SetBroadcaster(hub)
+
 	// 初始化 ChatService
 	var chatService service.ChatServiceInterface
 	if llmClient != nil && mcpClient != nil && serverClient != nil && contextBuilder != nil {
-		chatService = service.NewChatService(serverClient, llmClient, mcpClient, contextBuilder)
+		svc := service.NewChatService(serverClient, llmClient, mcpClient, contextBuilder)
+		svc.SetToolLoopConfig(service.ToolLoopConfig{
+			MaxIterations: cfg.LLM.MaxToolIterations,
+			MaxToolCalls:  cfg.LLM.MaxToolCalls,
+		})
+
+		// 启动时获取 Server 工具目录，失败时在首次对话时重试
+		toolCatalog := service.NewToolCatalog(serverClient, time.Duration(cfg.LLM.ToolCatalogTTL)*time.Second)
+		if err := toolCatalog.Refresh(context.Background()); err != nil {
+			log.Printf("⚠ 获取 Server 工具目录失败: %v", err)
+		} else {
+			log.Println("✓ Server 工具目录已加载")
+		}
+		svc.SetToolCatalog(toolCatalog)
+		svc.SetBroadcaster(hub)
+		svc.SetLLMPool(llmPool, sessionStore)
+		svc.SetBudgetGuard(budgetGuard)
+
+		// 同一战役的玩家消息排队，每次只运行一轮对话
+		turns := service.NewTurnCoordinator(svc, service.TurnConfig{
+			BatchWindow:    time.Duration(cfg.Turn.BatchWindow) * time.Millisecond,
+			MaxBatch:       cfg.Turn.MaxBatch,
+			MaxQueue:       cfg.Turn.MaxQueue,
+			InitiativeMode: cfg.Turn.InitiativeMode,
+		})
+		turns.SetCombatReader(serverClient)
+		turns.SetSessionGetter(sessionStore)
+		turns.SetBroadcaster(hub)
+
+		chatService = turns
 		log.Println("✓ ChatService 初始化成功")
 	} else {
 		log.Println("⚠ ChatService 未完全初始化（LLM、MCP 或 Server 客户端缺失）")
@@ -213,6 +273,17 @@ func main() {
 
 	// 创建系统处理器
 	systemHandler := handler.NewSystemHandler(persistenceTriggerer, healthMonitor, statsMonitor)
+	systemHandler.SetUsageReporter(budgetGuard)
+
+	// 启用认证时校验 API Key / JWT，未启用时不做限制
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		authenticator, err = auth.NewAuthenticator(&cfg.Auth)
+		if err != nil {
+			log.Fatalf("初始化认证失败: %v", err)
+		}
+		log.Println("✓ API 认证已启用")
+	}
 
 	// 创建 API 服务器
 	apiServer := api.NewServer(
@@ -222,8 +293,9 @@ func main() {
 		sessionStore,
 		messageStore,
 		serverClient,
-		nil, // hub
+		hub,
 		systemHandler,
+		authenticator,
 	)
 
 	// 启动服务器（goroutine）
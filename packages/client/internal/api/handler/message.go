@@ -2,9 +2,11 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/dnd-mcp/client/internal/llm"
 	"github.com/dnd-mcp/client/internal/server"
 	"github.com/dnd-mcp/client/internal/service"
 	"github.com/dnd-mcp/client/internal/store"
@@ -40,9 +42,10 @@ func NewMessageHandler(
 
 // SendMessageRequest 发送消息请求
 type SendMessageRequest struct {
-	Content  string `json:"content" binding:"required"`
-	PlayerID string `json:"player_id" binding:"required"`
-	Stream   bool   `json:"stream"`
+	Content    string `json:"content" binding:"required"`
+	PlayerID   string `json:"player_id" binding:"required"`
+	PlayerName string `json:"player_name"`
+	Stream     bool   `json:"stream"`
 }
 
 // SendMessage 发送消息并获取 AI 响应
@@ -64,8 +67,10 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 
 	// 调用 ChatService 处理业务逻辑
 	message, err := h.chatService.SendMessage(c.Request.Context(), sessionID, &service.SendMessageRequest{
-		Content:  req.Content,
-		PlayerID: req.PlayerID,
+		Content:    req.Content,
+		PlayerID:   req.PlayerID,
+		PlayerName: req.PlayerName,
+		Stream:     req.Stream,
 	})
 	if err != nil {
 		// 根据错误类型返回不同的 HTTP 状态码
@@ -73,6 +78,26 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "SESSION_NOT_FOUND", "message": err.Error()}})
 			return
 		}
+		if errors.Is(err, service.ErrCampaignPaused) {
+			c.JSON(http.StatusConflict, gin.H{"error": gin.H{"code": "CAMPAIGN_PAUSED", "message": err.Error()}})
+			return
+		}
+		if errors.Is(err, service.ErrNotYourTurn) {
+			c.JSON(http.StatusConflict, gin.H{"error": gin.H{"code": "NOT_YOUR_TURN", "message": err.Error()}})
+			return
+		}
+		if errors.Is(err, service.ErrTurnQueueFull) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": gin.H{"code": "TURN_QUEUE_FULL", "message": err.Error()}})
+			return
+		}
+		if errors.Is(err, llm.ErrRateLimited) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": gin.H{"code": "RATE_LIMITED", "message": err.Error()}})
+			return
+		}
+		if errors.Is(err, llm.ErrCircuitOpen) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": gin.H{"code": "LLM_UNAVAILABLE", "message": err.Error()}})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"code": "INTERNAL_ERROR", "message": err.Error()}})
 		return
 	}
@@ -152,6 +152,14 @@ func handleError(c *gin.Context, err error) {
 				"details": gin.H{},
 			},
 		})
+	case errors.Is(err, errors.ErrInvalidArgument):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_ARGUMENT",
+				"message": err.Error(),
+				"details": gin.H{},
+			},
+		})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": gin.H{
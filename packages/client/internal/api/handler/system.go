@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/dnd-mcp/client/internal/monitor"
+	"github.com/dnd-mcp/client/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,11 +15,17 @@ type PersistenceTriggerer interface {
 	Trigger(ctx context.Context) error
 }
 
+// UsageReporter 战役 LLM 用量报告接口（由 service.BudgetGuard 实现）
+type UsageReporter interface {
+	Report(ctx context.Context, campaignID string) (*service.UsageReport, error)
+}
+
 // SystemHandler 系统处理器
 type SystemHandler struct {
 	persistenceTriggerer PersistenceTriggerer
 	healthMonitor        *monitor.HealthMonitor
 	statsMonitor         *monitor.StatsMonitor
+	usageReporter        UsageReporter // 可选，未设置时用量接口返回 503
 }
 
 // NewSystemHandler 创建系统处理器
@@ -30,6 +37,11 @@ func NewSystemHandler(persistenceTriggerer PersistenceTriggerer, healthMonitor *
 	}
 }
 
+// SetUsageReporter 设置战役用量报告
+func (h *SystemHandler) SetUsageReporter(reporter UsageReporter) {
+	h.usageReporter = reporter
+}
+
 // Health 健康检查
 func (h *SystemHandler) Health(c *gin.Context) {
 	if h.healthMonitor == nil {
@@ -66,6 +78,26 @@ func (h *SystemHandler) Stats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// CampaignUsage 战役的 LLM 用量和预算
+func (h *SystemHandler) CampaignUsage(c *gin.Context) {
+	if h.usageReporter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": gin.H{
+				"code":    "USAGE_NOT_AVAILABLE",
+				"message": "用量统计未配置",
+			},
+		})
+		return
+	}
+
+	report, err := h.usageReporter.Report(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
 // TriggerPersistence 手动触发持久化
 func (h *SystemHandler) TriggerPersistence(c *gin.Context) {
 	if h.persistenceTriggerer == nil {
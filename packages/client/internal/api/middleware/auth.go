@@ -0,0 +1,74 @@
+// Package middleware 提供 HTTP 中间件
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dnd-mcp/client/internal/api/httperror"
+	"github.com/dnd-mcp/client/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// publicPaths 无需认证的路径
+var publicPaths = map[string]bool{
+	"/health":            true,
+	"/api/system/health": true,
+}
+
+// Auth 认证中间件
+// 凭证可通过 Authorization: Bearer、X-API-Key 或 token 查询参数(用于浏览器 WebSocket)传递。
+// 观察者只能发起 GET 请求(包括 WebSocket 握手)，删除操作仅限 DM。
+func Auth(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if publicPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		credential := ""
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			credential = strings.TrimSpace(token)
+		} else if key := c.GetHeader("X-API-Key"); key != "" {
+			credential = strings.TrimSpace(key)
+		} else {
+			credential = c.Query("token")
+		}
+
+		principal, err := authenticator.Authenticate(credential)
+		if err != nil {
+			message := "需要认证"
+			if errors.Is(err, auth.ErrTokenExpired) {
+				message = "令牌已过期"
+			}
+			c.Header("WWW-Authenticate", `Bearer realm="dnd-client"`)
+			httperror.Unauthorized(c, message)
+			c.Abort()
+			return
+		}
+
+		if !allowedMethod(principal, c.Request.Method) {
+			httperror.Forbidden(c, "当前角色无权执行此操作")
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// allowedMethod 判断角色能否使用请求方法
+func allowedMethod(principal *auth.Principal, method string) bool {
+	switch {
+	case method == http.MethodGet || method == http.MethodHead:
+		return true
+	case !principal.CanWrite():
+		return false
+	case method == http.MethodDelete:
+		return principal.Role == auth.RoleDM || principal.Role == auth.RoleSystem
+	default:
+		return true
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dnd-mcp/client/internal/api/handler"
 	"github.com/dnd-mcp/client/internal/api/middleware"
+	"github.com/dnd-mcp/client/internal/auth"
 	"github.com/dnd-mcp/client/internal/server"
 	"github.com/dnd-mcp/client/internal/service"
 	"github.com/dnd-mcp/client/internal/store"
@@ -30,6 +31,7 @@ type Server struct {
 	serverClient   server.ServerClient
 	hub            *ws.Hub
 	systemHandler  *handler.SystemHandler
+	authenticator  *auth.Authenticator
 }
 
 // NewServer 创建 HTTP 服务器
@@ -42,6 +44,7 @@ func NewServer(
 	serverClient server.ServerClient,
 	hub *ws.Hub,
 	systemHandler *handler.SystemHandler,
+	authenticator *auth.Authenticator,
 ) *Server {
 	// 设置 Gin 模式
 	if cfg.Log.Level == "debug" {
@@ -62,6 +65,7 @@ func NewServer(
 		serverClient:   serverClient,
 		hub:            hub,
 		systemHandler:  systemHandler,
+		authenticator:  authenticator,
 	}
 
 	// 设置中间件
@@ -80,6 +84,10 @@ func (s *Server) setupMiddleware() {
 	if s.config.HTTP.EnableCORS {
 		s.router.Use(middleware.CORS())
 	}
+	// authenticator 为 nil 表示未启用认证
+	if s.authenticator != nil {
+		s.router.Use(middleware.Auth(s.authenticator))
+	}
 }
 
 // setupRoutes 设置路由
@@ -114,6 +122,9 @@ func (s *Server) setupRoutes() {
 			sessions.GET("/:id/messages", messageHandler.GetMessages)
 			sessions.GET("/:id/messages/:messageId", messageHandler.GetMessage)
 
+			// LLM 用量和预算
+			sessions.GET("/:id/usage", s.systemHandler.CampaignUsage)
+
 			// WebSocket 广播测试路由（仅用于测试）
 			sessions.POST("/:id/broadcast", wsHandler.BroadcastMessage)
 		}
@@ -135,6 +146,9 @@ func (s *Server) setupRoutes() {
 			campaigns.GET("/:id/messages", messageHandler.GetMessages)
 			campaigns.GET("/:id/messages/:messageId", messageHandler.GetMessage)
 
+			// LLM 用量和预算
+			campaigns.GET("/:id/usage", s.systemHandler.CampaignUsage)
+
 			// WebSocket 广播测试路由
 			campaigns.POST("/:id/broadcast", wsHandler.BroadcastMessage)
 		}
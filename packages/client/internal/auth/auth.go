@@ -0,0 +1,171 @@
+// Package auth 提供 API 调用者的认证与身份传递
+// 凭证格式与 Server 一致：API Key 或 HS256 签名的 JWT
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dnd-mcp/client/pkg/config"
+)
+
+// Role 调用者角色
+type Role string
+
+// 角色
+const (
+	// RoleSystem 受信任的服务，等同于所有战役的 DM
+	RoleSystem Role = "system"
+	// RoleDM 主持战役
+	RoleDM Role = "dm"
+	// RolePlayer 只能通过自己的角色行动
+	RolePlayer Role = "player"
+	// RoleObserver 只读
+	RoleObserver Role = "observer"
+)
+
+// ParseRole 解析角色名称
+func ParseRole(s string) (Role, error) {
+	switch role := Role(strings.ToLower(strings.TrimSpace(s))); role {
+	case RoleSystem, RoleDM, RolePlayer, RoleObserver:
+		return role, nil
+	}
+	return "", fmt.Errorf("未知角色: %s", s)
+}
+
+// Principal 已认证的调用者
+type Principal struct {
+	UserID string `json:"user_id"`
+	Role   Role   `json:"role"`
+}
+
+// CanWrite 是否可以修改游戏状态
+func (p *Principal) CanWrite() bool {
+	return p.Role != RoleObserver
+}
+
+type principalKey struct{}
+
+// WithPrincipal 返回携带调用者的上下文
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext 获取请求的调用者，未启用认证时不存在
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// 认证错误
+var (
+	ErrUnauthenticated = errors.New("缺少或无效的凭证")
+	ErrTokenExpired    = errors.New("令牌已过期")
+)
+
+// Authenticator 校验 API Key 和 JWT
+type Authenticator struct {
+	apiKeys map[[sha256.Size]byte]*Principal
+	secret  []byte
+	now     func() time.Time
+}
+
+// NewAuthenticator 根据认证配置创建认证器
+func NewAuthenticator(cfg *config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		apiKeys: make(map[[sha256.Size]byte]*Principal),
+		secret:  []byte(cfg.JWTSecret),
+		now:     time.Now,
+	}
+
+	for _, entry := range strings.Split(cfg.APIKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("无效的 API Key 配置，格式应为 key:role:user_id")
+		}
+		role, err := ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("无效的 API Key 配置: %w", err)
+		}
+		a.apiKeys[sha256.Sum256([]byte(parts[0]))] = &Principal{UserID: parts[2], Role: role}
+	}
+
+	if len(a.apiKeys) == 0 && len(a.secret) == 0 {
+		return nil, fmt.Errorf("认证需要配置 API Key 或 JWT 密钥")
+	}
+	return a, nil
+}
+
+// Authenticate 将凭证(API Key 或 JWT)解析为调用者
+func (a *Authenticator) Authenticate(credential string) (*Principal, error) {
+	if credential == "" {
+		return nil, ErrUnauthenticated
+	}
+	// 按哈希查找，避免比较时泄露 Key 前缀
+	if p, ok := a.apiKeys[sha256.Sum256([]byte(credential))]; ok {
+		copied := *p
+		return &copied, nil
+	}
+	if len(a.secret) > 0 && strings.Count(credential, ".") == 2 {
+		return a.verifyToken(credential)
+	}
+	return nil, ErrUnauthenticated
+}
+
+// claims JWT 声明
+type claims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// verifyToken 校验 JWT 签名和过期时间
+func (a *Authenticator) verifyToken(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrUnauthenticated
+	}
+
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	expected := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, ErrUnauthenticated
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return nil, ErrUnauthenticated
+	}
+	if c.ExpiresAt != 0 && a.now().Unix() >= c.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	role, err := ParseRole(c.Role)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	return &Principal{UserID: c.Subject, Role: role}, nil
+}
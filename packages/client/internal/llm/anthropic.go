@@ -0,0 +1,419 @@
+// Package llm 提供 Anthropic Messages API 实现
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dnd-mcp/client/pkg/config"
+)
+
+// anthropicVersion Messages API 版本
+const anthropicVersion = "2023-06-01"
+
+// AnthropicClient Anthropic Messages API 客户端
+// 把 OpenAI 风格的消息转换为 Messages API 格式：system 消息合并到 system 字段，
+// 工具调用和工具结果分别转换为 tool_use 和 tool_result 内容块
+type AnthropicClient struct {
+	config       *config.LLMConfig
+	httpClient   *http.Client
+	streamClient *http.Client
+	baseURL      string
+	apiKey       string
+}
+
+// NewAnthropicClient 创建 Anthropic 客户端
+func NewAnthropicClient(cfg *config.LLMConfig) (*AnthropicClient, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("Anthropic API key 不能为空")
+	}
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1"
+	}
+	httpClient, streamClient := newHTTPClients(cfg.Timeout)
+
+	return &AnthropicClient{
+		config:       cfg,
+		httpClient:   httpClient,
+		streamClient: streamClient,
+		baseURL:      baseURL,
+		apiKey:       cfg.APIKey,
+	}, nil
+}
+
+// anthropicRequest Messages API 请求
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	ToolChoice  any                `json:"tool_choice,omitempty"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+// anthropicMessage Messages API 消息，user 和 assistant 必须交替出现
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+// anthropicBlock 内容块（text、tool_use 或 tool_result）
+type anthropicBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+// anthropicTool 工具定义
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+// anthropicResponse Messages API 响应
+type anthropicResponse struct {
+	ID         string           `json:"id"`
+	Model      string           `json:"model"`
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason"`
+	Usage      anthropicUsage   `json:"usage"`
+}
+
+// anthropicUsage 使用统计
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicStreamEvent 流式响应事件
+type anthropicStreamEvent struct {
+	Type         string             `json:"type"`
+	Index        int                `json:"index"`
+	Message      *anthropicResponse `json:"message,omitempty"`       // message_start
+	ContentBlock *anthropicBlock    `json:"content_block,omitempty"` // content_block_start
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *anthropicUsage `json:"usage,omitempty"` // message_delta
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Chat 实现聊天接口
+func (c *AnthropicClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	resp, err := c.send(ctx, c.httpClient, c.buildRequest(req, false))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var anthropicResp anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&anthropicResp); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	return anthropicResp.toChatResponse(), nil
+}
+
+// ChatStream 实现流式聊天接口
+func (c *AnthropicClient) ChatStream(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+	resp, err := c.send(ctx, c.streamClient, c.buildRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readAnthropicStream(resp.Body, handler)
+}
+
+// send 发送 Messages API 请求，调用方负责关闭响应
+func (c *AnthropicClient) send(ctx context.Context, client *http.Client, req *anthropicRequest) (*http.Response, error) {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		c.baseURL+"/messages",
+		bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	if req.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("发送 HTTP 请求失败: %w", err)
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// buildRequest 把聊天请求转换为 Messages API 请求
+func (c *AnthropicClient) buildRequest(req *ChatRequest, stream bool) *anthropicRequest {
+	model := req.Model
+	if model == "" {
+		model = c.config.Model
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.MaxTokens
+	}
+	if maxTokens <= 0 {
+		maxTokens = 4096
+	}
+
+	anthropicReq := &anthropicRequest{
+		Model:       model,
+		MaxTokens:   maxTokens,
+		Temperature: req.Temperature,
+		Stream:      stream,
+		ToolChoice:  anthropicToolChoice(req.ToolChoice),
+	}
+
+	var system []string
+	for _, msg := range req.Messages {
+		var role string
+		var blocks []anthropicBlock
+		switch msg.Role {
+		case "system":
+			system = append(system, msg.Content)
+			continue
+		case "assistant":
+			role = "assistant"
+			if msg.Content != "" {
+				blocks = append(blocks, anthropicBlock{Type: "text", Text: msg.Content})
+			}
+			for _, call := range msg.ToolCalls {
+				blocks = append(blocks, anthropicBlock{
+					Type:  "tool_use",
+					ID:    call.ID,
+					Name:  call.Function.Name,
+					Input: toolArguments(call.Function.Arguments),
+				})
+			}
+		case "tool":
+			// 工具结果放在紧随其后的 user 消息中
+			role = "user"
+			blocks = append(blocks, anthropicBlock{
+				Type:      "tool_result",
+				ToolUseID: msg.ToolCallID,
+				Content:   msg.Content,
+			})
+		default:
+			role = "user"
+			if msg.Content != "" {
+				blocks = append(blocks, anthropicBlock{Type: "text", Text: msg.Content})
+			}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// 相邻的同角色消息合并为一条
+		if n := len(anthropicReq.Messages); n > 0 && anthropicReq.Messages[n-1].Role == role {
+			anthropicReq.Messages[n-1].Content = append(anthropicReq.Messages[n-1].Content, blocks...)
+			continue
+		}
+		anthropicReq.Messages = append(anthropicReq.Messages, anthropicMessage{Role: role, Content: blocks})
+	}
+	anthropicReq.System = strings.Join(system, "\n\n")
+
+	for _, tool := range req.Tools {
+		anthropicReq.Tools = append(anthropicReq.Tools, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: tool.Function.Parameters,
+		})
+	}
+
+	return anthropicReq
+}
+
+// anthropicToolChoice 转换工具选择策略，不支持的取值交给 API 默认处理
+func anthropicToolChoice(choice any) any {
+	switch choice {
+	case "auto":
+		return map[string]string{"type": "auto"}
+	case "required":
+		return map[string]string{"type": "any"}
+	case "none":
+		return map[string]string{"type": "none"}
+	default:
+		return nil
+	}
+}
+
+// toolArguments 把 JSON 字符串形式的参数转换为对象，无效时使用空对象
+func toolArguments(arguments string) json.RawMessage {
+	if strings.TrimSpace(arguments) == "" || !json.Valid([]byte(arguments)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(arguments)
+}
+
+// anthropicFinishReason 把 stop_reason 转换为 OpenAI 风格的 finish_reason
+func anthropicFinishReason(stopReason string) string {
+	switch stopReason {
+	case "tool_use":
+		return "tool_calls"
+	case "max_tokens":
+		return "length"
+	case "":
+		return ""
+	default:
+		return "stop"
+	}
+}
+
+// toChatResponse 转换为聊天响应
+func (r *anthropicResponse) toChatResponse() *ChatResponse {
+	message := Message{Role: "assistant"}
+	var content strings.Builder
+	for _, block := range r.Content {
+		switch block.Type {
+		case "text":
+			content.WriteString(block.Text)
+		case "tool_use":
+			message.ToolCalls = append(message.ToolCalls, ToolCall{
+				ID:       block.ID,
+				Type:     "function",
+				Function: FunctionCall{Name: block.Name, Arguments: string(toolArguments(string(block.Input)))},
+			})
+		}
+	}
+	message.Content = content.String()
+
+	return &ChatResponse{
+		ID:     r.ID,
+		Object: "chat.completion",
+		Model:  r.Model,
+		Choices: []Choice{
+			{Message: message, FinishReason: anthropicFinishReason(r.StopReason)},
+		},
+		Usage: Usage{
+			PromptTokens:     r.Usage.InputTokens,
+			CompletionTokens: r.Usage.OutputTokens,
+			TotalTokens:      r.Usage.InputTokens + r.Usage.OutputTokens,
+		},
+	}
+}
+
+// readAnthropicStream 读取 Messages API 的 SSE 事件，转换为流式片段后拼装完整响应
+func readAnthropicStream(r io.Reader, handler StreamHandler) (*ChatResponse, error) {
+	acc := newStreamAccumulator()
+	toolIndexes := make(map[int]int) // 内容块序号 -> 工具调用序号
+	inputTokens := 0
+
+	err := scanSSE(r, func(data string) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("解析流式响应失败: %w", err)
+		}
+
+		chunk := &ChatStreamChunk{}
+		delta := MessageDelta{}
+		finishReason := ""
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				chunk.ID = event.Message.ID
+				chunk.Model = event.Message.Model
+				inputTokens = event.Message.Usage.InputTokens
+			}
+			delta.Role = "assistant"
+		case "content_block_start":
+			if event.ContentBlock == nil {
+				return nil
+			}
+			switch event.ContentBlock.Type {
+			case "text":
+				delta.Content = event.ContentBlock.Text
+			case "tool_use":
+				index := len(toolIndexes)
+				toolIndexes[event.Index] = index
+				delta.ToolCalls = []ToolCallDelta{{
+					Index:    index,
+					ID:       event.ContentBlock.ID,
+					Type:     "function",
+					Function: FunctionCall{Name: event.ContentBlock.Name},
+				}}
+			}
+		case "content_block_delta":
+			switch event.Delta.Type {
+			case "text_delta":
+				delta.Content = event.Delta.Text
+			case "input_json_delta":
+				index, ok := toolIndexes[event.Index]
+				if !ok {
+					return nil
+				}
+				delta.ToolCalls = []ToolCallDelta{{
+					Index:    index,
+					Function: FunctionCall{Arguments: event.Delta.PartialJSON},
+				}}
+			}
+		case "message_delta":
+			finishReason = anthropicFinishReason(event.Delta.StopReason)
+			if event.Usage != nil {
+				chunk.Usage = &Usage{
+					PromptTokens:     inputTokens,
+					CompletionTokens: event.Usage.OutputTokens,
+					TotalTokens:      inputTokens + event.Usage.OutputTokens,
+				}
+			}
+		case "error":
+			if event.Error != nil {
+				return fmt.Errorf("API 流式响应错误 (%s): %s", event.Error.Type, event.Error.Message)
+			}
+			return fmt.Errorf("API 流式响应错误: %s", data)
+		default:
+			// ping、content_block_stop、message_stop
+			return nil
+		}
+
+		chunk.Choices = []StreamChoice{{Delta: delta, FinishReason: finishReason}}
+		acc.emit(chunk, handler)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 没有参数的工具调用不会收到 input_json_delta
+	result := acc.response()
+	for i := range result.Choices {
+		for j := range result.Choices[i].Message.ToolCalls {
+			call := &result.Choices[i].Message.ToolCalls[j]
+			call.Function.Arguments = string(toolArguments(call.Function.Arguments))
+		}
+	}
+	return result, nil
+}
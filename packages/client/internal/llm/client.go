@@ -3,15 +3,15 @@ package llm
 
 import (
 	"context"
-	"fmt"
-
-	"github.com/dnd-mcp/client/pkg/config"
 )
 
 // LLMClient LLM 客户端接口
 type LLMClient interface {
 	// Chat 聊天对话，返回 AI 响应
 	Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error)
+
+	// ChatStream 流式聊天对话，每收到一个增量调用 handler，结束后返回拼装好的完整响应
+	ChatStream(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error)
 }
 
 // ChatRequest 聊天请求
@@ -22,6 +22,7 @@ type ChatRequest struct {
 	ToolChoice  any       `json:"tool_choice,omitempty"` // 工具选择策略(可选)
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 	Temperature float64   `json:"temperature,omitempty"`
+	Stream      bool      `json:"stream,omitempty"` // 流式响应(由 ChatStream 设置)
 }
 
 // Tool 工具定义
@@ -81,20 +82,3 @@ type FunctionCall struct {
 	Name      string `json:"name"`
 	Arguments string `json:"arguments"` // JSON string
 }
-
-// NewClient 创建 LLM 客户端
-func NewClient(cfg *config.LLMConfig) (LLMClient, error) {
-	switch cfg.Provider {
-	case "openai":
-		if cfg.APIKey == "" {
-			return nil, fmt.Errorf("OpenAI API key 不能为空")
-		}
-		return NewOpenAIClient(cfg), nil
-
-	case "mock":
-		return NewMockLLMClient(), nil
-
-	default:
-		return nil, fmt.Errorf("不支持的 LLM provider: %s", cfg.Provider)
-	}
-}
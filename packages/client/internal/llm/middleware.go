@@ -0,0 +1,232 @@
+// Package llm 提供 LLM 客户端中间件
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Middleware 包装 LLMClient，添加重试、熔断、限流等能力
+type Middleware func(next LLMClient) LLMClient
+
+// Chain 按顺序包装客户端，第一个中间件在最外层
+func Chain(client LLMClient, middlewares ...Middleware) LLMClient {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		client = middlewares[i](client)
+	}
+	return client
+}
+
+// clientFuncs 由函数实现的 LLMClient，用于编写中间件
+type clientFuncs struct {
+	chat       func(ctx context.Context, req *ChatRequest) (*ChatResponse, error)
+	chatStream func(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error)
+}
+
+func (c *clientFuncs) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	return c.chat(ctx, req)
+}
+
+func (c *clientFuncs) ChatStream(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+	return c.chatStream(ctx, req, handler)
+}
+
+// APIError LLM API 返回的非 200 响应
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // Retry-After 响应头，未返回时为 0
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API 请求失败 (status %d): %s", e.StatusCode, e.Body)
+}
+
+// parseRetryAfter 解析 Retry-After（秒数或 HTTP 日期）
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+// IsRetryable 判断错误是否为临时错误（限流、服务端错误、网络错误），可以重试或切换模型
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode == http.StatusRequestTimeout ||
+			apiErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// campaignKey context 中战役 ID 的键
+type campaignKey struct{}
+
+// WithCampaign 在 context 中记录本次调用所属的战役，供限流和用量统计使用
+func WithCampaign(ctx context.Context, campaignID string) context.Context {
+	return context.WithValue(ctx, campaignKey{}, campaignID)
+}
+
+// CampaignFromContext 返回 context 中的战役 ID，未设置时返回空字符串
+func CampaignFromContext(ctx context.Context) string {
+	campaignID, _ := ctx.Value(campaignKey{}).(string)
+	return campaignID
+}
+
+// ErrRateLimited 战役的 LLM 调用超过速率限制
+var ErrRateLimited = errors.New("LLM 调用过于频繁，请稍后再试")
+
+// RateLimiter 按战役的令牌桶限流
+type RateLimiter struct {
+	rate    float64 // 每秒补充的令牌数
+	burst   float64
+	maxWait time.Duration // 需要等待更久时直接拒绝
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// tokenBucket 单个战役的令牌桶
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter 创建限流器，每个战役每分钟 perMinute 次调用，最多积累 burst 次
+func NewRateLimiter(perMinute, burst int, maxWait time.Duration) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    float64(perMinute) / 60,
+		burst:   float64(burst),
+		maxWait: maxWait,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Wait 为战役取一个令牌，令牌不足时等待；需要等待超过 maxWait 时返回 ErrRateLimited
+func (l *RateLimiter) Wait(ctx context.Context, campaignID string) error {
+	wait, err := l.reserve(campaignID)
+	if err != nil || wait <= 0 {
+		return err
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve 预留一个令牌，返回需要等待的时间
+func (l *RateLimiter) reserve(campaignID string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bucket, ok := l.buckets[campaignID]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[campaignID] = bucket
+	}
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0, nil
+	}
+	if l.rate <= 0 {
+		return 0, ErrRateLimited
+	}
+	wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	if wait > l.maxWait {
+		return 0, ErrRateLimited
+	}
+	bucket.tokens--
+	return wait, nil
+}
+
+// WithRateLimit 按 context 中的战役限流，没有战役的调用不限流
+func WithRateLimit(limiter *RateLimiter) Middleware {
+	return func(next LLMClient) LLMClient {
+		return &clientFuncs{
+			chat: func(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+				if err := limiter.wait(ctx); err != nil {
+					return nil, err
+				}
+				return next.Chat(ctx, req)
+			},
+			chatStream: func(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+				if err := limiter.wait(ctx); err != nil {
+					return nil, err
+				}
+				return next.ChatStream(ctx, req, handler)
+			},
+		}
+	}
+}
+
+func (l *RateLimiter) wait(ctx context.Context) error {
+	campaignID := CampaignFromContext(ctx)
+	if campaignID == "" {
+		return nil
+	}
+	return l.Wait(ctx, campaignID)
+}
+
+// UsageRecorder 记录 LLM 用量（由 monitor.UsageTracker 实现）
+type UsageRecorder interface {
+	RecordUsage(campaignID, model string, promptTokens, completionTokens int)
+}
+
+// WithUsageRecorder 记录每次成功调用的 token 用量，按 context 中的战役归类
+func WithUsageRecorder(recorder UsageRecorder) Middleware {
+	record := func(ctx context.Context, req *ChatRequest, resp *ChatResponse) {
+		model := resp.Model
+		if model == "" {
+			model = req.Model
+		}
+		recorder.RecordUsage(CampaignFromContext(ctx), model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+
+	return func(next LLMClient) LLMClient {
+		return &clientFuncs{
+			chat: func(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+				resp, err := next.Chat(ctx, req)
+				if err == nil {
+					record(ctx, req, resp)
+				}
+				return resp, err
+			},
+			chatStream: func(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+				resp, err := next.ChatStream(ctx, req, handler)
+				if err == nil {
+					record(ctx, req, resp)
+				}
+				return resp, err
+			},
+		}
+	}
+}
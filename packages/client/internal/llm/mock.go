@@ -32,9 +32,13 @@ func (m *MockLLMClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespon
 		return nil, fmt.Errorf("request cannot be nil")
 	}
 
-	// 获取最后一条用户消息
+	// 获取最后一条用户消息，并检查其后是否已有工具结果
 	lastUserMessage := ""
+	hasToolResult := false
 	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "tool" {
+			hasToolResult = true
+		}
 		if req.Messages[i].Role == "user" {
 			lastUserMessage = req.Messages[i].Content
 			break
@@ -42,7 +46,7 @@ func (m *MockLLMClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespon
 	}
 
 	// 如果用户消息包含"投掷"或"dice",返回tool_calls
-	if strings.Contains(lastUserMessage, "投掷") || strings.Contains(lastUserMessage, "dice") || strings.Contains(lastUserMessage, "d20") {
+	if !hasToolResult && strings.Contains(lastUserMessage, "投掷") || strings.Contains(lastUserMessage, "dice") || strings.Contains(lastUserMessage, "d20") {
 		// 构造 tool_calls
 		arguments := map[string]interface{}{
 			"formula": "1d20+5",
@@ -83,31 +87,29 @@ func (m *MockLLMClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespon
 	}
 
 	// 检查是否是工具调用后的后续请求
-	for i := len(req.Messages) - 1; i >= 0; i-- {
-		if req.Messages[i].Role == "tool" {
-			// 这是工具调用后的请求,返回最终响应
-			return &ChatResponse{
-				ID:      "mock-response-id",
-				Object:  "chat.completion",
-				Created: time.Now().Unix(),
-				Model:   "mock-model",
-				Choices: []Choice{
-					{
-						Index: 0,
-						Message: Message{
-							Role:    "assistant",
-							Content: fmt.Sprintf("投掷完成!结果是 18(15+3)。%s", m.Response),
-						},
-						FinishReason: "stop",
+	if hasToolResult {
+		// 这是工具调用后的请求,返回最终响应
+		return &ChatResponse{
+			ID:      "mock-response-id",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "mock-model",
+			Choices: []Choice{
+				{
+					Index: 0,
+					Message: Message{
+						Role:    "assistant",
+						Content: fmt.Sprintf("投掷完成!结果是 18(15+3)。%s", m.Response),
 					},
+					FinishReason: "stop",
 				},
-				Usage: Usage{
-					PromptTokens:     10,
-					CompletionTokens: 20,
-					TotalTokens:      30,
-				},
-			}, nil
-		}
+			},
+			Usage: Usage{
+				PromptTokens:     10,
+				CompletionTokens: 20,
+				TotalTokens:      30,
+			},
+		}, nil
 	}
 
 	// 返回预设响应（兼容新格式）
@@ -133,3 +135,22 @@ func (m *MockLLMClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespon
 		},
 	}, nil
 }
+
+// ChatStream 实现流式聊天接口，把预设响应按片段回调
+func (m *MockLLMClient) ChatStream(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+	resp, err := m.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if handler != nil {
+		content := []rune(resp.Choices[0].Message.Content)
+		for len(content) > 0 {
+			n := min(len(content), 8)
+			handler(MessageDelta{Content: string(content[:n])})
+			content = content[n:]
+		}
+	}
+
+	return resp, nil
+}
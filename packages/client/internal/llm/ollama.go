@@ -0,0 +1,288 @@
+// Package llm 提供 Ollama 本地模型实现
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dnd-mcp/client/pkg/config"
+	"github.com/google/uuid"
+)
+
+// OllamaClient Ollama /api/chat 客户端，用于离线运行本地模型
+// Ollama 的工具调用参数是 JSON 对象且没有 ID，客户端为每个调用生成 ID
+type OllamaClient struct {
+	config       *config.LLMConfig
+	httpClient   *http.Client
+	streamClient *http.Client
+	baseURL      string
+	apiKey       string // 可选，用于带认证的反向代理
+}
+
+// NewOllamaClient 创建 Ollama 客户端
+func NewOllamaClient(cfg *config.LLMConfig) *OllamaClient {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	httpClient, streamClient := newHTTPClients(cfg.Timeout)
+
+	return &OllamaClient{
+		config:       cfg,
+		httpClient:   httpClient,
+		streamClient: streamClient,
+		baseURL:      baseURL,
+		apiKey:       cfg.APIKey,
+	}
+}
+
+// ollamaRequest /api/chat 请求
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Tools    []Tool          `json:"tools,omitempty"`
+	Stream   bool            `json:"stream"` // Ollama 默认流式返回，需要显式关闭
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+// ollamaOptions 模型参数
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+// ollamaMessage 消息
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"` // tool 角色对应的工具名称
+}
+
+// ollamaToolCall 工具调用
+type ollamaToolCall struct {
+	ID       string `json:"id,omitempty"`
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// ollamaResponse 响应（流式时每行一个）
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+// Chat 实现聊天接口
+func (c *OllamaClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	resp, err := c.send(ctx, c.httpClient, c.buildRequest(req, false))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ollamaResp ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if ollamaResp.Error != "" {
+		return nil, fmt.Errorf("API 请求失败: %s", ollamaResp.Error)
+	}
+
+	message := Message{
+		Role:      "assistant",
+		Content:   ollamaResp.Message.Content,
+		ToolCalls: convertOllamaToolCalls(ollamaResp.Message.ToolCalls),
+	}
+	finishReason := ollamaFinishReason(ollamaResp.DoneReason)
+	if len(message.ToolCalls) > 0 {
+		finishReason = "tool_calls"
+	}
+
+	return &ChatResponse{
+		Object:  "chat.completion",
+		Model:   ollamaResp.Model,
+		Choices: []Choice{{Message: message, FinishReason: finishReason}},
+		Usage:   ollamaResp.usage(),
+	}, nil
+}
+
+// ChatStream 实现流式聊天接口（Ollama 按行返回 JSON）
+func (c *OllamaClient) ChatStream(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+	resp, err := c.send(ctx, c.streamClient, c.buildRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readOllamaStream(resp.Body, handler)
+}
+
+// send 发送 /api/chat 请求，调用方负责关闭响应
+func (c *OllamaClient) send(ctx context.Context, client *http.Client, req *ollamaRequest) (*http.Response, error) {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		c.baseURL+"/api/chat",
+		bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("发送 HTTP 请求失败: %w", err)
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// buildRequest 把聊天请求转换为 /api/chat 请求
+func (c *OllamaClient) buildRequest(req *ChatRequest, stream bool) *ollamaRequest {
+	model := req.Model
+	if model == "" {
+		model = c.config.Model
+	}
+
+	ollamaReq := &ollamaRequest{
+		Model:  model,
+		Tools:  req.Tools,
+		Stream: stream,
+	}
+	if req.Temperature != 0 || req.MaxTokens != 0 {
+		ollamaReq.Options = &ollamaOptions{Temperature: req.Temperature, NumPredict: req.MaxTokens}
+	}
+
+	toolNames := make(map[string]string) // 工具调用 ID -> 工具名称
+	for _, msg := range req.Messages {
+		ollamaMsg := ollamaMessage{Role: msg.Role, Content: msg.Content}
+		for _, call := range msg.ToolCalls {
+			toolNames[call.ID] = call.Function.Name
+
+			var ollamaCall ollamaToolCall
+			ollamaCall.ID = call.ID
+			ollamaCall.Function.Name = call.Function.Name
+			ollamaCall.Function.Arguments = toolArguments(call.Function.Arguments)
+			ollamaMsg.ToolCalls = append(ollamaMsg.ToolCalls, ollamaCall)
+		}
+		if msg.Role == "tool" {
+			ollamaMsg.ToolName = toolNames[msg.ToolCallID]
+		}
+		ollamaReq.Messages = append(ollamaReq.Messages, ollamaMsg)
+	}
+
+	return ollamaReq
+}
+
+// convertOllamaToolCalls 转换工具调用，没有 ID 的调用生成 ID
+func convertOllamaToolCalls(calls []ollamaToolCall) []ToolCall {
+	var toolCalls []ToolCall
+	for _, call := range calls {
+		id := call.ID
+		if id == "" {
+			id = "call_" + uuid.New().String()
+		}
+		toolCalls = append(toolCalls, ToolCall{
+			ID:       id,
+			Type:     "function",
+			Function: FunctionCall{Name: call.Function.Name, Arguments: string(toolArguments(string(call.Function.Arguments)))},
+		})
+	}
+	return toolCalls
+}
+
+// ollamaFinishReason 把 done_reason 转换为 OpenAI 风格的 finish_reason
+func ollamaFinishReason(doneReason string) string {
+	if doneReason == "length" {
+		return "length"
+	}
+	return "stop"
+}
+
+// usage 返回使用统计
+func (r *ollamaResponse) usage() Usage {
+	return Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// readOllamaStream 读取按行分隔的 JSON 流，转换为流式片段后拼装完整响应
+func readOllamaStream(r io.Reader, handler StreamHandler) (*ChatResponse, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	acc := newStreamAccumulator()
+	toolCount := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var ollamaResp ollamaResponse
+		if err := json.Unmarshal([]byte(line), &ollamaResp); err != nil {
+			return nil, fmt.Errorf("解析流式响应失败: %w", err)
+		}
+		if ollamaResp.Error != "" {
+			return nil, fmt.Errorf("API 流式响应错误: %s", ollamaResp.Error)
+		}
+
+		chunk := &ChatStreamChunk{Model: ollamaResp.Model}
+		delta := MessageDelta{Role: "assistant", Content: ollamaResp.Message.Content}
+		for _, call := range convertOllamaToolCalls(ollamaResp.Message.ToolCalls) {
+			delta.ToolCalls = append(delta.ToolCalls, ToolCallDelta{
+				Index:    toolCount,
+				ID:       call.ID,
+				Type:     call.Type,
+				Function: call.Function,
+			})
+			toolCount++
+		}
+
+		finishReason := ""
+		if ollamaResp.Done {
+			finishReason = ollamaFinishReason(ollamaResp.DoneReason)
+			if toolCount > 0 {
+				finishReason = "tool_calls"
+			}
+			usage := ollamaResp.usage()
+			chunk.Usage = &usage
+		}
+
+		chunk.Choices = []StreamChoice{{Delta: delta, FinishReason: finishReason}}
+		acc.emit(chunk, handler)
+		if ollamaResp.Done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取流式响应失败: %w", err)
+	}
+
+	return acc.response(), nil
+}
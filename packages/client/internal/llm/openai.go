@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/dnd-mcp/client/pkg/config"
@@ -15,10 +17,14 @@ import (
 
 // OpenAIClient OpenAI 客户端
 type OpenAIClient struct {
-	config     *config.LLMConfig
-	httpClient *http.Client
-	baseURL    string
-	apiKey     string
+	config       *config.LLMConfig
+	httpClient   *http.Client
+	streamClient *http.Client // 流式响应持续时间不定，只限制等待响应头的时间
+	baseURL      string
+	apiKey       string
+
+	endpoint func(model string) string // chat completions 地址
+	setAuth  func(req *http.Request)   // 设置认证请求头
 }
 
 // NewOpenAIClient 创建 OpenAI 客户端
@@ -27,13 +33,72 @@ func NewOpenAIClient(cfg *config.LLMConfig) *OpenAIClient {
 	if baseURL == "" {
 		baseURL = "https://api.openai.com/v1"
 	}
-	return &OpenAIClient{
-		config:  cfg,
-		baseURL: baseURL,
-		apiKey:  cfg.APIKey,
-		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.Timeout) * time.Second,
-		},
+	httpClient, streamClient := newHTTPClients(cfg.Timeout)
+
+	c := &OpenAIClient{
+		config:       cfg,
+		baseURL:      baseURL,
+		apiKey:       cfg.APIKey,
+		httpClient:   httpClient,
+		streamClient: streamClient,
+	}
+	c.endpoint = func(string) string {
+		return c.baseURL + "/chat/completions"
+	}
+	c.setAuth = func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return c
+}
+
+// NewAzureOpenAIClient 创建 Azure OpenAI 客户端
+// 模型名称即部署名称，请求发送到 {base_url}/openai/deployments/{deployment}/chat/completions
+func NewAzureOpenAIClient(cfg *config.LLMConfig) (*OpenAIClient, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("Azure OpenAI base URL 不能为空")
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("Azure OpenAI API key 不能为空")
+	}
+	apiVersion := cfg.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAzureAPIVersion
+	}
+
+	c := NewOpenAIClient(cfg)
+	c.baseURL = strings.TrimRight(cfg.BaseURL, "/")
+	c.endpoint = func(deployment string) string {
+		return c.baseURL + "/openai/deployments/" + url.PathEscape(deployment) +
+			"/chat/completions?api-version=" + url.QueryEscape(apiVersion)
+	}
+	c.setAuth = func(req *http.Request) {
+		req.Header.Set("api-key", c.apiKey)
+	}
+	return c, nil
+}
+
+// defaultAzureAPIVersion 未配置 LLM_API_VERSION 时使用的 Azure api-version
+const defaultAzureAPIVersion = "2024-06-01"
+
+// newHTTPClients 创建普通请求和流式请求使用的 HTTP 客户端
+func newHTTPClients(timeoutSeconds int) (*http.Client, *http.Client) {
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	streamTransport := http.DefaultTransport.(*http.Transport).Clone()
+	streamTransport.ResponseHeaderTimeout = timeout
+
+	return &http.Client{Timeout: timeout}, &http.Client{Transport: streamTransport}
+}
+
+// checkResponse 检查响应状态，非 200 时返回 *APIError
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
 	}
 }
 
@@ -52,7 +117,7 @@ func (c *OpenAIClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 
 	// 创建 HTTP 请求
 	httpReq, err := http.NewRequestWithContext(ctx, "POST",
-		c.baseURL+"/chat/completions",
+		c.endpoint(req.Model),
 		bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
@@ -60,7 +125,7 @@ func (c *OpenAIClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 
 	// 设置请求头
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setAuth(httpReq)
 
 	// 发送请求
 	resp, err := c.httpClient.Do(httpReq)
@@ -70,10 +135,8 @@ func (c *OpenAIClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 	defer resp.Body.Close()
 
 	// 检查响应状态
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API 请求失败 (status %d): %s",
-			resp.StatusCode, string(body))
+	if err := checkResponse(resp); err != nil {
+		return nil, err
 	}
 
 	// 解析响应
@@ -84,3 +147,47 @@ func (c *OpenAIClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 
 	return &chatResp, nil
 }
+
+// ChatStream 实现流式聊天接口
+func (c *OpenAIClient) ChatStream(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+	// 设置默认模型
+	if req.Model == "" {
+		req.Model = c.config.Model
+	}
+
+	streamReq := *req
+	streamReq.Stream = true
+
+	// 序列化请求
+	reqBody, err := json.Marshal(&streamReq)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	// 创建 HTTP 请求
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		c.endpoint(req.Model),
+		bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+
+	// 设置请求头
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	c.setAuth(httpReq)
+
+	// 发送请求
+	resp, err := c.streamClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("发送 HTTP 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 检查响应状态
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return readStream(resp.Body, handler)
+}
@@ -0,0 +1,159 @@
+// Package llm 提供 LLM provider 注册表
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dnd-mcp/client/pkg/config"
+)
+
+// ProviderFactory 根据配置创建 LLM 客户端
+type ProviderFactory func(cfg *config.LLMConfig) (LLMClient, error)
+
+// Registry LLM provider 注册表，按名称创建客户端
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]ProviderFactory
+}
+
+// NewRegistry 创建空的 provider 注册表
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]ProviderFactory)}
+}
+
+// Register 注册 provider，同名 provider 会被替换
+func (r *Registry) Register(name string, factory ProviderFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = factory
+}
+
+// Has 检查 provider 是否已注册
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.factories[name]
+	return ok
+}
+
+// Providers 返回已注册的 provider 名称（按字母排序）
+func (r *Registry) Providers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New 使用 cfg.Provider 对应的 provider 创建客户端
+func (r *Registry) New(cfg *config.LLMConfig) (LLMClient, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[cfg.Provider]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("不支持的 LLM provider: %s", cfg.Provider)
+	}
+	return factory(cfg)
+}
+
+// DefaultRegistry 默认注册表，包含内置的 provider
+var DefaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("openai", func(cfg *config.LLMConfig) (LLMClient, error) {
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("OpenAI API key 不能为空")
+		}
+		return NewOpenAIClient(cfg), nil
+	})
+	r.Register("azure", func(cfg *config.LLMConfig) (LLMClient, error) {
+		return NewAzureOpenAIClient(cfg)
+	})
+	r.Register("anthropic", func(cfg *config.LLMConfig) (LLMClient, error) {
+		return NewAnthropicClient(cfg)
+	})
+	r.Register("ollama", func(cfg *config.LLMConfig) (LLMClient, error) {
+		return NewOllamaClient(cfg), nil
+	})
+	r.Register("mock", func(cfg *config.LLMConfig) (LLMClient, error) {
+		return NewMockLLMClient(), nil
+	})
+	return r
+}
+
+// Register 向默认注册表注册 provider
+func Register(name string, factory ProviderFactory) {
+	DefaultRegistry.Register(name, factory)
+}
+
+// ClientPool 按 provider 创建并缓存 LLM 客户端
+// 默认 provider 使用基础配置，其他 provider 使用 config.LLMConfig.Providers 中的配置；
+// 每个客户端都包装了 Use 设置的中间件和按其配置创建的重试、熔断、备用模型中间件
+type ClientPool struct {
+	registry    *Registry
+	config      *config.LLMConfig
+	middlewares []Middleware
+
+	mu      sync.Mutex
+	clients map[string]LLMClient
+}
+
+// NewClientPool 创建客户端池，registry 为 nil 时使用默认注册表
+func NewClientPool(registry *Registry, cfg *config.LLMConfig) *ClientPool {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	return &ClientPool{
+		registry: registry,
+		config:   cfg,
+		clients:  make(map[string]LLMClient),
+	}
+}
+
+// Use 添加所有客户端共用的中间件（在重试等中间件之外），需要在 Get 之前调用
+func (p *ClientPool) Use(middlewares ...Middleware) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.middlewares = append(p.middlewares, middlewares...)
+}
+
+// DefaultProvider 返回默认 provider 名称
+func (p *ClientPool) DefaultProvider() string {
+	return p.config.Provider
+}
+
+// Get 返回 provider 的客户端，provider 为空时返回默认 provider 的客户端
+func (p *ClientPool) Get(provider string) (LLMClient, error) {
+	if provider == "" {
+		provider = p.config.Provider
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[provider]; ok {
+		return client, nil
+	}
+
+	cfg, ok := p.config.ProviderConfig(provider)
+	if !ok {
+		return nil, fmt.Errorf("LLM provider 未配置: %s", provider)
+	}
+	client, err := p.registry.New(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	client = Chain(client, append(append([]Middleware{}, p.middlewares...), ResilienceMiddlewares(&cfg)...)...)
+	p.clients[provider] = client
+	return client, nil
+}
+
+// NewClient 创建 LLM 客户端
+func NewClient(cfg *config.LLMConfig) (LLMClient, error) {
+	return DefaultRegistry.New(cfg)
+}
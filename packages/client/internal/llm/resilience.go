@@ -0,0 +1,250 @@
+// Package llm 提供 LLM 调用的重试、熔断和备用模型
+package llm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/dnd-mcp/client/pkg/config"
+)
+
+// RetryConfig 重试配置
+type RetryConfig struct {
+	MaxRetries int           // 首次调用失败后最多重试的次数
+	BaseDelay  time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	MaxDelay   time.Duration // 单次等待的上限（包括 Retry-After）
+}
+
+// WithRetry 对临时错误按指数退避重试，API 返回 Retry-After 时按其等待
+// 流式调用已经推送过增量内容时不再重试，避免重复推送
+func WithRetry(cfg RetryConfig) Middleware {
+	return func(next LLMClient) LLMClient {
+		return &clientFuncs{
+			chat: func(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+				return retry(ctx, cfg, func() (*ChatResponse, error) {
+					return next.Chat(ctx, req)
+				}, nil)
+			},
+			chatStream: func(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+				emitted := false
+				tracked := func(delta MessageDelta) {
+					emitted = true
+					if handler != nil {
+						handler(delta)
+					}
+				}
+				return retry(ctx, cfg, func() (*ChatResponse, error) {
+					return next.ChatStream(ctx, req, tracked)
+				}, func() bool { return !emitted })
+			},
+		}
+	}
+}
+
+// retry 执行 call，失败时按退避策略重试；canRetry 为 nil 表示总是可以重试
+func retry(ctx context.Context, cfg RetryConfig, call func() (*ChatResponse, error), canRetry func() bool) (*ChatResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := call()
+		if err == nil || attempt >= cfg.MaxRetries || ctx.Err() != nil || !IsRetryable(err) {
+			return resp, err
+		}
+		if canRetry != nil && !canRetry() {
+			return resp, err
+		}
+
+		delay := backoff(cfg, attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			delay = min(apiErr.RetryAfter, cfg.MaxDelay)
+		}
+		log.Printf("LLM 调用失败，%v 后第 %d 次重试: %v", delay, attempt+1, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff 第 attempt 次重试前的等待时间：指数增长并加入随机抖动
+func backoff(cfg RetryConfig, attempt int) time.Duration {
+	delay := cfg.BaseDelay << attempt
+	if delay <= 0 || delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// 在 [delay/2, delay] 之间随机，避免多个战役同时重试
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// ErrCircuitOpen 模型连续失败，熔断期间不再调用
+var ErrCircuitOpen = errors.New("LLM 服务暂时不可用（熔断中）")
+
+// BreakerConfig 熔断配置
+type BreakerConfig struct {
+	Threshold int           // 连续失败多少次后熔断
+	Cooldown  time.Duration // 熔断持续时间，之后放行一次试探调用
+}
+
+// WithCircuitBreaker 按模型熔断：连续的临时错误达到阈值后，冷却期内直接返回 ErrCircuitOpen
+// 阈值不大于 0 时不启用熔断
+func WithCircuitBreaker(cfg BreakerConfig) Middleware {
+	return func(next LLMClient) LLMClient {
+		if cfg.Threshold <= 0 {
+			return next
+		}
+		b := &circuitBreaker{config: cfg, states: make(map[string]*breakerState), now: time.Now}
+		return &clientFuncs{
+			chat: func(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+				model := req.Model
+				if err := b.allow(model); err != nil {
+					return nil, err
+				}
+				resp, err := next.Chat(ctx, req)
+				b.record(model, err)
+				return resp, err
+			},
+			chatStream: func(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+				model := req.Model
+				if err := b.allow(model); err != nil {
+					return nil, err
+				}
+				resp, err := next.ChatStream(ctx, req, handler)
+				b.record(model, err)
+				return resp, err
+			},
+		}
+	}
+}
+
+// circuitBreaker 按模型记录的熔断状态
+type circuitBreaker struct {
+	config BreakerConfig
+	mu     sync.Mutex
+	states map[string]*breakerState
+	now    func() time.Time
+}
+
+// breakerState 单个模型的熔断状态
+type breakerState struct {
+	failures  int
+	openUntil time.Time
+	probing   bool // 冷却结束后正在进行试探调用
+}
+
+// allow 检查是否可以调用模型
+func (b *circuitBreaker) allow(model string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.states[model]
+	if !ok || state.openUntil.IsZero() {
+		return nil
+	}
+	if b.now().Before(state.openUntil) || state.probing {
+		return ErrCircuitOpen
+	}
+	state.probing = true
+	return nil
+}
+
+// record 记录调用结果，只有临时错误计为失败
+func (b *circuitBreaker) record(model string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.states[model]
+	if !ok {
+		state = &breakerState{}
+		b.states[model] = state
+	}
+	state.probing = false
+
+	switch {
+	case err == nil:
+		state.failures = 0
+		state.openUntil = time.Time{}
+	case IsRetryable(err):
+		state.failures++
+		if state.failures >= b.config.Threshold {
+			state.openUntil = b.now().Add(b.config.Cooldown)
+			log.Printf("LLM 模型 %q 连续失败 %d 次，熔断 %v", model, state.failures, b.config.Cooldown)
+		}
+	}
+}
+
+// WithFallbackModels 调用因临时错误或熔断失败时，依次改用备用模型
+// 流式调用已经推送过增量内容时不再切换
+func WithFallbackModels(models []string) Middleware {
+	return func(next LLMClient) LLMClient {
+		if len(models) == 0 {
+			return next
+		}
+		return &clientFuncs{
+			chat: func(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+				return fallback(ctx, models, req, func(r *ChatRequest) (*ChatResponse, error) {
+					return next.Chat(ctx, r)
+				}, nil)
+			},
+			chatStream: func(ctx context.Context, req *ChatRequest, handler StreamHandler) (*ChatResponse, error) {
+				emitted := false
+				tracked := func(delta MessageDelta) {
+					emitted = true
+					if handler != nil {
+						handler(delta)
+					}
+				}
+				return fallback(ctx, models, req, func(r *ChatRequest) (*ChatResponse, error) {
+					return next.ChatStream(ctx, r, tracked)
+				}, func() bool { return !emitted })
+			},
+		}
+	}
+}
+
+// fallback 先用请求的模型调用，失败时依次尝试备用模型
+func fallback(ctx context.Context, models []string, req *ChatRequest, call func(*ChatRequest) (*ChatResponse, error), canFallback func() bool) (*ChatResponse, error) {
+	resp, err := call(req)
+	for _, model := range models {
+		if err == nil || ctx.Err() != nil || !(IsRetryable(err) || errors.Is(err, ErrCircuitOpen)) {
+			break
+		}
+		if canFallback != nil && !canFallback() {
+			break
+		}
+		if model == req.Model {
+			continue
+		}
+
+		log.Printf("LLM 模型 %q 调用失败，改用备用模型 %q: %v", req.Model, model, err)
+		fallbackReq := *req
+		fallbackReq.Model = model
+		resp, err = call(&fallbackReq)
+	}
+	return resp, err
+}
+
+// ResilienceMiddlewares 按配置创建备用模型、熔断和重试中间件（由外到内）
+func ResilienceMiddlewares(cfg *config.LLMConfig) []Middleware {
+	return []Middleware{
+		WithFallbackModels(cfg.FallbackModels),
+		WithCircuitBreaker(BreakerConfig{
+			Threshold: cfg.BreakerThreshold,
+			Cooldown:  time.Duration(cfg.BreakerCooldown) * time.Second,
+		}),
+		WithRetry(RetryConfig{
+			MaxRetries: cfg.MaxRetries,
+			BaseDelay:  time.Duration(cfg.RetryBaseDelay) * time.Millisecond,
+			MaxDelay:   time.Duration(cfg.RetryMaxDelay) * time.Millisecond,
+		}),
+	}
+}
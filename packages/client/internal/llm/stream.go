@@ -0,0 +1,211 @@
+// Package llm 提供流式响应解析
+package llm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// StreamHandler 流式响应回调，每收到一个增量片段调用一次
+type StreamHandler func(delta MessageDelta)
+
+// ChatStreamChunk 流式响应片段（stream: true 时每条 SSE data）
+type ChatStreamChunk struct {
+	ID      string         `json:"id"`
+	Object  string         `json:"object"`
+	Created int64          `json:"created"`
+	Model   string         `json:"model"`
+	Choices []StreamChoice `json:"choices"`
+	Usage   *Usage         `json:"usage,omitempty"` // 部分服务在最后一个片段返回
+}
+
+// StreamChoice 流式选择
+type StreamChoice struct {
+	Index        int          `json:"index"`
+	Delta        MessageDelta `json:"delta"`
+	FinishReason string       `json:"finish_reason"`
+}
+
+// MessageDelta 消息增量
+type MessageDelta struct {
+	Role      string          `json:"role,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ToolCallDelta 工具调用增量
+// 同一个调用分多个片段返回：第一个片段带 ID 和函数名，之后的片段只带参数的后续部分
+type ToolCallDelta struct {
+	Index    int          `json:"index"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function FunctionCall `json:"function"`
+}
+
+// readStream 读取 SSE 流，把增量交给 handler，并拼装出完整响应
+func readStream(r io.Reader, handler StreamHandler) (*ChatResponse, error) {
+	acc := newStreamAccumulator()
+	err := scanSSE(r, func(data string) error {
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("解析流式响应失败: %w", err)
+		}
+		acc.emit(&chunk, handler)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return acc.response(), nil
+}
+
+// scanSSE 逐条读取 SSE 的 data 内容，读到 [DONE] 或流结束时返回
+func scanSSE(r io.Reader, fn func(data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			// 空行、注释和 event: 行
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取流式响应失败: %w", err)
+	}
+	return nil
+}
+
+// streamAccumulator 将流式片段拼装为完整响应
+type streamAccumulator struct {
+	resp    ChatResponse
+	choices map[int]*choiceAccumulator
+}
+
+// choiceAccumulator 单个选择的拼装状态
+type choiceAccumulator struct {
+	role         string
+	content      strings.Builder
+	finishReason string
+	toolCalls    map[int]*ToolCall
+}
+
+func newStreamAccumulator() *streamAccumulator {
+	return &streamAccumulator{
+		resp:    ChatResponse{Object: "chat.completion"},
+		choices: make(map[int]*choiceAccumulator),
+	}
+}
+
+// add 合并一个片段
+func (a *streamAccumulator) add(chunk *ChatStreamChunk) {
+	if chunk.ID != "" {
+		a.resp.ID = chunk.ID
+	}
+	if chunk.Created != 0 {
+		a.resp.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		a.resp.Model = chunk.Model
+	}
+	if chunk.Usage != nil {
+		a.resp.Usage = *chunk.Usage
+	}
+
+	for _, sc := range chunk.Choices {
+		choice, ok := a.choices[sc.Index]
+		if !ok {
+			choice = &choiceAccumulator{toolCalls: make(map[int]*ToolCall)}
+			a.choices[sc.Index] = choice
+		}
+
+		if sc.Delta.Role != "" {
+			choice.role = sc.Delta.Role
+		}
+		choice.content.WriteString(sc.Delta.Content)
+		if sc.FinishReason != "" {
+			choice.finishReason = sc.FinishReason
+		}
+
+		for _, tc := range sc.Delta.ToolCalls {
+			call, ok := choice.toolCalls[tc.Index]
+			if !ok {
+				call = &ToolCall{Type: "function"}
+				choice.toolCalls[tc.Index] = call
+			}
+			if tc.ID != "" {
+				call.ID = tc.ID
+			}
+			if tc.Type != "" {
+				call.Type = tc.Type
+			}
+			if tc.Function.Name != "" {
+				call.Function.Name = tc.Function.Name
+			}
+			call.Function.Arguments += tc.Function.Arguments
+		}
+	}
+}
+
+// emit 合并一个片段，并把其中的增量交给 handler
+func (a *streamAccumulator) emit(chunk *ChatStreamChunk, handler StreamHandler) {
+	a.add(chunk)
+	if handler == nil {
+		return
+	}
+	for _, choice := range chunk.Choices {
+		if choice.Delta.Content != "" || len(choice.Delta.ToolCalls) > 0 {
+			handler(choice.Delta)
+		}
+	}
+}
+
+// response 返回拼装好的完整响应
+func (a *streamAccumulator) response() *ChatResponse {
+	resp := a.resp
+	resp.Choices = make([]Choice, 0, len(a.choices))
+
+	for index, choice := range a.choices {
+		message := Message{
+			Role:    choice.role,
+			Content: choice.content.String(),
+		}
+		if message.Role == "" {
+			message.Role = "assistant"
+		}
+
+		callIndexes := make([]int, 0, len(choice.toolCalls))
+		for i := range choice.toolCalls {
+			callIndexes = append(callIndexes, i)
+		}
+		sort.Ints(callIndexes)
+		for _, i := range callIndexes {
+			message.ToolCalls = append(message.ToolCalls, *choice.toolCalls[i])
+		}
+
+		resp.Choices = append(resp.Choices, Choice{
+			Index:        index,
+			Message:      message,
+			FinishReason: choice.finishReason,
+		})
+	}
+	sort.Slice(resp.Choices, func(i, j int) bool {
+		return resp.Choices[i].Index < resp.Choices[j].Index
+	})
+
+	return &resp
+}
@@ -22,18 +22,46 @@ type MCPClient interface {
 	Close(ctx context.Context) error
 }
 
+// MCP Server 推送的游戏事件类型
+const (
+	EventHPChanged        = "hp_changed"        // 生命值变化
+	EventConditionApplied = "condition_applied" // 施加状态
+	EventConditionRemoved = "condition_removed" // 移除状态
+	EventTurnAdvanced     = "turn_advanced"     // 战斗回合推进
+	EventTokenMoved       = "token_moved"       // Token 移动
+	EventDiceRolled       = "dice_rolled"       // 掷骰
+	EventMapEntered       = "map_entered"       // 进入地图
+)
+
 // Event MCP Server 事件
 type Event struct {
-	Type      string                 `json:"type"` // state_changed, combat_updated, etc.
+	Cursor    int64                  `json:"cursor,omitempty"` // 事件游标(单调递增,用于断线续传)
+	Type      string                 `json:"type"`             // hp_changed, turn_advanced, dice_rolled, etc.
 	SessionID string                 `json:"session_id"`
 	Data      map[string]interface{} `json:"data"`
 }
 
+// Tool MCP Server 提供的工具定义（与 Server 端 mcp.Tool 对齐）
+type Tool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	InputSchema InputSchema `json:"inputSchema"`
+}
+
+// InputSchema 工具参数的 JSON Schema
+type InputSchema struct {
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+}
+
 // NewClient 创建 MCP 客户端
 func NewClient(cfg *config.MCPConfig) (MCPClient, error) {
 	if cfg.ServerURL == "mock://" {
 		return NewMockClient(), nil
 	}
 
-	return NewHTTPClient(cfg.ServerURL, cfg.Timeout), nil
+	client := NewHTTPClient(cfg.ServerURL, cfg.Timeout)
+	client.SetAPIKey(cfg.APIKey)
+	return client, nil
 }
@@ -2,21 +2,39 @@
 package mcp
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// 事件订阅参数
+var (
+	// EventPollInterval 轮询事件的间隔
+	EventPollInterval = 2 * time.Second
+	// EventRetryInterval 事件流断开后重连的间隔
+	EventRetryInterval = 2 * time.Second
+)
+
+// errStreamUnsupported 服务器不提供 SSE 事件流
+var errStreamUnsupported = errors.New("服务器不支持事件流")
+
 // HTTPClient HTTP MCP 客户端
 type HTTPClient struct {
-	baseURL    string
-	httpClient *http.Client
-	sessionID  string
-	timeout    time.Duration
+	baseURL      string
+	httpClient   *http.Client
+	streamClient *http.Client // 事件流长连接(无超时)
+	sessionID    string
+	timeout      time.Duration
+	apiKey       string // 访问 MCP Server 的凭证(API Key 或 JWT)
 }
 
 // NewHTTPClient 创建 HTTP MCP 客户端
@@ -26,7 +44,20 @@ func NewHTTPClient(baseURL string, timeoutSeconds int) *HTTPClient {
 		httpClient: &http.Client{
 			Timeout: time.Duration(timeoutSeconds) * time.Second,
 		},
-		timeout: time.Duration(timeoutSeconds) * time.Second,
+		streamClient: &http.Client{},
+		timeout:      time.Duration(timeoutSeconds) * time.Second,
+	}
+}
+
+// SetAPIKey 设置访问 MCP Server 的凭证，以 Bearer 方式随每个请求发送
+func (c *HTTPClient) SetAPIKey(apiKey string) {
+	c.apiKey = apiKey
+}
+
+// authorize 为请求附加凭证
+func (c *HTTPClient) authorize(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
 }
 
@@ -54,6 +85,7 @@ func (c *HTTPClient) Initialize(ctx context.Context, sessionID, serverURL string
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	c.authorize(httpReq)
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -90,6 +122,7 @@ func (c *HTTPClient) CallTool(ctx context.Context, sessionID string, toolName st
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	c.authorize(httpReq)
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -110,49 +143,142 @@ func (c *HTTPClient) CallTool(ctx context.Context, sessionID string, toolName st
 	return result, nil
 }
 
-// SubscribeEvents 订阅事件(HTTP 轮询模拟)
+// SubscribeEvents 订阅事件
+// 优先使用 SSE 事件流,断线后携带 Last-Event-ID 重连续传;服务器不支持事件流时退回游标轮询
 func (c *HTTPClient) SubscribeEvents(ctx context.Context, sessionID string, eventTypes []string) (<-chan Event, error) {
-	eventChan := make(chan Event, 10)
+	eventChan := make(chan Event, 64)
 
 	go func() {
 		defer close(eventChan)
 
-		ticker := time.NewTicker(2 * time.Second)
-		defer ticker.Stop()
+		// 从当前位置开始跟随,断线期间的事件在重连时补发
+		cursor := int64(-1)
+		if page, err := c.pollEvents(ctx, sessionID, eventTypes, -1); err == nil {
+			cursor = page.Cursor
+		}
 
 		for {
+			err := c.streamEvents(ctx, sessionID, eventTypes, &cursor, eventChan)
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, errStreamUnsupported) {
+				c.followByPolling(ctx, sessionID, eventTypes, cursor, eventChan)
+				return
+			}
+
 			select {
 			case <-ctx.Done():
 				return
-			case <-ticker.C:
-				// 轮询获取事件
-				events, err := c.pollEvents(ctx, sessionID, eventTypes)
-				if err != nil {
-					continue
+			case <-time.After(EventRetryInterval):
+			}
+		}
+	}()
+
+	return eventChan, nil
+}
+
+// streamEvents 读取 SSE 事件流直到连接断开,cursor 随收到的事件推进
+func (c *HTTPClient) streamEvents(ctx context.Context, sessionID string, eventTypes []string, cursor *int64, out chan<- Event) error {
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		c.baseURL+"/events/stream?"+eventsQuery(sessionID, eventTypes, -1), nil)
+	if err != nil {
+		return fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.authorize(req)
+	if *cursor >= 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(*cursor, 10))
+	}
+
+	// 事件流是长连接,不能使用带超时的客户端
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("连接事件流失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return errStreamUnsupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("连接事件流失败: status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(data.String()), &event); err == nil {
+				if event.Cursor > *cursor {
+					*cursor = event.Cursor
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return ctx.Err()
 				}
+			}
+			data.Reset()
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取事件流失败: %w", err)
+	}
+	return nil
+}
 
-				for _, event := range events {
-					select {
-					case eventChan <- event:
-					case <-ctx.Done():
-						return
-					}
+// followByPolling 按游标轮询事件
+func (c *HTTPClient) followByPolling(ctx context.Context, sessionID string, eventTypes []string, cursor int64, out chan<- Event) {
+	ticker := time.NewTicker(EventPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			page, err := c.pollEvents(ctx, sessionID, eventTypes, cursor)
+			if err != nil {
+				continue
+			}
+			cursor = page.Cursor
+
+			for _, event := range page.Events {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
 				}
 			}
 		}
-	}()
+	}
+}
 
-	return eventChan, nil
+// eventPage 轮询返回的一页事件
+type eventPage struct {
+	Events []Event `json:"events"`
+	Cursor int64   `json:"cursor"` // 下次轮询的起始游标
 }
 
-// pollEvents 轮询事件
-func (c *HTTPClient) pollEvents(ctx context.Context, sessionID string, eventTypes []string) ([]Event, error) {
-	// 构建查询参数
+// pollEvents 轮询 after 之后的事件;after 为负数时只获取当前游标
+func (c *HTTPClient) pollEvents(ctx context.Context, sessionID string, eventTypes []string, after int64) (*eventPage, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET",
-		c.baseURL+"/events?session_id="+sessionID, nil)
+		c.baseURL+"/events?"+eventsQuery(sessionID, eventTypes, after), nil)
 	if err != nil {
 		return nil, err
 	}
+	c.authorize(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -164,12 +290,28 @@ func (c *HTTPClient) pollEvents(ctx context.Context, sessionID string, eventType
 		return nil, fmt.Errorf("获取事件失败: status %d", resp.StatusCode)
 	}
 
-	var events []Event
-	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+	var page eventPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return nil, err
 	}
+	if page.Cursor < after {
+		page.Cursor = after
+	}
 
-	return events, nil
+	return &page, nil
+}
+
+// eventsQuery 构建事件接口的查询参数
+func eventsQuery(sessionID string, eventTypes []string, after int64) string {
+	query := url.Values{}
+	query.Set("session_id", sessionID)
+	if len(eventTypes) > 0 {
+		query.Set("types", strings.Join(eventTypes, ","))
+	}
+	if after >= 0 {
+		query.Set("after", strconv.FormatInt(after, 10))
+	}
+	return query.Encode()
 }
 
 // Close 关闭连接
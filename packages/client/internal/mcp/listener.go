@@ -4,6 +4,7 @@ package mcp
 import (
 	"context"
 	"log"
+	"sort"
 
 	"github.com/dnd-mcp/client/internal/ws"
 )
@@ -28,13 +29,12 @@ func NewEventListener(mcpClient MCPClient, wsHub *ws.Hub) *EventListener {
 func (l *EventListener) Start(ctx context.Context, sessionID string) error {
 	l.sessionID = sessionID
 
-	// 订阅所有事件类型
-	eventTypes := []string{
-		"state_changed",
-		"combat_updated",
-		"character_moved",
-		"dice_rolled",
+	// 订阅所有游戏事件类型
+	eventTypes := make([]string, 0, len(wsEventTypes))
+	for eventType := range wsEventTypes {
+		eventTypes = append(eventTypes, eventType)
 	}
+	sort.Strings(eventTypes)
 
 	eventChan, err := l.mcpClient.SubscribeEvents(ctx, sessionID, eventTypes)
 	if err != nil {
@@ -61,15 +61,47 @@ func (l *EventListener) processEvents(ctx context.Context) {
 			}
 
 			// 转换为 WebSocket 事件并广播
-			wsEvent := ws.Event{
-				SessionID: l.sessionID,
-				Type:      event.Type,
-				Data:      event.Data,
-			}
+			wsEvent := ToWSEvent(l.sessionID, event)
 
-			l.wsHub.Broadcast <- wsEvent
+			select {
+			case l.wsHub.Broadcast <- *wsEvent:
+			case <-ctx.Done():
+				return
+			}
 
 			log.Printf("[MCP] 事件已广播: type=%s, session=%s", event.Type, l.sessionID)
 		}
 	}
 }
+
+// wsEventTypes 游戏事件类型到 WebSocket 事件类型的映射
+var wsEventTypes = map[string]string{
+	EventHPChanged:        "combat_updated",
+	EventConditionApplied: "combat_updated",
+	EventConditionRemoved: "combat_updated",
+	EventTurnAdvanced:     "combat_updated",
+	EventTokenMoved:       "character_moved",
+	EventDiceRolled:       "dice_rolled",
+	EventMapEntered:       "state_changed",
+}
+
+// ToWSEvent 将游戏事件转换为 WebSocket 事件
+// 事件数据中保留原始事件类型(event)和游标(cursor),便于前端区分和去重
+func ToWSEvent(sessionID string, event Event) *ws.Event {
+	wsType, ok := wsEventTypes[event.Type]
+	if !ok {
+		wsType = event.Type
+	}
+
+	data := make(map[string]interface{}, len(event.Data)+3)
+	for k, v := range event.Data {
+		data[k] = v
+	}
+	data["event"] = event.Type
+	data["session_id"] = sessionID
+	if event.Cursor > 0 {
+		data["cursor"] = event.Cursor
+	}
+
+	return ws.NewEvent(sessionID, wsType, data)
+}
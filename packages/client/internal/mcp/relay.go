@@ -0,0 +1,70 @@
+// Package mcp 提供 MCP 事件中继
+package mcp
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/dnd-mcp/client/internal/ws"
+)
+
+// EventRelay 事件中继
+// 会话有 WebSocket 连接时订阅该会话的游戏事件并转发给连接,最后一个连接断开后停止订阅
+type EventRelay struct {
+	ctx       context.Context
+	mcpClient MCPClient
+	wsHub     *ws.Hub
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewEventRelay 创建事件中继
+func NewEventRelay(ctx context.Context, mcpClient MCPClient, wsHub *ws.Hub) *EventRelay {
+	return &EventRelay{
+		ctx:       ctx,
+		mcpClient: mcpClient,
+		wsHub:     wsHub,
+		cancels:   make(map[string]context.CancelFunc),
+	}
+}
+
+// SessionOpened 开始转发会话事件
+func (r *EventRelay) SessionOpened(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.cancels[sessionID]; ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	listener := NewEventListener(r.mcpClient, r.wsHub)
+	if err := listener.Start(ctx, sessionID); err != nil {
+		cancel()
+		log.Printf("[MCP] 订阅会话事件失败: session=%s, error=%v", sessionID, err)
+		return
+	}
+
+	r.cancels[sessionID] = cancel
+}
+
+// SessionClosed 停止转发会话事件
+func (r *EventRelay) SessionClosed(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cancel, ok := r.cancels[sessionID]; ok {
+		cancel()
+		delete(r.cancels, sessionID)
+	}
+}
+
+// Sessions 返回正在转发事件的会话数
+func (r *EventRelay) Sessions() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.cancels)
+}
@@ -22,6 +22,29 @@ type Session struct {
 	Status       string                 `json:"status"`
 }
 
+// 会话状态
+const (
+	SessionStatusActive   = "active"
+	SessionStatusPaused   = "paused" // LLM 用量超过预算，暂停对话
+	SessionStatusArchived = "archived"
+)
+
+// 战役设置中 LLM 相关的键
+const (
+	SettingLLMProvider    = "llm_provider"     // LLM provider 名称，为空时使用默认 provider
+	SettingLLMModel       = "llm_model"        // 模型名称，为空时使用 provider 的默认模型
+	SettingLLMTokenBudget = "llm_token_budget" // token 上限，覆盖默认预算
+	SettingLLMCostBudget  = "llm_cost_budget"  // 费用上限（美元），覆盖默认预算
+)
+
+// 战役设置中回合协调相关的键和取值
+const (
+	SettingTurnMode = "turn_mode" // 回合模式，覆盖默认配置
+
+	TurnModeFree       = "free"       // 所有玩家都可以发言
+	TurnModeInitiative = "initiative" // 战斗中只接受当前行动者的玩家发言
+)
+
 // Campaign 是 Session 的类型别名，保持 API 兼容性
 // 推荐在代码中使用 Campaign 术语，API 层会自动映射
 type Campaign = Session
@@ -38,7 +61,7 @@ func NewSession(name, creatorID, mcpServerURL string) *Session {
 		Name:         name,
 		CreatorID:    creatorID,
 		MCPServerURL: mcpServerURL,
-		Status:       "active",
+		Status:       SessionStatusActive,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 		MaxPlayers:   4, // 默认最大4个玩家
@@ -48,12 +71,17 @@ func NewSession(name, creatorID, mcpServerURL string) *Session {
 
 // IsActive 检查会话是否活跃
 func (s *Session) IsActive() bool {
-	return s.Status == "active"
+	return s.Status == SessionStatusActive
+}
+
+// IsPaused 检查会话是否已暂停
+func (s *Session) IsPaused() bool {
+	return s.Status == SessionStatusPaused
 }
 
 // Archive 归档会话
 func (s *Session) Archive() {
-	s.Status = "archived"
+	s.Status = SessionStatusArchived
 	s.UpdatedAt = time.Now()
 }
 
@@ -67,3 +95,23 @@ func (s *Session) UpdateSettings(settings map[string]interface{}) {
 	}
 	s.UpdatedAt = time.Now()
 }
+
+// SettingString 返回字符串设置，不存在或不是字符串时返回空字符串
+func (s *Session) SettingString(key string) string {
+	value, _ := s.Settings[key].(string)
+	return value
+}
+
+// SettingFloat 返回数值设置，不存在或不是数值时返回 false
+func (s *Session) SettingFloat(key string) (float64, bool) {
+	switch value := s.Settings[key].(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,127 @@
+// Package monitor 提供 LLM 用量统计
+package monitor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ModelPrice 模型价格（美元/1K token）
+type ModelPrice struct {
+	Prompt     float64
+	Completion float64
+}
+
+// UsageRecord LLM 用量
+type UsageRecord struct {
+	Requests         int64     `json:"requests"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	TotalTokens      int64     `json:"total_tokens"`
+	Cost             float64   `json:"cost_usd"`
+	LastUsedAt       time.Time `json:"last_used_at,omitempty"`
+}
+
+// add 累加一次调用的用量
+func (r *UsageRecord) add(promptTokens, completionTokens int, cost float64, at time.Time) {
+	r.Requests++
+	r.PromptTokens += int64(promptTokens)
+	r.CompletionTokens += int64(completionTokens)
+	r.TotalTokens += int64(promptTokens + completionTokens)
+	r.Cost += cost
+	r.LastUsedAt = at
+}
+
+// UsageTracker 按会话统计 LLM token 用量和费用（内存中，重启后清零）
+type UsageTracker struct {
+	prices map[string]ModelPrice
+
+	mu       sync.RWMutex
+	total    UsageRecord
+	sessions map[string]*UsageRecord
+}
+
+// NewUsageTracker 创建用量统计器，prices 按模型名称计价，未配置价格的模型费用为 0
+func NewUsageTracker(prices map[string]ModelPrice) *UsageTracker {
+	if prices == nil {
+		prices = make(map[string]ModelPrice)
+	}
+	return &UsageTracker{
+		prices:   prices,
+		sessions: make(map[string]*UsageRecord),
+	}
+}
+
+// RecordUsage 记录一次 LLM 调用的用量，sessionID 为空时只计入总量
+func (t *UsageTracker) RecordUsage(sessionID, model string, promptTokens, completionTokens int) {
+	price := t.price(model)
+	cost := (float64(promptTokens)*price.Prompt + float64(completionTokens)*price.Completion) / 1000
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.total.add(promptTokens, completionTokens, cost, now)
+	if sessionID == "" {
+		return
+	}
+	record, ok := t.sessions[sessionID]
+	if !ok {
+		record = &UsageRecord{}
+		t.sessions[sessionID] = record
+	}
+	record.add(promptTokens, completionTokens, cost, now)
+}
+
+// Usage 返回会话的用量
+func (t *UsageTracker) Usage(sessionID string) UsageRecord {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if record, ok := t.sessions[sessionID]; ok {
+		return *record
+	}
+	return UsageRecord{}
+}
+
+// price 返回模型价格：先精确匹配，再匹配最长的前缀（API 返回的模型名称常带版本后缀）
+func (t *UsageTracker) price(model string) ModelPrice {
+	if price, ok := t.prices[model]; ok {
+		return price
+	}
+	var matched ModelPrice
+	longest := 0
+	for name, price := range t.prices {
+		if len(name) > longest && strings.HasPrefix(model, name) {
+			matched = price
+			longest = len(name)
+		}
+	}
+	return matched
+}
+
+// Collect 收集用量统计信息
+func (t *UsageTracker) Collect(ctx context.Context) map[string]interface{} {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	sessions := make(map[string]interface{}, len(t.sessions))
+	for id, record := range t.sessions {
+		sessions[id] = *record
+	}
+
+	return map[string]interface{}{
+		"requests":          t.total.Requests,
+		"prompt_tokens":     t.total.PromptTokens,
+		"completion_tokens": t.total.CompletionTokens,
+		"total_tokens":      t.total.TotalTokens,
+		"cost_usd":          t.total.Cost,
+		"sessions":          sessions,
+	}
+}
+
+// Name 返回收集器名称
+func (t *UsageTracker) Name() string {
+	return "llm_usage"
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestUsageTracker_RecordUsage(t *testing.T) {
+	tracker := NewUsageTracker(map[string]ModelPrice{
+		"gpt-4o":      {Prompt: 0.0025, Completion: 0.01},
+		"gpt-4o-mini": {Prompt: 0.00015, Completion: 0.0006},
+	})
+
+	tracker.RecordUsage("session-1", "gpt-4o", 1000, 500)
+	// 带版本后缀的模型名称按最长前缀计价
+	tracker.RecordUsage("session-1", "gpt-4o-mini-2024-07-18", 2000, 1000)
+	// 未配置价格的模型只统计 token
+	tracker.RecordUsage("session-2", "llama3.1", 300, 100)
+	// 没有会话的调用只计入总量
+	tracker.RecordUsage("", "gpt-4o", 100, 0)
+
+	usage := tracker.Usage("session-1")
+	if usage.Requests != 2 {
+		t.Errorf("expected 2 requests, got %d", usage.Requests)
+	}
+	if usage.TotalTokens != 4500 {
+		t.Errorf("expected 4500 tokens, got %d", usage.TotalTokens)
+	}
+	expectedCost := 0.0025 + 0.005 + 0.0003 + 0.0006
+	if math.Abs(usage.Cost-expectedCost) > 1e-9 {
+		t.Errorf("expected cost %v, got %v", expectedCost, usage.Cost)
+	}
+	if usage.LastUsedAt.IsZero() {
+		t.Error("expected last used time to be set")
+	}
+
+	if cost := tracker.Usage("session-2").Cost; cost != 0 {
+		t.Errorf("expected zero cost for unpriced model, got %v", cost)
+	}
+	if requests := tracker.Usage("unknown").Requests; requests != 0 {
+		t.Errorf("expected no usage for unknown session, got %d", requests)
+	}
+
+	stats := tracker.Collect(context.Background())
+	if stats["requests"] != int64(4) {
+		t.Errorf("expected 4 total requests, got %v", stats["requests"])
+	}
+	if stats["total_tokens"] != int64(5000) {
+		t.Errorf("expected 5000 total tokens, got %v", stats["total_tokens"])
+	}
+	sessions, ok := stats["sessions"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected sessions to be a map")
+	}
+	if len(sessions) != 2 {
+		t.Errorf("expected 2 sessions, got %d", len(sessions))
+	}
+}
+
+func TestUsageTracker_StatsMonitor(t *testing.T) {
+	monitor := NewStatsMonitor("v1.0.0")
+	tracker := NewUsageTracker(nil)
+	monitor.Register(tracker)
+
+	tracker.RecordUsage("session-1", "gpt-4o", 10, 5)
+
+	stats := monitor.Collect(context.Background())
+	usage, ok := stats.Components["llm_usage"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected llm_usage component to be a map")
+	}
+	if usage["total_tokens"] != int64(15) {
+		t.Errorf("expected 15 total tokens, got %v", usage["total_tokens"])
+	}
+}
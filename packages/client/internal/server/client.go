@@ -4,6 +4,7 @@ package server
 import (
 	"context"
 
+	"github.com/dnd-mcp/client/internal/mcp"
 	"github.com/dnd-mcp/client/pkg/config"
 )
 
@@ -24,6 +25,9 @@ type ServerClient interface {
 	// CallTool 调用 Server MCP Tool（复用现有 mcp.MCPClient）
 	CallTool(ctx context.Context, campaignID, toolName string, args map[string]any) (map[string]any, error)
 
+	// ListTools 获取 Server 提供的工具目录
+	ListTools(ctx context.Context) ([]mcp.Tool, error)
+
 	// Close 关闭客户端连接
 	Close(ctx context.Context) error
 }
@@ -34,5 +38,7 @@ func NewClient(cfg *config.ServerConfig) (ServerClient, error) {
 		return NewMockClient(), nil
 	}
 
-	return NewHTTPClient(cfg.ServerURL, cfg.Timeout), nil
+	client := NewHTTPClient(cfg.ServerURL, cfg.Timeout)
+	client.SetAPIKey(cfg.APIKey)
+	return client, nil
 }
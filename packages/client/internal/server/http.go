@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/dnd-mcp/client/internal/mcp"
 )
 
 // mcpContent MCP 响应内容
@@ -31,6 +33,7 @@ type HTTPClient struct {
 	timeout     time.Duration
 	mu          sync.RWMutex
 	initialized bool
+	apiKey      string // 访问 Server 的凭证(API Key 或 JWT)
 }
 
 // NewHTTPClient 创建 HTTP Server 客户端
@@ -45,6 +48,18 @@ func NewHTTPClient(baseURL string, timeoutSeconds int) *HTTPClient {
 	}
 }
 
+// SetAPIKey 设置访问 Server 的凭证，以 Bearer 方式随每个请求发送
+func (c *HTTPClient) SetAPIKey(apiKey string) {
+	c.apiKey = apiKey
+}
+
+// authorize 为请求附加凭证
+func (c *HTTPClient) authorize(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+}
+
 // mcpCall 是核心方法，所有操作都通过它调用 MCP Tool
 func (c *HTTPClient) mcpCall(ctx context.Context, toolName string, args map[string]any) (*mcpCallToolResponse, error) {
 	url := fmt.Sprintf("%s/mcp/tools/call", c.baseURL)
@@ -65,6 +80,7 @@ func (c *HTTPClient) mcpCall(ctx context.Context, toolName string, args map[stri
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	c.authorize(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -124,6 +140,7 @@ func (c *HTTPClient) Initialize(ctx context.Context) error {
 		return fmt.Errorf("创建初始化请求失败: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	c.authorize(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -194,6 +211,10 @@ func (c *HTTPClient) SaveMessage(ctx context.Context, campaignID string, msg *Me
 		args["player_id"] = msg.PlayerID
 	}
 
+	if msg.ToolCallID != "" {
+		args["tool_call_id"] = msg.ToolCallID
+	}
+
 	if len(msg.ToolCalls) > 0 {
 		toolCalls := make([]map[string]any, len(msg.ToolCalls))
 		for i, tc := range msg.ToolCalls {
@@ -242,6 +263,37 @@ func (c *HTTPClient) CallTool(ctx context.Context, campaignID, toolName string,
 	return *result, nil
 }
 
+// ListTools 获取 Server 提供的工具目录
+func (c *HTTPClient) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	url := fmt.Sprintf("%s/mcp/tools", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+	c.authorize(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("获取工具目录失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("获取工具目录失败 (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	return result.Tools, nil
+}
+
 // Close 关闭连接
 func (c *HTTPClient) Close(ctx context.Context) error {
 	// HTTP 无状态,无需关闭
@@ -4,6 +4,8 @@ package server
 import (
 	"context"
 	"time"
+
+	"github.com/dnd-mcp/client/internal/mcp"
 )
 
 // MockClient Mock Server 客户端
@@ -12,6 +14,8 @@ type MockClient struct {
 	Messages []Message
 	// 是否返回错误
 	ReturnError bool
+	// ListTools 返回的工具目录
+	Tools []mcp.Tool
 }
 
 // NewMockClient 创建 Mock 客户端
@@ -19,6 +23,31 @@ func NewMockClient() *MockClient {
 	return &MockClient{
 		Messages:    make([]Message, 0),
 		ReturnError: false,
+		Tools: []mcp.Tool{
+			{
+				Name:        "roll_dice",
+				Description: "Roll dice using standard D&D notation (e.g. 1d20+5)",
+				InputSchema: mcp.InputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"formula": map[string]interface{}{"type": "string", "description": "Dice formula"},
+					},
+					Required: []string{"formula"},
+				},
+			},
+			{
+				Name:        "resolve_attack",
+				Description: "Resolve an attack from one character against another",
+				InputSchema: mcp.InputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"attacker": map[string]interface{}{"type": "string", "description": "Attacker character ID"},
+						"target":   map[string]interface{}{"type": "string", "description": "Target character ID"},
+					},
+					Required: []string{"attacker", "target"},
+				},
+			},
+		},
 	}
 }
 
@@ -185,6 +214,14 @@ func (m *MockClient) CallTool(ctx context.Context, campaignID, toolName string,
 	}
 }
 
+// ListTools 获取 Server 提供的工具目录
+func (m *MockClient) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	if m.ReturnError {
+		return nil, &MockError{Msg: "mock error"}
+	}
+	return m.Tools, nil
+}
+
 // Close 关闭连接
 func (m *MockClient) Close(_ context.Context) error {
 	return nil
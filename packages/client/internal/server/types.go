@@ -32,6 +32,7 @@ type Message struct {
 	Content    string      `json:"content"`
 	PlayerID   string      `json:"player_id"`
 	ToolCalls  []ToolCall  `json:"tool_calls"`
+	ToolCallID string      `json:"tool_call_id,omitempty"`
 	CreatedAt  time.Time   `json:"created_at"`
 }
 
@@ -42,6 +43,7 @@ const (
 	MessageRoleUser      MessageRole = "user"
 	MessageRoleAssistant MessageRole = "assistant"
 	MessageRoleSystem    MessageRole = "system"
+	MessageRoleTool      MessageRole = "tool"
 )
 
 // ToolCall 工具调用（与 Server 端 models.ToolCall 对齐）
@@ -99,6 +101,7 @@ type Character struct {
 	ID              string           `json:"id"`
 	Name            string           `json:"name"`
 	Type            string           `json:"type"` // pc, npc, enemy
+	PlayerID        string           `json:"player_id,omitempty"` // 玩家ID（玩家角色使用）
 	HP              int              `json:"hp"`
 	MaxHP           int              `json:"max_hp"`
 	AC              int              `json:"ac"`
@@ -173,6 +176,34 @@ type Combat struct {
 	Initiative   []Initiative   `json:"initiative"`
 	CurrentActor string         `json:"current_actor"`
 	StartedAt    time.Time      `json:"started_at"`
+
+	// 与 Server 端 models.Combat 对齐的字段
+	Status       string              `json:"status,omitempty"` // active, finished
+	TurnIndex    int                 `json:"turn_index"`
+	Participants []CombatParticipant `json:"participants,omitempty"`
+}
+
+// CombatParticipant 参战者（与 Server 端 models.Participant 对齐）
+type CombatParticipant struct {
+	CharacterID string `json:"character_id"`
+	Initiative  int    `json:"initiative"`
+	HasActed    bool   `json:"has_acted"`
+}
+
+// IsActive 检查战斗是否进行中
+func (c *Combat) IsActive() bool {
+	return c.Active || c.Status == "active"
+}
+
+// CurrentCharacterID 返回当前行动者的角色 ID，没有行动者时返回空字符串
+func (c *Combat) CurrentCharacterID() string {
+	if c.CurrentActor != "" {
+		return c.CurrentActor
+	}
+	if c.TurnIndex >= 0 && c.TurnIndex < len(c.Participants) {
+		return c.Participants[c.TurnIndex].CharacterID
+	}
+	return ""
 }
 
 // Initiative 先攻
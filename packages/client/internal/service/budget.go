@@ -0,0 +1,136 @@
+// Package service 提供战役 LLM 用量预算
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dnd-mcp/client/internal/models"
+	"github.com/dnd-mcp/client/internal/monitor"
+	"github.com/dnd-mcp/client/internal/ws"
+)
+
+// ErrCampaignPaused 战役已暂停（LLM 用量超过预算），不再处理新消息
+var ErrCampaignPaused = errors.New("战役已暂停：LLM 用量超过预算")
+
+// UsageBudget 战役的 LLM 用量上限，0 表示不限制
+type UsageBudget struct {
+	MaxTokens int64   `json:"max_tokens"`
+	MaxCost   float64 `json:"max_cost_usd"`
+}
+
+// Exceeded 检查用量是否达到上限
+func (b UsageBudget) Exceeded(usage monitor.UsageRecord) bool {
+	return (b.MaxTokens > 0 && usage.TotalTokens >= b.MaxTokens) ||
+		(b.MaxCost > 0 && usage.Cost >= b.MaxCost)
+}
+
+// UsageReader 读取会话的 LLM 用量（由 monitor.UsageTracker 实现）
+type UsageReader interface {
+	Usage(sessionID string) monitor.UsageRecord
+}
+
+// CampaignStatusUpdater 读取和更新战役（由 SessionService 实现）
+type CampaignStatusUpdater interface {
+	GetSession(ctx context.Context, sessionID string) (*models.Session, error)
+	UpdateSession(ctx context.Context, sessionID string, req *UpdateSessionRequest) (*models.Session, error)
+}
+
+// UsageReport 战役的 LLM 用量和预算
+type UsageReport struct {
+	CampaignID string              `json:"campaign_id"`
+	Usage      monitor.UsageRecord `json:"usage"`
+	Budget     UsageBudget         `json:"budget"`
+	Paused     bool                `json:"paused"`
+}
+
+// BudgetGuard 检查战役的 LLM 用量预算，超出时暂停战役
+// 预算默认使用配置值，战役设置中的 llm_token_budget / llm_cost_budget 可以覆盖；
+// 暂停后需要提高预算并把状态改回 active 才能继续对话
+type BudgetGuard struct {
+	usage       UsageReader
+	campaigns   CampaignStatusUpdater
+	defaults    UsageBudget
+	broadcaster SessionBroadcaster // 可选，暂停时推送 campaign_paused 事件
+}
+
+// NewBudgetGuard 创建预算检查
+func NewBudgetGuard(usage UsageReader, campaigns CampaignStatusUpdater, defaults UsageBudget) *BudgetGuard {
+	return &BudgetGuard{
+		usage:     usage,
+		campaigns: campaigns,
+		defaults:  defaults,
+	}
+}
+
+// SetBroadcaster 设置会话事件广播
+func (g *BudgetGuard) SetBroadcaster(broadcaster SessionBroadcaster) {
+	g.broadcaster = broadcaster
+}
+
+// Budget 返回战役的预算
+func (g *BudgetGuard) Budget(campaign *models.Session) UsageBudget {
+	budget := g.defaults
+	if tokens, ok := campaign.SettingFloat(models.SettingLLMTokenBudget); ok {
+		budget.MaxTokens = int64(tokens)
+	}
+	if cost, ok := campaign.SettingFloat(models.SettingLLMCostBudget); ok {
+		budget.MaxCost = cost
+	}
+	return budget
+}
+
+// Check 检查战役是否可以继续调用 LLM
+// 战役已暂停时返回 ErrCampaignPaused；用量达到预算时暂停战役并返回 ErrCampaignPaused
+func (g *BudgetGuard) Check(ctx context.Context, campaignID string) error {
+	campaign, err := g.campaigns.GetSession(ctx, campaignID)
+	if err != nil {
+		// 读不到战役时无法判断预算，不阻止对话
+		log.Printf("读取战役 %s 失败，跳过预算检查: %v", campaignID, err)
+		return nil
+	}
+	if campaign.IsPaused() {
+		return ErrCampaignPaused
+	}
+
+	usage := g.usage.Usage(campaignID)
+	budget := g.Budget(campaign)
+	if !budget.Exceeded(usage) {
+		return nil
+	}
+
+	paused := models.SessionStatusPaused
+	if _, err := g.campaigns.UpdateSession(ctx, campaignID, &UpdateSessionRequest{Status: &paused}); err != nil {
+		return fmt.Errorf("暂停战役失败: %w", err)
+	}
+	log.Printf("战役 %s 的 LLM 用量超过预算（%d tokens, $%.4f），已暂停", campaignID, usage.TotalTokens, usage.Cost)
+
+	if g.broadcaster != nil {
+		g.broadcaster.BroadcastToSession(campaignID, *ws.NewEvent(campaignID, ws.EventCampaignPaused, map[string]interface{}{
+			"session_id": campaignID,
+			"reason":     "budget_exceeded",
+			"usage":      usage,
+			"budget":     budget,
+			"timestamp":  time.Now().Format(time.RFC3339),
+		}))
+	}
+	return ErrCampaignPaused
+}
+
+// Report 返回战役的用量报告
+func (g *BudgetGuard) Report(ctx context.Context, campaignID string) (*UsageReport, error) {
+	campaign, err := g.campaigns.GetSession(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UsageReport{
+		CampaignID: campaignID,
+		Usage:      g.usage.Usage(campaignID),
+		Budget:     g.Budget(campaign),
+		Paused:     campaign.IsPaused(),
+	}, nil
+}
@@ -5,12 +5,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/dnd-mcp/client/internal/llm"
 	"github.com/dnd-mcp/client/internal/mcp"
 	"github.com/dnd-mcp/client/internal/models"
 	"github.com/dnd-mcp/client/internal/server"
+	"github.com/dnd-mcp/client/internal/ws"
 	"github.com/google/uuid"
 )
 
@@ -25,8 +28,54 @@ type ChatServiceInterface interface {
 
 // SendMessageRequest 发送消息请求
 type SendMessageRequest struct {
-	Content  string
-	PlayerID string
+	Content    string
+	PlayerID   string
+	PlayerName string // 可选，合并多名玩家的消息时用于标注发言者，为空时使用 PlayerID
+	Stream     bool   // 通过 WebSocket 推送回复的增量内容
+}
+
+// speaker 返回合并消息时标注的发言者
+func (r *SendMessageRequest) speaker() string {
+	if r.PlayerName != "" {
+		return r.PlayerName
+	}
+	return r.PlayerID
+}
+
+// batchContent 合并同一轮的玩家消息，每行以发言者开头；只有一条消息时保持原样
+func batchContent(reqs []*SendMessageRequest) string {
+	if len(reqs) == 1 {
+		return reqs[0].Content
+	}
+	lines := make([]string, len(reqs))
+	for i, req := range reqs {
+		lines[i] = req.speaker() + ": " + req.Content
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SessionBroadcaster 会话事件广播（由 ws.Hub 实现）
+type SessionBroadcaster interface {
+	BroadcastToSession(sessionID string, event ws.Event)
+}
+
+// SessionGetter 读取会话（由 store.SessionStore 实现）
+type SessionGetter interface {
+	Get(ctx context.Context, id string) (*models.Session, error)
+}
+
+// ToolLoopConfig 工具调用循环配置
+type ToolLoopConfig struct {
+	// MaxIterations 单轮对话中最多调用 LLM 的次数（最后一次不再提供工具，要求模型直接回复）
+	MaxIterations int
+	// MaxToolCalls 单轮对话中最多执行的工具调用数，超出的调用直接返回错误结果
+	MaxToolCalls int
+}
+
+// DefaultToolLoopConfig 默认工具调用循环配置
+var DefaultToolLoopConfig = ToolLoopConfig{
+	MaxIterations: 8,
+	MaxToolCalls:  16,
 }
 
 // ChatService 聊天服务实现
@@ -36,6 +85,12 @@ type ChatService struct {
 	llmClient      llm.LLMClient
 	mcpClient      mcp.MCPClient
 	contextBuilder *ContextBuilder
+	toolCatalog    *ToolCatalog       // 可选，未设置时不向 LLM 提供工具
+	broadcaster    SessionBroadcaster // 可选，未设置时不推送对话事件
+	llmPool        *llm.ClientPool    // 可选，与 sessions 一起设置后按战役选择 LLM
+	sessions       SessionGetter
+	budget         *BudgetGuard // 可选，未设置时不检查用量预算
+	toolLoop       ToolLoopConfig
 }
 
 // NewChatService 创建聊天服务
@@ -50,64 +105,265 @@ func NewChatService(
 		llmClient:      llmClient,
 		mcpClient:      mcpClient,
 		contextBuilder: contextBuilder,
+		toolLoop:       DefaultToolLoopConfig,
+	}
+}
+
+// SetToolCatalog 设置工具目录，LLM 将可以调用目录中的工具
+func (s *ChatService) SetToolCatalog(catalog *ToolCatalog) {
+	s.toolCatalog = catalog
+}
+
+// SetBroadcaster 设置会话事件广播
+// 设置后推送工具调用的开始/完成和最终回复，流式请求还会推送回复的增量内容
+func (s *ChatService) SetBroadcaster(broadcaster SessionBroadcaster) {
+	s.broadcaster = broadcaster
+}
+
+// SetLLMPool 启用按战役选择 LLM
+// 战役设置中的 llm_provider 从 pool 中选择客户端，llm_model 覆盖模型；未设置时使用默认客户端
+func (s *ChatService) SetLLMPool(pool *llm.ClientPool, sessions SessionGetter) {
+	s.llmPool = pool
+	s.sessions = sessions
+}
+
+// SetBudgetGuard 设置用量预算检查
+// 每轮对话开始前检查战役是否已暂停或超出预算，对话结束后再次检查以便及时暂停
+func (s *ChatService) SetBudgetGuard(guard *BudgetGuard) {
+	s.budget = guard
+}
+
+// SetToolLoopConfig 设置工具调用循环配置，非正数的字段使用默认值
+func (s *ChatService) SetToolLoopConfig(config ToolLoopConfig) {
+	if config.MaxIterations <= 0 {
+		config.MaxIterations = DefaultToolLoopConfig.MaxIterations
 	}
+	if config.MaxToolCalls <= 0 {
+		config.MaxToolCalls = DefaultToolLoopConfig.MaxToolCalls
+	}
+	s.toolLoop = config
 }
 
 // SendMessage 发送消息并获取 AI 响应
+// LLM 请求工具调用时执行工具并将结果交回 LLM，直到 LLM 给出最终回复
 func (s *ChatService) SendMessage(ctx context.Context, campaignID string, req *SendMessageRequest) (*models.Message, error) {
-	// 1. 保存用户消息到 Server
-	userMsg := &server.Message{
-		ID:         uuid.New().String(),
-		CampaignID: campaignID,
-		Role:       server.MessageRoleUser,
-		Content:    req.Content,
-		PlayerID:   req.PlayerID,
-		CreatedAt:  time.Now(),
+	return s.SendBatch(ctx, campaignID, []*SendMessageRequest{req})
+}
+
+// SendBatch 将多名玩家的消息作为一轮对话发送，DM 只回复一次
+// 每条玩家消息单独保存，交给 LLM 的用户消息按 "玩家: 内容" 逐行合并；任一消息要求流式时流式推送回复
+func (s *ChatService) SendBatch(ctx context.Context, campaignID string, reqs []*SendMessageRequest) (*models.Message, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("消息不能为空")
+	}
+	if s.budget != nil {
+		if err := s.budget.Check(ctx, campaignID); err != nil {
+			return nil, err
+		}
 	}
-	if err := s.serverClient.SaveMessage(ctx, campaignID, userMsg); err != nil {
-		return nil, fmt.Errorf("保存用户消息失败: %w", err)
+	// LLM 中间件按战役限流和统计用量
+	ctx = llm.WithCampaign(ctx, campaignID)
+
+	// 1. 保存用户消息到 Server
+	stream := false
+	for _, req := range reqs {
+		userMsg := &server.Message{
+			ID:         uuid.New().String(),
+			CampaignID: campaignID,
+			Role:       server.MessageRoleUser,
+			Content:    req.Content,
+			PlayerID:   req.PlayerID,
+			CreatedAt:  time.Now(),
+		}
+		if err := s.serverClient.SaveMessage(ctx, campaignID, userMsg); err != nil {
+			return nil, fmt.Errorf("保存用户消息失败: %w", err)
+		}
+		stream = stream || req.Stream
 	}
 
 	// 2. 构建对话上下文（从 Server 获取）
-	messages, err := s.contextBuilder.BuildContext(ctx, campaignID, req.Content)
+	messages, err := s.contextBuilder.BuildContext(ctx, campaignID, batchContent(reqs))
 	if err != nil {
 		return nil, fmt.Errorf("构建对话上下文失败: %w", err)
 	}
 
-	// 3. 调用 LLM
-	llmResp, err := s.llmClient.Chat(ctx, &llm.ChatRequest{
-		Messages:    messages,
-		Temperature: 0.7,
-	})
+	// 3. 获取可用工具，选择战役使用的 LLM
+	tools := s.availableTools(ctx)
+	llmClient, model, err := s.selectLLM(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("选择 LLM 失败: %w", err)
+	}
+
+	// 4. 调用 LLM，执行工具调用直到得到最终回复
+	toolCallCount := 0
+	for iteration := 1; ; iteration++ {
+		chatReq := &llm.ChatRequest{
+			Model:       model,
+			Messages:    messages,
+			Temperature: 0.7,
+		}
+		// 本次回复保存时使用的消息 ID，流式增量事件也带上它
+		messageID := uuid.New().String()
+		// 最后一次调用不再提供工具，要求模型根据已有结果直接回复
+		lastIteration := iteration >= s.toolLoop.MaxIterations || toolCallCount >= s.toolLoop.MaxToolCalls
+		if !lastIteration {
+			chatReq.Tools = tools
+		}
+
+		llmResp, err := s.chat(ctx, llmClient, campaignID, messageID, chatReq, stream)
+		if err != nil {
+			return nil, fmt.Errorf("LLM 调用失败: %w", err)
+		}
+		if len(llmResp.Choices) == 0 {
+			return nil, fmt.Errorf("LLM 返回空响应")
+		}
+
+		choice := llmResp.Choices[0]
+		if len(choice.Message.ToolCalls) == 0 {
+			message, err := s.saveFinalMessage(ctx, campaignID, messageID, choice.Message.Content)
+			if err == nil && s.budget != nil {
+				// 本轮用量超出预算时立即暂停，本轮回复照常返回
+				_ = s.budget.Check(ctx, campaignID)
+			}
+			return message, err
+		}
+		if lastIteration {
+			return nil, fmt.Errorf("工具调用次数超过上限 (%d 轮, %d 次调用)", iteration, toolCallCount)
+		}
+
+		// 5. 保存 assistant 消息（包含 tool_calls）
+		toolCalls := choice.Message.ToolCalls
+		assistantMsg := &server.Message{
+			ID:         messageID,
+			CampaignID: campaignID,
+			Role:       server.MessageRoleAssistant,
+			Content:    choice.Message.Content,
+			ToolCalls:  convertLLMToolCallsToServer(toolCalls),
+			CreatedAt:  time.Now(),
+		}
+		if err := s.serverClient.SaveMessage(ctx, campaignID, assistantMsg); err != nil {
+			return nil, fmt.Errorf("保存工具调用消息失败: %w", err)
+		}
+		messages = append(messages, llm.Message{
+			Role:      "assistant",
+			Content:   choice.Message.Content,
+			ToolCalls: toolCalls,
+		})
+
+		// 6. 执行工具调用（超出预算的调用不执行）
+		budget := s.toolLoop.MaxToolCalls - toolCallCount
+		results := s.executeToolCalls(ctx, campaignID, messageID, toolCalls, budget)
+		toolCallCount += min(len(toolCalls), budget)
+
+		// 7. 保存 tool 响应消息，按调用顺序交回 LLM
+		for i, toolCall := range toolCalls {
+			toolMsg := &server.Message{
+				ID:         uuid.New().String(),
+				CampaignID: campaignID,
+				Role:       server.MessageRoleTool,
+				Content:    results[i],
+				ToolCallID: toolCall.ID,
+				CreatedAt:  time.Now(),
+			}
+			if err := s.serverClient.SaveMessage(ctx, campaignID, toolMsg); err != nil {
+				return nil, fmt.Errorf("保存工具响应失败: %w", err)
+			}
+			messages = append(messages, llm.Message{
+				Role:       "tool",
+				Content:    results[i],
+				ToolCallID: toolCall.ID,
+			})
+		}
+	}
+}
+
+// selectLLM 按战役设置选择 LLM 客户端和模型，返回的模型为空时使用客户端的默认模型
+func (s *ChatService) selectLLM(ctx context.Context, campaignID string) (llm.LLMClient, string, error) {
+	if s.llmPool == nil || s.sessions == nil {
+		return s.llmClient, "", nil
+	}
+
+	session, err := s.sessions.Get(ctx, campaignID)
+	if err != nil || session == nil {
+		// 读不到战役设置时使用默认 LLM，不影响对话
+		log.Printf("读取战役 %s 的 LLM 设置失败，使用默认 LLM: %v", campaignID, err)
+		return s.llmClient, "", nil
+	}
+
+	provider := session.SettingString(models.SettingLLMProvider)
+	model := session.SettingString(models.SettingLLMModel)
+	if provider == "" || provider == s.llmPool.DefaultProvider() {
+		return s.llmClient, model, nil
+	}
+
+	client, err := s.llmPool.Get(provider)
 	if err != nil {
-		return nil, fmt.Errorf("LLM 调用失败: %w", err)
+		return nil, "", err
 	}
+	return client, model, nil
+}
 
-	// 4. 检查响应类型
-	if len(llmResp.Choices) == 0 {
-		return nil, fmt.Errorf("LLM 返回空响应")
+// chat 调用 LLM，流式请求时把回复的增量内容推送到会话
+func (s *ChatService) chat(ctx context.Context, llmClient llm.LLMClient, campaignID, messageID string, req *llm.ChatRequest, stream bool) (*llm.ChatResponse, error) {
+	if !stream || s.broadcaster == nil {
+		return llmClient.Chat(ctx, req)
 	}
 
-	choice := llmResp.Choices[0]
+	return llmClient.ChatStream(ctx, req, func(delta llm.MessageDelta) {
+		if delta.Content == "" {
+			return
+		}
+		s.broadcast(campaignID, ws.EventMessageDelta, map[string]interface{}{
+			"message_id": messageID,
+			"delta":      delta.Content,
+		})
+	})
+}
+
+// broadcast 推送会话事件
+func (s *ChatService) broadcast(campaignID, eventType string, data map[string]interface{}) {
+	if s.broadcaster == nil {
+		return
+	}
+
+	data["session_id"] = campaignID
+	data["timestamp"] = time.Now().Format(time.RFC3339)
+	s.broadcaster.BroadcastToSession(campaignID, *ws.NewEvent(campaignID, eventType, data))
+}
+
+// availableTools 获取提供给 LLM 的工具定义，获取失败时不提供工具
+func (s *ChatService) availableTools(ctx context.Context) []llm.Tool {
+	if s.toolCatalog == nil {
+		return nil
+	}
 
-	// 5. 判断是否有 tool_calls
-	if choice.FinishReason == "tool_calls" && len(choice.Message.ToolCalls) > 0 {
-		// 处理工具调用
-		return s.handleToolCalls(ctx, campaignID, choice.Message.ToolCalls, req.Content)
+	tools, err := s.toolCatalog.Tools(ctx)
+	if err != nil {
+		log.Printf("[ChatService] 获取工具目录失败，本轮不提供工具: %v", err)
+		return nil
 	}
+	return tools
+}
 
-	// 6. 保存助手消息到 Server
+// saveFinalMessage 保存最终助手回复到 Server
+func (s *ChatService) saveFinalMessage(ctx context.Context, campaignID, messageID, content string) (*models.Message, error) {
 	assistantMsg := &server.Message{
-		ID:         uuid.New().String(),
+		ID:         messageID,
 		CampaignID: campaignID,
 		Role:       server.MessageRoleAssistant,
-		Content:    choice.Message.Content,
+		Content:    content,
 		CreatedAt:  time.Now(),
 	}
 	if err := s.serverClient.SaveMessage(ctx, campaignID, assistantMsg); err != nil {
 		return nil, fmt.Errorf("保存助手消息失败: %w", err)
 	}
 
+	s.broadcast(campaignID, ws.EventNewMessage, map[string]interface{}{
+		"message_id": assistantMsg.ID,
+		"role":       string(assistantMsg.Role),
+		"content":    assistantMsg.Content,
+	})
+
 	// 转换为本地 models.Message 以保持接口兼容
 	return &models.Message{
 		ID:        assistantMsg.ID,
@@ -118,116 +374,77 @@ func (s *ChatService) SendMessage(ctx context.Context, campaignID string, req *S
 	}, nil
 }
 
-// handleToolCalls 处理工具调用
-func (s *ChatService) handleToolCalls(ctx context.Context, campaignID string, toolCalls []llm.ToolCall, originalUserMessage string) (*models.Message, error) {
-	// 1. 保存 assistant 消息（包含 tool_calls）到 Server
-	assistantMsg := &server.Message{
-		ID:         uuid.New().String(),
-		CampaignID: campaignID,
-		Role:       server.MessageRoleAssistant,
-		Content:    "",
-		ToolCalls:  convertLLMToolCallsToServer(toolCalls),
-		CreatedAt:  time.Now(),
-	}
-
-	if err := s.serverClient.SaveMessage(ctx, campaignID, assistantMsg); err != nil {
-		return nil, fmt.Errorf("保存工具调用消息失败: %w", err)
-	}
-
-	// 2. 执行所有工具调用
-	toolResults := make([]map[string]interface{}, len(toolCalls))
+// executeToolCalls 按模型给出的顺序依次执行一批工具调用，返回与 toolCalls 一一对应的 JSON 结果
+// 后面的调用可能依赖前面调用的结果（如先移动再攻击），因此不并发执行
+// 只执行前 budget 个调用，其余调用返回预算耗尽的错误结果
+func (s *ChatService) executeToolCalls(ctx context.Context, campaignID, messageID string, toolCalls []llm.ToolCall, budget int) []string {
+	results := make([]string, len(toolCalls))
 	for i, toolCall := range toolCalls {
-		// 解析 arguments
-		var args map[string]interface{}
-		json.Unmarshal([]byte(toolCall.Function.Arguments), &args)
-
-		// 通过 ServerClient 调用工具
-		result, err := s.serverClient.CallTool(ctx, campaignID, toolCall.Function.Name, args)
-		if err != nil {
-			// 尝试使用 MCP 客户端作为备用
-			result, err = s.mcpClient.CallTool(ctx, campaignID, toolCall.Function.Name, args)
-			if err != nil {
-				return nil, fmt.Errorf("工具调用失败: %w", err)
-			}
+		if i >= budget {
+			results[i] = toolErrorResult(fmt.Errorf("本轮工具调用次数已达上限 (%d)", s.toolLoop.MaxToolCalls))
+			continue
 		}
 
-		toolResults[i] = result
-	}
+		s.broadcast(campaignID, ws.EventToolCallStarted, map[string]interface{}{
+			"message_id":   messageID,
+			"tool_call_id": toolCall.ID,
+			"name":         toolCall.Function.Name,
+			"arguments":    toolCall.Function.Arguments,
+		})
 
-	// 3. 保存 tool 响应消息到 Server
-	for i, toolCall := range toolCalls {
-		toolMsg := &server.Message{
-			ID:         uuid.New().String(),
-			CampaignID: campaignID,
-			Role:       "tool",
-			Content:    fmt.Sprintf("工具 %s 执行结果: %+v", toolCall.Function.Name, toolResults[i]),
-			CreatedAt:  time.Now(),
+		finished := map[string]interface{}{
+			"message_id":   messageID,
+			"tool_call_id": toolCall.ID,
+			"name":         toolCall.Function.Name,
 		}
-
-		if err := s.serverClient.SaveMessage(ctx, campaignID, toolMsg); err != nil {
-			return nil, fmt.Errorf("保存工具响应失败: %w", err)
+		result, err := s.executeToolCall(ctx, campaignID, toolCall)
+		if err == nil {
+			var data []byte
+			if data, err = json.Marshal(result); err == nil {
+				results[i] = string(data)
+				finished["result"] = result
+			} else {
+				err = fmt.Errorf("序列化工具结果失败: %w", err)
+			}
 		}
+		if err != nil {
+			results[i] = toolErrorResult(err)
+			finished["error"] = err.Error()
+		}
+		s.broadcast(campaignID, ws.EventToolCallFinished, finished)
 	}
 
-	// 4. 重新构建上下文（包含工具调用和结果）
-	messages, err := s.contextBuilder.BuildContext(ctx, campaignID, originalUserMessage)
-	if err != nil {
-		return nil, fmt.Errorf("构建上下文失败: %w", err)
-	}
-
-	// 添加 tool_calls 消息
-	messages = append(messages, llm.Message{
-		Role:      "assistant",
-		Content:   "",
-		ToolCalls: toolCalls,
-	})
-
-	// 添加 tool 响应消息
-	for i, result := range toolResults {
-		messages = append(messages, llm.Message{
-			Role:       "tool",
-			Content:    fmt.Sprintf("%+v", result),
-			ToolCallID: toolCalls[i].ID,
-		})
-	}
-
-	// 5. 继续调用 LLM（传递工具结果）
-	followupReq := &llm.ChatRequest{
-		Model:       "gpt-4",
-		Messages:    messages,
-		Temperature: 0.7,
-	}
-
-	followupResp, err := s.llmClient.Chat(ctx, followupReq)
-	if err != nil {
-		return nil, fmt.Errorf("LLM 后续调用失败: %w", err)
-	}
+	return results
+}
 
-	// 6. 保存最终助手响应到 Server
-	if len(followupResp.Choices) == 0 {
-		return nil, fmt.Errorf("LLM 返回空响应")
+// executeToolCall 执行单个工具调用
+func (s *ChatService) executeToolCall(ctx context.Context, campaignID string, toolCall llm.ToolCall) (map[string]interface{}, error) {
+	name := toolCall.Function.Name
+	if s.toolCatalog != nil && !s.toolCatalog.Has(name) {
+		// Server 的工具可能已经变化，下次使用前重新获取目录
+		s.toolCatalog.Invalidate()
+		return nil, fmt.Errorf("未知工具: %s", name)
 	}
 
-	finalMsg := &server.Message{
-		ID:         uuid.New().String(),
-		CampaignID: campaignID,
-		Role:       server.MessageRoleAssistant,
-		Content:    followupResp.Choices[0].Message.Content,
-		CreatedAt:  time.Now(),
+	args := make(map[string]interface{})
+	if toolCall.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+			return nil, fmt.Errorf("解析工具参数失败: %w", err)
+		}
 	}
 
-	if err := s.serverClient.SaveMessage(ctx, campaignID, finalMsg); err != nil {
-		return nil, fmt.Errorf("保存最终响应失败: %w", err)
+	// 通过 ServerClient 调用工具，失败时使用 MCP 客户端作为备用
+	result, err := s.serverClient.CallTool(ctx, campaignID, name, args)
+	if err != nil && s.mcpClient != nil {
+		result, err = s.mcpClient.CallTool(ctx, campaignID, name, args)
 	}
+	return result, err
+}
 
-	// 转换为本地 models.Message 以保持接口兼容
-	return &models.Message{
-		ID:        finalMsg.ID,
-		SessionID: campaignID,
-		Role:      string(finalMsg.Role),
-		Content:   finalMsg.Content,
-		CreatedAt: finalMsg.CreatedAt,
-	}, nil
+// toolErrorResult 将工具调用错误转换为 JSON 结果
+func toolErrorResult(err error) string {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
 }
 
 // convertLLMToolCallsToServer 转换 LLM tool_calls 到 Server 格式
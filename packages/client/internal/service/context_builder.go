@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -86,12 +87,11 @@ func (b *ContextBuilder) buildFromContext(ctx context.Context, campaignID, userM
 	}
 
 	// 2. 添加历史消息
-	for _, msg := range serverCtx.Messages {
-		messages = append(messages, llm.Message{
-			Role:    string(msg.Role),
-			Content: msg.Content,
-		})
+	history := make([]llm.Message, 0, len(serverCtx.Messages))
+	for i := range serverCtx.Messages {
+		history = append(history, historyMessage(&serverCtx.Messages[i]))
 	}
+	messages = append(messages, pairToolMessages(history)...)
 
 	// 3. 添加当前用户消息
 	messages = append(messages, llm.Message{
@@ -129,13 +129,11 @@ func (b *ContextBuilder) buildFromRawContext(ctx context.Context, campaignID, us
 		startIdx = len(rawCtx.Messages) - messageLimit
 	}
 
+	history := make([]llm.Message, 0, len(rawCtx.Messages)-startIdx)
 	for i := startIdx; i < len(rawCtx.Messages); i++ {
-		msg := rawCtx.Messages[i]
-		messages = append(messages, llm.Message{
-			Role:    string(msg.Role),
-			Content: msg.Content,
-		})
+		history = append(history, historyMessage(rawCtx.Messages[i]))
 	}
+	messages = append(messages, pairToolMessages(history)...)
 
 	// 3. 添加当前用户消息
 	messages = append(messages, llm.Message{
@@ -146,6 +144,64 @@ func (b *ContextBuilder) buildFromRawContext(ctx context.Context, campaignID, us
 	return messages, nil
 }
 
+// historyMessage 将 Server 历史消息转换为 LLM 消息，保留工具调用及其结果的关联
+func historyMessage(msg *server.Message) llm.Message {
+	message := llm.Message{
+		Role:       string(msg.Role),
+		Content:    msg.Content,
+		ToolCallID: msg.ToolCallID,
+	}
+	for _, tc := range msg.ToolCalls {
+		arguments, _ := json.Marshal(tc.Arguments)
+		message.ToolCalls = append(message.ToolCalls, llm.ToolCall{
+			ID:   tc.ID,
+			Type: "function",
+			Function: llm.FunctionCall{
+				Name:      tc.Name,
+				Arguments: string(arguments),
+			},
+		})
+	}
+	return message
+}
+
+// pairToolMessages 去掉不成对的工具调用和工具结果
+// 滑动窗口可能截断在工具调用和结果之间，中断的对话轮次也可能缺少结果，
+// 而 LLM 要求每个 tool 消息都对应之前的工具调用、每个工具调用都有结果
+func pairToolMessages(history []llm.Message) []llm.Message {
+	answered := make(map[string]bool)
+	for _, msg := range history {
+		if msg.Role == "tool" {
+			answered[msg.ToolCallID] = true
+		}
+	}
+
+	called := make(map[string]bool)
+	paired := make([]llm.Message, 0, len(history))
+	for _, msg := range history {
+		switch {
+		case msg.Role == "tool":
+			if !called[msg.ToolCallID] {
+				continue
+			}
+		case len(msg.ToolCalls) > 0:
+			var toolCalls []llm.ToolCall
+			for _, tc := range msg.ToolCalls {
+				if answered[tc.ID] {
+					toolCalls = append(toolCalls, tc)
+					called[tc.ID] = true
+				}
+			}
+			msg.ToolCalls = toolCalls
+			if len(toolCalls) == 0 && msg.Content == "" {
+				continue
+			}
+		}
+		paired = append(paired, msg)
+	}
+	return paired
+}
+
 // buildSystemPrompt 构建 System Prompt（简化模式）
 func (b *ContextBuilder) buildSystemPrompt(ctx *server.Context) string {
 	var sb strings.Builder
@@ -144,6 +144,7 @@ type UpdateSessionRequest struct {
 	Name       *string
 	MaxPlayers *int
 	Settings   map[string]interface{}
+	Status     *string // 只能设置为 active 或 paused（恢复或暂停战役）
 }
 
 // UpdateSession 更新会话
@@ -167,6 +168,12 @@ func (s *SessionService) UpdateSession(ctx context.Context, sessionID string, re
 	if req.Settings != nil {
 		session.Settings = req.Settings
 	}
+	if req.Status != nil {
+		if *req.Status != models.SessionStatusActive && *req.Status != models.SessionStatusPaused {
+			return nil, errors.Wrapf(errors.ErrInvalidArgument, "无效的会话状态 %q", *req.Status)
+		}
+		session.Status = *req.Status
+	}
 	session.UpdatedAt = time.Now()
 
 	// 保存更新到 Redis（主存储）
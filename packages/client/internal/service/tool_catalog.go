@@ -0,0 +1,134 @@
+// Package service 提供业务逻辑服务
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dnd-mcp/client/internal/llm"
+	"github.com/dnd-mcp/client/internal/mcp"
+	"github.com/dnd-mcp/client/internal/server"
+)
+
+// DefaultToolCatalogTTL 工具目录默认缓存时间
+const DefaultToolCatalogTTL = 5 * time.Minute
+
+// ToolCatalog 工具目录
+// 缓存 Server 提供的工具定义（GET /mcp/tools），转换为 LLM function calling 格式
+// 目录过期或被标记失效（例如 LLM 调用了目录中不存在的工具）后，下次使用时重新获取
+type ToolCatalog struct {
+	serverClient server.ServerClient
+	ttl          time.Duration
+
+	mu        sync.RWMutex
+	tools     []llm.Tool
+	names     map[string]bool
+	fetchedAt time.Time
+}
+
+// NewToolCatalog 创建工具目录，ttl <= 0 时使用默认缓存时间
+func NewToolCatalog(serverClient server.ServerClient, ttl time.Duration) *ToolCatalog {
+	if ttl <= 0 {
+		ttl = DefaultToolCatalogTTL
+	}
+	return &ToolCatalog{
+		serverClient: serverClient,
+		ttl:          ttl,
+		names:        make(map[string]bool),
+	}
+}
+
+// Refresh 从 Server 重新获取工具目录
+func (c *ToolCatalog) Refresh(ctx context.Context) error {
+	tools, err := c.serverClient.ListTools(ctx)
+	if err != nil {
+		return fmt.Errorf("获取工具目录失败: %w", err)
+	}
+
+	converted := make([]llm.Tool, len(tools))
+	names := make(map[string]bool, len(tools))
+	for i, tool := range tools {
+		converted[i] = ConvertTool(tool)
+		names[tool.Name] = true
+	}
+
+	c.mu.Lock()
+	c.tools = converted
+	c.names = names
+	c.fetchedAt = time.Now()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Tools 返回 LLM 工具定义
+// 目录过期时先刷新；刷新失败但已有缓存时继续使用旧目录
+func (c *ToolCatalog) Tools(ctx context.Context) ([]llm.Tool, error) {
+	c.mu.RLock()
+	tools, fetchedAt := c.tools, c.fetchedAt
+	c.mu.RUnlock()
+
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < c.ttl {
+		return tools, nil
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		if tools != nil {
+			log.Printf("[ToolCatalog] 刷新工具目录失败，继续使用缓存: %v", err)
+			return tools, nil
+		}
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tools, nil
+}
+
+// Has 检查工具是否在目录中
+func (c *ToolCatalog) Has(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.names[name]
+}
+
+// Invalidate 标记目录失效，下次使用时重新获取
+func (c *ToolCatalog) Invalidate() {
+	c.mu.Lock()
+	c.fetchedAt = time.Time{}
+	c.mu.Unlock()
+}
+
+// ConvertTool 将 MCP 工具定义转换为 LLM function 定义
+func ConvertTool(tool mcp.Tool) llm.Tool {
+	schemaType := tool.InputSchema.Type
+	if schemaType == "" {
+		schemaType = "object"
+	}
+
+	// OpenAI 要求 object 类型的参数总是带 properties
+	properties := tool.InputSchema.Properties
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+
+	parameters := map[string]interface{}{
+		"type":       schemaType,
+		"properties": properties,
+	}
+	if len(tool.InputSchema.Required) > 0 {
+		parameters["required"] = tool.InputSchema.Required
+	}
+
+	return llm.Tool{
+		Type: "function",
+		Function: llm.FunctionDef{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  parameters,
+		},
+	}
+}
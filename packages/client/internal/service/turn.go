@@ -0,0 +1,336 @@
+// Package service 提供多人战役的回合协调
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dnd-mcp/client/internal/models"
+	"github.com/dnd-mcp/client/internal/server"
+	"github.com/dnd-mcp/client/internal/ws"
+)
+
+var (
+	// ErrTurnQueueFull 战役的回合队列已满
+	ErrTurnQueueFull = errors.New("回合队列已满，请稍后再试")
+	// ErrNotYourTurn 先攻模式下战斗中不是该玩家的回合
+	ErrNotYourTurn = errors.New("还没有轮到你行动")
+)
+
+// TurnConfig 回合协调配置
+type TurnConfig struct {
+	// BatchWindow 第一条消息入队后等待其他玩家消息的时间，0 表示不等待
+	BatchWindow time.Duration
+	// MaxBatch 一轮最多合并的消息数，1 表示不合并（上一轮进行期间排队的消息也会合并）
+	MaxBatch int
+	// MaxQueue 每个战役最多排队的消息数
+	MaxQueue int
+	// InitiativeMode 默认是否启用先攻模式，战役设置 turn_mode 可以覆盖
+	InitiativeMode bool
+}
+
+// DefaultTurnConfig 默认回合协调配置
+var DefaultTurnConfig = TurnConfig{
+	MaxBatch: 4,
+	MaxQueue: 16,
+}
+
+// TurnRunner 执行一轮对话（由 ChatService 实现）
+type TurnRunner interface {
+	SendBatch(ctx context.Context, campaignID string, reqs []*SendMessageRequest) (*models.Message, error)
+}
+
+// CombatReader 读取战役的战斗状态和角色（由 server.ServerClient 实现）
+type CombatReader interface {
+	GetRawContext(ctx context.Context, campaignID string) (*server.RawContext, error)
+}
+
+// TurnCoordinator 按战役协调玩家消息
+// 同一战役的消息进入队列，每次只运行一轮 LLM 和工具调用；排队的消息合并为一轮，
+// DM 的回复返回给这一轮的所有玩家。先攻模式下战斗中只接受当前行动者的玩家发言
+type TurnCoordinator struct {
+	runner      TurnRunner
+	config      TurnConfig
+	combat      CombatReader       // 可选，未设置时不检查先攻
+	sessions    SessionGetter      // 可选，未设置时使用默认回合模式
+	broadcaster SessionBroadcaster // 可选，未设置时不推送队列事件
+
+	mu     sync.Mutex
+	queues map[string]*turnQueue
+}
+
+var _ ChatServiceInterface = (*TurnCoordinator)(nil)
+
+// turnQueue 战役的回合队列
+type turnQueue struct {
+	pending []*queuedMessage
+	running bool
+}
+
+// queuedMessage 排队中的玩家消息
+type queuedMessage struct {
+	ctx      context.Context
+	req      *SendMessageRequest
+	queuedAt time.Time
+	done     chan turnResult
+}
+
+// turnResult 一轮对话的结果
+type turnResult struct {
+	message *models.Message
+	err     error
+}
+
+// NewTurnCoordinator 创建回合协调器，非正数的 MaxBatch / MaxQueue 使用默认值
+func NewTurnCoordinator(runner TurnRunner, config TurnConfig) *TurnCoordinator {
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = DefaultTurnConfig.MaxBatch
+	}
+	if config.MaxQueue <= 0 {
+		config.MaxQueue = DefaultTurnConfig.MaxQueue
+	}
+	if config.BatchWindow < 0 {
+		config.BatchWindow = 0
+	}
+	return &TurnCoordinator{
+		runner: runner,
+		config: config,
+		queues: make(map[string]*turnQueue),
+	}
+}
+
+// SetCombatReader 设置战斗状态读取，启用先攻模式检查
+func (c *TurnCoordinator) SetCombatReader(combat CombatReader) {
+	c.combat = combat
+}
+
+// SetSessionGetter 设置会话读取，战役设置中的 turn_mode 将覆盖默认回合模式
+func (c *TurnCoordinator) SetSessionGetter(sessions SessionGetter) {
+	c.sessions = sessions
+}
+
+// SetBroadcaster 设置会话事件广播，推送排队位置和回合开始事件
+func (c *TurnCoordinator) SetBroadcaster(broadcaster SessionBroadcaster) {
+	c.broadcaster = broadcaster
+}
+
+// SendMessage 将玩家消息加入战役的回合队列，等待这一轮的 DM 回复
+// 等待期间 ctx 取消时，尚未开始的消息会移出队列；已经开始的一轮会继续完成
+func (c *TurnCoordinator) SendMessage(ctx context.Context, campaignID string, req *SendMessageRequest) (*models.Message, error) {
+	if err := c.checkInitiative(ctx, campaignID, req); err != nil {
+		return nil, err
+	}
+
+	item := &queuedMessage{
+		ctx:      ctx,
+		req:      req,
+		queuedAt: time.Now(),
+		done:     make(chan turnResult, 1),
+	}
+	position, err := c.enqueue(campaignID, item)
+	if err != nil {
+		return nil, err
+	}
+	c.broadcast(campaignID, ws.EventTurnQueued, map[string]interface{}{
+		"player_id": req.PlayerID,
+		"position":  position,
+	})
+
+	select {
+	case result := <-item.done:
+		return result.message, result.err
+	case <-ctx.Done():
+		c.remove(campaignID, item)
+		return nil, ctx.Err()
+	}
+}
+
+// QueueLength 返回战役排队中（尚未开始）的消息数
+func (c *TurnCoordinator) QueueLength(campaignID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if queue, ok := c.queues[campaignID]; ok {
+		return len(queue.pending)
+	}
+	return 0
+}
+
+// enqueue 将消息加入队列并返回排队位置（从 1 开始），战役没有进行中的回合时启动处理
+func (c *TurnCoordinator) enqueue(campaignID string, item *queuedMessage) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, ok := c.queues[campaignID]
+	if !ok {
+		queue = &turnQueue{}
+		c.queues[campaignID] = queue
+	}
+	if len(queue.pending) >= c.config.MaxQueue {
+		return 0, ErrTurnQueueFull
+	}
+	queue.pending = append(queue.pending, item)
+	if !queue.running {
+		queue.running = true
+		go c.run(campaignID, queue)
+	}
+	return len(queue.pending), nil
+}
+
+// remove 将尚未开始的消息移出队列
+func (c *TurnCoordinator) remove(campaignID string, item *queuedMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, ok := c.queues[campaignID]
+	if !ok {
+		return
+	}
+	for i, pending := range queue.pending {
+		if pending == item {
+			queue.pending = append(queue.pending[:i], queue.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// run 依次处理战役队列中的消息，队列为空时退出
+func (c *TurnCoordinator) run(campaignID string, queue *turnQueue) {
+	for {
+		batch, waiting := c.nextBatch(campaignID, queue)
+		if batch == nil {
+			return
+		}
+		for i, playerID := range waiting {
+			c.broadcast(campaignID, ws.EventTurnQueued, map[string]interface{}{
+				"player_id": playerID,
+				"position":  i + 1,
+			})
+		}
+		if batch = c.admit(campaignID, batch); len(batch) == 0 {
+			continue
+		}
+
+		reqs := make([]*SendMessageRequest, len(batch))
+		playerIDs := make([]string, len(batch))
+		for i, item := range batch {
+			reqs[i] = item.req
+			playerIDs[i] = item.req.PlayerID
+		}
+		c.broadcast(campaignID, ws.EventTurnStarted, map[string]interface{}{
+			"player_ids":   playerIDs,
+			"queue_length": c.QueueLength(campaignID),
+		})
+
+		// 这一轮不随发起请求的取消而中断，避免工具调用执行到一半
+		ctx := context.WithoutCancel(batch[0].ctx)
+		message, err := c.runner.SendBatch(ctx, campaignID, reqs)
+		for _, item := range batch {
+			item.done <- turnResult{message: message, err: err}
+		}
+	}
+}
+
+// nextBatch 等待合并窗口后取出下一轮的消息，并返回仍在排队的玩家（按排队位置）
+// 队列为空时移除队列并返回 nil
+func (c *TurnCoordinator) nextBatch(campaignID string, queue *turnQueue) ([]*queuedMessage, []string) {
+	c.mu.Lock()
+	var wait time.Duration
+	if len(queue.pending) > 0 && c.config.BatchWindow > 0 {
+		// 从最早的消息入队开始计算，上一轮进行期间排队的消息不再额外等待
+		wait = time.Until(queue.pending[0].queuedAt.Add(c.config.BatchWindow))
+	}
+	c.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(queue.pending) == 0 {
+		queue.running = false
+		delete(c.queues, campaignID)
+		return nil, nil
+	}
+	n := min(len(queue.pending), c.config.MaxBatch)
+	batch := queue.pending[:n:n]
+	queue.pending = queue.pending[n:]
+	waiting := make([]string, len(queue.pending))
+	for i, item := range queue.pending {
+		waiting[i] = item.req.PlayerID
+	}
+	return batch, waiting
+}
+
+// admit 重新检查这一轮消息的先攻，排队期间回合可能已经轮换
+// 不再轮到的玩家收到 ErrNotYourTurn，返回仍可以进行这一轮的消息
+func (c *TurnCoordinator) admit(campaignID string, batch []*queuedMessage) []*queuedMessage {
+	admitted := make([]*queuedMessage, 0, len(batch))
+	for _, item := range batch {
+		if err := c.checkInitiative(context.WithoutCancel(item.ctx), campaignID, item.req); err != nil {
+			item.done <- turnResult{err: err}
+			continue
+		}
+		admitted = append(admitted, item)
+	}
+	return admitted
+}
+
+// checkInitiative 先攻模式下检查战斗中是否轮到该玩家
+// 当前行动者不是玩家角色（NPC 或怪物）时所有玩家都可以发言，以便推进 DM 的回合
+func (c *TurnCoordinator) checkInitiative(ctx context.Context, campaignID string, req *SendMessageRequest) error {
+	if c.combat == nil || !c.initiativeMode(ctx, campaignID) {
+		return nil
+	}
+
+	raw, err := c.combat.GetRawContext(ctx, campaignID)
+	if err != nil {
+		// 读不到战斗状态时无法判断回合，不阻止发言
+		log.Printf("读取战役 %s 战斗状态失败，跳过先攻检查: %v", campaignID, err)
+		return nil
+	}
+	if raw.Combat == nil || !raw.Combat.IsActive() {
+		return nil
+	}
+
+	actorID := raw.Combat.CurrentCharacterID()
+	for _, character := range raw.Characters {
+		if character.ID != actorID {
+			continue
+		}
+		if character.PlayerID == "" || character.PlayerID == req.PlayerID {
+			return nil
+		}
+		return fmt.Errorf("%w，当前行动者: %s", ErrNotYourTurn, character.Name)
+	}
+	return nil
+}
+
+// initiativeMode 返回战役是否启用先攻模式
+func (c *TurnCoordinator) initiativeMode(ctx context.Context, campaignID string) bool {
+	if c.sessions != nil {
+		if session, err := c.sessions.Get(ctx, campaignID); err == nil {
+			switch session.SettingString(models.SettingTurnMode) {
+			case models.TurnModeInitiative:
+				return true
+			case models.TurnModeFree:
+				return false
+			}
+		}
+	}
+	return c.config.InitiativeMode
+}
+
+// broadcast 推送回合事件
+func (c *TurnCoordinator) broadcast(campaignID, eventType string, data map[string]interface{}) {
+	if c.broadcaster == nil {
+		return
+	}
+	data["session_id"] = campaignID
+	data["timestamp"] = time.Now().Format(time.RFC3339)
+	c.broadcaster.BroadcastToSession(campaignID, *ws.NewEvent(campaignID, eventType, data))
+}
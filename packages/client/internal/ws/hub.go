@@ -24,10 +24,20 @@ type Hub struct {
 	// 注销通道
 	Unregister chan *Connection
 
+	// 会话观察者
+	observer SessionObserver
+
 	// 互斥锁
 	mu sync.RWMutex
 }
 
+// SessionObserver 会话生命周期观察者
+// 会话的第一个连接建立时调用 SessionOpened，最后一个连接断开时调用 SessionClosed
+type SessionObserver interface {
+	SessionOpened(sessionID string)
+	SessionClosed(sessionID string)
+}
+
 // NewHub 创建新 Hub
 func NewHub() *Hub {
 	hub := &Hub{
@@ -60,23 +70,54 @@ func (h *Hub) Run() {
 	}
 }
 
+// SetSessionObserver 设置会话观察者
+func (h *Hub) SetSessionObserver(observer SessionObserver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.observer = observer
+}
+
 // register 注册连接
 func (h *Hub) register(conn *Connection) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
 
 	// 添加连接
 	h.Connections[conn.ID] = conn
 
 	// 添加会话索引
+	opened := len(h.SessionConnections[conn.SessionID]) == 0
 	h.SessionConnections[conn.SessionID] = append(
 		h.SessionConnections[conn.SessionID],
 		conn.ID,
 	)
+
+	observer := h.observer
+	h.mu.Unlock()
+
+	// 在锁外通知观察者
+	if opened && observer != nil {
+		observer.SessionOpened(conn.SessionID)
+	}
 }
 
 // unregister 注销连接
 func (h *Hub) unregister(conn *Connection) {
+	closed := h.removeConnection(conn)
+	conn.Close()
+
+	h.mu.RLock()
+	observer := h.observer
+	h.mu.RUnlock()
+
+	// 在锁外通知观察者
+	if closed && observer != nil {
+		observer.SessionClosed(conn.SessionID)
+	}
+}
+
+// removeConnection 移除连接，返回会话是否已没有连接
+func (h *Hub) removeConnection(conn *Connection) bool {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -98,12 +139,12 @@ func (h *Hub) unregister(conn *Connection) {
 
 		if len(newConnections) == 0 {
 			delete(h.SessionConnections, conn.SessionID)
-		} else {
-			h.SessionConnections[conn.SessionID] = newConnections
+			return true
 		}
+		h.SessionConnections[conn.SessionID] = newConnections
 	}
 
-	conn.Close()
+	return false
 }
 
 // broadcast 广播事件
@@ -137,8 +178,8 @@ func (h *Hub) broadcast(event *Event) {
 		select {
 		case conn.Send <- serverMsg:
 		default:
-			// 发送缓冲区已满，关闭连接
-			h.Unregister <- conn
+			// 发送缓冲区已满，关闭连接（异步注销，避免阻塞 Run 循环）
+			go func(conn *Connection) { h.Unregister <- conn }(conn)
 		}
 	}
 }
@@ -28,10 +28,21 @@ type ClientMessageData struct {
 
 // ServerMessage 服务器发送的消息
 type ServerMessage struct {
-	Type string                 `json:"type"` // new_message, state_changed, combat_updated, dice_rolled, pong, error
+	Type string                 `json:"type"` // new_message, message_delta, tool_call_started, tool_call_finished, campaign_paused, turn_queued, turn_started, state_changed, combat_updated, dice_rolled, pong, error
 	Data map[string]interface{} `json:"data"`
 }
 
+// 对话事件类型
+const (
+	EventNewMessage       = "new_message"        // 新消息（流式回复的最终消息）
+	EventMessageDelta     = "message_delta"      // 流式回复的增量内容
+	EventToolCallStarted  = "tool_call_started"  // 开始执行工具调用
+	EventToolCallFinished = "tool_call_finished" // 工具调用执行完成
+	EventCampaignPaused   = "campaign_paused"    // LLM 用量超过预算，战役已暂停
+	EventTurnQueued       = "turn_queued"        // 玩家消息进入回合队列
+	EventTurnStarted      = "turn_started"       // 开始处理一轮（可能合并多名玩家的）消息
+)
+
 // Event 事件结构
 type Event struct {
 	ID        string                 `json:"id"`
@@ -88,6 +99,36 @@ type NewMessageEventData struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// MessageDeltaEventData 消息增量事件数据
+// MessageID 与之后 new_message 事件的 message_id 相同
+type MessageDeltaEventData struct {
+	MessageID string `json:"message_id"`
+	SessionID string `json:"session_id"`
+	Delta     string `json:"delta"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ToolCallStartedEventData 工具调用开始事件数据
+type ToolCallStartedEventData struct {
+	MessageID  string `json:"message_id"` // 发起调用的 assistant 消息
+	SessionID  string `json:"session_id"`
+	ToolCallID string `json:"tool_call_id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments"` // JSON 字符串
+	Timestamp  string `json:"timestamp"`
+}
+
+// ToolCallFinishedEventData 工具调用完成事件数据
+type ToolCallFinishedEventData struct {
+	MessageID  string                 `json:"message_id"`
+	SessionID  string                 `json:"session_id"`
+	ToolCallID string                 `json:"tool_call_id"`
+	Name       string                 `json:"name"`
+	Result     map[string]interface{} `json:"result,omitempty"` // 成功时的工具结果
+	Error      string                 `json:"error,omitempty"`  // 失败时的错误信息
+	Timestamp  string                 `json:"timestamp"`
+}
+
 // StateChangedEventData 状态变更事件数据
 type StateChangedEventData struct {
 	SessionID string                 `json:"session_id"`
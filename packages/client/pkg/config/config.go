@@ -20,6 +20,8 @@ type Config struct {
 	LLM      LLMConfig      `mapstructure:"llm"`
 	MCP      MCPConfig      `mapstructure:"mcp"`
 	Server   ServerConfig   `mapstructure:"server"`
+	Auth     AuthConfig     `mapstructure:"auth"`
+	Turn     TurnConfig     `mapstructure:"turn"`
 }
 
 // RedisConfig Redis 配置
@@ -64,23 +66,163 @@ type PostgresConfig struct {
 type LLMConfig struct {
 	Provider    string  `mapstructure:"provider" env:"LLM_PROVIDER" default:"mock"`
 	APIKey      string  `mapstructure:"api_key" env:"LLM_API_KEY" default:""`
-	BaseURL     string  `mapstructure:"base_url" env:"LLM_BASE_URL" default:"https://api.openai.com/v1"`
+	BaseURL     string  `mapstructure:"base_url" env:"LLM_BASE_URL" default:""` // 为空时使用 provider 的默认地址
 	Model       string  `mapstructure:"model" env:"LLM_MODEL" default:"gpt-4"`
+	APIVersion  string  `mapstructure:"api_version" env:"LLM_API_VERSION" default:""` // Azure OpenAI 的 api-version
 	MaxTokens   int     `mapstructure:"max_tokens" env:"LLM_MAX_TOKENS" default:"4096"`
 	Temperature float64 `mapstructure:"temperature" env:"LLM_TEMPERATURE" default:"0.7"`
 	Timeout     int     `mapstructure:"timeout" env:"LLM_TIMEOUT" default:"30"` // seconds
+
+	// 重试、熔断和备用模型
+	MaxRetries       int      `mapstructure:"max_retries" env:"LLM_MAX_RETRIES" default:"3"`                // 临时错误（429、5xx、网络错误）的重试次数
+	RetryBaseDelay   int      `mapstructure:"retry_base_delay" env:"LLM_RETRY_BASE_DELAY_MS" default:"500"` // 第一次重试前的等待时间（毫秒），之后每次翻倍
+	RetryMaxDelay    int      `mapstructure:"retry_max_delay" env:"LLM_RETRY_MAX_DELAY_MS" default:"10000"` // 单次等待上限（毫秒），包括 Retry-After
+	BreakerThreshold int      `mapstructure:"breaker_threshold" env:"LLM_BREAKER_THRESHOLD" default:"5"`    // 连续失败多少次后熔断，0 表示不熔断
+	BreakerCooldown  int      `mapstructure:"breaker_cooldown" env:"LLM_BREAKER_COOLDOWN" default:"30"`     // seconds
+	FallbackModels   []string `mapstructure:"fallback_models" env:"LLM_FALLBACK_MODELS" default:""`         // 逗号分隔，主模型失败时依次使用
+
+	// 战役限流、用量和预算
+	CampaignRateLimit   int                 `mapstructure:"campaign_rate_limit" env:"LLM_CAMPAIGN_RATE_LIMIT" default:"30"`    // 每个战役每分钟的 LLM 调用数，0 表示不限流
+	CampaignRateBurst   int                 `mapstructure:"campaign_rate_burst" env:"LLM_CAMPAIGN_RATE_BURST" default:"10"`    // 允许的突发调用数
+	CampaignTokenBudget int64               `mapstructure:"campaign_token_budget" env:"LLM_CAMPAIGN_TOKEN_BUDGET" default:"0"` // 每个战役的 token 上限，0 表示不限制
+	CampaignCostBudget  float64             `mapstructure:"campaign_cost_budget" env:"LLM_CAMPAIGN_COST_BUDGET" default:"0"`   // 每个战役的费用上限（美元），0 表示不限制
+	Prices              map[string]LLMPrice `mapstructure:"prices" env:"LLM_PRICES" default:""`                                // 格式 "model:prompt:completion,..."，单位为美元/1K token
+
+	// 可供战役选择的其他 provider（LLM_PROVIDERS 逗号分隔，每个 provider 读取 LLM_<NAME>_* 环境变量）
+	Providers map[string]LLMProviderConfig `mapstructure:"providers"`
+
+	// 工具调用循环
+	MaxToolIterations int `mapstructure:"max_tool_iterations" env:"LLM_MAX_TOOL_ITERATIONS" default:"8"` // 单轮对话最多调用 LLM 的次数
+	MaxToolCalls      int `mapstructure:"max_tool_calls" env:"LLM_MAX_TOOL_CALLS" default:"16"`          // 单轮对话最多执行的工具调用数
+	ToolCatalogTTL    int `mapstructure:"tool_catalog_ttl" env:"LLM_TOOL_CATALOG_TTL" default:"300"`     // seconds
+}
+
+// LLMProviderConfig 单个 LLM provider 的连接配置
+type LLMProviderConfig struct {
+	APIKey         string   `mapstructure:"api_key"`
+	BaseURL        string   `mapstructure:"base_url"`
+	Model          string   `mapstructure:"model"`
+	APIVersion     string   `mapstructure:"api_version"`
+	FallbackModels []string `mapstructure:"fallback_models"`
+}
+
+// LLMPrice 模型价格（美元/1K token）
+type LLMPrice struct {
+	Prompt     float64 `mapstructure:"prompt"`
+	Completion float64 `mapstructure:"completion"`
+}
+
+// ProviderConfig 返回指定 provider 的完整配置
+// 默认 provider 使用基础配置，其他 provider 使用 Providers 中的连接配置，其余参数与基础配置相同
+func (c *LLMConfig) ProviderConfig(name string) (LLMConfig, bool) {
+	cfg := *c
+	if name == c.Provider {
+		return cfg, true
+	}
+
+	provider, ok := c.Providers[name]
+	if !ok {
+		return LLMConfig{}, false
+	}
+	cfg.Provider = name
+	cfg.APIKey = provider.APIKey
+	cfg.BaseURL = provider.BaseURL
+	cfg.Model = provider.Model
+	cfg.APIVersion = provider.APIVersion
+	cfg.FallbackModels = provider.FallbackModels
+	return cfg, true
+}
+
+// llmProviderRequiresAPIKey 需要 API key 的内置 provider
+var llmProviderRequiresAPIKey = map[string]bool{
+	"openai":    true,
+	"azure":     true,
+	"anthropic": true,
+}
+
+// validateLLMProvider 验证 provider 的连接配置
+func validateLLMProvider(name, apiKey, baseURL string) error {
+	if name == "" {
+		return fmt.Errorf("LLM provider 不能为空")
+	}
+	if llmProviderRequiresAPIKey[name] && apiKey == "" {
+		return fmt.Errorf("LLM provider %s 的 API key 不能为空", name)
+	}
+	if name == "azure" && baseURL == "" {
+		return fmt.Errorf("LLM provider azure 的 base URL 不能为空")
+	}
+	return nil
+}
+
+// loadLLMProviders 读取 LLM_PROVIDERS 中列出的 provider 配置
+func loadLLMProviders() map[string]LLMProviderConfig {
+	providers := make(map[string]LLMProviderConfig)
+	for _, name := range strings.Split(getEnv("LLM_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "LLM_" + strings.ToUpper(name) + "_"
+		providers[name] = LLMProviderConfig{
+			APIKey:         getEnv(prefix+"API_KEY", ""),
+			BaseURL:        getEnv(prefix+"BASE_URL", ""),
+			Model:          getEnv(prefix+"MODEL", ""),
+			APIVersion:     getEnv(prefix+"API_VERSION", ""),
+			FallbackModels: getEnvList(prefix + "FALLBACK_MODELS"),
+		}
+	}
+	return providers
+}
+
+// loadLLMPrices 读取 LLM_PRICES，格式为 "model:prompt:completion,..."
+func loadLLMPrices() (map[string]LLMPrice, error) {
+	prices := make(map[string]LLMPrice)
+	for _, entry := range getEnvList("LLM_PRICES") {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("无效的 LLM 价格配置: %s", entry)
+		}
+		prompt, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 LLM 价格配置: %s", entry)
+		}
+		completion, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 LLM 价格配置: %s", entry)
+		}
+		prices[strings.TrimSpace(parts[0])] = LLMPrice{Prompt: prompt, Completion: completion}
+	}
+	return prices, nil
 }
 
 // MCPConfig MCP 配置
 type MCPConfig struct {
 	ServerURL string `mapstructure:"server_url" env:"MCP_SERVER_URL" default:"mock://"` // mock:// or http://...
 	Timeout   int    `mapstructure:"timeout" env:"MCP_TIMEOUT" default:"30"`            // seconds
+	APIKey    string `mapstructure:"api_key" env:"MCP_API_KEY" default:""`              // API Key 或 JWT
 }
 
 // ServerConfig Server API 配置
 type ServerConfig struct {
 	ServerURL string `mapstructure:"server_url" env:"SERVER_URL" default:"mock://"` // mock:// or http://...
 	Timeout   int    `mapstructure:"timeout" env:"SERVER_TIMEOUT" default:"30"`     // seconds
+	APIKey    string `mapstructure:"api_key" env:"SERVER_API_KEY" default:""`       // API Key 或 JWT
+}
+
+// AuthConfig 认证配置
+// APIKeys 格式为逗号分隔的 "key:role:user_id"，role 为 dm、player 或 observer
+type AuthConfig struct {
+	Enabled   bool   `mapstructure:"enabled" env:"AUTH_ENABLED" default:"false"`
+	APIKeys   string `mapstructure:"api_keys" env:"AUTH_API_KEYS" default:""`
+	JWTSecret string `mapstructure:"jwt_secret" env:"AUTH_JWT_SECRET" default:""`
+}
+
+// TurnConfig 多人战役回合协调配置
+type TurnConfig struct {
+	BatchWindow    int  `mapstructure:"batch_window" env:"TURN_BATCH_WINDOW_MS" default:"0"`        // 等待合并其他玩家消息的时间（毫秒），0 表示不等待
+	MaxBatch       int  `mapstructure:"max_batch" env:"TURN_MAX_BATCH" default:"4"`                 // 一轮最多合并的消息数，1 表示不合并
+	MaxQueue       int  `mapstructure:"max_queue" env:"TURN_MAX_QUEUE" default:"16"`                // 每个战役最多排队的消息数
+	InitiativeMode bool `mapstructure:"initiative_mode" env:"TURN_INITIATIVE_MODE" default:"false"` // 战斗中只接受当前行动者的玩家发言
 }
 
 // Load 从环境变量和.env文件加载配置
@@ -123,22 +265,60 @@ func Load() (*Config, error) {
 		LLM: LLMConfig{
 			Provider:    getEnv("LLM_PROVIDER", "mock"),
 			APIKey:      getEnv("LLM_API_KEY", ""),
-			BaseURL:     getEnv("LLM_BASE_URL", "https://api.openai.com/v1"),
+			BaseURL:     getEnv("LLM_BASE_URL", ""),
 			Model:       getEnv("LLM_MODEL", "gpt-4"),
+			APIVersion:  getEnv("LLM_API_VERSION", ""),
 			MaxTokens:   getEnvInt("LLM_MAX_TOKENS", 4096),
 			Temperature: getEnvFloat64("LLM_TEMPERATURE", 0.7),
 			Timeout:     getEnvInt("LLM_TIMEOUT", 30),
+
+			MaxToolIterations: getEnvInt("LLM_MAX_TOOL_ITERATIONS", 8),
+			MaxToolCalls:      getEnvInt("LLM_MAX_TOOL_CALLS", 16),
+			ToolCatalogTTL:    getEnvInt("LLM_TOOL_CATALOG_TTL", 300),
+
+			MaxRetries:       getEnvInt("LLM_MAX_RETRIES", 3),
+			RetryBaseDelay:   getEnvInt("LLM_RETRY_BASE_DELAY_MS", 500),
+			RetryMaxDelay:    getEnvInt("LLM_RETRY_MAX_DELAY_MS", 10000),
+			BreakerThreshold: getEnvInt("LLM_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getEnvInt("LLM_BREAKER_COOLDOWN", 30),
+			FallbackModels:   getEnvList("LLM_FALLBACK_MODELS"),
+
+			CampaignRateLimit:   getEnvInt("LLM_CAMPAIGN_RATE_LIMIT", 30),
+			CampaignRateBurst:   getEnvInt("LLM_CAMPAIGN_RATE_BURST", 10),
+			CampaignTokenBudget: int64(getEnvInt("LLM_CAMPAIGN_TOKEN_BUDGET", 0)),
+			CampaignCostBudget:  getEnvFloat64("LLM_CAMPAIGN_COST_BUDGET", 0),
+
+			Providers: loadLLMProviders(),
 		},
 		MCP: MCPConfig{
 			ServerURL: getEnv("MCP_SERVER_URL", "mock://"),
 			Timeout:   getEnvInt("MCP_TIMEOUT", 30),
+			APIKey:    getEnv("MCP_API_KEY", ""),
 		},
 		Server: ServerConfig{
 			ServerURL: getEnv("SERVER_URL", "mock://"),
 			Timeout:   getEnvInt("SERVER_TIMEOUT", 30),
+			APIKey:    getEnv("SERVER_API_KEY", ""),
+		},
+		Auth: AuthConfig{
+			Enabled:   getEnvBool("AUTH_ENABLED", false),
+			APIKeys:   getEnv("AUTH_API_KEYS", ""),
+			JWTSecret: getEnv("AUTH_JWT_SECRET", ""),
+		},
+		Turn: TurnConfig{
+			BatchWindow:    getEnvInt("TURN_BATCH_WINDOW_MS", 0),
+			MaxBatch:       getEnvInt("TURN_MAX_BATCH", 4),
+			MaxQueue:       getEnvInt("TURN_MAX_QUEUE", 16),
+			InitiativeMode: getEnvBool("TURN_INITIATIVE_MODE", false),
 		},
 	}
 
+	prices, err := loadLLMPrices()
+	if err != nil {
+		return nil, fmt.Errorf("配置验证失败: %w", err)
+	}
+	cfg.LLM.Prices = prices
+
 	// 验证配置
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("配置验证失败: %w", err)
@@ -209,13 +389,14 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("postgres max conn idletime 必须大于 0")
 	}
 
-	// 验证 LLM 配置
-	if c.LLM.Provider != "mock" && c.LLM.Provider != "openai" {
-		return fmt.Errorf("无效的 LLM provider: %s", c.LLM.Provider)
+	// 验证 LLM 配置（provider 是否已注册由 LLM 注册表在创建客户端时检查）
+	if err := validateLLMProvider(c.LLM.Provider, c.LLM.APIKey, c.LLM.BaseURL); err != nil {
+		return err
 	}
-
-	if c.LLM.Provider == "openai" && c.LLM.APIKey == "" {
-		return fmt.Errorf("OpenAI API key 不能为空")
+	for name, provider := range c.LLM.Providers {
+		if err := validateLLMProvider(name, provider.APIKey, provider.BaseURL); err != nil {
+			return err
+		}
 	}
 
 	if c.LLM.MaxTokens <= 0 {
@@ -230,6 +411,38 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("LLM timeout 必须大于 0")
 	}
 
+	if c.LLM.MaxToolIterations <= 0 {
+		return fmt.Errorf("LLM max tool iterations 必须大于 0")
+	}
+
+	if c.LLM.MaxToolCalls <= 0 {
+		return fmt.Errorf("LLM max tool calls 必须大于 0")
+	}
+
+	if c.LLM.ToolCatalogTTL <= 0 {
+		return fmt.Errorf("LLM tool catalog TTL 必须大于 0")
+	}
+
+	if c.LLM.MaxRetries < 0 {
+		return fmt.Errorf("LLM max retries 不能为负数")
+	}
+
+	if c.LLM.RetryBaseDelay <= 0 || c.LLM.RetryMaxDelay < c.LLM.RetryBaseDelay {
+		return fmt.Errorf("LLM retry delay 必须大于 0，且上限不小于初始值")
+	}
+
+	if c.LLM.BreakerThreshold < 0 || c.LLM.BreakerCooldown <= 0 {
+		return fmt.Errorf("LLM breaker threshold 不能为负数，cooldown 必须大于 0")
+	}
+
+	if c.LLM.CampaignRateLimit < 0 || c.LLM.CampaignRateBurst <= 0 {
+		return fmt.Errorf("LLM campaign rate limit 不能为负数，burst 必须大于 0")
+	}
+
+	if c.LLM.CampaignTokenBudget < 0 || c.LLM.CampaignCostBudget < 0 {
+		return fmt.Errorf("LLM campaign budget 不能为负数")
+	}
+
 	// 验证 MCP 配置
 	if c.MCP.ServerURL == "" {
 		return fmt.Errorf("MCP server URL 不能为空")
@@ -248,6 +461,20 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("Server timeout 必须大于 0")
 	}
 
+	// 验证认证配置
+	if c.Auth.Enabled && c.Auth.APIKeys == "" && c.Auth.JWTSecret == "" {
+		return fmt.Errorf("启用认证时必须配置 API Key 或 JWT 密钥")
+	}
+
+	// 验证回合协调配置
+	if c.Turn.BatchWindow < 0 {
+		return fmt.Errorf("turn batch window 不能为负数")
+	}
+
+	if c.Turn.MaxBatch <= 0 || c.Turn.MaxQueue <= 0 {
+		return fmt.Errorf("turn max batch 和 max queue 必须大于 0")
+	}
+
 	return nil
 }
 
@@ -288,3 +515,14 @@ func getEnvFloat64(key string, defaultValue float64) float64 {
 	}
 	return defaultValue
 }
+
+// getEnvList 获取逗号分隔的环境变量，去掉空白和空项
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,149 @@
+// Package api_test 测试 API 认证中间件
+package api_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/client/internal/api/middleware"
+	"github.com/dnd-mcp/client/internal/auth"
+	"github.com/dnd-mcp/client/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+const testSecret = "client-secret"
+
+// signToken 按 Server 的格式签发 HS256 JWT
+func signToken(t *testing.T, secret, sub, role string, exp time.Time) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, err := json.Marshal(map[string]any{"sub": sub, "role": role, "exp": exp.Unix()})
+	if err != nil {
+		t.Fatalf("序列化声明失败: %v", err)
+	}
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// setupRouter 创建启用认证的路由，处理器返回调用者的用户 ID
+func setupRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	authenticator, err := auth.NewAuthenticator(&config.AuthConfig{
+		Enabled:   true,
+		APIKeys:   "dm-key:dm:alice,player-key:player:bob,observer-key:observer:carol",
+		JWTSecret: testSecret,
+	})
+	if err != nil {
+		t.Fatalf("创建认证器失败: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(middleware.Auth(authenticator))
+	whoami := func(c *gin.Context) {
+		principal, _ := auth.FromContext(c.Request.Context())
+		c.String(http.StatusOK, principal.UserID)
+	}
+	router.GET("/health", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	router.GET("/api/sessions", whoami)
+	router.POST("/api/sessions/:id/chat", whoami)
+	router.DELETE("/api/sessions/:id", whoami)
+	return router
+}
+
+func serve(router *gin.Engine, method, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestAuth_Credentials 测试凭证校验
+func TestAuth_Credentials(t *testing.T) {
+	router := setupRouter(t)
+
+	tests := []struct {
+		name       string
+		target     string
+		headers    map[string]string
+		wantStatus int
+		wantUser   string
+	}{
+		{"健康检查无需认证", "/health", nil, http.StatusOK, "ok"},
+		{"缺少凭证", "/api/sessions", nil, http.StatusUnauthorized, ""},
+		{"无效 API Key", "/api/sessions", map[string]string{"X-API-Key": "nope"}, http.StatusUnauthorized, ""},
+		{"Bearer API Key", "/api/sessions", map[string]string{"Authorization": "Bearer dm-key"}, http.StatusOK, "alice"},
+		{"X-API-Key", "/api/sessions", map[string]string{"X-API-Key": "player-key"}, http.StatusOK, "bob"},
+		{"查询参数令牌", "/api/sessions?token=observer-key", nil, http.StatusOK, "carol"},
+		{"有效 JWT", "/api/sessions", map[string]string{"Authorization": "Bearer " + signToken(t, testSecret, "dave", "player", time.Now().Add(time.Hour))}, http.StatusOK, "dave"},
+		{"过期 JWT", "/api/sessions", map[string]string{"Authorization": "Bearer " + signToken(t, testSecret, "dave", "player", time.Now().Add(-time.Minute))}, http.StatusUnauthorized, ""},
+		{"签名错误的 JWT", "/api/sessions", map[string]string{"Authorization": "Bearer " + signToken(t, "other", "dave", "dm", time.Now().Add(time.Hour))}, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodGet, tt.target, tt.headers)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("期望状态码 %d, 得到 %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantUser != "" && rec.Body.String() != tt.wantUser {
+				t.Errorf("期望用户 %s, 得到 %s", tt.wantUser, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestAuth_RolePermissions 测试角色权限
+func TestAuth_RolePermissions(t *testing.T) {
+	router := setupRouter(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		apiKey     string
+		wantStatus int
+	}{
+		{"观察者可以读取", http.MethodGet, "observer-key", http.StatusOK},
+		{"观察者不能发送消息", http.MethodPost, "observer-key", http.StatusForbidden},
+		{"玩家可以发送消息", http.MethodPost, "player-key", http.StatusOK},
+		{"玩家不能删除会话", http.MethodDelete, "player-key", http.StatusForbidden},
+		{"DM 可以删除会话", http.MethodDelete, "dm-key", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/api/sessions"
+			if tt.method != http.MethodGet {
+				target = "/api/sessions/s1"
+				if tt.method == http.MethodPost {
+					target += "/chat"
+				}
+			}
+			rec := serve(router, tt.method, target, map[string]string{"X-API-Key": tt.apiKey})
+			if rec.Code != tt.wantStatus {
+				t.Errorf("期望状态码 %d, 得到 %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+// TestNewAuthenticator_InvalidConfig 测试无效的认证配置
+func TestNewAuthenticator_InvalidConfig(t *testing.T) {
+	if _, err := auth.NewAuthenticator(&config.AuthConfig{Enabled: true}); err == nil {
+		t.Error("未配置凭证时应该返回错误")
+	}
+	if _, err := auth.NewAuthenticator(&config.AuthConfig{APIKeys: "key:wizard:alice"}); err == nil {
+		t.Error("未知角色应该返回错误")
+	}
+}
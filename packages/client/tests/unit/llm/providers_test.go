@@ -0,0 +1,323 @@
+// Package llm_test 测试 LLM 客户端
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dnd-mcp/client/internal/llm"
+	"github.com/dnd-mcp/client/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolConversation 带一轮工具调用的对话
+func toolConversation() *llm.ChatRequest {
+	return &llm.ChatRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: "你是地下城主"},
+			{Role: "user", Content: "我攻击哥布林"},
+			{Role: "assistant", ToolCalls: []llm.ToolCall{
+				{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "roll_dice", Arguments: `{"formula":"1d20+5"}`}},
+			}},
+			{Role: "tool", Content: `{"total":18}`, ToolCallID: "call_1"},
+		},
+		Tools: []llm.Tool{
+			{Type: "function", Function: llm.FunctionDef{
+				Name:       "roll_dice",
+				Parameters: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+			}},
+		},
+		Temperature: 0.7,
+	}
+}
+
+// decodeBody 解析请求体
+func decodeBody(t *testing.T, r *http.Request) map[string]interface{} {
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	return body
+}
+
+// TestAnthropicClient_Chat 测试 Messages API 请求转换和响应转换
+func TestAnthropicClient_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		assert.NotEmpty(t, r.Header.Get("anthropic-version"))
+
+		body := decodeBody(t, r)
+		assert.Equal(t, "claude-test", body["model"])
+		assert.Equal(t, "你是地下城主", body["system"])
+		assert.Equal(t, float64(1024), body["max_tokens"])
+
+		messages := body["messages"].([]interface{})
+		require.Len(t, messages, 3)
+		assistant := messages[1].(map[string]interface{})
+		assert.Equal(t, "assistant", assistant["role"])
+		toolUse := assistant["content"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "tool_use", toolUse["type"])
+		assert.Equal(t, map[string]interface{}{"formula": "1d20+5"}, toolUse["input"])
+
+		result := messages[2].(map[string]interface{})
+		assert.Equal(t, "user", result["role"])
+		toolResult := result["content"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "tool_result", toolResult["type"])
+		assert.Equal(t, "call_1", toolResult["tool_use_id"])
+
+		tool := body["tools"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "roll_dice", tool["name"])
+		assert.NotNil(t, tool["input_schema"])
+
+		fmt.Fprint(w, `{
+			"id": "msg_1", "type": "message", "role": "assistant", "model": "claude-test",
+			"content": [
+				{"type": "text", "text": "命中！再投伤害。"},
+				{"type": "tool_use", "id": "toolu_1", "name": "roll_dice", "input": {"formula": "1d8+3"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 30, "output_tokens": 12}
+		}`)
+	}))
+	defer server.Close()
+
+	client, err := llm.NewAnthropicClient(&config.LLMConfig{
+		APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "claude-test", MaxTokens: 1024, Timeout: 5,
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Chat(context.Background(), toolConversation())
+	require.NoError(t, err)
+
+	choice := resp.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	assert.Equal(t, "命中！再投伤害。", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "toolu_1", choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"formula":"1d8+3"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, 42, resp.Usage.TotalTokens)
+}
+
+// TestAnthropicClient_ChatStream 测试 Messages API 流式事件
+func TestAnthropicClient_ChatStream(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","model":"claude-test","content":[],"usage":{"input_tokens":20,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"ping"}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"你投出了"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" 18"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"roll_dice","input":{}}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"formula\":"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"1d8\"}"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_2","name":"list_monsters","input":{}}}`,
+		`{"type":"content_block_stop","index":2}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":15}}`,
+		`{"type":"message_stop"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, true, decodeBody(t, r)["stream"])
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			var typed struct{ Type string }
+			require.NoError(t, json.Unmarshal([]byte(event), &typed))
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, event)
+		}
+	}))
+	defer server.Close()
+
+	client, err := llm.NewAnthropicClient(&config.LLMConfig{APIKey: "test-key", BaseURL: server.URL, Model: "claude-test", Timeout: 5})
+	require.NoError(t, err)
+
+	var content []string
+	resp, err := client.ChatStream(context.Background(), toolConversation(), func(delta llm.MessageDelta) {
+		if delta.Content != "" {
+			content = append(content, delta.Content)
+		}
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"你投出了", " 18"}, content)
+	assert.Equal(t, "msg_1", resp.ID)
+	choice := resp.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	assert.Equal(t, "你投出了 18", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 2)
+	assert.JSONEq(t, `{"formula":"1d8"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "toolu_2", choice.Message.ToolCalls[1].ID)
+	assert.Equal(t, "{}", choice.Message.ToolCalls[1].Function.Arguments)
+	assert.Equal(t, 35, resp.Usage.TotalTokens)
+}
+
+// TestAnthropicClient_RequiresAPIKey 测试缺少 API key
+func TestAnthropicClient_RequiresAPIKey(t *testing.T) {
+	_, err := llm.NewAnthropicClient(&config.LLMConfig{Model: "claude-test"})
+	assert.Error(t, err)
+}
+
+// TestOllamaClient_Chat 测试 Ollama 请求转换和工具调用
+func TestOllamaClient_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+
+		body := decodeBody(t, r)
+		assert.Equal(t, "llama-test", body["model"])
+		assert.Equal(t, false, body["stream"])
+
+		messages := body["messages"].([]interface{})
+		require.Len(t, messages, 4)
+		call := messages[2].(map[string]interface{})["tool_calls"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"formula": "1d20+5"}, call["function"].(map[string]interface{})["arguments"])
+		assert.Equal(t, "roll_dice", messages[3].(map[string]interface{})["tool_name"])
+		assert.Len(t, body["tools"], 1)
+
+		fmt.Fprint(w, `{
+			"model": "llama-test",
+			"message": {"role": "assistant", "content": "", "tool_calls": [
+				{"function": {"name": "roll_dice", "arguments": {"formula": "1d8+3"}}}
+			]},
+			"done": true, "done_reason": "stop",
+			"prompt_eval_count": 40, "eval_count": 8
+		}`)
+	}))
+	defer server.Close()
+
+	client := llm.NewOllamaClient(&config.LLMConfig{BaseURL: server.URL + "/", Model: "llama-test", Timeout: 5})
+	resp, err := client.Chat(context.Background(), toolConversation())
+	require.NoError(t, err)
+
+	choice := resp.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.True(t, strings.HasPrefix(choice.Message.ToolCalls[0].ID, "call_"), "Ollama 的工具调用没有 ID，由客户端生成")
+	assert.JSONEq(t, `{"formula":"1d8+3"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, 48, resp.Usage.TotalTokens)
+}
+
+// TestOllamaClient_ChatStream 测试 Ollama 按行返回的流式响应
+func TestOllamaClient_ChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, true, decodeBody(t, r)["stream"])
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"model":"llama-test","message":{"role":"assistant","content":"你走进"},"done":false}`)
+		fmt.Fprintln(w, `{"model":"llama-test","message":{"role":"assistant","content":"酒馆。"},"done":false}`)
+		fmt.Fprintln(w, `{"model":"llama-test","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":10,"eval_count":4}`)
+	}))
+	defer server.Close()
+
+	client := llm.NewOllamaClient(&config.LLMConfig{BaseURL: server.URL, Model: "llama-test", Timeout: 5})
+
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), &llm.ChatRequest{
+		Messages: []llm.Message{{Role: "user", Content: "进入酒馆"}},
+	}, func(delta llm.MessageDelta) {
+		deltas = append(deltas, delta.Content)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"你走进", "酒馆。"}, deltas)
+	assert.Equal(t, "你走进酒馆。", resp.Choices[0].Message.Content)
+	assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+	assert.Equal(t, 14, resp.Usage.TotalTokens)
+}
+
+// TestOllamaClient_Error 测试模型不存在等错误
+func TestOllamaClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model \"missing\" not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := llm.NewOllamaClient(&config.LLMConfig{BaseURL: server.URL, Model: "missing", Timeout: 5})
+	_, err := client.Chat(context.Background(), &llm.ChatRequest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+// TestAzureOpenAIClient_Chat 测试 Azure 部署地址和认证头
+func TestAzureOpenAIClient_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/openai/deployments/dnd-gpt/chat/completions", r.URL.Path)
+		assert.Equal(t, "2024-10-21", r.URL.Query().Get("api-version"))
+		assert.Equal(t, "azure-key", r.Header.Get("api-key"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+
+		fmt.Fprint(w, `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"欢迎冒险者"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := llm.NewAzureOpenAIClient(&config.LLMConfig{
+		APIKey: "azure-key", BaseURL: server.URL + "/", Model: "dnd-gpt", APIVersion: "2024-10-21", Timeout: 5,
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Chat(context.Background(), &llm.ChatRequest{
+		Messages: []llm.Message{{Role: "user", Content: "你好"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "欢迎冒险者", resp.Choices[0].Message.Content)
+
+	_, err = llm.NewAzureOpenAIClient(&config.LLMConfig{APIKey: "azure-key"})
+	assert.Error(t, err, "缺少 base URL")
+}
+
+// TestRegistry 测试 provider 注册表
+func TestRegistry(t *testing.T) {
+	assert.Equal(t, []string{"anthropic", "azure", "mock", "ollama", "openai"}, llm.DefaultRegistry.Providers())
+
+	registry := llm.NewRegistry()
+	_, err := registry.New(&config.LLMConfig{Provider: "mock"})
+	assert.Error(t, err)
+
+	registry.Register("local", func(cfg *config.LLMConfig) (llm.LLMClient, error) {
+		return llm.NewMockLLMClient(), nil
+	})
+	assert.True(t, registry.Has("local"))
+	client, err := registry.New(&config.LLMConfig{Provider: "local"})
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
+
+// TestClientPool 测试客户端池按 provider 使用各自的配置并缓存客户端
+func TestClientPool(t *testing.T) {
+	var created []config.LLMConfig
+	registry := llm.NewRegistry()
+	for _, name := range []string{"openai", "ollama"} {
+		registry.Register(name, func(cfg *config.LLMConfig) (llm.LLMClient, error) {
+			created = append(created, *cfg)
+			return llm.NewMockLLMClient(), nil
+		})
+	}
+
+	pool := llm.NewClientPool(registry, &config.LLMConfig{
+		Provider: "openai", APIKey: "openai-key", Model: "gpt-test", MaxTokens: 2048,
+		Providers: map[string]config.LLMProviderConfig{
+			"ollama": {BaseURL: "http://gpu-box:11434", Model: "llama-test"},
+		},
+	})
+
+	defaultClient, err := pool.Get("")
+	require.NoError(t, err)
+	again, err := pool.Get("openai")
+	require.NoError(t, err)
+	assert.Same(t, defaultClient, again)
+
+	_, err = pool.Get("ollama")
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, "ollama", created[1].Provider)
+	assert.Empty(t, created[1].APIKey, "不继承默认 provider 的 API key")
+	assert.Equal(t, "http://gpu-box:11434", created[1].BaseURL)
+	assert.Equal(t, "llama-test", created[1].Model)
+	assert.Equal(t, 2048, created[1].MaxTokens)
+
+	_, err = pool.Get("anthropic")
+	assert.Error(t, err, "未配置的 provider")
+}
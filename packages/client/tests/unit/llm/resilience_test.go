@@ -0,0 +1,258 @@
+// Package llm_test 测试 LLM 客户端
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/client/internal/llm"
+	"github.com/dnd-mcp/client/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedClient 按顺序返回预设结果，并记录请求的模型
+type scriptedClient struct {
+	mu      sync.Mutex
+	results []error
+	models  []string
+}
+
+func (c *scriptedClient) next(req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.models = append(c.models, req.Model)
+
+	var err error
+	if len(c.results) > 0 {
+		err, c.results = c.results[0], c.results[1:]
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &llm.ChatResponse{
+		Model:   req.Model,
+		Choices: []llm.Choice{{Message: llm.Message{Role: "assistant", Content: "ok"}, FinishReason: "stop"}},
+		Usage:   llm.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func (c *scriptedClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return c.next(req)
+}
+
+func (c *scriptedClient) ChatStream(ctx context.Context, req *llm.ChatRequest, handler llm.StreamHandler) (*llm.ChatResponse, error) {
+	resp, err := c.next(req)
+	if err == nil && handler != nil {
+		handler(llm.MessageDelta{Content: "ok"})
+	}
+	return resp, err
+}
+
+func apiError(status int) error {
+	return &llm.APIError{StatusCode: status, Body: "{}"}
+}
+
+var fastRetry = llm.RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// TestWithRetry_RetryAfter 测试 429 时按 Retry-After 重试
+func TestWithRetry_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, `{"error":{"message":"rate limited"}}`, http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"index":0,"message":{"role":"assistant","content":"你好"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	client := llm.Chain(newStreamClient(server.URL), llm.WithRetry(fastRetry))
+	resp, err := client.Chat(context.Background(), &llm.ChatRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "你好", resp.Choices[0].Message.Content)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestWithRetry_NotRetryable 测试请求错误不重试、重试次数用完后返回最后的错误
+func TestWithRetry_NotRetryable(t *testing.T) {
+	inner := &scriptedClient{results: []error{apiError(http.StatusBadRequest)}}
+	_, err := llm.Chain(inner, llm.WithRetry(fastRetry)).Chat(context.Background(), &llm.ChatRequest{})
+	var apiErr *llm.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Len(t, inner.models, 1)
+
+	inner = &scriptedClient{results: []error{apiError(500), apiError(502), apiError(503), nil}}
+	_, err = llm.Chain(inner, llm.WithRetry(fastRetry)).Chat(context.Background(), &llm.ChatRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 503, apiErr.StatusCode)
+	assert.Len(t, inner.models, 3, "首次调用加两次重试")
+}
+
+// TestWithCircuitBreaker 测试连续失败后熔断，冷却后放行试探调用
+func TestWithCircuitBreaker(t *testing.T) {
+	inner := &scriptedClient{results: []error{apiError(500), apiError(500)}}
+	client := llm.Chain(inner, llm.WithCircuitBreaker(llm.BreakerConfig{Threshold: 2, Cooldown: 20 * time.Millisecond}))
+	ctx := context.Background()
+	req := &llm.ChatRequest{Model: "gpt-test"}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Chat(ctx, req)
+		require.Error(t, err)
+	}
+	_, err := client.Chat(ctx, req)
+	assert.ErrorIs(t, err, llm.ErrCircuitOpen)
+	assert.Len(t, inner.models, 2, "熔断期间不调用")
+
+	// 其他模型不受影响
+	_, err = client.Chat(ctx, &llm.ChatRequest{Model: "gpt-other"})
+	assert.NoError(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = client.Chat(ctx, req)
+	assert.NoError(t, err, "冷却后试探成功，恢复调用")
+}
+
+// TestWithFallbackModels 测试主模型失败时改用备用模型
+func TestWithFallbackModels(t *testing.T) {
+	inner := &scriptedClient{results: []error{apiError(503), apiError(429), nil}}
+	client := llm.Chain(inner, llm.WithFallbackModels([]string{"gpt-test", "gpt-small", "gpt-tiny"}))
+
+	resp, err := client.Chat(context.Background(), &llm.ChatRequest{Model: "gpt-test"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gpt-test", "gpt-small", "gpt-tiny"}, inner.models)
+	assert.Equal(t, "gpt-tiny", resp.Model)
+
+	// 请求错误不切换模型
+	inner = &scriptedClient{results: []error{apiError(400)}}
+	_, err = llm.Chain(inner, llm.WithFallbackModels([]string{"gpt-small"})).Chat(context.Background(), &llm.ChatRequest{Model: "gpt-test"})
+	assert.Error(t, err)
+	assert.Len(t, inner.models, 1)
+}
+
+// TestWithFallbackModels_Stream 测试流式调用已推送内容后不再切换或重试
+func TestWithFallbackModels_Stream(t *testing.T) {
+	partial := &partialStreamClient{}
+	client := llm.Chain(partial, llm.WithFallbackModels([]string{"gpt-small"}), llm.WithRetry(fastRetry))
+
+	var deltas []string
+	_, err := client.ChatStream(context.Background(), &llm.ChatRequest{Model: "gpt-test"}, func(delta llm.MessageDelta) {
+		deltas = append(deltas, delta.Content)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"你走"}, deltas)
+	assert.Equal(t, int32(1), partial.calls.Load())
+}
+
+// partialStreamClient 推送一个片段后连接中断
+type partialStreamClient struct {
+	calls atomic.Int32
+}
+
+func (c *partialStreamClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return nil, errors.New("not used")
+}
+
+func (c *partialStreamClient) ChatStream(ctx context.Context, req *llm.ChatRequest, handler llm.StreamHandler) (*llm.ChatResponse, error) {
+	c.calls.Add(1)
+	handler(llm.MessageDelta{Content: "你走"})
+	return nil, apiError(http.StatusBadGateway)
+}
+
+// TestRateLimiter 测试按战役的令牌桶
+func TestRateLimiter(t *testing.T) {
+	inner := &scriptedClient{}
+	limiter := llm.NewRateLimiter(60, 2, 0)
+	client := llm.Chain(inner, llm.WithRateLimit(limiter))
+
+	ctx := llm.WithCampaign(context.Background(), "campaign-1")
+	for i := 0; i < 2; i++ {
+		_, err := client.Chat(ctx, &llm.ChatRequest{})
+		require.NoError(t, err)
+	}
+	_, err := client.Chat(ctx, &llm.ChatRequest{})
+	assert.ErrorIs(t, err, llm.ErrRateLimited)
+
+	// 其他战役和没有战役的调用不受影响
+	_, err = client.Chat(llm.WithCampaign(context.Background(), "campaign-2"), &llm.ChatRequest{})
+	assert.NoError(t, err)
+	_, err = client.Chat(context.Background(), &llm.ChatRequest{})
+	assert.NoError(t, err)
+
+	// 允许等待时等到令牌补充
+	waiting := llm.NewRateLimiter(6000, 1, time.Second)
+	require.NoError(t, waiting.Wait(context.Background(), "campaign-1"))
+	start := time.Now()
+	require.NoError(t, waiting.Wait(context.Background(), "campaign-1"))
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+}
+
+// usageRecord 记录的一次用量
+type usageRecord struct {
+	campaignID string
+	model      string
+	prompt     int
+	completion int
+}
+
+type recordingUsage struct {
+	records []usageRecord
+}
+
+func (r *recordingUsage) RecordUsage(campaignID, model string, promptTokens, completionTokens int) {
+	r.records = append(r.records, usageRecord{campaignID, model, promptTokens, completionTokens})
+}
+
+// TestWithUsageRecorder 测试按战役记录用量，失败的调用不记录
+func TestWithUsageRecorder(t *testing.T) {
+	recorder := &recordingUsage{}
+	inner := &scriptedClient{results: []error{nil, apiError(500)}}
+	client := llm.Chain(inner, llm.WithUsageRecorder(recorder))
+
+	ctx := llm.WithCampaign(context.Background(), "campaign-1")
+	_, err := client.ChatStream(ctx, &llm.ChatRequest{Model: "gpt-test"}, nil)
+	require.NoError(t, err)
+	_, err = client.Chat(ctx, &llm.ChatRequest{Model: "gpt-test"})
+	require.Error(t, err)
+
+	assert.Equal(t, []usageRecord{{"campaign-1", "gpt-test", 10, 5}}, recorder.records)
+}
+
+// TestClientPool_Resilience 测试客户端池按配置包装重试和备用模型
+func TestClientPool_Resilience(t *testing.T) {
+	inner := &scriptedClient{results: []error{apiError(500), apiError(500)}}
+	registry := llm.NewRegistry()
+	registry.Register("openai", func(cfg *config.LLMConfig) (llm.LLMClient, error) {
+		return inner, nil
+	})
+	recorder := &recordingUsage{}
+
+	pool := llm.NewClientPool(registry, &config.LLMConfig{
+		Provider:         "openai",
+		MaxRetries:       1,
+		RetryBaseDelay:   1,
+		RetryMaxDelay:    1,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30,
+		FallbackModels:   []string{"gpt-small"},
+	})
+	pool.Use(llm.WithUsageRecorder(recorder))
+	client, err := pool.Get("")
+	require.NoError(t, err)
+
+	resp, err := client.Chat(llm.WithCampaign(context.Background(), "campaign-1"), &llm.ChatRequest{Model: "gpt-test"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gpt-test", "gpt-test", "gpt-small"}, inner.models)
+	assert.Equal(t, "gpt-small", resp.Model)
+	require.Len(t, recorder.records, 1)
+	assert.Equal(t, "gpt-small", recorder.records[0].model)
+}
@@ -0,0 +1,110 @@
+// Package llm_test 测试 LLM 客户端
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dnd-mcp/client/internal/llm"
+	"github.com/dnd-mcp/client/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseServer 按顺序以 SSE 返回 chunks
+func sseServer(t *testing.T, chunks []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, true, req["stream"])
+		assert.Equal(t, "test-model", req["model"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func newStreamClient(url string) *llm.OpenAIClient {
+	return llm.NewOpenAIClient(&config.LLMConfig{
+		APIKey:  "test-key",
+		BaseURL: url,
+		Model:   "test-model",
+		Timeout: 5,
+	})
+}
+
+// TestOpenAIClient_ChatStream_Content 测试流式文本回复
+func TestOpenAIClient_ChatStream_Content(t *testing.T) {
+	server := sseServer(t, []string{
+		`{"id":"chatcmpl-1","model":"test-model","choices":[{"index":0,"delta":{"role":"assistant","content":""}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"你走进"}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"酒馆。"}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`,
+	})
+	defer server.Close()
+
+	var deltas []string
+	resp, err := newStreamClient(server.URL).ChatStream(context.Background(), &llm.ChatRequest{
+		Messages: []llm.Message{{Role: "user", Content: "进入酒馆"}},
+	}, func(delta llm.MessageDelta) {
+		deltas = append(deltas, delta.Content)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"你走进", "酒馆。"}, deltas)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "assistant", resp.Choices[0].Message.Role)
+	assert.Equal(t, "你走进酒馆。", resp.Choices[0].Message.Content)
+	assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+	assert.Equal(t, "chatcmpl-1", resp.ID)
+	assert.Equal(t, 17, resp.Usage.TotalTokens)
+}
+
+// TestOpenAIClient_ChatStream_ToolCalls 测试分片返回的工具调用参数
+func TestOpenAIClient_ChatStream_ToolCalls(t *testing.T) {
+	server := sseServer(t, []string{
+		`{"choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"roll_dice","arguments":""}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"formula\":"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"resolve_attack","arguments":"{}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"1d20+5\"}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+	})
+	defer server.Close()
+
+	fragments := 0
+	resp, err := newStreamClient(server.URL).ChatStream(context.Background(), &llm.ChatRequest{
+		Messages: []llm.Message{{Role: "user", Content: "投 d20"}},
+	}, func(delta llm.MessageDelta) {
+		fragments += len(delta.ToolCalls)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, fragments)
+	choice := resp.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 2)
+	assert.Equal(t, "call_1", choice.Message.ToolCalls[0].ID)
+	assert.Equal(t, "roll_dice", choice.Message.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"formula":"1d20+5"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "call_2", choice.Message.ToolCalls[1].ID)
+}
+
+// TestOpenAIClient_ChatStream_Error 测试 API 错误
+func TestOpenAIClient_ChatStream_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"rate limited"}}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := newStreamClient(server.URL).ChatStream(context.Background(), &llm.ChatRequest{}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+}
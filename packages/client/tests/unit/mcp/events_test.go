@@ -0,0 +1,198 @@
+// Package mcp_test 测试 MCP 游戏事件订阅
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/client/internal/mcp"
+)
+
+// receiveEvent 在超时前接收一个事件
+func receiveEvent(t *testing.T, events <-chan mcp.Event) mcp.Event {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("事件通道意外关闭")
+		}
+		return event
+	case <-time.After(3 * time.Second):
+		t.Fatal("等待事件超时")
+	}
+	return mcp.Event{}
+}
+
+// TestHTTPClient_SubscribeEvents_Stream 测试通过 SSE 事件流订阅并断线续传
+func TestHTTPClient_SubscribeEvents_Stream(t *testing.T) {
+	mcp.EventRetryInterval = 10 * time.Millisecond
+
+	var mu sync.Mutex
+	var lastEventIDs []string
+	connections := 0
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mcp/events":
+			if r.URL.Query().Get("session_id") != "campaign-1" {
+				t.Errorf("期望 session_id campaign-1, 得到 %s", r.URL.Query().Get("session_id"))
+			}
+			json.NewEncoder(w).Encode(map[string]any{"events": []any{}, "cursor": 5})
+		case "/mcp/events/stream":
+			mu.Lock()
+			lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+			connections++
+			n := connections
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			// 每次连接发送一个事件后断开,客户端应携带最新游标重连
+			cursor := 5 + n
+			fmt.Fprintf(w, ": keep-alive\n\n")
+			fmt.Fprintf(w, "id: %d\nevent: hp_changed\ndata: {\"cursor\":%d,\"type\":\"hp_changed\",\"session_id\":\"campaign-1\",\"data\":{\"current\":%d}}\n\n", cursor, cursor, n)
+		default:
+			t.Errorf("意外的请求路径: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	client := mcp.NewHTTPClient(testServer.URL+"/mcp", 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.SubscribeEvents(ctx, "campaign-1", []string{mcp.EventHPChanged})
+	if err != nil {
+		t.Fatalf("SubscribeEvents() 不应该返回错误: %v", err)
+	}
+
+	first := receiveEvent(t, events)
+	second := receiveEvent(t, events)
+	if first.Cursor != 6 || second.Cursor != 7 {
+		t.Errorf("期望游标 6 和 7, 得到 %d 和 %d", first.Cursor, second.Cursor)
+	}
+	if first.Type != mcp.EventHPChanged || first.Data["current"] != float64(1) {
+		t.Errorf("事件内容不正确: %+v", first)
+	}
+
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if lastEventIDs[0] != "5" || lastEventIDs[1] != "6" {
+		t.Errorf("期望依次携带 Last-Event-ID 5 和 6, 得到 %v", lastEventIDs)
+	}
+}
+
+// TestHTTPClient_SubscribeEvents_PollFallback 测试服务器不支持事件流时退回游标轮询
+func TestHTTPClient_SubscribeEvents_PollFallback(t *testing.T) {
+	mcp.EventPollInterval = 10 * time.Millisecond
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mcp/events":
+			after := r.URL.Query().Get("after")
+			switch after {
+			case "":
+				json.NewEncoder(w).Encode(map[string]any{"events": []any{}, "cursor": 2})
+			case "2":
+				json.NewEncoder(w).Encode(map[string]any{
+					"events": []map[string]any{
+						{"cursor": 3, "type": "dice_rolled", "session_id": "campaign-1", "data": map[string]any{"total": 17}},
+					},
+					"cursor": 3,
+				})
+			default:
+				json.NewEncoder(w).Encode(map[string]any{"events": []any{}, "cursor": 3})
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer testServer.Close()
+
+	client := mcp.NewHTTPClient(testServer.URL+"/mcp", 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.SubscribeEvents(ctx, "campaign-1", nil)
+	if err != nil {
+		t.Fatalf("SubscribeEvents() 不应该返回错误: %v", err)
+	}
+
+	event := receiveEvent(t, events)
+	if event.Cursor != 3 || event.Type != mcp.EventDiceRolled {
+		t.Errorf("期望游标 3 的 dice_rolled 事件, 得到 %+v", event)
+	}
+
+	// 已追上最新游标,不应重复收到事件
+	select {
+	case event := <-events:
+		t.Errorf("不应重复收到事件: %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestToWSEvent 测试游戏事件到 WebSocket 事件的转换
+func TestToWSEvent(t *testing.T) {
+	tests := []struct {
+		eventType string
+		wsType    string
+	}{
+		{mcp.EventHPChanged, "combat_updated"},
+		{mcp.EventConditionApplied, "combat_updated"},
+		{mcp.EventConditionRemoved, "combat_updated"},
+		{mcp.EventTurnAdvanced, "combat_updated"},
+		{mcp.EventTokenMoved, "character_moved"},
+		{mcp.EventDiceRolled, "dice_rolled"},
+		{mcp.EventMapEntered, "state_changed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			data := map[string]interface{}{"character_id": "char-1"}
+			wsEvent := mcp.ToWSEvent("campaign-1", mcp.Event{Cursor: 9, Type: tt.eventType, Data: data})
+
+			if wsEvent.Type != tt.wsType {
+				t.Errorf("期望类型 %s, 得到 %s", tt.wsType, wsEvent.Type)
+			}
+			if wsEvent.SessionID != "campaign-1" {
+				t.Errorf("期望会话 campaign-1, 得到 %s", wsEvent.SessionID)
+			}
+			if wsEvent.Data["event"] != tt.eventType || wsEvent.Data["cursor"] != int64(9) || wsEvent.Data["character_id"] != "char-1" {
+				t.Errorf("事件数据不正确: %v", wsEvent.Data)
+			}
+			if _, ok := data["event"]; ok {
+				t.Error("不应修改原始事件数据")
+			}
+		})
+	}
+}
+
+// TestEventRelay 测试会话开始和结束时启停事件转发
+func TestEventRelay(t *testing.T) {
+	relay := mcp.NewEventRelay(context.Background(), mcp.NewMockClient(), nil)
+
+	relay.SessionOpened("campaign-1")
+	relay.SessionOpened("campaign-1")
+	relay.SessionOpened("campaign-2")
+	if relay.Sessions() != 2 {
+		t.Errorf("期望转发 2 个会话, 得到 %d", relay.Sessions())
+	}
+
+	relay.SessionClosed("campaign-1")
+	relay.SessionClosed("campaign-1")
+	if relay.Sessions() != 1 {
+		t.Errorf("期望转发 1 个会话, 得到 %d", relay.Sessions())
+	}
+
+	relay.SessionClosed("campaign-2")
+	if relay.Sessions() != 0 {
+		t.Errorf("期望没有转发的会话, 得到 %d", relay.Sessions())
+	}
+}
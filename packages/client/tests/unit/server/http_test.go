@@ -714,6 +714,48 @@ func TestHTTPClient_GetRawContext(t *testing.T) {
 	})
 }
 
+// TestHTTPClient_ListTools 测试获取工具目录
+func TestHTTPClient_ListTools(t *testing.T) {
+	testServer := setupTestHTTPServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mcp/tools" {
+			t.Errorf("期望路径 /mcp/tools, 得到 %s", r.URL.Path)
+		}
+		if r.Method != "GET" {
+			t.Errorf("期望方法 GET, 得到 %s", r.Method)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]any{
+			"tools": []map[string]any{
+				{
+					"name":        "roll_dice",
+					"description": "Roll dice",
+					"inputSchema": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"formula": map[string]any{"type": "string"},
+						},
+						"required": []string{"formula"},
+					},
+				},
+			},
+		})
+	}))
+	defer testServer.Close()
+
+	client := serverpkg.NewHTTPClient(testServer.URL, 30)
+	tools, err := client.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools() 不应该返回错误: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "roll_dice" {
+		t.Fatalf("期望返回 roll_dice, 得到 %+v", tools)
+	}
+	if len(tools[0].InputSchema.Required) != 1 || tools[0].InputSchema.Properties["formula"] == nil {
+		t.Errorf("工具参数定义不完整: %+v", tools[0].InputSchema)
+	}
+}
+
 // TestHTTPClient_Close 测试关闭方法
 func TestHTTPClient_Close(t *testing.T) {
 	t.Run("关闭成功", func(t *testing.T) {
@@ -777,3 +819,21 @@ func TestHTTPClient_Timeout(t *testing.T) {
 		}
 	})
 }
+
+// TestHTTPClient_APIKey 测试请求携带 API Key
+func TestHTTPClient_APIKey(t *testing.T) {
+	testServer := setupTestHTTPServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret-key" {
+			t.Errorf("期望 Authorization: Bearer secret-key, 得到 %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]any{"protocolVersion": "2024-11-05"})
+	}))
+	defer testServer.Close()
+
+	client := serverpkg.NewHTTPClient(testServer.URL, 30)
+	client.SetAPIKey("secret-key")
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Errorf("Initialize() 不应该返回错误: %v", err)
+	}
+}
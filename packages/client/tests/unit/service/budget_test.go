@@ -0,0 +1,102 @@
+// Package service_test 提供 Service 层单元测试
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dnd-mcp/client/internal/models"
+	"github.com/dnd-mcp/client/internal/monitor"
+	"github.com/dnd-mcp/client/internal/service"
+	"github.com/dnd-mcp/client/internal/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCampaigns 内存中的战役，记录状态更新
+type stubCampaigns map[string]*models.Session
+
+func (s stubCampaigns) GetSession(ctx context.Context, sessionID string) (*models.Session, error) {
+	session, ok := s[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+	return session, nil
+}
+
+func (s stubCampaigns) UpdateSession(ctx context.Context, sessionID string, req *service.UpdateSessionRequest) (*models.Session, error) {
+	session, err := s.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != nil {
+		session.Status = *req.Status
+	}
+	return session, nil
+}
+
+// TestBudgetGuard_PausesCampaign 测试用量达到预算时暂停战役并推送事件
+func TestBudgetGuard_PausesCampaign(t *testing.T) {
+	campaigns := stubCampaigns{
+		"campaign-1": campaignWithSettings("campaign-1", nil),
+		"campaign-2": campaignWithSettings("campaign-2", map[string]interface{}{
+			models.SettingLLMTokenBudget: float64(10000),
+		}),
+	}
+	tracker := monitor.NewUsageTracker(nil)
+	guard := service.NewBudgetGuard(tracker, campaigns, service.UsageBudget{MaxTokens: 1000})
+	broadcaster := &recordingBroadcaster{}
+	guard.SetBroadcaster(broadcaster)
+
+	ctx := context.Background()
+	require.NoError(t, guard.Check(ctx, "campaign-1"))
+
+	tracker.RecordUsage("campaign-1", "gpt-test", 800, 200)
+	tracker.RecordUsage("campaign-2", "gpt-test", 800, 200)
+
+	assert.ErrorIs(t, guard.Check(ctx, "campaign-1"), service.ErrCampaignPaused)
+	assert.True(t, campaigns["campaign-1"].IsPaused())
+	assert.Equal(t, []string{ws.EventCampaignPaused}, broadcaster.types())
+
+	// 已暂停的战役直接拒绝，不再重复推送
+	assert.ErrorIs(t, guard.Check(ctx, "campaign-1"), service.ErrCampaignPaused)
+	assert.Len(t, broadcaster.types(), 1)
+
+	// 战役设置覆盖默认预算
+	assert.NoError(t, guard.Check(ctx, "campaign-2"))
+
+	// 读不到战役时不阻止对话
+	assert.NoError(t, guard.Check(ctx, "campaign-3"))
+
+	report, err := guard.Report(ctx, "campaign-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), report.Usage.TotalTokens)
+	assert.Equal(t, int64(1000), report.Budget.MaxTokens)
+	assert.True(t, report.Paused)
+}
+
+// TestChatService_BudgetExceeded 测试暂停的战役拒绝新消息
+func TestChatService_BudgetExceeded(t *testing.T) {
+	chatService, _, mockLLMClient, _ := setupToolLoop()
+	campaigns := stubCampaigns{
+		"campaign-1": campaignWithSettings("campaign-1", map[string]interface{}{
+			models.SettingLLMCostBudget: 0.01,
+		}),
+	}
+	tracker := monitor.NewUsageTracker(map[string]monitor.ModelPrice{"gpt-test": {Prompt: 0.01, Completion: 0.03}})
+	chatService.SetBudgetGuard(service.NewBudgetGuard(tracker, campaigns, service.UsageBudget{}))
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("欢迎来到冒险"), nil)
+
+	ctx := context.Background()
+	_, err := chatService.SendMessage(ctx, "campaign-1", &service.SendMessageRequest{Content: "你好", PlayerID: "player-1"})
+	require.NoError(t, err)
+
+	tracker.RecordUsage("campaign-1", "gpt-test", 1000, 0)
+
+	_, err = chatService.SendMessage(ctx, "campaign-1", &service.SendMessageRequest{Content: "继续", PlayerID: "player-1"})
+	assert.ErrorIs(t, err, service.ErrCampaignPaused)
+	assert.True(t, campaigns["campaign-1"].IsPaused())
+	mockLLMClient.AssertNumberOfCalls(t, "Chat", 1)
+}
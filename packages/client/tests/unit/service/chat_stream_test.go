@@ -0,0 +1,121 @@
+// Package service_test 提供 Service 层单元测试
+package service_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dnd-mcp/client/internal/llm"
+	"github.com/dnd-mcp/client/internal/service"
+	"github.com/dnd-mcp/client/internal/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingBroadcaster 记录推送的会话事件
+type recordingBroadcaster struct {
+	mu     sync.Mutex
+	events []ws.Event
+}
+
+func (b *recordingBroadcaster) BroadcastToSession(sessionID string, event ws.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+}
+
+func (b *recordingBroadcaster) types() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	types := make([]string, len(b.events))
+	for i, event := range b.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// streamDeltas 让 ChatStream 的 Mock 按片段回调内容
+func streamDeltas(deltas ...string) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		handler := args.Get(2).(llm.StreamHandler)
+		for _, delta := range deltas {
+			handler(llm.MessageDelta{Content: delta})
+		}
+	}
+}
+
+// TestChatService_Stream 测试流式回复推送增量、工具调用和最终消息事件
+func TestChatService_Stream(t *testing.T) {
+	chatService, _, mockLLMClient, _ := setupToolLoop()
+	broadcaster := &recordingBroadcaster{}
+	chatService.SetBroadcaster(broadcaster)
+
+	mockLLMClient.On("ChatStream", mock.Anything, mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-1", "roll_dice", `{"formula": "1d20+5"}`),
+	), nil).Once()
+	mockLLMClient.On("ChatStream", mock.Anything, mock.Anything, mock.Anything).
+		Run(streamDeltas("你投出了", " 18，", "命中！")).
+		Return(stopResponse("你投出了 18，命中！"), nil).Once()
+
+	message, err := chatService.SendMessage(context.Background(), "campaign-1", &service.SendMessageRequest{
+		Content:  "我攻击哥布林",
+		PlayerID: "player-1",
+		Stream:   true,
+	})
+	require.NoError(t, err)
+	mockLLMClient.AssertNotCalled(t, "Chat", mock.Anything, mock.Anything)
+
+	assert.Equal(t, []string{
+		ws.EventToolCallStarted,
+		ws.EventToolCallFinished,
+		ws.EventMessageDelta,
+		ws.EventMessageDelta,
+		ws.EventMessageDelta,
+		ws.EventNewMessage,
+	}, broadcaster.types())
+
+	events := broadcaster.events
+	assert.Equal(t, "campaign-1", events[0].SessionID)
+	assert.Equal(t, "call-1", events[0].Data["tool_call_id"])
+	assert.Equal(t, "roll_dice", events[1].Data["name"])
+	assert.NotNil(t, events[1].Data["result"])
+	assert.NotContains(t, events[1].Data, "error")
+
+	// 增量内容与最终消息使用同一个消息 ID
+	var streamed strings.Builder
+	for _, event := range events[2:5] {
+		assert.Equal(t, message.ID, event.Data["message_id"])
+		streamed.WriteString(event.Data["delta"].(string))
+	}
+	assert.Equal(t, message.Content, streamed.String())
+	assert.Equal(t, message.ID, events[5].Data["message_id"])
+	assert.Equal(t, "你投出了 18，命中！", events[5].Data["content"])
+}
+
+// TestChatService_NoStream 测试非流式请求只推送工具调用和最终消息
+func TestChatService_NoStream(t *testing.T) {
+	chatService, _, mockLLMClient, _ := setupToolLoop()
+	broadcaster := &recordingBroadcaster{}
+	chatService.SetBroadcaster(broadcaster)
+
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-1", "cast_spell", `{}`),
+	), nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("法术失败"), nil).Once()
+
+	_, err := chatService.SendMessage(context.Background(), "campaign-1", &service.SendMessageRequest{
+		Content:  "施放火球术",
+		PlayerID: "player-1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		ws.EventToolCallStarted,
+		ws.EventToolCallFinished,
+		ws.EventNewMessage,
+	}, broadcaster.types())
+	assert.Contains(t, broadcaster.events[1].Data["error"], "cast_spell")
+}
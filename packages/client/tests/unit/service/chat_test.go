@@ -26,6 +26,14 @@ func (m *MockLLMClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.Ch
 	return args.Get(0).(*llm.ChatResponse), args.Error(1)
 }
 
+func (m *MockLLMClient) ChatStream(ctx context.Context, req *llm.ChatRequest, handler llm.StreamHandler) (*llm.ChatResponse, error) {
+	args := m.Called(ctx, req, handler)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*llm.ChatResponse), args.Error(1)
+}
+
 // MockMCPClient Mock MCP 客户端接口
 type MockMCPClient struct {
 	mock.Mock
@@ -221,7 +229,18 @@ func TestChatService_SendMessage_WithToolCalls(t *testing.T) {
 				FinishReason: "tool_calls",
 			},
 		},
-	}, nil)
+	}, nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.AnythingOfType("*llm.ChatRequest")).Return(&llm.ChatResponse{
+		Choices: []llm.Choice{
+			{
+				Message: llm.Message{
+					Role:    "assistant",
+					Content: "你投出了 18",
+				},
+				FinishReason: "stop",
+			},
+		},
+	}, nil).Once()
 
 	// 设置 MCP 工具调用 Mock
 	mockMCPClient.On("CallTool", mock.Anything, mock.Anything, "roll_dice", mock.Anything).Return(map[string]interface{}{
@@ -242,6 +261,7 @@ func TestChatService_SendMessage_WithToolCalls(t *testing.T) {
 	// 断言
 	assert.NoError(t, err)
 	assert.NotNil(t, message)
+	assert.Equal(t, "你投出了 18", message.Content)
 
 	mockLLMClient.AssertExpectations(t)
 }
@@ -0,0 +1,98 @@
+// Package service_test 提供 Service 层单元测试
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dnd-mcp/client/internal/llm"
+	"github.com/dnd-mcp/client/internal/models"
+	"github.com/dnd-mcp/client/internal/service"
+	"github.com/dnd-mcp/client/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSessions 按 ID 返回预设会话
+type stubSessions map[string]*models.Session
+
+func (s stubSessions) Get(ctx context.Context, id string) (*models.Session, error) {
+	session, ok := s[id]
+	if !ok {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	return session, nil
+}
+
+func campaignWithSettings(id string, settings map[string]interface{}) *models.Session {
+	session := models.NewSession(id, "dm-1", "")
+	session.ID = id
+	session.UpdateSettings(settings)
+	return session
+}
+
+// setupLLMSelection 默认 provider 为 openai，战役可以选择本地 provider
+func setupLLMSelection(sessions stubSessions) (*service.ChatService, *MockLLMClient, *MockLLMClient) {
+	chatService, _, defaultLLM, _ := setupToolLoop()
+	localLLM := new(MockLLMClient)
+
+	registry := llm.NewRegistry()
+	registry.Register("local", func(cfg *config.LLMConfig) (llm.LLMClient, error) {
+		return localLLM, nil
+	})
+	pool := llm.NewClientPool(registry, &config.LLMConfig{
+		Provider:  "openai",
+		Providers: map[string]config.LLMProviderConfig{"local": {}},
+	})
+	chatService.SetLLMPool(pool, sessions)
+	return chatService, defaultLLM, localLLM
+}
+
+// TestChatService_CampaignLLM 测试战役设置选择 provider 和模型
+func TestChatService_CampaignLLM(t *testing.T) {
+	chatService, defaultLLM, localLLM := setupLLMSelection(stubSessions{
+		"campaign-1": campaignWithSettings("campaign-1", map[string]interface{}{
+			models.SettingLLMProvider: "local",
+			models.SettingLLMModel:    "llama-test",
+		}),
+		"campaign-2": campaignWithSettings("campaign-2", map[string]interface{}{
+			models.SettingLLMModel: "gpt-test",
+		}),
+	})
+	localLLM.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("本地模型回复"), nil)
+	defaultLLM.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("默认模型回复"), nil)
+
+	ctx := context.Background()
+	message, err := chatService.SendMessage(ctx, "campaign-1", &service.SendMessageRequest{Content: "你好", PlayerID: "player-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "本地模型回复", message.Content)
+	assert.Equal(t, "llama-test", chatRequest(localLLM, 0).Model)
+
+	// 只设置模型时使用默认 provider
+	message, err = chatService.SendMessage(ctx, "campaign-2", &service.SendMessageRequest{Content: "你好", PlayerID: "player-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "默认模型回复", message.Content)
+	assert.Equal(t, "gpt-test", chatRequest(defaultLLM, 0).Model)
+
+	// 读不到战役时使用默认 provider 和模型
+	_, err = chatService.SendMessage(ctx, "campaign-3", &service.SendMessageRequest{Content: "你好", PlayerID: "player-1"})
+	require.NoError(t, err)
+	assert.Empty(t, chatRequest(defaultLLM, 1).Model)
+	localLLM.AssertNumberOfCalls(t, "Chat", 1)
+}
+
+// TestChatService_CampaignLLM_UnknownProvider 测试战役选择了未配置的 provider
+func TestChatService_CampaignLLM_UnknownProvider(t *testing.T) {
+	chatService, defaultLLM, _ := setupLLMSelection(stubSessions{
+		"campaign-1": campaignWithSettings("campaign-1", map[string]interface{}{
+			models.SettingLLMProvider: "anthropic",
+		}),
+	})
+
+	_, err := chatService.SendMessage(context.Background(), "campaign-1", &service.SendMessageRequest{Content: "你好", PlayerID: "player-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anthropic")
+	defaultLLM.AssertNotCalled(t, "Chat", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,74 @@
+// Package service_test 提供 Service 层单元测试
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnd-mcp/client/internal/mcp"
+	"github.com/dnd-mcp/client/internal/server"
+	"github.com/dnd-mcp/client/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConvertTool 测试 MCP 工具定义转换为 LLM function 定义
+func TestConvertTool(t *testing.T) {
+	tool := service.ConvertTool(mcp.Tool{
+		Name:        "roll_dice",
+		Description: "Roll dice",
+		InputSchema: mcp.InputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"formula": map[string]interface{}{"type": "string"},
+			},
+			Required: []string{"formula"},
+		},
+	})
+
+	assert.Equal(t, "function", tool.Type)
+	assert.Equal(t, "roll_dice", tool.Function.Name)
+	assert.Equal(t, "Roll dice", tool.Function.Description)
+	assert.Equal(t, "object", tool.Function.Parameters["type"])
+	assert.Equal(t, []string{"formula"}, tool.Function.Parameters["required"])
+
+	// 没有参数的工具也带上空的 properties
+	empty := service.ConvertTool(mcp.Tool{Name: "list_campaigns"})
+	assert.Equal(t, "object", empty.Function.Parameters["type"])
+	assert.Equal(t, map[string]interface{}{}, empty.Function.Parameters["properties"])
+	assert.NotContains(t, empty.Function.Parameters, "required")
+}
+
+// TestToolCatalog_Tools 测试工具目录缓存
+func TestToolCatalog_Tools(t *testing.T) {
+	mockServerClient := server.NewMockClient()
+	catalog := service.NewToolCatalog(mockServerClient, 0)
+	ctx := context.Background()
+
+	tools, err := catalog.Tools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tools, 2)
+	assert.True(t, catalog.Has("roll_dice"))
+	assert.False(t, catalog.Has("cast_spell"))
+
+	// 缓存未过期时不重新获取
+	mockServerClient.Tools = mockServerClient.Tools[:1]
+	tools, _ = catalog.Tools(ctx)
+	assert.Len(t, tools, 2)
+
+	// 失效后重新获取
+	catalog.Invalidate()
+	tools, _ = catalog.Tools(ctx)
+	assert.Len(t, tools, 1)
+
+	// 获取失败时继续使用旧目录
+	catalog.Invalidate()
+	mockServerClient.SetReturnError(true)
+	tools, err = catalog.Tools(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tools, 1)
+
+	// 从未获取成功时返回错误
+	_, err = service.NewToolCatalog(mockServerClient, 0).Tools(ctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,256 @@
+// Package service_test 提供 Service 层单元测试
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dnd-mcp/client/internal/llm"
+	"github.com/dnd-mcp/client/internal/mcp"
+	"github.com/dnd-mcp/client/internal/server"
+	"github.com/dnd-mcp/client/internal/service"
+	"github.com/dnd-mcp/client/internal/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// toolCallResponse 构造请求工具调用的 LLM 响应
+func toolCallResponse(calls ...llm.ToolCall) *llm.ChatResponse {
+	return &llm.ChatResponse{
+		Choices: []llm.Choice{
+			{
+				Message:      llm.Message{Role: "assistant", ToolCalls: calls},
+				FinishReason: "tool_calls",
+			},
+		},
+	}
+}
+
+// stopResponse 构造最终回复的 LLM 响应
+func stopResponse(content string) *llm.ChatResponse {
+	return &llm.ChatResponse{
+		Choices: []llm.Choice{
+			{
+				Message:      llm.Message{Role: "assistant", Content: content},
+				FinishReason: "stop",
+			},
+		},
+	}
+}
+
+func toolCall(id, name, arguments string) llm.ToolCall {
+	return llm.ToolCall{
+		ID:       id,
+		Type:     "function",
+		Function: llm.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+// chatRequest 返回第 i 次 LLM 调用的请求
+func chatRequest(m *MockLLMClient, i int) *llm.ChatRequest {
+	return m.Calls[i].Arguments.Get(1).(*llm.ChatRequest)
+}
+
+func setupToolLoop() (*service.ChatService, *server.MockClient, *MockLLMClient, *service.ToolCatalog) {
+	mockServerClient := server.NewMockClient()
+	mockLLMClient := new(MockLLMClient)
+	catalog := service.NewToolCatalog(mockServerClient, 0)
+
+	chatService := service.NewChatService(mockServerClient, mockLLMClient, new(MockMCPClient), service.NewContextBuilder(mockServerClient, nil))
+	chatService.SetToolCatalog(catalog)
+	return chatService, mockServerClient, mockLLMClient, catalog
+}
+
+// TestChatService_ToolLoop_MultipleRounds 测试多轮工具调用
+func TestChatService_ToolLoop_MultipleRounds(t *testing.T) {
+	chatService, mockServerClient, mockLLMClient, _ := setupToolLoop()
+
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-1", "roll_dice", `{"formula": "1d20+5"}`),
+		toolCall("call-2", "resolve_attack", `{"attacker": "char-1", "target": "goblin-1"}`),
+	), nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-3", "roll_dice", `{"formula": "1d8+3"}`),
+	), nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("哥布林倒下了"), nil).Once()
+
+	message, err := chatService.SendMessage(context.Background(), "campaign-1", &service.SendMessageRequest{
+		Content:  "我攻击哥布林",
+		PlayerID: "player-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "哥布林倒下了", message.Content)
+	mockLLMClient.AssertExpectations(t)
+
+	// 工具目录提供给 LLM
+	first := chatRequest(mockLLMClient, 0)
+	require.Len(t, first.Tools, 2)
+	assert.Equal(t, "roll_dice", first.Tools[0].Function.Name)
+
+	// 后续请求带上之前的工具调用和结果
+	last := chatRequest(mockLLMClient, 2)
+	tail := last.Messages[len(last.Messages)-2:]
+	assert.Equal(t, "assistant", tail[0].Role)
+	assert.Equal(t, "call-3", tail[0].ToolCalls[0].ID)
+	assert.Equal(t, "tool", tail[1].Role)
+	assert.Equal(t, "call-3", tail[1].ToolCallID)
+
+	// 每条中间消息都保存到 Server
+	saved := mockServerClient.GetMessages()
+	roles := make([]server.MessageRole, len(saved))
+	for i, msg := range saved {
+		roles[i] = msg.Role
+	}
+	assert.Equal(t, []server.MessageRole{
+		server.MessageRoleUser,
+		server.MessageRoleAssistant,
+		server.MessageRoleTool,
+		server.MessageRoleTool,
+		server.MessageRoleAssistant,
+		server.MessageRoleTool,
+		server.MessageRoleAssistant,
+	}, roles)
+	assert.Len(t, saved[1].ToolCalls, 2)
+	assert.Equal(t, "call-1", saved[2].ToolCallID)
+	assert.Equal(t, "call-2", saved[3].ToolCallID)
+
+	// 工具结果为 JSON
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(saved[2].Content), &result))
+	assert.Equal(t, true, result["success"])
+}
+
+// TestChatService_ToolLoop_Sequential 测试同一批工具调用按模型给出的顺序依次执行
+func TestChatService_ToolLoop_Sequential(t *testing.T) {
+	chatService, _, mockLLMClient, _ := setupToolLoop()
+	broadcaster := &recordingBroadcaster{}
+	chatService.SetBroadcaster(broadcaster)
+
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-1", "roll_dice", `{"formula": "1d20+5"}`),
+		toolCall("call-2", "resolve_attack", `{"attacker": "char-1", "target": "goblin-1"}`),
+		toolCall("call-3", "roll_dice", `{"formula": "1d8+3"}`),
+	), nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("哥布林倒下了"), nil).Once()
+
+	_, err := chatService.SendMessage(context.Background(), "campaign-1", &service.SendMessageRequest{
+		Content:  "我攻击哥布林",
+		PlayerID: "player-1",
+	})
+	require.NoError(t, err)
+
+	// 每个调用结束后才开始下一个调用
+	var order []string
+	for _, event := range broadcaster.events[:6] {
+		order = append(order, event.Type+":"+event.Data["tool_call_id"].(string))
+	}
+	assert.Equal(t, []string{
+		ws.EventToolCallStarted + ":call-1",
+		ws.EventToolCallFinished + ":call-1",
+		ws.EventToolCallStarted + ":call-2",
+		ws.EventToolCallFinished + ":call-2",
+		ws.EventToolCallStarted + ":call-3",
+		ws.EventToolCallFinished + ":call-3",
+	}, order)
+}
+
+// TestChatService_ToolLoop_Budget 测试单轮工具调用预算
+func TestChatService_ToolLoop_Budget(t *testing.T) {
+	chatService, mockServerClient, mockLLMClient, _ := setupToolLoop()
+	chatService.SetToolLoopConfig(service.ToolLoopConfig{MaxToolCalls: 1})
+
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-1", "roll_dice", `{"formula": "1d20"}`),
+		toolCall("call-2", "roll_dice", `{"formula": "1d20"}`),
+	), nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("两次投掷"), nil).Once()
+
+	_, err := chatService.SendMessage(context.Background(), "campaign-1", &service.SendMessageRequest{
+		Content:  "投两次 d20",
+		PlayerID: "player-1",
+	})
+	require.NoError(t, err)
+
+	// 超出预算的调用得到错误结果，预算用完后不再提供工具
+	saved := mockServerClient.GetMessages()
+	require.Len(t, saved, 5)
+	assert.Contains(t, saved[2].Content, "success")
+	assert.Equal(t, "call-2", saved[3].ToolCallID)
+	assert.Contains(t, saved[3].Content, "error")
+	assert.Empty(t, chatRequest(mockLLMClient, 1).Tools)
+}
+
+// TestChatService_ToolLoop_MaxIterations 测试工具调用轮数上限
+func TestChatService_ToolLoop_MaxIterations(t *testing.T) {
+	chatService, _, mockLLMClient, _ := setupToolLoop()
+	chatService.SetToolLoopConfig(service.ToolLoopConfig{MaxIterations: 2})
+
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-1", "roll_dice", `{"formula": "1d20"}`),
+	), nil)
+
+	message, err := chatService.SendMessage(context.Background(), "campaign-1", &service.SendMessageRequest{
+		Content:  "一直投骰子",
+		PlayerID: "player-1",
+	})
+	assert.Error(t, err)
+	assert.Nil(t, message)
+	mockLLMClient.AssertNumberOfCalls(t, "Chat", 2)
+	assert.Empty(t, chatRequest(mockLLMClient, 1).Tools, "最后一轮不提供工具")
+}
+
+// TestChatService_ToolLoop_UnknownTool 测试调用目录外的工具后重新获取目录
+func TestChatService_ToolLoop_UnknownTool(t *testing.T) {
+	chatService, mockServerClient, mockLLMClient, _ := setupToolLoop()
+
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(toolCallResponse(
+		toolCall("call-1", "cast_spell", `{"spell": "fireball"}`),
+	), nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("法术失败"), nil).Once()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("火球术!"), nil).Once()
+
+	ctx := context.Background()
+	req := &service.SendMessageRequest{Content: "施放火球术", PlayerID: "player-1"}
+	_, err := chatService.SendMessage(ctx, "campaign-1", req)
+	require.NoError(t, err)
+
+	saved := mockServerClient.GetMessages()
+	assert.Contains(t, saved[2].Content, "cast_spell")
+
+	// Server 新增了工具，下一轮对话使用新目录
+	mockServerClient.Tools = append(mockServerClient.Tools, mcp.Tool{
+		Name:        "cast_spell",
+		InputSchema: mcp.InputSchema{Type: "object"},
+	})
+	_, err = chatService.SendMessage(ctx, "campaign-1", req)
+	require.NoError(t, err)
+	assert.Len(t, chatRequest(mockLLMClient, 2).Tools, 3)
+}
+
+// TestContextBuilder_PairsToolMessages 测试历史中的工具调用与结果配对
+func TestContextBuilder_PairsToolMessages(t *testing.T) {
+	mockServerClient := server.NewMockClient()
+	mockServerClient.Messages = []server.Message{
+		{CampaignID: "campaign-1", Role: server.MessageRoleTool, Content: `{"total":3}`, ToolCallID: "call-0"},
+		{CampaignID: "campaign-1", Role: server.MessageRoleAssistant, ToolCalls: []server.ToolCall{
+			{ID: "call-1", Name: "roll_dice", Arguments: map[string]interface{}{"formula": "1d20"}},
+			{ID: "call-2", Name: "roll_dice", Arguments: map[string]interface{}{"formula": "1d20"}},
+		}},
+		{CampaignID: "campaign-1", Role: server.MessageRoleTool, Content: `{"total":18}`, ToolCallID: "call-1"},
+		{CampaignID: "campaign-1", Role: server.MessageRoleAssistant, Content: "你投出了 18"},
+	}
+
+	messages, err := service.NewContextBuilder(mockServerClient, nil).BuildContext(context.Background(), "campaign-1", "继续")
+	require.NoError(t, err)
+
+	// system + 3 条历史 + user，没有结果的调用和没有调用的结果被去掉
+	require.Len(t, messages, 5)
+	assert.Equal(t, "assistant", messages[1].Role)
+	require.Len(t, messages[1].ToolCalls, 1)
+	assert.Equal(t, "call-1", messages[1].ToolCalls[0].ID)
+	assert.JSONEq(t, `{"formula":"1d20"}`, messages[1].ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "tool", messages[2].Role)
+	assert.Equal(t, "call-1", messages[2].ToolCallID)
+}
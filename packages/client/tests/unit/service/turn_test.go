@@ -0,0 +1,303 @@
+// Package service_test 提供 Service 层单元测试
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/client/internal/models"
+	"github.com/dnd-mcp/client/internal/server"
+	"github.com/dnd-mcp/client/internal/service"
+	"github.com/dnd-mcp/client/internal/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingRunner 记录每轮的消息，收到 release 后才结束一轮
+type blockingRunner struct {
+	mu      sync.Mutex
+	batches [][]string
+	running int
+	maxRun  int
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingRunner() *blockingRunner {
+	return &blockingRunner{
+		started: make(chan struct{}, 16),
+		release: make(chan struct{}),
+	}
+}
+
+func (r *blockingRunner) SendBatch(ctx context.Context, campaignID string, reqs []*service.SendMessageRequest) (*models.Message, error) {
+	contents := make([]string, len(reqs))
+	for i, req := range reqs {
+		contents[i] = req.Content
+	}
+	r.mu.Lock()
+	r.batches = append(r.batches, contents)
+	r.running++
+	r.maxRun = max(r.maxRun, r.running)
+	r.mu.Unlock()
+
+	r.started <- struct{}{}
+	<-r.release
+
+	r.mu.Lock()
+	r.running--
+	r.mu.Unlock()
+	return models.NewAssistantMessage(campaignID, "DM 回复"), nil
+}
+
+func (r *blockingRunner) recorded() ([][]string, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.batches, r.maxRun
+}
+
+// sendAsync 在后台发送消息，返回结果通道
+func sendAsync(coordinator *service.TurnCoordinator, ctx context.Context, campaignID, playerID, content string) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := coordinator.SendMessage(ctx, campaignID, &service.SendMessageRequest{Content: content, PlayerID: playerID})
+		result <- err
+	}()
+	return result
+}
+
+// waitQueued 等待战役队列达到指定长度
+func waitQueued(t *testing.T, coordinator *service.TurnCoordinator, campaignID string, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return coordinator.QueueLength(campaignID) == n
+	}, time.Second, time.Millisecond)
+}
+
+// TestTurnCoordinator_SerializesAndBatches 测试同一战役每次只运行一轮，排队的消息合并为下一轮
+func TestTurnCoordinator_SerializesAndBatches(t *testing.T) {
+	runner := newBlockingRunner()
+	coordinator := service.NewTurnCoordinator(runner, service.TurnConfig{MaxBatch: 4})
+	broadcaster := &recordingBroadcaster{}
+	coordinator.SetBroadcaster(broadcaster)
+	ctx := context.Background()
+
+	first := sendAsync(coordinator, ctx, "campaign-1", "player-1", "我打开门")
+	<-runner.started
+
+	second := sendAsync(coordinator, ctx, "campaign-1", "player-2", "我举起盾牌")
+	waitQueued(t, coordinator, "campaign-1", 1)
+	third := sendAsync(coordinator, ctx, "campaign-1", "player-3", "我点燃火把")
+	waitQueued(t, coordinator, "campaign-1", 2)
+
+	// 其他战役不受影响
+	other := sendAsync(coordinator, ctx, "campaign-2", "player-4", "我休息")
+	<-runner.started
+
+	runner.release <- struct{}{}
+	runner.release <- struct{}{}
+	<-runner.started
+	runner.release <- struct{}{}
+
+	for _, result := range []<-chan error{first, second, third, other} {
+		require.NoError(t, <-result)
+	}
+
+	batches, maxRun := runner.recorded()
+	assert.Len(t, batches, 3)
+	assert.Contains(t, batches, []string{"我举起盾牌", "我点燃火把"})
+	assert.Equal(t, 2, maxRun, "只有不同战役的回合同时运行")
+	assert.Equal(t, 0, coordinator.QueueLength("campaign-1"))
+
+	types := broadcaster.types()
+	assert.Contains(t, types, ws.EventTurnQueued)
+	assert.Contains(t, types, ws.EventTurnStarted)
+}
+
+// TestTurnCoordinator_BatchWindow 测试合并窗口内的消息作为一轮
+func TestTurnCoordinator_BatchWindow(t *testing.T) {
+	runner := newBlockingRunner()
+	close(runner.release)
+	coordinator := service.NewTurnCoordinator(runner, service.TurnConfig{BatchWindow: 100 * time.Millisecond, MaxBatch: 2})
+	ctx := context.Background()
+
+	results := []<-chan error{
+		sendAsync(coordinator, ctx, "campaign-1", "player-1", "一"),
+	}
+	waitQueued(t, coordinator, "campaign-1", 1)
+	results = append(results, sendAsync(coordinator, ctx, "campaign-1", "player-2", "二"))
+	waitQueued(t, coordinator, "campaign-1", 2)
+	results = append(results, sendAsync(coordinator, ctx, "campaign-1", "player-3", "三"))
+	waitQueued(t, coordinator, "campaign-1", 3)
+
+	for _, result := range results {
+		require.NoError(t, <-result)
+	}
+	batches, _ := runner.recorded()
+	assert.Equal(t, [][]string{{"一", "二"}, {"三"}}, batches, "超过 MaxBatch 的消息留到下一轮")
+}
+
+// TestTurnCoordinator_QueueFullAndCancel 测试队列已满和取消排队
+func TestTurnCoordinator_QueueFullAndCancel(t *testing.T) {
+	runner := newBlockingRunner()
+	coordinator := service.NewTurnCoordinator(runner, service.TurnConfig{MaxBatch: 1, MaxQueue: 1})
+	ctx := context.Background()
+
+	first := sendAsync(coordinator, ctx, "campaign-1", "player-1", "一")
+	<-runner.started
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	queued := sendAsync(coordinator, cancelCtx, "campaign-1", "player-2", "二")
+	waitQueued(t, coordinator, "campaign-1", 1)
+
+	_, err := coordinator.SendMessage(ctx, "campaign-1", &service.SendMessageRequest{Content: "三", PlayerID: "player-3"})
+	assert.ErrorIs(t, err, service.ErrTurnQueueFull)
+
+	cancel()
+	assert.ErrorIs(t, <-queued, context.Canceled)
+	assert.Equal(t, 0, coordinator.QueueLength("campaign-1"))
+
+	runner.release <- struct{}{}
+	require.NoError(t, <-first)
+	batches, _ := runner.recorded()
+	assert.Equal(t, [][]string{{"一"}}, batches, "取消的消息不再处理")
+}
+
+// stubCombat 返回预设的战斗状态
+type stubCombat struct {
+	raw *server.RawContext
+	err error
+}
+
+func (s *stubCombat) GetRawContext(ctx context.Context, campaignID string) (*server.RawContext, error) {
+	return s.raw, s.err
+}
+
+// TestTurnCoordinator_InitiativeMode 测试先攻模式下战斗中只接受当前行动者的玩家
+func TestTurnCoordinator_InitiativeMode(t *testing.T) {
+	runner := newBlockingRunner()
+	close(runner.release)
+	combat := &stubCombat{raw: &server.RawContext{
+		Characters: []*server.Character{
+			{ID: "char-1", Name: "Aldric", PlayerID: "player-1"},
+			{ID: "char-2", Name: "Lyra", PlayerID: "player-2"},
+			{ID: "goblin-1", Name: "哥布林"},
+		},
+		Combat: &server.Combat{
+			Status:    "active",
+			TurnIndex: 1,
+			Participants: []server.CombatParticipant{
+				{CharacterID: "char-2"}, {CharacterID: "char-1"}, {CharacterID: "goblin-1"},
+			},
+		},
+	}}
+	coordinator := service.NewTurnCoordinator(runner, service.TurnConfig{})
+	coordinator.SetCombatReader(combat)
+	coordinator.SetSessionGetter(stubSessions{
+		"campaign-1": campaignWithSettings("campaign-1", map[string]interface{}{
+			models.SettingTurnMode: models.TurnModeInitiative,
+		}),
+	})
+	ctx := context.Background()
+	send := func(campaignID, playerID string) error {
+		_, err := coordinator.SendMessage(ctx, campaignID, &service.SendMessageRequest{Content: "我攻击", PlayerID: playerID})
+		return err
+	}
+
+	// 轮到 char-1（player-1）
+	err := send("campaign-1", "player-2")
+	assert.ErrorIs(t, err, service.ErrNotYourTurn)
+	assert.Contains(t, err.Error(), "Aldric")
+	assert.NoError(t, send("campaign-1", "player-1"))
+
+	// 未启用先攻模式的战役不检查
+	assert.NoError(t, send("campaign-2", "player-2"))
+
+	// 轮到怪物时所有玩家都可以发言
+	combat.raw.Combat.TurnIndex = 2
+	assert.NoError(t, send("campaign-1", "player-2"))
+
+	// 战斗结束或读不到战斗状态时不检查
+	combat.raw.Combat.TurnIndex = 1
+	combat.raw.Combat.Status = "finished"
+	assert.NoError(t, send("campaign-1", "player-2"))
+	combat.err = errors.New("server unavailable")
+	assert.NoError(t, send("campaign-1", "player-2"))
+}
+
+// TestTurnCoordinator_InitiativeRecheckedWhenDequeued 测试排队期间回合轮换后，取出的消息重新检查先攻
+func TestTurnCoordinator_InitiativeRecheckedWhenDequeued(t *testing.T) {
+	runner := newBlockingRunner()
+	combat := &stubCombat{raw: &server.RawContext{
+		Characters: []*server.Character{
+			{ID: "char-1", Name: "Aldric", PlayerID: "player-1"},
+			{ID: "char-2", Name: "Lyra", PlayerID: "player-2"},
+		},
+		Combat: &server.Combat{
+			Status:       "active",
+			Participants: []server.CombatParticipant{{CharacterID: "char-1"}, {CharacterID: "char-2"}},
+		},
+	}}
+	coordinator := service.NewTurnCoordinator(runner, service.TurnConfig{MaxBatch: 1, InitiativeMode: true})
+	coordinator.SetCombatReader(combat)
+	broadcaster := &recordingBroadcaster{}
+	coordinator.SetBroadcaster(broadcaster)
+	ctx := context.Background()
+
+	first := sendAsync(coordinator, ctx, "campaign-1", "player-1", "我攻击")
+	<-runner.started
+	second := sendAsync(coordinator, ctx, "campaign-1", "player-1", "我再攻击")
+	waitQueued(t, coordinator, "campaign-1", 1)
+	third := sendAsync(coordinator, ctx, "campaign-1", "player-1", "我后撤")
+	waitQueued(t, coordinator, "campaign-1", 2)
+
+	// 第一轮结束了 Aldric 的回合
+	combat.raw.Combat.TurnIndex = 1
+	runner.release <- struct{}{}
+
+	require.NoError(t, <-first)
+	assert.ErrorIs(t, <-second, service.ErrNotYourTurn)
+	assert.ErrorIs(t, <-third, service.ErrNotYourTurn)
+	batches, _ := runner.recorded()
+	assert.Equal(t, [][]string{{"我攻击"}}, batches)
+
+	// 取出消息后推送剩余消息的新排队位置
+	var positions []interface{}
+	broadcaster.mu.Lock()
+	for _, event := range broadcaster.events {
+		if event.Type == ws.EventTurnQueued {
+			positions = append(positions, event.Data["position"])
+		}
+	}
+	broadcaster.mu.Unlock()
+	assert.Equal(t, []interface{}{1, 1, 2, 1}, positions, "三条消息依次入队，取出第二条后第三条排到第 1 位")
+}
+
+// TestChatService_SendBatch 测试合并多名玩家的消息为一轮对话
+func TestChatService_SendBatch(t *testing.T) {
+	chatService, mockServerClient, mockLLMClient, _ := setupToolLoop()
+	mockLLMClient.On("Chat", mock.Anything, mock.Anything).Return(stopResponse("哥布林四散而逃"), nil)
+
+	message, err := chatService.SendBatch(context.Background(), "campaign-1", []*service.SendMessageRequest{
+		{Content: "我冲向哥布林", PlayerID: "player-1", PlayerName: "Alice"},
+		{Content: "我施放火球术", PlayerID: "player-2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "哥布林四散而逃", message.Content)
+	mockLLMClient.AssertNumberOfCalls(t, "Chat", 1)
+
+	messages := chatRequest(mockLLMClient, 0).Messages
+	assert.Equal(t, "Alice: 我冲向哥布林\nplayer-2: 我施放火球术", messages[len(messages)-1].Content)
+
+	var players []string
+	for _, msg := range mockServerClient.Messages {
+		if msg.Role == server.MessageRoleUser {
+			players = append(players, msg.PlayerID)
+		}
+	}
+	assert.Equal(t, []string{"player-1", "player-2"}, players, "每名玩家的消息单独保存")
+}
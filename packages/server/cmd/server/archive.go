@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dnd-mcp/server/internal/service"
+)
+
+// runArchiveCommand runs the export or import subcommand:
+//
+//	server export -campaign <id> [-out campaign.zip]
+//	server import -in campaign.zip [-dm <user id>] [-name <name>]
+func runArchiveCommand(ctx context.Context, archiveService *service.ArchiveService, args []string) error {
+	switch args[0] {
+	case "export":
+		flags := flag.NewFlagSet("export", flag.ContinueOnError)
+		campaignID := flags.String("campaign", "", "ID of the campaign to export (required)")
+		out := flags.String("out", "", "Archive file to write (default: campaign-<id>.zip)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *campaignID == "" {
+			return fmt.Errorf("export: -campaign is required")
+		}
+		if *out == "" {
+			*out = fmt.Sprintf("campaign-%s.zip", *campaignID)
+		}
+
+		resp, err := archiveService.ExportCampaign(ctx, *campaignID)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*out, resp.Archive, 0o644); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		fmt.Printf("Exported campaign '%s' to %s\n", resp.Manifest.CampaignName, *out)
+		return nil
+
+	case "import":
+		flags := flag.NewFlagSet("import", flag.ContinueOnError)
+		in := flags.String("in", "", "Archive file to import (required)")
+		dmID := flags.String("dm", "", "DM of the imported campaign (default: the archive's DM)")
+		name := flags.String("name", "", "Name of the imported campaign (default: the archive's name)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *in == "" {
+			return fmt.Errorf("import: -in is required")
+		}
+
+		data, err := os.ReadFile(*in)
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		resp, err := archiveService.ImportCampaign(ctx, &service.ImportCampaignRequest{
+			Archive: data,
+			DMID:    *dmID,
+			Name:    *name,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported campaign '%s' with ID: %s\n", resp.Campaign.Name, resp.Campaign.ID)
+		return nil
+	}
+
+	return fmt.Errorf("unknown command %q (available: export, import)", args[0])
+}
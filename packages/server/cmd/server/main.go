@@ -11,13 +11,18 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/dnd-mcp/server/internal/api/events"
+	"github.com/dnd-mcp/server/internal/api/prompts"
+	"github.com/dnd-mcp/server/internal/api/resources"
 	"github.com/dnd-mcp/server/internal/api/tools"
+	"github.com/dnd-mcp/server/internal/auth"
 	"github.com/dnd-mcp/server/internal/importer"
 	"github.com/dnd-mcp/server/internal/importer/converter"
 	"github.com/dnd-mcp/server/internal/importer/format"
 	importer_parser "github.com/dnd-mcp/server/internal/importer/parser"
 	"github.com/dnd-mcp/server/internal/mcp"
 	"github.com/dnd-mcp/server/internal/service"
+	"github.com/dnd-mcp/server/internal/store"
 	"github.com/dnd-mcp/server/internal/store/postgres"
 	"github.com/dnd-mcp/server/pkg/config"
 )
@@ -32,8 +37,15 @@ var (
 func main() {
 	// Parse command line flags
 	showVersion := flag.Bool("version", false, "Show version information")
+	stdio := flag.Bool("stdio", false, "Serve MCP over stdin/stdout instead of HTTP")
 	flag.Parse()
 
+	// In stdio mode stdout carries the protocol, so all logging goes to stderr
+	protocolOut := os.Stdout
+	if *stdio {
+		os.Stdout = os.Stderr
+	}
+
 	if *showVersion {
 		fmt.Printf("DND MCP Server v%s\n", version)
 		fmt.Printf("Git Commit: %s\n", gitCommit)
@@ -88,23 +100,59 @@ func main() {
 	// Step 4: Create MCP Server
 	server := mcp.NewServer(cfg)
 
-	// Step 5: Initialize stores
-	campaignStore := postgres.NewCampaignStore(dbClient)
-	gameStateStore := postgres.NewGameStateStore(dbClient)
-	characterStore := postgres.NewCharacterStore(dbClient)
-	combatStore := postgres.NewCombatStore(dbClient)
-	mapStore := postgres.NewMapStore(dbClient)
+	// Step 5: Initialize stores; writes are published to the change feed
+	// and recorded in the journal of audited tool calls
+	changes := store.NewChangeFeed()
+	campaignStore := store.ObserveCampaignStore(postgres.NewCampaignStore(dbClient), changes)
+	gameStateStore := store.JournalGameStateStore(store.ObserveGameStateStore(postgres.NewGameStateStore(dbClient), changes))
+	characterStore := store.JournalCharacterStore(store.ObserveCharacterStore(postgres.NewCharacterStore(dbClient), changes))
+	combatStore := store.JournalCombatStore(store.ObserveCombatStore(postgres.NewCombatStore(dbClient), changes))
+	mapStore := store.JournalMapStore(store.ObserveMapStore(postgres.NewMapStore(dbClient), changes))
 	messageStore := postgres.NewMessageStore(dbClient) // M7: Context Management
+	eventStore := postgres.NewEventStore(dbClient)
+	auditStore := postgres.NewAuditStore(dbClient)
+	snapshotStore := postgres.NewSnapshotStore(dbClient)
 
 	// Step 6: Initialize services
 	campaignService := service.NewCampaignService(campaignStore, gameStateStore)
 	characterService := service.NewCharacterService(characterStore)
 	diceService := service.NewDiceService(characterStore)
+	diceService.SetCombatStore(combatStore) // Checks and saves apply combat participant conditions
 	combatService := service.NewCombatService(combatStore, characterStore, campaignStore, gameStateStore, diceService)
 	mapService := service.NewMapServiceWithCharacters(mapStore, campaignStore, gameStateStore, characterStore)
+	mapService.SetCombatStore(combatStore) // Token movement consumes combat turn movement
+	mapService.SetTransactor(dbClient)     // Turn movement and token position are saved together
+	combatService.SetMapStore(mapStore)    // Token positions drive range-dependent condition effects
+	combatService.SetTransactor(dbClient)  // A spell's slot, targets and combat are saved together
+	mapService.SetOpportunityAttackHandler(combatService) // Leaving a hostile's reach provokes opportunity attacks
 	contextService := service.NewContextService(messageStore, characterStore, gameStateStore, combatStore, mapStore) // M7: Context Management
 	restService := service.NewRestService(characterStore, gameStateStore)                                           // M7.5: Rest System
 	conditionService := service.NewConditionService(characterStore)                                                 // M7.5: Condition System
+	visionService := service.NewVisionService(mapStore, characterStore, combatStore, diceService)
+
+	// Step 6.1: Publish game events (HP, conditions, turns, movement, dice, maps) to clients
+	eventBus := service.NewEventBus(eventStore)
+	characterService.SetEventPublisher(eventBus)
+	diceService.SetEventPublisher(eventBus)
+	combatService.SetEventPublisher(eventBus)
+	mapService.SetEventPublisher(eventBus)
+	conditionService.SetEventPublisher(eventBus)
+
+	// Step 6.2: Audit mutating tool calls; undo restores records in one transaction
+	auditService := service.NewAuditService(auditStore, dbClient, campaignStore, characterStore, combatStore, mapStore, gameStateStore)
+
+	// Step 6.3: Export and import campaign archives; the export and import subcommands run here and exit
+	archiveService := service.NewArchiveService(campaignStore, gameStateStore, characterStore, mapStore, combatStore, messageStore, dbClient)
+	if flag.NArg() > 0 {
+		if err := runArchiveCommand(context.Background(), archiveService, flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Step 6.4: Campaign snapshots are captured in one repeatable read transaction
+	campaignService.SetSnapshotStores(snapshotStore, characterStore, mapStore, combatStore, messageStore, dbClient)
 
 	// Step 6.5: Initialize import service
 	importService := importer.NewImportService(mapStore)
@@ -118,24 +166,29 @@ func main() {
 	// Step 7: Register Tools
 	campaignTools := tools.NewCampaignTools(campaignService)
 	campaignTools.Register(server.Registry())
-	fmt.Println("Campaign tools registered: create_campaign, get_campaign, list_campaigns, delete_campaign, get_campaign_summary")
+	fmt.Println("Campaign tools registered: create_campaign, get_campaign, list_campaigns, update_campaign, delete_campaign, get_campaign_summary, create_snapshot, list_snapshots, restore_snapshot, fork_campaign")
 
 	characterTools := tools.NewCharacterTools(characterService)
 	characterTools.Register(server.Registry())
-	fmt.Println("Character tools registered: create_character, get_character, update_character, list_characters, delete_character")
+	fmt.Println("Character tools registered: create_character, get_character, update_character, list_characters, delete_character, death_save, stabilize, get_encumbrance")
 
 	diceTools := tools.NewDiceTools(diceService)
 	diceTools.Register(server.Registry())
-	fmt.Println("Dice tools registered: roll_dice, roll_check, roll_save")
+	fmt.Println("Dice tools registered: roll_dice, roll_check, roll_save, roll_attack, roll_damage")
 
 	combatTools := tools.NewCombatTools(combatService)
 	combatTools.Register(server.Registry())
-	fmt.Println("Combat tools registered: start_combat, get_combat_state, attack, cast_spell, end_turn, end_combat")
+	fmt.Println("Combat tools registered: start_combat, get_combat_state, attack, cast_spell, end_turn, end_combat, dash, dodge, disengage, help, hide, ready")
 
 	mapTools := tools.NewMapToolsWithCharacters(mapService)
+	mapTools.SetVisionService(visionService) // Player-scoped get_battle_map
 	mapTools.Register(server.Registry())
 	fmt.Println("Map tools registered: get_world_map, move_to, move_token, enter_battle_map, get_battle_map, exit_battle_map, create_visual_location, update_location")
 
+	visionTools := tools.NewVisionTools(visionService)
+	visionTools.Register(server.Registry())
+	fmt.Println("Vision tools registered: get_visible_area, can_see")
+
 	// Step 7.5: Register Import Tools
 	importTools := tools.NewImportTools(importService)
 	importTools.Register(server.Registry())
@@ -156,7 +209,72 @@ func main() {
 	conditionTools.Register(server.Registry())
 	fmt.Println("Condition tools registered: apply_condition, remove_condition, get_conditions, has_condition")
 
-	// Step 8: Start HTTP server in goroutine
+	// Step 7.8.3: Register Archive Tools
+	archiveTools := tools.NewArchiveTools(archiveService)
+	archiveTools.Register(server.Registry())
+	fmt.Println("Archive tools registered: export_campaign, import_campaign")
+
+	// Step 7.8.4: Register Audit Tools; every successful mutating tool call is recorded
+	auditTools := tools.NewAuditTools(auditService)
+	auditTools.Register(server.Registry())
+	fmt.Println("Audit tools registered: list_actions, undo_last_action")
+
+	// Step 7.8.5: Restrict tools by role; requests without credentials (stdio) keep full access
+	tools.RegisterAccess(server.Registry())
+	// DMs run only their own campaigns
+	server.Registry().SetCampaignResolver(tools.CampaignResolver(tools.CampaignRecords{
+		Characters: characterStore,
+		Combats:    combatStore,
+		Maps:       mapStore,
+	}))
+	server.SetCampaignDMs(func(ctx context.Context, campaignID string) (string, error) {
+		campaign, err := campaignStore.Get(ctx, campaignID)
+		if err != nil {
+			return "", err
+		}
+		return campaign.DMID, nil
+	})
+	if cfg.Auth.Enabled {
+		authenticator, err := auth.NewAuthenticator(&cfg.Auth)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to configure authentication: %v\n", err)
+			os.Exit(1)
+		}
+		server.SetAuthenticator(authenticator)
+		fmt.Println("Authentication enabled: DM, player and observer roles")
+	}
+
+	// Step 7.9: Register Resources; subscribers are notified when the underlying records change
+	gameResources := resources.NewGameResources(campaignService, characterService, mapService.MapService, combatService)
+	gameResources.Register(server.Resources())
+	gameResources.Watch(changes, server)
+	fmt.Println("Resources registered: campaign, party, character, map, combat_log")
+
+	// Step 7.10: Register Prompts; campaigns may override the templates in their settings
+	gamePrompts := prompts.NewGamePrompts(campaignService, characterService, mapService.MapService, combatService, contextService)
+	gamePrompts.Register(server.Prompts())
+	fmt.Println("Prompts registered: run_combat_turn, describe_location, npc_dialogue, session_recap")
+
+	// Step 7.11: Serve game events to clients
+	eventSource := events.NewSource(eventBus)
+	eventSource.SetAccess(service.NewEventAccess(characterStore, mapStore)) // Players follow their campaigns without NPC HP or hidden tokens
+	server.SetEventSource(eventSource)
+	fmt.Printf("Game events served at %s and %s\n", mcp.EventsEndpoint, mcp.EventStreamEndpoint)
+
+	// Step 8: Serve MCP over stdio when requested
+	if *stdio {
+		stdioCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Println("Serving MCP over stdio")
+		if err := server.ServeStdio(stdioCtx, os.Stdin, protocolOut); err != nil && err != context.Canceled {
+			fmt.Fprintf(os.Stderr, "stdio server error: %v\n", err)
+		}
+		fmt.Println("Server stopped")
+		return
+	}
+
+	// Step 8.5: Otherwise start HTTP server in goroutine
 	go func() {
 		fmt.Printf("HTTP server listening on %s:%d\n", cfg.HTTP.Host, cfg.HTTP.Port)
 		if err := server.Start(context.Background()); err != nil && err != http.ErrServerClosed {
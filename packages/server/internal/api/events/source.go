@@ -0,0 +1,135 @@
+// Package events serves the service layer's game events over the MCP server's event endpoints
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+)
+
+// Source adapts an EventBus to mcp.EventSource; client session ids are campaign ids
+type Source struct {
+	bus    *service.EventBus
+	access *service.EventAccess
+}
+
+// NewSource creates a new Source instance
+func NewSource(bus *service.EventBus) *Source {
+	return &Source{bus: bus}
+}
+
+// SetAccess lets players follow the campaigns they play in, without NPC HP or hidden tokens.
+// Without it only the campaign's DM may follow its events.
+func (s *Source) SetAccess(access *service.EventAccess) {
+	s.access = access
+}
+
+// View checks the caller may follow the campaign's events and returns their view of them
+func (s *Source) View(ctx context.Context, sessionID string) (mcp.EventView, error) {
+	if s.access == nil {
+		if !auth.RunsCampaign(ctx, sessionID) {
+			return nil, fmt.Errorf("%w: only the DM may follow the events of campaign %s", mcp.ErrForbidden, sessionID)
+		}
+		return func(event mcp.Event) (mcp.Event, bool) { return event, true }, nil
+	}
+
+	view, err := s.access.View(ctx, sessionID)
+	if err != nil {
+		if se := service.GetServiceError(err); se != nil && se.Code == service.ErrCodeForbidden {
+			return nil, fmt.Errorf("%w: %s", mcp.ErrForbidden, se.Message)
+		}
+		return nil, err
+	}
+	return func(event mcp.Event) (mcp.Event, bool) {
+		shown, ok := view(fromMCPEvent(event))
+		if !ok {
+			return mcp.Event{}, false
+		}
+		return toMCPEvent(shown), true
+	}, nil
+}
+
+// Events lists up to limit events after a cursor
+func (s *Source) Events(ctx context.Context, sessionID string, after int64, types []string, limit int) ([]mcp.Event, error) {
+	events, err := s.bus.Since(ctx, sessionID, after, eventTypes(types), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]mcp.Event, 0, len(events))
+	for _, event := range events {
+		result = append(result, toMCPEvent(event))
+	}
+	return result, nil
+}
+
+// Latest returns the cursor of the campaign's most recent event
+func (s *Source) Latest(ctx context.Context, sessionID string) (int64, error) {
+	return s.bus.Latest(ctx, sessionID)
+}
+
+// Subscribe delivers the campaign's new events until cancel is called
+func (s *Source) Subscribe(sessionID string, types []string) (<-chan mcp.Event, func()) {
+	sub := s.bus.Subscribe(sessionID, eventTypes(types))
+	out := make(chan mcp.Event)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(out)
+		for event := range sub.Events() {
+			select {
+			case out <- toMCPEvent(event):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+			sub.Close()
+		})
+	}
+	return out, cancel
+}
+
+// eventTypes converts a type filter
+func eventTypes(types []string) []models.EventType {
+	if len(types) == 0 {
+		return nil
+	}
+	result := make([]models.EventType, len(types))
+	for i, t := range types {
+		result[i] = models.EventType(t)
+	}
+	return result
+}
+
+// toMCPEvent converts a game event for delivery
+func toMCPEvent(event *models.GameEvent) mcp.Event {
+	return mcp.Event{
+		Cursor:    event.Cursor,
+		Type:      string(event.Type),
+		SessionID: event.CampaignID,
+		Data:      event.Data,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// fromMCPEvent converts a delivered event back to a game event
+func fromMCPEvent(event mcp.Event) *models.GameEvent {
+	return &models.GameEvent{
+		Cursor:     event.Cursor,
+		CampaignID: event.SessionID,
+		Type:       models.EventType(event.Type),
+		Data:       event.Data,
+		CreatedAt:  event.CreatedAt,
+	}
+}
@@ -0,0 +1,373 @@
+// Package prompts provides MCP prompt implementations
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+)
+
+// Prompt names
+const (
+	RunCombatTurn    = "run_combat_turn"
+	DescribeLocation = "describe_location"
+	NPCDialogue      = "npc_dialogue"
+	SessionRecap     = "session_recap"
+)
+
+// PromptData is the data every prompt is rendered with. Campaign overrides in
+// CampaignSettings.Prompts are text/template templates over the same fields.
+type PromptData struct {
+	Campaign   *models.Campaign
+	Summary    *models.GameSummary // time, location, weather and party health
+	GameState  *models.GameState
+	Map        *models.Map      // current map, if any
+	Location   *models.Location // named location on the current map, if any
+	Party      []*models.Character
+	Combat     *models.Combat
+	Combatants []Combatant       // participants in initiative order
+	Current    *Combatant        // participant whose turn it is
+	Character  *models.Character // character_id argument
+	Speaker    *models.Character // speaker_id argument
+	Messages   []models.Message  // recent conversation, oldest first
+	Args       map[string]string // raw prompt arguments
+}
+
+// Combatant is a combat participant resolved to its character
+type Combatant struct {
+	ID         string
+	Name       string
+	IsNPC      bool
+	Initiative int
+	HP         string
+	AC         int
+	Conditions string
+	Current    bool
+}
+
+// Default templates
+const (
+	runCombatTurnTemplate = `You are the Dungeon Master of the D&D 5e campaign "{{.Campaign.Name}}". Run the current combat turn.
+
+Round {{.Combat.Round}}, initiative order:
+{{range .Combatants}}{{if .Current}}> {{else}}- {{end}}{{.Name}}{{if .IsNPC}} (NPC){{end}}: initiative {{.Initiative}}, HP {{.HP}}, AC {{.AC}}{{if .Conditions}}, {{.Conditions}}{{end}}
+{{end}}
+It is {{.Current.Name}}'s turn.
+{{if .Current.IsNPC}}Choose {{.Current.Name}}'s action the way the creature would, favouring believable tactics over optimal play. Resolve it with the tools (move_token, attack, cast_spell, dash, disengage, dodge, hide), narrate the outcome in two or three sentences, then call end_turn.
+{{else}}Briefly describe what {{.Current.Name}} sees and ask the player what they do. Resolve the declared action with the tools and call end_turn when the player is done.
+{{end}}
+Never invent dice results: roll through the tools and report the numbers they return.`
+
+	describeLocationTemplate = `You are the Dungeon Master of the D&D 5e campaign "{{.Campaign.Name}}". Describe {{if .Location}}{{.Location.Name}}{{else if .Args.location}}{{.Args.location}}{{else}}the party's current surroundings{{end}} to the players.
+
+Time: {{.Summary.Time}}
+Weather: {{.Summary.Weather}}
+{{with .Map}}Map: {{.Name}} ({{.Type}} map)
+{{end}}{{with .Location}}{{if .Description}}Notes: {{.Description}}
+{{end}}{{end}}{{if .Party}}Party: {{range $i, $c := .Party}}{{if $i}}, {{end}}{{$c.Name}}{{end}}
+{{end}}
+Appeal to several senses, point out exits and anything the party can interact with, and stay consistent with the time of day and weather. Do not reveal hidden creatures, traps or secrets. End by asking the players what they do.`
+
+	npcDialogueTemplate = `You are the Dungeon Master of the D&D 5e campaign "{{.Campaign.Name}}". Speak as {{.Character.Name}} and stay in character.
+
+{{.Character.Name}}: {{.Character.Race}} {{.Character.Class}}{{if .Character.Alignment}}, {{.Character.Alignment}}{{end}}{{if .Character.Background}}, {{.Character.Background}}{{end}}
+{{with .Character.Biography}}{{if .Appearance}}Appearance: {{.Appearance}}
+{{end}}{{if .PersonalityTraits}}Personality: {{.PersonalityTraits}}
+{{end}}{{if .Ideals}}Ideals: {{.Ideals}}
+{{end}}{{if .Bonds}}Bonds: {{.Bonds}}
+{{end}}{{if .Flaws}}Flaws: {{.Flaws}}
+{{end}}{{if .Backstory}}Backstory: {{.Backstory}}
+{{end}}{{end}}{{with .Character.Traits}}{{if .Languages}}Languages: {{range $i, $l := .Languages}}{{if $i}}, {{end}}{{$l}}{{end}}
+{{end}}{{end}}Current location: {{.Summary.Location}}, {{.Summary.Time}}
+{{with .Speaker}}Speaking with: {{.Name}}, {{.Race}} {{.Class}}
+{{end}}{{with .Args.topic}}Topic: {{.}}
+{{end}}
+Answer in the NPC's own voice and manner of speech. The NPC only knows what they could plausibly know, may lie or withhold information when it suits their personality, and reacts to how they are treated. Use roll_check for Persuasion, Deception, Intimidation or Insight when the outcome is uncertain.`
+
+	sessionRecapTemplate = `You are the Dungeon Master of the D&D 5e campaign "{{.Campaign.Name}}". Write a recap of the story so far to open the next session.
+
+Time: {{.Summary.Time}}
+Location: {{.Summary.Location}}
+{{if .Party}}Party:
+{{range .Party}}- {{.Name}}, level {{.Level}} {{.Race}} {{.Class}}
+{{end}}{{end}}{{with .Combat}}The party is in combat (round {{.Round}}).
+{{end}}
+Recent events:
+{{range .Messages}}[{{.Role}}] {{.Content}}
+{{else}}(no messages yet)
+{{end}}
+Summarize in a few short paragraphs: where the party is, what they achieved, unresolved threads and who they met. Write in the past tense as a narrator, without game mechanics, and finish with the situation the party is facing now.`
+)
+
+// defaultTemplates maps prompt names to their built-in templates
+var defaultTemplates = map[string]string{
+	RunCombatTurn:    runCombatTurnTemplate,
+	DescribeLocation: describeLocationTemplate,
+	NPCDialogue:      npcDialogueTemplate,
+	SessionRecap:     sessionRecapTemplate,
+}
+
+// DefaultTemplate returns the built-in template of a prompt
+func DefaultTemplate(name string) (string, bool) {
+	text, ok := defaultTemplates[name]
+	return text, ok
+}
+
+// GamePrompts renders DM prompts from live campaign, combat and character data
+type GamePrompts struct {
+	campaignService  *service.CampaignService
+	characterService *service.CharacterService
+	mapService       *service.MapService
+	combatService    *service.CombatService
+	contextService   *service.ContextService
+}
+
+// NewGamePrompts creates a new GamePrompts instance
+func NewGamePrompts(campaignService *service.CampaignService, characterService *service.CharacterService, mapService *service.MapService, combatService *service.CombatService, contextService *service.ContextService) *GamePrompts {
+	return &GamePrompts{
+		campaignService:  campaignService,
+		characterService: characterService,
+		mapService:       mapService,
+		combatService:    combatService,
+		contextService:   contextService,
+	}
+}
+
+// campaignArgument is accepted by every prompt
+var campaignArgument = mcp.PromptArgument{Name: "campaign_id", Description: "The campaign ID", Required: true}
+
+// Register registers all prompts with the registry
+func (p *GamePrompts) Register(registry *mcp.PromptRegistry) {
+	registry.MustRegister(mcp.Prompt{
+		Name:        RunCombatTurn,
+		Description: "Run the current turn of the active combat: initiative order, hit points and conditions of every combatant, and whose turn it is.",
+		Arguments:   []mcp.PromptArgument{campaignArgument},
+	}, p.runCombatTurn)
+
+	registry.MustRegister(mcp.Prompt{
+		Name:        DescribeLocation,
+		Description: "Describe the party's surroundings or a named location using the current time, weather and map.",
+		Arguments: []mcp.PromptArgument{
+			campaignArgument,
+			{Name: "location", Description: "Name of a location on the current map, or a place to describe (defaults to the party's surroundings)"},
+		},
+	}, p.describeLocation)
+
+	registry.MustRegister(mcp.Prompt{
+		Name:        NPCDialogue,
+		Description: "Voice an NPC in conversation based on their character sheet and biography.",
+		Arguments: []mcp.PromptArgument{
+			campaignArgument,
+			{Name: "character_id", Description: "The NPC's character ID", Required: true},
+			{Name: "speaker_id", Description: "ID of the player character talking to the NPC"},
+			{Name: "topic", Description: "What the conversation is about"},
+		},
+	}, p.npcDialogue)
+
+	registry.MustRegister(mcp.Prompt{
+		Name:        SessionRecap,
+		Description: "Recap the story so far from the conversation history and the current game state.",
+		Arguments: []mcp.PromptArgument{
+			campaignArgument,
+			{Name: "message_limit", Description: "Number of recent messages to summarize (defaults to the campaign's context window)"},
+		},
+	}, p.sessionRecap)
+}
+
+// runCombatTurn renders run_combat_turn
+func (p *GamePrompts) runCombatTurn(ctx context.Context, args map[string]string) (*mcp.GetPromptResponse, error) {
+	data, err := p.load(ctx, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if data.Combat == nil || data.Current == nil {
+		return nil, fmt.Errorf("%w: campaign %s has no active combat", mcp.ErrInvalidPromptArguments, data.Campaign.ID)
+	}
+	return p.render(RunCombatTurn, fmt.Sprintf("Round %d: %s's turn", data.Combat.Round, data.Current.Name), data)
+}
+
+// describeLocation renders describe_location
+func (p *GamePrompts) describeLocation(ctx context.Context, args map[string]string) (*mcp.GetPromptResponse, error) {
+	data, err := p.load(ctx, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if data.Map != nil {
+		data.Location = findLocation(data.Map, args["location"], data.GameState)
+	}
+
+	description := "The party's surroundings"
+	if data.Location != nil {
+		description = data.Location.Name
+	} else if args["location"] != "" {
+		description = args["location"]
+	}
+	return p.render(DescribeLocation, description, data)
+}
+
+// npcDialogue renders npc_dialogue
+func (p *GamePrompts) npcDialogue(ctx context.Context, args map[string]string) (*mcp.GetPromptResponse, error) {
+	data, err := p.load(ctx, args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Character, err = p.characterService.GetCharacterByCampaign(ctx, data.Campaign.ID, args["character_id"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: character %s: %v", mcp.ErrInvalidPromptArguments, args["character_id"], err)
+	}
+	if !data.Character.IsNPC {
+		return nil, fmt.Errorf("%w: %s is not an NPC", mcp.ErrInvalidPromptArguments, data.Character.Name)
+	}
+	if speakerID := args["speaker_id"]; speakerID != "" {
+		data.Speaker, err = p.characterService.GetCharacterByCampaign(ctx, data.Campaign.ID, speakerID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: speaker %s: %v", mcp.ErrInvalidPromptArguments, speakerID, err)
+		}
+	}
+	return p.render(NPCDialogue, "Dialogue with "+data.Character.Name, data)
+}
+
+// sessionRecap renders session_recap
+func (p *GamePrompts) sessionRecap(ctx context.Context, args map[string]string) (*mcp.GetPromptResponse, error) {
+	limit := 0
+	if raw := args["message_limit"]; raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: message_limit must be a positive integer", mcp.ErrInvalidPromptArguments)
+		}
+		limit = n
+	}
+
+	data, err := p.load(ctx, args, limit)
+	if err != nil {
+		return nil, err
+	}
+	return p.render(SessionRecap, "Recap of "+data.Campaign.Name, data)
+}
+
+// load gathers the campaign state shared by all prompts
+func (p *GamePrompts) load(ctx context.Context, args map[string]string, messageLimit int) (*PromptData, error) {
+	campaign, err := p.campaignService.GetCampaign(ctx, args["campaign_id"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: campaign %s: %v", mcp.ErrInvalidPromptArguments, args["campaign_id"], err)
+	}
+	if messageLimit <= 0 && campaign.Settings != nil {
+		messageLimit = campaign.Settings.ContextWindow
+	}
+
+	gameContext, err := p.contextService.GetContext(ctx, campaign.ID, messageLimit, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get context: %w", err)
+	}
+
+	data := &PromptData{
+		Campaign: campaign,
+		Summary:  gameContext.GameSummary,
+		Messages: gameContext.Messages,
+		Args:     args,
+	}
+
+	// Missing optional state leaves the field nil; templates guard with if/with
+	if gameState, err := p.campaignService.GetGameState(ctx, campaign.ID); err == nil {
+		data.GameState = gameState
+		if gameState.CurrentMapID != "" {
+			if gameMap, err := p.mapService.GetMap(ctx, gameState.CurrentMapID); err == nil {
+				data.Map = gameMap
+			}
+		}
+	}
+
+	isNPC := false
+	data.Party, err = p.characterService.ListCharacters(ctx, &service.ListCharactersRequest{
+		CampaignID: campaign.ID,
+		IsNPC:      &isNPC,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list party: %w", err)
+	}
+
+	if combat, err := p.combatService.GetActiveCombat(ctx, campaign.ID); err == nil && combat != nil {
+		data.Combat = combat
+		p.loadCombatants(ctx, data)
+	}
+
+	return data, nil
+}
+
+// loadCombatants resolves the combat participants to their characters
+func (p *GamePrompts) loadCombatants(ctx context.Context, data *PromptData) {
+	data.Combatants = make([]Combatant, 0, len(data.Combat.Participants))
+	for i, participant := range data.Combat.Participants {
+		combatant := Combatant{
+			ID:         participant.CharacterID,
+			Name:       participant.CharacterID,
+			Initiative: participant.Initiative,
+			HP:         "?",
+			Current:    i == data.Combat.TurnIndex,
+		}
+		conditions := make([]string, 0, len(participant.Conditions))
+		for _, condition := range participant.Conditions {
+			conditions = append(conditions, condition.Type)
+		}
+
+		if character, err := p.characterService.GetCharacter(ctx, participant.CharacterID); err == nil {
+			combatant.Name = character.Name
+			combatant.IsNPC = character.IsNPC
+			combatant.AC = character.AC
+			if character.HP != nil {
+				combatant.HP = fmt.Sprintf("%d/%d", character.HP.Current, character.HP.Max)
+			}
+			for _, condition := range character.Conditions {
+				conditions = append(conditions, condition.Type)
+			}
+		}
+		combatant.Conditions = strings.Join(conditions, ", ")
+		data.Combatants = append(data.Combatants, combatant)
+	}
+
+	for i := range data.Combatants {
+		if data.Combatants[i].Current {
+			data.Current = &data.Combatants[i]
+		}
+	}
+}
+
+// render executes the campaign's override of a prompt, or its default template
+func (p *GamePrompts) render(name, description string, data *PromptData) (*mcp.GetPromptResponse, error) {
+	text := defaultTemplates[name]
+	if settings := data.Campaign.Settings; settings != nil && settings.Prompts[name] != "" {
+		text = settings.Prompts[name]
+	}
+
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+	return mcp.NewUserPrompt(description, buf.String()), nil
+}
+
+// findLocation looks up a location by name, or the one at the party position when no name is given
+func findLocation(m *models.Map, name string, gameState *models.GameState) *models.Location {
+	for i := range m.Locations {
+		loc := &m.Locations[i]
+		if name != "" && strings.EqualFold(loc.Name, name) {
+			return loc
+		}
+		if name == "" && gameState != nil && gameState.PartyPosition != nil && loc.Position == *gameState.PartyPosition {
+			return loc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+// Package resources provides MCP resource implementations
+package resources
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/render"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/dnd-mcp/server/internal/store"
+)
+
+// Resource URI templates
+const (
+	CampaignURI  = "dnd://campaign/{id}"
+	PartyURI     = "dnd://campaign/{id}/party"
+	CharacterURI = "dnd://campaign/{id}/character/{cid}"
+	MapURI       = "dnd://campaign/{id}/map/{mid}"
+	CombatLogURI = "dnd://combat/{id}/log"
+)
+
+const (
+	mimeJSON = "application/json"
+	mimePNG  = "image/png"
+)
+
+// ResourceNotifier delivers resources/updated notifications to subscribed clients
+type ResourceNotifier interface {
+	NotifyResourceUpdated(uri string) int
+}
+
+// GameResources exposes campaigns, characters, maps and combat logs as MCP resources
+type GameResources struct {
+	campaignService  *service.CampaignService
+	characterService *service.CharacterService
+	mapService       *service.MapService
+	combatService    *service.CombatService
+}
+
+// NewGameResources creates a new GameResources instance
+func NewGameResources(campaignService *service.CampaignService, characterService *service.CharacterService, mapService *service.MapService, combatService *service.CombatService) *GameResources {
+	return &GameResources{
+		campaignService:  campaignService,
+		characterService: characterService,
+		mapService:       mapService,
+		combatService:    combatService,
+	}
+}
+
+// Register registers all resource templates with the registry
+func (r *GameResources) Register(registry *mcp.ResourceRegistry) {
+	registry.MustRegisterTemplate(mcp.ResourceTemplate{
+		URITemplate: CampaignURI,
+		Name:        "campaign",
+		Description: "Campaign settings and current game state (time, weather, party position, active combat).",
+		MIMEType:    mimeJSON,
+	}, r.readCampaign, r.listCampaigns)
+
+	registry.MustRegisterTemplate(mcp.ResourceTemplate{
+		URITemplate: PartyURI,
+		Name:        "party",
+		Description: "Character sheets of every player character in the campaign.",
+		MIMEType:    mimeJSON,
+	}, r.readParty, r.listParties)
+
+	registry.MustRegisterTemplate(mcp.ResourceTemplate{
+		URITemplate: CharacterURI,
+		Name:        "character",
+		Description: "Full character sheet of a player character or NPC.",
+		MIMEType:    mimeJSON,
+	}, r.readCharacter, r.listCharacters)
+
+	registry.MustRegisterTemplate(mcp.ResourceTemplate{
+		URITemplate: MapURI,
+		Name:        "map",
+		Description: "World or battle map as JSON, plus a rendered PNG of grid maps showing terrain, walls, doors and tokens.",
+		MIMEType:    mimeJSON,
+	}, r.readMap, r.listMaps)
+
+	registry.MustRegisterTemplate(mcp.ResourceTemplate{
+		URITemplate: CombatLogURI,
+		Name:        "combat_log",
+		Description: "Round, turn order and log entries of a combat encounter.",
+		MIMEType:    mimeJSON,
+	}, r.readCombatLog, r.listCombatLogs)
+}
+
+// Watch notifies subscribed clients whenever a store write changes a resource
+func (r *GameResources) Watch(feed *store.ChangeFeed, notifier ResourceNotifier) {
+	feed.Subscribe(func(ctx context.Context, change store.Change) {
+		for _, uri := range ChangedURIs(change) {
+			notifier.NotifyResourceUpdated(uri)
+		}
+	})
+}
+
+// ChangedURIs returns the resource URIs whose contents a store change affects
+func ChangedURIs(change store.Change) []string {
+	switch change.Entity {
+	case store.EntityCampaign, store.EntityGameState:
+		return []string{expand(CampaignURI, change.CampaignID, "")}
+	case store.EntityCharacter:
+		if change.CampaignID == "" {
+			return nil
+		}
+		return []string{
+			expand(CharacterURI, change.CampaignID, change.ID),
+			expand(PartyURI, change.CampaignID, ""),
+		}
+	case store.EntityMap:
+		if change.CampaignID == "" {
+			return nil
+		}
+		return []string{expand(MapURI, change.CampaignID, change.ID)}
+	case store.EntityCombat:
+		return []string{expand(CombatLogURI, change.ID, "")}
+	default:
+		return nil
+	}
+}
+
+// readCampaign reads dnd://campaign/{id}
+func (r *GameResources) readCampaign(ctx context.Context, uri string, params map[string]string) ([]mcp.ResourceContents, error) {
+	campaign, err := r.campaignService.GetCampaign(ctx, params["id"])
+	if err != nil {
+		return nil, notFound(err)
+	}
+	gameState, err := r.campaignService.GetGameState(ctx, campaign.ID)
+	if err != nil {
+		gameState = nil
+	}
+
+	return jsonContents(uri, map[string]interface{}{
+		"campaign":   campaign,
+		"game_state": gameState,
+	})
+}
+
+// readParty reads dnd://campaign/{id}/party
+func (r *GameResources) readParty(ctx context.Context, uri string, params map[string]string) ([]mcp.ResourceContents, error) {
+	if _, err := r.campaignService.GetCampaign(ctx, params["id"]); err != nil {
+		return nil, notFound(err)
+	}
+
+	isNPC := false
+	characters, err := r.characterService.ListCharacters(ctx, &service.ListCharactersRequest{
+		CampaignID: params["id"],
+		IsNPC:      &isNPC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonContents(uri, map[string]interface{}{
+		"campaign_id": params["id"],
+		"characters":  characters,
+	})
+}
+
+// readCharacter reads dnd://campaign/{id}/character/{cid}
+func (r *GameResources) readCharacter(ctx context.Context, uri string, params map[string]string) ([]mcp.ResourceContents, error) {
+	character, err := r.characterService.GetCharacterByCampaign(ctx, params["id"], params["cid"])
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return jsonContents(uri, service.RedactCharacter(ctx, character))
+}
+
+// readMap reads dnd://campaign/{id}/map/{mid} as JSON and, for grid maps, a PNG
+func (r *GameResources) readMap(ctx context.Context, uri string, params map[string]string) ([]mcp.ResourceContents, error) {
+	gameMap, err := r.mapService.GetMap(ctx, params["mid"])
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if gameMap.CampaignID != params["id"] {
+		return nil, fmt.Errorf("%w: map %s is not in campaign %s", mcp.ErrResourceNotFound, params["mid"], params["id"])
+	}
+	gameMap = service.RedactMap(ctx, gameMap)
+
+	contents, err := jsonContents(uri, gameMap)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := render.MapPNG(gameMap)
+	if errors.Is(err, render.ErrNoGrid) {
+		return contents, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render map: %w", err)
+	}
+	return append(contents, mcp.ResourceContents{
+		URI:      uri,
+		MIMEType: mimePNG,
+		Blob:     base64.StdEncoding.EncodeToString(image),
+	}), nil
+}
+
+// readCombatLog reads dnd://combat/{id}/log
+func (r *GameResources) readCombatLog(ctx context.Context, uri string, params map[string]string) ([]mcp.ResourceContents, error) {
+	combat, err := r.combatService.GetCombatState(ctx, params["id"])
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return jsonContents(uri, map[string]interface{}{
+		"combat_id":    combat.ID,
+		"campaign_id":  combat.CampaignID,
+		"status":       combat.Status,
+		"round":        combat.Round,
+		"turn_index":   combat.TurnIndex,
+		"participants": combat.Participants,
+		"log":          combat.Log,
+	})
+}
+
+// listCampaigns lists a campaign resource per campaign
+func (r *GameResources) listCampaigns(ctx context.Context) ([]mcp.Resource, error) {
+	campaigns, err := r.campaignService.ListCampaigns(ctx, &service.ListCampaignsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]mcp.Resource, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		resources = append(resources, mcp.Resource{
+			URI:      expand(CampaignURI, campaign.ID, ""),
+			Name:     campaign.Name,
+			MIMEType: mimeJSON,
+		})
+	}
+	return resources, nil
+}
+
+// listParties lists a party resource per campaign
+func (r *GameResources) listParties(ctx context.Context) ([]mcp.Resource, error) {
+	campaigns, err := r.campaignService.ListCampaigns(ctx, &service.ListCampaignsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]mcp.Resource, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		resources = append(resources, mcp.Resource{
+			URI:      expand(PartyURI, campaign.ID, ""),
+			Name:     campaign.Name + " party",
+			MIMEType: mimeJSON,
+		})
+	}
+	return resources, nil
+}
+
+// listCharacters lists every character of every campaign
+func (r *GameResources) listCharacters(ctx context.Context) ([]mcp.Resource, error) {
+	campaigns, err := r.campaignService.ListCampaigns(ctx, &service.ListCampaignsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]mcp.Resource, 0)
+	for _, campaign := range campaigns {
+		characters, err := r.characterService.ListCharacters(ctx, &service.ListCharactersRequest{CampaignID: campaign.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, character := range characters {
+			resources = append(resources, mcp.Resource{
+				URI:         expand(CharacterURI, campaign.ID, character.ID),
+				Name:        character.Name,
+				Description: characterDescription(service.RedactCharacter(ctx, character)),
+				MIMEType:    mimeJSON,
+			})
+		}
+	}
+	return resources, nil
+}
+
+// listMaps lists every map of every campaign
+func (r *GameResources) listMaps(ctx context.Context) ([]mcp.Resource, error) {
+	campaigns, err := r.campaignService.ListCampaigns(ctx, &service.ListCampaignsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]mcp.Resource, 0)
+	for _, campaign := range campaigns {
+		maps, err := r.mapService.ListMaps(ctx, campaign.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, gameMap := range maps {
+			resources = append(resources, mcp.Resource{
+				URI:         expand(MapURI, campaign.ID, gameMap.ID),
+				Name:        gameMap.Name,
+				Description: fmt.Sprintf("%s map", gameMap.Type),
+				MIMEType:    mimeJSON,
+			})
+		}
+	}
+	return resources, nil
+}
+
+// listCombatLogs lists the log of each campaign's active combat
+func (r *GameResources) listCombatLogs(ctx context.Context) ([]mcp.Resource, error) {
+	campaigns, err := r.campaignService.ListCampaigns(ctx, &service.ListCampaignsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]mcp.Resource, 0)
+	for _, campaign := range campaigns {
+		combat, err := r.combatService.GetActiveCombat(ctx, campaign.ID)
+		if err != nil || combat == nil {
+			continue
+		}
+		resources = append(resources, mcp.Resource{
+			URI:      expand(CombatLogURI, combat.ID, ""),
+			Name:     campaign.Name + " combat log",
+			MIMEType: mimeJSON,
+		})
+	}
+	return resources, nil
+}
+
+// jsonContents encodes a value as a single JSON resource contents
+func jsonContents(uri string, v interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource: %w", err)
+	}
+	return []mcp.ResourceContents{{URI: uri, MIMEType: mimeJSON, Text: string(data)}}, nil
+}
+
+// notFound reports a failed lookup of the addressed record as a missing resource
+func notFound(err error) error {
+	return fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+}
+
+// expand fills the {id} and {cid}/{mid} variables of a URI template
+func expand(template, id, child string) string {
+	return mcp.ExpandURITemplate(template, map[string]string{"id": id, "cid": child, "mid": child})
+}
+
+// characterDescription summarizes a character for resource listings
+func characterDescription(character *models.Character) string {
+	if character.IsNPC {
+		// Redacted NPCs carry no stats
+		if character.Class == "" {
+			return "NPC"
+		}
+		return fmt.Sprintf("NPC, level %d %s", character.Level, character.Class)
+	}
+	return fmt.Sprintf("Level %d %s %s", character.Level, character.Race, character.Class)
+}
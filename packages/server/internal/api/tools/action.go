@@ -0,0 +1,279 @@
+// Package tools provides MCP tool implementations
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/service"
+)
+
+// turnActionSchema builds the schema shared by dash/dodge/disengage
+func turnActionSchema(allowBonusAction bool) mcp.InputSchema {
+	props := map[string]mcp.Property{
+		"combat_id":    mcp.StringProp("The ID of the combat encounter (required)"),
+		"character_id": mcp.StringProp("The ID of the acting character; must be their turn (required)"),
+	}
+	if allowBonusAction {
+		props["use_bonus_action"] = mcp.BoolProp("Spend the bonus action instead of the action (e.g. Cunning Action)")
+	}
+	return mcp.NewObjectSchema(props, mcp.Required("combat_id", "character_id"))
+}
+
+// turnActionHandler builds a handler for the simple turn actions
+func (t *CombatTools) turnActionHandler(name string, action func(context.Context, *service.TurnActionRequest) (*service.TurnActionResponse, error)) mcp.ToolHandler {
+	return func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CombatID       string `json:"combat_id"`
+			CharacterID    string `json:"character_id"`
+			UseBonusAction bool   `json:"use_bonus_action"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		resp, err := action(ctx, &service.TurnActionRequest{
+			CombatID:       input.CombatID,
+			CharacterID:    input.CharacterID,
+			UseBonusAction: input.UseBonusAction,
+		})
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"character_id": input.CharacterID,
+			"actions":      resp.Actions,
+			"message":      fmt.Sprintf("Character %s takes the %s action. Movement remaining: %d feet.", input.CharacterID, name, resp.Actions.Movement),
+		})
+	}
+}
+
+// dashTool implements the dash tool
+func (t *CombatTools) dashTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"dash",
+		"Take the Dash action: gain extra movement equal to your speed for the current turn. Consumes the action (or bonus action).",
+		turnActionSchema(true),
+	)
+	return tool, t.turnActionHandler("Dash", t.combatService.Dash)
+}
+
+// dodgeTool implements the dodge tool
+func (t *CombatTools) dodgeTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"dodge",
+		"Take the Dodge action: until the start of your next turn, attacks against you have disadvantage. Consumes the action.",
+		turnActionSchema(false),
+	)
+	return tool, t.turnActionHandler("Dodge", t.combatService.Dodge)
+}
+
+// disengageTool implements the disengage tool
+func (t *CombatTools) disengageTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"disengage",
+		"Take the Disengage action: your movement doesn't provoke opportunity attacks for the rest of the turn. Consumes the action (or bonus action).",
+		turnActionSchema(true),
+	)
+	return tool, t.turnActionHandler("Disengage", t.combatService.Disengage)
+}
+
+// helpTool implements the help tool
+func (t *CombatTools) helpTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"help",
+		"Take the Help action: an ally gains advantage on their next attack (optionally against a specific target) before the start of your next turn. Consumes the action.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"combat_id":    mcp.StringProp("The ID of the combat encounter (required)"),
+				"character_id": mcp.StringProp("The ID of the helping character; must be their turn (required)"),
+				"ally_id":      mcp.StringProp("The ID of the ally who gains advantage (required)"),
+				"target_id":    mcp.StringProp("The ID of the enemy the ally's attack must target (optional)"),
+			},
+			mcp.Required("combat_id", "character_id", "ally_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CombatID    string `json:"combat_id"`
+			CharacterID string `json:"character_id"`
+			AllyID      string `json:"ally_id"`
+			TargetID    string `json:"target_id"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		resp, err := t.combatService.Help(ctx, &service.HelpRequest{
+			CombatID:    input.CombatID,
+			CharacterID: input.CharacterID,
+			AllyID:      input.AllyID,
+			TargetID:    input.TargetID,
+		})
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"character_id": input.CharacterID,
+			"actions":      resp.Actions,
+			"message":      fmt.Sprintf("Character %s helps %s, granting advantage on their next attack.", input.CharacterID, input.AllyID),
+		})
+	}
+
+	return tool, handler
+}
+
+// hideTool implements the hide tool
+func (t *CombatTools) hideTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"hide",
+		"Take the Hide action: roll a Dexterity (Stealth) check. If it meets the DC (usually the highest passive Perception among enemies), the character is hidden and their next attack has advantage. Consumes the action (or bonus action).",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"combat_id":        mcp.StringProp("The ID of the combat encounter (required)"),
+				"character_id":     mcp.StringProp("The ID of the hiding character; must be their turn (required)"),
+				"dc":               mcp.IntProp("The DC to beat, e.g. the highest enemy passive Perception (optional)"),
+				"use_bonus_action": mcp.BoolProp("Spend the bonus action instead of the action (e.g. Cunning Action)"),
+			},
+			mcp.Required("combat_id", "character_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CombatID       string `json:"combat_id"`
+			CharacterID    string `json:"character_id"`
+			DC             int    `json:"dc"`
+			UseBonusAction bool   `json:"use_bonus_action"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		resp, err := t.combatService.Hide(ctx, &service.HideRequest{
+			CombatID:       input.CombatID,
+			CharacterID:    input.CharacterID,
+			DC:             input.DC,
+			UseBonusAction: input.UseBonusAction,
+		})
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		message := fmt.Sprintf("Stealth check %d: character %s is hidden.", resp.Check.DiceResult.Total, input.CharacterID)
+		if !resp.Hidden {
+			message = fmt.Sprintf("Stealth check %d: character %s fails to hide.", resp.Check.DiceResult.Total, input.CharacterID)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"character_id": input.CharacterID,
+			"hidden":       resp.Hidden,
+			"stealth_roll": resp.Check.DiceResult.Total,
+			"actions":      resp.Actions,
+			"message":      message,
+		})
+	}
+
+	return tool, handler
+}
+
+// readyTool implements the ready tool
+func (t *CombatTools) readyTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"ready",
+		"Take the Ready action: choose a trigger and an action to perform with your reaction when it occurs, before the start of your next turn. Consumes the action.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"combat_id":    mcp.StringProp("The ID of the combat encounter (required)"),
+				"character_id": mcp.StringProp("The ID of the readying character; must be their turn (required)"),
+				"trigger":      mcp.StringProp("The perceivable circumstance that triggers the action (required)"),
+				"action":       mcp.StringProp("The action to take when triggered (required)"),
+			},
+			mcp.Required("combat_id", "character_id", "trigger", "action"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CombatID    string `json:"combat_id"`
+			CharacterID string `json:"character_id"`
+			Trigger     string `json:"trigger"`
+			Action      string `json:"action"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		resp, err := t.combatService.Ready(ctx, &service.ReadyRequest{
+			CombatID:    input.CombatID,
+			CharacterID: input.CharacterID,
+			Trigger:     input.Trigger,
+			Action:      input.Action,
+		})
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"character_id": input.CharacterID,
+			"actions":      resp.Actions,
+			"message":      fmt.Sprintf("Character %s readies: %s (trigger: %s).", input.CharacterID, input.Action, input.Trigger),
+		})
+	}
+
+	return tool, handler
+}
+
+// opportunityAttackTool implements the opportunity_attack tool
+func (t *CombatTools) opportunityAttackTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"opportunity_attack",
+		"Make an opportunity attack with a player character's reaction against a creature that moved out of their reach this turn (offered in move_token's opportunity_attacks). Makes one melee attack with the character's longest-reach melee weapon. NPC opportunity attacks are resolved automatically when the creature moves; the Disengage action prevents them.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"combat_id":   mcp.StringProp("The ID of the combat encounter (required)"),
+				"attacker_id": mcp.StringProp("The ID of the character taking the opportunity attack (required)"),
+				"target_id":   mcp.StringProp("The ID of the creature that left the attacker's reach (required)"),
+			},
+			mcp.Required("combat_id", "attacker_id", "target_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CombatID   string `json:"combat_id"`
+			AttackerID string `json:"attacker_id"`
+			TargetID   string `json:"target_id"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		resp, err := t.combatService.OpportunityAttack(ctx, &service.OpportunityAttackRequest{
+			CombatID:   input.CombatID,
+			AttackerID: input.AttackerID,
+			TargetID:   input.TargetID,
+		})
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"result":               attackResultMap(resp.Result),
+			"concentration_checks": resp.ConcentrationChecks,
+			"message":              "Opportunity attack: " + attackMessage(resp.Result) + concentrationSummary(resp.ConcentrationChecks),
+		})
+	}
+
+	return tool, handler
+}
@@ -0,0 +1,133 @@
+// Package tools provides MCP tool implementations
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/archive"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/service"
+)
+
+// ArchiveTools provides campaign export and import MCP tools
+type ArchiveTools struct {
+	archiveService *service.ArchiveService
+}
+
+// NewArchiveTools creates a new ArchiveTools instance
+func NewArchiveTools(archiveService *service.ArchiveService) *ArchiveTools {
+	return &ArchiveTools{
+		archiveService: archiveService,
+	}
+}
+
+// Register registers all archive tools with the registry
+func (t *ArchiveTools) Register(registry *mcp.Registry) {
+	registry.MustRegister(t.exportCampaignTool())
+	registry.MustRegister(t.importCampaignTool())
+}
+
+// Tool definitions
+
+func (t *ArchiveTools) exportCampaignTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"export_campaign",
+		"Export a campaign as a portable archive: a Base64-encoded zip holding the campaign, its game state, characters, maps (with walls, tokens, lights and images), combat history and messages as JSON files. Pass the archive to import_campaign on this or another server.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"campaign_id": mcp.StringProp("The unique ID of the campaign to export (required)"),
+			},
+			mcp.Required("campaign_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CampaignID string `json:"campaign_id"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		resp, err := t.archiveService.ExportCampaign(ctx, input.CampaignID)
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"archive":  base64.StdEncoding.EncodeToString(resp.Archive),
+			"encoding": "base64",
+			"manifest": resp.Manifest,
+			"message": fmt.Sprintf("Exported campaign '%s': %s (%d bytes)",
+				resp.Manifest.CampaignName, archiveSummary(resp.Manifest), len(resp.Archive)),
+		})
+	}
+
+	return tool, handler
+}
+
+func (t *ArchiveTools) importCampaignTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"import_campaign",
+		"Import a campaign archive produced by export_campaign as a new campaign. Every record gets a new ID, so an archive can be imported next to its original or more than once. Archives from older server versions are upgraded automatically.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"data":  mcp.StringProp("The Base64-encoded campaign archive (required)"),
+				"dm_id": mcp.StringProp("The DM of the imported campaign (default: the caller when a DM, otherwise the archive's DM)"),
+				"name":  mcp.StringProp("A new name for the imported campaign (default: the archive's name)"),
+			},
+			mcp.Required("data"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			Data string `json:"data"`
+			DMID string `json:"dm_id"`
+			Name string `json:"name"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		data, err := base64.StdEncoding.DecodeString(input.Data)
+		if err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("failed to decode base64 data: %w", err))
+		}
+
+		resp, err := t.archiveService.ImportCampaign(ctx, &service.ImportCampaignRequest{
+			Archive: data,
+			DMID:    input.DMID,
+			Name:    input.Name,
+		})
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"campaign": resp.Campaign,
+			"manifest": resp.Manifest,
+			"message": fmt.Sprintf("Imported campaign '%s' with ID: %s (%s)",
+				resp.Campaign.Name, resp.Campaign.ID, archiveSummary(resp.Manifest)),
+		})
+	}
+
+	return tool, handler
+}
+
+// archiveSummary counts the records in an archive
+func archiveSummary(manifest *archive.Manifest) string {
+	return fmt.Sprintf("%d characters, %d maps, %d combats",
+		manifest.Count(archive.KindCharacter), manifest.Count(archive.KindMap), manifest.Count(archive.KindCombat))
+}
+
+// Tool list for external registration
+var ArchiveToolNames = []string{
+	"export_campaign",
+	"import_campaign",
+}
@@ -0,0 +1,166 @@
+// Package tools provides MCP tool implementations
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/dnd-mcp/server/pkg/logger"
+)
+
+// unaudited lists tools whose calls are never recorded: the audit tools themselves,
+// and campaign creation, import, forking and deletion, which change records the audit log cannot restore
+var unaudited = map[string]bool{
+	"list_actions":     true,
+	"undo_last_action": true,
+	"create_campaign":  true,
+	"import_campaign":  true,
+	"fork_campaign":    true,
+	"delete_campaign":  true,
+}
+
+// AuditTools provides the audit log and undo MCP tools
+type AuditTools struct {
+	auditService *service.AuditService
+}
+
+// NewAuditTools creates a new AuditTools instance
+func NewAuditTools(auditService *service.AuditService) *AuditTools {
+	return &AuditTools{
+		auditService: auditService,
+	}
+}
+
+// Register registers all audit tools with the registry and records every mutating tool call
+func (t *AuditTools) Register(registry *mcp.Registry) {
+	registry.MustRegister(t.listActionsTool())
+	registry.MustRegister(t.undoLastActionTool())
+	registry.Use(t.Middleware())
+}
+
+// Middleware records the characters, combats, maps and game states each successful
+// tool call changed. Read-only tools are skipped.
+func (t *AuditTools) Middleware() mcp.ToolMiddleware {
+	return func(ctx context.Context, req mcp.ToolRequest, next mcp.ToolHandler) mcp.ToolResponse {
+		if unaudited[req.ToolName] || ToolAccess[req.ToolName] == mcp.AccessRead {
+			return next(ctx, req)
+		}
+
+		journal := store.NewJournal()
+		resp := next(store.WithJournal(ctx, journal), req)
+		if resp.IsError {
+			return resp
+		}
+
+		// The call already succeeded; a failure to record it must not turn it into an error
+		if _, err := t.auditService.Record(ctx, req.ToolName, req.Arguments, journal.Revisions()); err != nil {
+			logger.Error("failed to audit tool call", "tool", req.ToolName, "error", err)
+		}
+		return resp
+	}
+}
+
+// listActionsTool implements the list_actions tool
+func (t *AuditTools) listActionsTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"list_actions",
+		"List the most recent state-changing actions in a campaign, newest first. Each action shows who called which tool with what arguments, and which fields of which characters, combats, maps and game state it changed. Use the action IDs with undo_last_action.",
+		func(ctx context.Context, input *service.ListActionsRequest) mcp.ToolResponse {
+			entries, err := t.auditService.ListActions(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+
+			actions := make([]map[string]interface{}, len(entries))
+			for i, entry := range entries {
+				actions[i] = auditEntryMap(entry)
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"actions": actions,
+				"count":   len(actions),
+			})
+		},
+	)
+}
+
+// undoLastActionTool implements the undo_last_action tool
+func (t *AuditTools) undoLastActionTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"undo_last_action",
+		"Undo the most recent state-changing action in a campaign (or the last count actions, or one action_id from list_actions), restoring the characters, combats, maps and game state it changed. All actions are reverted together or not at all. Refused if a later action or an edit outside the tools changed the same records since.",
+		func(ctx context.Context, input *service.UndoActionRequest) mcp.ToolResponse {
+			resp, err := t.auditService.UndoActions(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+
+			undone := make([]map[string]interface{}, len(resp.Undone))
+			for i, entry := range resp.Undone {
+				undone[i] = auditEntryMap(entry)
+			}
+
+			message := fmt.Sprintf("Undid action #%d (%s).", resp.Undone[0].ID, resp.Undone[0].Tool)
+			if len(resp.Undone) > 1 {
+				message = fmt.Sprintf("Undid %d actions, back to before action #%d (%s).",
+					len(resp.Undone), resp.Undone[len(resp.Undone)-1].ID, resp.Undone[len(resp.Undone)-1].Tool)
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"undone":  undone,
+				"message": message,
+			})
+		},
+	)
+}
+
+// auditEntryMap converts an audit entry to a response map with a per-field diff of each change
+func auditEntryMap(entry *models.AuditEntry) map[string]interface{} {
+	changes := make([]map[string]interface{}, len(entry.Changes))
+	for i, change := range entry.Changes {
+		changeMap := map[string]interface{}{
+			"entity":    change.Entity,
+			"entity_id": change.EntityID,
+			"summary":   summarizeChange(change),
+		}
+		if !change.Created() && !change.Deleted() {
+			changeMap["diff"] = change.Diff()
+		}
+		changes[i] = changeMap
+	}
+
+	result := map[string]interface{}{
+		"id":         entry.ID,
+		"tool":       entry.Tool,
+		"arguments":  entry.Arguments,
+		"changes":    changes,
+		"created_at": entry.CreatedAt,
+		"undone":     entry.IsUndone(),
+	}
+	if entry.ActorID != "" {
+		result["actor"] = map[string]interface{}{"user_id": entry.ActorID, "role": entry.ActorRole}
+	}
+	return result
+}
+
+// summarizeChange describes in one line how an action changed a record
+func summarizeChange(change models.AuditChange) string {
+	switch {
+	case change.Created():
+		return fmt.Sprintf("created %s %s", change.Entity, change.EntityID)
+	case change.Deleted():
+		return fmt.Sprintf("deleted %s %s", change.Entity, change.EntityID)
+	}
+
+	diffs := change.Diff()
+	paths := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		paths = append(paths, diff.Path)
+	}
+	return fmt.Sprintf("updated %s %s: %s", change.Entity, change.EntityID, strings.Join(paths, ", "))
+}
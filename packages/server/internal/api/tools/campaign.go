@@ -28,8 +28,13 @@ func (t *CampaignTools) Register(registry *mcp.Registry) {
 	registry.MustRegister(t.createCampaignTool())
 	registry.MustRegister(t.getCampaignTool())
 	registry.MustRegister(t.listCampaignsTool())
+	registry.MustRegister(t.updateCampaignTool())
 	registry.MustRegister(t.deleteCampaignTool())
 	registry.MustRegister(t.getCampaignSummaryTool())
+	registry.MustRegister(t.createSnapshotTool())
+	registry.MustRegister(t.listSnapshotsTool())
+	registry.MustRegister(t.restoreSnapshotTool())
+	registry.MustRegister(t.forkCampaignTool())
 }
 
 // Tool definitions
@@ -43,7 +48,7 @@ func (t *CampaignTools) createCampaignTool() (mcp.Tool, mcp.ToolHandler) {
 				"name":        mcp.StringProp("The name of the campaign (required)"),
 				"description": mcp.StringProp("A description of the campaign setting and theme"),
 				"dm_id":       mcp.StringProp("The DM (Dungeon Master) user ID (required)"),
-				"settings": mcp.ObjectProp("Campaign settings including max_players, start_level, ruleset, house_rules, context_window"),
+				"settings": mcp.ObjectProp("Campaign settings including max_players, start_level, ruleset, house_rules, context_window and prompts (per-prompt template overrides keyed by prompt name)"),
 			},
 			mcp.Required("name", "dm_id"),
 		),
@@ -183,6 +188,60 @@ func (t *CampaignTools) listCampaignsTool() (mcp.Tool, mcp.ToolHandler) {
 	return tool, handler
 }
 
+func (t *CampaignTools) updateCampaignTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"update_campaign",
+		"Update a campaign's name, description, status or settings. Only the fields provided are changed; settings are merged into the existing ones.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"campaign_id": mcp.StringProp("The unique ID of the campaign to update (required)"),
+				"name":        mcp.StringProp("New campaign name"),
+				"description": mcp.StringProp("New campaign description"),
+				"status": mcp.PropWithEnum(
+					"New campaign status",
+					string(models.CampaignStatusActive), string(models.CampaignStatusPaused),
+					string(models.CampaignStatusFinished), string(models.CampaignStatusArchived),
+				),
+				"settings": mcp.ObjectProp("Settings to change: max_players, start_level, ruleset, house_rules, context_window and prompts"),
+			},
+			mcp.Required("campaign_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CampaignID  string                         `json:"campaign_id"`
+			Name        *string                        `json:"name"`
+			Description *string                        `json:"description"`
+			Status      *models.CampaignStatus         `json:"status"`
+			Settings    *service.CampaignSettingsInput `json:"settings"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		updateReq := &service.UpdateCampaignRequest{
+			Name:        input.Name,
+			Description: input.Description,
+			Settings:    input.Settings,
+			Status:      input.Status,
+		}
+
+		campaign, err := t.campaignService.UpdateCampaign(ctx, input.CampaignID, updateReq)
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"campaign": campaign,
+			"message":  fmt.Sprintf("Campaign '%s' updated", campaign.Name),
+		})
+	}
+
+	return tool, handler
+}
+
 func (t *CampaignTools) deleteCampaignTool() (mcp.Tool, mcp.ToolHandler) {
 	tool := mcp.NewTool(
 		"delete_campaign",
@@ -258,6 +317,11 @@ var CampaignToolNames = []string{
 	"create_campaign",
 	"get_campaign",
 	"list_campaigns",
+	"update_campaign",
 	"delete_campaign",
 	"get_campaign_summary",
+	"create_snapshot",
+	"list_snapshots",
+	"restore_snapshot",
+	"fork_campaign",
 }
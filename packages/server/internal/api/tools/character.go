@@ -30,6 +30,9 @@ func (t *CharacterTools) Register(registry *mcp.Registry) {
 	registry.MustRegister(t.updateCharacterTool())
 	registry.MustRegister(t.listCharactersTool())
 	registry.MustRegister(t.deleteCharacterTool())
+	registry.MustRegister(t.deathSaveTool())
+	registry.MustRegister(t.stabilizeTool())
+	registry.MustRegister(t.getEncumbranceTool())
 }
 
 // Tool definitions
@@ -50,11 +53,11 @@ func (t *CharacterTools) createCharacterTool() (mcp.Tool, mcp.ToolHandler) {
 				"player_id":  mcp.StringProp("The player's user ID (required for player characters)"),
 				"race":       mcp.StringProp("The character's race (required)"),
 				"class":      mcp.StringProp("The character's class (required)"),
-				"level":      mcp.IntProp("The character's level (default: 1, range: 1-20)"),
+				"level":      mcp.IntRange("The character's level (default: 1, range: 1-20)", 1, 20),
 				"background": mcp.StringProp("The character's background"),
 				"alignment":  mcp.StringProp("The character's alignment (e.g., 'Lawful Good')"),
-				"abilities":  mcp.ObjectProp("Ability scores: strength, dexterity, constitution, intelligence, wisdom, charisma (default: standard array 15,14,13,12,10,8)"),
-				"hp":         mcp.ObjectProp("HP values: current, max, temp"),
+				"abilities":  abilitiesProp("Ability scores: strength, dexterity, constitution, intelligence, wisdom, charisma (default: standard array 15,14,13,12,10,8)"),
+				"hp":         hpProp("HP values: current, max, temp"),
 				"ac":         mcp.IntProp("Armor Class"),
 				"speed":      mcp.IntProp("Movement speed in feet (default: 30)"),
 				"initiative": mcp.IntProp("Initiative bonus"),
@@ -151,7 +154,7 @@ func (t *CharacterTools) getCharacterTool() (mcp.Tool, mcp.ToolHandler) {
 		}
 
 		return mcp.NewJSONResponse(map[string]interface{}{
-			"character": character,
+			"character": service.RedactCharacter(ctx, character),
 		})
 	}
 
@@ -168,11 +171,11 @@ func (t *CharacterTools) updateCharacterTool() (mcp.Tool, mcp.ToolHandler) {
 				"name":         mcp.StringProp("New character name"),
 				"race":         mcp.StringProp("New race"),
 				"class":        mcp.StringProp("New class"),
-				"level":        mcp.IntProp("New level (1-20)"),
+				"level":        mcp.IntRange("New level (1-20)", 1, 20),
 				"background":   mcp.StringProp("New background"),
 				"alignment":    mcp.StringProp("New alignment"),
-				"abilities":    mcp.ObjectProp("New ability scores"),
-				"hp":           mcp.ObjectProp("New HP values (current, max, temp)"),
+				"abilities":    abilitiesProp("New ability scores"),
+				"hp":           hpProp("New HP values (current, max, temp)"),
 				"ac":           mcp.IntProp("New Armor Class"),
 				"speed":        mcp.IntProp("New movement speed"),
 				"initiative":   mcp.IntProp("New initiative bonus"),
@@ -282,6 +285,7 @@ func (t *CharacterTools) listCharactersTool() (mcp.Tool, mcp.ToolHandler) {
 		if err != nil {
 			return mcp.NewErrorResponse(err)
 		}
+		characters = service.RedactCharacters(ctx, characters)
 
 		// Return simplified summaries
 		summaries := make([]map[string]interface{}, len(characters))
@@ -361,6 +365,97 @@ func (t *CharacterTools) deleteCharacterTool() (mcp.Tool, mcp.ToolHandler) {
 	return tool, handler
 }
 
+// deathSaveTool implements the death_save tool
+func (t *CharacterTools) deathSaveTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"death_save",
+		"Make a death saving throw for a character at 0 HP. Rolls a d20 unless the player's own roll is given: 10 or higher succeeds, a natural 1 counts as two failures, and a natural 20 restores 1 HP. Three successes stabilize the character; three failures kill them. In combat, end_turn rolls this automatically when a dying player character's turn starts.",
+		func(ctx context.Context, input *service.DeathSaveRequest) mcp.ToolResponse {
+			resp, err := t.characterService.MakeDeathSave(ctx, input.CharacterID, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"death_save": resp,
+				"message":    resp.Summary(),
+			})
+		},
+	)
+}
+
+func (t *CharacterTools) stabilizeTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"stabilize",
+		"Stabilize a character at 0 HP, e.g. after a successful DC 10 Wisdom (Medicine) check or a healer's kit. The character stops making death saving throws but stays unconscious until healed.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"character_id": mcp.StringProp("The ID of the dying character (required)"),
+			},
+			mcp.Required("character_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CharacterID string `json:"character_id"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		character, err := t.characterService.StabilizeCharacter(ctx, input.CharacterID)
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"character": character,
+			"message":   fmt.Sprintf("%s is stable", character.Name),
+		})
+	}
+
+	return tool, handler
+}
+
+func (t *CharacterTools) getEncumbranceTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"get_encumbrance",
+		"Get how much a character is carrying compared to their carrying capacity (Strength x 15 lb). With use_variant_rules=true, applies the variant encumbrance speed penalties.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"character_id":      mcp.StringProp("The ID of the character (required)"),
+				"use_variant_rules": mcp.BoolProp("Use the variant encumbrance rules (default: false)"),
+			},
+			mcp.Required("character_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CharacterID     string `json:"character_id"`
+			UseVariantRules bool   `json:"use_variant_rules"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		encumbrance, err := t.characterService.GetEncumbrance(ctx, input.CharacterID, input.UseVariantRules)
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"encumbrance": encumbrance,
+			"message":     fmt.Sprintf("Carrying %.1f of %d lb (%s)", encumbrance.Carried, encumbrance.Capacity, encumbrance.Level),
+		})
+	}
+
+	return tool, handler
+}
+
 // Tool list for external registration
 var CharacterToolNames = []string{
 	"create_character",
@@ -368,4 +463,31 @@ var CharacterToolNames = []string{
 	"update_character",
 	"list_characters",
 	"delete_character",
+	"death_save",
+	"stabilize",
+	"get_encumbrance",
+}
+
+// abilitiesProp describes a set of ability scores
+func abilitiesProp(desc string) mcp.Property {
+	score := func(name string) mcp.Property {
+		return mcp.IntRange(name+" score (1-30)", 1, 30)
+	}
+	return mcp.ObjectOf(desc, map[string]mcp.Property{
+		"strength":     score("Strength"),
+		"dexterity":    score("Dexterity"),
+		"constitution": score("Constitution"),
+		"intelligence": score("Intelligence"),
+		"wisdom":       score("Wisdom"),
+		"charisma":     score("Charisma"),
+	}, nil)
+}
+
+// hpProp describes a character's hit points
+func hpProp(desc string) mcp.Property {
+	return mcp.ObjectOf(desc, map[string]mcp.Property{
+		"current": mcp.IntProp("Current hit points"),
+		"max":     mcp.IntMin("Maximum hit points", 1),
+		"temp":    mcp.IntMin("Temporary hit points", 0),
+	}, nil)
 }
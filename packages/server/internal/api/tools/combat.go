@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/dnd-mcp/server/internal/mcp"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
 	"github.com/dnd-mcp/server/internal/service"
 )
 
@@ -30,6 +32,13 @@ func (t *CombatTools) Register(registry *mcp.Registry) {
 	registry.MustRegister(t.castSpellTool())
 	registry.MustRegister(t.endTurnTool())
 	registry.MustRegister(t.endCombatTool())
+	registry.MustRegister(t.dashTool())
+	registry.MustRegister(t.dodgeTool())
+	registry.MustRegister(t.disengageTool())
+	registry.MustRegister(t.helpTool())
+	registry.MustRegister(t.hideTool())
+	registry.MustRegister(t.readyTool())
+	registry.MustRegister(t.opportunityAttackTool())
 }
 
 // startCombatTool implements the start_combat tool
@@ -40,7 +49,7 @@ func (t *CombatTools) startCombatTool() (mcp.Tool, mcp.ToolHandler) {
 		mcp.NewObjectSchema(
 			map[string]mcp.Property{
 				"campaign_id": mcp.StringProp("The ID of the campaign (required)"),
-				"participant_ids": mcp.ArrayProp("List of character IDs participating in combat (required)"),
+				"participant_ids": mcp.ArrayOf("List of character IDs participating in combat (required)", mcp.StringProp("Character ID")),
 				"map_id": mcp.StringProp("Optional map ID for the combat encounter"),
 			},
 			mcp.Required("campaign_id", "participant_ids"),
@@ -100,7 +109,7 @@ func (t *CombatTools) startCombatTool() (mcp.Tool, mcp.ToolHandler) {
 func (t *CombatTools) getCombatStateTool() (mcp.Tool, mcp.ToolHandler) {
 	tool := mcp.NewTool(
 		"get_combat_state",
-		"Get the current state of a combat encounter, including round, turn, participants order, and combat log.",
+		"Get the current state of a combat encounter, including round, turn, participants order, each participant's remaining action/bonus action/reaction/movement, and combat log.",
 		mcp.NewObjectSchema(
 			map[string]mcp.Property{
 				"combat_id": mcp.StringProp("The unique ID of the combat encounter (required)"),
@@ -131,6 +140,8 @@ func (t *CombatTools) getCombatStateTool() (mcp.Tool, mcp.ToolHandler) {
 				"initiative":   p.Initiative,
 				"has_acted":    p.HasActed,
 				"conditions":   p.Conditions,
+				"actions":      p.Actions,
+				"hidden":       p.Hidden,
 			}
 		}
 
@@ -153,108 +164,145 @@ func (t *CombatTools) getCombatStateTool() (mcp.Tool, mcp.ToolHandler) {
 
 // attackTool implements the attack tool
 func (t *CombatTools) attackTool() (mcp.Tool, mcp.ToolHandler) {
-	tool := mcp.NewTool(
+	return mcp.TypedTool(
 		"attack",
-		"Execute an attack in combat. Must be the attacker's turn. Rolls attack dice, determines hit/miss, calculates damage, and updates target HP.",
-		mcp.NewObjectSchema(
-			map[string]mcp.Property{
-				"combat_id":    mcp.StringProp("The ID of the combat encounter (required)"),
-				"attacker_id":  mcp.StringProp("The ID of the attacking character (required)"),
-				"target_id":    mcp.StringProp("The ID of the target character (required)"),
-				"advantage":    mcp.BoolProp("Roll with advantage (roll 2d20, take higher)"),
-				"disadvantage": mcp.BoolProp("Roll with disadvantage (roll 2d20, take lower)"),
-			},
-			mcp.Required("combat_id", "attacker_id", "target_id"),
-		),
+		"Take the Attack action in combat. Must be the attacker's turn and consumes their action. Makes every attack the action allows in one call: Extra Attack from class features, or a monster's Multiattack routine. Use weapon_id to pick a specific weapon or natural attack, count to split Extra Attack between targets, and off_hand for a two-weapon fighting bonus action attack. When the combat has a battle map, cover from walls and intervening creatures adds +2 (half) or +5 (three-quarters) to the target's AC, and targets with total cover cannot be attacked. Targets must be within the weapon's reach (5 feet, 10 for reach weapons) or range; attacks beyond normal range, or ranged attacks with a hostile creature within 5 feet, have disadvantage. Rolls attack dice, determines hit/miss, calculates damage, and updates target HP.",
+		func(ctx context.Context, attackReq *service.AttackRequest) mcp.ToolResponse {
+			resp, err := t.combatService.Attack(ctx, attackReq)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+
+			// Build result messages, one per attack
+			attacks := make([]map[string]interface{}, len(resp.Attacks))
+			messages := make([]string, len(resp.Attacks))
+			for i, result := range resp.Attacks {
+				attacks[i] = attackResultMap(result)
+				messages[i] = attackMessage(result)
+			}
+			message := strings.Join(messages, " ")
+			if len(resp.Attacks) > 1 {
+				message = fmt.Sprintf("%d attacks, %d total damage. %s", len(resp.Attacks), resp.TotalDamage, message)
+			}
+			if resp.AttacksRemaining > 0 {
+				message += fmt.Sprintf(" %d attacks remaining in this Attack action.", resp.AttacksRemaining)
+			}
+			for _, skipped := range resp.SkippedAttacks {
+				message += fmt.Sprintf(" Skipped %s.", skipped)
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"result":            attacks[0],
+				"attacks":           attacks,
+				"total_damage":      resp.TotalDamage,
+				"attacks_remaining": resp.AttacksRemaining,
+				"target_dead":       resp.TargetDead,
+				"skipped_attacks":   resp.SkippedAttacks,
+				"concentration_checks": resp.ConcentrationChecks,
+				"message":           message + concentrationSummary(resp.ConcentrationChecks),
+			})
+		},
 	)
+}
 
-	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
-		var input struct {
-			CombatID     string `json:"combat_id"`
-			AttackerID   string `json:"attacker_id"`
-			TargetID     string `json:"target_id"`
-			Advantage    bool   `json:"advantage"`
-			Disadvantage bool   `json:"disadvantage"`
+// concentrationSummary describes concentration saves and broken concentration
+func concentrationSummary(checks []*service.ConcentrationCheck) string {
+	var summary string
+	for _, check := range checks {
+		if check.Maintained {
+			summary += fmt.Sprintf(" %s maintains concentration on %s (DC %d).", check.CharacterID, check.SpellID, check.DC)
+			continue
 		}
-
-		if err := json.Unmarshal(req.Arguments, &input); err != nil {
-			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		summary += fmt.Sprintf(" %s loses concentration on %s: %s.", check.CharacterID, check.SpellID, check.Reason)
+		for _, removed := range check.RemovedConditions {
+			summary += fmt.Sprintf(" %s is no longer %s.", removed.CharacterID, removed.Condition)
 		}
+	}
+	return summary
+}
 
-		attackReq := &service.AttackRequest{
-			CombatID:     input.CombatID,
-			AttackerID:   input.AttackerID,
-			TargetID:     input.TargetID,
-			Advantage:    input.Advantage,
-			Disadvantage: input.Disadvantage,
-		}
+// attackResultMap converts a single attack result to its JSON representation
+func attackResultMap(result *rulescombat.AttackResult) map[string]interface{} {
+	return map[string]interface{}{
+		"hit":              result.Hit,
+		"crit":             result.Crit,
+		"attack_roll":      result.AttackRoll.Total,
+		"target_ac":        result.TargetAC,
+		"damage":           result.Damage,
+		"raw_damage":       result.RawDamage,
+		"damage_type":      result.DamageType,
+		"damage_breakdown": result.DamageBreakdown,
+		"target_hp":        result.TargetHP,
+		"target_down":      result.TargetDown,
+		"modifiers":        result.Modifiers,
+		"cover":            coverOrNone(result.Cover),
+		"cover_bonus":      result.CoverBonus,
+	}
+}
 
-		resp, err := t.combatService.Attack(ctx, attackReq)
-		if err != nil {
-			return mcp.NewErrorResponse(err)
-		}
+// coverOrNone reports a missing cover level as "none"
+func coverOrNone(cover rulescombat.CoverLevel) rulescombat.CoverLevel {
+	if cover == "" {
+		return rulescombat.CoverNone
+	}
+	return cover
+}
 
-		// Build result message
-		result := resp.Result
-		var message string
-		if result.Hit {
-			if result.Crit {
-				message = fmt.Sprintf("CRITICAL HIT! Attack roll %d vs AC %d, dealing %d %s damage!",
-					result.AttackRoll.Total, result.TargetAC, result.Damage, result.DamageType)
-			} else {
-				message = fmt.Sprintf("Hit! Attack roll %d vs AC %d, dealing %d %s damage.",
-					result.AttackRoll.Total, result.TargetAC, result.Damage, result.DamageType)
-			}
-			if result.TargetHP != nil {
-				message += fmt.Sprintf(" Target HP: %d/%d", result.TargetHP.Current, result.TargetHP.Max)
-			}
-			if result.TargetDown {
-				message += " Target is down!"
-			}
+// attackMessage builds a human readable description of a single attack
+func attackMessage(result *rulescombat.AttackResult) string {
+	var message string
+	if result.Hit {
+		if result.Crit {
+			message = fmt.Sprintf("CRITICAL HIT! Attack roll %d vs AC %d, dealing %d %s damage!",
+				result.AttackRoll.Total, result.TargetAC, result.Damage, result.DamageType)
 		} else {
-			if result.AttackRoll.IsFumble() {
-				message = fmt.Sprintf("FUMBLE! Natural 1 - Attack roll %d vs AC %d, miss!",
-					result.AttackRoll.Total, result.TargetAC)
-			} else {
-				message = fmt.Sprintf("Miss! Attack roll %d vs AC %d.", result.AttackRoll.Total, result.TargetAC)
-			}
+			message = fmt.Sprintf("Hit! Attack roll %d vs AC %d, dealing %d %s damage.",
+				result.AttackRoll.Total, result.TargetAC, result.Damage, result.DamageType)
+		}
+		if result.RawDamage != result.Damage {
+			message += fmt.Sprintf(" (%d before resistances)", result.RawDamage)
+		}
+		if result.TargetHP != nil {
+			message += fmt.Sprintf(" Target HP: %d/%d", result.TargetHP.Current, result.TargetHP.Max)
+		}
+		if result.TargetDown {
+			message += " Target is down!"
+		}
+	} else {
+		if result.AttackRoll.IsFumble() {
+			message = fmt.Sprintf("FUMBLE! Natural 1 - Attack roll %d vs AC %d, miss!",
+				result.AttackRoll.Total, result.TargetAC)
+		} else {
+			message = fmt.Sprintf("Miss! Attack roll %d vs AC %d.", result.AttackRoll.Total, result.TargetAC)
 		}
-
-		return mcp.NewJSONResponse(map[string]interface{}{
-			"result": map[string]interface{}{
-				"hit":         result.Hit,
-				"crit":        result.Crit,
-				"attack_roll": result.AttackRoll.Total,
-				"target_ac":   result.TargetAC,
-				"damage":      result.Damage,
-				"damage_type": result.DamageType,
-				"target_hp":   result.TargetHP,
-				"target_down": result.TargetDown,
-			},
-			"target_dead": resp.TargetDead,
-			"message":     message,
-		})
 	}
-
-	return tool, handler
+	if result.CoverBonus > 0 {
+		message += fmt.Sprintf(" Target has %s cover (+%d AC).", strings.ReplaceAll(string(result.Cover), "_", "-"), result.CoverBonus)
+	}
+	return message + modifierSummary(result.Modifiers)
 }
 
 // castSpellTool implements the cast_spell tool
 func (t *CombatTools) castSpellTool() (mcp.Tool, mcp.ToolHandler) {
 	tool := mcp.NewTool(
 		"cast_spell",
-		"Cast a spell in combat. Must be the caster's turn. Applies damage or healing to targets and updates their HP.",
+		"Cast a spell in combat. Must be the caster's turn. The spell is looked up in the caster's spellbook and must be known (and prepared, for preparing casters); a spell slot of the cast level is spent. Targets roll saving throws against the caster's spell save DC (half damage on success where the spell allows) or the caster makes a spell attack roll. Casting above the spell's level scales its dice.",
 		mcp.NewObjectSchema(
 			map[string]mcp.Property{
-				"combat_id":   mcp.StringProp("The ID of the combat encounter (required)"),
-				"caster_id":   mcp.StringProp("The ID of the caster character (required)"),
-				"spell_id":    mcp.StringProp("The ID of the spell to cast"),
-				"spell_name":  mcp.StringProp("The name of the spell to cast"),
-				"target_ids":  mcp.ArrayProp("List of target character IDs (required)"),
-				"level":       mcp.IntProp("The spell level to cast at (for upcasting)"),
-				"damage":      mcp.StringProp("Damage formula (e.g., '2d6', '3d8')"),
-				"damage_type": mcp.StringProp("Type of damage (e.g., 'fire', 'cold', 'necrotic')"),
-				"is_healing":  mcp.BoolProp("Whether this is a healing spell"),
+				"combat_id":    mcp.StringProp("The ID of the combat encounter (required)"),
+				"caster_id":    mcp.StringProp("The ID of the caster character (required)"),
+				"spell_id":     mcp.StringProp("The ID of the spell in the caster's spellbook"),
+				"spell_name":   mcp.StringProp("The name of the spell to cast (used if spell_id is not given)"),
+				"target_ids":   mcp.ArrayOf("List of target character IDs (required)", mcp.StringProp("Character ID")),
+				"level":        mcp.IntProp("The spell slot level to cast at (defaults to the spell's level; higher levels upcast)"),
+				"advantage":    mcp.BoolProp("Make the spell attack roll with advantage"),
+				"disadvantage": mcp.BoolProp("Make the spell attack roll with disadvantage"),
+				"damage":       mcp.StringProp("Damage formula (e.g., '2d6') - only used when the caster has no spellbook entry"),
+				"damage_type":  mcp.StringProp("Type of damage (e.g., 'fire') - only used when the caster has no spellbook entry"),
+				"is_healing":   mcp.BoolProp("Whether this is a healing spell - only used when the caster has no spellbook entry"),
+				"save_ability":  mcp.StringProp("Ability targets save with (e.g., 'wisdom') - only used when the caster has no spellbook entry"),
+				"concentration": mcp.BoolProp("Whether the spell requires concentration - only used when the caster has no spellbook entry"),
+				"conditions":    mcp.ArrayOf("Conditions applied on a failed save or hit (e.g., ['paralyzed']) - only used when the caster has no spellbook entry", mcp.StringProp("Condition name")),
 			},
 			mcp.Required("combat_id", "caster_id", "target_ids"),
 		),
@@ -262,15 +310,20 @@ func (t *CombatTools) castSpellTool() (mcp.Tool, mcp.ToolHandler) {
 
 	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
 		var input struct {
-			CombatID   string   `json:"combat_id"`
-			CasterID   string   `json:"caster_id"`
-			SpellID    string   `json:"spell_id"`
-			SpellName  string   `json:"spell_name"`
-			TargetIDs  []string `json:"target_ids"`
-			Level      int      `json:"level"`
-			Damage     string   `json:"damage"`
-			DamageType string   `json:"damage_type"`
-			IsHealing  bool     `json:"is_healing"`
+			CombatID     string   `json:"combat_id"`
+			CasterID     string   `json:"caster_id"`
+			SpellID      string   `json:"spell_id"`
+			SpellName    string   `json:"spell_name"`
+			TargetIDs    []string `json:"target_ids"`
+			Level        int      `json:"level"`
+			Advantage    bool     `json:"advantage"`
+			Disadvantage bool     `json:"disadvantage"`
+			Damage       string   `json:"damage"`
+			DamageType   string   `json:"damage_type"`
+			IsHealing    bool     `json:"is_healing"`
+			SaveAbility   string   `json:"save_ability"`
+			Concentration bool     `json:"concentration"`
+			Conditions    []string `json:"conditions"`
 		}
 
 		if err := json.Unmarshal(req.Arguments, &input); err != nil {
@@ -278,15 +331,20 @@ func (t *CombatTools) castSpellTool() (mcp.Tool, mcp.ToolHandler) {
 		}
 
 		castReq := &service.CastSpellRequest{
-			CombatID:   input.CombatID,
-			CasterID:   input.CasterID,
-			SpellID:    input.SpellID,
-			SpellName:  input.SpellName,
-			TargetIDs:  input.TargetIDs,
-			Level:      input.Level,
-			Damage:     input.Damage,
-			DamageType: input.DamageType,
-			IsHealing:  input.IsHealing,
+			CombatID:     input.CombatID,
+			CasterID:     input.CasterID,
+			SpellID:      input.SpellID,
+			SpellName:    input.SpellName,
+			TargetIDs:    input.TargetIDs,
+			Level:        input.Level,
+			Advantage:    input.Advantage,
+			Disadvantage: input.Disadvantage,
+			Damage:       input.Damage,
+			DamageType:   input.DamageType,
+			IsHealing:    input.IsHealing,
+			SaveAbility:   input.SaveAbility,
+			Concentration: input.Concentration,
+			Conditions:    input.Conditions,
 		}
 
 		resp, err := t.combatService.CastSpell(ctx, castReq)
@@ -307,30 +365,66 @@ func (t *CombatTools) castSpellTool() (mcp.Tool, mcp.ToolHandler) {
 		} else {
 			message = fmt.Sprintf("%s dealt %d %s damage", spellName, result.Damage, result.DamageType)
 		}
+		if result.Upcast {
+			message += fmt.Sprintf(" (upcast at level %d)", result.Level)
+		}
+		if result.SlotUsed {
+			message += fmt.Sprintf(". Level %d slots remaining: %d", result.Level, result.SlotsRemaining)
+		}
 
 		// Build target results
 		targetResults := make([]map[string]interface{}, len(result.Results))
 		for i, tr := range result.Results {
-			targetResults[i] = map[string]interface{}{
+			targetResult := map[string]interface{}{
 				"target_id": tr.TargetID,
 				"hit":       tr.Hit,
 				"damage":    tr.Damage,
 				"target_hp": tr.TargetHP,
 			}
+			if tr.AttackRoll != nil {
+				targetResult["attack_roll"] = tr.AttackRoll.Total
+				targetResult["crit"] = tr.Crit
+				targetResult["attack_modifiers"] = tr.AttackModifiers
+			}
+			if tr.SaveRoll != nil {
+				targetResult["save_roll"] = tr.SaveRoll.DiceResult.Total
+				targetResult["saved"] = tr.Saved
+				targetResult["save_modifiers"] = tr.SaveModifiers
+			}
+			if tr.Cover != "" {
+				targetResult["cover"] = tr.Cover
+			}
+			if len(tr.ConditionsApplied) > 0 {
+				targetResult["conditions_applied"] = tr.ConditionsApplied
+			}
+			if len(tr.DamageBreakdown) > 0 {
+				targetResult["raw_damage"] = tr.RawDamage
+				targetResult["damage_breakdown"] = tr.DamageBreakdown
+			}
+			targetResults[i] = targetResult
 		}
 
 		return mcp.NewJSONResponse(map[string]interface{}{
 			"result": map[string]interface{}{
-				"spell_id":     result.SpellID,
-				"spell_name":   result.SpellName,
-				"level":        result.Level,
-				"caster_id":    result.CasterID,
-				"damage":       result.Damage,
-				"damage_type":  result.DamageType,
-				"is_healing":   result.IsHealing,
-				"target_results": targetResults,
+				"spell_id":        result.SpellID,
+				"spell_name":      result.SpellName,
+				"level":           result.Level,
+				"spell_level":     result.SpellLevel,
+				"upcast":          result.Upcast,
+				"caster_id":       result.CasterID,
+				"slot_used":       result.SlotUsed,
+				"slots_remaining": result.SlotsRemaining,
+				"save_dc":         result.SaveDC,
+				"save_ability":    result.SaveAbility,
+				"attack_bonus":    result.AttackBonus,
+				"formula":         result.Formula,
+				"damage":          result.Damage,
+				"damage_type":     result.DamageType,
+				"is_healing":      result.IsHealing,
+				"target_results":  targetResults,
 			},
-			"message": message,
+			"concentration_checks": resp.ConcentrationChecks,
+			"message":              message + concentrationSummary(resp.ConcentrationChecks),
 		})
 	}
 
@@ -341,7 +435,7 @@ func (t *CombatTools) castSpellTool() (mcp.Tool, mcp.ToolHandler) {
 func (t *CombatTools) endTurnTool() (mcp.Tool, mcp.ToolHandler) {
 	tool := mcp.NewTool(
 		"end_turn",
-		"End the current character's turn and advance to the next participant. If this is the last participant, a new round begins. Also processes condition durations.",
+		"End the current character's turn and advance to the next participant. If this is the last participant, a new round begins. Also processes condition durations, and rolls the death saving throw of a dying player character whose turn starts.",
 		mcp.NewObjectSchema(
 			map[string]mcp.Property{
 				"combat_id": mcp.StringProp("The unique ID of the combat encounter (required)"),
@@ -373,6 +467,7 @@ func (t *CombatTools) endTurnTool() (mcp.Tool, mcp.ToolHandler) {
 				"initiative":   currentParticipant.Initiative,
 				"has_acted":    currentParticipant.HasActed,
 				"conditions":   currentParticipant.Conditions,
+				"actions":      currentParticipant.Actions,
 			}
 		}
 
@@ -387,7 +482,7 @@ func (t *CombatTools) endTurnTool() (mcp.Tool, mcp.ToolHandler) {
 			message += fmt.Sprintf("It is now character %s's turn.", currentParticipant.CharacterID)
 		}
 
-		return mcp.NewJSONResponse(map[string]interface{}{
+		result := map[string]interface{}{
 			"combat": map[string]interface{}{
 				"id":             resp.Combat.ID,
 				"round":          resp.Combat.Round,
@@ -397,8 +492,16 @@ func (t *CombatTools) endTurnTool() (mcp.Tool, mcp.ToolHandler) {
 				"participant_count": len(resp.Combat.Participants),
 			},
 			"current_turn_name": resp.CurrentTurnName,
-			"message":           message,
-		})
+		}
+
+		// Dying characters roll their death save as their turn starts
+		if resp.DeathSave != nil {
+			result["death_save"] = resp.DeathSave
+			message += " " + resp.DeathSave.Summary() + "."
+		}
+		result["message"] = message
+
+		return mcp.NewJSONResponse(result)
 	}
 
 	return tool, handler
@@ -412,6 +515,13 @@ var CombatToolNames = []string{
 	"cast_spell",
 	"end_turn",
 	"end_combat",
+	"dash",
+	"dodge",
+	"disengage",
+	"help",
+	"hide",
+	"ready",
+	"opportunity_attack",
 }
 
 // endCombatTool implements the end_combat tool
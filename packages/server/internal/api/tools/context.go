@@ -100,6 +100,9 @@ func (t *ContextTools) getRawContextTool() (mcp.Tool, mcp.ToolHandler) {
 		if err != nil {
 			return mcp.NewErrorResponse(err)
 		}
+		// Players and observers get the same view as get_character and get_battle_map
+		rawContext.Characters = service.RedactCharacters(ctx, rawContext.Characters)
+		rawContext.Map = service.RedactMap(ctx, rawContext.Map)
 
 		return mcp.NewJSONResponse(rawContext)
 	}
@@ -111,14 +114,15 @@ func (t *ContextTools) getRawContextTool() (mcp.Tool, mcp.ToolHandler) {
 func (t *ContextTools) saveMessageTool() (mcp.Tool, mcp.ToolHandler) {
 	tool := mcp.NewTool(
 		"save_message",
-		"Save a dialogue message to the campaign history. Supports user, assistant, and system messages.",
+		"Save a dialogue message to the campaign history. Supports user, assistant, system and tool messages.",
 		mcp.NewObjectSchema(
 			map[string]mcp.Property{
-				"campaign_id": mcp.StringProp("The campaign ID to save the message to (required)"),
-				"role":        mcp.StringProp("The message role: 'user', 'assistant', or 'system' (required)"),
-				"content":     mcp.StringProp("The message content (required)"),
-				"player_id":   mcp.StringProp("The player ID (required for user messages)"),
-				"tool_calls":  mcp.ObjectProp("Array of tool calls made by the assistant (optional, for assistant messages)"),
+				"campaign_id":  mcp.StringProp("The campaign ID to save the message to (required)"),
+				"role":         mcp.StringProp("The message role: 'user', 'assistant', 'system' or 'tool' (required)"),
+				"content":      mcp.StringProp("The message content (required)"),
+				"player_id":    mcp.StringProp("The player ID (required for user messages)"),
+				"tool_calls":   mcp.ArrayOf("Array of tool calls made by the assistant (optional, for assistant messages)", mcp.ObjectProp("A tool call with id, name and arguments")),
+				"tool_call_id": mcp.StringProp("The ID of the assistant tool call this message answers (required for tool messages)"),
 			},
 			mcp.Required("campaign_id", "role", "content"),
 		),
@@ -131,6 +135,7 @@ func (t *ContextTools) saveMessageTool() (mcp.Tool, mcp.ToolHandler) {
 			Content    string                  `json:"content"`
 			PlayerID   string                  `json:"player_id"`
 			ToolCalls  []map[string]interface{} `json:"tool_calls"`
+			ToolCallID string                  `json:"tool_call_id"`
 		}
 
 		if err := json.Unmarshal(req.Arguments, &input); err != nil {
@@ -140,6 +145,7 @@ func (t *ContextTools) saveMessageTool() (mcp.Tool, mcp.ToolHandler) {
 		// Create message
 		message := models.NewMessage(input.CampaignID, models.MessageRole(input.Role), input.Content)
 		message.PlayerID = input.PlayerID
+		message.ToolCallID = input.ToolCallID
 
 		// Convert tool calls if provided
 		if len(input.ToolCalls) > 0 {
@@ -3,10 +3,12 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
 	"github.com/dnd-mcp/server/internal/service"
 )
 
@@ -27,192 +29,163 @@ func (t *DiceTools) Register(registry *mcp.Registry) {
 	registry.MustRegister(t.rollDiceTool())
 	registry.MustRegister(t.rollCheckTool())
 	registry.MustRegister(t.rollSaveTool())
+	registry.MustRegister(t.rollAttackTool())
+	registry.MustRegister(t.rollDamageTool())
 }
 
 // rollDiceTool implements the roll_dice tool
 func (t *DiceTools) rollDiceTool() (mcp.Tool, mcp.ToolHandler) {
-	tool := mcp.NewTool(
+	return mcp.TypedTool(
 		"roll_dice",
 		"Roll dice using standard D&D notation. Supports formulas like '1d20+5', '2d6', '4d6kh3' (keep highest 3), '2d20kh1' (advantage), '2d20kl1' (disadvantage).",
-		mcp.NewObjectSchema(
-			map[string]mcp.Property{
-				"formula": mcp.StringProp("The dice formula to roll (e.g., '1d20+5', '2d6', '4d6kh3') (required)"),
-			},
-			mcp.Required("formula"),
-		),
-	)
-
-	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
-		var input struct {
-			Formula string `json:"formula"`
-		}
-
-		if err := json.Unmarshal(req.Arguments, &input); err != nil {
-			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
-		}
-
-		resp, err := t.diceService.RollDice(ctx, &service.RollDiceRequest{
-			Formula: input.Formula,
-		})
-		if err != nil {
-			return mcp.NewErrorResponse(err)
-		}
-
-		return mcp.NewJSONResponse(map[string]interface{}{
-			"result": resp.Result,
-		})
-	}
+		func(ctx context.Context, input *service.RollDiceRequest) mcp.ToolResponse {
+			resp, err := t.diceService.RollDice(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
 
-	return tool, handler
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"result": resp.Result,
+			})
+		},
+	)
 }
 
 // rollCheckTool implements the roll_check tool
 func (t *DiceTools) rollCheckTool() (mcp.Tool, mcp.ToolHandler) {
-	tool := mcp.NewTool(
+	return mcp.TypedTool(
 		"roll_check",
 		"Perform an ability or skill check for a character. Rolls d20, adds ability modifier and proficiency bonus if applicable. Supports advantage/disadvantage.",
-		mcp.NewObjectSchema(
-			map[string]mcp.Property{
-				"character_id":  mcp.StringProp("The ID of the character making the check (required)"),
-				"ability":       mcp.StringProp("The ability to use (strength, dexterity, constitution, intelligence, wisdom, charisma) (required)"),
-				"skill":         mcp.StringProp("Optional skill to apply proficiency bonus (e.g., athletics, stealth, perception)"),
-				"dc":            mcp.IntProp("Optional difficulty class (DC) to compare against"),
-				"advantage":     mcp.BoolProp("Roll with advantage (roll 2d20, take higher)"),
-				"disadvantage":  mcp.BoolProp("Roll with disadvantage (roll 2d20, take lower)"),
-			},
-			mcp.Required("character_id", "ability"),
-		),
-	)
-
-	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
-		var input struct {
-			CharacterID  string `json:"character_id"`
-			Ability      string `json:"ability"`
-			Skill        string `json:"skill"`
-			DC           int    `json:"dc"`
-			Advantage    bool   `json:"advantage"`
-			Disadvantage bool   `json:"disadvantage"`
-		}
-
-		if err := json.Unmarshal(req.Arguments, &input); err != nil {
-			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
-		}
-
-		resp, err := t.diceService.RollCheck(ctx, &service.RollCheckRequest{
-			CharacterID:  input.CharacterID,
-			Ability:      input.Ability,
-			Skill:        input.Skill,
-			DC:           input.DC,
-			Advantage:    input.Advantage,
-			Disadvantage: input.Disadvantage,
-		})
-		if err != nil {
-			return mcp.NewErrorResponse(err)
-		}
-
-		// Build response message
-		result := map[string]interface{}{
-			"result": resp.Result,
-		}
-
-		// Add human-readable message
-		msg := fmt.Sprintf("Rolled %s check", input.Ability)
-		if input.Skill != "" {
-			msg = fmt.Sprintf("Rolled %s (%s) check", input.Skill, input.Ability)
-		}
-		msg += fmt.Sprintf(": %d", resp.Result.DiceResult.Total)
-
-		if input.DC > 0 {
-			if resp.Result.Success {
-				msg += fmt.Sprintf(" vs DC %d - SUCCESS", input.DC)
-			} else {
-				msg += fmt.Sprintf(" vs DC %d - FAILURE", input.DC)
+		func(ctx context.Context, input *service.RollCheckRequest) mcp.ToolResponse {
+			resp, err := t.diceService.RollCheck(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
 			}
-		}
 
-		if resp.Result.DiceResult.IsCritical() {
-			msg += " [CRITICAL!]"
-		} else if resp.Result.DiceResult.IsFumble() {
-			msg += " [FUMBLE!]"
-		}
+			// Build response message
+			result := map[string]interface{}{
+				"result":    resp.Result,
+				"modifiers": resp.Modifiers,
+			}
 
-		result["message"] = msg
+			// Add human-readable message
+			msg := fmt.Sprintf("Rolled %s check", input.Ability)
+			if input.Skill != "" {
+				msg = fmt.Sprintf("Rolled %s (%s) check", input.Skill, input.Ability)
+			}
+			msg += fmt.Sprintf(": %d", resp.Result.DiceResult.Total)
+			msg += outcomeSummary(input.DC, resp.Result)
+			msg += modifierSummary(resp.Modifiers)
 
-		return mcp.NewJSONResponse(result)
-	}
+			result["message"] = msg
 
-	return tool, handler
+			return mcp.NewJSONResponse(result)
+		},
+	)
 }
 
 // rollSaveTool implements the roll_save tool
 func (t *DiceTools) rollSaveTool() (mcp.Tool, mcp.ToolHandler) {
-	tool := mcp.NewTool(
+	return mcp.TypedTool(
 		"roll_save",
 		"Perform a saving throw for a character. Rolls d20, adds ability modifier and proficiency bonus if the character has proficiency in that save. Supports advantage/disadvantage.",
-		mcp.NewObjectSchema(
-			map[string]mcp.Property{
-				"character_id":  mcp.StringProp("The ID of the character making the save (required)"),
-				"ability":       mcp.StringProp("The ability for the saving throw (strength, dexterity, constitution, intelligence, wisdom, charisma) (required)"),
-				"dc":            mcp.IntProp("Optional difficulty class (DC) to compare against"),
-				"advantage":     mcp.BoolProp("Roll with advantage (roll 2d20, take higher)"),
-				"disadvantage":  mcp.BoolProp("Roll with disadvantage (roll 2d20, take lower)"),
-			},
-			mcp.Required("character_id", "ability"),
-		),
-	)
+		func(ctx context.Context, input *service.RollSaveRequest) mcp.ToolResponse {
+			resp, err := t.diceService.RollSave(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
 
-	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
-		var input struct {
-			CharacterID  string `json:"character_id"`
-			Ability      string `json:"ability"`
-			DC           int    `json:"dc"`
-			Advantage    bool   `json:"advantage"`
-			Disadvantage bool   `json:"disadvantage"`
-		}
+			// Build response message
+			result := map[string]interface{}{
+				"result":    resp.Result,
+				"modifiers": resp.Modifiers,
+			}
 
-		if err := json.Unmarshal(req.Arguments, &input); err != nil {
-			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
-		}
+			// Add human-readable message
+			msg := fmt.Sprintf("Rolled %s saving throw: %d", input.Ability, resp.Result.DiceResult.Total)
+			msg += outcomeSummary(input.DC, resp.Result)
+			msg += modifierSummary(resp.Modifiers)
 
-		resp, err := t.diceService.RollSave(ctx, &service.RollSaveRequest{
-			CharacterID:  input.CharacterID,
-			Ability:      input.Ability,
-			DC:           input.DC,
-			Advantage:    input.Advantage,
-			Disadvantage: input.Disadvantage,
-		})
-		if err != nil {
-			return mcp.NewErrorResponse(err)
-		}
+			result["message"] = msg
 
-		// Build response message
-		result := map[string]interface{}{
-			"result": resp.Result,
-		}
+			return mcp.NewJSONResponse(result)
+		},
+	)
+}
 
-		// Add human-readable message
-		msg := fmt.Sprintf("Rolled %s saving throw: %d", input.Ability, resp.Result.DiceResult.Total)
+// rollAttackTool implements the roll_attack tool
+func (t *DiceTools) rollAttackTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"roll_attack",
+		"Roll an attack against a target's Armor Class without applying damage. A natural 20 always hits and is a critical hit; a natural 1 always misses. Supports advantage/disadvantage. Use roll_damage afterwards on a hit.",
+		func(ctx context.Context, input *service.RollAttackRequest) mcp.ToolResponse {
+			resp, err := t.diceService.RollAttack(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
 
-		if input.DC > 0 {
-			if resp.Result.Success {
-				msg += fmt.Sprintf(" vs DC %d - SUCCESS", input.DC)
-			} else {
-				msg += fmt.Sprintf(" vs DC %d - FAILURE", input.DC)
+			msg := fmt.Sprintf("Attack roll: %d vs AC %d - ", resp.AttackRoll.Total, resp.TargetAC)
+			switch {
+			case resp.Crit:
+				msg += "CRITICAL HIT!"
+			case resp.Hit:
+				msg += "HIT"
+			default:
+				msg += "MISS"
 			}
-		}
 
-		if resp.Result.DiceResult.IsCritical() {
-			msg += " [CRITICAL!]"
-		} else if resp.Result.DiceResult.IsFumble() {
-			msg += " [FUMBLE!]"
-		}
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"attack_roll": resp.AttackRoll,
+				"target_ac":   resp.TargetAC,
+				"hit":         resp.Hit,
+				"crit":        resp.Crit,
+				"message":     msg,
+			})
+		},
+	)
+}
 
-		result["message"] = msg
+// rollDamageTool implements the roll_damage tool
+func (t *DiceTools) rollDamageTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"roll_damage",
+		"Roll damage dice without applying them to a character. Set crit=true on a critical hit to double the damage dice (not the modifier).",
+		func(ctx context.Context, input *service.RollDamageRequest) mcp.ToolResponse {
+			resp, err := t.diceService.RollDamage(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
 
-		return mcp.NewJSONResponse(result)
+			msg := fmt.Sprintf("Rolled %d damage (%s)", resp.Result.Total, input.Formula)
+			if input.Crit {
+				msg += " [CRITICAL]"
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"result":  resp.Result,
+				"message": msg,
+			})
+		},
+	)
+}
+
+// outcomeSummary describes a check's result against its DC and any critical or fumble
+func outcomeSummary(dc int, result *models.CheckResult) string {
+	var summary string
+	if dc > 0 {
+		if result.Success {
+			summary += fmt.Sprintf(" vs DC %d - SUCCESS", dc)
+		} else {
+			summary += fmt.Sprintf(" vs DC %d - FAILURE", dc)
+		}
 	}
 
-	return tool, handler
+	if result.DiceResult.IsCritical() {
+		summary += " [CRITICAL!]"
+	} else if result.DiceResult.IsFumble() {
+		summary += " [FUMBLE!]"
+	}
+	return summary
 }
 
 // Tool list for external registration
@@ -220,4 +193,14 @@ var DiceToolNames = []string{
 	"roll_dice",
 	"roll_check",
 	"roll_save",
+	"roll_attack",
+	"roll_damage",
+}
+
+// modifierSummary describes the automatic roll modifiers applied to a roll
+func modifierSummary(modifiers *rulescombat.RollModifiers) string {
+	if modifiers == nil || len(modifiers.Reasons) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(modifiers.Reasons, "; "))
 }
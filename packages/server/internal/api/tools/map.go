@@ -15,6 +15,7 @@ import (
 type MapTools struct {
 	mapService          *service.MapService
 	mapServiceWithChars *service.MapServiceWithCharacters
+	visionService       *service.VisionService
 }
 
 // NewMapTools creates a new MapTools instance
@@ -31,6 +32,11 @@ func NewMapToolsWithCharacters(mapService *service.MapServiceWithCharacters) *Ma
 	}
 }
 
+// SetVisionService enables player-scoped views in get_battle_map
+func (t *MapTools) SetVisionService(visionService *service.VisionService) {
+	t.visionService = visionService
+}
+
 // Register registers all map tools with the registry
 func (t *MapTools) Register(registry *mcp.Registry) {
 	registry.MustRegister(t.getWorldMapTool())
@@ -235,7 +241,7 @@ func (t *MapTools) getMoveToTool() (mcp.Tool, mcp.ToolHandler) {
 func (t *MapTools) getMoveTokenTool() (mcp.Tool, mcp.ToolHandler) {
 	tool := mcp.NewTool(
 		"move_token",
-		"Move a token on a battle map. Calculates movement cost considering terrain and obstacles. Rules: 1 square = 5 feet, difficult terrain costs double, diagonal movement has +50% cost. Size-based movement: can move through creatures 2+ sizes smaller.",
+		"Move a token on a battle map along the cheapest legal route. The route goes around walls, closed or locked doors and hostile creatures. Rules: 1 square = 5 feet, every second diagonal costs 10 feet, difficult terrain, terrain walls and other creatures' spaces cost double. A creature can pass through a hostile creature only if it is 2+ sizes larger or smaller. During combat, leaving a hostile creature's reach provokes an opportunity attack unless the mover took the Disengage action: NPCs attack immediately, player characters are offered opportunity_attack.",
 		mcp.NewObjectSchema(
 			map[string]mcp.Property{
 				"campaign_id": mcp.StringProp("The ID of the campaign (required)"),
@@ -243,7 +249,9 @@ func (t *MapTools) getMoveTokenTool() (mcp.Tool, mcp.ToolHandler) {
 				"token_id":    mcp.StringProp("The ID of the token to move (required)"),
 				"to_x":        mcp.IntProp("The destination X coordinate on the grid (required, non-negative)"),
 				"to_y":        mcp.IntProp("The destination Y coordinate on the grid (required, non-negative)"),
-				"speed":       mcp.IntProp("Available movement speed in feet for this turn (optional, defaults to character speed)"),
+				"speed":       mcp.IntProp("Available movement speed in feet for this turn (optional, defaults to character speed; ignored during combat where the turn's remaining movement applies)"),
+				"forced":      mcp.BoolProp("Forced movement (shove, spell effect) that doesn't require the creature's turn or consume its movement"),
+				"truncate":    mcp.BoolProp("If the route costs more than the available speed, stop as far along it as possible instead of failing"),
 			},
 			mcp.Required("campaign_id", "map_id", "token_id", "to_x", "to_y"),
 		),
@@ -257,6 +265,8 @@ func (t *MapTools) getMoveTokenTool() (mcp.Tool, mcp.ToolHandler) {
 			ToX        int    `json:"to_x"`
 			ToY        int    `json:"to_y"`
 			Speed      *int   `json:"speed"`
+			Forced     bool   `json:"forced"`
+			Truncate   bool   `json:"truncate"`
 		}
 
 		if err := json.Unmarshal(req.Arguments, &input); err != nil {
@@ -270,6 +280,8 @@ func (t *MapTools) getMoveTokenTool() (mcp.Tool, mcp.ToolHandler) {
 			ToX:        input.ToX,
 			ToY:        input.ToY,
 			Speed:      input.Speed,
+			Forced:     input.Forced,
+			Truncate:   input.Truncate,
 		}
 
 		// Perform the move
@@ -278,8 +290,17 @@ func (t *MapTools) getMoveTokenTool() (mcp.Tool, mcp.ToolHandler) {
 			return mcp.NewErrorResponse(err)
 		}
 
+		message := fmt.Sprintf("Token moved to position (%d, %d), using %d feet of movement", result.Token.Position.X, result.Token.Position.Y, result.MovementUsed)
+		for _, opportunity := range result.OpportunityAttacks {
+			if opportunity.Resolved {
+				message += fmt.Sprintf(". Opportunity attack from %s: %s", opportunity.AttackerID, attackMessage(opportunity.Result))
+			} else {
+				message += fmt.Sprintf(". %s may take an opportunity attack (use opportunity_attack)", opportunity.AttackerID)
+			}
+		}
+
 		return mcp.NewJSONResponse(map[string]interface{}{
-			"message": fmt.Sprintf("Token moved to position (%d, %d), using %d feet of movement", input.ToX, input.ToY, result.MovementUsed),
+			"message": message,
 			"token": map[string]interface{}{
 				"id":           result.Token.ID,
 				"character_id": result.Token.CharacterID,
@@ -293,8 +314,10 @@ func (t *MapTools) getMoveTokenTool() (mcp.Tool, mcp.ToolHandler) {
 				"used":              result.MovementUsed,
 				"remaining":         result.RemainingSpeed,
 				"difficult_terrain": result.DifficultTerrainCount,
+				"truncated":         result.Truncated,
 			},
-			"path": result.Path,
+			"path":                result.Path,
+			"opportunity_attacks": result.OpportunityAttacks,
 		})
 	}
 
@@ -369,10 +392,11 @@ func (t *MapTools) getEnterBattleMapTool() (mcp.Tool, mcp.ToolHandler) {
 func (t *MapTools) getGetBattleMapTool() (mcp.Tool, mcp.ToolHandler) {
 	tool := mcp.NewTool(
 		"get_battle_map",
-		"Get the current battle map for the campaign, including all token positions. This can only be used when the party is currently in a battle map.",
+		"Get the current battle map for the campaign, including all token positions. This can only be used when the party is currently in a battle map. Pass character_id when narrating for a specific player to only include what that character can perceive.",
 		mcp.NewObjectSchema(
 			map[string]mcp.Property{
-				"campaign_id": mcp.StringProp("The ID of the campaign (required)"),
+				"campaign_id":  mcp.StringProp("The ID of the campaign (required)"),
+				"character_id": mcp.StringProp("View the map from this character's perspective: only their own token, allies and tokens they can see are returned, together with their visible cells (optional)"),
 			},
 			mcp.Required("campaign_id"),
 		),
@@ -380,7 +404,8 @@ func (t *MapTools) getGetBattleMapTool() (mcp.Tool, mcp.ToolHandler) {
 
 	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
 		var input struct {
-			CampaignID string `json:"campaign_id"`
+			CampaignID  string `json:"campaign_id"`
+			CharacterID string `json:"character_id"`
 		}
 
 		if err := json.Unmarshal(req.Arguments, &input); err != nil {
@@ -402,18 +427,38 @@ func (t *MapTools) getGetBattleMapTool() (mcp.Tool, mcp.ToolHandler) {
 		if err != nil {
 			return mcp.NewErrorResponse(err)
 		}
+		battleMap = service.RedactMap(ctx, battleMap)
+
+		// Player-scoped view: filter out what the character cannot perceive
+		var view *service.PlayerMapView
+		if input.CharacterID != "" {
+			if t.visionService == nil {
+				return mcp.NewErrorResponse(fmt.Errorf("vision service not configured"))
+			}
+			view, err = t.visionService.FilterBattleMapForCharacter(ctx, battleMap, input.CharacterID)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+			battleMap = view.Map
+		}
+
+		mapData := map[string]interface{}{
+			"id":        battleMap.ID,
+			"name":      battleMap.Name,
+			"type":      battleMap.Type,
+			"width":     battleMap.Grid.Width,
+			"height":    battleMap.Grid.Height,
+			"cell_size": battleMap.Grid.CellSize,
+			"tokens":    battleMap.Tokens,
+		}
+		if view != nil {
+			mapData["viewer_character_id"] = input.CharacterID
+			mapData["visible_cells"] = view.Visible.Cells
+		}
 
 		return mcp.NewJSONResponse(map[string]interface{}{
-			"message": fmt.Sprintf("Retrieved battle map '%s'", battleMap.Name),
-			"battle_map": map[string]interface{}{
-				"id":        battleMap.ID,
-				"name":      battleMap.Name,
-				"type":      battleMap.Type,
-				"width":     battleMap.Grid.Width,
-				"height":    battleMap.Grid.Height,
-				"cell_size": battleMap.Grid.CellSize,
-				"tokens":    battleMap.Tokens,
-			},
+			"message":    fmt.Sprintf("Retrieved battle map '%s'", battleMap.Name),
+			"battle_map": mapData,
 		})
 	}
 
@@ -499,10 +544,10 @@ func (t *MapTools) getCreateVisualLocationTool() (mcp.Tool, mcp.ToolHandler) {
 				"name":        mcp.StringProp("The name of the location (required)"),
 				"description": mcp.StringProp("A description of the location (optional)"),
 				"type":        mcp.StringProp("The type of location: town, dungeon, forest, mountain, etc. (required)"),
-				"position_x":  mcp.Prop("number", "X coordinate as normalized value between 0 and 1 (required)"),
-				"position_y":  mcp.Prop("number", "Y coordinate as normalized value between 0 and 1 (required)"),
+				"position_x":  mcp.NumberRange("X coordinate as normalized value between 0 and 1 (defaults to 0)", 0, 1),
+				"position_y":  mcp.NumberRange("Y coordinate as normalized value between 0 and 1 (defaults to 0)", 0, 1),
 			},
-			mcp.Required("campaign_id", "map_id", "name", "type", "position_x", "position_y"),
+			mcp.Required("campaign_id", "map_id", "name", "type"),
 		),
 	)
 
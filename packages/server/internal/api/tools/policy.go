@@ -0,0 +1,150 @@
+// Package tools provides MCP tool implementations
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// ToolAccess is the least privileged role allowed to call each tool.
+// Tools not listed here are DM-only. Player tools act through a character, and the
+// services check the player owns it (and, in combat, that it is that character's turn).
+var ToolAccess = map[string]mcp.Access{
+	// Campaigns
+	"get_campaign":         mcp.AccessRead,
+	"list_campaigns":       mcp.AccessRead,
+	"get_campaign_summary": mcp.AccessRead,
+
+	// Characters
+	"create_character": mcp.AccessPlayer, // players may only create their own player character
+	"get_character":    mcp.AccessRead,
+	"list_characters":  mcp.AccessRead,
+	"update_character": mcp.AccessPlayer,
+	"death_save":       mcp.AccessPlayer,
+	"get_encumbrance":  mcp.AccessRead,
+
+	// Combat
+	"get_combat_state":   mcp.AccessRead,
+	"attack":             mcp.AccessPlayer,
+	"cast_spell":         mcp.AccessPlayer,
+	"end_turn":           mcp.AccessPlayer,
+	"dash":               mcp.AccessPlayer,
+	"dodge":              mcp.AccessPlayer,
+	"disengage":          mcp.AccessPlayer,
+	"help":               mcp.AccessPlayer,
+	"hide":               mcp.AccessPlayer,
+	"ready":              mcp.AccessPlayer,
+	"opportunity_attack": mcp.AccessPlayer,
+
+	// Conditions
+	"get_conditions": mcp.AccessRead,
+	"has_condition":  mcp.AccessRead,
+
+	// Context
+	"get_context":     mcp.AccessRead,
+	"get_raw_context": mcp.AccessRead,
+	"save_message":    mcp.AccessPlayer,
+
+	// Dice
+	"roll_dice":   mcp.AccessPlayer,
+	"roll_check":  mcp.AccessPlayer,
+	"roll_save":   mcp.AccessPlayer,
+	"roll_attack": mcp.AccessPlayer,
+	"roll_damage": mcp.AccessPlayer,
+
+	// Maps
+	"get_world_map":  mcp.AccessRead,
+	"get_battle_map": mcp.AccessRead,
+	"move_token":     mcp.AccessPlayer,
+
+	// Rest
+	"take_short_rest": mcp.AccessPlayer,
+	"take_long_rest":  mcp.AccessPlayer,
+
+	// Vision; players view only from their own character's token
+	"get_visible_area": mcp.AccessPlayer,
+	"can_see":          mcp.AccessPlayer,
+}
+
+// RegisterAccess applies ToolAccess to the registry
+func RegisterAccess(registry *mcp.Registry) {
+	for name, access := range ToolAccess {
+		registry.SetAccess(name, access)
+	}
+}
+
+// CampaignRecords reads the records tool arguments refer to by id
+type CampaignRecords struct {
+	Characters interface {
+		Get(ctx context.Context, id string) (*models.Character, error)
+	}
+	Combats interface {
+		Get(ctx context.Context, id string) (*models.Combat, error)
+	}
+	Maps interface {
+		Get(ctx context.Context, id string) (*models.Map, error)
+	}
+}
+
+// campaignArgs are the tool arguments that identify a campaign or one of its records
+type campaignArgs struct {
+	CampaignID     string   `json:"campaign_id"`
+	CombatID       string   `json:"combat_id"`
+	MapID          string   `json:"map_id"`
+	BattleMapID    string   `json:"battle_map_id"`
+	CharacterID    string   `json:"character_id"`
+	AttackerID     string   `json:"attacker_id"`
+	CasterID       string   `json:"caster_id"`
+	TargetID       string   `json:"target_id"`
+	AllyID         string   `json:"ally_id"`
+	TargetIDs      []string `json:"target_ids"`
+	ParticipantIDs []string `json:"participant_ids"`
+}
+
+// CampaignResolver returns the campaigns of every record a tool call refers to, so that
+// DMs are limited to the campaigns they run. Records that cannot be read are left to the
+// tool, which reports them as missing.
+func CampaignResolver(records CampaignRecords) mcp.CampaignResolver {
+	return func(ctx context.Context, arguments json.RawMessage) ([]string, error) {
+		var args campaignArgs
+		if len(arguments) > 0 {
+			if err := json.Unmarshal(arguments, &args); err != nil {
+				return nil, err
+			}
+		}
+
+		var campaigns []string
+		add := func(campaignID string) {
+			if campaignID != "" {
+				campaigns = append(campaigns, campaignID)
+			}
+		}
+		add(args.CampaignID)
+		if args.CombatID != "" && records.Combats != nil {
+			if combat, err := records.Combats.Get(ctx, args.CombatID); err == nil {
+				add(combat.CampaignID)
+			}
+		}
+		for _, id := range []string{args.MapID, args.BattleMapID} {
+			if id == "" || records.Maps == nil {
+				continue
+			}
+			if gameMap, err := records.Maps.Get(ctx, id); err == nil {
+				add(gameMap.CampaignID)
+			}
+		}
+		characterIDs := append([]string{args.CharacterID, args.AttackerID, args.CasterID, args.TargetID, args.AllyID}, args.TargetIDs...)
+		for _, id := range append(characterIDs, args.ParticipantIDs...) {
+			if id == "" || records.Characters == nil {
+				continue
+			}
+			if character, err := records.Characters.Get(ctx, id); err == nil {
+				add(character.CampaignID)
+			}
+		}
+		return campaigns, nil
+	}
+}
@@ -0,0 +1,104 @@
+// Package tools provides MCP tool implementations
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/service"
+)
+
+// Campaign snapshot tools
+
+func (t *CampaignTools) createSnapshotTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"create_snapshot",
+		"Save the campaign at this point in time: its game state, characters, maps and active combat, plus the position in the message history. Take one before a risky encounter, then use restore_snapshot to rewind or fork_campaign to play out an alternative.",
+		func(ctx context.Context, input *service.CreateSnapshotRequest) mcp.ToolResponse {
+			snapshot, err := t.campaignService.CreateSnapshot(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"snapshot": snapshot,
+				"message":  fmt.Sprintf("Saved snapshot '%s' with ID: %s", snapshot.Name, snapshot.ID),
+			})
+		},
+	)
+}
+
+func (t *CampaignTools) listSnapshotsTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"list_snapshots",
+		"List a campaign's snapshots (save points), newest first.",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"campaign_id": mcp.StringProp("The unique ID of the campaign (required)"),
+			},
+			mcp.Required("campaign_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			CampaignID string `json:"campaign_id"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		snapshots, err := t.campaignService.ListSnapshots(ctx, input.CampaignID)
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"snapshots": snapshots,
+			"count":     len(snapshots),
+		})
+	}
+
+	return tool, handler
+}
+
+func (t *CampaignTools) restoreSnapshotTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"restore_snapshot",
+		"Rewind a campaign to a snapshot. Game state, characters, maps and the active combat return to how they were; characters and maps created since are deleted, combats started since are deleted, and later messages are removed. Create a snapshot first to keep the current state.",
+		func(ctx context.Context, input *service.RestoreSnapshotRequest) mcp.ToolResponse {
+			resp, err := t.campaignService.RestoreSnapshot(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"restore": resp,
+				"message": fmt.Sprintf("Restored snapshot '%s' (%d characters, %d maps; removed %d messages)",
+					resp.Snapshot.Name, resp.Characters, resp.Maps, resp.RemovedMessages),
+			})
+		},
+	)
+}
+
+func (t *CampaignTools) forkCampaignTool() (mcp.Tool, mcp.ToolHandler) {
+	return mcp.TypedTool(
+		"fork_campaign",
+		"Copy a campaign into a new campaign, from its current state or from a snapshot, including the message history up to that point. Every record gets a new ID; the original campaign is not changed.",
+		func(ctx context.Context, input *service.ForkCampaignRequest) mcp.ToolResponse {
+			resp, err := t.campaignService.ForkCampaign(ctx, input)
+			if err != nil {
+				return mcp.NewErrorResponse(err)
+			}
+
+			return mcp.NewJSONResponse(map[string]interface{}{
+				"campaign": resp.Campaign,
+				"snapshot": resp.Snapshot,
+				"message":  fmt.Sprintf("Forked campaign '%s' with ID: %s", resp.Campaign.Name, resp.Campaign.ID),
+			})
+		},
+	)
+}
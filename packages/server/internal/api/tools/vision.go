@@ -0,0 +1,135 @@
+// Package tools provides MCP tool implementations
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/service"
+)
+
+// VisionTools provides line of sight and visibility MCP tools
+type VisionTools struct {
+	visionService *service.VisionService
+}
+
+// NewVisionTools creates a new VisionTools instance
+func NewVisionTools(visionService *service.VisionService) *VisionTools {
+	return &VisionTools{
+		visionService: visionService,
+	}
+}
+
+// Register registers all vision tools with the registry
+func (t *VisionTools) Register(registry *mcp.Registry) {
+	registry.MustRegister(t.getVisibleAreaTool())
+	registry.MustRegister(t.canSeeTool())
+}
+
+// getVisibleAreaTool implements the get_visible_area tool
+func (t *VisionTools) getVisibleAreaTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"get_visible_area",
+		"Get the cells and other tokens a token can currently perceive on a battle map. Accounts for sight-blocking walls, open/closed doors, bright/dim light and darkness, darkvision, blindsight, truesight, blindness, invisibility and hidden creatures (stealth vs passive Perception).",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"map_id":        mcp.StringProp("The ID of the battle map (required)"),
+				"token_id":      mcp.StringProp("The ID of the viewing token (required)"),
+				"include_cells": mcp.BoolProp("Include the list of visible cells (optional, default true); set false to only list visible tokens"),
+			},
+			mcp.Required("map_id", "token_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			MapID        string `json:"map_id"`
+			TokenID      string `json:"token_id"`
+			IncludeCells *bool  `json:"include_cells"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		area, err := t.visionService.GetVisibleArea(ctx, input.MapID, input.TokenID)
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		response := map[string]interface{}{
+			"message":            fmt.Sprintf("Token %s can see %d cells and %d other tokens", area.TokenID, len(area.Cells), len(area.Tokens)),
+			"token_id":           area.TokenID,
+			"senses":             area.Senses,
+			"blinded":            area.Blinded,
+			"visible_cell_count": len(area.Cells),
+			"tokens":             area.Tokens,
+		}
+		if input.IncludeCells == nil || *input.IncludeCells {
+			response["cells"] = area.Cells
+		}
+
+		return mcp.NewJSONResponse(response)
+	}
+
+	return tool, handler
+}
+
+// canSeeTool implements the can_see tool
+func (t *VisionTools) canSeeTool() (mcp.Tool, mcp.ToolHandler) {
+	tool := mcp.NewTool(
+		"can_see",
+		"Check whether one token can see another on a battle map, and which sense is used. Returns the reason when it cannot (no line of sight, darkness, blinded, invisible, hidden).",
+		mcp.NewObjectSchema(
+			map[string]mcp.Property{
+				"map_id":          mcp.StringProp("The ID of the battle map (required)"),
+				"viewer_token_id": mcp.StringProp("The ID of the token that is looking (required)"),
+				"target_token_id": mcp.StringProp("The ID of the token being looked at (required)"),
+			},
+			mcp.Required("map_id", "viewer_token_id", "target_token_id"),
+		),
+	)
+
+	handler := func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var input struct {
+			MapID         string `json:"map_id"`
+			ViewerTokenID string `json:"viewer_token_id"`
+			TargetTokenID string `json:"target_token_id"`
+		}
+
+		if err := json.Unmarshal(req.Arguments, &input); err != nil {
+			return mcp.NewErrorResponse(fmt.Errorf("invalid arguments: %w", err))
+		}
+
+		check, err := t.visionService.CanSee(ctx, input.MapID, input.ViewerTokenID, input.TargetTokenID)
+		if err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+
+		message := fmt.Sprintf("Token %s can see token %s (%s, %s light, %d feet)", check.ViewerTokenID, check.TargetTokenID, check.Sense, check.Light, check.Distance)
+		if !check.CanSee {
+			message = fmt.Sprintf("Token %s cannot see token %s: %s", check.ViewerTokenID, check.TargetTokenID, check.Reason)
+		}
+
+		return mcp.NewJSONResponse(map[string]interface{}{
+			"message":         message,
+			"viewer_token_id": check.ViewerTokenID,
+			"target_token_id": check.TargetTokenID,
+			"can_see":         check.CanSee,
+			"sense":           check.Sense,
+			"light":           check.Light,
+			"distance":        check.Distance,
+			"reason":          check.Reason,
+		})
+	}
+
+	return tool, handler
+}
+
+// Tool list for external registration
+var VisionToolNames = []string{
+	"get_visible_area",
+	"can_see",
+}
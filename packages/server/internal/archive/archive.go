@@ -0,0 +1,307 @@
+// Package archive reads and writes portable campaign archives.
+//
+// An archive is a zip file holding one JSON document per record and a
+// manifest.json that lists them:
+//
+//	manifest.json          format, version and the entries below
+//	campaign.json          the campaign
+//	game_state.json        the campaign's game state
+//	characters/<id>.json   one file per character
+//	maps/<id>.json         one file per map, with its walls, tokens, lights and images
+//	combats/<id>.json      one file per combat, with its log
+//	messages.json          the conversation history, oldest first
+//
+// Archives carry a format version. Older versions are upgraded on read, one
+// version at a time, before the documents are decoded into models; fields added
+// to the models since an archive was written simply decode to their zero values.
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+const (
+	// Format identifies campaign archives in their manifest
+	Format = "dnd-mcp-campaign"
+
+	// Version is the archive format version written by this server
+	Version = 1
+
+	// ManifestFile is the name of the manifest inside the archive
+	ManifestFile = "manifest.json"
+
+	// maxFileSize limits the uncompressed size of each file read from an archive
+	maxFileSize = 64 << 20
+)
+
+// Entry kinds
+const (
+	KindCampaign  = "campaign"
+	KindGameState = "game_state"
+	KindCharacter = "character"
+	KindMap       = "map"
+	KindCombat    = "combat"
+	KindMessages  = "messages"
+)
+
+// ErrInvalidArchive is returned when data is not a readable campaign archive
+var ErrInvalidArchive = errors.New("invalid campaign archive")
+
+// Manifest describes an archive's contents
+type Manifest struct {
+	Format       string    `json:"format"`
+	Version      int       `json:"version"`
+	ExportedAt   time.Time `json:"exported_at"`
+	CampaignID   string    `json:"campaign_id"`
+	CampaignName string    `json:"campaign_name"`
+	Entries      []Entry   `json:"entries"`
+}
+
+// Entry is one document in an archive
+type Entry struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id,omitempty"`
+	Path string `json:"path"`
+}
+
+// Count returns how many entries of a kind the archive holds
+func (m *Manifest) Count(kind string) int {
+	count := 0
+	for _, entry := range m.Entries {
+		if entry.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Contents holds the records of one campaign
+type Contents struct {
+	Campaign   *models.Campaign    `json:"campaign"`
+	GameState  *models.GameState   `json:"game_state,omitempty"`
+	Characters []*models.Character `json:"characters"`
+	Maps       []*models.Map       `json:"maps"`
+	Combats    []*models.Combat    `json:"combats"`
+	Messages   []*models.Message   `json:"messages"`
+}
+
+// Document is an archive's manifest and raw files, as upgrade steps see them
+type Document struct {
+	Manifest *Manifest
+	Files    map[string]json.RawMessage
+}
+
+// upgrades converts a document of version v to version v+1, keyed by v.
+// A format change adds a step here and bumps Version.
+var upgrades = map[int]func(doc *Document) error{}
+
+// Write writes contents as an archive and returns its manifest
+func Write(w io.Writer, contents *Contents, exportedAt time.Time) (*Manifest, error) {
+	if contents == nil || contents.Campaign == nil {
+		return nil, errors.New("archive has no campaign")
+	}
+
+	manifest := &Manifest{
+		Format:       Format,
+		Version:      Version,
+		ExportedAt:   exportedAt,
+		CampaignID:   contents.Campaign.ID,
+		CampaignName: contents.Campaign.Name,
+	}
+	files := make(map[string]interface{})
+	add := func(kind, id, name string, value interface{}) {
+		manifest.Entries = append(manifest.Entries, Entry{Kind: kind, ID: id, Path: name})
+		files[name] = value
+	}
+
+	add(KindCampaign, contents.Campaign.ID, "campaign.json", contents.Campaign)
+	if contents.GameState != nil {
+		add(KindGameState, contents.GameState.ID, "game_state.json", contents.GameState)
+	}
+	for _, character := range contents.Characters {
+		add(KindCharacter, character.ID, path.Join("characters", character.ID+".json"), character)
+	}
+	for _, gameMap := range contents.Maps {
+		add(KindMap, gameMap.ID, path.Join("maps", gameMap.ID+".json"), gameMap)
+	}
+	for _, combat := range contents.Combats {
+		add(KindCombat, combat.ID, path.Join("combats", combat.ID+".json"), combat)
+	}
+	messages := contents.Messages
+	if messages == nil {
+		messages = []*models.Message{}
+	}
+	add(KindMessages, "", "messages.json", messages)
+
+	zw := zip.NewWriter(w)
+	if err := writeJSON(zw, ManifestFile, manifest); err != nil {
+		return nil, err
+	}
+	for _, entry := range manifest.Entries {
+		if err := writeJSON(zw, entry.Path, files[entry.Path]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// Read reads an archive, upgrading it to the current version
+func Read(data []byte) (*Contents, *Manifest, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	doc := &Document{Files: make(map[string]json.RawMessage)}
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		raw, err := readFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		doc.Files[file.Name] = raw
+	}
+
+	rawManifest, ok := doc.Files[ManifestFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, ManifestFile)
+	}
+	if err := json.Unmarshal(rawManifest, &doc.Manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to parse manifest: %v", ErrInvalidArchive, err)
+	}
+	if err := upgrade(doc); err != nil {
+		return nil, nil, err
+	}
+
+	contents, err := decode(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contents, doc.Manifest, nil
+}
+
+// upgrade applies the upgrade steps from the document's version to the current one
+func upgrade(doc *Document) error {
+	manifest := doc.Manifest
+	if manifest.Format != Format {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, manifest.Format)
+	}
+	if manifest.Version < 1 {
+		return fmt.Errorf("%w: missing version", ErrInvalidArchive)
+	}
+	if manifest.Version > Version {
+		return fmt.Errorf("%w: version %d is newer than the supported version %d", ErrInvalidArchive, manifest.Version, Version)
+	}
+
+	for manifest.Version < Version {
+		step, ok := upgrades[manifest.Version]
+		if !ok {
+			return fmt.Errorf("%w: no upgrade from version %d", ErrInvalidArchive, manifest.Version)
+		}
+		if err := step(doc); err != nil {
+			return fmt.Errorf("failed to upgrade archive from version %d: %w", manifest.Version, err)
+		}
+		manifest.Version++
+	}
+	return nil
+}
+
+// decode decodes the documents listed in the manifest
+func decode(doc *Document) (*Contents, error) {
+	contents := &Contents{
+		Characters: make([]*models.Character, 0),
+		Maps:       make([]*models.Map, 0),
+		Combats:    make([]*models.Combat, 0),
+		Messages:   make([]*models.Message, 0),
+	}
+
+	for _, entry := range doc.Manifest.Entries {
+		raw, ok := doc.Files[entry.Path]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, entry.Path)
+		}
+
+		var target interface{}
+		switch entry.Kind {
+		case KindCampaign:
+			contents.Campaign = &models.Campaign{}
+			target = contents.Campaign
+		case KindGameState:
+			contents.GameState = &models.GameState{}
+			target = contents.GameState
+		case KindCharacter:
+			character := &models.Character{}
+			contents.Characters = append(contents.Characters, character)
+			target = character
+		case KindMap:
+			gameMap := &models.Map{}
+			contents.Maps = append(contents.Maps, gameMap)
+			target = gameMap
+		case KindCombat:
+			combat := &models.Combat{}
+			contents.Combats = append(contents.Combats, combat)
+			target = combat
+		case KindMessages:
+			target = &contents.Messages
+		default:
+			return nil, fmt.Errorf("%w: unknown entry kind %q", ErrInvalidArchive, entry.Kind)
+		}
+
+		if err := json.Unmarshal(raw, target); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse %s: %v", ErrInvalidArchive, entry.Path, err)
+		}
+	}
+
+	if contents.Campaign == nil {
+		return nil, fmt.Errorf("%w: no campaign", ErrInvalidArchive)
+	}
+	return contents, nil
+}
+
+// writeJSON writes a value as an indented JSON file
+func writeJSON(zw *zip.Writer, name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// readFile reads one file from an archive, refusing files over maxFileSize
+func readFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open %s: %v", ErrInvalidArchive, file.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %s: %v", ErrInvalidArchive, file.Name, err)
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidArchive, file.Name, maxFileSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RemapIDs gives every record in contents a fresh ID so an archive can be
+// imported next to the campaign it came from, or imported more than once.
+//
+// Every UUID found under an "id" key (campaigns, game states, characters, maps,
+// locations, tokens, walls, lights, combats, messages) is replaced, along with
+// every other string in the contents equal to it, so references between records
+// follow their targets. Non-UUID IDs such as item and spell identifiers, and
+// user IDs like dm_id and player_id, are left alone.
+// It returns the mapping from old to new IDs.
+func RemapIDs(contents *Contents) (map[string]string, error) {
+	data, err := json.Marshal(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal contents: %w", err)
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal contents: %w", err)
+	}
+
+	mapping := make(map[string]string)
+	collectIDs(tree, mapping)
+	tree = replaceIDs(tree, mapping)
+
+	data, err = json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal remapped contents: %w", err)
+	}
+	remapped := &Contents{}
+	if err := json.Unmarshal(data, remapped); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remapped contents: %w", err)
+	}
+	*contents = *remapped
+	return mapping, nil
+}
+
+// collectIDs assigns a new ID to every UUID under an "id" key
+func collectIDs(node interface{}, mapping map[string]string) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if id, ok := value["id"].(string); ok {
+			if _, err := uuid.Parse(id); err == nil {
+				if _, seen := mapping[id]; !seen {
+					mapping[id] = uuid.New().String()
+				}
+			}
+		}
+		for _, child := range value {
+			collectIDs(child, mapping)
+		}
+	case []interface{}:
+		for _, child := range value {
+			collectIDs(child, mapping)
+		}
+	}
+}
+
+// replaceIDs replaces every string equal to a mapped ID
+func replaceIDs(node interface{}, mapping map[string]string) interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			value[key] = replaceIDs(child, mapping)
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = replaceIDs(child, mapping)
+		}
+	case string:
+		if replacement, ok := mapping[value]; ok {
+			return replacement
+		}
+	}
+	return node
+}
@@ -0,0 +1,213 @@
+// Package auth authenticates callers and carries their identity through request contexts
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dnd-mcp/server/pkg/config"
+)
+
+// Role is what a principal may do in the game
+type Role string
+
+// Roles
+const (
+	// RoleSystem is a trusted service such as the bundled client; it acts as the DM of every campaign
+	RoleSystem Role = "system"
+	// RoleDM runs campaigns: creates NPCs, starts combat, deletes data
+	RoleDM Role = "dm"
+	// RolePlayer acts only through their own characters
+	RolePlayer Role = "player"
+	// RoleObserver may only read
+	RoleObserver Role = "observer"
+)
+
+// ParseRole parses a role name
+func ParseRole(s string) (Role, error) {
+	switch role := Role(strings.ToLower(strings.TrimSpace(s))); role {
+	case RoleSystem, RoleDM, RolePlayer, RoleObserver:
+		return role, nil
+	}
+	return "", fmt.Errorf("unknown role %q", s)
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	UserID string `json:"user_id"`
+	Role   Role   `json:"role"`
+}
+
+// IsDM reports whether the principal runs games (the DM or a system service)
+func (p *Principal) IsDM() bool {
+	return p.Role == RoleDM || p.Role == RoleSystem
+}
+
+// CanWrite reports whether the principal may change game state
+func (p *Principal) CanWrite() bool {
+	return p.Role != RoleObserver
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of a request.
+// Requests without one come from trusted transports (stdio) or servers with auth disabled.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Authentication errors
+var (
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	ErrTokenExpired    = errors.New("token expired")
+)
+
+// Authenticator verifies API keys and HS256-signed JWTs
+type Authenticator struct {
+	apiKeys map[[sha256.Size]byte]*Principal
+	secret  []byte
+	now     func() time.Time
+}
+
+// NewAuthenticator creates an authenticator from the auth configuration.
+// API keys are "key:role:user_id" entries separated by commas.
+func NewAuthenticator(cfg *config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		apiKeys: make(map[[sha256.Size]byte]*Principal),
+		secret:  []byte(cfg.JWTSecret),
+		now:     time.Now,
+	}
+
+	for _, entry := range strings.Split(cfg.APIKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid API key entry: expected key:role:user_id")
+		}
+		role, err := ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid API key entry: %w", err)
+		}
+		a.apiKeys[sha256.Sum256([]byte(parts[0]))] = &Principal{UserID: parts[2], Role: role}
+	}
+
+	if len(a.apiKeys) == 0 && len(a.secret) == 0 {
+		return nil, fmt.Errorf("auth requires API keys or a JWT secret")
+	}
+	return a, nil
+}
+
+// Authenticate resolves a credential, an API key or a JWT, to its principal
+func (a *Authenticator) Authenticate(credential string) (*Principal, error) {
+	if credential == "" {
+		return nil, ErrUnauthenticated
+	}
+	// Keys are looked up by hash so the comparison does not leak key prefixes
+	if p, ok := a.apiKeys[sha256.Sum256([]byte(credential))]; ok {
+		copied := *p
+		return &copied, nil
+	}
+	if len(a.secret) > 0 && strings.Count(credential, ".") == 2 {
+		return a.verifyToken(credential)
+	}
+	return nil, ErrUnauthenticated
+}
+
+// tokenHeader is the JOSE header of the tokens this package issues
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// claims are the JWT claims read by the authenticator
+type claims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// IssueToken signs an HS256 JWT for a principal that expires after ttl (never when ttl is 0)
+func IssueToken(secret []byte, p Principal, ttl time.Duration) (string, error) {
+	if len(secret) == 0 {
+		return "", fmt.Errorf("JWT secret is required")
+	}
+	now := time.Now()
+	c := claims{Subject: p.UserID, Role: string(p.Role), IssuedAt: now.Unix()}
+	if ttl != 0 {
+		c.ExpiresAt = now.Add(ttl).Unix()
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode claims: %w", err)
+	}
+
+	signed := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + sign(secret, signed), nil
+}
+
+// verifyToken checks a JWT's signature and expiry and returns its principal
+func (a *Authenticator) verifyToken(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrUnauthenticated
+	}
+
+	expected := sign(a.secret, parts[0]+"."+parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, ErrUnauthenticated
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return nil, ErrUnauthenticated
+	}
+	if c.ExpiresAt != 0 && a.now().Unix() >= c.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	role, err := ParseRole(c.Role)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	return &Principal{UserID: c.Subject, Role: role}, nil
+}
+
+// sign computes the base64url HMAC-SHA256 signature of a signing input
+func sign(secret []byte, input string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Credential extracts the credential from an Authorization bearer header or X-API-Key header
+func Credential(authorization, apiKey string) string {
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(apiKey)
+}
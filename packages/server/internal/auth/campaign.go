@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"sync"
+)
+
+// CampaignDMs looks up the user id of a campaign's DM
+type CampaignDMs func(ctx context.Context, campaignID string) (string, error)
+
+// campaignDMCache remembers the DMs looked up during one request
+type campaignDMCache struct {
+	lookup CampaignDMs
+	mu     sync.Mutex
+	dms    map[string]string
+}
+
+type campaignDMsKey struct{}
+
+// WithCampaignDMs returns a context whose principal may be checked against the DM of a campaign.
+// Lookups are cached for the lifetime of the context.
+func WithCampaignDMs(ctx context.Context, lookup CampaignDMs) context.Context {
+	return context.WithValue(ctx, campaignDMsKey{}, &campaignDMCache{lookup: lookup, dms: make(map[string]string)})
+}
+
+// RunsCampaign reports whether the request's principal is the DM of a campaign.
+// Requests without a principal and system principals run every campaign. A DM runs only
+// the campaigns whose DM they are, which needs the lookup from WithCampaignDMs; without it,
+// or when the campaign cannot be found, a DM runs no campaign. Players and observers run none.
+func RunsCampaign(ctx context.Context, campaignID string) bool {
+	principal, ok := FromContext(ctx)
+	if !ok || principal.Role == RoleSystem {
+		return true
+	}
+	if principal.Role != RoleDM || campaignID == "" {
+		return false
+	}
+
+	cache, ok := ctx.Value(campaignDMsKey{}).(*campaignDMCache)
+	if !ok {
+		return false
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	dmID, found := cache.dms[campaignID]
+	if !found {
+		var err error
+		if dmID, err = cache.lookup(ctx, campaignID); err != nil {
+			return false
+		}
+		cache.dms[campaignID] = dmID
+	}
+	return dmID == principal.UserID
+}
@@ -107,6 +107,15 @@ func (c *MapConverter) ConvertFromUVTT(uvtt *format.UVTTData, opts format.Import
 		}
 	}
 
+	// Convert lights if requested
+	if opts.ImportLights {
+		for _, light := range c.convertUVTTLights(uvtt.Lights, uvtt.Resolution.PixelsPerGrid, gameMap.Grid.CellSize) {
+			if err := gameMap.AddLight(light); err != nil {
+				continue
+			}
+		}
+	}
+
 	// Convert tokens if requested
 	if opts.ImportTokens {
 		tokens := c.convertUVTTTokens(uvtt.Tokens, uvtt.Resolution.PixelsPerGrid)
@@ -249,6 +258,27 @@ func (c *MapConverter) convertUVTTTokens(uvttTokens []format.UVTTToken, pixelsPe
 	return tokens
 }
 
+// convertUVTTLights converts UVTT lights to model light sources
+// UVTT lights only carry a single range (in grid squares), which is treated as bright light
+func (c *MapConverter) convertUVTTLights(uvttLights []format.UVTTLight, pixelsPerGrid, cellSize int) []models.LightSource {
+	lights := make([]models.LightSource, 0, len(uvttLights))
+	if pixelsPerGrid <= 0 {
+		return lights
+	}
+
+	for _, l := range uvttLights {
+		radius := l.Range * cellSize
+		lights = append(lights, models.LightSource{
+			ID:       uuid.NewString(),
+			Position: models.Position{X: l.Position.X / pixelsPerGrid, Y: l.Position.Y / pixelsPerGrid},
+			Bright:   radius,
+			Dim:      radius,
+		})
+	}
+
+	return lights
+}
+
 // ConvertFromFVTTScene converts FVTT Scene data to a Map model
 func (c *MapConverter) ConvertFromFVTTScene(scene *format.FVTTScene, opts format.ImportOptions) (*models.Map, error) {
 	// Calculate grid dimensions
@@ -283,6 +313,16 @@ func (c *MapConverter) ConvertFromFVTTScene(scene *format.FVTTScene, opts format
 		}
 	}
 
+	// Convert lights if requested
+	if opts.ImportLights {
+		gameMap.AmbientLight = fvttAmbientLight(scene)
+		for _, light := range c.convertFVTTLights(scene.Lights, scene.Grid) {
+			if err := gameMap.AddLight(light); err != nil {
+				continue
+			}
+		}
+	}
+
 	// Convert tokens if requested
 	if opts.ImportTokens {
 		tokens := c.convertFVTTTokens(scene.Tokens, scene.Grid)
@@ -356,6 +396,60 @@ func (c *MapConverter) convertFVTTWalls(fvttWalls []format.FVTTWall, gridSize in
 	return walls
 }
 
+// convertFVTTLights converts FVTT ambient lights to model light sources
+// FVTT light radii are already in scene distance units (feet)
+func (c *MapConverter) convertFVTTLights(fvttLights []format.FVTTLight, gridSize int) []models.LightSource {
+	lights := make([]models.LightSource, 0, len(fvttLights))
+	if gridSize <= 0 {
+		return lights
+	}
+
+	for _, l := range fvttLights {
+		id := l.ID
+		if id == "" {
+			id = uuid.NewString()
+		}
+
+		bright := l.GetBrightInt()
+		dim := l.GetDimInt()
+		if dim < bright {
+			dim = bright
+		}
+
+		lights = append(lights, models.LightSource{
+			ID:       id,
+			Position: models.Position{X: l.GetXInt() / gridSize, Y: l.GetYInt() / gridSize},
+			Bright:   bright,
+			Dim:      dim,
+		})
+	}
+
+	return lights
+}
+
+// fvttAmbientLight derives the map's ambient light from the scene darkness level
+// Global illumination lights the whole scene while darkness is at or below its threshold
+func fvttAmbientLight(scene *format.FVTTScene) models.LightLevel {
+	if scene.GlobalLight {
+		threshold := 1.0
+		if scene.GlobalLightThreshold != nil {
+			threshold = *scene.GlobalLightThreshold
+		}
+		if scene.Darkness <= threshold {
+			return models.LightBright
+		}
+	}
+
+	switch {
+	case scene.Darkness >= 0.75:
+		return models.LightDarkness
+	case scene.Darkness >= 0.25:
+		return models.LightDim
+	default:
+		return models.LightBright
+	}
+}
+
 // convertFVTTTokens converts FVTT tokens to model tokens
 func (c *MapConverter) convertFVTTTokens(fvttTokens []format.FVTTToken, gridSize int) []models.Token {
 	tokens := make([]models.Token, 0, len(fvttTokens))
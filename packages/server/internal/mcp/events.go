@@ -0,0 +1,263 @@
+// Package mcp provides MCP (Model Context Protocol) server implementation
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Game event endpoint constants
+const (
+	// EventsEndpoint lists a session's events after a cursor
+	EventsEndpoint = "/mcp/events"
+	// EventStreamEndpoint streams a session's events as server-sent events
+	EventStreamEndpoint = "/mcp/events/stream"
+	// LastEventIDHeader carries the cursor a reconnecting SSE client resumes after
+	LastEventIDHeader = "Last-Event-ID"
+
+	// defaultEventPage is the number of events returned when no limit is given
+	defaultEventPage = 100
+	// maxEventPage caps the number of events returned by one request
+	maxEventPage = 500
+)
+
+// Event is a game event delivered to clients; SessionID is the campaign the event belongs to
+type Event struct {
+	Cursor    int64                  `json:"cursor"`
+	Type      string                 `json:"type"`
+	SessionID string                 `json:"session_id"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// EventsResponse is the result of polling the events endpoint.
+// Cursor is the position to poll after next: the last returned event's cursor,
+// or the latest cursor when the request did not give one.
+type EventsResponse struct {
+	Events []Event `json:"events"`
+	Cursor int64   `json:"cursor"`
+}
+
+// EventView returns an event as the caller may see it, or false when they may not see it
+type EventView func(event Event) (Event, bool)
+
+// EventSource supplies a session's game events
+type EventSource interface {
+	// View checks the request's principal may follow a session's events and returns how
+	// each event is shown to them. It returns an error wrapping ErrForbidden when they may not.
+	View(ctx context.Context, sessionID string) (EventView, error)
+	// Events lists up to limit events after a cursor, optionally limited to some types
+	Events(ctx context.Context, sessionID string, after int64, types []string, limit int) ([]Event, error)
+	// Latest returns the cursor of the session's most recent event
+	Latest(ctx context.Context, sessionID string) (int64, error)
+	// Subscribe delivers new events until cancel is called. The channel is closed
+	// when the subscriber falls too far behind and must resume from its last cursor.
+	Subscribe(sessionID string, types []string) (events <-chan Event, cancel func())
+}
+
+// eventSource returns the configured event source, if any
+func (s *Server) eventSource() EventSource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.events
+}
+
+// handleListEvents returns a session's events after the "after" cursor.
+// Without a cursor it returns no events and the latest cursor, so a new client
+// starts following the session from now on.
+func (s *Server) handleListEvents(c *gin.Context) {
+	source, view, sessionID, types, ok := s.eventRequest(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultEventPage
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = min(n, maxEventPage)
+	}
+
+	ctx := c.Request.Context()
+	raw := c.Query("after")
+	if raw == "" {
+		cursor, err := source.Latest(ctx, sessionID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, EventsResponse{Events: []Event{}, Cursor: cursor})
+		return
+	}
+
+	after, err := parseCursor(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	events, err := source.Events(ctx, sessionID, after, types, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	cursor := after
+	if len(events) > 0 {
+		cursor = events[len(events)-1].Cursor
+	}
+	c.JSON(http.StatusOK, EventsResponse{Events: viewEvents(view, events), Cursor: cursor})
+}
+
+// handleEventStream streams a session's events as server-sent events whose ids are
+// event cursors. A client resuming with Last-Event-ID (or "after") first receives the
+// events it missed; otherwise the stream starts with the next event.
+func (s *Server) handleEventStream(c *gin.Context) {
+	source, view, sessionID, types, ok := s.eventRequest(c)
+	if !ok {
+		return
+	}
+
+	resume := c.GetHeader(LastEventIDHeader)
+	if resume == "" {
+		resume = c.Query("after")
+	}
+	var last int64
+	replay := resume != ""
+	if replay {
+		var err error
+		if last, err = parseCursor(resume); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Subscribe before replaying so no event falls between the two
+	live, cancel := source.Subscribe(sessionID, types)
+	defer cancel()
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for replay {
+		missed, err := source.Events(ctx, sessionID, last, types, maxEventPage)
+		if err != nil {
+			return
+		}
+		for _, event := range missed {
+			if shown, ok := view(event); ok {
+				writeSSEEvent(c.Writer, shown)
+			}
+			last = event.Cursor
+		}
+		replay = len(missed) == maxEventPage
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-live:
+			if !ok {
+				// Fell behind; the client reconnects with Last-Event-ID
+				return
+			}
+			// Events are delivered as their transactions commit, which need not be in
+			// cursor order, so only the ones already replayed are skipped
+			if event.Cursor <= last {
+				continue
+			}
+			if shown, ok := view(event); ok {
+				writeSSEEvent(c.Writer, shown)
+			}
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// eventRequest resolves the event source, the caller's view, session id and type filter of an
+// events request, writing the error response and returning false when any is missing or the
+// caller may not follow the session
+func (s *Server) eventRequest(c *gin.Context) (EventSource, EventView, string, []string, bool) {
+	source := s.eventSource()
+	if source == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "game events are not enabled"})
+		return nil, nil, "", nil, false
+	}
+
+	sessionID := c.Query("session_id")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session_id is required"})
+		return nil, nil, "", nil, false
+	}
+
+	view, err := source.View(c.Request.Context(), sessionID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return nil, nil, "", nil, false
+	}
+
+	var types []string
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return source, view, sessionID, types, true
+}
+
+// viewEvents applies the caller's view to a page of events
+func viewEvents(view EventView, events []Event) []Event {
+	shown := make([]Event, 0, len(events))
+	for _, event := range events {
+		if event, ok := view(event); ok {
+			shown = append(shown, event)
+		}
+	}
+	return shown
+}
+
+// parseCursor parses an event cursor
+func parseCursor(raw string) (int64, error) {
+	cursor, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || cursor < 0 {
+		return 0, fmt.Errorf("invalid event cursor %q", raw)
+	}
+	return cursor, nil
+}
+
+// writeSSEEvent writes a game event as an SSE message and flushes it
+func writeSSEEvent(w gin.ResponseWriter, event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Cursor, event.Type, data)
+	w.Flush()
+}
@@ -0,0 +1,366 @@
+// Package mcp provides MCP (Model Context Protocol) types and server implementation
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// JSONRPCVersion is the only JSON-RPC version MCP speaks
+const JSONRPCVersion = "2.0"
+
+// Standard JSON-RPC 2.0 error codes
+const (
+	ErrCodeParse          = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternal       = -32603
+)
+
+// LatestProtocolVersion is the newest MCP revision this server implements
+const LatestProtocolVersion = "2025-06-18"
+
+// SupportedProtocolVersions lists the MCP revisions this server can negotiate, newest first
+var SupportedProtocolVersions = []string{LatestProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// MCP method names
+const (
+	MethodInitialize  = "initialize"
+	MethodInitialized = "notifications/initialized"
+	MethodCancelled   = "notifications/cancelled"
+	MethodPing        = "ping"
+	MethodToolsList   = "tools/list"
+	MethodToolsCall   = "tools/call"
+
+	MethodResourcesList         = "resources/list"
+	MethodResourceTemplatesList = "resources/templates/list"
+	MethodResourcesRead         = "resources/read"
+	MethodResourcesSubscribe    = "resources/subscribe"
+	MethodResourcesUnsubscribe  = "resources/unsubscribe"
+	MethodResourceUpdated       = "notifications/resources/updated"
+
+	MethodPromptsList = "prompts/list"
+	MethodPromptsGet  = "prompts/get"
+)
+
+// RPCRequest is a JSON-RPC 2.0 request or notification (a request without an id)
+type RPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether the request expects no response
+func (r *RPCRequest) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// RPCResponse is a JSON-RPC 2.0 response carrying either a result or an error
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC 2.0 error object
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// RPCNotification is a server-to-client JSON-RPC 2.0 notification
+type RPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// nullID is used for responses to messages whose id could not be read
+var nullID = json.RawMessage("null")
+
+// newRPCResult creates a successful response
+func newRPCResult(id json.RawMessage, result interface{}) *RPCResponse {
+	return &RPCResponse{JSONRPC: JSONRPCVersion, ID: id, Result: result}
+}
+
+// newRPCError creates an error response
+func newRPCError(id json.RawMessage, code int, message string) *RPCResponse {
+	if len(id) == 0 {
+		id = nullID
+	}
+	return &RPCResponse{JSONRPC: JSONRPCVersion, ID: id, Error: &RPCError{Code: code, Message: message}}
+}
+
+// jsonrpcCallParams are the params of a tools/call request
+type jsonrpcCallParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// parseMessages decodes a single JSON-RPC message or a batch.
+// A parse failure is returned as a ready-to-send error response.
+func parseMessages(body []byte) ([]*RPCRequest, bool, *RPCResponse) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, false, newRPCError(nil, ErrCodeInvalidRequest, "empty message")
+	}
+
+	if body[0] == '[' {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, true, newRPCError(nil, ErrCodeParse, "parse error: "+err.Error())
+		}
+		if len(raw) == 0 {
+			return nil, true, newRPCError(nil, ErrCodeInvalidRequest, "empty batch")
+		}
+		requests := make([]*RPCRequest, len(raw))
+		for i, msg := range raw {
+			requests[i] = decodeRequest(msg)
+		}
+		return requests, true, nil
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, false, newRPCError(nil, ErrCodeParse, "parse error: "+err.Error())
+	}
+	return []*RPCRequest{decodeRequest(body)}, false, nil
+}
+
+// decodeRequest decodes one message; malformed messages come back with an empty method
+// so dispatch reports them as invalid requests
+func decodeRequest(msg json.RawMessage) *RPCRequest {
+	var req RPCRequest
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return &RPCRequest{}
+	}
+	return &req
+}
+
+// handleMessages dispatches every message in a body and returns the responses to send back.
+// Notifications produce no response; the result is nil when nothing needs to be sent.
+func (s *Server) handleMessages(ctx context.Context, sess *session, requests []*RPCRequest) []*RPCResponse {
+	responses := make([]*RPCResponse, 0, len(requests))
+	for _, req := range requests {
+		if resp := s.dispatch(ctx, sess, req); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+// dispatch routes a single JSON-RPC message to its MCP method
+func (s *Server) dispatch(ctx context.Context, sess *session, req *RPCRequest) *RPCResponse {
+	if req.JSONRPC != JSONRPCVersion || req.Method == "" || string(req.ID) == "null" {
+		return newRPCError(req.ID, ErrCodeInvalidRequest, "invalid request")
+	}
+
+	if req.IsNotification() {
+		switch req.Method {
+		case MethodInitialized:
+			sess.markInitialized()
+		}
+		// Other notifications (cancellation, progress) need no handling
+		return nil
+	}
+
+	switch req.Method {
+	case MethodInitialize:
+		return s.rpcInitialize(sess, req)
+	case MethodPing:
+		return newRPCResult(req.ID, struct{}{})
+	case MethodToolsList:
+		return newRPCResult(req.ID, ListToolsResponse{Tools: s.sortedTools(ctx)})
+	case MethodToolsCall:
+		return s.rpcCallTool(ctx, req)
+	case MethodResourcesList:
+		return s.rpcListResources(ctx, req)
+	case MethodResourceTemplatesList:
+		return newRPCResult(req.ID, ListResourceTemplatesResponse{ResourceTemplates: s.resources.Templates()})
+	case MethodResourcesRead:
+		return s.rpcReadResource(ctx, req)
+	case MethodResourcesSubscribe:
+		return s.rpcSubscribe(sess, req, true)
+	case MethodResourcesUnsubscribe:
+		return s.rpcSubscribe(sess, req, false)
+	case MethodPromptsList:
+		return newRPCResult(req.ID, ListPromptsResponse{Prompts: s.prompts.List()})
+	case MethodPromptsGet:
+		return s.rpcGetPrompt(ctx, req)
+	default:
+		return newRPCError(req.ID, ErrCodeMethodNotFound, fmt.Sprintf("method not found: %s", req.Method))
+	}
+}
+
+// rpcInitialize negotiates the protocol version and returns the server capabilities
+func (s *Server) rpcInitialize(sess *session, req *RPCRequest) *RPCResponse {
+	var params InitializeRequest
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newRPCError(req.ID, ErrCodeInvalidParams, "invalid params: "+err.Error())
+		}
+	}
+
+	version := NegotiateProtocolVersion(params.ProtocolVersion)
+	sess.setClient(version, params.ClientInfo)
+
+	s.mu.Lock()
+	s.initialized = true
+	s.mu.Unlock()
+
+	capabilities := ServerCapabilities{
+		Tools: &ToolsCapability{
+			ListChanged: false,
+		},
+	}
+	if s.resources.Count() > 0 {
+		capabilities.Resources = &ResourcesCapability{Subscribe: true}
+	}
+	if s.prompts.Count() > 0 {
+		capabilities.Prompts = &PromptsCapability{}
+	}
+
+	return newRPCResult(req.ID, InitializeResponse{
+		ProtocolVersion: version,
+		Capabilities:    capabilities,
+		ServerInfo:      serverInfo,
+	})
+}
+
+// rpcCallTool invokes a registered tool; tool failures are reported in the result, not as protocol errors
+func (s *Server) rpcCallTool(ctx context.Context, req *RPCRequest) *RPCResponse {
+	var params jsonrpcCallParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return newRPCError(req.ID, ErrCodeInvalidParams, "invalid params: "+err.Error())
+	}
+	if params.Name == "" {
+		return newRPCError(req.ID, ErrCodeInvalidParams, "tool name is required")
+	}
+	if !s.registry.Has(params.Name) {
+		return newRPCError(req.ID, ErrCodeInvalidParams, fmt.Sprintf("unknown tool: %s", params.Name))
+	}
+
+	args := params.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	resp := s.registry.Call(ctx, ToolRequest{ToolName: params.Name, Arguments: args})
+	return newRPCResult(req.ID, CallToolResponse{
+		Content: resp.Content,
+		IsError: resp.IsError,
+	})
+}
+
+// jsonrpcResourceParams are the params of resources/read, subscribe and unsubscribe
+type jsonrpcResourceParams struct {
+	URI string `json:"uri"`
+}
+
+// rpcListResources enumerates the concrete resources
+func (s *Server) rpcListResources(ctx context.Context, req *RPCRequest) *RPCResponse {
+	resources, err := s.resources.List(ctx)
+	if err != nil {
+		return newRPCError(req.ID, ErrCodeInternal, err.Error())
+	}
+	return newRPCResult(req.ID, ListResourcesResponse{Resources: resources})
+}
+
+// rpcReadResource reads a resource by URI
+func (s *Server) rpcReadResource(ctx context.Context, req *RPCRequest) *RPCResponse {
+	var params jsonrpcResourceParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return newRPCError(req.ID, ErrCodeInvalidParams, "resource uri is required")
+	}
+
+	contents, err := s.resources.Read(ctx, params.URI)
+	if err != nil {
+		if errors.Is(err, ErrResourceNotFound) {
+			return newRPCError(req.ID, ErrCodeResourceNotFound, err.Error())
+		}
+		return newRPCError(req.ID, ErrCodeInternal, err.Error())
+	}
+	return newRPCResult(req.ID, ReadResourceResponse{Contents: contents})
+}
+
+// rpcSubscribe subscribes the session to, or unsubscribes it from, updates of a resource
+func (s *Server) rpcSubscribe(sess *session, req *RPCRequest, subscribe bool) *RPCResponse {
+	var params jsonrpcResourceParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return newRPCError(req.ID, ErrCodeInvalidParams, "resource uri is required")
+	}
+
+	if subscribe {
+		sess.subscribe(params.URI)
+	} else {
+		sess.unsubscribe(params.URI)
+	}
+	return newRPCResult(req.ID, struct{}{})
+}
+
+// jsonrpcPromptParams are the params of prompts/get
+type jsonrpcPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// rpcGetPrompt renders a prompt; unknown prompts and bad arguments are invalid params
+func (s *Server) rpcGetPrompt(ctx context.Context, req *RPCRequest) *RPCResponse {
+	var params jsonrpcPromptParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+		return newRPCError(req.ID, ErrCodeInvalidParams, "prompt name is required")
+	}
+
+	result, err := s.prompts.Get(ctx, params.Name, params.Arguments)
+	if err != nil {
+		if errors.Is(err, ErrPromptNotFound) || errors.Is(err, ErrInvalidPromptArguments) {
+			return newRPCError(req.ID, ErrCodeInvalidParams, err.Error())
+		}
+		if errors.Is(err, ErrForbidden) {
+			return newRPCError(req.ID, ErrCodeInvalidRequest, err.Error())
+		}
+		return newRPCError(req.ID, ErrCodeInternal, err.Error())
+	}
+	return newRPCResult(req.ID, result)
+}
+
+// sortedTools lists the tools the caller may use in a stable order
+func (s *Server) sortedTools(ctx context.Context) []Tool {
+	tools := s.registry.ListFor(ctx)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// NegotiateProtocolVersion returns the requested version when supported, otherwise the latest one
+func NegotiateProtocolVersion(requested string) string {
+	if IsSupportedProtocolVersion(requested) {
+		return requested
+	}
+	return LatestProtocolVersion
+}
+
+// IsSupportedProtocolVersion reports whether the server implements an MCP revision
+func IsSupportedProtocolVersion(version string) bool {
+	for _, v := range SupportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+// Package mcp provides MCP (Model Context Protocol) types and server implementation
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dnd-mcp/server/internal/auth"
+)
+
+// ErrPromptNotFound is returned when prompts/get names an unregistered prompt
+var ErrPromptNotFound = errors.New("prompt not found")
+
+// ErrInvalidPromptArguments is returned when prompt arguments are missing or do not
+// address existing game data
+var ErrInvalidPromptArguments = errors.New("invalid prompt arguments")
+
+// PromptArgument describes an argument a prompt template accepts
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Prompt is a prompt template offered to clients
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptMessage is one message of a rendered prompt; Role is "user" or "assistant"
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+// GetPromptResponse is the result of prompts/get
+type GetPromptResponse struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// ListPromptsResponse is the result of prompts/list
+type ListPromptsResponse struct {
+	Prompts []Prompt `json:"prompts"`
+}
+
+// PromptsCapability describes prompt capabilities
+type PromptsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// PromptHandler renders a prompt from its arguments
+type PromptHandler func(ctx context.Context, args map[string]string) (*GetPromptResponse, error)
+
+// NewUserPrompt creates a prompt result holding a single user text message
+func NewUserPrompt(description, text string) *GetPromptResponse {
+	return &GetPromptResponse{
+		Description: description,
+		Messages: []PromptMessage{
+			{Role: "user", Content: Content{Type: "text", Text: text}},
+		},
+	}
+}
+
+// promptEntry is a registered prompt with its handler
+type promptEntry struct {
+	prompt  Prompt
+	handler PromptHandler
+}
+
+// PromptRegistry manages prompt templates
+type PromptRegistry struct {
+	mu      sync.RWMutex
+	prompts map[string]*promptEntry
+}
+
+// NewPromptRegistry creates an empty prompt registry
+func NewPromptRegistry() *PromptRegistry {
+	return &PromptRegistry{prompts: make(map[string]*promptEntry)}
+}
+
+// Register registers a prompt
+func (r *PromptRegistry) Register(prompt Prompt, handler PromptHandler) error {
+	if prompt.Name == "" {
+		return fmt.Errorf("prompt name cannot be empty")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.prompts[prompt.Name]; exists {
+		return fmt.Errorf("prompt %q already registered", prompt.Name)
+	}
+	r.prompts[prompt.Name] = &promptEntry{prompt: prompt, handler: handler}
+	return nil
+}
+
+// MustRegister registers a prompt and panics on error
+func (r *PromptRegistry) MustRegister(prompt Prompt, handler PromptHandler) {
+	if err := r.Register(prompt, handler); err != nil {
+		panic(err)
+	}
+}
+
+// List returns all registered prompts sorted by name
+func (r *PromptRegistry) List() []Prompt {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prompts := make([]Prompt, 0, len(r.prompts))
+	for _, entry := range r.prompts {
+		prompts = append(prompts, entry.prompt)
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts
+}
+
+// Get checks the required arguments and renders a prompt
+func (r *PromptRegistry) Get(ctx context.Context, name string, args map[string]string) (*GetPromptResponse, error) {
+	r.mu.RLock()
+	entry, ok := r.prompts[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+	// Prompts are DM material and may reveal hidden NPC stats
+	if principal, ok := auth.FromContext(ctx); ok && !principal.IsDM() {
+		return nil, fmt.Errorf("%w: role %q may not use prompt %s", ErrForbidden, principal.Role, name)
+	}
+	if campaignID := args["campaign_id"]; campaignID != "" && !auth.RunsCampaign(ctx, campaignID) {
+		return nil, fmt.Errorf("%w: prompts of campaign %s are for its DM", ErrForbidden, campaignID)
+	}
+	for _, arg := range entry.prompt.Arguments {
+		if arg.Required && args[arg.Name] == "" {
+			return nil, fmt.Errorf("%w: %s is required", ErrInvalidPromptArguments, arg.Name)
+		}
+	}
+	if args == nil {
+		args = make(map[string]string)
+	}
+	return entry.handler(ctx, args)
+}
+
+// Count returns the number of registered prompts
+func (r *PromptRegistry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.prompts)
+}
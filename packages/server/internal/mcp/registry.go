@@ -3,23 +3,102 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
+
+	"github.com/dnd-mcp/server/internal/auth"
+)
+
+// Access is the least privileged role allowed to call a tool
+type Access int
+
+// Tool access levels
+const (
+	// AccessDM tools change the game world: create NPCs, start combat, delete data.
+	// It is the default for tools without an explicit access level.
+	AccessDM Access = iota
+	// AccessPlayer tools act through a character; services check the player owns it
+	AccessPlayer
+	// AccessRead tools only read and are open to observers
+	AccessRead
 )
 
+// ErrForbidden is returned when the caller's role may not use a tool
+var ErrForbidden = errors.New("forbidden")
+
+// ToolMiddleware wraps every tool call, e.g. to record what the call changed.
+// It runs after the access check and argument validation; next invokes the tool.
+type ToolMiddleware func(ctx context.Context, req ToolRequest, next ToolHandler) ToolResponse
+
+// CampaignResolver returns the campaigns a tool call's arguments refer to
+type CampaignResolver func(ctx context.Context, arguments json.RawMessage) ([]string, error)
+
 // Registry manages tool registration and lookup
 type Registry struct {
-	mu     sync.RWMutex
-	tools  map[string]*ToolInfo
+	mu         sync.RWMutex
+	tools      map[string]*ToolInfo
+	access     map[string]Access
+	middleware []ToolMiddleware
+	campaigns  CampaignResolver
 }
 
 // NewRegistry creates a new tool registry
 func NewRegistry() *Registry {
 	return &Registry{
-		tools: make(map[string]*ToolInfo),
+		tools:  make(map[string]*ToolInfo),
+		access: make(map[string]Access),
 	}
 }
 
+// Use adds a middleware around every tool call.
+// Middleware added first is outermost.
+func (r *Registry) Use(mw ToolMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, mw)
+}
+
+// SetAccess sets the least privileged role allowed to call a tool
+func (r *Registry) SetAccess(name string, access Access) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.access[name] = access
+}
+
+// SetCampaignResolver limits DMs to the campaigns they run.
+// A DM calling a tool on another campaign's records has observer access there:
+// read tools still work (the services redact them), every other tool is refused.
+func (r *Registry) SetCampaignResolver(resolver CampaignResolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.campaigns = resolver
+}
+
+// Allowed reports whether the request's principal may call a tool.
+// Requests without a principal (stdio, auth disabled) may call every tool.
+// DMs may call every tool here; Call also checks they run the campaign the call refers to.
+func (r *Registry) Allowed(ctx context.Context, name string) bool {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.IsDM() {
+		return true
+	}
+
+	r.mu.RLock()
+	access := r.access[name]
+	r.mu.RUnlock()
+
+	switch principal.Role {
+	case auth.RolePlayer:
+		return access == AccessPlayer || access == AccessRead
+	case auth.RoleObserver:
+		return access == AccessRead
+	}
+	return false
+}
+
 // Register registers a tool with its handler
 func (r *Registry) Register(tool Tool, handler ToolHandler) error {
 	if tool.Name == "" {
@@ -72,17 +151,78 @@ func (r *Registry) List() []Tool {
 	return tools
 }
 
+// ListFor returns the tools the request's principal may call
+func (r *Registry) ListFor(ctx context.Context) []Tool {
+	tools := r.List()
+	allowed := tools[:0]
+	for _, tool := range tools {
+		if r.Allowed(ctx, tool.Name) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
 // Call executes a tool by name
 func (r *Registry) Call(ctx context.Context, req ToolRequest) ToolResponse {
 	r.mu.RLock()
 	info, ok := r.tools[req.ToolName]
+	middleware := r.middleware
 	r.mu.RUnlock()
 
 	if !ok {
 		return NewErrorResponse(fmt.Errorf("unknown tool: %s", req.ToolName))
 	}
 
-	return info.Handler(ctx, req)
+	if !r.Allowed(ctx, req.ToolName) {
+		principal, _ := auth.FromContext(ctx)
+		return NewErrorResponse(fmt.Errorf("%w: role %q may not use tool %s", ErrForbidden, principal.Role, req.ToolName))
+	}
+
+	// Reject arguments that do not match the tool's schema before dispatch
+	if errs := ValidateArguments(info.Tool.InputSchema, req.Arguments); len(errs) > 0 {
+		return NewErrorResponse(&ValidationError{Tool: req.ToolName, Errors: errs})
+	}
+
+	if err := r.authorizeCampaigns(ctx, req); err != nil {
+		return NewErrorResponse(err)
+	}
+
+	handler := info.Handler
+	for i := len(middleware) - 1; i >= 0; i-- {
+		mw, next := middleware[i], handler
+		handler = func(ctx context.Context, req ToolRequest) ToolResponse {
+			return mw(ctx, req, next)
+		}
+	}
+	return handler(ctx, req)
+}
+
+// authorizeCampaigns checks that a DM runs every campaign a call to a non-read tool refers to
+func (r *Registry) authorizeCampaigns(ctx context.Context, req ToolRequest) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.Role != auth.RoleDM {
+		return nil
+	}
+
+	r.mu.RLock()
+	resolver := r.campaigns
+	access := r.access[req.ToolName]
+	r.mu.RUnlock()
+	if resolver == nil || access == AccessRead {
+		return nil
+	}
+
+	campaigns, err := resolver(ctx, req.Arguments)
+	if err != nil {
+		return fmt.Errorf("failed to resolve campaign: %w", err)
+	}
+	for _, campaignID := range campaigns {
+		if campaignID != "" && !auth.RunsCampaign(ctx, campaignID) {
+			return fmt.Errorf("%w: %s does not run campaign %s", ErrForbidden, principal.UserID, campaignID)
+		}
+	}
+	return nil
 }
 
 // Has checks if a tool is registered
@@ -164,6 +304,64 @@ func PropWithDefault(typ, desc string, def interface{}) Property {
 	}
 }
 
+// ArrayOf creates an array property whose elements match items
+func ArrayOf(desc string, items Property) Property {
+	return Property{
+		Type:        "array",
+		Description: desc,
+		Items:       &items,
+	}
+}
+
+// ObjectOf creates a nested object property
+func ObjectOf(desc string, properties map[string]Property, required []string) Property {
+	return Property{
+		Type:        "object",
+		Description: desc,
+		Properties:  properties,
+		Required:    required,
+	}
+}
+
+// IntRange creates an integer property bounded by min and max (inclusive)
+func IntRange(desc string, min, max int) Property {
+	lo, hi := float64(min), float64(max)
+	return Property{
+		Type:        "integer",
+		Description: desc,
+		Minimum:     &lo,
+		Maximum:     &hi,
+	}
+}
+
+// IntMin creates an integer property with a lower bound (inclusive)
+func IntMin(desc string, min int) Property {
+	lo := float64(min)
+	return Property{
+		Type:        "integer",
+		Description: desc,
+		Minimum:     &lo,
+	}
+}
+
+// NumberRange creates a number property bounded by min and max (inclusive)
+func NumberRange(desc string, min, max float64) Property {
+	return Property{
+		Type:        "number",
+		Description: desc,
+		Minimum:     &min,
+		Maximum:     &max,
+	}
+}
+
+// OneOf creates a property that must match exactly one of the alternatives
+func OneOf(desc string, alternatives ...Property) Property {
+	return Property{
+		Description: desc,
+		OneOf:       alternatives,
+	}
+}
+
 // Required marks property names as required
 func Required(names ...string) []string {
 	return names
@@ -0,0 +1,232 @@
+// Package mcp provides MCP (Model Context Protocol) types and server implementation
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrResourceNotFound is returned by resource handlers when the addressed resource does not exist
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrCodeResourceNotFound is the MCP error code for reading an unknown resource
+const ErrCodeResourceNotFound = -32002
+
+// Resource is a concrete resource a client can read
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceTemplate describes a family of resources addressed by an RFC 6570 URI template
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents is one representation of a resource: text, or base64 binary data in Blob
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// ResourceReadHandler reads a resource; params holds the values of the template variables
+type ResourceReadHandler func(ctx context.Context, uri string, params map[string]string) ([]ResourceContents, error)
+
+// ResourceLister enumerates the concrete resources of a template for resources/list
+type ResourceLister func(ctx context.Context) ([]Resource, error)
+
+// ListResourcesResponse is the result of resources/list
+type ListResourcesResponse struct {
+	Resources []Resource `json:"resources"`
+}
+
+// ListResourceTemplatesResponse is the result of resources/templates/list
+type ListResourceTemplatesResponse struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// ReadResourceResponse is the result of resources/read
+type ReadResourceResponse struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// ResourcesCapability describes resource capabilities
+type ResourcesCapability struct {
+	Subscribe   bool `json:"subscribe,omitempty"`
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// ResourceUpdatedParams are the params of notifications/resources/updated
+type ResourceUpdatedParams struct {
+	URI string `json:"uri"`
+}
+
+// templateVariable matches a simple {name} expression in a URI template
+var templateVariable = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// resourceEntry is a registered template with its compiled matcher
+type resourceEntry struct {
+	template ResourceTemplate
+	pattern  *regexp.Regexp
+	names    []string
+	handler  ResourceReadHandler
+	lister   ResourceLister
+}
+
+// ResourceRegistry manages resource templates and resolves URIs to their handlers
+type ResourceRegistry struct {
+	mu      sync.RWMutex
+	entries []*resourceEntry
+}
+
+// NewResourceRegistry creates an empty resource registry
+func NewResourceRegistry() *ResourceRegistry {
+	return &ResourceRegistry{}
+}
+
+// RegisterTemplate registers a resource template. Each {name} variable matches one
+// path segment. The lister may be nil when the resources cannot be enumerated.
+func (r *ResourceRegistry) RegisterTemplate(template ResourceTemplate, handler ResourceReadHandler, lister ResourceLister) error {
+	if template.URITemplate == "" {
+		return fmt.Errorf("resource template URI cannot be empty")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler cannot be nil")
+	}
+
+	pattern, names := compileURITemplate(template.URITemplate)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range r.entries {
+		if entry.template.URITemplate == template.URITemplate {
+			return fmt.Errorf("resource template %q already registered", template.URITemplate)
+		}
+	}
+	r.entries = append(r.entries, &resourceEntry{
+		template: template,
+		pattern:  pattern,
+		names:    names,
+		handler:  handler,
+		lister:   lister,
+	})
+	return nil
+}
+
+// MustRegisterTemplate registers a resource template and panics on error
+func (r *ResourceRegistry) MustRegisterTemplate(template ResourceTemplate, handler ResourceReadHandler, lister ResourceLister) {
+	if err := r.RegisterTemplate(template, handler, lister); err != nil {
+		panic(err)
+	}
+}
+
+// Templates returns all registered templates
+func (r *ResourceRegistry) Templates() []ResourceTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]ResourceTemplate, len(r.entries))
+	for i, entry := range r.entries {
+		templates[i] = entry.template
+	}
+	return templates
+}
+
+// List enumerates the concrete resources of every template, sorted by URI
+func (r *ResourceRegistry) List(ctx context.Context) ([]Resource, error) {
+	r.mu.RLock()
+	entries := append([]*resourceEntry(nil), r.entries...)
+	r.mu.RUnlock()
+
+	resources := make([]Resource, 0)
+	for _, entry := range entries {
+		if entry.lister == nil {
+			continue
+		}
+		listed, err := entry.lister(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", entry.template.Name, err)
+		}
+		resources = append(resources, listed...)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+	return resources, nil
+}
+
+// Read resolves a URI against the registered templates and reads it
+func (r *ResourceRegistry) Read(ctx context.Context, uri string) ([]ResourceContents, error) {
+	r.mu.RLock()
+	entries := append([]*resourceEntry(nil), r.entries...)
+	r.mu.RUnlock()
+
+	for _, entry := range entries {
+		match := entry.pattern.FindStringSubmatch(uri)
+		if match == nil {
+			continue
+		}
+		params := make(map[string]string, len(entry.names))
+		for i, name := range entry.names {
+			params[name] = match[i+1]
+		}
+		return entry.handler(ctx, uri, params)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+}
+
+// Count returns the number of registered templates
+func (r *ResourceRegistry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.entries)
+}
+
+// compileURITemplate turns a template into an anchored regular expression and its variable names
+func compileURITemplate(template string) (*regexp.Regexp, []string) {
+	var pattern strings.Builder
+	names := make([]string, 0)
+	last := 0
+	for _, loc := range templateVariable.FindAllStringSubmatchIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		pattern.WriteString(`([^/]+)`)
+		names = append(names, template[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	return regexp.MustCompile("^" + pattern.String() + "$"), names
+}
+
+// ExpandURITemplate fills the {name} variables of a template
+func ExpandURITemplate(template string, values map[string]string) string {
+	return templateVariable.ReplaceAllStringFunc(template, func(expr string) string {
+		return values[expr[1:len(expr)-1]]
+	})
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated to every client subscribed to the URI.
+// It returns the number of clients notified.
+func (s *Server) NotifyResourceUpdated(uri string) int {
+	sessions := s.sessions.all()
+	sent := 0
+	for _, sess := range sessions {
+		if !sess.isSubscribed(uri) {
+			continue
+		}
+		if msg, err := encodeNotification(MethodResourceUpdated, ResourceUpdatedParams{URI: uri}); err == nil && sess.send(msg) {
+			sent++
+		}
+	}
+	return sent
+}
@@ -0,0 +1,531 @@
+// Package mcp provides MCP (Model Context Protocol) types and server implementation
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError describes one invalid tool argument.
+// Field is a path into the arguments such as "target.x" or "participant_ids[2]".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid argument of a tool call
+type ValidationError struct {
+	Tool   string       `json:"tool"`
+	Errors []FieldError `json:"errors"`
+}
+
+// Error lists each bad field on its own line so a model can fix them all in one retry
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid arguments for tool %q:", e.Tool)
+	for _, fe := range e.Errors {
+		if fe.Field == "" {
+			fmt.Fprintf(&b, "\n- %s", fe.Message)
+		} else {
+			fmt.Fprintf(&b, "\n- %s %s", fe.Field, fe.Message)
+		}
+	}
+	return b.String()
+}
+
+// ValidateArguments checks tool arguments against an input schema and returns every
+// violation found. Missing or null arguments are treated as an empty object, and a
+// null property counts as absent. Properties the schema does not declare are allowed.
+func ValidateArguments(schema InputSchema, args json.RawMessage) []FieldError {
+	value, err := decodeJSONValue(args)
+	if err != nil {
+		return []FieldError{{Message: "arguments must be valid JSON: " + err.Error()}}
+	}
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+
+	root := Property{
+		Type:       schema.Type,
+		Properties: schema.Properties,
+		Required:   schema.Required,
+	}
+	if root.Type == "" {
+		root.Type = "object"
+	}
+
+	var errs []FieldError
+	validateValue("", root, value, &errs)
+	if len(errs) > 0 && errs[0].Field == "" && root.Type == "object" {
+		if _, ok := value.(map[string]interface{}); !ok {
+			errs[0].Message = "arguments must be a JSON object"
+		}
+	}
+	return errs
+}
+
+// decodeJSONValue decodes raw JSON keeping numbers exact
+func decodeJSONValue(raw json.RawMessage) (interface{}, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// validateValue validates one value against a property, appending violations to errs
+func validateValue(path string, prop Property, value interface{}, errs *[]FieldError) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(prop.OneOf) > 0 {
+		matched := 0
+		for _, alternative := range prop.OneOf {
+			var alternativeErrs []FieldError
+			validateValue(path, alternative, value, &alternativeErrs)
+			if len(alternativeErrs) == 0 {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0:
+			fail("must match one of: %s", describeAlternatives(prop.OneOf))
+			return
+		case matched > 1:
+			fail("matches more than one of: %s", describeAlternatives(prop.OneOf))
+			return
+		}
+	}
+
+	if prop.Type != "" && !hasJSONType(value, prop.Type) {
+		fail("must be %s, got %s", withArticle(prop.Type), jsonTypeOf(value))
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		if len(prop.Enum) > 0 && !containsString(prop.Enum, v) {
+			fail("must be one of: %s (got %q)", strings.Join(prop.Enum, ", "), v)
+		}
+		length := utf8.RuneCountInString(v)
+		if prop.MinLength != nil && length < *prop.MinLength {
+			fail("must be at least %d characters long", *prop.MinLength)
+		}
+		if prop.MaxLength != nil && length > *prop.MaxLength {
+			fail("must be at most %d characters long", *prop.MaxLength)
+		}
+		if prop.Pattern != "" {
+			if re, err := compilePattern(prop.Pattern); err == nil && !re.MatchString(v) {
+				fail("must match pattern %s", prop.Pattern)
+			}
+		}
+
+	case json.Number:
+		n, _ := v.Float64()
+		below := prop.Minimum != nil && n < *prop.Minimum
+		above := prop.Maximum != nil && n > *prop.Maximum
+		switch {
+		case (below || above) && prop.Minimum != nil && prop.Maximum != nil:
+			fail("must be between %s and %s (got %s)", formatNumber(*prop.Minimum), formatNumber(*prop.Maximum), v)
+		case below:
+			fail("must be at least %s (got %s)", formatNumber(*prop.Minimum), v)
+		case above:
+			fail("must be at most %s (got %s)", formatNumber(*prop.Maximum), v)
+		}
+
+	case []interface{}:
+		if prop.MinItems != nil && len(v) < *prop.MinItems {
+			fail("must have at least %d items", *prop.MinItems)
+		}
+		if prop.MaxItems != nil && len(v) > *prop.MaxItems {
+			fail("must have at most %d items", *prop.MaxItems)
+		}
+		if prop.Items != nil {
+			for i, item := range v {
+				validateValue(fmt.Sprintf("%s[%d]", path, i), *prop.Items, item, errs)
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range prop.Required {
+			if v[name] == nil {
+				*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child, declared := prop.Properties[name]
+			if !declared || v[name] == nil {
+				continue
+			}
+			validateValue(joinPath(path, name), child, v[name], errs)
+		}
+	}
+}
+
+// hasJSONType reports whether a decoded JSON value has a JSON Schema type
+func hasJSONType(value interface{}, typ string) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// jsonTypeOf names the JSON type of a decoded value
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if hasJSONType(v, "integer") {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// describeAlternatives summarizes oneOf alternatives for an error message
+func describeAlternatives(alternatives []Property) string {
+	parts := make([]string, len(alternatives))
+	for i, alternative := range alternatives {
+		switch {
+		case alternative.Description != "":
+			parts[i] = alternative.Description
+		case alternative.Type != "":
+			parts[i] = alternative.Type
+		default:
+			parts[i] = "any value"
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// withArticle prefixes a JSON type name with "a" or "an"
+func withArticle(typ string) string {
+	if typ != "" && strings.ContainsRune("aeiou", rune(typ[0])) {
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+// joinPath appends a property name to an argument path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// formatNumber formats a schema bound without a trailing ".0"
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// patternCache holds compiled schema patterns
+var patternCache sync.Map
+
+// compilePattern compiles a schema pattern once
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// Schema derivation from Go request structs
+//
+// Properties are named after the fields' json tags; fields tagged json:"-" and unexported
+// fields are skipped and embedded structs are flattened. Two struct tags refine a field:
+//
+//	description:"Free text shown to the model"
+//	jsonschema:"required,minimum=1,maximum=20,enum=a|b|c,pattern=^[a-z]+$,minLength=1,maxLength=64,minItems=1,maxItems=10"
+//
+// Options in the jsonschema tag are comma separated, so patterns cannot contain commas.
+
+// SchemaFor derives the input schema of a tool from its request struct T.
+// It panics if T is not a struct or a jsonschema tag is malformed, since both are
+// programming errors found when the tool is registered.
+func SchemaFor[T any]() InputSchema {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("mcp: SchemaFor requires a struct, got %s", t))
+	}
+
+	prop := structProperty(t)
+	return InputSchema{
+		Type:       "object",
+		Properties: prop.Properties,
+		Required:   prop.Required,
+	}
+}
+
+// DecodeArguments decodes tool arguments into a new T.
+// Type mismatches are reported as a ValidationError naming the offending field.
+func DecodeArguments[T any](toolName string, args json.RawMessage) (*T, error) {
+	input := new(T)
+	if len(bytes.TrimSpace(args)) == 0 {
+		return input, nil
+	}
+
+	if err := json.Unmarshal(args, input); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, &ValidationError{Tool: toolName, Errors: []FieldError{{
+				Field:   typeErr.Field,
+				Message: fmt.Sprintf("must be %s, got %s", goTypeName(typeErr.Type), typeErr.Value),
+			}}}
+		}
+		return nil, &ValidationError{Tool: toolName, Errors: []FieldError{{
+			Message: "arguments must be a JSON object: " + err.Error(),
+		}}}
+	}
+	return input, nil
+}
+
+// TypedTool creates a tool whose input schema is derived from T and whose handler
+// receives the decoded arguments. The registry validates arguments against the schema
+// before dispatch, so handle only sees input that matches it.
+func TypedTool[T any](name, description string, handle func(ctx context.Context, input *T) ToolResponse) (Tool, ToolHandler) {
+	tool := NewTool(name, description, SchemaFor[T]())
+
+	handler := func(ctx context.Context, req ToolRequest) ToolResponse {
+		input, err := DecodeArguments[T](name, req.Arguments)
+		if err != nil {
+			return NewErrorResponse(err)
+		}
+		return handle(ctx, input)
+	}
+
+	return tool, handler
+}
+
+// structProperty derives an object property from a struct type
+func structProperty(t reflect.Type) Property {
+	prop := Property{Type: "object", Properties: map[string]Property{}}
+	addStructFields(&prop, t)
+	return prop
+}
+
+// addStructFields adds a struct's fields to an object property, flattening embedded structs
+func addStructFields(prop *Property, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(prop, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		child := typeProperty(field.Type)
+		child.Description = field.Tag.Get("description")
+		required := applySchemaTag(&child, field)
+		prop.Properties[name] = child
+		if required {
+			prop.Required = append(prop.Required, name)
+		}
+	}
+}
+
+// jsonFieldName returns a field's json name, or skip for json:"-"
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, false
+}
+
+// typeProperty maps a Go type to a JSON Schema property
+func typeProperty(t reflect.Type) Property {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return Property{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Property{Type: "string"}
+	case reflect.Bool:
+		return Property{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Property{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Property{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Property{Type: "string"}
+		}
+		items := typeProperty(t.Elem())
+		return Property{Type: "array", Items: &items}
+	case reflect.Map:
+		return Property{Type: "object"}
+	case reflect.Struct:
+		return structProperty(t)
+	}
+	// interface{} and other dynamic values accept anything
+	return Property{}
+}
+
+// applySchemaTag applies a field's jsonschema tag and reports whether it is required
+func applySchemaTag(prop *Property, field reflect.StructField) bool {
+	tag, ok := field.Tag.Lookup("jsonschema")
+	if !ok {
+		return false
+	}
+
+	required := false
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "":
+		case "required":
+			required = true
+		case "enum":
+			prop.Enum = strings.Split(value, "|")
+		case "pattern":
+			if _, err := compilePattern(value); err != nil {
+				panic(fmt.Sprintf("mcp: field %s: invalid pattern %q: %v", field.Name, value, err))
+			}
+			prop.Pattern = value
+		case "minimum":
+			prop.Minimum = parseFloatOption(field, key, value)
+		case "maximum":
+			prop.Maximum = parseFloatOption(field, key, value)
+		case "minLength":
+			prop.MinLength = parseIntOption(field, key, value)
+		case "maxLength":
+			prop.MaxLength = parseIntOption(field, key, value)
+		case "minItems":
+			prop.MinItems = parseIntOption(field, key, value)
+		case "maxItems":
+			prop.MaxItems = parseIntOption(field, key, value)
+		default:
+			panic(fmt.Sprintf("mcp: field %s: unknown jsonschema option %q", field.Name, key))
+		}
+	}
+	return required
+}
+
+// parseFloatOption parses a numeric jsonschema option
+func parseFloatOption(field reflect.StructField, key, value string) *float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("mcp: field %s: invalid %s %q", field.Name, key, value))
+	}
+	return &n
+}
+
+// parseIntOption parses an integer jsonschema option
+func parseIntOption(field reflect.StructField, key, value string) *int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		panic(fmt.Sprintf("mcp: field %s: invalid %s %q", field.Name, key, value))
+	}
+	return &n
+}
+
+// goTypeName names the JSON type expected for a Go type in decode errors
+func goTypeName(t reflect.Type) string {
+	typ := typeProperty(t).Type
+	if typ == "" {
+		return "a " + t.String()
+	}
+	return withArticle(typ)
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dnd-mcp/server/internal/auth"
 	"github.com/dnd-mcp/server/pkg/config"
 	"github.com/gin-gonic/gin"
 )
@@ -16,10 +17,20 @@ import (
 // Server represents an MCP server
 type Server struct {
 	registry    *Registry
+	resources   *ResourceRegistry
+	prompts     *PromptRegistry
+	events      EventSource
+	auth        *auth.Authenticator
+	campaignDMs auth.CampaignDMs
 	cfg         *config.Config
 	httpServer  *http.Server
+	sessions    *sessionStore
 	mu          sync.RWMutex
 	initialized bool
+
+	sweepOnce sync.Once     // starts the idle session sweep
+	stopSweep chan struct{} // closed on shutdown to stop the sweep
+	stopOnce  sync.Once
 }
 
 // ServerInfo contains server metadata
@@ -31,8 +42,12 @@ var serverInfo = ServerInfo{
 // NewServer creates a new MCP server
 func NewServer(cfg *config.Config) *Server {
 	return &Server{
-		registry: NewRegistry(),
-		cfg:      cfg,
+		registry:  NewRegistry(),
+		resources: NewResourceRegistry(),
+		prompts:   NewPromptRegistry(),
+		cfg:       cfg,
+		sessions:  newSessionStore(),
+		stopSweep: make(chan struct{}),
 	}
 }
 
@@ -41,6 +56,42 @@ func (s *Server) Registry() *Registry {
 	return s.registry
 }
 
+// Resources returns the resource registry for registering resource templates
+func (s *Server) Resources() *ResourceRegistry {
+	return s.resources
+}
+
+// Prompts returns the prompt registry for registering prompt templates
+func (s *Server) Prompts() *PromptRegistry {
+	return s.prompts
+}
+
+// SetEventSource enables the game event endpoints.
+// When set, clients can poll GET /mcp/events or follow GET /mcp/events/stream
+// to receive a campaign's game events as they happen.
+func (s *Server) SetEventSource(source EventSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = source
+}
+
+// SetAuthenticator requires every request except the health check to authenticate.
+// Callers pass an API key or JWT as "Authorization: Bearer <credential>" or "X-API-Key";
+// streaming clients that cannot set headers may use the access_token query parameter.
+func (s *Server) SetAuthenticator(authenticator *auth.Authenticator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = authenticator
+}
+
+// SetCampaignDMs looks up campaign DMs for authenticated requests.
+// Without it, DM principals run no campaign (see auth.RunsCampaign).
+func (s *Server) SetCampaignDMs(lookup auth.CampaignDMs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.campaignDMs = lookup
+}
+
 // Handler returns the HTTP handler for testing purposes
 // This allows tests to use the real routing configuration
 func (s *Server) Handler() http.Handler {
@@ -59,6 +110,11 @@ func (s *Server) Handler() http.Handler {
 		router.Use(corsMiddleware())
 	}
 
+	// Authentication middleware
+	if s.auth != nil {
+		router.Use(authMiddleware(s.auth, s.campaignDMs))
+	}
+
 	// Register routes
 	s.registerRoutes(router)
 
@@ -89,6 +145,11 @@ func (s *Server) Start(ctx context.Context) error {
 		router.Use(corsMiddleware())
 	}
 
+	// Authentication middleware
+	if s.auth != nil {
+		router.Use(authMiddleware(s.auth, s.campaignDMs))
+	}
+
 	// Register routes
 	s.registerRoutes(router)
 
@@ -113,6 +174,8 @@ func (s *Server) Start(ctx context.Context) error {
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopSweep) })
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -131,10 +194,20 @@ func (s *Server) registerRoutes(router *gin.Engine) {
 	// Health check
 	router.GET("/health", s.handleHealth)
 
-	// MCP endpoints
+	// MCP Streamable HTTP transport (JSON-RPC 2.0)
+	router.POST(StreamableEndpoint, s.handleStreamablePost)
+	router.GET(StreamableEndpoint, s.handleStreamableGet)
+	router.DELETE(StreamableEndpoint, s.handleStreamableDelete)
+	s.startSessionSweep()
+
+	// Legacy REST endpoints used by the bundled client
 	router.POST("/mcp/initialize", s.handleInitialize)
 	router.GET("/mcp/tools", s.handleListTools)
 	router.POST("/mcp/tools/call", s.handleCallTool)
+
+	// Game events consumed by the bundled client
+	router.GET(EventsEndpoint, s.handleListEvents)
+	router.GET(EventStreamEndpoint, s.handleEventStream)
 }
 
 // handleHealth handles health check requests
@@ -170,7 +243,7 @@ func (s *Server) handleInitialize(c *gin.Context) {
 
 // handleListTools handles tool listing requests
 func (s *Server) handleListTools(c *gin.Context) {
-	tools := s.registry.List()
+	tools := s.registry.ListFor(c.Request.Context())
 	c.JSON(http.StatusOK, ListToolsResponse{
 		Tools: tools,
 	})
@@ -211,8 +284,9 @@ func (s *Server) handleCallTool(c *gin.Context) {
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, X-API-Key, "+SessionHeader+", "+ProtocolVersionHeader)
+		c.Header("Access-Control-Expose-Headers", SessionHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -222,3 +296,33 @@ func corsMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// authMiddleware authenticates requests and stores the caller's principal, and the campaign DM
+// lookup it is checked against, in the request context
+func authMiddleware(authenticator *auth.Authenticator, campaignDMs auth.CampaignDMs) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.URL.Path == "/health" {
+			c.Next()
+			return
+		}
+
+		credential := auth.Credential(c.GetHeader("Authorization"), c.GetHeader("X-API-Key"))
+		if credential == "" {
+			credential = c.Query("access_token")
+		}
+
+		principal, err := authenticator.Authenticate(credential)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="dnd-mcp-server"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		if campaignDMs != nil {
+			ctx = auth.WithCampaignDMs(ctx, campaignDMs)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
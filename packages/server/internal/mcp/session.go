@@ -0,0 +1,233 @@
+// Package mcp provides MCP (Model Context Protocol) types and server implementation
+package mcp
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sessionStreamBuffer is how many server-initiated messages may queue for a slow stream
+const sessionStreamBuffer = 64
+
+// session is the state of one connected MCP client
+type session struct {
+	id     string
+	userID string // user who created the session; empty without authentication
+
+	mu              sync.Mutex
+	protocolVersion string
+	clientInfo      ClientInfo
+	initialized     bool
+	stream          chan []byte         // server-initiated messages; nil while no stream is open
+	subscriptions   map[string]struct{} // resource URIs the client subscribed to
+	lastSeen        time.Time           // latest Streamable HTTP request or stream detach; zero for stdio sessions, which never expire
+
+	closed    chan struct{} // closed when the session ends, ending its open stream
+	closeOnce sync.Once
+}
+
+// newSession creates a session with a random id
+func newSession() *session {
+	return &session{id: uuid.New().String(), closed: make(chan struct{})}
+}
+
+// touch records a request from the client
+func (s *session) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSeen = time.Now()
+}
+
+// idleSince reports whether the client has no open stream and its latest request came before cutoff
+func (s *session) idleSince(cutoff time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream == nil && !s.lastSeen.IsZero() && s.lastSeen.Before(cutoff)
+}
+
+// close ends the session and its open stream
+func (s *session) close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// done is closed when the session ends
+func (s *session) done() <-chan struct{} {
+	return s.closed
+}
+
+// setClient records the negotiated protocol version and client information
+func (s *session) setClient(version string, info ClientInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protocolVersion = version
+	s.clientInfo = info
+}
+
+// markInitialized records the client's notifications/initialized
+func (s *session) markInitialized() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initialized = true
+}
+
+// subscribe records a resource subscription
+func (s *session) subscribe(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscriptions == nil {
+		s.subscriptions = make(map[string]struct{})
+	}
+	s.subscriptions[uri] = struct{}{}
+}
+
+// unsubscribe removes a resource subscription
+func (s *session) unsubscribe(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscriptions, uri)
+}
+
+// isSubscribed reports whether the client subscribed to a resource
+func (s *session) isSubscribed(uri string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.subscriptions[uri]
+	return ok
+}
+
+// openStream attaches a stream for server-initiated messages; false if one is already open
+func (s *session) openStream() (chan []byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream != nil {
+		return nil, false
+	}
+	s.stream = make(chan []byte, sessionStreamBuffer)
+	return s.stream, true
+}
+
+// closeStream detaches the stream opened by openStream; the session is idle from then on
+func (s *session) closeStream(stream chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream == stream {
+		s.stream = nil
+		if !s.lastSeen.IsZero() {
+			s.lastSeen = time.Now()
+		}
+	}
+}
+
+// send queues a message on the open stream, dropping it when there is no stream or it is full
+func (s *session) send(msg []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream == nil {
+		return false
+	}
+	select {
+	case s.stream <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// sessionStore tracks the sessions of all connected clients
+type sessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*session
+}
+
+// newSessionStore creates an empty session store
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: make(map[string]*session)}
+}
+
+// add registers a session
+func (st *sessionStore) add(sess *session) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sessions[sess.id] = sess
+}
+
+// get looks up a session by id
+func (st *sessionStore) get(id string) (*session, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	sess, ok := st.sessions[id]
+	return sess, ok
+}
+
+// remove closes and forgets a session, reporting whether it existed
+func (st *sessionStore) remove(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	sess, ok := st.sessions[id]
+	if !ok {
+		return false
+	}
+	sess.close()
+	delete(st.sessions, id)
+	return true
+}
+
+// expire closes and forgets the sessions whose latest request came before cutoff,
+// returning how many were removed
+func (st *sessionStore) expire(cutoff time.Time) int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	removed := 0
+	for id, sess := range st.sessions {
+		if sess.idleSince(cutoff) {
+			sess.close()
+			delete(st.sessions, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// all returns a snapshot of the current sessions
+func (st *sessionStore) all() []*session {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	sessions := make([]*session, 0, len(st.sessions))
+	for _, sess := range st.sessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
+
+// sweepSessions periodically removes Streamable HTTP sessions idle for longer than ttl
+// until the server shuts down
+func (s *Server) sweepSessions(ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopSweep:
+			return
+		case now := <-ticker.C:
+			s.sessions.expire(now.Add(-ttl))
+		}
+	}
+}
+
+// startSessionSweep starts sweeping idle sessions once, when a session TTL is configured
+func (s *Server) startSessionSweep() {
+	if s.cfg.HTTP.SessionTTL <= 0 {
+		return
+	}
+	s.sweepOnce.Do(func() {
+		go s.sweepSessions(time.Duration(s.cfg.HTTP.SessionTTL) * time.Second)
+	})
+}
+
+// encodeNotification marshals a JSON-RPC notification
+func encodeNotification(method string, params interface{}) ([]byte, error) {
+	return json.Marshal(RPCNotification{JSONRPC: JSONRPCVersion, Method: method, Params: params})
+}
@@ -0,0 +1,100 @@
+// Package mcp provides MCP (Model Context Protocol) types and server implementation
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ServeStdio serves MCP over the stdio transport: newline-delimited JSON-RPC messages
+// are read from in and responses and notifications are written to out, one per line.
+// Nothing else may be written to out while serving. It returns when in reaches EOF or
+// ctx is cancelled.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	sess := newSession()
+	stream, _ := sess.openStream()
+	s.sessions.add(sess)
+	defer s.sessions.remove(sess.id)
+
+	var writeMu sync.Mutex
+	write := func(v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Server-initiated notifications share the output with responses
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-stream:
+				_ = write(json.RawMessage(msg))
+			}
+		}
+	}()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			return nil
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			requests, batch, parseErr := parseMessages(line)
+			if parseErr != nil {
+				if err := write(parseErr); err != nil {
+					return fmt.Errorf("failed to write response: %w", err)
+				}
+				continue
+			}
+
+			responses := s.handleMessages(ctx, sess, requests)
+			if responses == nil {
+				continue
+			}
+			var payload interface{} = responses[0]
+			if batch {
+				payload = responses
+			}
+			if err := write(payload); err != nil {
+				return fmt.Errorf("failed to write response: %w", err)
+			}
+		}
+	}
+}
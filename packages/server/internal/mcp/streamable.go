@@ -0,0 +1,201 @@
+// Package mcp provides MCP (Model Context Protocol) types and server implementation
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// Streamable HTTP transport constants
+const (
+	// StreamableEndpoint is the single MCP endpoint for the Streamable HTTP transport
+	StreamableEndpoint = "/mcp"
+	// SessionHeader carries the session id assigned at initialization
+	SessionHeader = "Mcp-Session-Id"
+	// ProtocolVersionHeader carries the negotiated protocol version on later requests
+	ProtocolVersionHeader = "MCP-Protocol-Version"
+
+	// maxMessageBytes limits the size of a POSTed JSON-RPC body
+	maxMessageBytes = 10 << 20
+	// streamKeepAlive is how often an idle SSE stream sends a comment to keep proxies from closing it
+	streamKeepAlive = 25 * time.Second
+)
+
+// handleStreamablePost handles JSON-RPC messages POSTed to the MCP endpoint.
+// An initialize request starts a new session; every other message must carry its session id.
+func (s *Server) handleStreamablePost(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxMessageBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newRPCError(nil, ErrCodeParse, "failed to read body"))
+		return
+	}
+
+	requests, batch, parseErr := parseMessages(body)
+	if parseErr != nil {
+		c.JSON(http.StatusBadRequest, parseErr)
+		return
+	}
+
+	var sess *session
+	if containsInitialize(requests) {
+		if len(requests) > 1 {
+			c.JSON(http.StatusBadRequest, newRPCError(nil, ErrCodeInvalidRequest, "initialize must not be sent in a batch"))
+			return
+		}
+		sess = newSession()
+		sess.userID = requestUser(c)
+		sess.touch()
+		s.sessions.add(sess)
+		c.Header(SessionHeader, sess.id)
+	} else {
+		var ok bool
+		if sess, ok = s.requireSession(c); !ok {
+			return
+		}
+	}
+
+	responses := s.handleMessages(c.Request.Context(), sess, requests)
+	if responses == nil {
+		// Only notifications or responses were sent
+		c.Status(http.StatusAccepted)
+		return
+	}
+
+	var payload interface{} = responses[0]
+	if batch {
+		payload = responses
+	}
+
+	accept := c.GetHeader("Accept")
+	if strings.Contains(accept, "text/event-stream") && !strings.Contains(accept, "application/json") {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, newRPCError(nil, ErrCodeInternal, "failed to encode response"))
+			return
+		}
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Status(http.StatusOK)
+		writeSSEMessage(c.Writer, data)
+		return
+	}
+	c.JSON(http.StatusOK, payload)
+}
+
+// handleStreamableGet opens an SSE stream for server-initiated notifications
+func (s *Server) handleStreamableGet(c *gin.Context) {
+	if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		c.JSON(http.StatusMethodNotAllowed, newRPCError(nil, ErrCodeInvalidRequest, "GET requires Accept: text/event-stream"))
+		return
+	}
+
+	sess, ok := s.requireSession(c)
+	if !ok {
+		return
+	}
+
+	stream, ok := sess.openStream()
+	if !ok {
+		c.JSON(http.StatusConflict, newRPCError(nil, ErrCodeInvalidRequest, "a stream is already open for this session"))
+		return
+	}
+	defer sess.closeStream(stream)
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sess.done():
+			// The session was deleted or expired
+			return
+		case msg := <-stream:
+			writeSSEMessage(c.Writer, msg)
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// handleStreamableDelete terminates a session
+func (s *Server) handleStreamableDelete(c *gin.Context) {
+	sess, ok := s.requireSession(c)
+	if !ok {
+		return
+	}
+	if !s.sessions.remove(sess.id) {
+		c.JSON(http.StatusNotFound, newRPCError(nil, ErrCodeInvalidRequest, "session not found"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// requireSession resolves the request's session, checks that it belongs to the caller and checks
+// its protocol version header, writing the error response and returning false when any is invalid
+func (s *Server) requireSession(c *gin.Context) (*session, bool) {
+	id := c.GetHeader(SessionHeader)
+	if id == "" {
+		c.JSON(http.StatusBadRequest, newRPCError(nil, ErrCodeInvalidRequest, "missing "+SessionHeader+" header"))
+		return nil, false
+	}
+	sess, ok := s.sessions.get(id)
+	if !ok {
+		// Clients must start a new session with initialize
+		c.JSON(http.StatusNotFound, newRPCError(nil, ErrCodeInvalidRequest, "session not found"))
+		return nil, false
+	}
+	if sess.userID != requestUser(c) {
+		c.JSON(http.StatusForbidden, newRPCError(nil, ErrCodeInvalidRequest, "session belongs to another user"))
+		return nil, false
+	}
+	if version := c.GetHeader(ProtocolVersionHeader); version != "" && !IsSupportedProtocolVersion(version) {
+		c.JSON(http.StatusBadRequest, newRPCError(nil, ErrCodeInvalidRequest, "unsupported protocol version: "+version))
+		return nil, false
+	}
+	sess.touch()
+	return sess, true
+}
+
+// requestUser returns the id of the authenticated caller, or empty without authentication
+func requestUser(c *gin.Context) string {
+	if principal, ok := auth.FromContext(c.Request.Context()); ok {
+		return principal.UserID
+	}
+	return ""
+}
+
+// containsInitialize reports whether any message is an initialize request
+func containsInitialize(requests []*RPCRequest) bool {
+	for _, req := range requests {
+		if req.Method == MethodInitialize {
+			return true
+		}
+	}
+	return false
+}
+
+// writeSSEMessage writes one JSON-RPC message as an SSE event and flushes it
+func writeSSEMessage(w gin.ResponseWriter, data []byte) {
+	fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	w.Flush()
+}
@@ -20,12 +20,34 @@ type InputSchema struct {
 	Required   []string               `json:"required,omitempty"`
 }
 
-// Property represents a property in the input schema
+// Property represents a property in the input schema.
+// Objects nest through Properties/Required, arrays describe their elements with Items,
+// and OneOf lists alternative schemas of which a value must match exactly one.
 type Property struct {
-	Type        string `json:"type"`
-	Description string `json:"description,omitempty"`
-	Enum        []string `json:"enum,omitempty"`
+	Type        string      `json:"type,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Enum        []string    `json:"enum,omitempty"`
 	Default     interface{} `json:"default,omitempty"`
+
+	// Object
+	Properties map[string]Property `json:"properties,omitempty"`
+	Required   []string            `json:"required,omitempty"`
+
+	// Array
+	Items    *Property `json:"items,omitempty"`
+	MinItems *int      `json:"minItems,omitempty"`
+	MaxItems *int      `json:"maxItems,omitempty"`
+
+	// Number and integer
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	// String
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	OneOf []Property `json:"oneOf,omitempty"`
 }
 
 // ToolRequest represents an incoming tool call request
@@ -130,7 +152,9 @@ type InitializeResponse struct {
 
 // ServerCapabilities describes server capabilities
 type ServerCapabilities struct {
-	Tools *ToolsCapability `json:"tools,omitempty"`
+	Tools     *ToolsCapability     `json:"tools,omitempty"`
+	Resources *ResourcesCapability `json:"resources,omitempty"`
+	Prompts   *PromptsCapability   `json:"prompts,omitempty"`
 }
 
 // ToolsCapability describes tool capabilities
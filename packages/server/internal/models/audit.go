@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// AuditChange 一次操作对单条记录的修改
+// Before 为空表示操作创建了记录，After 为空表示操作删除了记录
+type AuditChange struct {
+	Entity   string          `json:"entity"`           // 记录类型（character, combat, map, game_state）
+	EntityID string          `json:"entity_id"`        // 记录ID（游戏状态为战役ID）
+	Before   json.RawMessage `json:"before,omitempty"` // 操作前的记录
+	After    json.RawMessage `json:"after,omitempty"`  // 操作后的记录
+}
+
+// FieldDiff 记录中一个字段的变化
+type FieldDiff struct {
+	Path   string      `json:"path"`             // 字段路径（如 hp.current）
+	Before interface{} `json:"before,omitempty"` // 修改前的值
+	After  interface{} `json:"after,omitempty"`  // 修改后的值
+}
+
+// AuditEntry 修改游戏状态的工具调用记录
+type AuditEntry struct {
+	ID         int64                  `json:"id"`                   // 自增ID，按操作顺序递增
+	CampaignID string                 `json:"campaign_id"`          // 所属战役ID
+	ActorID    string                 `json:"actor_id,omitempty"`   // 调用者ID（未认证时为空）
+	ActorRole  string                 `json:"actor_role,omitempty"` // 调用者角色
+	Tool       string                 `json:"tool"`                 // 工具名称
+	Arguments  map[string]interface{} `json:"arguments"`            // 工具参数
+	Changes    []AuditChange          `json:"changes"`              // 修改的记录
+	CreatedAt  time.Time              `json:"created_at"`
+	UndoneAt   *time.Time             `json:"undone_at,omitempty"` // 撤销时间
+}
+
+// IsUndone 检查操作是否已撤销
+func (e *AuditEntry) IsUndone() bool {
+	return e.UndoneAt != nil
+}
+
+// Created 检查操作是否创建了记录
+func (c *AuditChange) Created() bool {
+	return isNullJSON(c.Before)
+}
+
+// Deleted 检查操作是否删除了记录
+func (c *AuditChange) Deleted() bool {
+	return isNullJSON(c.After)
+}
+
+// Diff 返回修改前后不同的字段，按路径排序
+// 数组整体比较；updated_at 每次写入都会变化，不计入差异
+func (c *AuditChange) Diff() []FieldDiff {
+	return DiffJSON(c.Before, c.After)
+}
+
+// DiffJSON 比较两个 JSON 对象，返回不同的字段
+func DiffJSON(before, after json.RawMessage) []FieldDiff {
+	var b, a interface{}
+	if !isNullJSON(before) {
+		if err := json.Unmarshal(before, &b); err != nil {
+			return nil
+		}
+	}
+	if !isNullJSON(after) {
+		if err := json.Unmarshal(after, &a); err != nil {
+			return nil
+		}
+	}
+
+	diffs := make([]FieldDiff, 0)
+	diffValue("", b, a, &diffs)
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+// SameJSON 检查两个 JSON 对象除 updated_at 外是否相同
+func SameJSON(x, y json.RawMessage) bool {
+	if isNullJSON(x) || isNullJSON(y) {
+		return isNullJSON(x) == isNullJSON(y)
+	}
+	return len(DiffJSON(x, y)) == 0
+}
+
+// diffValue 递归比较两个值，对象逐字段比较
+func diffValue(path string, before, after interface{}, diffs *[]FieldDiff) {
+	bm, bok := before.(map[string]interface{})
+	am, aok := after.(map[string]interface{})
+	if !bok || !aok {
+		if !reflect.DeepEqual(before, after) {
+			*diffs = append(*diffs, FieldDiff{Path: path, Before: before, After: after})
+		}
+		return
+	}
+
+	keys := make(map[string]bool, len(bm)+len(am))
+	for k := range bm {
+		keys[k] = true
+	}
+	for k := range am {
+		keys[k] = true
+	}
+	for k := range keys {
+		if path == "" && k == "updated_at" {
+			continue
+		}
+		child := k
+		if path != "" {
+			child = path + "." + k
+		}
+		diffValue(child, bm[k], am[k], diffs)
+	}
+}
+
+// isNullJSON 检查 JSON 是否为空或 null
+func isNullJSON(raw json.RawMessage) bool {
+	s := string(raw)
+	return s == "" || s == "null"
+}
@@ -2,6 +2,7 @@
 package models
 
 import (
+	"text/template"
 	"time"
 )
 
@@ -26,6 +27,7 @@ type CampaignSettings struct {
 	Ruleset       string                 `json:"ruleset"`         // 规则集，默认 "dnd5e"
 	HouseRules    map[string]interface{} `json:"house_rules"`     // 房规
 	ContextWindow int                    `json:"context_window"`  // 上下文窗口大小，默认20
+	Prompts       map[string]string      `json:"prompts,omitempty"` // 提示词模板覆盖（按提示词名称，text/template 语法）
 }
 
 // NewCampaignSettings 创建默认战役设置
@@ -50,6 +52,11 @@ func (s *CampaignSettings) Validate() error {
 	if s.ContextWindow < 1 {
 		return NewValidationError("context_window", "must be at least 1")
 	}
+	for name, text := range s.Prompts {
+		if _, err := template.New(name).Parse(text); err != nil {
+			return NewValidationError("prompts."+name, "invalid template: "+err.Error())
+		}
+	}
 	return nil
 }
 
@@ -247,6 +247,10 @@ type Condition struct {
 	Type     string `json:"type"`     // 状态类型（poisoned, paralyzed, etc.）
 	Duration int    `json:"duration"` // 持续回合数，-1表示永久
 	Source   string `json:"source"`   // 来源
+
+	// 由专注法术维持的状态，施法者失去专注时移除
+	CasterID string `json:"caster_id,omitempty"` // 维持该状态的施法者ID
+	SpellID  string `json:"spell_id,omitempty"`  // 维持该状态的法术ID
 }
 
 // Validate 验证状态效果
@@ -349,6 +353,12 @@ type Character struct {
 	// 专长/特性
 	Features []*Feature `json:"features,omitempty"` // 专长/特性列表
 
+	// 天生武器攻击（爪、咬等，主要用于怪物）
+	NaturalAttacks []*EquipmentItem `json:"natural_attacks,omitempty"` // 天生攻击列表
+
+	// 怪物多重攻击定义
+	Multiattack *Multiattack `json:"multiattack,omitempty"` // 多重攻击
+
 	// 传记
 	Biography *Biography `json:"biography,omitempty"` // 传记
 
@@ -1065,6 +1075,26 @@ func (t *Traits) HasImmunity(damageType string) bool {
 	return false
 }
 
+// AddVulnerability 添加伤害易伤
+func (t *Traits) AddVulnerability(damageType string) {
+	for _, r := range t.DamageVulnerabilities {
+		if r == damageType {
+			return
+		}
+	}
+	t.DamageVulnerabilities = append(t.DamageVulnerabilities, damageType)
+}
+
+// HasVulnerability 检查是否有伤害易伤
+func (t *Traits) HasVulnerability(damageType string) bool {
+	for _, r := range t.DamageVulnerabilities {
+		if r == damageType {
+			return true
+		}
+	}
+	return false
+}
+
 // AddConditionImmunity 添加状态免疫
 func (t *Traits) AddConditionImmunity(condition string) {
 	for _, r := range t.ConditionImmunities {
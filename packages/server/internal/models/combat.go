@@ -175,6 +175,46 @@ type Participant struct {
 	Position    *Position    `json:"position"`      // 战斗地图位置
 	TempHP      int          `json:"temp_hp"`       // 临时HP（战斗中）
 	Conditions  []Condition  `json:"conditions"`    // 战斗中的临时状态
+	Actions     *ActionBudget `json:"actions,omitempty"` // 本回合行动资源
+	Hidden      bool         `json:"hidden,omitempty"`       // 是否处于躲藏状态
+	StealthRoll int          `json:"stealth_roll,omitempty"` // 躲藏时的隐匿检定结果
+	OpportunityTargets []string `json:"opportunity_targets,omitempty"` // 可以对其进行借机攻击的目标（等待玩家决定，回合结束时失效）
+}
+
+// OfferOpportunityAttack 记录一次可进行的借机攻击
+// 规则参考: PHB 第9章 - Opportunity Attacks
+func (p *Participant) OfferOpportunityAttack(targetID string) {
+	for _, id := range p.OpportunityTargets {
+		if id == targetID {
+			return
+		}
+	}
+	p.OpportunityTargets = append(p.OpportunityTargets, targetID)
+}
+
+// TakeOpportunityAttack 消耗对目标的借机攻击机会，没有该机会时返回 false
+func (p *Participant) TakeOpportunityAttack(targetID string) bool {
+	for i, id := range p.OpportunityTargets {
+		if id == targetID {
+			p.OpportunityTargets = append(p.OpportunityTargets[:i], p.OpportunityTargets[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureActions 获取行动资源，未初始化时按给定速度创建
+func (p *Participant) EnsureActions(speed int) *ActionBudget {
+	if p.Actions == nil {
+		p.Actions = NewActionBudget(speed)
+	}
+	return p.Actions
+}
+
+// StartTurn 在参战者回合开始时重置行动资源
+// 规则参考: PHB 第9章 - Your Turn / Reactions
+func (p *Participant) StartTurn(speed int) {
+	p.EnsureActions(speed).Reset(speed)
 }
 
 // Validate 验证参战者数据
@@ -223,6 +263,46 @@ func (p *Participant) AddCondition(conditionType string, duration int, source st
 	})
 }
 
+// AddConcentrationCondition 添加由施法者专注维持的状态
+// 持续到施法者失去专注（或状态被移除）为止
+// 规则参考: PHB 第10章 - Concentration
+func (p *Participant) AddConcentrationCondition(conditionType, source, casterID, spellID string) {
+	for i, cond := range p.Conditions {
+		if cond.Type == conditionType && cond.CasterID == casterID && cond.SpellID == spellID {
+			p.Conditions[i].Duration = -1
+			return
+		}
+	}
+
+	p.Conditions = append(p.Conditions, Condition{
+		Type:     conditionType,
+		Duration: -1,
+		Source:   source,
+		CasterID: casterID,
+		SpellID:  spellID,
+	})
+}
+
+// RemoveConcentrationConditions 移除由指定施法者专注维持的状态
+// spellID 为空时移除该施法者的所有专注状态，返回被移除的状态类型
+func (p *Participant) RemoveConcentrationConditions(casterID, spellID string) []string {
+	removed := make([]string, 0)
+	kept := make([]Condition, 0, len(p.Conditions))
+
+	for _, cond := range p.Conditions {
+		if cond.CasterID == casterID && (spellID == "" || cond.SpellID == spellID) {
+			removed = append(removed, cond.Type)
+			continue
+		}
+		kept = append(kept, cond)
+	}
+
+	if len(removed) > 0 {
+		p.Conditions = kept
+	}
+	return removed
+}
+
 // RemoveCondition 移除战斗中的临时状态
 func (p *Participant) RemoveCondition(conditionType string) bool {
 	for i, cond := range p.Conditions {
@@ -264,6 +344,121 @@ func (p *Participant) TickConditions() []string {
 	return expired
 }
 
+// ActionType 行动类型
+// 规则参考: PHB 第9章 - Your Turn
+type ActionType string
+
+const (
+	// ActionTypeAction 动作
+	ActionTypeAction ActionType = "action"
+	// ActionTypeBonusAction 附赠动作
+	ActionTypeBonusAction ActionType = "bonus_action"
+	// ActionTypeReaction 反应
+	ActionTypeReaction ActionType = "reaction"
+	// ActionTypeObjectInteraction 自由物件互动
+	ActionTypeObjectInteraction ActionType = "object_interaction"
+)
+
+// ActionBudget 参战者在一个回合内可用的行动资源
+// 每个参战者回合开始时重置
+// 规则参考: PHB 第9章 - Your Turn / Bonus Actions / Reactions / Movement
+type ActionBudget struct {
+	Action            bool `json:"action"`             // 动作是否可用
+	BonusAction       bool `json:"bonus_action"`       // 附赠动作是否可用
+	Reaction          bool `json:"reaction"`           // 反应是否可用
+	ObjectInteraction bool `json:"object_interaction"` // 自由物件互动是否可用
+	Speed             int  `json:"speed"`              // 本回合移动速度（英尺）
+	Movement          int  `json:"movement"`           // 剩余移动距离（英尺）
+
+	// 攻击动作
+	AttackActionTaken bool `json:"attack_action_taken,omitempty"` // 本回合已采取攻击动作
+	AttacksRemaining  int  `json:"attacks_remaining,omitempty"`   // 攻击动作中剩余的攻击次数（额外攻击）
+
+	// 本回合采取的特殊动作
+	Dashed        bool           `json:"dashed,omitempty"`         // 已疾走
+	Dodging       bool           `json:"dodging,omitempty"`        // 闪避中（持续到下回合开始）
+	Disengaged    bool           `json:"disengaged,omitempty"`     // 已撤离（本回合移动不引发借机攻击）
+	HelpingID     string         `json:"helping_id,omitempty"`     // 协助的盟友ID
+	HelpAgainstID string         `json:"help_against_id,omitempty"` // 协助攻击的目标ID（可选）
+	Readied       *ReadiedAction `json:"readied,omitempty"`        // 预备动作
+}
+
+// ReadiedAction 预备动作
+// 规则参考: PHB 第9章 - Ready
+type ReadiedAction struct {
+	Trigger string `json:"trigger"` // 触发条件
+	Action  string `json:"action"`  // 触发时执行的动作
+}
+
+// NewActionBudget 创建满额的行动资源
+func NewActionBudget(speed int) *ActionBudget {
+	b := &ActionBudget{}
+	b.Reset(speed)
+	return b
+}
+
+// Reset 重置所有行动资源和回合内效果
+func (b *ActionBudget) Reset(speed int) {
+	*b = ActionBudget{
+		Action:            true,
+		BonusAction:       true,
+		Reaction:          true,
+		ObjectInteraction: true,
+		Speed:             speed,
+		Movement:          speed,
+	}
+}
+
+// Has 检查指定类型的行动是否可用
+func (b *ActionBudget) Has(actionType ActionType) bool {
+	switch actionType {
+	case ActionTypeAction:
+		return b.Action
+	case ActionTypeBonusAction:
+		return b.BonusAction
+	case ActionTypeReaction:
+		return b.Reaction
+	case ActionTypeObjectInteraction:
+		return b.ObjectInteraction
+	default:
+		return false
+	}
+}
+
+// Use 消耗指定类型的行动，不可用时返回 false
+func (b *ActionBudget) Use(actionType ActionType) bool {
+	if !b.Has(actionType) {
+		return false
+	}
+	switch actionType {
+	case ActionTypeAction:
+		b.Action = false
+	case ActionTypeBonusAction:
+		b.BonusAction = false
+	case ActionTypeReaction:
+		b.Reaction = false
+	case ActionTypeObjectInteraction:
+		b.ObjectInteraction = false
+	}
+	return true
+}
+
+// UseMovement 消耗移动距离，剩余不足时返回 false
+func (b *ActionBudget) UseMovement(feet int) bool {
+	if feet < 0 || feet > b.Movement {
+		return false
+	}
+	b.Movement -= feet
+	return true
+}
+
+// Dash 疾走：本回合获得等同于速度的额外移动
+// 规则参考: PHB 第9章 - Dash
+func (b *ActionBudget) Dash() {
+	b.Movement += b.Speed
+	b.Dashed = true
+}
+
 // CombatLogEntry 战斗日志条目
 type CombatLogEntry struct {
 	Round     int       `json:"round"`       // 回合数
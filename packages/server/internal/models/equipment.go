@@ -194,6 +194,7 @@ type EquipmentItem struct {
 	DamageType  string         `json:"damage_type,omitempty"` // 伤害类型
 	Range       string         `json:"range,omitempty"`       // 射程
 	Properties  []string       `json:"properties,omitempty"`  // 武器属性（如 versatile, finesse）
+	ExtraDamage []DamagePart   `json:"extra_damage,omitempty"` // 附加伤害（如火焰剑的 1d6 火焰）
 
 	// 护甲属性
 	AC          int            `json:"ac,omitempty"`          // 护甲等级
@@ -210,6 +211,27 @@ type EquipmentItem struct {
 	RawData     map[string]interface{} `json:"raw_data,omitempty"`
 }
 
+// Multiattack 怪物多重攻击
+// 一个攻击动作中依次进行的攻击列表
+// 规则参考: MM 第1章 - Multiattack
+type Multiattack struct {
+	Description string             `json:"description,omitempty"` // 描述（如 "一次喙击和一次爪击"）
+	Attacks     []MultiattackEntry `json:"attacks"`               // 攻击列表
+}
+
+// MultiattackEntry 多重攻击中的一项
+type MultiattackEntry struct {
+	AttackID string `json:"attack_id"` // 武器或天生攻击的ID/名称
+	Count    int    `json:"count"`     // 攻击次数
+}
+
+// DamagePart 附加伤害部分
+// 规则参考: PHB 第9章 - Damage Types
+type DamagePart struct {
+	Damage     string `json:"damage"`      // 伤害骰（如 "1d6"）
+	DamageType string `json:"damage_type"` // 伤害类型
+}
+
 // EquipmentType 装备类型
 type EquipmentType string
 
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// EventType 游戏事件类型
+type EventType string
+
+const (
+	// EventHPChanged 生命值变化（伤害、治疗）
+	EventHPChanged EventType = "hp_changed"
+	// EventConditionApplied 施加状态
+	EventConditionApplied EventType = "condition_applied"
+	// EventConditionRemoved 移除状态
+	EventConditionRemoved EventType = "condition_removed"
+	// EventTurnAdvanced 战斗回合推进
+	EventTurnAdvanced EventType = "turn_advanced"
+	// EventTokenMoved 战斗地图上的 Token 移动
+	EventTokenMoved EventType = "token_moved"
+	// EventDiceRolled 掷骰
+	EventDiceRolled EventType = "dice_rolled"
+	// EventMapEntered 进入地图（战斗地图或返回大地图）
+	EventMapEntered EventType = "map_entered"
+)
+
+// GameEvent 游戏领域事件
+// Cursor 由存储在追加时分配，单调递增，客户端据此续传
+type GameEvent struct {
+	Cursor     int64                  `json:"cursor"`
+	CampaignID string                 `json:"campaign_id"`
+	Type       EventType              `json:"type"`
+	Data       map[string]interface{} `json:"data"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// NewGameEvent 创建游戏事件（尚未分配游标）
+func NewGameEvent(campaignID string, eventType EventType, data map[string]interface{}) *GameEvent {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return &GameEvent{
+		CampaignID: campaignID,
+		Type:       eventType,
+		Data:       data,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// IsValidEventType 检查事件类型是否有效
+func IsValidEventType(eventType string) bool {
+	switch EventType(eventType) {
+	case EventHPChanged, EventConditionApplied, EventConditionRemoved,
+		EventTurnAdvanced, EventTokenMoved, EventDiceRolled, EventMapEntered:
+		return true
+	default:
+		return false
+	}
+}
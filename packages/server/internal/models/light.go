@@ -0,0 +1,61 @@
+package models
+
+// LightLevel 光照等级
+// 规则参考: PHB 第8章 - Vision and Light
+type LightLevel string
+
+const (
+	// LightBright 明亮光照
+	LightBright LightLevel = "bright"
+	// LightDim 微光（轻度遮蔽）
+	LightDim LightLevel = "dim"
+	// LightDarkness 黑暗（重度遮蔽）
+	LightDarkness LightLevel = "darkness"
+)
+
+// lightRank 光照等级排序，用于取较亮者
+var lightRank = map[LightLevel]int{
+	LightDarkness: 0,
+	LightDim:      1,
+	LightBright:   2,
+}
+
+// Brighter 返回两个光照等级中较亮的一个
+func (l LightLevel) Brighter(other LightLevel) LightLevel {
+	if lightRank[other] > lightRank[l] {
+		return other
+	}
+	return l
+}
+
+// LightSource 地图上的光源（如火把、灯笼）
+// 规则参考: PHB 第5章 - Adventuring Gear (Torch, Lantern)
+type LightSource struct {
+	ID       string   `json:"id"`
+	Position Position `json:"position"` // 光源所在格子
+	Bright   int      `json:"bright"`   // 明亮光照半径（英尺）
+	Dim      int      `json:"dim"`      // 光照总半径（英尺），超出明亮半径的部分为微光
+}
+
+// Validate 验证光源
+func (l *LightSource) Validate() error {
+	if err := l.Position.Validate(); err != nil {
+		return err
+	}
+	if l.Bright < 0 || l.Dim < 0 {
+		return NewValidationError("light.radius", "cannot be negative")
+	}
+	return nil
+}
+
+// LevelAt 返回距离光源指定英尺处的光照等级
+func (l *LightSource) LevelAt(distance int) LightLevel {
+	switch {
+	case distance <= l.Bright:
+		return LightBright
+	case distance <= l.Dim:
+		return LightDim
+	default:
+		return LightDarkness
+	}
+}
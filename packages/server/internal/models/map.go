@@ -330,6 +330,8 @@ type Map struct {
 	Walls           Walls            `json:"walls,omitempty"`             // 墙壁列表
 	ImportMeta      *MapImportMeta   `json:"import_meta,omitempty"`       // 导入元数据
 	VisualLocations []VisualLocation `json:"visual_locations,omitempty"`  // 视觉识别的地点（Image 模式）
+	Lights          []LightSource    `json:"lights,omitempty"`            // 光源列表
+	AmbientLight    LightLevel       `json:"ambient_light,omitempty"`     // 环境光照（为空时视为明亮）
 }
 
 // NewMap 创建新地图
@@ -413,6 +415,24 @@ func (m *Map) IsBattleMap() bool {
 	return m.Type == MapTypeBattle
 }
 
+// GetAmbientLight 获取地图环境光照，未设置时为明亮光照
+func (m *Map) GetAmbientLight() LightLevel {
+	if m.AmbientLight == "" {
+		return LightBright
+	}
+	return m.AmbientLight
+}
+
+// AddLight 添加光源
+func (m *Map) AddLight(light LightSource) error {
+	if err := light.Validate(); err != nil {
+		return err
+	}
+	m.Lights = append(m.Lights, light)
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
 // AddLocation 添加地点
 func (m *Map) AddLocation(location Location) error {
 	if err := location.Validate(); err != nil {
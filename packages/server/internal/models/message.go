@@ -16,16 +16,19 @@ const (
 	MessageRoleAssistant MessageRole = "assistant"
 	// MessageRoleSystem 系统消息
 	MessageRoleSystem MessageRole = "system"
+	// MessageRoleTool 工具执行结果消息
+	MessageRoleTool MessageRole = "tool"
 )
 
 // Message 对话消息
 type Message struct {
-	ID         string      `json:"id"`          // UUID
-	CampaignID string      `json:"campaign_id"` // 所属战役ID
-	Role       MessageRole `json:"role"`        // 角色（user, assistant, system）
-	Content    string      `json:"content"`     // 消息内容
-	PlayerID   string      `json:"player_id"`   // 玩家ID（user消息）
-	ToolCalls  []ToolCall  `json:"tool_calls"`  // 工具调用（assistant消息）
+	ID         string      `json:"id"`                     // UUID
+	CampaignID string      `json:"campaign_id"`            // 所属战役ID
+	Role       MessageRole `json:"role"`                   // 角色（user, assistant, system, tool）
+	Content    string      `json:"content"`                // 消息内容
+	PlayerID   string      `json:"player_id"`              // 玩家ID（user消息）
+	ToolCalls  []ToolCall  `json:"tool_calls"`             // 工具调用（assistant消息）
+	ToolCallID string      `json:"tool_call_id,omitempty"` // 对应的工具调用ID（tool消息）
 	CreatedAt  time.Time   `json:"created_at"`
 }
 
@@ -69,6 +72,13 @@ func NewAssistantMessage(campaignID, content string, toolCalls []ToolCall) *Mess
 	return msg
 }
 
+// NewToolMessage 创建工具结果消息
+func NewToolMessage(campaignID, toolCallID, content string) *Message {
+	msg := NewMessage(campaignID, MessageRoleTool, content)
+	msg.ToolCallID = toolCallID
+	return msg
+}
+
 // Validate 验证消息
 func (m *Message) Validate() error {
 	if m.CampaignID == "" {
@@ -77,14 +87,14 @@ func (m *Message) Validate() error {
 
 	// 验证角色
 	switch m.Role {
-	case MessageRoleUser, MessageRoleAssistant, MessageRoleSystem:
+	case MessageRoleUser, MessageRoleAssistant, MessageRoleSystem, MessageRoleTool:
 		// 有效角色
 	default:
-		return NewValidationError("role", "must be one of: user, assistant, system")
+		return NewValidationError("role", "must be one of: user, assistant, system, tool")
 	}
 
-	// 验证内容长度（1-100000字符）
-	if len(m.Content) < 1 {
+	// 验证内容长度（1-100000字符，只调用工具的 assistant 消息可以没有内容）
+	if len(m.Content) < 1 && !m.HasToolCalls() {
 		return NewValidationError("content", "cannot be empty")
 	}
 	if len(m.Content) > 100000 {
@@ -96,6 +106,14 @@ func (m *Message) Validate() error {
 		return NewValidationError("player_id", "is required for user messages")
 	}
 
+	// tool 消息必须关联到 assistant 消息中的工具调用
+	if m.Role == MessageRoleTool && m.ToolCallID == "" {
+		return NewValidationError("tool_call_id", "is required for tool messages")
+	}
+	if m.ToolCallID != "" && m.Role != MessageRoleTool {
+		return NewValidationError("tool_call_id", "only allowed for tool messages")
+	}
+
 	// 验证 tool_calls（仅 assistant 消息可以有 tool_calls）
 	if len(m.ToolCalls) > 0 && m.Role != MessageRoleAssistant {
 		return NewValidationError("tool_calls", "only allowed for assistant messages")
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snapshot 战役存档点
+// 保存某一时刻战役的游戏状态、角色、地图和进行中的战斗，用于回滚或分支出新战役
+type Snapshot struct {
+	ID            string     `json:"id"`                       // UUID
+	CampaignID    string     `json:"campaign_id"`              // 所属战役ID
+	Name          string     `json:"name"`                     // 存档名称
+	Description   string     `json:"description,omitempty"`    // 存档说明
+	CreatedBy     string     `json:"created_by,omitempty"`     // 创建者ID（未认证时为空）
+	MessageCount  int        `json:"message_count"`            // 存档时的消息数
+	MessageCursor *time.Time `json:"message_cursor,omitempty"` // 存档时最后一条消息的时间，之后的消息不属于存档
+	Data          []byte     `json:"-"`                        // 压缩后的记录（战役归档格式）
+	Size          int        `json:"size"`                     // 压缩后的字节数
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NewSnapshot 创建新存档点
+func NewSnapshot(campaignID, name string) *Snapshot {
+	return &Snapshot{
+		ID:         uuid.New().String(),
+		CampaignID: campaignID,
+		Name:       name,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Includes 检查消息是否在存档点之前
+func (s *Snapshot) Includes(message *Message) bool {
+	return s.MessageCursor != nil && !message.CreatedAt.After(*s.MessageCursor)
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // Spellbook 法术书
 // 规则参考: PHB 第10章 Spellcasting
 type Spellbook struct {
@@ -161,6 +163,55 @@ func (s *Spellbook) UnprepareSpell(spellID string, level int) bool {
 	return false
 }
 
+// FindSpell 按ID或名称（不区分大小写）查找法术
+func (s *Spellbook) FindSpell(ref string) *Spell {
+	if s.Spells == nil || ref == "" {
+		return nil
+	}
+	if spell, ok := s.Spells[ref]; ok {
+		return spell
+	}
+	for _, spell := range s.Spells {
+		if strings.EqualFold(spell.Name, ref) {
+			return spell
+		}
+	}
+	return nil
+}
+
+// IsSpellKnown 检查法术是否在法术书或已知法术列表中
+func (s *Spellbook) IsSpellKnown(spellID string) bool {
+	if s.Spells != nil {
+		if _, ok := s.Spells[spellID]; ok {
+			return true
+		}
+	}
+	for _, spells := range s.KnownSpells {
+		for _, id := range spells {
+			if id == spellID {
+				return true
+			}
+		}
+	}
+	return s.IsSpellPrepared(spellID)
+}
+
+// HasSpellList 检查法术书是否记录了任何法术
+func (s *Spellbook) HasSpellList() bool {
+	return len(s.Spells) > 0 || len(s.KnownSpells) > 0 || len(s.PreparedSpells) > 0
+}
+
+// RequiresPreparation 检查是否为准备施法者（法师、牧师等）
+// 规则参考: PHB 第10章 - Preparing and Casting Spells
+func (s *Spellbook) RequiresPreparation() bool {
+	for _, spells := range s.PreparedSpells {
+		if len(spells) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // IsSpellPrepared 检查法术是否已准备
 func (s *Spellbook) IsSpellPrepared(spellID string) bool {
 	if s.PreparedSpells == nil {
@@ -191,6 +242,23 @@ func (s *Spellbook) UseSlotAtLevel(level int) bool {
 	return slots.UseSlot()
 }
 
+// AvailableSlots 返回指定等级的剩余法术位
+func (s *Spellbook) AvailableSlots(level int) int {
+	if s.Slots == nil {
+		return 0
+	}
+	slots, exists := s.Slots[level]
+	if !exists {
+		return 0
+	}
+	return slots.Available()
+}
+
+// HasSlots 检查法术书是否记录了法术位
+func (s *Spellbook) HasSlots() bool {
+	return len(s.Slots) > 0
+}
+
 // RestoreAllSlots 恢复所有法术位
 func (s *Spellbook) RestoreAllSlots() {
 	for _, slots := range s.Slots {
@@ -246,8 +314,10 @@ type Spell struct {
 	// 伤害/效果
 	Damage       *SpellDamage     `json:"damage,omitempty"`       // 伤害
 	Save         *SpellSave       `json:"save,omitempty"`         // 豁免
+	AttackType   SpellAttackType  `json:"attack_type,omitempty"`  // 法术攻击类型（为空表示无需攻击检定）
 	Healing      *SpellHealing    `json:"healing,omitempty"`      // 治疗
 	AreaOfEffect *AreaOfEffect    `json:"area_of_effect,omitempty"` // 范围效果
+	Conditions   []string         `json:"conditions,omitempty"`   // 豁免失败或命中时施加的状态（如 Hold Person 的 paralyzed）
 
 	// 元数据
 	Classes      []string         `json:"classes,omitempty"`      // 可用职业
@@ -270,6 +340,15 @@ const (
 	SchoolTransmutation SpellSchool = "transmutation" // 变化
 )
 
+// SpellAttackType 法术攻击类型
+// 规则参考: PHB 第10章 - Attack Rolls
+type SpellAttackType string
+
+const (
+	SpellAttackMelee  SpellAttackType = "melee"  // 近战法术攻击
+	SpellAttackRanged SpellAttackType = "ranged" // 远程法术攻击
+)
+
 // SpellComponents 法术成分
 // 规则参考: PHB 第10章 Components
 type SpellComponents struct {
@@ -287,6 +366,7 @@ type SpellDamage struct {
 	BaseDamage string `json:"base_damage,omitempty"` // 基础伤害（如 "3d8"）
 	SpellMod   bool   `json:"spell_mod,omitempty"`   // 是否加施法属性修正
 	LevelScale []string `json:"level_scale,omitempty"` // 升阶伤害（每级增加）
+	ExtraDamage []DamagePart `json:"extra_damage,omitempty"` // 其他类型的附加伤害（如焰击的光耀伤害）
 }
 
 // SpellSave 法术豁免
@@ -326,6 +406,21 @@ func (s *Spell) IsCantrip() bool {
 	return s.Level == 0
 }
 
+// RequiresAttackRoll 是否需要法术攻击检定
+func (s *Spell) RequiresAttackRoll() bool {
+	return s.AttackType != ""
+}
+
+// RequiresSave 是否需要目标进行豁免
+func (s *Spell) RequiresSave() bool {
+	return s.Save != nil && s.Save.Ability != ""
+}
+
+// HasAreaOfEffect 是否为范围法术
+func (s *Spell) HasAreaOfEffect() bool {
+	return s.AreaOfEffect != nil && s.AreaOfEffect.Type != ""
+}
+
 // GetLevelName 获取法术等级名称
 func (s *Spell) GetLevelName() string {
 	if s.Level == 0 {
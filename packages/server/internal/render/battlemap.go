@@ -0,0 +1,217 @@
+// Package render draws game state as images
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// ErrNoGrid is returned for maps without a grid (image mode maps are already pictures)
+var ErrNoGrid = errors.New("map has no grid to render")
+
+const (
+	// maxCellPixels is the cell size of small maps
+	maxCellPixels = 32
+	// minCellPixels keeps large maps legible
+	minCellPixels = 4
+	// maxImagePixels caps the longer side of the rendered image
+	maxImagePixels = 2048
+)
+
+// Palette
+var (
+	gridLineColor  = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	wallColor      = color.RGBA{R: 40, G: 40, B: 40, A: 255}
+	doorColor      = color.RGBA{R: 139, G: 90, B: 43, A: 255}
+	openDoorColor  = color.RGBA{R: 205, G: 170, B: 125, A: 255}
+	windowColor    = color.RGBA{R: 100, G: 160, B: 230, A: 255}
+	terrainColor   = color.RGBA{R: 150, G: 150, B: 150, A: 255}
+	locationColor  = color.RGBA{R: 120, G: 30, B: 120, A: 255}
+	tokenEdgeColor = color.RGBA{A: 255}
+
+	cellColors = map[models.CellType]color.RGBA{
+		models.CellTypeEmpty:     {R: 245, G: 240, B: 225, A: 255},
+		models.CellTypeWall:      {R: 60, G: 60, B: 60, A: 255},
+		models.CellTypeDifficult: {R: 200, G: 180, B: 130, A: 255},
+		models.CellTypeWater:     {R: 120, G: 170, B: 220, A: 255},
+		models.CellTypeDoor:      {R: 139, G: 90, B: 43, A: 255},
+		models.CellTypeRoad:      {R: 210, G: 190, B: 150, A: 255},
+		models.CellTypeForest:    {R: 80, G: 140, B: 70, A: 255},
+		models.CellTypeMountain:  {R: 140, G: 120, B: 100, A: 255},
+		models.CellTypeBuilding:  {R: 170, G: 110, B: 90, A: 255},
+	}
+
+	dispositionColors = map[models.TokenDisposition]color.RGBA{
+		models.DispositionFriendly: {R: 40, G: 160, B: 60, A: 255},
+		models.DispositionNeutral:  {R: 220, G: 190, B: 40, A: 255},
+		models.DispositionHostile:  {R: 200, G: 40, B: 40, A: 255},
+		models.DispositionSecret:   {R: 130, G: 60, B: 180, A: 255},
+	}
+)
+
+// MapPNG renders a grid map as a PNG: terrain cells, grid lines, walls and doors,
+// world map locations and tokens colored by disposition.
+func MapPNG(m *models.Map) ([]byte, error) {
+	img, err := MapImage(m)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// MapImage renders a grid map as an image
+func MapImage(m *models.Map) (*image.RGBA, error) {
+	if m == nil || m.Grid == nil || m.Grid.Width <= 0 || m.Grid.Height <= 0 {
+		return nil, ErrNoGrid
+	}
+	grid := m.Grid
+	cell := cellPixels(grid.Width, grid.Height)
+	img := image.NewRGBA(image.Rect(0, 0, grid.Width*cell+1, grid.Height*cell+1))
+
+	// Terrain
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			cellType := models.CellTypeEmpty
+			if y < len(grid.Cells) && x < len(grid.Cells[y]) {
+				cellType = grid.Cells[y][x]
+			}
+			fill, ok := cellColors[cellType]
+			if !ok {
+				fill = cellColors[models.CellTypeEmpty]
+			}
+			draw.Draw(img, image.Rect(x*cell, y*cell, (x+1)*cell, (y+1)*cell), image.NewUniform(fill), image.Point{}, draw.Src)
+		}
+	}
+
+	// Grid lines, skipped when cells are too small to see them
+	if cell >= 8 {
+		for x := 0; x <= grid.Width; x++ {
+			drawLine(img, x*cell, 0, x*cell, grid.Height*cell, 1, gridLineColor)
+		}
+		for y := 0; y <= grid.Height; y++ {
+			drawLine(img, 0, y*cell, grid.Width*cell, y*cell, 1, gridLineColor)
+		}
+	}
+
+	// Walls run along grid lines between corners
+	thickness := max(cell/8, 2)
+	for _, wall := range m.Walls {
+		if wall == nil || len(wall.Bounds) != 4 {
+			continue
+		}
+		drawLine(img, wall.Bounds[0]*cell, wall.Bounds[1]*cell, wall.Bounds[2]*cell, wall.Bounds[3]*cell, thickness, wallStyle(wall))
+	}
+
+	// World map locations
+	for _, loc := range m.Locations {
+		cx, cy := loc.Position.X*cell+cell/2, loc.Position.Y*cell+cell/2
+		r := max(cell/4, 1)
+		draw.Draw(img, image.Rect(cx-r, cy-r, cx+r+1, cy+r+1), image.NewUniform(locationColor), image.Point{}, draw.Src)
+	}
+
+	// Tokens cover their whole space
+	for _, token := range m.Tokens {
+		span := max(models.GetTokenSizeInGrids(token.Size), 1)
+		size := span * cell
+		cx := token.Position.X*cell + size/2
+		cy := token.Position.Y*cell + size/2
+		radius := size/2 - max(cell/10, 1)
+		fill, ok := dispositionColors[token.Disposition]
+		if !ok {
+			fill = dispositionColors[models.DispositionNeutral]
+		}
+		if token.Size == models.TokenSizeTiny {
+			radius = cell / 4
+		}
+		fillCircle(img, cx, cy, radius, tokenEdgeColor)
+		fillCircle(img, cx, cy, radius-max(radius/6, 1), fill)
+	}
+
+	return img, nil
+}
+
+// cellPixels picks a cell size that keeps the image within maxImagePixels
+func cellPixels(width, height int) int {
+	cell := maxImagePixels / max(width, height)
+	return min(max(cell, minCellPixels), maxCellPixels)
+}
+
+// wallStyle returns the color used for a wall
+func wallStyle(wall *models.Wall) color.RGBA {
+	switch wall.Type {
+	case models.WallTypeDoor:
+		if wall.IsOpen() {
+			return openDoorColor
+		}
+		return doorColor
+	case models.WallTypeWindow:
+		return windowColor
+	case models.WallTypeTerrain:
+		return terrainColor
+	default:
+		return wallColor
+	}
+}
+
+// drawLine draws a straight line of the given thickness using Bresenham's algorithm
+func drawLine(img *image.RGBA, x0, y0, x1, y1, thickness int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	half := thickness / 2
+	err := dx + dy
+	for {
+		for ox := -half; ox < thickness-half; ox++ {
+			for oy := -half; oy < thickness-half; oy++ {
+				img.SetRGBA(x0+ox, y0+oy, c)
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// fillCircle fills a disc centered on (cx, cy)
+func fillCircle(img *image.RGBA, cx, cy, r int, c color.RGBA) {
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				img.SetRGBA(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+// abs returns the absolute value of an integer
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/dnd-mcp/server/internal/models"
 	"github.com/dnd-mcp/server/internal/rules"
 	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/rules/movement"
 )
 
 // AttackResult 攻击结果
@@ -17,10 +18,16 @@ type AttackResult struct {
 	Hit          bool               `json:"hit"`           // 是否命中
 	Crit         bool               `json:"crit"`          // 是否暴击
 	DamageRoll   *models.DiceResult `json:"damage_roll"`   // 伤害骰
-	Damage       int                `json:"damage"`        // 总伤害
+	Damage       int                `json:"damage"`        // 总伤害（已应用抗性/免疫/易伤）
 	DamageType   string             `json:"damage_type"`   // 伤害类型
+	RawDamage    int                `json:"raw_damage"`    // 调整前总伤害
+	ExtraDamageRolls []*models.DiceResult `json:"extra_damage_rolls,omitempty"` // 附加伤害骰
+	DamageBreakdown  []DamageTypeResult   `json:"damage_breakdown,omitempty"`   // 各类型伤害明细
 	TargetHP     *models.HP         `json:"target_hp"`     // 目标剩余HP
 	TargetDown   bool               `json:"target_down"`   // 目标是否倒地
+	Modifiers    *RollModifiers     `json:"modifiers,omitempty"` // 优势/劣势及自动暴击的来源
+	Cover        CoverLevel         `json:"cover,omitempty"`     // 目标的掩护等级
+	CoverBonus   int                `json:"cover_bonus,omitempty"` // 掩护提供的 AC 加值（已计入 TargetAC）
 }
 
 // SpellResult 法术结果
@@ -35,22 +42,67 @@ type SpellResult struct {
 	Damage     int                `json:"damage"`      // 总伤害/治疗量
 	IsHealing  bool               `json:"is_healing"`  // 是否为治疗
 	Results    []SpellTargetResult `json:"results"`    // 各目标结果
+
+	// 施法细节
+	SpellLevel     int                  `json:"spell_level"`               // 法术本身等级
+	Upcast         bool                 `json:"upcast,omitempty"`          // 是否升阶施放
+	SlotUsed       bool                 `json:"slot_used"`                 // 是否消耗了法术位
+	SlotsRemaining int                  `json:"slots_remaining"`           // 该等级剩余法术位
+	SaveDC         int                  `json:"save_dc,omitempty"`         // 法术豁免DC
+	SaveAbility    string               `json:"save_ability,omitempty"`    // 豁免属性
+	AttackBonus    int                  `json:"attack_bonus,omitempty"`    // 法术攻击加值
+	Formula        string               `json:"formula,omitempty"`         // 实际使用的伤害/治疗公式
+	AreaOfEffect   *models.AreaOfEffect `json:"area_of_effect,omitempty"`  // 范围效果
 }
 
 // SpellTargetResult 法术目标结果
 type SpellTargetResult struct {
 	TargetID  string             `json:"target_id"`  // 目标ID
 	Hit       bool               `json:"hit"`        // 是否命中（需要攻击检定的法术）
-	Damage    int                `json:"damage"`     // 伤害/治疗量
+	Damage    int                `json:"damage"`     // 伤害/治疗量（已应用抗性/免疫/易伤）
 	TargetHP  *models.HP         `json:"target_hp"`  // 目标剩余HP
+	RawDamage int                `json:"raw_damage"` // 调整前伤害（豁免减半后）
+
+	DamageBreakdown []DamageTypeResult `json:"damage_breakdown,omitempty"` // 各类型伤害明细
+
+	AttackRoll *models.DiceResult  `json:"attack_roll,omitempty"` // 法术攻击骰
+	AttackModifiers *RollModifiers `json:"attack_modifiers,omitempty"` // 法术攻击的优势/劣势来源
+	SaveModifiers   *RollModifiers `json:"save_modifiers,omitempty"`   // 豁免的优势/劣势及自动失败来源
+	Crit       bool                `json:"crit,omitempty"`        // 是否暴击
+	SaveRoll   *models.CheckResult `json:"save_roll,omitempty"`   // 豁免检定
+	Saved      bool                `json:"saved,omitempty"`       // 是否豁免成功
+	ConditionsApplied []string     `json:"conditions_applied,omitempty"` // 施加的状态
+	Cover      CoverLevel          `json:"cover,omitempty"`       // 目标的掩护等级（影响法术攻击 AC 和敏捷豁免）
+	DamageRoll *models.DiceResult  `json:"damage_roll,omitempty"` // 伤害/治疗骰
 }
 
 // ResolveAttack 执行攻击
+// 自动应用攻击者和目标角色身上的状态（距离未知，近战攻击视为在 5 尺内）
 // 规则参考: PHB 第9章 - Making an Attack
 func ResolveAttack(attacker, target *models.Character, weapon *models.EquipmentItem, advantage, disadvantage bool, roller *dice.Roller) *AttackResult {
+	modifiers := NewRollModifiers(advantage, disadvantage)
+	AddAttackConditionModifiers(modifiers, CollectConditions(attacker, nil), CollectConditions(target, nil), movement.DistanceUnknown, IsRangedWeapon(weapon))
+	return ResolveAttackWithModifiers(attacker, target, weapon, modifiers, roller)
+}
+
+// ResolveAttackWithModifiers 按已汇总的检定调整执行攻击
+// 规则参考: PHB 第9章 - Making an Attack
+func ResolveAttackWithModifiers(attacker, target *models.Character, weapon *models.EquipmentItem, modifiers *RollModifiers, roller *dice.Roller) *AttackResult {
+	return ResolveAttackWithCover(attacker, target, weapon, modifiers, CoverNone, roller)
+}
+
+// ResolveAttackWithCover 按已汇总的检定调整执行攻击，目标的掩护加值计入 AC
+// 全身掩护的目标不能被直接攻击，应在调用前拒绝
+// 规则参考: PHB 第9章 - Making an Attack / Cover
+func ResolveAttackWithCover(attacker, target *models.Character, weapon *models.EquipmentItem, modifiers *RollModifiers, cover CoverLevel, roller *dice.Roller) *AttackResult {
 	result := &AttackResult{
-		TargetAC:   GetArmorClass(target),
+		TargetAC:   GetArmorClass(target) + cover.Bonus(),
 		DamageType: "slashing", // 默认挥砍伤害
+		Modifiers:  modifiers,
+		CoverBonus: cover.Bonus(),
+	}
+	if cover != CoverNone {
+		result.Cover = cover
 	}
 
 	// 获取武器伤害类型
@@ -63,13 +115,7 @@ func ResolveAttack(attacker, target *models.Character, weapon *models.EquipmentI
 
 	// 投攻击骰
 	// 规则参考: PHB 第9章 - Attack Rolls
-	if advantage && !disadvantage {
-		result.AttackRoll = roller.RollWithAdvantage(attackBonus)
-	} else if disadvantage && !advantage {
-		result.AttackRoll = roller.RollWithDisadvantage(attackBonus)
-	} else {
-		result.AttackRoll = roller.RollD20(attackBonus)
-	}
+	result.AttackRoll = modifiers.RollD20(attackBonus, roller)
 
 	// 判定命中
 	// 规则参考: PHB 第9章 - Attack Rolls / Critical Hits
@@ -84,13 +130,34 @@ func ResolveAttack(attacker, target *models.Character, weapon *models.EquipmentI
 	} else {
 		// 普通命中判定
 		result.Hit = result.AttackRoll.Total >= result.TargetAC
-		result.Crit = false
+		// 5 尺内命中麻痹/昏迷目标自动暴击
+		result.Crit = result.Hit && modifiers.AutoCrit
 	}
 
 	// 如果命中，计算伤害
 	if result.Hit {
 		result.DamageRoll = RollDamage(weapon, result.Crit, roller)
-		result.Damage = result.DamageRoll.Total
+		instances := []DamageInstance{{DamageType: result.DamageType, Amount: result.DamageRoll.Total}}
+
+		// 附加伤害（如火焰剑的 1d6 火焰），暴击时同样翻倍
+		// 规则参考: PHB 第9章 - Critical Hits
+		if weapon != nil {
+			for _, extra := range weapon.ExtraDamage {
+				if extra.Damage == "" {
+					continue
+				}
+				roll := RollFormulas([]string{extra.Damage}, 0, result.Crit, roller)
+				result.ExtraDamageRolls = append(result.ExtraDamageRolls, roll)
+				instances = append(instances, DamageInstance{DamageType: extra.DamageType, Amount: roll.Total})
+			}
+		}
+
+		// 应用免疫、抗性和易伤
+		// 规则参考: PHB 第9章 - Damage Resistance and Vulnerability
+		application := ApplyDamageModifiers(target, instances)
+		result.RawDamage = application.Before
+		result.Damage = application.After
+		result.DamageBreakdown = application.Breakdown
 
 		// 应用伤害到目标
 		if target.HP != nil {
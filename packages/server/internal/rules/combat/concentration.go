@@ -0,0 +1,71 @@
+package combat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// durationPattern 匹配法术持续时间中的数值和单位，如 "up to 1 minute"
+var durationPattern = regexp.MustCompile(`(\d+)\s*(round|minute|hour|day)`)
+
+// ConcentrationSaveDC 计算维持专注的体质豁免DC
+// DC 为 10 或所受伤害的一半，取较高者
+// 规则参考: PHB 第10章 - Concentration
+func ConcentrationSaveDC(damage int) int {
+	dc := damage / 2
+	if dc < 10 {
+		dc = 10
+	}
+	return dc
+}
+
+// LosesConcentration 检查角色是否因失能或生命值降为0而失去专注
+// 规则参考: PHB 第10章 - Concentration / 附录A - Incapacitated
+func LosesConcentration(character *models.Character, conditions []models.Condition) (bool, string) {
+	if character != nil && character.HP != nil && character.HP.IsAtZero() {
+		return true, "dropped to 0 hit points"
+	}
+
+	for _, cond := range conditions {
+		if cond.Type == models.ConditionIncapacitated {
+			return true, "incapacitated"
+		}
+		effect := models.GetConditionEffect(cond.Type)
+		if effect.OtherEffects["incapacitated"] == "true" {
+			return true, fmt.Sprintf("%s (incapacitated)", cond.Type)
+		}
+	}
+	return false, ""
+}
+
+// ParseDurationRounds 将法术持续时间转换为回合数（1 回合 = 6 秒）
+// 无法解析或为瞬间/直到解除时返回 0
+// 规则参考: PHB 第10章 - Duration
+func ParseDurationRounds(duration string) int {
+	matches := durationPattern.FindStringSubmatch(strings.ToLower(duration))
+	if matches == nil {
+		return 0
+	}
+
+	amount, err := strconv.Atoi(matches[1])
+	if err != nil || amount <= 0 {
+		return 0
+	}
+
+	switch matches[2] {
+	case "round":
+		return amount
+	case "minute":
+		return amount * 10
+	case "hour":
+		return amount * 600
+	case "day":
+		return amount * 14400
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,251 @@
+package combat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+)
+
+// meleeReach 近战范围内的距离（英尺），用于倒地和自动暴击判定
+const meleeReach = 5
+
+// autoFailSaveConditions 力量和敏捷豁免自动失败的状态
+// 规则参考: PHB 附录A - Paralyzed / Petrified / Stunned / Unconscious
+var autoFailSaveConditions = map[string]bool{
+	models.ConditionParalyzed:   true,
+	models.ConditionPetrified:   true,
+	models.ConditionStunned:     true,
+	models.ConditionUnconscious: true,
+}
+
+// RollModifiers 由状态、动作等因素汇总出的 d20 检定调整
+// 优势与劣势同时存在时相互抵消
+// 规则参考: PHB 第7章 - Advantage and Disadvantage
+type RollModifiers struct {
+	Advantage    bool     `json:"advantage"`           // 是否有优势来源
+	Disadvantage bool     `json:"disadvantage"`        // 是否有劣势来源
+	AutoFail     bool     `json:"auto_fail,omitempty"` // 自动失败（豁免）
+	AutoCrit     bool     `json:"auto_crit,omitempty"` // 命中自动暴击
+	Reasons      []string `json:"reasons,omitempty"`   // 各项调整的原因
+}
+
+// NewRollModifiers 创建检定调整，包含调用方指定的优势/劣势
+func NewRollModifiers(advantage, disadvantage bool) *RollModifiers {
+	m := &RollModifiers{Reasons: make([]string, 0)}
+	if advantage {
+		m.AddAdvantage("requested advantage")
+	}
+	if disadvantage {
+		m.AddDisadvantage("requested disadvantage")
+	}
+	return m
+}
+
+// AddAdvantage 添加优势来源
+func (m *RollModifiers) AddAdvantage(reason string) {
+	m.Advantage = true
+	m.Reasons = append(m.Reasons, "advantage: "+reason)
+}
+
+// AddDisadvantage 添加劣势来源
+func (m *RollModifiers) AddDisadvantage(reason string) {
+	m.Disadvantage = true
+	m.Reasons = append(m.Reasons, "disadvantage: "+reason)
+}
+
+// SetAutoFail 标记检定自动失败
+func (m *RollModifiers) SetAutoFail(reason string) {
+	m.AutoFail = true
+	m.Reasons = append(m.Reasons, "auto-fail: "+reason)
+}
+
+// SetAutoCrit 标记命中自动暴击
+func (m *RollModifiers) SetAutoCrit(reason string) {
+	m.AutoCrit = true
+	m.Reasons = append(m.Reasons, "auto-crit: "+reason)
+}
+
+// RollD20 按优势/劣势投 d20
+func (m *RollModifiers) RollD20(modifier int, roller *dice.Roller) *models.DiceResult {
+	if m.Advantage && !m.Disadvantage {
+		return roller.RollWithAdvantage(modifier)
+	}
+	if m.Disadvantage && !m.Advantage {
+		return roller.RollWithDisadvantage(modifier)
+	}
+	return roller.RollD20(modifier)
+}
+
+// CollectConditions 合并角色自身状态与战斗中参战者的临时状态
+// 同类状态只保留一个，力竭保留最高等级
+func CollectConditions(character *models.Character, extra []models.Condition) []models.Condition {
+	all := make([]models.Condition, 0)
+	if character != nil {
+		all = append(all, character.Conditions...)
+	}
+	all = append(all, extra...)
+
+	merged := make([]models.Condition, 0, len(all))
+	index := make(map[string]int)
+	for _, cond := range all {
+		i, ok := index[cond.Type]
+		if !ok {
+			index[cond.Type] = len(merged)
+			merged = append(merged, cond)
+			continue
+		}
+		if cond.Type == models.ConditionExhaustion &&
+			models.ExtractExhaustionLevel(cond.Source) > models.ExtractExhaustionLevel(merged[i].Source) {
+			merged[i] = cond
+		}
+	}
+	return merged
+}
+
+// conditionEffect 获取状态对应的效果，力竭按等级展开
+func conditionEffect(cond models.Condition) (string, models.ConditionEffect) {
+	if cond.Type == models.ConditionExhaustion {
+		level := models.ExtractExhaustionLevel(cond.Source)
+		return fmt.Sprintf("exhaustion level %d", level), models.GetExhaustionEffect(level)
+	}
+	return cond.Type, models.GetConditionEffect(cond.Type)
+}
+
+// AddAttackConditionModifiers 根据攻击者和目标的状态调整攻击检定
+// distance 为双方距离（英尺），未知时为负数，此时近战攻击视为在 5 尺内
+// 规则参考: PHB 附录A - Conditions / 第9章 - Unseen Attackers and Targets
+func AddAttackConditionModifiers(m *RollModifiers, attackerConditions, targetConditions []models.Condition, distance int, ranged bool) {
+	withinReach := !ranged
+	if distance >= 0 {
+		withinReach = distance <= meleeReach
+	}
+
+	for _, cond := range attackerConditions {
+		name, effect := conditionEffect(cond)
+		if containsEffect(effect.Disadvantages, "attack_rolls") {
+			m.AddDisadvantage(fmt.Sprintf("attacker is %s", name))
+		}
+		if containsEffect(effect.Advantages, "attack_rolls") {
+			m.AddAdvantage(fmt.Sprintf("attacker is %s", name))
+		}
+	}
+
+	for _, cond := range targetConditions {
+		name, effect := conditionEffect(cond)
+
+		// 倒地：5 尺内的攻击具有优势，其余具有劣势
+		if cond.Type == models.ConditionProne {
+			if withinReach {
+				m.AddAdvantage("target is prone within 5 feet")
+			} else {
+				m.AddDisadvantage("target is prone and more than 5 feet away")
+			}
+			continue
+		}
+
+		if containsEffect(effect.Advantages, "attack_against") {
+			m.AddAdvantage(fmt.Sprintf("target is %s", name))
+		}
+		if containsEffect(effect.Disadvantages, "attack_against") {
+			m.AddDisadvantage(fmt.Sprintf("target is %s", name))
+		}
+		if withinReach && effect.OtherEffects["auto_crit"] == "true" && !m.AutoCrit {
+			m.SetAutoCrit(fmt.Sprintf("target is %s within 5 feet", name))
+		}
+	}
+}
+
+// AddSaveConditionModifiers 根据状态调整豁免检定
+// 麻痹、石化、震慑、昏迷的生物力量和敏捷豁免自动失败
+// 规则参考: PHB 附录A - Conditions / Exhaustion
+func AddSaveConditionModifiers(m *RollModifiers, conditions []models.Condition, ability string) {
+	ability = strings.ToLower(ability)
+	physical := ability == "strength" || ability == "dexterity"
+
+	for _, cond := range conditions {
+		name, effect := conditionEffect(cond)
+		if physical && autoFailSaveConditions[cond.Type] {
+			if !m.AutoFail {
+				m.SetAutoFail(fmt.Sprintf("%s fails %s saving throws", name, ability))
+			}
+			continue
+		}
+		if containsEffect(effect.Disadvantages, ability+"_saves") || containsEffect(effect.Disadvantages, "saving_throws") {
+			m.AddDisadvantage(fmt.Sprintf("%s (%s saving throws)", name, ability))
+		}
+	}
+}
+
+// AddCheckConditionModifiers 根据状态调整属性检定
+// 规则参考: PHB 附录A - Poisoned / Exhaustion
+func AddCheckConditionModifiers(m *RollModifiers, conditions []models.Condition) {
+	for _, cond := range conditions {
+		name, effect := conditionEffect(cond)
+		if containsEffect(effect.Disadvantages, "ability_checks") {
+			m.AddDisadvantage(fmt.Sprintf("%s (ability checks)", name))
+		}
+	}
+}
+
+// RollSavingThrowWithModifiers 按检定调整执行豁免检定
+// 自动失败时仍会投骰以便记录，但结果判定为失败
+func RollSavingThrowWithModifiers(character *models.Character, ability string, dc int, m *RollModifiers, roller *dice.Roller) *models.CheckResult {
+	return RollSavingThrowWithBonus(character, ability, dc, 0, m, roller)
+}
+
+// RollSavingThrowWithBonus 投豁免检定，附加额外加值（如掩护对敏捷豁免的加值）
+// 规则参考: PHB 第7章 - Saving Throws / 第9章 - Cover
+func RollSavingThrowWithBonus(character *models.Character, ability string, dc, bonus int, m *RollModifiers, roller *dice.Roller) *models.CheckResult {
+	diceResult := m.RollD20(GetSaveModifier(character, ability)+bonus, roller)
+
+	checkResult := models.NewCheckResult(diceResult, strings.ToLower(ability))
+	checkResult.SetDC(dc)
+	if m.AutoFail {
+		checkResult.Success = false
+	}
+	return checkResult
+}
+
+// IsIncapacitated 检查状态中是否有失能（或包含失能的状态），失能的生物不能采取动作或反应
+// 规则参考: PHB 附录A - Incapacitated
+func IsIncapacitated(conditions []models.Condition) bool {
+	for _, cond := range conditions {
+		if cond.Type == models.ConditionIncapacitated {
+			return true
+		}
+		if _, effect := conditionEffect(cond); effect.OtherEffects["incapacitated"] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRangedWeapon 检查是否为远程武器
+// 规则参考: PHB 第5章 - Weapon Properties (Ammunition / Range)
+func IsRangedWeapon(weapon *models.EquipmentItem) bool {
+	if weapon == nil {
+		return false
+	}
+	if strings.Contains(strings.ToLower(weapon.Subtype), "ranged") {
+		return true
+	}
+	for _, prop := range weapon.Properties {
+		switch strings.ToLower(prop) {
+		case "ranged", "ammunition":
+			return true
+		}
+	}
+	return false
+}
+
+// containsEffect 检查效果列表中是否包含指定项
+func containsEffect(effects []string, name string) bool {
+	for _, e := range effects {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package combat
+
+import (
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+)
+
+// CoverLevel 掩护等级
+// 规则参考: PHB 第9章 - Cover
+type CoverLevel string
+
+const (
+	CoverNone          CoverLevel = "none"           // 无掩护
+	CoverHalf          CoverLevel = "half"           // 半身掩护: AC 和敏捷豁免 +2
+	CoverThreeQuarters CoverLevel = "three_quarters" // 四分之三掩护: AC 和敏捷豁免 +5
+	CoverTotal         CoverLevel = "total"          // 全身掩护: 不能被攻击或法术直接指定为目标
+)
+
+// Bonus 返回掩护提供的 AC 和敏捷豁免加值
+// 规则参考: PHB 第9章 - Cover
+func (c CoverLevel) Bonus() int {
+	switch c {
+	case CoverHalf:
+		return 2
+	case CoverThreeQuarters:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// IsTotal 检查是否为全身掩护
+func (c CoverLevel) IsTotal() bool {
+	return c == CoverTotal
+}
+
+// coverInset 连线端点向格子内部收缩的距离（格）
+// 避免沿墙或贴着生物边缘的连线被误判为受阻；生物所占格收缩得更多，
+// 使沿格线经过生物边缘的连线不算受阻
+const (
+	coverInset    = 0.01
+	creatureInset = 0.02
+)
+
+// CalculateCover 使用角到角方法计算目标相对攻击者的掩护
+// 从攻击者所占空间的一个角向目标所占某一格的四个角连线，
+// 取对目标最不利（受阻最少）的组合：
+// 1-2 条受阻为半身掩护，3-4 条受阻为四分之三掩护；
+// 所有连线都被墙壁阻挡时为全身掩护（生物只提供半身或四分之三掩护）
+// 阻挡移动的墙壁和关闭的门视为障碍，打开的门和地形墙不提供掩护
+// 规则参考: DMG 第8章 - Cover (Optional Rule: Determining Cover on a Grid) / PHB 第9章 - Cover
+func CalculateCover(battleMap *models.Map, attacker, target *models.Token) CoverLevel {
+	if battleMap == nil || attacker == nil || target == nil {
+		return CoverNone
+	}
+
+	walls := make([]*models.Wall, 0, len(battleMap.Walls))
+	for _, wall := range battleMap.Walls {
+		if providesCover(wall) {
+			walls = append(walls, wall)
+		}
+	}
+	creatures := make([]models.Position, 0, len(battleMap.Tokens))
+	for i := range battleMap.Tokens {
+		token := &battleMap.Tokens[i]
+		if token.ID == attacker.ID || token.ID == target.ID {
+			continue
+		}
+		creatures = append(creatures, occupiedSquares(token)...)
+	}
+
+	best := -1
+	allWalled := true
+	for _, from := range occupiedSquares(attacker) {
+		for _, corner := range squareCorners(from) {
+			for _, square := range occupiedSquares(target) {
+				blocked, walled := 0, 0
+				for _, to := range squareCorners(square) {
+					switch {
+					case lineBlockedByWall(corner, to, walls):
+						blocked++
+						walled++
+					case lineBlockedByCreature(corner, to, creatures):
+						blocked++
+					}
+				}
+				if walled < 4 {
+					allWalled = false
+				}
+				if best < 0 || blocked < best {
+					best = blocked
+				}
+			}
+		}
+	}
+
+	switch {
+	case allWalled:
+		return CoverTotal
+	case best >= 3:
+		return CoverThreeQuarters
+	case best >= 1:
+		return CoverHalf
+	default:
+		return CoverNone
+	}
+}
+
+// providesCover 检查墙壁是否构成掩护障碍
+func providesCover(wall *models.Wall) bool {
+	if wall == nil {
+		return false
+	}
+	if wall.Type == models.WallTypeDoor && wall.Door != nil {
+		return wall.Door.State != models.DoorStateOpen
+	}
+	if wall.Type == models.WallTypeTerrain {
+		return false
+	}
+	return wall.IsBlocking()
+}
+
+// corner 连线端点（格坐标，已向所在格内收缩）
+type corner struct {
+	x, y float64
+}
+
+// squareCorners 返回格子的四个角，略向格子中心收缩
+func squareCorners(square models.Position) [4]corner {
+	x, y := float64(square.X), float64(square.Y)
+	return [4]corner{
+		{x + coverInset, y + coverInset},
+		{x + 1 - coverInset, y + coverInset},
+		{x + coverInset, y + 1 - coverInset},
+		{x + 1 - coverInset, y + 1 - coverInset},
+	}
+}
+
+// occupiedSquares 返回 Token 占据的格子（超小型生物占据自身所在格）
+func occupiedSquares(token *models.Token) []models.Position {
+	squares := movement.GetTokenOccupiedSquares(token)
+	if len(squares) == 0 {
+		squares = []models.Position{token.Position}
+	}
+	return squares
+}
+
+// lineBlockedByWall 检查连线是否穿过墙壁
+func lineBlockedByWall(from, to corner, walls []*models.Wall) bool {
+	for _, wall := range walls {
+		if movement.SegmentCrossesWall(from.x, from.y, to.x, to.y, wall) {
+			return true
+		}
+	}
+	return false
+}
+
+// lineBlockedByCreature 检查连线是否穿过其他生物所占的格子
+func lineBlockedByCreature(from, to corner, squares []models.Position) bool {
+	for _, square := range squares {
+		x0, y0 := float64(square.X)+creatureInset, float64(square.Y)+creatureInset
+		x1, y1 := float64(square.X+1)-creatureInset, float64(square.Y+1)-creatureInset
+		if movement.SegmentsIntersect(from.x, from.y, to.x, to.y, x0, y0, x1, y0) ||
+			movement.SegmentsIntersect(from.x, from.y, to.x, to.y, x1, y0, x1, y1) ||
+			movement.SegmentsIntersect(from.x, from.y, to.x, to.y, x1, y1, x0, y1) ||
+			movement.SegmentsIntersect(from.x, from.y, to.x, to.y, x0, y1, x0, y0) {
+			return true
+		}
+	}
+	return false
+}
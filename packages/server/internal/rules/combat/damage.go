@@ -0,0 +1,141 @@
+package combat
+
+import (
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// DamageModifier 伤害调整类型
+// 规则参考: PHB 第9章 - Damage Resistance and Vulnerability
+type DamageModifier string
+
+const (
+	// DamageModifierNone 无调整
+	DamageModifierNone DamageModifier = "none"
+	// DamageModifierImmune 免疫（伤害为0）
+	DamageModifierImmune DamageModifier = "immune"
+	// DamageModifierResistant 抗性（伤害减半）
+	DamageModifierResistant DamageModifier = "resistant"
+	// DamageModifierVulnerable 易伤（伤害加倍）
+	DamageModifierVulnerable DamageModifier = "vulnerable"
+	// DamageModifierResistantVulnerable 同时具有抗性和易伤（先减半后加倍）
+	DamageModifierResistantVulnerable DamageModifier = "resistant_vulnerable"
+)
+
+// DamageInstance 单一类型的伤害
+type DamageInstance struct {
+	DamageType string `json:"damage_type"` // 伤害类型
+	Amount     int    `json:"amount"`      // 伤害量（调整前）
+}
+
+// DamageTypeResult 单一类型伤害的结算结果
+type DamageTypeResult struct {
+	DamageType string         `json:"damage_type"` // 伤害类型
+	Before     int            `json:"before"`      // 调整前伤害
+	After      int            `json:"after"`       // 调整后伤害
+	Modifier   DamageModifier `json:"modifier"`    // 应用的调整
+}
+
+// DamageApplication 伤害结算结果
+type DamageApplication struct {
+	Before    int                `json:"before"`    // 调整前总伤害
+	After     int                `json:"after"`     // 调整后总伤害
+	Breakdown []DamageTypeResult `json:"breakdown"` // 各类型明细
+}
+
+// GetDamageModifier 获取目标对某种伤害类型的调整
+// 规则参考: PHB 第9章 - Damage Resistance and Vulnerability
+func GetDamageModifier(target *models.Character, damageType string) DamageModifier {
+	if target == nil || target.Traits == nil || damageType == "" {
+		return DamageModifierNone
+	}
+
+	traits := target.Traits
+	if hasDamageType(traits.DamageImmunities, damageType) {
+		return DamageModifierImmune
+	}
+
+	resistant := hasDamageType(traits.DamageResistances, damageType)
+	vulnerable := hasDamageType(traits.DamageVulnerabilities, damageType)
+	switch {
+	case resistant && vulnerable:
+		return DamageModifierResistantVulnerable
+	case resistant:
+		return DamageModifierResistant
+	case vulnerable:
+		return DamageModifierVulnerable
+	default:
+		return DamageModifierNone
+	}
+}
+
+// AdjustDamage 按调整类型计算最终伤害
+// 顺序: 免疫 → 抗性（减半，向下取整）→ 易伤（加倍）
+// 同一类型的多个抗性或易伤不叠加
+// 规则参考: PHB 第9章 - Damage Resistance and Vulnerability
+func AdjustDamage(amount int, modifier DamageModifier) int {
+	if amount <= 0 {
+		return 0
+	}
+
+	switch modifier {
+	case DamageModifierImmune:
+		return 0
+	case DamageModifierResistant:
+		return amount / 2
+	case DamageModifierVulnerable:
+		return amount * 2
+	case DamageModifierResistantVulnerable:
+		return (amount / 2) * 2
+	default:
+		return amount
+	}
+}
+
+// ApplyDamageModifiers 对多种类型的伤害应用目标的免疫、抗性和易伤
+// 相同类型的伤害先合并再调整，避免多次向下取整
+// 规则参考: PHB 第9章 - Damage Resistance and Vulnerability
+func ApplyDamageModifiers(target *models.Character, instances []DamageInstance) *DamageApplication {
+	result := &DamageApplication{
+		Breakdown: make([]DamageTypeResult, 0, len(instances)),
+	}
+
+	index := make(map[string]int)
+	for _, instance := range instances {
+		damageType := strings.ToLower(strings.TrimSpace(instance.DamageType))
+		amount := instance.Amount
+		if amount < 0 {
+			amount = 0
+		}
+		if i, ok := index[damageType]; ok {
+			result.Breakdown[i].Before += amount
+			continue
+		}
+		index[damageType] = len(result.Breakdown)
+		result.Breakdown = append(result.Breakdown, DamageTypeResult{
+			DamageType: damageType,
+			Before:     amount,
+		})
+	}
+
+	for i := range result.Breakdown {
+		entry := &result.Breakdown[i]
+		entry.Modifier = GetDamageModifier(target, entry.DamageType)
+		entry.After = AdjustDamage(entry.Before, entry.Modifier)
+		result.Before += entry.Before
+		result.After += entry.After
+	}
+
+	return result
+}
+
+// hasDamageType 检查伤害类型列表中是否包含指定类型（忽略大小写）
+func hasDamageType(list []string, damageType string) bool {
+	for _, t := range list {
+		if strings.EqualFold(strings.TrimSpace(t), damageType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package combat
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// extraAttackPattern 匹配 "Extra Attack (2)" 形式的额外攻击次数
+var extraAttackPattern = regexp.MustCompile(`\((\d+)\)`)
+
+// GetAttacksPerAction 获取一次攻击动作可进行的攻击次数
+// 根据角色特性中的 Extra Attack 计算（"Extra Attack" 为 2 次，
+// "Extra Attack (2)" 为 3 次，依此类推）
+// 规则参考: PHB 第3章 - Extra Attack
+func GetAttacksPerAction(character *models.Character) int {
+	attacks := 1
+	if character == nil {
+		return attacks
+	}
+
+	for _, feature := range character.Features {
+		if feature == nil {
+			continue
+		}
+		name := strings.ToLower(feature.Name)
+		if !strings.Contains(name, "extra attack") {
+			continue
+		}
+
+		count := 2
+		if matches := extraAttackPattern.FindStringSubmatch(name); matches != nil {
+			if extra, err := strconv.Atoi(matches[1]); err == nil && extra > 0 {
+				count = extra + 1
+			}
+		}
+		if count > attacks {
+			attacks = count
+		}
+	}
+
+	return attacks
+}
+
+// FindAttack 按ID或名称查找角色可用的武器或天生攻击
+// 查找顺序: 主手 → 副手 → 天生攻击
+func FindAttack(character *models.Character, ref string) *models.EquipmentItem {
+	if character == nil || ref == "" {
+		return nil
+	}
+
+	candidates := make([]*models.EquipmentItem, 0, len(character.NaturalAttacks)+2)
+	if character.EquipmentSlots != nil {
+		candidates = append(candidates, character.EquipmentSlots.MainHand, character.EquipmentSlots.OffHand)
+	}
+	candidates = append(candidates, character.NaturalAttacks...)
+
+	for _, item := range candidates {
+		if item != nil && item.ID == ref {
+			return item
+		}
+	}
+	for _, item := range candidates {
+		if item != nil && strings.EqualFold(item.Name, ref) {
+			return item
+		}
+	}
+	return nil
+}
+
+// BuildMultiattack 展开怪物的多重攻击为按顺序执行的攻击列表
+// 无法找到的攻击项会被跳过
+// 规则参考: MM 第1章 - Multiattack
+func BuildMultiattack(character *models.Character) []*models.EquipmentItem {
+	if character == nil || character.Multiattack == nil {
+		return nil
+	}
+
+	sequence := make([]*models.EquipmentItem, 0)
+	for _, entry := range character.Multiattack.Attacks {
+		attack := FindAttack(character, entry.AttackID)
+		if attack == nil {
+			continue
+		}
+		count := entry.Count
+		if count <= 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			sequence = append(sequence, attack)
+		}
+	}
+	return sequence
+}
+
+// IsLightWeapon 检查武器是否具有轻型属性
+// 规则参考: PHB 第5章 - Weapon Properties (Light)
+func IsLightWeapon(weapon *models.EquipmentItem) bool {
+	if weapon == nil {
+		return false
+	}
+	for _, prop := range weapon.Properties {
+		if strings.EqualFold(prop, "light") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package combat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// 射程和触及的解析模式，如 "80/320"、"range 20/60 ft."、"reach 10 ft."
+var (
+	rangePattern = regexp.MustCompile(`(\d+)\s*/\s*(\d+)`)
+	reachPattern = regexp.MustCompile(`(?i)reach\s*(\d+)`)
+	feetPattern  = regexp.MustCompile(`(\d+)`)
+)
+
+// WeaponRange 武器的触及和射程（英尺）
+// 规则参考: PHB 第5章 - Weapon Properties (Range / Reach / Thrown)
+type WeaponRange struct {
+	Reach  int  `json:"reach"`            // 近战触及
+	Normal int  `json:"normal,omitempty"` // 正常射程（远程或投掷）
+	Long   int  `json:"long,omitempty"`   // 最远射程（远程或投掷）
+	Ranged bool `json:"ranged"`           // 是否为远程武器
+	Thrown bool `json:"thrown,omitempty"` // 是否可投掷
+}
+
+// GetWeaponRange 从武器的射程字段和属性解析触及与射程
+// 近战武器触及 5 尺，具有触及属性时 10 尺；只写一个射程的远程武器没有远距离
+// 没有武器时按徒手攻击处理（触及 5 尺）
+// 规则参考: PHB 第5章 - Weapon Properties
+func GetWeaponRange(weapon *models.EquipmentItem) WeaponRange {
+	r := WeaponRange{Reach: meleeReach}
+	if weapon == nil {
+		return r
+	}
+
+	r.Ranged = IsRangedWeapon(weapon)
+	for _, prop := range weapon.Properties {
+		switch strings.ToLower(prop) {
+		case "reach":
+			r.Reach = 10
+		case "thrown":
+			r.Thrown = true
+		}
+	}
+
+	if match := reachPattern.FindStringSubmatch(weapon.Range); match != nil {
+		if reach, err := strconv.Atoi(match[1]); err == nil && reach > 0 {
+			r.Reach = reach
+		}
+	}
+
+	if match := rangePattern.FindStringSubmatch(weapon.Range); match != nil {
+		r.Normal, _ = strconv.Atoi(match[1])
+		r.Long, _ = strconv.Atoi(match[2])
+	} else if r.Ranged {
+		if match := feetPattern.FindStringSubmatch(weapon.Range); match != nil {
+			r.Normal, _ = strconv.Atoi(match[1])
+			r.Long = r.Normal
+		}
+	}
+	return r
+}
+
+// RangeCheck 攻击距离判定结果
+type RangeCheck struct {
+	Distance  int    `json:"distance"`             // 双方距离（英尺），未知时为负数
+	InRange   bool   `json:"in_range"`             // 目标是否在触及或射程内
+	Ranged    bool   `json:"ranged"`               // 是否作为远程攻击进行（包括投掷）
+	LongRange bool   `json:"long_range,omitempty"` // 目标在远距离射程内（攻击具有劣势）
+	Reason    string `json:"reason,omitempty"`     // 超出范围的原因
+}
+
+// CheckAttackRange 检查目标是否在武器的触及或射程内
+// 远程攻击超过正常射程时具有劣势，超过最远射程无法攻击；
+// 投掷武器的目标超出触及时作为远程攻击
+// 距离未知时视为在范围内
+// 规则参考: PHB 第9章 - Ranged Attacks / Melee Attacks
+func CheckAttackRange(weapon *models.EquipmentItem, distance int) RangeCheck {
+	r := GetWeaponRange(weapon)
+	check := RangeCheck{Distance: distance, Ranged: r.Ranged}
+	if distance < 0 {
+		check.InRange = true
+		return check
+	}
+
+	if !r.Ranged {
+		if distance <= r.Reach {
+			check.InRange = true
+			return check
+		}
+		if !r.Thrown || r.Normal == 0 {
+			check.Reason = fmt.Sprintf("target is %d feet away, beyond %d feet reach", distance, r.Reach)
+			return check
+		}
+		check.Ranged = true
+	}
+
+	// 没有记录射程的远程武器不做限制
+	if r.Long == 0 {
+		check.InRange = true
+		return check
+	}
+	if distance > r.Long {
+		check.Reason = fmt.Sprintf("target is %d feet away, beyond %d feet long range", distance, r.Long)
+		return check
+	}
+	check.InRange = true
+	check.LongRange = distance > r.Normal
+	return check
+}
+
+// AddRangeModifiers 根据攻击距离调整攻击检定
+// 远距离射程的攻击具有劣势；5 尺内有未失能的敌对生物时远程攻击具有劣势
+// 规则参考: PHB 第9章 - Ranged Attacks (Range / Ranged Attacks in Close Combat)
+func AddRangeModifiers(m *RollModifiers, check RangeCheck, hostileAdjacent bool) {
+	if check.LongRange {
+		m.AddDisadvantage(fmt.Sprintf("target is %d feet away, beyond normal range", check.Distance))
+	}
+	if check.Ranged && hostileAdjacent {
+		m.AddDisadvantage("hostile creature within 5 feet of a ranged attacker")
+	}
+}
+
+// GetMeleeReach 获取角色可用于借机攻击的最大近战触及（英尺）
+// 包括装备的近战武器和怪物的天生攻击，没有近战武器时为徒手攻击的 5 尺
+// 规则参考: PHB 第9章 - Opportunity Attacks / 第5章 - Reach
+func GetMeleeReach(character *models.Character) int {
+	return GetWeaponRange(GetMeleeWeapon(character)).Reach
+}
+
+// GetMeleeWeapon 选择触及最远的近战武器或天生攻击，没有时返回 nil（徒手攻击）
+func GetMeleeWeapon(character *models.Character) *models.EquipmentItem {
+	if character == nil {
+		return nil
+	}
+
+	candidates := make([]*models.EquipmentItem, 0, len(character.NaturalAttacks)+2)
+	if slots := character.EquipmentSlots; slots != nil {
+		candidates = append(candidates, slots.MainHand, slots.OffHand)
+	}
+	candidates = append(candidates, character.NaturalAttacks...)
+
+	var best *models.EquipmentItem
+	bestReach := 0
+	for _, weapon := range candidates {
+		if weapon == nil || weapon.Damage == "" || IsRangedWeapon(weapon) {
+			continue
+		}
+		if reach := GetWeaponRange(weapon).Reach; reach > bestReach {
+			best, bestReach = weapon, reach
+		}
+	}
+	return best
+}
@@ -0,0 +1,305 @@
+package combat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+)
+
+// higherLevelsPattern 匹配升阶描述中的每级增加骰子
+// 例如: "the damage increases by 1d6 for each slot level above 3rd"
+var higherLevelsPattern = regexp.MustCompile(`(?i)(\d+d\d+)\s+for\s+each\s+(?:spell\s+)?(?:slot\s+)?level\s+above`)
+
+// GetSpellcastingAbility 获取施法属性
+// 优先使用法术书中记录的施法属性，否则按职业推断
+// 规则参考: PHB 第10章 - Spellcasting Ability
+func GetSpellcastingAbility(caster *models.Character) rules.AbilityName {
+	if caster == nil {
+		return rules.AbilityCharisma
+	}
+
+	if caster.Spellbook != nil && caster.Spellbook.SpellcastingAbility != "" {
+		ability := strings.ToLower(caster.Spellbook.SpellcastingAbility)
+		if _, ok := rules.SaveAbilityMapping[ability]; ok {
+			return rules.AbilityName(ability)
+		}
+	}
+
+	switch strings.ToLower(caster.Class) {
+	case "cleric", "druid", "ranger":
+		return rules.AbilityWisdom
+	case "wizard", "artificer":
+		return rules.AbilityIntelligence
+	default:
+		// 吟游诗人、术士、邪术师、圣武士及未知职业使用魅力
+		return rules.AbilityCharisma
+	}
+}
+
+// GetSpellcastingModifier 获取施法属性调整值
+// 规则参考: PHB 第10章 - Spellcasting Ability
+func GetSpellcastingModifier(caster *models.Character) int {
+	if caster == nil || caster.Abilities == nil {
+		return 0
+	}
+	return rules.GetModifierByName(caster.Abilities, GetSpellcastingAbility(caster))
+}
+
+// GetSpellSaveDC 获取法术豁免 DC
+// 规则参考: PHB 第10章 - Saving Throws
+// 公式: 8 + 熟练加值 + 施法属性调整值
+func GetSpellSaveDC(caster *models.Character) int {
+	if caster == nil {
+		return 8
+	}
+	return 8 + caster.GetProficiencyBonus() + GetSpellcastingModifier(caster)
+}
+
+// GetSpellAttackBonus 获取法术攻击加值
+// 规则参考: PHB 第10章 - Attack Rolls
+// 公式: 熟练加值 + 施法属性调整值
+func GetSpellAttackBonus(caster *models.Character) int {
+	if caster == nil {
+		return 0
+	}
+	return caster.GetProficiencyBonus() + GetSpellcastingModifier(caster)
+}
+
+// GetSaveModifier 获取豁免加值
+// 优先使用结构化豁免，其次为简单豁免加值，最后为属性调整值
+// 规则参考: PHB 第7章 - Saving Throws
+func GetSaveModifier(character *models.Character, ability string) int {
+	if character == nil {
+		return 0
+	}
+
+	ability = strings.ToLower(ability)
+	modifier := 0
+	if character.Abilities != nil {
+		modifier = rules.GetModifierByName(character.Abilities, rules.AbilityName(ability))
+	}
+
+	if character.SavesDetail != nil {
+		if saveDetail, ok := character.SavesDetail[ability]; ok {
+			return saveDetail.CalculateBonus(modifier, character.GetProficiencyBonus())
+		}
+	}
+
+	if character.Saves != nil {
+		if bonus, ok := character.Saves[ability]; ok {
+			return bonus
+		}
+	}
+
+	return modifier
+}
+
+// RollSavingThrow 投豁免检定
+// 规则参考: PHB 第7章 - Saving Throws
+func RollSavingThrow(character *models.Character, ability string, dc int, advantage, disadvantage bool, roller *dice.Roller) *models.CheckResult {
+	modifiers := NewRollModifiers(advantage, disadvantage)
+	AddSaveConditionModifiers(modifiers, CollectConditions(character, nil), ability)
+	return RollSavingThrowWithModifiers(character, ability, dc, modifiers, roller)
+}
+
+// ResolveSpellAttack 执行法术攻击检定
+// 自动应用施法者和目标角色身上的状态（距离未知，按远程攻击处理）
+// 规则参考: PHB 第10章 - Attack Rolls / 第9章 - Critical Hits
+func ResolveSpellAttack(caster, target *models.Character, advantage, disadvantage bool, roller *dice.Roller) (roll *models.DiceResult, hit, crit bool) {
+	modifiers := NewRollModifiers(advantage, disadvantage)
+	AddAttackConditionModifiers(modifiers, CollectConditions(caster, nil), CollectConditions(target, nil), movement.DistanceUnknown, true)
+	return ResolveSpellAttackWithModifiers(caster, target, modifiers, roller)
+}
+
+// ResolveSpellAttackWithModifiers 按已汇总的检定调整执行法术攻击检定
+// 规则参考: PHB 第10章 - Attack Rolls / 附录A - Paralyzed
+func ResolveSpellAttackWithModifiers(caster, target *models.Character, modifiers *RollModifiers, roller *dice.Roller) (roll *models.DiceResult, hit, crit bool) {
+	return ResolveSpellAttackWithCover(caster, target, modifiers, CoverNone, roller)
+}
+
+// ResolveSpellAttackWithCover 执行法术攻击检定，目标的掩护加值计入 AC
+// 规则参考: PHB 第10章 - Attack Rolls / 第9章 - Cover
+func ResolveSpellAttackWithCover(caster, target *models.Character, modifiers *RollModifiers, cover CoverLevel, roller *dice.Roller) (roll *models.DiceResult, hit, crit bool) {
+	attackBonus := GetSpellAttackBonus(caster)
+	targetAC := GetArmorClass(target) + cover.Bonus()
+
+	roll = modifiers.RollD20(attackBonus, roller)
+
+	switch {
+	case roll.IsFumble():
+		return roll, false, false
+	case roll.IsCritical():
+		return roll, true, true
+	default:
+		hit = roll.Total >= targetAC
+		return roll, hit, hit && modifiers.AutoCrit
+	}
+}
+
+// GetCantripTier 获取戏法伤害倍数
+// 规则参考: PHB 第10章 - Cantrips（5级、11级、17级时增强）
+func GetCantripTier(characterLevel int) int {
+	switch {
+	case characterLevel >= 17:
+		return 4
+	case characterLevel >= 11:
+		return 3
+	case characterLevel >= 5:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// ScaleSpellDice 计算升阶后的骰子公式列表
+// 戏法按角色等级增加骰子数量；其他法术按高于法术等级的每一环增加 levelScale
+// （levelScale 为空时尝试从 higherLevels 描述中解析）
+// 规则参考: PHB 第10章 - Casting a Spell at a Higher Level
+func ScaleSpellDice(base string, levelScale []string, higherLevels string, spellLevel, castLevel, characterLevel int) []string {
+	if base == "" {
+		return nil
+	}
+
+	if spellLevel == 0 {
+		tier := GetCantripTier(characterLevel)
+		if tier == 1 {
+			return []string{base}
+		}
+		formula, err := dice.ParseFormula(base)
+		if err != nil || formula.Count == 0 {
+			return []string{base}
+		}
+		formula.Count *= tier
+		return []string{formatDiceTerm(formula.Count, formula.Sides, formula.Modifier)}
+	}
+
+	formulas := []string{base}
+	extraLevels := castLevel - spellLevel
+	if extraLevels <= 0 {
+		return formulas
+	}
+
+	scale := levelScale
+	if len(scale) == 0 {
+		if matches := higherLevelsPattern.FindStringSubmatch(higherLevels); matches != nil {
+			scale = []string{strings.ToLower(matches[1])}
+		}
+	}
+	if len(scale) == 0 {
+		return formulas
+	}
+
+	// 每高一环使用对应的增量；条目不足时重复最后一项
+	for i := 0; i < extraLevels; i++ {
+		idx := i
+		if idx >= len(scale) {
+			idx = len(scale) - 1
+		}
+		formulas = append(formulas, scale[idx])
+	}
+
+	return CombineDiceTerms(formulas)
+}
+
+// CombineDiceTerms 合并相同面数的骰子项
+// 例如: ["8d6", "1d6", "1d6"] -> ["10d6"]
+func CombineDiceTerms(formulas []string) []string {
+	type term struct {
+		count int
+		sides int
+	}
+
+	terms := make([]term, 0, len(formulas))
+	index := make(map[int]int)
+	modifier := 0
+	unparsed := make([]string, 0)
+
+	for _, f := range formulas {
+		parsed, err := dice.ParseFormula(f)
+		if err != nil || parsed.IsKeepRoll() {
+			unparsed = append(unparsed, f)
+			continue
+		}
+		modifier += parsed.Modifier
+		if parsed.Count == 0 || parsed.Sides == 0 {
+			continue
+		}
+		if i, ok := index[parsed.Sides]; ok {
+			terms[i].count += parsed.Count
+			continue
+		}
+		index[parsed.Sides] = len(terms)
+		terms = append(terms, term{count: parsed.Count, sides: parsed.Sides})
+	}
+
+	result := make([]string, 0, len(terms)+len(unparsed)+1)
+	for i, t := range terms {
+		mod := 0
+		if i == 0 {
+			mod = modifier
+		}
+		result = append(result, formatDiceTerm(t.count, t.sides, mod))
+	}
+	if len(terms) == 0 && modifier != 0 {
+		result = append(result, fmt.Sprintf("%d", modifier))
+	}
+	return append(result, unparsed...)
+}
+
+// RollFormulas 投掷多个骰子公式并合并为一个结果
+// 暴击时骰子数量翻倍（修正值不变）
+// 规则参考: PHB 第9章 - Damage Rolls / Critical Hits
+func RollFormulas(formulas []string, modifier int, isCrit bool, roller *dice.Roller) *models.DiceResult {
+	result := models.NewDiceResult(strings.Join(formulas, "+"))
+	rolls := make([]int, 0)
+	totalMod := modifier
+
+	for _, f := range formulas {
+		formula, err := dice.ParseFormula(f)
+		if err != nil {
+			continue
+		}
+		if isCrit {
+			formula.Count *= 2
+		}
+		roll := roller.RollFormula(formula)
+		rolls = append(rolls, roll.Rolls...)
+		totalMod += roll.Modifier
+	}
+
+	if modifier != 0 {
+		result.Formula = appendModifier(result.Formula, modifier)
+	}
+	if isCrit {
+		result.Formula += " (critical)"
+	}
+
+	result.SetRolls(rolls)
+	result.SetModifier(totalMod)
+	return result
+}
+
+// appendModifier 在公式末尾追加修正值
+func appendModifier(formula string, modifier int) string {
+	if modifier == 0 {
+		return formula
+	}
+	if formula == "" {
+		return fmt.Sprintf("%d", modifier)
+	}
+	return fmt.Sprintf("%s%+d", formula, modifier)
+}
+
+// formatDiceTerm 格式化骰子项
+func formatDiceTerm(count, sides, modifier int) string {
+	term := fmt.Sprintf("%dd%d", count, sides)
+	if modifier != 0 {
+		term += fmt.Sprintf("%+d", modifier)
+	}
+	return term
+}
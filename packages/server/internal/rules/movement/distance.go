@@ -0,0 +1,52 @@
+package movement
+
+import (
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// DistanceUnknown indicates that the distance between two creatures is unknown
+// (for example, when the combat has no battle map or a token is missing)
+const DistanceUnknown = -1
+
+// TokenDistance returns the distance in feet between the closest occupied squares
+// of two tokens. Adjacent tokens are 5 feet apart (one cell); diagonals count as one cell.
+// 规则参考: PHB 第9章 - Movement and Position / DMG 第8章 - Tactical Maps
+func TokenDistance(a, b *models.Token, cellSize int) int {
+	if a == nil || b == nil {
+		return DistanceUnknown
+	}
+	if cellSize <= 0 {
+		cellSize = 5
+	}
+
+	dx := axisGap(a.Position.X, a.GetSizeInGrids(), b.Position.X, b.GetSizeInGrids())
+	dy := axisGap(a.Position.Y, a.GetSizeInGrids(), b.Position.Y, b.GetSizeInGrids())
+
+	cells := dx
+	if dy > cells {
+		cells = dy
+	}
+	return cells * cellSize
+}
+
+// axisGap returns the number of cells between two spans on one axis
+// (0 when they overlap, 1 when adjacent)
+func axisGap(startA, sizeA, startB, sizeB int) int {
+	if sizeA < 1 {
+		sizeA = 1
+	}
+	if sizeB < 1 {
+		sizeB = 1
+	}
+	endA := startA + sizeA - 1
+	endB := startB + sizeB - 1
+
+	switch {
+	case endA < startB:
+		return startB - endA
+	case endB < startA:
+		return startA - endB
+	default:
+		return 0
+	}
+}
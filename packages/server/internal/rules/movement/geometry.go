@@ -0,0 +1,80 @@
+package movement
+
+import (
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// CellCenter returns the centre of a grid cell in grid units.
+// Wall bounds use the same units, with cell (x, y) spanning [x, x+1] x [y, y+1].
+func CellCenter(x, y int) (float64, float64) {
+	return float64(x) + 0.5, float64(y) + 0.5
+}
+
+// SegmentCrossesWall reports whether the segment from A to B touches the wall segment.
+// Walls without valid bounds never block.
+func SegmentCrossesWall(ax, ay, bx, by float64, wall *models.Wall) bool {
+	if wall == nil || len(wall.Bounds) != 4 {
+		return false
+	}
+	return SegmentsIntersect(ax, ay, bx, by,
+		float64(wall.Bounds[0]), float64(wall.Bounds[1]), float64(wall.Bounds[2]), float64(wall.Bounds[3]))
+}
+
+// SegmentsIntersect reports whether segment AB touches segment CD (endpoints included,
+// so a line cannot squeeze diagonally past the end of a wall)
+func SegmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	d1 := cross(cx, cy, dx, dy, ax, ay)
+	d2 := cross(cx, cy, dx, dy, bx, by)
+	d3 := cross(ax, ay, bx, by, cx, cy)
+	d4 := cross(ax, ay, bx, by, dx, dy)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(cx, cy, dx, dy, ax, ay)) ||
+		(d2 == 0 && onSegment(cx, cy, dx, dy, bx, by)) ||
+		(d3 == 0 && onSegment(ax, ay, bx, by, cx, cy)) ||
+		(d4 == 0 && onSegment(ax, ay, bx, by, dx, dy))
+}
+
+// DistanceToCell returns the distance in feet from the closest square a token occupies
+// to the given cell, measured like TokenDistance
+func DistanceToCell(token *models.Token, x, y, cellSize int) int {
+	if token == nil {
+		return DistanceUnknown
+	}
+	if cellSize <= 0 {
+		cellSize = 5
+	}
+
+	dx := axisGap(token.Position.X, token.GetSizeInGrids(), x, 1)
+	dy := axisGap(token.Position.Y, token.GetSizeInGrids(), y, 1)
+	if dy > dx {
+		dx = dy
+	}
+	return dx * cellSize
+}
+
+// cross returns the cross product of (B - A) and (P - A)
+func cross(ax, ay, bx, by, px, py float64) float64 {
+	return (bx-ax)*(py-ay) - (by-ay)*(px-ax)
+}
+
+// onSegment checks whether collinear point P lies within the bounding box of segment AB
+func onSegment(ax, ay, bx, by, px, py float64) bool {
+	return px >= minFloat(ax, bx) && px <= maxFloat(ax, bx) && py >= minFloat(ay, by) && py <= maxFloat(ay, by)
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,450 @@
+package movement
+
+import (
+	"container/heap"
+	"errors"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+var (
+	// ErrInvalidDestination is returned when the destination is outside the map or not walkable
+	ErrInvalidDestination = errors.New("destination is not a legal position for this token")
+	// ErrNoPath is returned when walls, closed doors or hostile creatures block every route
+	ErrNoPath = errors.New("no legal path to destination")
+)
+
+// edgeKind classifies what lies between two neighbouring cells
+type edgeKind int8
+
+const (
+	edgeUnknown edgeKind = iota
+	edgeOpen
+	edgeDifficult
+	edgeBlocked
+)
+
+// neighbourOffsets lists the eight grid directions, orthogonal moves first
+var neighbourOffsets = [8][2]int{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// PathResult is the cheapest legal route found for a token
+type PathResult struct {
+	// Path lists every position of the token's top-left corner, starting with its current position
+	Path []models.Position `json:"path"`
+	// Cost is the total movement cost in feet
+	Cost int `json:"cost"`
+	// DifficultCount is the number of steps that cost extra because of difficult terrain
+	DifficultCount int `json:"difficult_count"`
+
+	costs     []int  // cumulative cost at each path position
+	difficult []bool // whether the step into each path position was difficult
+	occupied  []bool // whether each path position overlaps another creature's space
+}
+
+// Truncate returns the longest prefix of the path that costs at most maxFeet
+// and does not end in another creature's space. The result contains only the
+// starting position when not even the first step is affordable.
+// 规则参考: PHB 第9章 - Moving Around Other Creatures
+func (r *PathResult) Truncate(maxFeet int) *PathResult {
+	end := 0
+	for i := 1; i < len(r.Path); i++ {
+		if r.costs[i] > maxFeet {
+			break
+		}
+		if !r.occupied[i] {
+			end = i
+		}
+	}
+	return r.TruncateAt(end)
+}
+
+// TruncateAt returns the prefix of the path that ends at the given path index
+func (r *PathResult) TruncateAt(index int) *PathResult {
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(r.Path)-1 {
+		return r
+	}
+
+	truncated := &PathResult{
+		Path:      append([]models.Position(nil), r.Path[:index+1]...),
+		Cost:      r.costs[index],
+		costs:     r.costs[:index+1],
+		difficult: r.difficult[:index+1],
+		occupied:  r.occupied[:index+1],
+	}
+	for _, d := range truncated.difficult {
+		if d {
+			truncated.DifficultCount++
+		}
+	}
+	return truncated
+}
+
+// IsHostile reports whether two tokens are on opposing sides (friendly vs hostile)
+func IsHostile(a, b *models.Token) bool {
+	return (a.Disposition == models.DispositionFriendly && b.Disposition == models.DispositionHostile) ||
+		(a.Disposition == models.DispositionHostile && b.Disposition == models.DispositionFriendly)
+}
+
+// CanMoveThroughHostile reports whether a creature may pass through a hostile creature's space
+// 规则参考: PHB 第9章 - Moving Around Other Creatures
+// "You can move through a hostile creature's space only if the creature is at least
+// two sizes larger or smaller than you."
+func CanMoveThroughHostile(moving, other *models.Token) bool {
+	diff := getSizeRank(moving.Size) - getSizeRank(other.Size)
+	return diff >= 2 || diff <= -2
+}
+
+// pathfinder holds the precomputed state for one A* search
+type pathfinder struct {
+	grid     *models.Grid
+	walls    models.Walls
+	size     int
+	cellSize int
+
+	blocked  []bool     // cells a hostile creature prevents the token from entering
+	occupied []bool     // cells occupied by another creature (difficult terrain)
+	edges    []edgeKind // lazily computed wall classification per cell and direction
+}
+
+// FindPath finds the cheapest legal route for a token to the given destination using A*.
+// Diagonal steps alternate between one and two squares, wall segments and closed or
+// locked doors block movement, difficult terrain cells, terrain walls and other
+// creatures' spaces double the cost of a step, and hostile creatures are routed
+// around unless they are two or more sizes different from the moving token.
+// 规则参考: PHB 第9章 - Movement and Position / DMG 第8章 - Tactical Maps (Diagonals)
+func FindPath(battleMap *models.Map, token *models.Token, toX, toY int) (*PathResult, error) {
+	grid := battleMap.Grid
+	if grid == nil || token == nil {
+		return nil, ErrInvalidDestination
+	}
+
+	pf := &pathfinder{
+		grid:     grid,
+		walls:    battleMap.Walls,
+		size:     token.GetSizeInGrids(),
+		cellSize: grid.CellSize,
+		blocked:  make([]bool, grid.Width*grid.Height),
+		occupied: make([]bool, grid.Width*grid.Height),
+		edges:    make([]edgeKind, grid.Width*grid.Height*len(neighbourOffsets)),
+	}
+	if pf.size < 1 {
+		pf.size = 1
+	}
+	if pf.cellSize <= 0 {
+		pf.cellSize = 5
+	}
+	pf.markCreatures(battleMap.Tokens, token)
+
+	fromX, fromY := token.Position.X, token.Position.Y
+	if !pf.canOccupy(toX, toY) {
+		return nil, ErrInvalidDestination
+	}
+	if fromX == toX && fromY == toY {
+		return pf.result([]int{pf.index(fromX, fromY)}, []int{0}, []bool{false}), nil
+	}
+
+	// Search state is (cell, diagonal parity): the next diagonal costs double when parity is 1
+	stateCount := grid.Width * grid.Height * 2
+	best := make([]int, stateCount)
+	for i := range best {
+		best[i] = -1
+	}
+	parent := make([]int, stateCount)
+	difficult := make([]bool, stateCount)
+
+	start := pf.index(fromX, fromY) * 2
+	best[start] = 0
+	parent[start] = -1
+
+	open := &nodeQueue{}
+	order := 0
+	heap.Push(open, &node{state: start, cost: 0, priority: pf.heuristic(fromX, fromY, toX, toY, 0)})
+
+	goal := -1
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		if current.cost > best[current.state] {
+			continue
+		}
+
+		cell := current.state / 2
+		parity := current.state % 2
+		x, y := cell%grid.Width, cell/grid.Width
+		if x == toX && y == toY {
+			goal = current.state
+			break
+		}
+
+		for dir, offset := range neighbourOffsets {
+			nx, ny := x+offset[0], y+offset[1]
+			if !pf.canOccupy(nx, ny) {
+				continue
+			}
+			stepDifficult, ok := pf.stepTerrain(x, y, dir)
+			if !ok {
+				continue
+			}
+
+			diagonal := offset[0] != 0 && offset[1] != 0
+			stepCost := pf.cellSize
+			nextParity := parity
+			if diagonal {
+				if parity == 1 {
+					stepCost *= 2
+				}
+				nextParity = 1 - parity
+			}
+			if stepDifficult {
+				stepCost *= 2
+			}
+
+			next := pf.index(nx, ny)*2 + nextParity
+			cost := current.cost + stepCost
+			if best[next] >= 0 && cost >= best[next] {
+				continue
+			}
+			best[next] = cost
+			parent[next] = current.state
+			difficult[next] = stepDifficult
+			order++
+			heap.Push(open, &node{
+				state:    next,
+				cost:     cost,
+				priority: cost + pf.heuristic(nx, ny, toX, toY, nextParity),
+				order:    order,
+			})
+		}
+	}
+
+	if goal < 0 {
+		return nil, ErrNoPath
+	}
+
+	// Walk back from the goal to rebuild the route
+	var cells, costs []int
+	var steps []bool
+	for state := goal; state >= 0; state = parent[state] {
+		cells = append(cells, state/2)
+		costs = append(costs, best[state])
+		steps = append(steps, difficult[state])
+	}
+	for i, j := 0, len(cells)-1; i < j; i, j = i+1, j-1 {
+		cells[i], cells[j] = cells[j], cells[i]
+		costs[i], costs[j] = costs[j], costs[i]
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	steps[0] = false
+
+	return pf.result(cells, costs, steps), nil
+}
+
+// markCreatures records which cells other creatures occupy and which of them are impassable
+func (pf *pathfinder) markCreatures(tokens []models.Token, moving *models.Token) {
+	for i := range tokens {
+		other := &tokens[i]
+		if other.ID == moving.ID {
+			continue
+		}
+		impassable := IsHostile(moving, other) && !CanMoveThroughHostile(moving, other)
+		squares := GetTokenOccupiedSquares(other)
+		if len(squares) == 0 {
+			// Tiny creatures still occupy the square they stand in
+			squares = []models.Position{other.Position}
+		}
+		for _, pos := range squares {
+			if pos.X < 0 || pos.Y < 0 || pos.X >= pf.grid.Width || pos.Y >= pf.grid.Height {
+				continue
+			}
+			idx := pf.index(pos.X, pos.Y)
+			pf.occupied[idx] = true
+			if impassable {
+				pf.blocked[idx] = true
+			}
+		}
+	}
+}
+
+// canOccupy checks whether the token's whole footprint fits at (x, y)
+func (pf *pathfinder) canOccupy(x, y int) bool {
+	if x < 0 || y < 0 || x+pf.size > pf.grid.Width || y+pf.size > pf.grid.Height {
+		return false
+	}
+	for dy := 0; dy < pf.size; dy++ {
+		for dx := 0; dx < pf.size; dx++ {
+			if !pf.grid.IsWalkable(x+dx, y+dy) || pf.blocked[pf.index(x+dx, y+dy)] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// footprintOccupied checks whether the token's footprint at (x, y) overlaps another creature
+func (pf *pathfinder) footprintOccupied(x, y int) bool {
+	for dy := 0; dy < pf.size; dy++ {
+		for dx := 0; dx < pf.size; dx++ {
+			if pf.occupied[pf.index(x+dx, y+dy)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stepTerrain checks one step of the whole footprint from (x, y) in the given direction.
+// It returns whether the step is difficult and whether it is allowed at all.
+// 规则参考: PHB 第8章 - Difficult Terrain
+// "Another creature's space is difficult terrain for you." Difficult terrain does not stack.
+func (pf *pathfinder) stepTerrain(x, y, dir int) (bool, bool) {
+	offset := neighbourOffsets[dir]
+	difficult := false
+	for dy := 0; dy < pf.size; dy++ {
+		for dx := 0; dx < pf.size; dx++ {
+			cx, cy := x+dx, y+dy
+			nx, ny := cx+offset[0], cy+offset[1]
+
+			switch pf.edge(cx, cy, dir) {
+			case edgeBlocked:
+				return false, false
+			case edgeDifficult:
+				difficult = true
+			}
+
+			if pf.grid.IsDifficultTerrain(nx, ny) || pf.occupied[pf.index(nx, ny)] {
+				difficult = true
+			}
+		}
+	}
+	return difficult, true
+}
+
+// edge classifies the walls crossed when moving from the centre of (x, y) to its neighbour
+func (pf *pathfinder) edge(x, y, dir int) edgeKind {
+	key := pf.index(x, y)*len(neighbourOffsets) + dir
+	if pf.edges[key] != edgeUnknown {
+		return pf.edges[key]
+	}
+
+	offset := neighbourOffsets[dir]
+	ax, ay := CellCenter(x, y)
+	bx, by := CellCenter(x+offset[0], y+offset[1])
+
+	kind := edgeOpen
+	for _, wall := range pf.walls {
+		if wall == nil {
+			continue
+		}
+		wallKind := wallMovement(wall)
+		if wallKind == edgeOpen || (wallKind == edgeDifficult && kind == edgeDifficult) {
+			continue
+		}
+		if !SegmentCrossesWall(ax, ay, bx, by, wall) {
+			continue
+		}
+		if wallKind == edgeBlocked {
+			kind = edgeBlocked
+			break
+		}
+		kind = edgeDifficult
+	}
+
+	pf.edges[key] = kind
+	return kind
+}
+
+// wallMovement classifies how a wall restricts movement
+// Closed and locked doors block; open doors allow movement; terrain walls are difficult terrain
+func wallMovement(wall *models.Wall) edgeKind {
+	if wall.Type == models.WallTypeDoor && wall.Door != nil {
+		if wall.Door.State == models.DoorStateOpen {
+			return edgeOpen
+		}
+		return edgeBlocked
+	}
+	if wall.Type == models.WallTypeTerrain || wall.IsDifficult() {
+		return edgeDifficult
+	}
+	if wall.IsBlocking() {
+		return edgeBlocked
+	}
+	return edgeOpen
+}
+
+// heuristic is the cost of the shortest unobstructed route, a lower bound for A*
+func (pf *pathfinder) heuristic(x, y, toX, toY, parity int) int {
+	dx, dy := x-toX, y-toY
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	long, short := dx, dy
+	if short > long {
+		long, short = short, long
+	}
+	// Every second diagonal costs an extra square
+	return (long + (short+parity)/2) * pf.cellSize
+}
+
+// result builds a PathResult from the cells visited along the route
+func (pf *pathfinder) result(cells, costs []int, difficult []bool) *PathResult {
+	r := &PathResult{
+		Path:      make([]models.Position, len(cells)),
+		Cost:      costs[len(costs)-1],
+		costs:     costs,
+		difficult: difficult,
+		occupied:  make([]bool, len(cells)),
+	}
+	for i, cell := range cells {
+		x, y := cell%pf.grid.Width, cell/pf.grid.Width
+		r.Path[i] = models.Position{X: x, Y: y}
+		r.occupied[i] = pf.footprintOccupied(x, y)
+		if difficult[i] {
+			r.DifficultCount++
+		}
+	}
+	return r
+}
+
+// index converts grid coordinates into a flat cell index
+func (pf *pathfinder) index(x, y int) int {
+	return y*pf.grid.Width + x
+}
+
+// node is an entry in the A* open set
+type node struct {
+	state    int
+	cost     int
+	priority int
+	order    int
+}
+
+// nodeQueue is a min-heap ordered by priority, then by insertion order for stable results
+type nodeQueue []*node
+
+func (q nodeQueue) Len() int { return len(q) }
+
+func (q nodeQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].order < q[j].order
+}
+
+func (q nodeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(*node)) }
+
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,279 @@
+// Package vision provides D&D 5e line of sight, light and senses rules for battle maps
+// 规则参考: PHB 第8章 - Vision and Light
+package vision
+
+import (
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+)
+
+// Sense names as stored in Traits.Senses
+const (
+	SenseSight      = "sight"
+	SenseDarkvision = "darkvision"
+	SenseBlindsight = "blindsight"
+	SenseTruesight  = "truesight"
+)
+
+// Viewer describes the creature that is looking and the senses it can use
+type Viewer struct {
+	Token      *models.Token
+	Darkvision int  // range in feet, 0 if none
+	Blindsight int  // range in feet, 0 if none
+	Truesight  int  // range in feet, 0 if none
+	Blinded    bool // blinded creatures rely on blindsight only
+}
+
+// NewViewer builds a viewer from a token and the character behind it
+// 规则参考: PHB 第8章 - Darkvision / Blindsight / Truesight, 附录A - Blinded
+func NewViewer(token *models.Token, character *models.Character, conditions []models.Condition) *Viewer {
+	viewer := &Viewer{Token: token}
+	if character != nil && character.Traits != nil {
+		viewer.Darkvision = character.Traits.Senses[SenseDarkvision]
+		viewer.Blindsight = character.Traits.Senses[SenseBlindsight]
+		viewer.Truesight = character.Traits.Senses[SenseTruesight]
+	}
+	for _, cond := range conditions {
+		if cond.Type == models.ConditionBlinded {
+			viewer.Blinded = true
+		}
+	}
+	return viewer
+}
+
+// Senses returns the special senses the viewer has, keyed by sense name
+func (v *Viewer) Senses() map[string]int {
+	senses := make(map[string]int)
+	if v.Darkvision > 0 {
+		senses[SenseDarkvision] = v.Darkvision
+	}
+	if v.Blindsight > 0 {
+		senses[SenseBlindsight] = v.Blindsight
+	}
+	if v.Truesight > 0 {
+		senses[SenseTruesight] = v.Truesight
+	}
+	return senses
+}
+
+// VisibleCell is a grid cell the viewer can perceive
+type VisibleCell struct {
+	X     int               `json:"x"`
+	Y     int               `json:"y"`
+	Light models.LightLevel `json:"light"` // actual light level in the cell
+	Sense string            `json:"sense"` // sense used to perceive the cell
+}
+
+// Sight is the outcome of checking whether a viewer perceives a cell or token
+type Sight struct {
+	Visible  bool              `json:"visible"`
+	Sense    string            `json:"sense,omitempty"`
+	Light    models.LightLevel `json:"light"`
+	Distance int               `json:"distance"`
+	Reason   string            `json:"reason,omitempty"` // why the target cannot be seen
+}
+
+// Field precomputes light levels for a battle map so that repeated checks stay cheap
+type Field struct {
+	battleMap *models.Map
+	cellSize  int
+	light     []models.LightLevel // lazily computed per cell, "" when unknown
+}
+
+// NewField prepares visibility checks for a battle map
+func NewField(battleMap *models.Map) *Field {
+	cellSize := 5
+	width, height := 0, 0
+	if battleMap.Grid != nil {
+		width, height = battleMap.Grid.Width, battleMap.Grid.Height
+		if battleMap.Grid.CellSize > 0 {
+			cellSize = battleMap.Grid.CellSize
+		}
+	}
+	return &Field{
+		battleMap: battleMap,
+		cellSize:  cellSize,
+		light:     make([]models.LightLevel, width*height),
+	}
+}
+
+// HasLineOfSight reports whether an unobstructed line joins the centres of two cells.
+// Sight-blocking walls and closed doors stop the line; limited walls (such as terrain
+// walls or closed doors with limited sense) only block once two of them are crossed.
+// 规则参考: PHB 第8章 - Vision and Light / DMG 第8章 - Tactical Maps
+func (f *Field) HasLineOfSight(fromX, fromY, toX, toY int) bool {
+	if fromX == toX && fromY == toY {
+		return true
+	}
+	ax, ay := movement.CellCenter(fromX, fromY)
+	bx, by := movement.CellCenter(toX, toY)
+
+	limited := 0
+	for _, wall := range f.battleMap.Walls {
+		sense := wallSense(wall)
+		if sense == 2 || !movement.SegmentCrossesWall(ax, ay, bx, by, wall) {
+			continue
+		}
+		if sense == 0 {
+			return false
+		}
+		limited++
+		if limited >= 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// LightAt returns the light level in a cell: the brightest of the ambient light and
+// every light source that reaches the cell with line of sight
+// 规则参考: PHB 第8章 - Light
+func (f *Field) LightAt(x, y int) models.LightLevel {
+	index := f.index(x, y)
+	if index >= 0 && f.light[index] != "" {
+		return f.light[index]
+	}
+
+	level := f.battleMap.GetAmbientLight()
+	for i := range f.battleMap.Lights {
+		if level == models.LightBright {
+			break
+		}
+		source := &f.battleMap.Lights[i]
+		distance := cellDistance(source.Position.X, source.Position.Y, x, y) * f.cellSize
+		lit := source.LevelAt(distance)
+		if level.Brighter(lit) == level {
+			continue // would not make the cell any brighter
+		}
+		if f.HasLineOfSight(source.Position.X, source.Position.Y, x, y) {
+			level = lit
+		}
+	}
+
+	if index >= 0 {
+		f.light[index] = level
+	}
+	return level
+}
+
+// SeeCell checks whether a viewer can perceive a cell.
+// Blindsight and truesight work regardless of light within their range; otherwise
+// the viewer needs bright or dim light, or darkvision to see in darkness.
+// 规则参考: PHB 第8章 - Vision and Light
+func (f *Field) SeeCell(viewer *Viewer, x, y int) Sight {
+	sight := Sight{
+		Light:    f.LightAt(x, y),
+		Distance: movement.DistanceToCell(viewer.Token, x, y, f.cellSize),
+	}
+
+	if !f.lineOfSightFromToken(viewer.Token, x, y) {
+		sight.Reason = "no line of sight"
+		return sight
+	}
+
+	switch {
+	case viewer.Truesight > 0 && sight.Distance <= viewer.Truesight:
+		sight.Sense = SenseTruesight
+	case viewer.Blindsight > 0 && sight.Distance <= viewer.Blindsight:
+		sight.Sense = SenseBlindsight
+	case viewer.Blinded:
+		sight.Reason = "blinded"
+	case sight.Light != models.LightDarkness:
+		sight.Sense = SenseSight
+	case viewer.Darkvision > 0 && sight.Distance <= viewer.Darkvision:
+		sight.Sense = SenseDarkvision
+	default:
+		sight.Reason = "darkness"
+	}
+
+	sight.Visible = sight.Sense != ""
+	return sight
+}
+
+// SeeToken checks whether a viewer can perceive any square of another token
+func (f *Field) SeeToken(viewer *Viewer, target *models.Token) Sight {
+	var best Sight
+	squares := movement.GetTokenOccupiedSquares(target)
+	if len(squares) == 0 {
+		squares = []models.Position{target.Position}
+	}
+
+	for i, pos := range squares {
+		sight := f.SeeCell(viewer, pos.X, pos.Y)
+		if sight.Visible {
+			sight.Distance = movement.TokenDistance(viewer.Token, target, f.cellSize)
+			return sight
+		}
+		if i == 0 {
+			best = sight
+		}
+	}
+	best.Distance = movement.TokenDistance(viewer.Token, target, f.cellSize)
+	return best
+}
+
+// VisibleCells lists every cell on the map the viewer can perceive
+func (f *Field) VisibleCells(viewer *Viewer) []VisibleCell {
+	cells := make([]VisibleCell, 0)
+	grid := f.battleMap.Grid
+	if grid == nil {
+		return cells
+	}
+
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			sight := f.SeeCell(viewer, x, y)
+			if sight.Visible {
+				cells = append(cells, VisibleCell{X: x, Y: y, Light: sight.Light, Sense: sight.Sense})
+			}
+		}
+	}
+	return cells
+}
+
+// lineOfSightFromToken checks line of sight from any square the token occupies
+func (f *Field) lineOfSightFromToken(token *models.Token, x, y int) bool {
+	squares := movement.GetTokenOccupiedSquares(token)
+	if len(squares) == 0 {
+		squares = []models.Position{token.Position}
+	}
+	for _, pos := range squares {
+		if f.HasLineOfSight(pos.X, pos.Y, x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// index converts grid coordinates into a flat cell index, -1 when outside the grid
+func (f *Field) index(x, y int) int {
+	grid := f.battleMap.Grid
+	if grid == nil || x < 0 || y < 0 || x >= grid.Width || y >= grid.Height {
+		return -1
+	}
+	return y*grid.Width + x
+}
+
+// wallSense returns how much a wall restricts sight: 0 blocks, 1 limited, 2 allows.
+// Open doors never block sight.
+func wallSense(wall *models.Wall) int {
+	if wall == nil || wall.IsOpen() {
+		return 2
+	}
+	return wall.Sense
+}
+
+// cellDistance returns the grid distance in cells between two cells
+func cellDistance(x1, y1, x2, y2 int) int {
+	dx, dy := x1-x2, y1-y2
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if dy > dx {
+		return dy
+	}
+	return dx
+}
@@ -0,0 +1,315 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// defaultSpeed 未知速度时使用的默认步行速度（英尺）
+const defaultSpeed = 30
+
+// TurnActionRequest 回合动作请求（疾走、闪避、撤离）
+type TurnActionRequest struct {
+	CombatID       string `json:"combat_id"`
+	CharacterID    string `json:"character_id"`
+	UseBonusAction bool   `json:"use_bonus_action"` // 以附赠动作执行（如游荡者的灵巧动作）
+}
+
+// HideRequest 躲藏请求
+type HideRequest struct {
+	CombatID       string `json:"combat_id"`
+	CharacterID    string `json:"character_id"`
+	DC             int    `json:"dc"`               // 对抗的被动察觉（可选，0 表示由 DM 裁定）
+	UseBonusAction bool   `json:"use_bonus_action"` // 以附赠动作执行
+}
+
+// HelpRequest 协助请求
+type HelpRequest struct {
+	CombatID    string `json:"combat_id"`
+	CharacterID string `json:"character_id"`
+	AllyID      string `json:"ally_id"`   // 获得优势的盟友
+	TargetID    string `json:"target_id"` // 协助攻击的目标（可选）
+}
+
+// ReadyRequest 预备动作请求
+type ReadyRequest struct {
+	CombatID    string `json:"combat_id"`
+	CharacterID string `json:"character_id"`
+	Trigger     string `json:"trigger"` // 触发条件
+	Action      string `json:"action"`  // 触发时执行的动作
+}
+
+// TurnActionResponse 回合动作响应
+type TurnActionResponse struct {
+	Combat  *models.Combat       `json:"combat"`
+	Actions *models.ActionBudget `json:"actions"`
+	Check   *models.CheckResult  `json:"check,omitempty"` // 躲藏时的隐匿检定
+	Hidden  bool                 `json:"hidden,omitempty"`
+}
+
+// Dash 疾走
+// 规则参考: PHB 第9章 - Dash
+func (s *CombatService) Dash(ctx context.Context, req *TurnActionRequest) (*TurnActionResponse, error) {
+	combat, participant, character, err := s.beginTurnAction(ctx, req.CombatID, req.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.spendAction(participant, character, bonusOrAction(req.UseBonusAction)); err != nil {
+		return nil, err
+	}
+	participant.Actions.Dash()
+
+	combat.AddLogEntry(req.CharacterID, "dash", "", fmt.Sprintf("dashed, %d feet of movement remaining", participant.Actions.Movement))
+	return s.finishTurnAction(ctx, combat, participant, nil)
+}
+
+// Dodge 闪避
+// 直到下个回合开始前，对其发动的攻击具有劣势，敏捷豁免具有优势
+// 规则参考: PHB 第9章 - Dodge
+func (s *CombatService) Dodge(ctx context.Context, req *TurnActionRequest) (*TurnActionResponse, error) {
+	combat, participant, character, err := s.beginTurnAction(ctx, req.CombatID, req.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.spendAction(participant, character, models.ActionTypeAction); err != nil {
+		return nil, err
+	}
+	participant.Actions.Dodging = true
+
+	combat.AddLogEntry(req.CharacterID, "dodge", "", "took the Dodge action")
+	return s.finishTurnAction(ctx, combat, participant, nil)
+}
+
+// Disengage 撤离
+// 本回合剩余时间内的移动不会引发借机攻击
+// 规则参考: PHB 第9章 - Disengage
+func (s *CombatService) Disengage(ctx context.Context, req *TurnActionRequest) (*TurnActionResponse, error) {
+	combat, participant, character, err := s.beginTurnAction(ctx, req.CombatID, req.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.spendAction(participant, character, bonusOrAction(req.UseBonusAction)); err != nil {
+		return nil, err
+	}
+	participant.Actions.Disengaged = true
+
+	combat.AddLogEntry(req.CharacterID, "disengage", "", "took the Disengage action")
+	return s.finishTurnAction(ctx, combat, participant, nil)
+}
+
+// Help 协助
+// 盟友在协助者下个回合开始前对目标的下一次攻击具有优势
+// 规则参考: PHB 第9章 - Help
+func (s *CombatService) Help(ctx context.Context, req *HelpRequest) (*TurnActionResponse, error) {
+	if req.AllyID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "ally ID is required")
+	}
+	if req.AllyID == req.CharacterID {
+		return nil, NewServiceError(ErrCodeInvalidInput, "a character cannot help themselves")
+	}
+
+	combat, participant, character, err := s.beginTurnAction(ctx, req.CombatID, req.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+	if combat.GetParticipantByCharacterID(req.AllyID) == nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "ally is not in combat")
+	}
+	if req.TargetID != "" && combat.GetParticipantByCharacterID(req.TargetID) == nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "target is not in combat")
+	}
+
+	if err := s.spendAction(participant, character, models.ActionTypeAction); err != nil {
+		return nil, err
+	}
+	participant.Actions.HelpingID = req.AllyID
+	participant.Actions.HelpAgainstID = req.TargetID
+
+	combat.AddLogEntry(req.CharacterID, "help", req.AllyID, "took the Help action")
+	return s.finishTurnAction(ctx, combat, participant, nil)
+}
+
+// Hide 躲藏
+// 进行敏捷（隐匿）检定，结果与敌人的被动察觉对抗
+// 规则参考: PHB 第9章 - Hide / 第7章 - Hiding
+func (s *CombatService) Hide(ctx context.Context, req *HideRequest) (*TurnActionResponse, error) {
+	combat, participant, character, err := s.beginTurnAction(ctx, req.CombatID, req.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.spendAction(participant, character, bonusOrAction(req.UseBonusAction)); err != nil {
+		return nil, err
+	}
+
+	modifier := 0
+	if s.diceService != nil {
+		modifier = s.diceService.calculateCheckModifier(character, "dexterity", "stealth")
+	}
+	check := models.NewCheckResult(s.roller.RollD20(modifier), "dexterity")
+	check.SetSkill("stealth")
+	if req.DC > 0 {
+		check.SetDC(req.DC)
+	} else {
+		check.Success = true
+	}
+
+	participant.Hidden = check.IsSuccess()
+	participant.StealthRoll = check.DiceResult.Total
+
+	result := fmt.Sprintf("stealth %d", check.DiceResult.Total)
+	if participant.Hidden {
+		result += ", hidden"
+	} else {
+		result += ", failed to hide"
+	}
+	combat.AddLogEntry(req.CharacterID, "hide", "", result)
+
+	resp, err := s.finishTurnAction(ctx, combat, participant, check)
+	if err != nil {
+		return nil, err
+	}
+	resp.Hidden = participant.Hidden
+	return resp, nil
+}
+
+// Ready 预备动作
+// 消耗动作，触发时使用反应执行
+// 规则参考: PHB 第9章 - Ready
+func (s *CombatService) Ready(ctx context.Context, req *ReadyRequest) (*TurnActionResponse, error) {
+	if req.Trigger == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "trigger is required")
+	}
+	if req.Action == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "action is required")
+	}
+
+	combat, participant, character, err := s.beginTurnAction(ctx, req.CombatID, req.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.spendAction(participant, character, models.ActionTypeAction); err != nil {
+		return nil, err
+	}
+	participant.Actions.Readied = &models.ReadiedAction{
+		Trigger: req.Trigger,
+		Action:  req.Action,
+	}
+
+	combat.AddLogEntry(req.CharacterID, "ready", "", fmt.Sprintf("readied %q when %q", req.Action, req.Trigger))
+	return s.finishTurnAction(ctx, combat, participant, nil)
+}
+
+// beginTurnAction 验证战斗状态和行动者回合，并获取行动者
+func (s *CombatService) beginTurnAction(ctx context.Context, combatID, characterID string) (*models.Combat, *models.Participant, *models.Character, error) {
+	if combatID == "" {
+		return nil, nil, nil, NewServiceError(ErrCodeInvalidInput, "combat ID is required")
+	}
+	if characterID == "" {
+		return nil, nil, nil, NewServiceError(ErrCodeInvalidInput, "character ID is required")
+	}
+
+	combat, err := s.combatStore.Get(ctx, combatID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get combat: %w", err)
+	}
+	if !combat.IsActive() {
+		return nil, nil, nil, NewServiceError(ErrCodeInvalidState, "combat is not active")
+	}
+
+	if err := authorizeTurn(ctx, combat, characterID); err != nil {
+		return nil, nil, nil, err
+	}
+	participant := combat.GetCurrentParticipant()
+	if participant == nil || participant.CharacterID != characterID {
+		return nil, nil, nil, NewServiceError(ErrCodeInvalidState, "not this character's turn")
+	}
+
+	character, err := s.characterStore.Get(ctx, characterID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get character: %w", err)
+	}
+	if err := authorizeActor(ctx, character); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return combat, participant, character, nil
+}
+
+// finishTurnAction 保存战斗状态并构造响应
+func (s *CombatService) finishTurnAction(ctx context.Context, combat *models.Combat, participant *models.Participant, check *models.CheckResult) (*TurnActionResponse, error) {
+	if err := s.combatStore.Update(ctx, combat); err != nil {
+		return nil, fmt.Errorf("failed to update combat: %w", err)
+	}
+	return &TurnActionResponse{
+		Combat:  combat,
+		Actions: participant.Actions,
+		Check:   check,
+	}, nil
+}
+
+// checkAction 检查参战者是否还有指定类型的行动可用
+func (s *CombatService) checkAction(participant *models.Participant, character *models.Character, actionType models.ActionType) error {
+	if participant.EnsureActions(characterSpeed(character)).Has(actionType) {
+		return nil
+	}
+	name := participant.CharacterID
+	if character != nil && character.Name != "" {
+		name = character.Name
+	}
+	return NewServiceError(ErrCodeInvalidState,
+		fmt.Sprintf("%s has already used their %s this turn", name, strings.ReplaceAll(string(actionType), "_", " ")))
+}
+
+// spendAction 检查并消耗参战者的指定类型行动
+func (s *CombatService) spendAction(participant *models.Participant, character *models.Character, actionType models.ActionType) error {
+	if err := s.checkAction(participant, character, actionType); err != nil {
+		return err
+	}
+	participant.Actions.Use(actionType)
+	return nil
+}
+
+// bonusOrAction 根据请求选择附赠动作或动作
+func bonusOrAction(useBonusAction bool) models.ActionType {
+	if useBonusAction {
+		return models.ActionTypeBonusAction
+	}
+	return models.ActionTypeAction
+}
+
+// spellActionType 根据施法时间确定法术消耗的行动类型
+// 规则参考: PHB 第10章 - Casting Time
+func spellActionType(spell *models.Spell) models.ActionType {
+	castingTime := strings.ToLower(spell.CastingTime)
+	switch {
+	case strings.Contains(castingTime, "bonus"):
+		return models.ActionTypeBonusAction
+	case strings.Contains(castingTime, "reaction"):
+		return models.ActionTypeReaction
+	default:
+		return models.ActionTypeAction
+	}
+}
+
+// characterSpeed 获取角色步行速度（英尺）
+func characterSpeed(character *models.Character) int {
+	if character == nil {
+		return defaultSpeed
+	}
+	if character.SpeedDetail != nil && character.SpeedDetail.Walk > 0 {
+		return character.SpeedDetail.Walk
+	}
+	if character.Speed > 0 {
+		return character.Speed
+	}
+	return defaultSpeed
+}
@@ -0,0 +1,267 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/archive"
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+)
+
+// MessageStoreForArchive defines the interface for message data operations needed by archive service
+type MessageStoreForArchive interface {
+	ListByCampaign(ctx context.Context, campaignID string, limit int) ([]*models.Message, error)
+	Create(ctx context.Context, message *models.Message) error
+}
+
+// ArchiveService exports campaigns as portable archives and imports them as new campaigns
+type ArchiveService struct {
+	records    *campaignRecords
+	transactor store.Transactor
+	now        func() time.Time
+}
+
+// NewArchiveService creates a new archive service.
+// Imports write all records inside one transaction of the given transactor.
+func NewArchiveService(
+	campaignStore store.CampaignStore,
+	gameStateStore store.GameStateStore,
+	characterStore store.CharacterStore,
+	mapStore store.MapStore,
+	combatStore store.CombatStore,
+	messageStore MessageStoreForArchive,
+	transactor store.Transactor,
+) *ArchiveService {
+	return &ArchiveService{
+		records: &campaignRecords{
+			campaignStore:  campaignStore,
+			gameStateStore: gameStateStore,
+			characterStore: characterStore,
+			mapStore:       mapStore,
+			combatStore:    combatStore,
+			messageStore:   messageStore,
+		},
+		transactor: transactor,
+		now:        time.Now,
+	}
+}
+
+// ExportCampaignResponse 战役导出响应
+type ExportCampaignResponse struct {
+	Archive  []byte            `json:"-"`        // zip 归档
+	Manifest *archive.Manifest `json:"manifest"` // 归档清单
+}
+
+// ExportCampaign writes a campaign and all its records to an archive
+func (s *ArchiveService) ExportCampaign(ctx context.Context, campaignID string) (*ExportCampaignResponse, error) {
+	if campaignID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+
+	campaign, err := s.records.campaignStore.Get(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	if err := authorizeCampaignDM(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	contents, err := s.records.load(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if contents.Combats, err = s.records.combatStore.GetByCampaign(ctx, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to list combats: %w", err)
+	}
+	if contents.Messages, err = s.records.messageStore.ListByCampaign(ctx, campaignID, 0); err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	var buf bytes.Buffer
+	manifest, err := archive.Write(&buf, contents, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return &ExportCampaignResponse{
+		Archive:  buf.Bytes(),
+		Manifest: manifest,
+	}, nil
+}
+
+// ImportCampaignRequest 战役导入请求
+type ImportCampaignRequest struct {
+	Archive []byte `json:"-"`     // zip 归档
+	DMID    string `json:"dm_id"` // 新战役的 DM（可选，默认为归档中的 DM；DM 调用时默认为自己）
+	Name    string `json:"name"`  // 新战役名称（可选，默认为归档中的名称）
+}
+
+// ImportCampaignResponse 战役导入响应
+type ImportCampaignResponse struct {
+	Campaign *models.Campaign  `json:"campaign"` // 新建的战役
+	Manifest *archive.Manifest `json:"manifest"` // 归档清单
+	IDs      map[string]string `json:"ids"`      // 旧ID到新ID的映射
+}
+
+// ImportCampaign creates a new campaign from an archive.
+// Every record gets a new ID, so the same archive can be imported any number of times.
+func (s *ArchiveService) ImportCampaign(ctx context.Context, req *ImportCampaignRequest) (*ImportCampaignResponse, error) {
+	if len(req.Archive) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "archive is required")
+	}
+
+	contents, manifest, err := archive.Read(req.Archive)
+	if err != nil {
+		if errors.Is(err, archive.ErrInvalidArchive) {
+			return nil, NewServiceError(ErrCodeInvalidInput, err.Error())
+		}
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	// A DM imports campaigns they run, like creating one
+	dmID := req.DMID
+	if principal, ok := auth.FromContext(ctx); ok && principal.Role == auth.RoleDM {
+		if dmID == "" {
+			dmID = principal.UserID
+		}
+		if dmID != principal.UserID {
+			return nil, NewServiceError(ErrCodeForbidden, "a DM may only import campaigns they run")
+		}
+	}
+
+	ids, err := archive.RemapIDs(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remap IDs: %w", err)
+	}
+
+	campaign := contents.Campaign
+	if dmID != "" {
+		campaign.DMID = dmID
+	}
+	if req.Name != "" {
+		campaign.Name = req.Name
+	}
+	campaign.DeletedAt = nil
+	if err := campaign.Validate(); err != nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("invalid campaign: %v", err))
+	}
+
+	err = inTx(ctx, s.transactor, func(ctx context.Context) error {
+		return s.records.create(ctx, contents)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportCampaignResponse{
+		Campaign: campaign,
+		Manifest: manifest,
+		IDs:      ids,
+	}, nil
+}
+
+// inTx runs fn in a transaction when a transactor is configured
+func inTx(ctx context.Context, transactor store.Transactor, fn func(ctx context.Context) error) error {
+	if transactor == nil {
+		return fn(ctx)
+	}
+	return transactor.InTx(ctx, fn)
+}
+
+// campaignRecords reads and writes the records that make up a campaign
+type campaignRecords struct {
+	campaignStore  CampaignStore
+	gameStateStore GameStateStore
+	characterStore store.CharacterStore
+	mapStore       store.MapStore
+	combatStore    store.CombatStore
+	messageStore   MessageStoreForArchive
+}
+
+// load reads a campaign's game state, characters and maps
+func (r *campaignRecords) load(ctx context.Context, campaign *models.Campaign) (*archive.Contents, error) {
+	var err error
+	contents := &archive.Contents{Campaign: campaign}
+	if contents.GameState, err = r.gameStateStore.Get(ctx, campaign.ID); err != nil {
+		return nil, fmt.Errorf("failed to get game state: %w", err)
+	}
+	if contents.Characters, err = r.characterStore.List(ctx, &store.CharacterFilter{CampaignID: campaign.ID}); err != nil {
+		return nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+	if contents.Maps, err = r.mapStore.GetByCampaign(ctx, campaign.ID); err != nil {
+		return nil, fmt.Errorf("failed to list maps: %w", err)
+	}
+	return contents, nil
+}
+
+// create stores remapped archive contents as a new campaign
+func (r *campaignRecords) create(ctx context.Context, contents *archive.Contents) error {
+	if err := r.campaignStore.Create(ctx, contents.Campaign); err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+	for _, character := range contents.Characters {
+		if err := r.characterStore.Create(ctx, character); err != nil {
+			return fmt.Errorf("failed to create character %s: %w", character.Name, err)
+		}
+	}
+	// 战斗地图引用父地图，先创建父地图
+	for _, gameMap := range parentsFirst(contents.Maps) {
+		if err := r.mapStore.Create(ctx, gameMap); err != nil {
+			return fmt.Errorf("failed to create map %s: %w", gameMap.Name, err)
+		}
+	}
+	for _, combat := range contents.Combats {
+		if err := r.combatStore.Create(ctx, combat); err != nil {
+			return fmt.Errorf("failed to create combat: %w", err)
+		}
+	}
+	// 游戏状态引用当前地图和战斗，最后创建
+	gameState := contents.GameState
+	if gameState == nil {
+		gameState = models.NewGameState(contents.Campaign.ID)
+	}
+	if err := r.gameStateStore.Create(ctx, gameState); err != nil {
+		return fmt.Errorf("failed to create game state: %w", err)
+	}
+	for _, message := range contents.Messages {
+		if err := r.messageStore.Create(ctx, message); err != nil {
+			return fmt.Errorf("failed to create message: %w", err)
+		}
+	}
+	return nil
+}
+
+// parentsFirst orders maps so each map comes after its parent.
+// Parents missing from the archive are cleared.
+func parentsFirst(maps []*models.Map) []*models.Map {
+	byID := make(map[string]*models.Map, len(maps))
+	for _, gameMap := range maps {
+		byID[gameMap.ID] = gameMap
+	}
+
+	ordered := make([]*models.Map, 0, len(maps))
+	placed := make(map[string]bool, len(maps))
+	var place func(gameMap *models.Map)
+	place = func(gameMap *models.Map) {
+		if placed[gameMap.ID] {
+			return
+		}
+		placed[gameMap.ID] = true
+		if parent, ok := byID[gameMap.ParentID]; ok {
+			place(parent)
+		} else {
+			gameMap.ParentID = ""
+		}
+		ordered = append(ordered, gameMap)
+	}
+	for _, gameMap := range maps {
+		place(gameMap)
+	}
+	return ordered
+}
@@ -0,0 +1,375 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+)
+
+// maxUndoActions is how far back in a campaign's active audit entries an undo may reach
+const maxUndoActions = 50
+
+// AuditService records state-mutating tool calls and undoes them
+type AuditService struct {
+	auditStore     store.AuditStore
+	transactor     store.Transactor
+	campaignStore  store.CampaignStore
+	characterStore store.CharacterStore
+	combatStore    store.CombatStore
+	mapStore       store.MapStore
+	gameStateStore store.GameStateStore
+	now            func() time.Time
+}
+
+// NewAuditService creates a new audit service.
+// Undo restores records through the given stores inside one transaction.
+func NewAuditService(
+	auditStore store.AuditStore,
+	transactor store.Transactor,
+	campaignStore store.CampaignStore,
+	characterStore store.CharacterStore,
+	combatStore store.CombatStore,
+	mapStore store.MapStore,
+	gameStateStore store.GameStateStore,
+) *AuditService {
+	return &AuditService{
+		auditStore:     auditStore,
+		transactor:     transactor,
+		campaignStore:  campaignStore,
+		characterStore: characterStore,
+		combatStore:    combatStore,
+		mapStore:       mapStore,
+		gameStateStore: gameStateStore,
+		now:            time.Now,
+	}
+}
+
+// Record stores an audit entry for a tool call from the revisions it made.
+// It returns nil when the call changed nothing or no campaign can be attributed to it.
+func (s *AuditService) Record(ctx context.Context, tool string, arguments json.RawMessage, revisions []store.Revision) (*models.AuditEntry, error) {
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+
+	entry := &models.AuditEntry{
+		Tool:      tool,
+		Arguments: make(map[string]interface{}),
+		Changes:   make([]models.AuditChange, 0, len(revisions)),
+		CreatedAt: s.now(),
+	}
+	if len(arguments) > 0 {
+		// 参数已通过 schema 校验，解析失败时只记录空参数
+		_ = json.Unmarshal(arguments, &entry.Arguments)
+	}
+	if principal, ok := auth.FromContext(ctx); ok {
+		entry.ActorID = principal.UserID
+		entry.ActorRole = string(principal.Role)
+	}
+
+	for _, rev := range revisions {
+		if entry.CampaignID == "" {
+			entry.CampaignID = rev.CampaignID
+		}
+		entry.Changes = append(entry.Changes, models.AuditChange{
+			Entity:   string(rev.Entity),
+			EntityID: rev.ID,
+			Before:   rev.Before,
+			After:    rev.After,
+		})
+	}
+	if entry.CampaignID == "" {
+		if id, ok := entry.Arguments["campaign_id"].(string); ok {
+			entry.CampaignID = id
+		}
+	}
+	if entry.CampaignID == "" {
+		return nil, nil
+	}
+
+	if err := s.auditStore.Append(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return entry, nil
+}
+
+// ListActionsRequest represents a request to list a campaign's recorded actions
+type ListActionsRequest struct {
+	CampaignID    string `json:"campaign_id" jsonschema:"required" description:"The campaign ID"`                                         // 战役ID
+	Limit         int    `json:"limit" jsonschema:"minimum=1,maximum=100" description:"Maximum number of actions to return (default 20)"` // 返回数量
+	IncludeUndone bool   `json:"include_undone" description:"Also list actions that have already been undone"`                            // 是否包含已撤销的操作
+}
+
+// ListActions lists a campaign's recorded actions, newest first
+func (s *AuditService) ListActions(ctx context.Context, req *ListActionsRequest) ([]*models.AuditEntry, error) {
+	if err := s.authorize(ctx, req.CampaignID); err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	entries, err := s.auditStore.List(ctx, &store.AuditFilter{
+		CampaignID:    req.CampaignID,
+		IncludeUndone: req.IncludeUndone,
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actions: %w", err)
+	}
+	return entries, nil
+}
+
+// UndoActionRequest represents a request to undo recorded actions
+type UndoActionRequest struct {
+	CampaignID string `json:"campaign_id" jsonschema:"required" description:"The campaign ID"`                                                                             // 战役ID
+	Count      int    `json:"count" jsonschema:"minimum=1,maximum=50" description:"Number of most recent actions to undo (default 1)"`                                     // 撤销最近的操作数
+	ActionID   int64  `json:"action_id" jsonschema:"minimum=1" description:"Undo this action only, from list_actions. Refused if a later action changed the same records"` // 指定撤销的操作
+}
+
+// UndoActionResponse represents the result of an undo
+type UndoActionResponse struct {
+	Undone []*models.AuditEntry `json:"undone"` // 已撤销的操作，从新到旧
+}
+
+// UndoActions reverts the most recent actions of a campaign, or one given action, in one transaction.
+// It refuses when a later action changed the same records, or when a record no longer
+// matches the state the action left it in, e.g. because it was edited outside a tool call.
+func (s *AuditService) UndoActions(ctx context.Context, req *UndoActionRequest) (*UndoActionResponse, error) {
+	if err := s.authorize(ctx, req.CampaignID); err != nil {
+		return nil, err
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = 1
+	}
+	if req.ActionID != 0 && req.Count > 1 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "count cannot be combined with action_id")
+	}
+
+	resp := &UndoActionResponse{}
+	err := s.transactor.InTx(ctx, func(ctx context.Context) error {
+		active, err := s.auditStore.List(ctx, &store.AuditFilter{CampaignID: req.CampaignID, Limit: maxUndoActions})
+		if err != nil {
+			return fmt.Errorf("failed to list actions: %w", err)
+		}
+
+		targets, err := selectUndoTargets(active, req.ActionID, count)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkCurrentState(ctx, targets); err != nil {
+			return err
+		}
+
+		// 从新到旧逐条恢复，同一操作内的修改倒序恢复
+		ids := make([]int64, 0, len(targets))
+		for _, entry := range targets {
+			for i := len(entry.Changes) - 1; i >= 0; i-- {
+				if err := s.restore(ctx, &entry.Changes[i]); err != nil {
+					return fmt.Errorf("failed to undo action #%d (%s): %w", entry.ID, entry.Tool, err)
+				}
+			}
+			ids = append(ids, entry.ID)
+		}
+
+		undoneAt := s.now()
+		if err := s.auditStore.MarkUndone(ctx, ids, undoneAt); err != nil {
+			return NewServiceError(ErrCodeConflict, fmt.Sprintf("actions were undone concurrently: %v", err))
+		}
+		for _, entry := range targets {
+			entry.UndoneAt = &undoneAt
+		}
+		resp.Undone = targets
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// authorize 检查战役存在且调用者是其 DM
+func (s *AuditService) authorize(ctx context.Context, campaignID string) error {
+	if campaignID == "" {
+		return NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+	campaign, err := s.campaignStore.Get(ctx, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return authorizeCampaignDM(ctx, campaign)
+}
+
+// selectUndoTargets 从未撤销的操作（从新到旧）中选出要撤销的操作
+// 指定 actionID 时，之后的操作不能修改过相同的记录
+func selectUndoTargets(active []*models.AuditEntry, actionID int64, count int) ([]*models.AuditEntry, error) {
+	if len(active) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidState, "there are no actions to undo")
+	}
+
+	if actionID == 0 {
+		if count > len(active) {
+			return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("only %d actions can be undone", len(active)))
+		}
+		return active[:count], nil
+	}
+
+	for i, entry := range active {
+		if entry.ID != actionID {
+			continue
+		}
+		touched := changedRecords(entry)
+		for _, later := range active[:i] {
+			for _, change := range later.Changes {
+				if touched[recordKey(change)] {
+					return nil, NewServiceError(ErrCodeConflict, fmt.Sprintf(
+						"action #%d (%s) changed %s %s after action #%d; undo it first",
+						later.ID, later.Tool, change.Entity, change.EntityID, actionID))
+				}
+			}
+		}
+		return []*models.AuditEntry{entry}, nil
+	}
+	return nil, NewServiceError(ErrCodeNotFound, fmt.Sprintf("action #%d not found or already undone", actionID))
+}
+
+// checkCurrentState 检查每条记录仍处于最后一个待撤销操作留下的状态
+func (s *AuditService) checkCurrentState(ctx context.Context, targets []*models.AuditEntry) error {
+	checked := make(map[string]bool)
+	for _, entry := range targets {
+		for _, change := range entry.Changes {
+			key := recordKey(change)
+			if checked[key] {
+				continue
+			}
+			checked[key] = true
+
+			current, err := s.load(ctx, change.Entity, change.EntityID)
+			if err != nil {
+				return err
+			}
+			if !models.SameJSON(current, change.After) {
+				return NewServiceError(ErrCodeConflict, fmt.Sprintf(
+					"%s %s changed after action #%d (%s); it can no longer be undone",
+					change.Entity, change.EntityID, entry.ID, entry.Tool))
+			}
+		}
+	}
+	return nil
+}
+
+// load 读取记录的当前状态，记录不存在时返回 nil
+func (s *AuditService) load(ctx context.Context, entity, id string) (json.RawMessage, error) {
+	var (
+		record interface{}
+		err    error
+	)
+	switch store.Entity(entity) {
+	case store.EntityCharacter:
+		record, err = s.characterStore.Get(ctx, id)
+	case store.EntityCombat:
+		record, err = s.combatStore.Get(ctx, id)
+	case store.EntityMap:
+		record, err = s.mapStore.Get(ctx, id)
+	case store.EntityGameState:
+		record, err = s.gameStateStore.Get(ctx, id)
+	default:
+		return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("cannot undo changes to %s", entity))
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", entity, id, err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", entity, err)
+	}
+	return data, nil
+}
+
+// restore 将记录恢复到操作前的状态
+// 操作创建的记录被删除，删除的记录被重新创建
+func (s *AuditService) restore(ctx context.Context, change *models.AuditChange) error {
+	switch store.Entity(change.Entity) {
+	case store.EntityCharacter:
+		if change.Created() {
+			return s.characterStore.Delete(ctx, change.EntityID)
+		}
+		var character models.Character
+		if err := json.Unmarshal(change.Before, &character); err != nil {
+			return fmt.Errorf("failed to unmarshal character: %w", err)
+		}
+		if change.Deleted() {
+			return s.characterStore.Create(ctx, &character)
+		}
+		return s.characterStore.Update(ctx, &character)
+
+	case store.EntityCombat:
+		if change.Created() {
+			return s.combatStore.Delete(ctx, change.EntityID)
+		}
+		var combat models.Combat
+		if err := json.Unmarshal(change.Before, &combat); err != nil {
+			return fmt.Errorf("failed to unmarshal combat: %w", err)
+		}
+		if change.Deleted() {
+			return s.combatStore.Create(ctx, &combat)
+		}
+		return s.combatStore.Update(ctx, &combat)
+
+	case store.EntityMap:
+		if change.Created() {
+			return s.mapStore.Delete(ctx, change.EntityID)
+		}
+		var gameMap models.Map
+		if err := json.Unmarshal(change.Before, &gameMap); err != nil {
+			return fmt.Errorf("failed to unmarshal map: %w", err)
+		}
+		if change.Deleted() {
+			return s.mapStore.Create(ctx, &gameMap)
+		}
+		return s.mapStore.Update(ctx, &gameMap)
+
+	case store.EntityGameState:
+		if change.Created() {
+			return s.gameStateStore.Delete(ctx, change.EntityID)
+		}
+		var gameState models.GameState
+		if err := json.Unmarshal(change.Before, &gameState); err != nil {
+			return fmt.Errorf("failed to unmarshal game state: %w", err)
+		}
+		if change.Deleted() {
+			return s.gameStateStore.Create(ctx, &gameState)
+		}
+		return s.gameStateStore.Update(ctx, &gameState)
+	}
+	return NewServiceError(ErrCodeInvalidState, fmt.Sprintf("cannot undo changes to %s", change.Entity))
+}
+
+// changedRecords 返回操作修改过的记录
+func changedRecords(entry *models.AuditEntry) map[string]bool {
+	records := make(map[string]bool, len(entry.Changes))
+	for _, change := range entry.Changes {
+		records[recordKey(change)] = true
+	}
+	return records
+}
+
+// recordKey 记录的唯一标识
+func recordKey(change models.AuditChange) string {
+	return change.Entity + "/" + change.EntityID
+}
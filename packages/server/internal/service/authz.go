@@ -0,0 +1,306 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+)
+
+// 访问控制
+// 请求上下文中没有主体（stdio、未启用认证）时视为受信任调用，不做限制。
+// DM 只在自己主持的战役（Campaign.DMID 为其用户 ID）中拥有 DM 权限，系统主体视为所有战役的 DM，
+// 见 auth.RunsCampaign。
+
+// authorizeActor 检查调用者能否以角色身份行动
+// 角色所属战役的 DM 不受限；玩家只能操作自己的玩家角色；观察者只读
+func authorizeActor(ctx context.Context, character *models.Character) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || auth.RunsCampaign(ctx, character.CampaignID) {
+		return nil
+	}
+	if principal.Role == auth.RolePlayer && !character.IsNPC && character.PlayerID == principal.UserID {
+		return nil
+	}
+	return NewServiceError(ErrCodeForbidden, fmt.Sprintf("%s %s may not act as %s", principal.Role, principal.UserID, character.Name))
+}
+
+// authorizeCampaignDM 检查调用者是否为战役的 DM
+// 系统主体视为所有战役的 DM
+func authorizeCampaignDM(ctx context.Context, campaign *models.Campaign) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.Role == auth.RoleSystem {
+		return nil
+	}
+	if principal.Role == auth.RoleDM && campaign.DMID == principal.UserID {
+		return nil
+	}
+	return NewServiceError(ErrCodeForbidden, fmt.Sprintf("only the DM of campaign %s may do this", campaign.Name))
+}
+
+// authorizeCreateCharacter 检查调用者能否创建角色
+// 战役的 DM 不受限；玩家只能为自己创建玩家角色，未指定 player_id 时默认为自己
+func authorizeCreateCharacter(ctx context.Context, req *CreateCharacterRequest) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || auth.RunsCampaign(ctx, req.CampaignID) {
+		return nil
+	}
+	if principal.Role != auth.RolePlayer || req.IsNPC {
+		return NewServiceError(ErrCodeForbidden, "only the DM of the campaign may create NPCs")
+	}
+	if req.PlayerID == "" {
+		req.PlayerID = principal.UserID
+	}
+	if req.PlayerID != principal.UserID {
+		return NewServiceError(ErrCodeForbidden, "players may only create their own characters")
+	}
+	return nil
+}
+
+// authorizeTurn 检查玩家是否在自己的回合行动，战役的 DM 由各行动自行检查回合
+// 规则参考: PHB 第9章 - Combat Step by Step
+func authorizeTurn(ctx context.Context, combat *models.Combat, characterID string) error {
+	if combat == nil || !combat.IsActive() || !redacted(ctx, combat.CampaignID) {
+		return nil
+	}
+	current := combat.GetCurrentParticipant()
+	if current == nil || current.CharacterID != characterID {
+		return NewServiceError(ErrCodeForbidden, "players may only act on their own turn")
+	}
+	return nil
+}
+
+// redacted 判断调用者在战役中是否只能看到玩家视角的数据
+func redacted(ctx context.Context, campaignID string) bool {
+	return !auth.RunsCampaign(ctx, campaignID)
+}
+
+// RedactCharacter 返回调用者可见的角色数据
+// 玩家和观察者只能看到 NPC 的外观信息（名称、种族、图片、状态），看不到其属性、生命值、装备和能力
+func RedactCharacter(ctx context.Context, character *models.Character) *models.Character {
+	if character == nil || !character.IsNPC || !redacted(ctx, character.CampaignID) {
+		return character
+	}
+	return &models.Character{
+		ID:         character.ID,
+		CampaignID: character.CampaignID,
+		Name:       character.Name,
+		IsNPC:      character.IsNPC,
+		NPCType:    character.NPCType,
+		Race:       character.Race,
+		Image:      character.Image,
+		Conditions: character.Conditions,
+		CreatedAt:  character.CreatedAt,
+		UpdatedAt:  character.UpdatedAt,
+	}
+}
+
+// RedactCharacters 对角色列表逐个应用 RedactCharacter
+func RedactCharacters(ctx context.Context, characters []*models.Character) []*models.Character {
+	if _, ok := auth.FromContext(ctx); !ok {
+		return characters
+	}
+	result := make([]*models.Character, len(characters))
+	for i, character := range characters {
+		result[i] = RedactCharacter(ctx, character)
+	}
+	return result
+}
+
+// RedactMap 返回调用者可见的地图
+// 玩家和观察者看不到隐藏的 Token、未公开的 Token 属性条和未被发现的暗门
+func RedactMap(ctx context.Context, gameMap *models.Map) *models.Map {
+	if gameMap == nil || !redacted(ctx, gameMap.CampaignID) {
+		return gameMap
+	}
+
+	copied := *gameMap
+	copied.Tokens = make([]models.Token, 0, len(gameMap.Tokens))
+	for _, token := range gameMap.Tokens {
+		if token.Hidden || token.Disposition == models.DispositionSecret {
+			continue
+		}
+		if token.Bar1 != nil && !token.Bar1.Visible {
+			token.Bar1 = nil
+		}
+		if token.Bar2 != nil && !token.Bar2.Visible {
+			token.Bar2 = nil
+		}
+		copied.Tokens = append(copied.Tokens, token)
+	}
+
+	copied.Walls = make(models.Walls, 0, len(gameMap.Walls))
+	for _, wall := range gameMap.Walls {
+		if !wall.IsSecret() || wall.IsOpen() {
+			copied.Walls = append(copied.Walls, wall)
+		}
+	}
+	return &copied
+}
+
+// authorizeCombatDM 检查调用者是否为战斗所属战役的 DM
+func (s *CombatService) authorizeCombatDM(ctx context.Context, combat *models.Combat) error {
+	if principal, ok := auth.FromContext(ctx); !ok || principal.Role == auth.RoleSystem {
+		return nil
+	}
+	campaign, err := s.campaignStore.Get(ctx, combat.CampaignID)
+	if err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return authorizeCampaignDM(ctx, campaign)
+}
+
+// authorizeCurrentTurn 检查调用者能否操作当前行动者
+func (s *CombatService) authorizeCurrentTurn(ctx context.Context, combat *models.Combat) error {
+	if !redacted(ctx, combat.CampaignID) {
+		return nil
+	}
+	current := combat.GetCurrentParticipant()
+	if current == nil {
+		return nil
+	}
+	character, err := s.characterStore.Get(ctx, current.CharacterID)
+	if err != nil {
+		return fmt.Errorf("failed to get character: %w", err)
+	}
+	return authorizeActor(ctx, character)
+}
+
+// authorizeToken 检查调用者能否移动 Token
+// 玩家只能移动自己角色的 Token；没有角色存储时无法确认归属，玩家一律不可移动
+func (s *MapService) authorizeToken(ctx context.Context, campaignID string, token *models.Token) error {
+	if !redacted(ctx, campaignID) {
+		return nil
+	}
+	if s.characterStore == nil || token.CharacterID == "" {
+		return NewServiceError(ErrCodeForbidden, "only the DM may move this token")
+	}
+	character, err := s.characterStore.GetByCampaignAndID(ctx, campaignID, token.CharacterID)
+	if err != nil {
+		return fmt.Errorf("failed to get character: %w", err)
+	}
+	return authorizeActor(ctx, character)
+}
+
+// authorizeViewer 检查调用者能否以 Token 的视角查看地图
+// 玩家只能查看自己角色 Token 的视野，否则可以借怪物或 NPC 的视野得知其能看到的内容
+func (s *VisionService) authorizeViewer(ctx context.Context, campaignID string, token *models.Token) error {
+	if !redacted(ctx, campaignID) {
+		return nil
+	}
+	if s.characterStore == nil || token.CharacterID == "" {
+		return NewServiceError(ErrCodeForbidden, "only the DM may view from this token")
+	}
+	character, err := s.characterStore.Get(ctx, token.CharacterID)
+	if err != nil {
+		return fmt.Errorf("failed to get character: %w", err)
+	}
+	return authorizeActor(ctx, character)
+}
+
+// CharacterStoreForEvents defines the character store interface needed by event access
+type CharacterStoreForEvents interface {
+	Get(ctx context.Context, id string) (*models.Character, error)
+	List(ctx context.Context, filter *store.CharacterFilter) ([]*models.Character, error)
+}
+
+// MapStoreForEvents defines the map store interface needed by event access
+type MapStoreForEvents interface {
+	Get(ctx context.Context, id string) (*models.Map, error)
+}
+
+// EventAccess 决定调用者能读取战役的哪些事件
+type EventAccess struct {
+	characterStore CharacterStoreForEvents
+	mapStore       MapStoreForEvents
+}
+
+// NewEventAccess creates a new event access check
+func NewEventAccess(characterStore CharacterStoreForEvents, mapStore MapStoreForEvents) *EventAccess {
+	return &EventAccess{characterStore: characterStore, mapStore: mapStore}
+}
+
+// EventView 返回调用者看到的事件，false 表示调用者看不到该事件
+type EventView func(event *models.GameEvent) (*models.GameEvent, bool)
+
+// View 检查调用者是否属于战役，并返回其事件视图
+// 战役的 DM 看到全部事件；玩家必须在战役中拥有玩家角色，看不到 NPC 的生命值和隐藏 Token 的移动；
+// 观察者和其他战役的 DM 不属于战役
+func (a *EventAccess) View(ctx context.Context, campaignID string) (EventView, error) {
+	if !redacted(ctx, campaignID) {
+		return func(event *models.GameEvent) (*models.GameEvent, bool) { return event, true }, nil
+	}
+	if err := a.authorizeMember(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	npcs := make(map[string]bool)
+	return func(event *models.GameEvent) (*models.GameEvent, bool) {
+		switch event.Type {
+		case models.EventHPChanged:
+			characterID, _ := event.Data["character_id"].(string)
+			if a.isNPC(ctx, npcs, characterID) {
+				return redactHPEvent(event), true
+			}
+		case models.EventTokenMoved:
+			mapID, _ := event.Data["map_id"].(string)
+			tokenID, _ := event.Data["token_id"].(string)
+			if a.tokenHidden(ctx, mapID, tokenID) {
+				return nil, false
+			}
+		}
+		return event, true
+	}, nil
+}
+
+// authorizeMember 检查玩家是否在战役中拥有玩家角色
+func (a *EventAccess) authorizeMember(ctx context.Context, campaignID string) error {
+	principal, _ := auth.FromContext(ctx)
+	if principal.Role == auth.RolePlayer {
+		isNPC := false
+		characters, err := a.characterStore.List(ctx, &store.CharacterFilter{CampaignID: campaignID, PlayerID: principal.UserID, IsNPC: &isNPC, Limit: 1})
+		if err != nil {
+			return fmt.Errorf("failed to list characters: %w", err)
+		}
+		if len(characters) > 0 {
+			return nil
+		}
+	}
+	return NewServiceError(ErrCodeForbidden, fmt.Sprintf("%s %s is not a member of campaign %s", principal.Role, principal.UserID, campaignID))
+}
+
+// isNPC 判断角色是否为 NPC，读取失败时按 NPC 处理
+func (a *EventAccess) isNPC(ctx context.Context, npcs map[string]bool, characterID string) bool {
+	if npc, ok := npcs[characterID]; ok {
+		return npc
+	}
+	character, err := a.characterStore.Get(ctx, characterID)
+	npc := err != nil || character.IsNPC
+	npcs[characterID] = npc
+	return npc
+}
+
+// tokenHidden 判断 Token 当前是否对玩家隐藏，读取失败时按隐藏处理
+func (a *EventAccess) tokenHidden(ctx context.Context, mapID, tokenID string) bool {
+	gameMap, err := a.mapStore.Get(ctx, mapID)
+	if err != nil {
+		return true
+	}
+	token := gameMap.GetToken(tokenID)
+	return token == nil || token.Hidden || token.Disposition == models.DispositionSecret
+}
+
+// redactHPEvent 去掉 NPC 生命值变化事件中的具体数值，只保留是否倒下
+func redactHPEvent(event *models.GameEvent) *models.GameEvent {
+	copied := *event
+	copied.Data = make(map[string]interface{})
+	for _, key := range []string{"character_id", "name", "down", "source"} {
+		if value, ok := event.Data[key]; ok {
+			copied.Data[key] = value
+		}
+	}
+	return &copied
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dnd-mcp/server/internal/auth"
 	"github.com/dnd-mcp/server/internal/models"
 	"github.com/dnd-mcp/server/internal/store"
 	"github.com/google/uuid"
@@ -34,6 +35,12 @@ type GameStateStore interface {
 type CampaignService struct {
 	campaignStore CampaignStore
 	gameStateStore GameStateStore
+
+	// 存档点（可选，通过 SetSnapshotStores 启用）
+	snapshotStore store.SnapshotStore
+	records       *campaignRecords
+	messageStore  MessageStoreForSnapshot
+	transactor    store.ConsistentTransactor
 }
 
 // NewCampaignService creates a new campaign service
@@ -59,6 +66,7 @@ type CampaignSettingsInput struct {
 	Ruleset       string                 `json:"ruleset"`
 	HouseRules    map[string]interface{} `json:"house_rules"`
 	ContextWindow int                    `json:"context_window"`
+	Prompts       map[string]string      `json:"prompts"`
 }
 
 // UpdateCampaignRequest represents a campaign update request
@@ -80,6 +88,16 @@ type ListCampaignsRequest struct {
 
 // CreateCampaign creates a new campaign with associated game state
 func (s *CampaignService) CreateCampaign(ctx context.Context, req *CreateCampaignRequest) (*models.Campaign, error) {
+	// A DM creates campaigns they run
+	if principal, ok := auth.FromContext(ctx); ok && principal.Role == auth.RoleDM {
+		if req.DMID == "" {
+			req.DMID = principal.UserID
+		}
+		if req.DMID != principal.UserID {
+			return nil, NewServiceError(ErrCodeForbidden, "a DM may only create campaigns they run")
+		}
+	}
+
 	// Validate request
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
@@ -182,6 +200,9 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID string,
 	if err != nil {
 		return nil, fmt.Errorf("failed to get campaign: %w", err)
 	}
+	if err := authorizeCampaignDM(ctx, campaign); err != nil {
+		return nil, err
+	}
 
 	// Apply updates
 	if req.Name != nil {
@@ -228,11 +249,14 @@ func (s *CampaignService) DeleteCampaign(ctx context.Context, campaignID string)
 		return NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
 	}
 
-	// Verify campaign exists
-	_, err := s.campaignStore.Get(ctx, campaignID)
+	// Verify campaign exists and is run by the caller
+	campaign, err := s.campaignStore.Get(ctx, campaignID)
 	if err != nil {
 		return fmt.Errorf("failed to get campaign: %w", err)
 	}
+	if err := authorizeCampaignDM(ctx, campaign); err != nil {
+		return err
+	}
 
 	// Delete campaign (soft delete)
 	if err := s.campaignStore.Delete(ctx, campaignID); err != nil {
@@ -338,6 +362,17 @@ func (s *CampaignService) applySettings(settings *models.CampaignSettings, input
 	if input.ContextWindow > 0 {
 		settings.ContextWindow = input.ContextWindow
 	}
+	// Prompt overrides are merged; an empty template restores the default
+	for name, text := range input.Prompts {
+		if settings.Prompts == nil {
+			settings.Prompts = make(map[string]string)
+		}
+		if text == "" {
+			delete(settings.Prompts, name)
+		} else {
+			settings.Prompts[name] = text
+		}
+	}
 }
 
 // validateStatusTransition validates status transitions
@@ -401,6 +436,8 @@ const (
 	ErrCodeInvalidInput = "INVALID_INPUT"
 	ErrCodeNotFound     = "NOT_FOUND"
 	ErrCodeInvalidState = "INVALID_STATE"
+	ErrCodeForbidden    = "FORBIDDEN"
+	ErrCodeConflict     = "CONFLICT"
 )
 
 // ServiceError represents a service-level error
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dnd-mcp/server/internal/models"
 	"github.com/dnd-mcp/server/internal/rules"
+	"github.com/dnd-mcp/server/internal/rules/dice"
 	"github.com/dnd-mcp/server/internal/store"
 	"github.com/google/uuid"
 )
@@ -27,13 +28,24 @@ type CharacterStore interface {
 // CharacterService provides character business logic
 // 规则参考: PHB 第7章 Ability Scores and Modifiers, 第9章 Combat
 type CharacterService struct {
-	store CharacterStore
+	eventEmitter
+	store  CharacterStore
+	roller *dice.Roller
 }
 
 // NewCharacterService creates a new character service
 func NewCharacterService(store CharacterStore) *CharacterService {
 	return &CharacterService{
-		store: store,
+		store:  store,
+		roller: dice.NewRoller(),
+	}
+}
+
+// NewCharacterServiceWithRoller creates a new character service with a custom roller (for testing)
+func NewCharacterServiceWithRoller(store CharacterStore, roller *dice.Roller) *CharacterService {
+	return &CharacterService{
+		store:  store,
+		roller: roller,
 	}
 }
 
@@ -103,6 +115,10 @@ type CreateCharacterRequest struct {
 	// 专长/特性
 	Features []*models.Feature `json:"features"`
 
+	// 天生攻击与多重攻击（怪物）
+	NaturalAttacks []*models.EquipmentItem `json:"natural_attacks"`
+	Multiattack    *models.Multiattack     `json:"multiattack"`
+
 	// 传记
 	Biography *models.Biography `json:"biography"`
 
@@ -143,6 +159,8 @@ type UpdateCharacterRequest struct {
 	InventoryItems []*models.InventoryItem `json:"inventory_items"`
 	Spellbook     *models.Spellbook     `json:"spellbook"`
 	Features      []*models.Feature     `json:"features"`
+	NaturalAttacks []*models.EquipmentItem `json:"natural_attacks"`
+	Multiattack   *models.Multiattack   `json:"multiattack"`
 	Biography     *models.Biography     `json:"biography"`
 	Traits        *models.Traits        `json:"traits"`
 	ImportMeta    *models.ImportMeta    `json:"import_meta"`
@@ -169,7 +187,10 @@ type ListCharactersRequest struct {
 // CreateCharacter creates a new character
 // 规则参考: PHB 第1章 Step-by-Step Characters
 func (s *CharacterService) CreateCharacter(ctx context.Context, req *CreateCharacterRequest) (*models.Character, error) {
-	// 1. 参数验证
+	// 1. 权限与参数验证
+	if err := authorizeCreateCharacter(ctx, req); err != nil {
+		return nil, err
+	}
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
 	}
@@ -244,6 +265,12 @@ func (s *CharacterService) CreateCharacter(ctx context.Context, req *CreateChara
 	if req.Features != nil {
 		character.Features = req.Features
 	}
+	if req.NaturalAttacks != nil {
+		character.NaturalAttacks = req.NaturalAttacks
+	}
+	if req.Multiattack != nil {
+		character.Multiattack = req.Multiattack
+	}
 	if req.Biography != nil {
 		character.Biography = req.Biography
 	}
@@ -336,6 +363,9 @@ func (s *CharacterService) UpdateCharacter(ctx context.Context, id string, req *
 	if err != nil {
 		return nil, fmt.Errorf("failed to get character: %w", err)
 	}
+	if err := authorizeActor(ctx, character); err != nil {
+		return nil, err
+	}
 
 	// 应用更新
 	if req.Name != nil {
@@ -442,6 +472,12 @@ func (s *CharacterService) UpdateCharacter(ctx context.Context, id string, req *
 	if req.Features != nil {
 		character.Features = req.Features
 	}
+	if req.NaturalAttacks != nil {
+		character.NaturalAttacks = req.NaturalAttacks
+	}
+	if req.Multiattack != nil {
+		character.Multiattack = req.Multiattack
+	}
 	if req.Biography != nil {
 		character.Biography = req.Biography
 	}
@@ -502,6 +538,7 @@ func (s *CharacterService) ChangeHP(ctx context.Context, id string, req *HPChang
 	if character.HP == nil {
 		character.HP = models.NewHP(1) // 默认最小 HP
 	}
+	before := hpSnapshot(character)
 
 	// 应用伤害（先扣临时 HP，再扣当前 HP）
 	if req.Damage > 0 {
@@ -535,6 +572,7 @@ func (s *CharacterService) ChangeHP(ctx context.Context, id string, req *HPChang
 	if err := s.store.Update(ctx, character); err != nil {
 		return nil, fmt.Errorf("failed to update character: %w", err)
 	}
+	s.emitHPChanged(ctx, character, before, "hp_change")
 
 	return character, nil
 }
@@ -562,6 +600,7 @@ func (s *CharacterService) AddCondition(ctx context.Context, id string, conditio
 	if err := s.store.Update(ctx, character); err != nil {
 		return nil, fmt.Errorf("failed to update character: %w", err)
 	}
+	s.emitConditions(ctx, character, models.EventConditionApplied, []string{conditionType}, source)
 
 	return character, nil
 }
@@ -590,6 +629,7 @@ func (s *CharacterService) RemoveCondition(ctx context.Context, id string, condi
 	if err := s.store.Update(ctx, character); err != nil {
 		return nil, fmt.Errorf("failed to update character: %w", err)
 	}
+	s.emitConditions(ctx, character, models.EventConditionRemoved, []string{conditionType}, "")
 
 	return character, nil
 }
@@ -703,11 +743,15 @@ func (s *CharacterService) calculateCombatStats(character *models.Character, req
 
 // DeathSaveRequest 死亡豁免请求
 type DeathSaveRequest struct {
-	Roll int `json:"roll"` // 豁免骰结果 (1-20)
+	CharacterID string `json:"character_id" jsonschema:"required" description:"The ID of the dying character"`                                                     // 角色ID
+	Roll        int    `json:"roll" jsonschema:"minimum=0,maximum=20" description:"The d20 result if the player rolled physical dice; omit to roll automatically"` // 豁免骰结果 (1-20)，0 表示自动掷骰
 }
 
 // DeathSaveResponse 死亡豁免响应
 type DeathSaveResponse struct {
+	CharacterID string `json:"character_id"` // 角色ID
+	Name        string `json:"name"`         // 角色名称
+	Roll        int    `json:"roll"`         // 豁免骰结果
 	Result      string `json:"result"`       // 结果: success, failure, critical_success, critical_failure
 	IsStable    bool   `json:"is_stable"`    // 是否稳定
 	IsDead      bool   `json:"is_dead"`      // 是否死亡
@@ -716,14 +760,29 @@ type DeathSaveResponse struct {
 	HealedHP    int    `json:"healed_hp"`    // 恢复的 HP（大成功时）
 }
 
-// MakeDeathSave 执行死亡豁免
+// Summary 死亡豁免结果描述，用于战斗日志和工具消息
+func (r *DeathSaveResponse) Summary() string {
+	switch {
+	case r.HealedHP > 0:
+		return fmt.Sprintf("%s rolled a natural 20 on a death save and regains %d HP", r.Name, r.HealedHP)
+	case r.IsDead:
+		return fmt.Sprintf("%s rolled %d on a death save (%s) and dies", r.Name, r.Roll, r.Result)
+	case r.IsStable:
+		return fmt.Sprintf("%s rolled %d on a death save (%s) and is stable", r.Name, r.Roll, r.Result)
+	default:
+		return fmt.Sprintf("%s rolled %d on a death save (%s): %d successes, %d failures",
+			r.Name, r.Roll, r.Result, r.Successes, r.Failures)
+	}
+}
+
+// MakeDeathSave 执行死亡豁免，未提供骰子结果时自动掷 d20
 // 规则参考: PHB 第9章 - Dropping to 0 Hit Points / Death Saving Throws
 func (s *CharacterService) MakeDeathSave(ctx context.Context, id string, req *DeathSaveRequest) (*DeathSaveResponse, error) {
 	if id == "" {
 		return nil, NewServiceError(ErrCodeInvalidInput, "character ID is required")
 	}
-	if req.Roll < 1 || req.Roll > 20 {
-		return nil, NewServiceError(ErrCodeInvalidInput, "roll must be between 1 and 20")
+	if req.Roll < 0 || req.Roll > 20 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "roll must be between 1 and 20, or 0 to roll automatically")
 	}
 
 	// 获取角色
@@ -732,57 +791,58 @@ func (s *CharacterService) MakeDeathSave(ctx context.Context, id string, req *De
 		return nil, fmt.Errorf("failed to get character: %w", err)
 	}
 
+	// 玩家只能为自己的角色进行死亡豁免
+	if err := authorizeActor(ctx, character); err != nil {
+		return nil, err
+	}
+
 	// 验证角色处于昏迷状态
 	if !character.IsUnconscious() {
 		return nil, NewServiceError(ErrCodeInvalidInput, "character is not unconscious")
 	}
 
-	// 确保死亡豁免结构存在
+	roll := req.Roll
+	if roll == 0 {
+		roll = s.roller.Roll(20)
+	}
+	before := hpSnapshot(character)
+	response := resolveDeathSave(character, roll)
+
+	// 保存更新
+	if err := s.store.Update(ctx, character); err != nil {
+		return nil, fmt.Errorf("failed to update character: %w", err)
+	}
+	s.emitHPChanged(ctx, character, before, "death_save")
+
+	return response, nil
+}
+
+// resolveDeathSave 按骰子结果更新昏迷角色的死亡豁免
+// 规则参考: PHB 第9章 - Dropping to 0 Hit Points / Death Saving Throws
+func resolveDeathSave(character *models.Character, roll int) *DeathSaveResponse {
 	deathSaves := character.GetDeathSaves()
+	result, _ := rules.MakeDeathSave(deathSaves, roll)
 
 	response := &DeathSaveResponse{
-		Successes: deathSaves.Successes,
-		Failures:  deathSaves.Failures,
+		CharacterID: character.ID,
+		Name:        character.Name,
+		Roll:        roll,
+		Result:      string(result),
 	}
 
-	// 执行豁免
-	// 自然 20: 大成功，恢复 1 HP
-	if req.Roll == 20 {
+	// 自然 20: 大成功，恢复 1 HP 并重置死亡豁免
+	if result == rules.DeathSaveCriticalSuccess {
 		deathSaves.Reset()
 		if character.HP != nil {
 			response.HealedHP = character.HP.Heal(1)
 		}
-		response.Result = "critical_success"
-		response.Successes = 0
-		response.Failures = 0
-	} else if req.Roll == 1 {
-		// 自然 1: 大失败，失败 +2
-		deathSaves.AddFailure()
-		deathSaves.AddFailure()
-		response.Result = "critical_failure"
-		response.Failures = deathSaves.Failures
-	} else if req.Roll >= 10 {
-		// >= 10: 成功
-		deathSaves.AddSuccess()
-		response.Result = "success"
-		response.Successes = deathSaves.Successes
-		response.IsStable = deathSaves.IsStable()
-	} else {
-		// < 10: 失败
-		deathSaves.AddFailure()
-		response.Result = "failure"
-		response.Failures = deathSaves.Failures
 	}
 
-	// 检查死亡
+	response.Successes = deathSaves.Successes
+	response.Failures = deathSaves.Failures
+	response.IsStable = deathSaves.IsStable()
 	response.IsDead = deathSaves.IsDead()
-
-	// 保存更新
-	if err := s.store.Update(ctx, character); err != nil {
-		return nil, fmt.Errorf("failed to update character: %w", err)
-	}
-
-	return response, nil
+	return response
 }
 
 // DamageWhileUnconsciousRequest 昏迷时受伤请求
@@ -4,10 +4,13 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/dnd-mcp/server/internal/models"
 	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
 	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+	"github.com/dnd-mcp/server/internal/store"
 )
 
 // CombatStore defines the interface for combat data operations
@@ -31,15 +34,23 @@ type GameStateStoreForCombat interface {
 	Update(ctx context.Context, gameState *models.GameState) error
 }
 
+// MapStoreForCombat defines the map store interface needed by combat service
+type MapStoreForCombat interface {
+	Get(ctx context.Context, id string) (*models.Map, error)
+}
+
 // CombatService provides combat business logic
 // 规则参考: PHB 第9章 Combat
 type CombatService struct {
+	eventEmitter
 	combatStore     CombatStore
 	characterStore  CharacterStore
 	campaignStore   CampaignStoreForCombat
 	gameStateStore  GameStateStoreForCombat
+	mapStore        MapStoreForCombat
 	diceService     *DiceService
 	roller          *dice.Roller
+	transactor      store.Transactor
 }
 
 // NewCombatService creates a new combat service
@@ -79,6 +90,18 @@ func NewCombatServiceWithRoller(
 	}
 }
 
+// SetMapStore enables distance-aware combat rules.
+// When set, token positions on the combat's battle map are used to
+// resolve range-dependent effects such as attacking a prone target.
+func (s *CombatService) SetMapStore(mapStore MapStoreForCombat) {
+	s.mapStore = mapStore
+}
+
+// SetTransactor makes spellcasting save the caster, its targets and the combat in one transaction.
+func (s *CombatService) SetTransactor(transactor store.Transactor) {
+	s.transactor = transactor
+}
+
 // StartCombatRequest 开始战斗请求
 type StartCombatRequest struct {
 	CampaignID     string   `json:"campaign_id"`
@@ -97,11 +120,14 @@ func (s *CombatService) StartCombat(ctx context.Context, req *StartCombatRequest
 		return nil, NewServiceError(ErrCodeInvalidInput, "at least one participant is required")
 	}
 
-	// 2. 验证战役存在
-	_, err := s.campaignStore.Get(ctx, req.CampaignID)
+	// 2. 验证战役存在，且调用者是该战役的 DM
+	campaign, err := s.campaignStore.Get(ctx, req.CampaignID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get campaign: %w", err)
 	}
+	if err := authorizeCampaignDM(ctx, campaign); err != nil {
+		return nil, err
+	}
 
 	// 3. 检查是否已有活动战斗
 	activeCombat, err := s.combatStore.GetActive(ctx, req.CampaignID)
@@ -126,6 +152,7 @@ func (s *CombatService) StartCombat(ctx context.Context, req *StartCombatRequest
 			Initiative:  initiative,
 			HasActed:    false,
 			Conditions:  make([]models.Condition, 0),
+			Actions:     models.NewActionBudget(characterSpeed(character)),
 		})
 	}
 
@@ -193,22 +220,33 @@ func (s *CombatService) GetActiveCombat(ctx context.Context, campaignID string)
 
 // AttackRequest 攻击请求
 type AttackRequest struct {
-	CombatID     string `json:"combat_id"`
-	AttackerID   string `json:"attacker_id"`
-	TargetID     string `json:"target_id"`
-	Advantage    bool   `json:"advantage"`
-	Disadvantage bool   `json:"disadvantage"`
+	CombatID     string `json:"combat_id" jsonschema:"required" description:"The ID of the combat encounter"`
+	AttackerID   string `json:"attacker_id" jsonschema:"required" description:"The ID of the attacking character"`
+	TargetID     string `json:"target_id" jsonschema:"required" description:"The ID of the target character"`
+	Advantage    bool   `json:"advantage" description:"Roll with advantage (roll 2d20, take higher)"`
+	Disadvantage bool   `json:"disadvantage" description:"Roll with disadvantage (roll 2d20, take lower)"`
+	WeaponID     string `json:"weapon_id" description:"ID or name of the weapon or natural attack to use (optional, defaults to the equipped weapon or Multiattack)"`                      // 指定武器或天生攻击（可选，默认使用装备的武器或多重攻击）
+	Count        int    `json:"count" jsonschema:"minimum=0" description:"Number of attacks to make against this target (optional, defaults to all remaining attacks of the Attack action)"` // 本次进行的攻击次数（可选，默认用完攻击动作的全部攻击）
+	OffHand      bool   `json:"off_hand" description:"Make a two-weapon fighting attack with the off-hand light weapon as a bonus action (requires the Attack action first)"`               // 双武器战斗：以附赠动作用副手轻型武器攻击
 }
 
 // AttackResponse 攻击响应
 type AttackResponse struct {
-	Result     *rulescombat.AttackResult `json:"result"`
-	Combat     *models.Combat            `json:"combat"`
-	TargetDead bool                      `json:"target_dead"`
+	Result           *rulescombat.AttackResult   `json:"result"`            // 第一次攻击结果（向后兼容）
+	Attacks          []*rulescombat.AttackResult `json:"attacks"`           // 本次所有攻击结果
+	TotalDamage      int                         `json:"total_damage"`      // 总伤害
+	AttacksRemaining int                         `json:"attacks_remaining"` // 攻击动作剩余的攻击次数
+	Combat           *models.Combat              `json:"combat"`
+	TargetDead       bool                        `json:"target_dead"`
+
+	ConcentrationChecks []*ConcentrationCheck `json:"concentration_checks,omitempty"` // 目标受伤后的专注检定
+	SkippedAttacks      []string              `json:"skipped_attacks,omitempty"`      // 因超出触及或射程而未进行的攻击
 }
 
 // Attack 执行攻击
-// 规则参考: PHB 第9章 - Making an Attack
+// 一次调用完成攻击动作中的全部攻击（额外攻击、怪物多重攻击），
+// 或以附赠动作进行双武器战斗的副手攻击
+// 规则参考: PHB 第9章 - Making an Attack / 第3章 - Extra Attack
 func (s *CombatService) Attack(ctx context.Context, req *AttackRequest) (*AttackResponse, error) {
 	// 1. 参数验证
 	if req.CombatID == "" {
@@ -220,6 +258,9 @@ func (s *CombatService) Attack(ctx context.Context, req *AttackRequest) (*Attack
 	if req.TargetID == "" {
 		return nil, NewServiceError(ErrCodeInvalidInput, "target ID is required")
 	}
+	if req.Count < 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "attack count cannot be negative")
+	}
 
 	// 2. 获取战斗
 	combat, err := s.combatStore.Get(ctx, req.CombatID)
@@ -233,6 +274,9 @@ func (s *CombatService) Attack(ctx context.Context, req *AttackRequest) (*Attack
 	}
 
 	// 4. 验证当前是攻击者的回合
+	if err := authorizeTurn(ctx, combat, req.AttackerID); err != nil {
+		return nil, err
+	}
 	currentParticipant := combat.GetCurrentParticipant()
 	if currentParticipant == nil || currentParticipant.CharacterID != req.AttackerID {
 		return nil, NewServiceError(ErrCodeInvalidState, "not attacker's turn")
@@ -243,6 +287,9 @@ func (s *CombatService) Attack(ctx context.Context, req *AttackRequest) (*Attack
 	if err != nil {
 		return nil, fmt.Errorf("failed to get attacker: %w", err)
 	}
+	if err := authorizeActor(ctx, attacker); err != nil {
+		return nil, err
+	}
 
 	target, err := s.characterStore.Get(ctx, req.TargetID)
 	if err != nil {
@@ -255,77 +302,266 @@ func (s *CombatService) Attack(ctx context.Context, req *AttackRequest) (*Attack
 		return nil, NewServiceError(ErrCodeInvalidInput, "target is not in combat")
 	}
 
-	// 7. 获取攻击者装备的武器
-	weapon := s.getEquippedWeapon(attacker)
+	// 7. 根据战斗地图计算目标的掩护，全身掩护的目标不能被直接攻击
+	// 规则参考: PHB 第9章 - Cover
+	cover := s.participantCover(ctx, combat, req.AttackerID, req.TargetID)
+	if cover.IsTotal() {
+		return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("%s has total cover and cannot be targeted directly", target.Name))
+	}
 
-	// 8. 执行攻击检定
-	// 规则参考: PHB 第9章 - Attack Rolls
-	result := rulescombat.ResolveAttack(attacker, target, weapon, req.Advantage, req.Disadvantage, s.roller)
+	// 8. 确定攻击序列并消耗动作
+	// 规则参考: PHB 第9章 - Your Turn / Attack
+	budget := *currentParticipant.EnsureActions(characterSpeed(attacker))
+	sequence, refundable, err := s.buildAttackSequence(currentParticipant, attacker, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 9. 检查每次攻击的触及或射程，跳过无法触及目标的攻击
+	// 没有一次攻击能触及目标时拒绝攻击并退还动作
+	// 规则参考: PHB 第9章 - Melee Attacks / Ranged Attacks, 第5章 - Reach / Range
+	resp := &AttackResponse{
+		Attacks: make([]*rulescombat.AttackResult, 0, len(sequence)),
+		Combat:  combat,
+	}
+	distance := s.participantDistance(ctx, combat, req.AttackerID, req.TargetID)
+	inRange := make([]*models.EquipmentItem, 0, len(sequence))
+	ranges := make([]rulescombat.RangeCheck, 0, len(sequence))
+	for _, weapon := range sequence {
+		check := rulescombat.CheckAttackRange(weapon, distance)
+		if !check.InRange {
+			resp.SkippedAttacks = append(resp.SkippedAttacks, fmt.Sprintf("%s: %s", attackName(weapon), check.Reason))
+			continue
+		}
+		inRange = append(inRange, weapon)
+		ranges = append(ranges, check)
+	}
+	if len(inRange) == 0 {
+		*currentParticipant.Actions = budget
+		return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("%s is out of range: %s",
+			target.Name, rulescombat.CheckAttackRange(sequence[0], distance).Reason))
+	}
+	sequence = inRange
+	hostileAdjacent := s.hostileNearby(ctx, combat, req.AttackerID)
 
-	// 9. 如果命中并造成伤害，更新目标 HP
-	if result.Hit && result.Damage > 0 {
-		// 更新角色 HP
-		target.TakeDamage(result.Damage)
-		if err := s.characterStore.Update(ctx, target); err != nil {
-			return nil, fmt.Errorf("failed to update target: %w", err)
+	// 10. 依次执行攻击检定
+	// 规则参考: PHB 第9章 - Attack Rolls
+	for i, weapon := range sequence {
+		// 目标倒下后停止，剩余的额外攻击可以转向其他目标
+		if i > 0 && target.HP != nil && target.HP.IsAtZero() {
+			if refundable {
+				currentParticipant.Actions.AttacksRemaining += len(sequence) - i
+			}
+			break
 		}
 
-		// 更新参战者临时 HP（用于战斗追踪）
-		if target.HP != nil {
-			result.TargetHP = &models.HP{
-				Current: target.HP.Current,
-				Max:     target.HP.Max,
-				Temp:    target.HP.Temp,
+		// 汇总优势/劣势来源: 请求、回合动作效果、双方状态
+		// 规则参考: PHB 第7章 - Advantage and Disadvantage / 附录A - Conditions
+		modifiers := rulescombat.NewRollModifiers(req.Advantage, req.Disadvantage)
+		s.applyTurnActionEffects(combat, currentParticipant, targetParticipant, modifiers)
+		rulescombat.AddAttackConditionModifiers(modifiers,
+			rulescombat.CollectConditions(attacker, currentParticipant.Conditions),
+			rulescombat.CollectConditions(target, targetParticipant.Conditions),
+			distance, ranges[i].Ranged)
+		rulescombat.AddRangeModifiers(modifiers, ranges[i], hostileAdjacent)
+		result := rulescombat.ResolveAttackWithCover(attacker, target, weapon, modifiers, cover, s.roller)
+
+		// 11. 如果命中并造成伤害，更新目标 HP
+		if result.Hit && result.Damage > 0 {
+			before := hpSnapshot(target)
+			target.TakeDamage(result.Damage)
+			// 每次受到伤害都需要单独进行专注检定
+			// 规则参考: PHB 第10章 - Concentration
+			if check := s.checkConcentration(combat, target, result.Damage); check != nil {
+				resp.ConcentrationChecks = append(resp.ConcentrationChecks, check)
+			}
+			if err := s.characterStore.Update(ctx, target); err != nil {
+				return nil, fmt.Errorf("failed to update target: %w", err)
+			}
+			s.emitHPChanged(ctx, target, before, attackName(weapon))
+
+			// 更新参战者临时 HP（用于战斗追踪）
+			if target.HP != nil {
+				result.TargetHP = &models.HP{
+					Current: target.HP.Current,
+					Max:     target.HP.Max,
+					Temp:    target.HP.Temp,
+				}
+				result.TargetDown = target.HP.IsAtZero()
 			}
-			result.TargetDown = target.HP.IsAtZero()
 		}
+
+		// 12. 记录战斗日志
+		s.logAttack(combat, req.AttackerID, req.TargetID, weapon, result)
+
+		resp.Attacks = append(resp.Attacks, result)
+		resp.TotalDamage += result.Damage
 	}
+	resp.Result = resp.Attacks[0]
+	resp.AttacksRemaining = currentParticipant.Actions.AttacksRemaining
 
-	// 10. 记录战斗日志
+	// 13. 保存战斗状态
+	if err := s.combatStore.Update(ctx, combat); err != nil {
+		return nil, fmt.Errorf("failed to update combat: %w", err)
+	}
+
+	resp.TargetDead = target.IsDead()
+	return resp, nil
+}
+
+// buildAttackSequence 根据请求和攻击者能力确定本次要执行的攻击，并消耗相应的动作
+// - 副手攻击: 需要已采取攻击动作，双手均持轻型近战武器，消耗附赠动作
+// - 攻击动作剩余的额外攻击: 不再消耗动作
+// - 新的攻击动作: 怪物多重攻击或（额外攻击次数 × 所选武器）
+// refundable 表示未执行的攻击可以保留在攻击动作中（额外攻击）
+// 规则参考: PHB 第9章 - Two-Weapon Fighting / 第3章 - Extra Attack; MM - Multiattack
+func (s *CombatService) buildAttackSequence(participant *models.Participant, attacker *models.Character, req *AttackRequest) (sequence []*models.EquipmentItem, refundable bool, err error) {
+	budget := participant.EnsureActions(characterSpeed(attacker))
+
+	// 指定武器
+	var weapon *models.EquipmentItem
+	if req.WeaponID != "" {
+		weapon = rulescombat.FindAttack(attacker, req.WeaponID)
+		if weapon == nil {
+			return nil, false, NewServiceError(ErrCodeInvalidInput,
+				fmt.Sprintf("%s has no weapon or attack %s", attacker.Name, req.WeaponID))
+		}
+	}
+
+	// 双武器战斗
+	if req.OffHand {
+		if !budget.AttackActionTaken {
+			return nil, false, NewServiceError(ErrCodeInvalidState, "must take the Attack action before attacking with an off-hand weapon")
+		}
+		slots := attacker.EquipmentSlots
+		if slots == nil || !rulescombat.IsLightWeapon(slots.MainHand) || !rulescombat.IsLightWeapon(slots.OffHand) {
+			return nil, false, NewServiceError(ErrCodeInvalidState, "two-weapon fighting requires a light weapon in each hand")
+		}
+		if err := s.spendAction(participant, attacker, models.ActionTypeBonusAction); err != nil {
+			return nil, false, err
+		}
+		return []*models.EquipmentItem{slots.OffHand}, false, nil
+	}
+
+	if weapon == nil {
+		weapon = s.getEquippedWeapon(attacker)
+	}
+
+	// 继续攻击动作中剩余的额外攻击
+	if budget.AttacksRemaining > 0 {
+		count := req.Count
+		if count == 0 {
+			count = budget.AttacksRemaining
+		}
+		if count > budget.AttacksRemaining {
+			return nil, false, NewServiceError(ErrCodeInvalidInput,
+				fmt.Sprintf("only %d attacks remaining in this Attack action", budget.AttacksRemaining))
+		}
+		budget.AttacksRemaining -= count
+		return repeatAttack(weapon, count), true, nil
+	}
+
+	// 新的攻击动作
+	if err := s.checkAction(participant, attacker, models.ActionTypeAction); err != nil {
+		return nil, false, err
+	}
+
+	if req.WeaponID == "" && attacker.Multiattack != nil {
+		sequence = rulescombat.BuildMultiattack(attacker)
+	}
+	if len(sequence) == 0 {
+		available := rulescombat.GetAttacksPerAction(attacker)
+		count := req.Count
+		if count == 0 {
+			count = available
+		}
+		if count > available {
+			return nil, false, NewServiceError(ErrCodeInvalidInput,
+				fmt.Sprintf("%s can make at most %d attacks with the Attack action", attacker.Name, available))
+		}
+		budget.AttacksRemaining = available - count
+		sequence = repeatAttack(weapon, count)
+		refundable = true
+	}
+
+	budget.Use(models.ActionTypeAction)
+	budget.AttackActionTaken = true
+	return sequence, refundable, nil
+}
+
+// logAttack 记录单次攻击的战斗日志
+func (s *CombatService) logAttack(combat *models.Combat, attackerID, targetID string, weapon *models.EquipmentItem, result *rulescombat.AttackResult) {
 	action := "attack"
 	if result.Crit {
 		action = "critical_hit"
 	}
 	resultDesc := fmt.Sprintf("roll %d vs AC %d", result.AttackRoll.Total, result.TargetAC)
+	if result.Cover != "" {
+		resultDesc += fmt.Sprintf(" (%s cover)", strings.ReplaceAll(string(result.Cover), "_", "-"))
+	}
+	if weapon != nil && weapon.Name != "" {
+		resultDesc = fmt.Sprintf("%s: %s", weapon.Name, resultDesc)
+	}
 	if result.Hit {
 		resultDesc += fmt.Sprintf(", hit for %d damage", result.Damage)
+		if result.RawDamage != result.Damage {
+			resultDesc += fmt.Sprintf(" (%d before resistances)", result.RawDamage)
+		}
 	} else {
 		resultDesc += ", miss"
 	}
-	combat.AddLogEntry(req.AttackerID, action, req.TargetID, resultDesc)
+	combat.AddLogEntry(attackerID, action, targetID, resultDesc)
+}
 
-	// 11. 保存战斗状态
-	if err := s.combatStore.Update(ctx, combat); err != nil {
-		return nil, fmt.Errorf("failed to update combat: %w", err)
+// attackName 返回攻击使用的武器名称，没有武器时为徒手攻击
+func attackName(weapon *models.EquipmentItem) string {
+	if weapon == nil || weapon.Name == "" {
+		return "unarmed strike"
 	}
+	return weapon.Name
+}
 
-	return &AttackResponse{
-		Result:     result,
-		Combat:     combat,
-		TargetDead: target.IsDead(),
-	}, nil
+// repeatAttack 生成同一武器的多次攻击
+func repeatAttack(weapon *models.EquipmentItem, count int) []*models.EquipmentItem {
+	sequence := make([]*models.EquipmentItem, count)
+	for i := range sequence {
+		sequence[i] = weapon
+	}
+	return sequence
 }
 
 // CastSpellRequest 施法请求
+// 法术优先从施法者法术书中查找；Damage/DamageType/IsHealing 仅用于
+// 没有法术书记录的施法者（如即兴创建的 NPC）
 type CastSpellRequest struct {
-	CombatID  string   `json:"combat_id"`
-	CasterID  string   `json:"caster_id"`
-	SpellID   string   `json:"spell_id"`
-	SpellName string   `json:"spell_name"`
-	TargetIDs []string `json:"target_ids"`
-	Level     int      `json:"level"`
-	Damage    string   `json:"damage"`     // 伤害公式（如 "2d6"）
-	DamageType string  `json:"damage_type"` // 伤害类型
-	IsHealing  bool    `json:"is_healing"`  // 是否为治疗法术
+	CombatID     string   `json:"combat_id"`
+	CasterID     string   `json:"caster_id"`
+	SpellID      string   `json:"spell_id"`
+	SpellName    string   `json:"spell_name"`
+	TargetIDs    []string `json:"target_ids"`
+	Level        int      `json:"level"`        // 施法环阶（0 表示使用法术本身等级）
+	Damage       string   `json:"damage"`       // 伤害公式（如 "2d6"）
+	DamageType   string   `json:"damage_type"`  // 伤害类型
+	IsHealing    bool     `json:"is_healing"`   // 是否为治疗法术
+	Advantage    bool     `json:"advantage"`    // 法术攻击检定优势
+	Disadvantage bool     `json:"disadvantage"` // 法术攻击检定劣势
+
+	// 临时法术（不在法术书中）的附加参数
+	SaveAbility   string   `json:"save_ability"`  // 目标豁免属性（为空表示无豁免）
+	Concentration bool     `json:"concentration"` // 是否需要专注
+	Conditions    []string `json:"conditions"`    // 豁免失败或命中时施加的状态
 }
 
 // CastSpellResponse 施法响应
 type CastSpellResponse struct {
-	Result *rulescombat.SpellResult `json:"result"`
-	Combat *models.Combat           `json:"combat"`
+	Result              *rulescombat.SpellResult `json:"result"`
+	Combat              *models.Combat           `json:"combat"`
+	ConcentrationChecks []*ConcentrationCheck    `json:"concentration_checks,omitempty"` // 本次施法引发的专注检定或结束
 }
 
 // CastSpell 施放法术
+// 流程：查找法术 → 验证已知/已准备 → 消耗法术位 → 对每个目标进行
+// 法术攻击或豁免检定 → 结算（升阶后的）伤害或治疗
 // 规则参考: PHB 第10-11章 - Spellcasting
 func (s *CombatService) CastSpell(ctx context.Context, req *CastSpellRequest) (*CastSpellResponse, error) {
 	// 1. 参数验证
@@ -341,6 +577,9 @@ func (s *CombatService) CastSpell(ctx context.Context, req *CastSpellRequest) (*
 	if len(req.TargetIDs) == 0 {
 		return nil, NewServiceError(ErrCodeInvalidInput, "at least one target is required")
 	}
+	if req.Level < 0 || req.Level > 9 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "spell level must be between 0 and 9")
+	}
 
 	// 2. 获取战斗
 	combat, err := s.combatStore.Get(ctx, req.CombatID)
@@ -354,6 +593,9 @@ func (s *CombatService) CastSpell(ctx context.Context, req *CastSpellRequest) (*
 	}
 
 	// 4. 验证当前是施法者的回合
+	if err := authorizeTurn(ctx, combat, req.CasterID); err != nil {
+		return nil, err
+	}
 	currentParticipant := combat.GetCurrentParticipant()
 	if currentParticipant == nil || currentParticipant.CharacterID != req.CasterID {
 		return nil, NewServiceError(ErrCodeInvalidState, "not caster's turn")
@@ -364,97 +606,450 @@ func (s *CombatService) CastSpell(ctx context.Context, req *CastSpellRequest) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to get caster: %w", err)
 	}
+	if err := authorizeActor(ctx, caster); err != nil {
+		return nil, err
+	}
 
-	// 6. 创建法术结果
-	result := &rulescombat.SpellResult{
-		SpellID:    req.SpellID,
-		SpellName:  req.SpellName,
-		Level:      req.Level,
-		CasterID:   req.CasterID,
-		TargetIDs:  req.TargetIDs,
-		DamageType: req.DamageType,
-		IsHealing:  req.IsHealing,
-		Results:    make([]rulescombat.SpellTargetResult, 0),
+	// 6. 查找法术并验证施法者可以施放
+	// 规则参考: PHB 第10章 - Known and Prepared Spells
+	spell, fromSpellbook, err := s.resolveSpell(caster, req)
+	if err != nil {
+		return nil, err
+	}
+
+	castLevel := req.Level
+	if castLevel == 0 {
+		castLevel = spell.Level
+	}
+	if castLevel < spell.Level {
+		return nil, NewServiceError(ErrCodeInvalidInput,
+			fmt.Sprintf("%s cannot be cast below level %d", spell.Name, spell.Level))
+	}
+	if spell.IsCantrip() {
+		castLevel = 0
+	}
+
+	// 根据施法时间检查动作、附赠动作或反应是否可用
+	// 规则参考: PHB 第10章 - Casting Time
+	actionType := spellActionType(spell)
+	if err := s.checkAction(currentParticipant, caster, actionType); err != nil {
+		return nil, err
 	}
 
-	// 7. 对每个目标应用法术效果
+	// 7. 获取目标（在消耗法术位之前拒绝无效目标）
+	targets := make([]*models.Character, 0, len(req.TargetIDs))
 	for _, targetID := range req.TargetIDs {
+		if targetID == caster.ID {
+			targets = append(targets, caster)
+			continue
+		}
 		target, err := s.characterStore.Get(ctx, targetID)
 		if err != nil {
-			continue // 跳过无效目标
+			return nil, fmt.Errorf("failed to get target %s: %w", targetID, err)
 		}
+		targets = append(targets, target)
+	}
 
+	// 计算每个目标相对施法者的掩护
+	// 全身掩护的目标不能被法术直接指定；范围法术可以绕过掩护波及目标
+	// （没有记录范围的作用点，全身掩护在范围法术中不提供加值）
+	// 规则参考: PHB 第9章 - Cover
+	covers := make(map[string]rulescombat.CoverLevel, len(targets))
+	for _, target := range targets {
+		if target.ID == caster.ID {
+			continue
+		}
+		cover := s.participantCover(ctx, combat, caster.ID, target.ID)
+		if cover.IsTotal() && !spell.HasAreaOfEffect() {
+			return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("%s has total cover and cannot be targeted directly", target.Name))
+		}
+		if cover != rulescombat.CoverNone {
+			covers[target.ID] = cover
+		}
+	}
+
+	// 8. 消耗法术位
+	// 规则参考: PHB 第10章 - Spell Slots / Casting a Spell at a Higher Level
+	result := &rulescombat.SpellResult{
+		SpellID:        spell.ID,
+		SpellName:      spell.Name,
+		Level:          castLevel,
+		SpellLevel:     spell.Level,
+		Upcast:         castLevel > spell.Level,
+		CasterID:       req.CasterID,
+		TargetIDs:      req.TargetIDs,
+		AreaOfEffect:   spell.AreaOfEffect,
+		SlotsRemaining: -1,
+		Results:        make([]rulescombat.SpellTargetResult, 0),
+	}
+
+	if castLevel > 0 && (fromSpellbook || (caster.Spellbook != nil && caster.Spellbook.HasSlots())) {
+		spellbook := caster.GetSpellbook()
+		if !spellbook.UseSlotAtLevel(castLevel) {
+			return nil, NewServiceError(ErrCodeInvalidState,
+				fmt.Sprintf("%s has no level %d spell slots remaining", caster.Name, castLevel))
+		}
+		result.SlotUsed = true
+		result.SlotsRemaining = spellbook.AvailableSlots(castLevel)
+	}
+	currentParticipant.Actions.Use(actionType)
+	if currentParticipant.Hidden {
+		// 施法会暴露躲藏位置
+		currentParticipant.Hidden = false
+	}
+
+	// 专注法术：结束原有专注并开始新的专注
+	// 规则参考: PHB 第10章 - Concentration
+	concentrationChecks := make([]*ConcentrationCheck, 0)
+	if spell.Concentration {
+		if ended := s.startConcentration(combat, caster, spell); ended != nil {
+			concentrationChecks = append(concentrationChecks, ended)
+		}
+	}
+
+	// 9. 计算伤害/治疗公式（含升阶）
+	var baseFormula, damageType string
+	var levelScale []string
+	addSpellMod := false
+	if spell.Healing != nil && spell.Healing.BaseHealing != "" {
+		result.IsHealing = true
+		baseFormula = spell.Healing.BaseHealing
+		levelScale = spell.Healing.LevelScale
+		addSpellMod = spell.Healing.SpellMod
+	} else if spell.Damage != nil && spell.Damage.BaseDamage != "" {
+		baseFormula = spell.Damage.BaseDamage
+		damageType = spell.Damage.DamageType
+		levelScale = spell.Damage.LevelScale
+		addSpellMod = spell.Damage.SpellMod
+	}
+	result.DamageType = damageType
+
+	// 临时法术直接使用请求给出的公式，不做升阶/戏法缩放
+	formulas := rulescombat.ScaleSpellDice(baseFormula, levelScale, spell.HigherLevels, spell.Level, castLevel, caster.Level)
+	if !fromSpellbook && baseFormula != "" {
+		formulas = []string{baseFormula}
+	}
+	modifier := 0
+	if addSpellMod {
+		modifier = rulescombat.GetSpellcastingModifier(caster)
+	}
+	if len(formulas) > 0 {
+		result.Formula = strings.Join(formulas, "+")
+		if modifier != 0 {
+			result.Formula += fmt.Sprintf("%+d", modifier)
+		}
+	}
+
+	if spell.RequiresSave() {
+		result.SaveDC = rulescombat.GetSpellSaveDC(caster)
+		result.SaveAbility = strings.ToLower(spell.Save.Ability)
+	}
+	if spell.RequiresAttackRoll() {
+		result.AttackBonus = rulescombat.GetSpellAttackBonus(caster)
+	}
+
+	// 范围法术只投一次伤害，所有目标共享
+	// 规则参考: PHB 第10章 - Areas of Effect / 第9章 - Damage Rolls
+	var extraDamage []models.DamagePart
+	if !result.IsHealing && spell.Damage != nil {
+		extraDamage = spell.Damage.ExtraDamage
+	}
+	var sharedRoll *models.DiceResult
+	var sharedExtra []rulescombat.DamageInstance
+	if len(formulas) > 0 && spell.HasAreaOfEffect() {
+		sharedRoll = rulescombat.RollFormulas(formulas, modifier, false, s.roller)
+		sharedExtra = s.rollExtraDamage(extraDamage, false)
+	}
+
+	// 10. 对每个目标应用法术效果（在第 12 步统一保存）
+	changes := make([]spellTargetChange, 0, len(targets))
+	for _, target := range targets {
+		change := spellTargetChange{target: target, before: hpSnapshot(target)}
 		targetResult := rulescombat.SpellTargetResult{
-			TargetID: targetID,
-			Hit:      true, // 简化版：默认命中
+			TargetID: target.ID,
+			Hit:      true,
+		}
+		cover := covers[target.ID]
+		targetResult.Cover = cover
+
+		// 法术攻击检定
+		if spell.RequiresAttackRoll() {
+			modifiers := rulescombat.NewRollModifiers(req.Advantage, req.Disadvantage)
+			rulescombat.AddAttackConditionModifiers(modifiers,
+				rulescombat.CollectConditions(caster, currentParticipant.Conditions),
+				rulescombat.CollectConditions(target, participantConditions(combat, target.ID)),
+				s.participantDistance(ctx, combat, caster.ID, target.ID),
+				spell.AttackType != models.SpellAttackMelee)
+			roll, hit, crit := rulescombat.ResolveSpellAttackWithCover(caster, target, modifiers, cover, s.roller)
+			targetResult.AttackModifiers = modifiers
+			targetResult.AttackRoll = roll
+			targetResult.Hit = hit
+			targetResult.Crit = crit
+		}
+
+		// 豁免检定
+		if spell.RequiresSave() {
+			modifiers := rulescombat.NewRollModifiers(false, false)
+			rulescombat.AddSaveConditionModifiers(modifiers, rulescombat.CollectConditions(target, participantConditions(combat, target.ID)), result.SaveAbility)
+			targetResult.SaveModifiers = modifiers
+			// 掩护为敏捷豁免提供加值
+			saveBonus := 0
+			if result.SaveAbility == "dexterity" {
+				saveBonus = cover.Bonus()
+			}
+			targetResult.SaveRoll = rulescombat.RollSavingThrowWithBonus(target, result.SaveAbility, result.SaveDC, saveBonus, modifiers, s.roller)
+			targetResult.Saved = targetResult.SaveRoll.IsSuccess()
+		}
+
+		// 豁免失败或命中时施加状态（专注法术的状态随专注结束而移除）
+		if targetResult.Hit && !targetResult.Saved {
+			targetResult.ConditionsApplied = s.applySpellConditions(combat, caster, target, spell)
+			change.conditions = targetResult.ConditionsApplied
 		}
 
 		// 投伤害/治疗骰
-		if req.Damage != "" {
-			formula, err := dice.ParseFormula(req.Damage)
-			if err == nil {
-				diceResult := s.roller.RollFormula(formula)
-				targetResult.Damage = diceResult.Total
-
-				// 添加施法属性调整值
-				// 规则参考: PHB 第10章 - Spellcasting Ability
-				abilityMod := s.getSpellcastingModifier(caster)
-				targetResult.Damage += abilityMod
-
-				// 应用伤害或治疗
-				if req.IsHealing {
-					target.Heal(targetResult.Damage)
-				} else {
-					target.TakeDamage(targetResult.Damage)
-				}
+		if targetResult.Hit && len(formulas) > 0 {
+			damageRoll := sharedRoll
+			if damageRoll == nil || targetResult.Crit {
+				damageRoll = rulescombat.RollFormulas(formulas, modifier, targetResult.Crit, s.roller)
+			}
+			targetResult.DamageRoll = damageRoll
+			amount := damageRoll.Total
+			if amount < 0 {
+				amount = 0
+			}
 
-				// 更新目标
-				if err := s.characterStore.Update(ctx, target); err != nil {
-					continue
+			if result.IsHealing {
+				targetResult.RawDamage = amount
+				targetResult.Damage = amount
+				target.Heal(amount)
+			} else {
+				extra := sharedExtra
+				if sharedRoll == nil || targetResult.Crit {
+					extra = s.rollExtraDamage(extraDamage, targetResult.Crit)
+				}
+				instances := append([]rulescombat.DamageInstance{{DamageType: damageType, Amount: amount}}, extra...)
+
+				// 豁免成功：减半或无伤害
+				// 规则参考: PHB 第10章 - Saving Throws
+				if targetResult.Saved {
+					for i := range instances {
+						if spell.Save.DamageHalf {
+							instances[i].Amount = instances[i].Amount / 2
+						} else {
+							instances[i].Amount = 0
+						}
+					}
 				}
 
-				// 记录 HP
-				if target.HP != nil {
-					hpCopy := *target.HP
-					targetResult.TargetHP = &hpCopy
+				// 应用免疫、抗性和易伤
+				// 规则参考: PHB 第9章 - Damage Resistance and Vulnerability
+				application := rulescombat.ApplyDamageModifiers(target, instances)
+				targetResult.RawDamage = application.Before
+				targetResult.Damage = application.After
+				targetResult.DamageBreakdown = application.Breakdown
+				target.TakeDamage(targetResult.Damage)
+				if check := s.checkConcentration(combat, target, targetResult.Damage); check != nil {
+					concentrationChecks = append(concentrationChecks, check)
 				}
 			}
+
+			change.affected = true
 		}
+		changes = append(changes, change)
 
-		result.Results = append(result.Results, targetResult)
-		if !req.IsHealing {
-			result.Damage += targetResult.Damage
-		} else {
-			result.Damage += targetResult.Damage // 治疗量也记录在 Damage 字段
+		// 记录 HP
+		if target.HP != nil {
+			hpCopy := *target.HP
+			targetResult.TargetHP = &hpCopy
 		}
+
+		result.Results = append(result.Results, targetResult)
+		result.Damage += targetResult.Damage // 治疗量也记录在 Damage 字段
 	}
 
-	// 8. 记录战斗日志
+	// 11. 记录战斗日志
 	action := "spell_cast"
-	if req.IsHealing {
+	if result.IsHealing {
 		action = "healing_spell"
 	}
-	resultDesc := fmt.Sprintf("cast %s", req.SpellName)
+	resultDesc := fmt.Sprintf("cast %s", spell.Name)
+	if castLevel > 0 {
+		resultDesc += fmt.Sprintf(" at level %d", castLevel)
+	}
 	if result.Damage > 0 {
-		if req.IsHealing {
+		if result.IsHealing {
 			resultDesc += fmt.Sprintf(", healed %d", result.Damage)
 		} else {
-			resultDesc += fmt.Sprintf(", dealt %d %s damage", result.Damage, req.DamageType)
+			resultDesc += fmt.Sprintf(", dealt %d %s damage", result.Damage, result.DamageType)
 		}
 	}
 	combat.AddLogEntry(req.CasterID, action, "", resultDesc)
 
-	// 9. 保存战斗状态
-	if err := s.combatStore.Update(ctx, combat); err != nil {
-		return nil, fmt.Errorf("failed to update combat: %w", err)
+	// 12. 在一个事务中保存施法者的法术位和专注状态、受影响的目标和战斗状态
+	err = inTx(ctx, s.transactor, func(ctx context.Context) error {
+		if result.SlotUsed || spell.Concentration {
+			if err := s.characterStore.Update(ctx, caster); err != nil {
+				return fmt.Errorf("failed to update caster: %w", err)
+			}
+		}
+		for _, change := range changes {
+			if change.affected {
+				if err := s.characterStore.Update(ctx, change.target); err != nil {
+					return fmt.Errorf("failed to update target %s: %w", change.target.ID, err)
+				}
+			}
+		}
+		if err := s.combatStore.Update(ctx, combat); err != nil {
+			return fmt.Errorf("failed to update combat: %w", err)
+		}
+
+		for _, change := range changes {
+			s.emitConditions(ctx, change.target, models.EventConditionApplied, change.conditions, spell.Name)
+			if change.affected {
+				s.emitHPChanged(ctx, change.target, change.before, spell.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &CastSpellResponse{
-		Result: result,
-		Combat: combat,
+		Result:              result,
+		Combat:              combat,
+		ConcentrationChecks: concentrationChecks,
 	}, nil
 }
 
+// spellTargetChange 施法对一个目标造成、等待保存的变化
+type spellTargetChange struct {
+	target     *models.Character
+	before     models.HP // 施法前的 HP
+	affected   bool      // 受到伤害或治疗
+	conditions []string  // 施加的状态
+}
+
+// applySpellConditions 对目标施加法术的状态效果，跳过目标免疫的状态
+// 专注法术的状态持续到施法者失去专注；其他法术按持续时间计算回合数
+// 规则参考: PHB 第10章 - Duration / Concentration
+func (s *CombatService) applySpellConditions(combat *models.Combat, caster, target *models.Character, spell *models.Spell) []string {
+	participant := combat.GetParticipantByCharacterID(target.ID)
+	if participant == nil || len(spell.Conditions) == 0 {
+		return nil
+	}
+
+	applied := make([]string, 0, len(spell.Conditions))
+	for _, condition := range spell.Conditions {
+		if !models.IsValidConditionType(condition) {
+			continue
+		}
+		if target.Traits != nil && target.Traits.HasConditionImmunity(condition) {
+			continue
+		}
+
+		if spell.Concentration {
+			participant.AddConcentrationCondition(condition, spell.Name, caster.ID, concentrationKey(spell))
+		} else {
+			duration := rulescombat.ParseDurationRounds(spell.Duration)
+			if duration == 0 {
+				duration = -1
+			}
+			participant.AddCondition(condition, duration, spell.Name)
+		}
+		applied = append(applied, condition)
+		combat.AddLogEntry(caster.ID, "condition_applied", target.ID, fmt.Sprintf("%s from %s", condition, spell.Name))
+	}
+	return applied
+}
+
+// rollExtraDamage 投掷法术的附加伤害（暴击时骰子翻倍）
+func (s *CombatService) rollExtraDamage(parts []models.DamagePart, isCrit bool) []rulescombat.DamageInstance {
+	instances := make([]rulescombat.DamageInstance, 0, len(parts))
+	for _, part := range parts {
+		if part.Damage == "" {
+			continue
+		}
+		roll := rulescombat.RollFormulas([]string{part.Damage}, 0, isCrit, s.roller)
+		instances = append(instances, rulescombat.DamageInstance{DamageType: part.DamageType, Amount: roll.Total})
+	}
+	return instances
+}
+
+// resolveSpell 查找施法者要施放的法术
+// 施法者有法术书记录时，法术必须已知（准备施法者还需已准备）；
+// 否则根据请求中的伤害/治疗参数构造一个临时法术
+// 规则参考: PHB 第10章 - Known and Prepared Spells
+func (s *CombatService) resolveSpell(caster *models.Character, req *CastSpellRequest) (*models.Spell, bool, error) {
+	ref := req.SpellID
+	if ref == "" {
+		ref = req.SpellName
+	}
+
+	spellbook := caster.Spellbook
+	if spellbook != nil && spellbook.HasSpellList() {
+		spell := spellbook.FindSpell(req.SpellID)
+		if spell == nil {
+			spell = spellbook.FindSpell(req.SpellName)
+		}
+
+		if spell == nil {
+			if !spellbook.IsSpellKnown(ref) {
+				return nil, false, NewServiceError(ErrCodeInvalidInput,
+					fmt.Sprintf("%s does not know the spell %s", caster.Name, ref))
+			}
+			// 已知但法术书中没有详细数据，使用请求参数；必须给出环阶，
+			// 否则会被当作戏法施放而不消耗法术位
+			if req.Level < 1 {
+				return nil, false, NewServiceError(ErrCodeInvalidInput,
+					fmt.Sprintf("%s has no data for %s; give its spell level (1-9) to cast it", caster.Name, ref))
+			}
+			spell = adHocSpell(req)
+			spell.ID = ref
+		}
+
+		if !spell.IsCantrip() && spellbook.RequiresPreparation() && !spellbook.IsSpellPrepared(spell.ID) {
+			return nil, false, NewServiceError(ErrCodeInvalidState,
+				fmt.Sprintf("%s has not prepared %s", caster.Name, spell.Name))
+		}
+
+		return spell, true, nil
+	}
+
+	return adHocSpell(req), false, nil
+}
+
+// adHocSpell 根据请求参数构造临时法术（无豁免、自动命中）
+func adHocSpell(req *CastSpellRequest) *models.Spell {
+	spell := &models.Spell{
+		ID:    req.SpellID,
+		Name:  req.SpellName,
+		Level: req.Level,
+	}
+	if spell.Name == "" {
+		spell.Name = req.SpellID
+	}
+
+	if req.Damage != "" {
+		if req.IsHealing {
+			spell.Healing = &models.SpellHealing{BaseHealing: req.Damage, SpellMod: true}
+		} else {
+			spell.Damage = &models.SpellDamage{BaseDamage: req.Damage, DamageType: req.DamageType, SpellMod: true}
+		}
+	} else if req.IsHealing {
+		spell.Healing = &models.SpellHealing{}
+	}
+
+	if req.SaveAbility != "" {
+		spell.Save = &models.SpellSave{Ability: req.SaveAbility, DamageHalf: true}
+	}
+	spell.Concentration = req.Concentration
+	spell.Conditions = req.Conditions
+
+	return spell
+}
+
 // AdvanceTurnRequest 推进回合请求
 type AdvanceTurnRequest struct {
 	CombatID string `json:"combat_id"`
@@ -462,9 +1057,10 @@ type AdvanceTurnRequest struct {
 
 // AdvanceTurnResponse 推进回合响应
 type AdvanceTurnResponse struct {
-	Combat          *models.Combat `json:"combat"`
-	NewRound        bool           `json:"new_round"`
-	CurrentTurnName string         `json:"current_turn_name"`
+	Combat          *models.Combat     `json:"combat"`
+	NewRound        bool               `json:"new_round"`
+	CurrentTurnName string             `json:"current_turn_name"`
+	DeathSave       *DeathSaveResponse `json:"death_save,omitempty"` // 濒死角色回合开始时的死亡豁免
 }
 
 // AdvanceTurn 推进回合
@@ -484,16 +1080,26 @@ func (s *CombatService) AdvanceTurn(ctx context.Context, combatID string) (*Adva
 		return nil, NewServiceError(ErrCodeInvalidState, "combat is not active")
 	}
 
+	// 玩家只能结束自己角色的回合
+	if err := s.authorizeCurrentTurn(ctx, combat); err != nil {
+		return nil, err
+	}
+
 	// 推进回合
 	newRound := combat.AdvanceTurn()
 
-	// 推进所有参战者状态持续时间
+	// 推进所有参战者状态持续时间，未执行的借机攻击随回合结束失效
+	expiredConditions := make(map[string][]string)
 	for i := range combat.Participants {
+		combat.Participants[i].OpportunityTargets = nil
 		expired := combat.Participants[i].TickConditions()
 		for _, cond := range expired {
 			combat.AddLogEntry(combat.Participants[i].CharacterID, "condition_expired", "",
 				fmt.Sprintf("condition %s expired", cond))
 		}
+		if len(expired) > 0 {
+			expiredConditions[combat.Participants[i].CharacterID] = expired
+		}
 	}
 
 	// 记录日志
@@ -501,25 +1107,84 @@ func (s *CombatService) AdvanceTurn(ctx context.Context, combatID string) (*Adva
 		combat.AddLogEntry("", "new_round", "", fmt.Sprintf("Round %d begins", combat.Round))
 	}
 
-	// 获取当前行动者名称
+	// 获取当前行动者名称，并重置其行动资源
+	// 规则参考: PHB 第9章 - Your Turn
 	currentParticipant := combat.GetCurrentParticipant()
 	currentTurnName := ""
+	var deathSave *DeathSaveResponse
 	if currentParticipant != nil {
+		speed := defaultSpeed
+		if currentParticipant.Actions != nil {
+			speed = currentParticipant.Actions.Speed
+		}
 		char, err := s.characterStore.Get(ctx, currentParticipant.CharacterID)
 		if err == nil {
 			currentTurnName = char.Name
+			speed = characterSpeed(char)
+
+			// 专注法术持续时间在施法者回合开始时推进
+			// 规则参考: PHB 第10章 - Duration / Concentration
+			if char.Spellbook != nil && char.Spellbook.IsConcentrating() {
+				spellID := char.Spellbook.ConcentrationSpell
+				if char.Spellbook.TickConcentration() {
+					combat.AddLogEntry(char.ID, "concentration_ended", "", fmt.Sprintf("%s expired", spellID))
+					if err := s.characterStore.Update(ctx, char); err != nil {
+						return nil, fmt.Errorf("failed to update character: %w", err)
+					}
+				}
+			}
+
+			// 濒死的玩家角色在回合开始时自动进行死亡豁免
+			// 规则参考: PHB 第9章 - Death Saving Throws
+			if !char.IsNPC && char.IsUnconscious() {
+				before := hpSnapshot(char)
+				deathSave = resolveDeathSave(char, s.roller.Roll(20))
+				combat.AddLogEntry(char.ID, "death_save", "", deathSave.Summary())
+				if err := s.characterStore.Update(ctx, char); err != nil {
+					return nil, fmt.Errorf("failed to update character: %w", err)
+				}
+				s.emitHPChanged(ctx, char, before, "death_save")
+			}
 		}
+		currentParticipant.StartTurn(speed)
 	}
 
+	// 移除已失去专注的法术所维持的状态
+	s.releaseOrphanedConditions(ctx, combat)
+
 	// 保存战斗
 	if err := s.combatStore.Update(ctx, combat); err != nil {
 		return nil, fmt.Errorf("failed to update combat: %w", err)
 	}
 
+	for _, participant := range combat.Participants {
+		if expired := expiredConditions[participant.CharacterID]; len(expired) > 0 {
+			s.emit(ctx, combat.CampaignID, models.EventConditionRemoved, map[string]interface{}{
+				"character_id": participant.CharacterID,
+				"combat_id":    combat.ID,
+				"conditions":   expired,
+				"source":       "expired",
+			})
+		}
+	}
+	currentCharacterID := ""
+	if currentParticipant != nil {
+		currentCharacterID = currentParticipant.CharacterID
+	}
+	s.emit(ctx, combat.CampaignID, models.EventTurnAdvanced, map[string]interface{}{
+		"combat_id":    combat.ID,
+		"round":        combat.Round,
+		"turn_index":   combat.TurnIndex,
+		"new_round":    newRound,
+		"character_id": currentCharacterID,
+		"name":         currentTurnName,
+	})
+
 	return &AdvanceTurnResponse{
 		Combat:          combat,
 		NewRound:        newRound,
 		CurrentTurnName: currentTurnName,
+		DeathSave:       deathSave,
 	}, nil
 }
 
@@ -545,6 +1210,11 @@ func (s *CombatService) EndCombat(ctx context.Context, combatID string) (*models
 		return nil, NewServiceError(ErrCodeInvalidState, "combat is not active")
 	}
 
+	// 只有战役的 DM 可以结束战斗
+	if err := s.authorizeCombatDM(ctx, combat); err != nil {
+		return nil, err
+	}
+
 	// 结束战斗
 	combat.End()
 	combat.AddLogEntry("", "combat_end", "", "Combat ended")
@@ -570,6 +1240,80 @@ func (s *CombatService) EndCombat(ctx context.Context, combatID string) (*models
 	return combat, nil
 }
 
+// applyTurnActionEffects 根据闪避、协助和躲藏调整攻击的优势/劣势
+// 攻击后协助效果被消耗，攻击者的躲藏状态解除
+// 规则参考: PHB 第9章 - Dodge / Help / Unseen Attackers and Targets
+func (s *CombatService) applyTurnActionEffects(combat *models.Combat, attacker, target *models.Participant, modifiers *rulescombat.RollModifiers) {
+	if target.Actions != nil && target.Actions.Dodging {
+		modifiers.AddDisadvantage("target is dodging")
+	}
+
+	if attacker.Hidden {
+		modifiers.AddAdvantage("attacker is hidden")
+		attacker.Hidden = false
+	}
+
+	for i := range combat.Participants {
+		helper := combat.Participants[i].Actions
+		if helper == nil || helper.HelpingID != attacker.CharacterID {
+			continue
+		}
+		if helper.HelpAgainstID != "" && helper.HelpAgainstID != target.CharacterID {
+			continue
+		}
+		modifiers.AddAdvantage(fmt.Sprintf("helped by %s", combat.Participants[i].CharacterID))
+		helper.HelpingID = ""
+		helper.HelpAgainstID = ""
+		break
+	}
+}
+
+// participantDistance 根据战斗地图上的 Token 位置计算两名参战者的距离（英尺）
+// 没有地图或找不到 Token 时返回 movement.DistanceUnknown
+func (s *CombatService) participantDistance(ctx context.Context, combat *models.Combat, fromID, toID string) int {
+	battleMap := s.combatMap(ctx, combat)
+	if battleMap == nil {
+		return movement.DistanceUnknown
+	}
+
+	cellSize := 5
+	if battleMap.Grid != nil && battleMap.Grid.CellSize > 0 {
+		cellSize = battleMap.Grid.CellSize
+	}
+	return movement.TokenDistance(battleMap.GetTokenByCharacterID(fromID), battleMap.GetTokenByCharacterID(toID), cellSize)
+}
+
+// participantCover 根据战斗地图上的墙壁和其他生物计算目标相对攻击者的掩护
+// 没有地图或找不到 Token 时视为无掩护
+// 规则参考: PHB 第9章 - Cover
+func (s *CombatService) participantCover(ctx context.Context, combat *models.Combat, attackerID, targetID string) rulescombat.CoverLevel {
+	battleMap := s.combatMap(ctx, combat)
+	if battleMap == nil {
+		return rulescombat.CoverNone
+	}
+	return rulescombat.CalculateCover(battleMap, battleMap.GetTokenByCharacterID(attackerID), battleMap.GetTokenByCharacterID(targetID))
+}
+
+// combatMap 获取战斗所在的战斗地图，未设置时返回 nil
+func (s *CombatService) combatMap(ctx context.Context, combat *models.Combat) *models.Map {
+	if s.mapStore == nil || combat.MapID == "" {
+		return nil
+	}
+	battleMap, err := s.mapStore.Get(ctx, combat.MapID)
+	if err != nil {
+		return nil
+	}
+	return battleMap
+}
+
+// participantConditions 获取参战者在战斗中的临时状态
+func participantConditions(combat *models.Combat, characterID string) []models.Condition {
+	if participant := combat.GetParticipantByCharacterID(characterID); participant != nil {
+		return participant.Conditions
+	}
+	return nil
+}
+
 // getEquippedWeapon 获取角色装备的武器
 func (s *CombatService) getEquippedWeapon(character *models.Character) *models.EquipmentItem {
 	if character.EquipmentSlots == nil {
@@ -604,31 +1348,6 @@ func (s *CombatService) getEquippedWeapon(character *models.Character) *models.E
 	return nil
 }
 
-// getSpellcastingModifier 获取施法属性调整值
-// 规则参考: PHB 第10章 - Spellcasting Ability
-func (s *CombatService) getSpellcastingModifier(character *models.Character) int {
-	if character == nil || character.Abilities == nil {
-		return 0
-	}
-
-	// 根据职业决定施法属性
-	// 规则参考: PHB 第10章 - Spellcasting Ability
-	class := character.Class
-	switch class {
-	case "Bard", "Sorcerer", "Warlock":
-		return GetAbilityModifier(character.Abilities.Charisma)
-	case "Cleric", "Druid", "Ranger":
-		return GetAbilityModifier(character.Abilities.Wisdom)
-	case "Wizard", "Artificer":
-		return GetAbilityModifier(character.Abilities.Intelligence)
-	case "Paladin":
-		return GetAbilityModifier(character.Abilities.Charisma)
-	default:
-		// 默认使用魅力（适用于邪术师、吟游诗人等）
-		return GetAbilityModifier(character.Abilities.Charisma)
-	}
-}
-
 // CombatSummary 战斗统计
 type CombatSummary struct {
 	TotalRounds  int                  `json:"total_rounds"`
@@ -673,6 +1392,11 @@ func (s *CombatService) EndCombatWithSummary(ctx context.Context, combatID strin
 		return nil, NewServiceError(ErrCodeInvalidState, "combat is not active")
 	}
 
+	// 只有战役的 DM 可以结束战斗
+	if err := s.authorizeCombatDM(ctx, combat); err != nil {
+		return nil, err
+	}
+
 	// 结束战斗
 	combat.End()
 	combat.AddLogEntry("", "combat_end", "", "Combat ended")
@@ -0,0 +1,165 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/models"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
+)
+
+// ConcentrationCheck 专注检定结果
+// 规则参考: PHB 第10章 - Concentration
+type ConcentrationCheck struct {
+	CharacterID       string              `json:"character_id"`
+	SpellID           string              `json:"spell_id"`                     // 专注的法术
+	Damage            int                 `json:"damage,omitempty"`             // 触发检定的伤害
+	DC                int                 `json:"dc,omitempty"`                 // 体质豁免DC
+	Save              *models.CheckResult `json:"save,omitempty"`               // 体质豁免（失能或HP为0时不投骰）
+	Maintained        bool                `json:"maintained"`                   // 是否维持专注
+	Reason            string              `json:"reason,omitempty"`             // 失去专注的原因
+	RemovedConditions []RemovedCondition  `json:"removed_conditions,omitempty"` // 专注结束后移除的状态
+}
+
+// RemovedCondition 专注结束时从生物身上移除的状态
+type RemovedCondition struct {
+	CharacterID string `json:"character_id"`
+	Condition   string `json:"condition"`
+}
+
+// concentrationKey 获取法术用于专注记录的标识
+func concentrationKey(spell *models.Spell) string {
+	if spell.ID != "" {
+		return spell.ID
+	}
+	return spell.Name
+}
+
+// startConcentration 开始专注一个法术
+// 已在专注其他法术时，先结束原有专注
+// 规则参考: PHB 第10章 - Concentration（同一时间只能专注一个法术）
+func (s *CombatService) startConcentration(combat *models.Combat, caster *models.Character, spell *models.Spell) *ConcentrationCheck {
+	var ended *ConcentrationCheck
+	spellbook := caster.GetSpellbook()
+	if spellbook.IsConcentrating() {
+		ended = &ConcentrationCheck{
+			CharacterID: caster.ID,
+			SpellID:     spellbook.ConcentrationSpell,
+			Reason:      fmt.Sprintf("cast %s", spell.Name),
+		}
+		ended.RemovedConditions = s.endConcentration(combat, caster, ended.Reason)
+	}
+
+	spellbook.StartConcentration(concentrationKey(spell), rulescombat.ParseDurationRounds(spell.Duration))
+	combat.AddLogEntry(caster.ID, "concentration", "", fmt.Sprintf("concentrating on %s", spell.Name))
+	return ended
+}
+
+// checkConcentration 角色受到伤害后进行专注检定
+// 失能或生命值降为0时直接失去专注，否则进行 DC max(10, 伤害/2) 的体质豁免
+// 角色未在专注或未受伤害时返回 nil
+// 规则参考: PHB 第10章 - Concentration
+func (s *CombatService) checkConcentration(combat *models.Combat, character *models.Character, damage int) *ConcentrationCheck {
+	if damage <= 0 || character.Spellbook == nil || !character.Spellbook.IsConcentrating() {
+		return nil
+	}
+
+	check := &ConcentrationCheck{
+		CharacterID: character.ID,
+		SpellID:     character.Spellbook.ConcentrationSpell,
+		Damage:      damage,
+		Maintained:  true,
+	}
+
+	conditions := rulescombat.CollectConditions(character, participantConditions(combat, character.ID))
+	if lost, reason := rulescombat.LosesConcentration(character, conditions); lost {
+		check.Maintained = false
+		check.Reason = reason
+	} else {
+		check.DC = rulescombat.ConcentrationSaveDC(damage)
+		modifiers := rulescombat.NewRollModifiers(false, false)
+		rulescombat.AddSaveConditionModifiers(modifiers, conditions, "constitution")
+		check.Save = rulescombat.RollSavingThrowWithModifiers(character, "constitution", check.DC, modifiers, s.roller)
+		if !check.Save.IsSuccess() {
+			check.Maintained = false
+			check.Reason = fmt.Sprintf("failed DC %d Constitution save (%d)", check.DC, check.Save.DiceResult.Total)
+		}
+	}
+
+	if check.Maintained {
+		combat.AddLogEntry(character.ID, "concentration", "",
+			fmt.Sprintf("maintained concentration on %s (DC %d, rolled %d)", check.SpellID, check.DC, check.Save.DiceResult.Total))
+		return check
+	}
+
+	check.RemovedConditions = s.endConcentration(combat, character, check.Reason)
+	return check
+}
+
+// endConcentration 结束角色的专注，并移除其专注法术施加在其他生物上的状态
+func (s *CombatService) endConcentration(combat *models.Combat, caster *models.Character, reason string) []RemovedCondition {
+	if caster.Spellbook == nil || !caster.Spellbook.IsConcentrating() {
+		return nil
+	}
+
+	spellID := caster.Spellbook.ConcentrationSpell
+	caster.Spellbook.EndConcentration()
+	combat.AddLogEntry(caster.ID, "concentration_ended", "", fmt.Sprintf("lost concentration on %s: %s", spellID, reason))
+
+	return releaseConcentrationConditions(combat, caster.ID, spellID)
+}
+
+// releaseConcentrationConditions 移除战斗中由指定专注法术维持的状态
+func releaseConcentrationConditions(combat *models.Combat, casterID, spellID string) []RemovedCondition {
+	removed := make([]RemovedCondition, 0)
+	for i := range combat.Participants {
+		participant := &combat.Participants[i]
+		for _, condition := range participant.RemoveConcentrationConditions(casterID, spellID) {
+			removed = append(removed, RemovedCondition{CharacterID: participant.CharacterID, Condition: condition})
+			combat.AddLogEntry(participant.CharacterID, "condition_removed", "",
+				fmt.Sprintf("condition %s ended with %s's concentration", condition, casterID))
+		}
+	}
+	return removed
+}
+
+// releaseOrphanedConditions 清理施法者已不再专注的法术所维持的状态
+// 处理专注到期、在战斗外被失能等情况
+func (s *CombatService) releaseOrphanedConditions(ctx context.Context, combat *models.Combat) {
+	linked := make(map[string]map[string]bool) // casterID -> spellIDs
+	for _, participant := range combat.Participants {
+		for _, cond := range participant.Conditions {
+			if cond.CasterID == "" {
+				continue
+			}
+			if linked[cond.CasterID] == nil {
+				linked[cond.CasterID] = make(map[string]bool)
+			}
+			linked[cond.CasterID][cond.SpellID] = true
+		}
+	}
+
+	for casterID, spells := range linked {
+		caster, err := s.characterStore.Get(ctx, casterID)
+		if err != nil {
+			continue
+		}
+
+		// 失能的施法者失去专注
+		conditions := rulescombat.CollectConditions(caster, participantConditions(combat, casterID))
+		if lost, reason := rulescombat.LosesConcentration(caster, conditions); lost && caster.Spellbook != nil && caster.Spellbook.IsConcentrating() {
+			s.endConcentration(combat, caster, reason)
+			if err := s.characterStore.Update(ctx, caster); err != nil {
+				continue
+			}
+		}
+
+		for spellID := range spells {
+			if caster.Spellbook != nil && caster.Spellbook.ConcentrationSpell == spellID {
+				continue
+			}
+			releaseConcentrationConditions(combat, casterID, spellID)
+		}
+	}
+}
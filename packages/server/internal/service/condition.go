@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/dnd-mcp/server/internal/models"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
 )
 
 // CharacterStoreForCondition defines the character store interface needed by condition service
@@ -17,6 +18,7 @@ type CharacterStoreForCondition interface {
 // ConditionService provides condition effect business logic
 // 规则参考: PHB 附录A - Conditions
 type ConditionService struct {
+	eventEmitter
 	characterStore CharacterStoreForCondition
 }
 
@@ -150,16 +152,28 @@ func (s *ConditionService) ApplyCondition(ctx context.Context, req *ApplyConditi
 		character.AddCondition(req.ConditionType, duration, source)
 	}
 
+	message := fmt.Sprintf("Applied %s to character", req.ConditionType)
+
+	// 失能状态使角色失去专注（其法术维持的状态在下次推进回合时移除）
+	// 规则参考: PHB 第10章 - Concentration
+	if character.Spellbook != nil && character.Spellbook.IsConcentrating() {
+		if lost, reason := rulescombat.LosesConcentration(character, character.Conditions); lost {
+			message += fmt.Sprintf("; concentration on %s ended (%s)", character.Spellbook.ConcentrationSpell, reason)
+			character.Spellbook.EndConcentration()
+		}
+	}
+
 	// 7. 保存角色
 	if err := s.characterStore.Update(ctx, character); err != nil {
 		return nil, fmt.Errorf("failed to update character: %w", err)
 	}
+	s.emitConditions(ctx, character, models.EventConditionApplied, []string{req.ConditionType}, req.Source)
 
 	return &ApplyConditionResponse{
 		Character:  character,
 		Applied:    true,
 		Conditions: character.Conditions,
-		Message:    fmt.Sprintf("Applied %s to character", req.ConditionType),
+		Message:    message,
 	}, nil
 }
 
@@ -195,11 +209,15 @@ func (s *ConditionService) RemoveCondition(ctx context.Context, req *RemoveCondi
 
 	removed := false
 	var message string
+	var removedTypes []string
 
 	// 3. 移除状态
 	if req.RemoveAll {
 		// 移除所有状态
 		if len(character.Conditions) > 0 {
+			for _, cond := range character.Conditions {
+				removedTypes = append(removedTypes, cond.Type)
+			}
 			character.Conditions = make([]models.Condition, 0)
 			removed = true
 			message = "Removed all conditions"
@@ -210,6 +228,7 @@ func (s *ConditionService) RemoveCondition(ctx context.Context, req *RemoveCondi
 		// 移除特定状态
 		removed = character.RemoveCondition(req.ConditionType)
 		if removed {
+			removedTypes = []string{req.ConditionType}
 			message = fmt.Sprintf("Removed %s", req.ConditionType)
 		} else {
 			message = fmt.Sprintf("Character does not have %s", req.ConditionType)
@@ -223,6 +242,7 @@ func (s *ConditionService) RemoveCondition(ctx context.Context, req *RemoveCondi
 		if err := s.characterStore.Update(ctx, character); err != nil {
 			return nil, fmt.Errorf("failed to update character: %w", err)
 		}
+		s.emitConditions(ctx, character, models.EventConditionRemoved, removedTypes, "")
 	}
 
 	return &RemoveConditionResponse{
@@ -8,13 +8,21 @@ import (
 
 	"github.com/dnd-mcp/server/internal/models"
 	"github.com/dnd-mcp/server/internal/rules"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
 	"github.com/dnd-mcp/server/internal/rules/dice"
 )
 
+// CombatStoreForDice defines the combat store interface needed by dice service
+type CombatStoreForDice interface {
+	GetActive(ctx context.Context, campaignID string) (*models.Combat, error)
+}
+
 // DiceService provides dice rolling and check functionality
 // 规则参考: PHB 第7章 Ability Checks, 第9章 Combat
 type DiceService struct {
+	eventEmitter
 	characterStore CharacterStore
+	combatStore    CombatStoreForDice
 	roller         *dice.Roller
 }
 
@@ -34,9 +42,17 @@ func NewDiceServiceWithRoller(characterStore CharacterStore, roller *dice.Roller
 	}
 }
 
+// SetCombatStore makes checks and saves apply the conditions a character has as a
+// participant of its campaign's active combat, not only the conditions on the character.
+func (s *DiceService) SetCombatStore(combatStore CombatStoreForDice) {
+	s.combatStore = combatStore
+}
+
 // RollDiceRequest represents a dice roll request
 type RollDiceRequest struct {
-	Formula string `json:"formula"` // 骰子公式（如 "1d20+5", "2d6", "4d6kh3"）
+	Formula    string `json:"formula" jsonschema:"required,minLength=1" description:"The dice formula to roll (e.g., '1d20+5', '2d6', '4d6kh3')"` // 骰子公式（如 "1d20+5", "2d6", "4d6kh3"）
+	CampaignID string `json:"campaign_id,omitempty" description:"Optional campaign ID; when set, players in the campaign see the roll"`            // 战役ID（可选，设置后向客户端推送掷骰事件）
+	Reason     string `json:"reason,omitempty" description:"Optional reason shown with the roll (e.g., 'wandering monster check')"`                // 掷骰原因（可选）
 }
 
 // RollDiceResponse represents a dice roll response
@@ -59,6 +75,14 @@ func (s *DiceService) RollDice(ctx context.Context, req *RollDiceRequest) (*Roll
 
 	// Roll the dice
 	result := s.roller.RollFormula(formula)
+	s.emit(ctx, req.CampaignID, models.EventDiceRolled, map[string]interface{}{
+		"roll_type": "dice",
+		"formula":   result.Formula,
+		"rolls":     result.Rolls,
+		"modifier":  result.Modifier,
+		"total":     result.Total,
+		"reason":    req.Reason,
+	})
 
 	return &RollDiceResponse{
 		Result: result,
@@ -67,17 +91,18 @@ func (s *DiceService) RollDice(ctx context.Context, req *RollDiceRequest) (*Roll
 
 // RollCheckRequest represents an ability/skill check request
 type RollCheckRequest struct {
-	CharacterID string `json:"character_id"` // 角色ID
-	Ability     string `json:"ability"`      // 属性（strength, dexterity, etc.）
-	Skill       string `json:"skill"`        // 技能（可选，如 athletics, stealth）
-	DC          int    `json:"dc"`           // 难度等级（可选）
-	Advantage   bool   `json:"advantage"`    // 是否优势
-	Disadvantage bool  `json:"disadvantage"` // 是否劣势
+	CharacterID  string `json:"character_id" jsonschema:"required" description:"The ID of the character making the check"`                                                    // 角色ID
+	Ability      string `json:"ability" jsonschema:"required" description:"The ability to use (strength, dexterity, constitution, intelligence, wisdom, charisma)"`           // 属性（strength, dexterity, etc.）
+	Skill        string `json:"skill" description:"Optional skill to apply proficiency bonus (e.g., athletics, stealth, perception)"`                                           // 技能（可选，如 athletics, stealth）
+	DC           int    `json:"dc" jsonschema:"minimum=0" description:"Optional difficulty class (DC) to compare against"`                                                     // 难度等级（可选）
+	Advantage    bool   `json:"advantage" description:"Roll with advantage (roll 2d20, take higher)"`                                                                          // 是否优势
+	Disadvantage bool   `json:"disadvantage" description:"Roll with disadvantage (roll 2d20, take lower)"`                                                                     // 是否劣势
 }
 
 // RollCheckResponse represents an ability/skill check response
 type RollCheckResponse struct {
-	Result *models.CheckResult `json:"result"` // 检定结果
+	Result    *models.CheckResult        `json:"result"`              // 检定结果
+	Modifiers *rulescombat.RollModifiers `json:"modifiers,omitempty"` // 优势/劣势来源（含状态效果）
 }
 
 // RollCheck performs an ability or skill check
@@ -101,20 +126,19 @@ func (s *DiceService) RollCheck(ctx context.Context, req *RollCheckRequest) (*Ro
 	if err != nil {
 		return nil, fmt.Errorf("failed to get character: %w", err)
 	}
+	if err := authorizeActor(ctx, character); err != nil {
+		return nil, err
+	}
 
 	// Calculate modifier
 	modifier := s.calculateCheckModifier(character, ability, req.Skill)
 
-	// Roll d20 with advantage/disadvantage
-	var diceResult *models.DiceResult
-	if req.Advantage && !req.Disadvantage {
-		diceResult = s.roller.RollWithAdvantage(modifier)
-	} else if req.Disadvantage && !req.Advantage {
-		diceResult = s.roller.RollWithDisadvantage(modifier)
-	} else {
-		// Normal roll or both advantage/disadvantage (they cancel out)
-		diceResult = s.roller.RollD20(modifier)
-	}
+	// Combine requested advantage/disadvantage with condition effects
+	// (both present cancel out)
+	// 规则参考: PHB 附录A - Conditions
+	modifiers := rulescombat.NewRollModifiers(req.Advantage, req.Disadvantage)
+	rulescombat.AddCheckConditionModifiers(modifiers, s.collectConditions(ctx, character))
+	diceResult := modifiers.RollD20(modifier, s.roller)
 
 	// Create check result
 	checkResult := models.NewCheckResult(diceResult, ability)
@@ -124,24 +148,27 @@ func (s *DiceService) RollCheck(ctx context.Context, req *RollCheckRequest) (*Ro
 	if req.DC > 0 {
 		checkResult.SetDC(req.DC)
 	}
+	s.emitCheck(ctx, character, "check", checkResult)
 
 	return &RollCheckResponse{
-		Result: checkResult,
+		Result:    checkResult,
+		Modifiers: modifiers,
 	}, nil
 }
 
 // RollSaveRequest represents a saving throw request
 type RollSaveRequest struct {
-	CharacterID  string `json:"character_id"`  // 角色ID
-	Ability      string `json:"ability"`       // 属性（strength, dexterity, etc.）
-	DC           int    `json:"dc"`            // 难度等级（可选）
-	Advantage    bool   `json:"advantage"`     // 是否优势
-	Disadvantage bool   `json:"disadvantage"`  // 是否劣势
+	CharacterID  string `json:"character_id" jsonschema:"required" description:"The ID of the character making the save"`                                                        // 角色ID
+	Ability      string `json:"ability" jsonschema:"required" description:"The ability for the saving throw (strength, dexterity, constitution, intelligence, wisdom, charisma)"` // 属性（strength, dexterity, etc.）
+	DC           int    `json:"dc" jsonschema:"minimum=0" description:"Optional difficulty class (DC) to compare against"`                                                         // 难度等级（可选）
+	Advantage    bool   `json:"advantage" description:"Roll with advantage (roll 2d20, take higher)"`                                                                              // 是否优势
+	Disadvantage bool   `json:"disadvantage" description:"Roll with disadvantage (roll 2d20, take lower)"`                                                                         // 是否劣势
 }
 
 // RollSaveResponse represents a saving throw response
 type RollSaveResponse struct {
-	Result *models.CheckResult `json:"result"` // 检定结果
+	Result    *models.CheckResult        `json:"result"`              // 检定结果
+	Modifiers *rulescombat.RollModifiers `json:"modifiers,omitempty"` // 优势/劣势及自动失败来源（含状态效果）
 }
 
 // RollSave performs a saving throw
@@ -165,39 +192,55 @@ func (s *DiceService) RollSave(ctx context.Context, req *RollSaveRequest) (*Roll
 	if err != nil {
 		return nil, fmt.Errorf("failed to get character: %w", err)
 	}
+	if err := authorizeActor(ctx, character); err != nil {
+		return nil, err
+	}
 
 	// Calculate save modifier
 	modifier := s.calculateSaveModifier(character, ability)
 
-	// Roll d20 with advantage/disadvantage
-	var diceResult *models.DiceResult
-	if req.Advantage && !req.Disadvantage {
-		diceResult = s.roller.RollWithAdvantage(modifier)
-	} else if req.Disadvantage && !req.Advantage {
-		diceResult = s.roller.RollWithDisadvantage(modifier)
-	} else {
-		// Normal roll or both advantage/disadvantage (they cancel out)
-		diceResult = s.roller.RollD20(modifier)
-	}
+	// Combine requested advantage/disadvantage with condition effects
+	// (both present cancel out; paralyzed/stunned etc. auto-fail STR/DEX saves)
+	// 规则参考: PHB 附录A - Conditions
+	modifiers := rulescombat.NewRollModifiers(req.Advantage, req.Disadvantage)
+	rulescombat.AddSaveConditionModifiers(modifiers, s.collectConditions(ctx, character), ability)
+	diceResult := modifiers.RollD20(modifier, s.roller)
 
 	// Create check result
 	checkResult := models.NewCheckResult(diceResult, ability)
 	if req.DC > 0 {
 		checkResult.SetDC(req.DC)
 	}
+	if modifiers.AutoFail {
+		checkResult.Success = false
+	}
+	s.emitCheck(ctx, character, "save", checkResult)
 
 	return &RollSaveResponse{
-		Result: checkResult,
+		Result:    checkResult,
+		Modifiers: modifiers,
 	}, nil
 }
 
+// collectConditions returns a character's conditions together with those it has as a
+// participant of its campaign's active combat
+func (s *DiceService) collectConditions(ctx context.Context, character *models.Character) []models.Condition {
+	var extra []models.Condition
+	if s.combatStore != nil && character.CampaignID != "" {
+		if combat, err := s.combatStore.GetActive(ctx, character.CampaignID); err == nil && combat != nil && combat.IsActive() {
+			extra = participantConditions(combat, character.ID)
+		}
+	}
+	return rulescombat.CollectConditions(character, extra)
+}
+
 // RollAttackRequest represents an attack roll request
 type RollAttackRequest struct {
-	CharacterID  string `json:"character_id"`  // 角色ID
-	AttackBonus  int    `json:"attack_bonus"`  // 攻击加值
-	TargetAC     int    `json:"target_ac"`     // 目标AC
-	Advantage    bool   `json:"advantage"`     // 是否优势
-	Disadvantage bool   `json:"disadvantage"`  // 是否劣势
+	CharacterID  string `json:"character_id" description:"Optional ID of the attacking character"`                // 角色ID
+	AttackBonus  int    `json:"attack_bonus" description:"The total attack bonus to add to the d20 roll"`         // 攻击加值
+	TargetAC     int    `json:"target_ac" jsonschema:"required,minimum=0" description:"The target's Armor Class"` // 目标AC
+	Advantage    bool   `json:"advantage" description:"Roll with advantage (roll 2d20, take higher)"`             // 是否优势
+	Disadvantage bool   `json:"disadvantage" description:"Roll with disadvantage (roll 2d20, take lower)"`        // 是否劣势
 }
 
 // RollAttackResponse represents an attack roll response
@@ -252,8 +295,8 @@ func (s *DiceService) RollAttack(ctx context.Context, req *RollAttackRequest) (*
 
 // RollDamageRequest represents a damage roll request
 type RollDamageRequest struct {
-	Formula  string `json:"formula"`   // 伤害公式（如 "1d8+3", "2d6"）
-	Crit     bool   `json:"crit"`      // 是否暴击（骰子翻倍）
+	Formula  string `json:"formula" jsonschema:"required" description:"Damage dice formula (e.g., '1d8+3', '2d6')"` // 伤害公式（如 "1d8+3", "2d6"）
+	Crit     bool   `json:"crit" description:"Whether the attack was a critical hit (damage dice are doubled)"`     // 是否暴击（骰子翻倍）
 }
 
 // RollDamageResponse represents a damage roll response
@@ -289,6 +332,26 @@ func (s *DiceService) RollDamage(ctx context.Context, req *RollDamageRequest) (*
 	}, nil
 }
 
+// emitCheck publishes an ability check or saving throw roll to the character's campaign
+func (s *DiceService) emitCheck(ctx context.Context, character *models.Character, rollType string, result *models.CheckResult) {
+	data := map[string]interface{}{
+		"roll_type":    rollType,
+		"character_id": character.ID,
+		"name":         character.Name,
+		"ability":      result.Ability,
+		"skill":        result.Skill,
+		"dc":           result.DC,
+		"success":      result.Success,
+	}
+	if result.DiceResult != nil {
+		data["formula"] = result.DiceResult.Formula
+		data["rolls"] = result.DiceResult.Rolls
+		data["modifier"] = result.DiceResult.Modifier
+		data["total"] = result.DiceResult.Total
+	}
+	s.emit(ctx, character.CampaignID, models.EventDiceRolled, data)
+}
+
 // calculateCheckModifier calculates the total modifier for an ability/skill check
 // 规则参考: PHB 第7章 Ability Checks
 func (s *DiceService) calculateCheckModifier(character *models.Character, ability string, skill string) int {
@@ -336,29 +399,7 @@ func (s *DiceService) getSkillBonus(character *models.Character, skill string, a
 // calculateSaveModifier calculates the total modifier for a saving throw
 // 规则参考: PHB 第7章 Saving Throws
 func (s *DiceService) calculateSaveModifier(character *models.Character, ability string) int {
-	var modifier int
-
-	// Get ability modifier
-	if character.Abilities != nil {
-		modifier = rules.GetModifierByName(character.Abilities, rules.AbilityName(ability))
-	}
-
-	// Check detailed saves first
-	if character.SavesDetail != nil {
-		if saveDetail, ok := character.SavesDetail[ability]; ok {
-			proficiencyBonus := character.GetProficiencyBonus()
-			return saveDetail.CalculateBonus(modifier, proficiencyBonus)
-		}
-	}
-
-	// Fall back to simple save bonus map
-	if character.Saves != nil {
-		if bonus, ok := character.Saves[ability]; ok {
-			return bonus
-		}
-	}
-
-	return modifier
+	return rulescombat.GetSaveModifier(character, ability)
 }
 
 // isValidAbility checks if the given string is a valid ability name
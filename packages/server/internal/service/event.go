@@ -0,0 +1,220 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+)
+
+// eventSubscriberBuffer is how many undelivered events a subscriber may fall behind
+const eventSubscriberBuffer = 64
+
+// EventPublisher publishes game events
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.GameEvent) error
+}
+
+// EventBus persists game events and fans them out to in-process subscribers
+type EventBus struct {
+	store store.EventStore
+
+	mu          sync.Mutex
+	subscribers map[*EventSubscription]struct{}
+}
+
+// NewEventBus creates a new event bus
+func NewEventBus(eventStore store.EventStore) *EventBus {
+	return &EventBus{
+		store:       eventStore,
+		subscribers: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// Publish stores an event, assigning its cursor, and delivers it to matching subscribers.
+// Inside a transaction the event is stored with the transaction's writes and delivered
+// only once it commits, so subscribers never see events of a rolled back action.
+func (b *EventBus) Publish(ctx context.Context, event *models.GameEvent) error {
+	if event.CampaignID == "" {
+		return NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+
+	if err := b.store.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to append event: %w", err)
+	}
+
+	store.AfterCommit(ctx, func() { b.deliver(event) })
+	return nil
+}
+
+// deliver fans an event out to matching subscribers
+func (b *EventBus) deliver(event *models.GameEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// 订阅者跟不上时断开，由其按游标续传
+			b.unsubscribe(sub)
+		}
+	}
+}
+
+// Subscribe delivers a campaign's new events, optionally limited to some types.
+// The subscription's channel is closed when it is closed or falls too far behind.
+func (b *EventBus) Subscribe(campaignID string, types []models.EventType) *EventSubscription {
+	sub := &EventSubscription{
+		bus:        b,
+		campaignID: campaignID,
+		events:     make(chan *models.GameEvent, eventSubscriberBuffer),
+	}
+	if len(types) > 0 {
+		sub.types = make(map[models.EventType]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Since lists a campaign's stored events after a cursor
+func (b *EventBus) Since(ctx context.Context, campaignID string, after int64, types []models.EventType, limit int) ([]*models.GameEvent, error) {
+	if campaignID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+	if after < 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "cursor cannot be negative")
+	}
+
+	events, err := b.store.List(ctx, &store.EventFilter{
+		CampaignID: campaignID,
+		After:      after,
+		Types:      types,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
+
+// Latest returns the cursor of a campaign's most recent event
+func (b *EventBus) Latest(ctx context.Context, campaignID string) (int64, error) {
+	if campaignID == "" {
+		return 0, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+
+	cursor, err := b.store.Latest(ctx, campaignID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest event: %w", err)
+	}
+	return cursor, nil
+}
+
+// unsubscribe removes a subscriber and closes its channel; the caller holds b.mu
+func (b *EventBus) unsubscribe(sub *EventSubscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+// EventSubscription receives a campaign's events from an EventBus
+type EventSubscription struct {
+	bus        *EventBus
+	campaignID string
+	types      map[models.EventType]bool
+	events     chan *models.GameEvent
+}
+
+// Events returns the channel events are delivered on
+func (s *EventSubscription) Events() <-chan *models.GameEvent {
+	return s.events
+}
+
+// Close stops delivery and closes the events channel
+func (s *EventSubscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.unsubscribe(s)
+}
+
+// matches reports whether the subscription wants an event
+func (s *EventSubscription) matches(event *models.GameEvent) bool {
+	if event.CampaignID != s.campaignID {
+		return false
+	}
+	return s.types == nil || s.types[event.Type]
+}
+
+// eventEmitter publishes game events from a service; it does nothing until a publisher is set
+type eventEmitter struct {
+	publisher EventPublisher
+}
+
+// SetEventPublisher enables game events.
+// When set, state changes such as damage, conditions, turns, movement, dice rolls and
+// map transitions are published so clients can follow the game as it happens.
+func (e *eventEmitter) SetEventPublisher(publisher EventPublisher) {
+	e.publisher = publisher
+}
+
+// emit publishes an event; a failed publish never fails the game action that caused it
+func (e *eventEmitter) emit(ctx context.Context, campaignID string, eventType models.EventType, data map[string]interface{}) {
+	if e.publisher == nil || campaignID == "" {
+		return
+	}
+	_ = e.publisher.Publish(ctx, models.NewGameEvent(campaignID, eventType, data))
+}
+
+// emitHPChanged publishes a character's HP change from the HP it had before
+func (e *eventEmitter) emitHPChanged(ctx context.Context, character *models.Character, before models.HP, source string) {
+	if character.HP == nil || (character.HP.Current == before.Current && character.HP.Temp == before.Temp && character.HP.Max == before.Max) {
+		return
+	}
+	e.emit(ctx, character.CampaignID, models.EventHPChanged, map[string]interface{}{
+		"character_id": character.ID,
+		"name":         character.Name,
+		"before":       before.Current,
+		"current":      character.HP.Current,
+		"max":          character.HP.Max,
+		"temp":         character.HP.Temp,
+		"change":       character.HP.Current - before.Current,
+		"down":         character.HP.IsAtZero(),
+		"source":       source,
+	})
+}
+
+// emitConditions publishes conditions applied to or removed from a character
+func (e *eventEmitter) emitConditions(ctx context.Context, character *models.Character, eventType models.EventType, conditions []string, source string) {
+	if len(conditions) == 0 {
+		return
+	}
+	e.emit(ctx, character.CampaignID, eventType, map[string]interface{}{
+		"character_id": character.ID,
+		"name":         character.Name,
+		"conditions":   conditions,
+		"source":       source,
+	})
+}
+
+// hpSnapshot copies a character's HP before a change
+func hpSnapshot(character *models.Character) models.HP {
+	if character.HP == nil {
+		return models.HP{}
+	}
+	return *character.HP
+}
@@ -3,9 +3,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/movement"
 	"github.com/dnd-mcp/server/internal/store"
 )
 
@@ -32,12 +35,32 @@ type GameStateStoreForMap interface {
 	Update(ctx context.Context, gameState *models.GameState) error
 }
 
+// CombatStoreForMap defines the combat store interface needed by map service
+type CombatStoreForMap interface {
+	Get(ctx context.Context, id string) (*models.Combat, error)
+	Update(ctx context.Context, combat *models.Combat) error
+}
+
+// OpportunityAttackHandler offers or resolves opportunity attacks provoked by token movement
+type OpportunityAttackHandler interface {
+	// OpportunityAttackReach returns the reach in feet a character threatens
+	OpportunityAttackReach(ctx context.Context, characterID string) int
+	// ProvokeOpportunityAttack offers or resolves an opportunity attack, returning nil when
+	// the attacker cannot react; the combat is saved by the caller
+	ProvokeOpportunityAttack(ctx context.Context, combat *models.Combat, attackerID, targetID string) (*OpportunityAttack, error)
+}
+
 // MapService provides map business logic
 // 规则参考: PHB 第8章 Travel, 第9章 Combat
 type MapService struct {
-	mapStore       MapStore
-	campaignStore  CampaignStoreForMap
-	gameStateStore GameStateStoreForMap
+	eventEmitter
+	mapStore           MapStore
+	campaignStore      CampaignStoreForMap
+	gameStateStore     GameStateStoreForMap
+	combatStore        CombatStoreForMap
+	opportunityHandler OpportunityAttackHandler
+	characterStore     CharacterStoreForMap
+	transactor         store.Transactor
 }
 
 // NewMapService creates a new map service
@@ -53,6 +76,26 @@ func NewMapService(
 	}
 }
 
+// SetCombatStore enables combat-aware token movement.
+// When set, moving a token during an active combat consumes the
+// participant's remaining movement for the turn.
+func (s *MapService) SetCombatStore(combatStore CombatStoreForMap) {
+	s.combatStore = combatStore
+}
+
+// SetTransactor makes token movement save the combat turn budget and the
+// token position in one transaction.
+func (s *MapService) SetTransactor(transactor store.Transactor) {
+	s.transactor = transactor
+}
+
+// SetOpportunityAttackHandler enables opportunity attacks during combat movement.
+// When set, a token leaving a hostile creature's reach on its turn provokes an
+// opportunity attack from that creature unless the token took the Disengage action.
+func (s *MapService) SetOpportunityAttackHandler(handler OpportunityAttackHandler) {
+	s.opportunityHandler = handler
+}
+
 // GetWorldMap retrieves the world map for a campaign
 func (s *MapService) GetWorldMap(ctx context.Context, campaignID string) (*models.Map, error) {
 	if campaignID == "" {
@@ -115,6 +158,34 @@ func (s *MapService) GetBattleMap(ctx context.Context, mapID string) (*models.Ma
 	return battleMap, nil
 }
 
+// GetMap retrieves a map of any type by ID
+func (s *MapService) GetMap(ctx context.Context, mapID string) (*models.Map, error) {
+	if mapID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "map ID is required")
+	}
+
+	gameMap, err := s.mapStore.Get(ctx, mapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map: %w", err)
+	}
+
+	return gameMap, nil
+}
+
+// ListMaps lists the world and battle maps of a campaign
+func (s *MapService) ListMaps(ctx context.Context, campaignID string) ([]*models.Map, error) {
+	if campaignID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+
+	maps, err := s.mapStore.GetByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maps: %w", err)
+	}
+
+	return maps, nil
+}
+
 // CreateBattleMapRequest represents a battle map creation request
 type CreateBattleMapRequest struct {
 	CampaignID string `json:"campaign_id"`
@@ -539,15 +610,21 @@ type TokenMoveRequest struct {
 	ToY        int    `json:"to_y"`
 	// Speed is the available movement speed in feet (optional, defaults to character speed)
 	Speed *int `json:"speed,omitempty"`
+	// Forced marks involuntary movement (shove, spell effects) that ignores the turn budget
+	Forced bool `json:"forced,omitempty"`
+	// Truncate stops the token as far along the route as its speed allows instead of rejecting the move
+	Truncate bool `json:"truncate,omitempty"`
 }
 
 // TokenMoveResult represents the result of a token move operation
 type TokenMoveResult struct {
-	Token                  *models.Token  `json:"token"`
-	MovementUsed           int            `json:"movement_used"`
-	RemainingSpeed         int            `json:"remaining_speed"`
-	Path                   []models.Position `json:"path"`
-	DifficultTerrainCount  int            `json:"difficult_terrain_count"`
+	Token                 *models.Token        `json:"token"`
+	MovementUsed          int                  `json:"movement_used"`
+	RemainingSpeed        int                  `json:"remaining_speed"`
+	Path                  []models.Position    `json:"path"`
+	DifficultTerrainCount int                  `json:"difficult_terrain_count"`
+	Truncated             bool                 `json:"truncated,omitempty"`           // stopped short of the requested destination
+	OpportunityAttacks    []*OpportunityAttack `json:"opportunity_attacks,omitempty"` // opportunity attacks provoked by leaving a hostile's reach
 }
 
 // MoveToken moves a token on a battle map
@@ -584,6 +661,14 @@ func (s *MapService) MoveToken(ctx context.Context, req *TokenMoveRequest) (*Tok
 		return nil, NewServiceError(ErrCodeNotFound, "token not found on this map")
 	}
 
+	// Players may only move their own character's token, and never force movement
+	if err := s.authorizeToken(ctx, req.CampaignID, token); err != nil {
+		return nil, err
+	}
+	if req.Forced && redacted(ctx, req.CampaignID) {
+		return nil, NewServiceError(ErrCodeForbidden, "only the DM may apply forced movement")
+	}
+
 	// Validate destination position BEFORE calculating movement cost
 	// This allows early exit for invalid positions
 	sizeInGrids := token.GetSizeInGrids()
@@ -591,6 +676,14 @@ func (s *MapService) MoveToken(ctx context.Context, req *TokenMoveRequest) (*Tok
 		return nil, NewServiceError(ErrCodeInvalidInput, "destination position is out of map bounds")
 	}
 
+	// Determine available speed
+	var availableSpeed int
+	if req.Speed != nil {
+		availableSpeed = *req.Speed
+	} else {
+		availableSpeed = s.tokenSpeed(ctx, req.CampaignID, token)
+	}
+
 	// Check if no actual movement
 	fromX, fromY := token.Position.X, token.Position.Y
 	if fromX == req.ToX && fromY == req.ToY {
@@ -598,36 +691,51 @@ func (s *MapService) MoveToken(ctx context.Context, req *TokenMoveRequest) (*Tok
 		return &TokenMoveResult{
 			Token:                 token,
 			MovementUsed:          0,
-			RemainingSpeed:        30, // Default or use req.Speed
+			RemainingSpeed:        availableSpeed,
 			Path:                  []models.Position{{X: fromX, Y: fromY}},
 			DifficultTerrainCount: 0,
 		}, nil
 	}
 
-	// Calculate movement cost using Manhattan distance with terrain consideration
-	movementCost, difficultCount := s.calculateMovementCost(battleMap, token, fromX, fromY, req.ToX, req.ToY)
-
-	// Determine available speed
-	availableSpeed := 30 // Default 30 feet
-	if req.Speed != nil {
-		availableSpeed = *req.Speed
-	} else {
-		// Try to get speed from character (if we had character store access)
-		// For now, use default speed
+	// During combat, voluntary movement is limited by the participant's turn budget
+	// 规则参考: PHB 第9章 - Movement and Position
+	combat, participant, err := s.getCombatParticipant(ctx, req.CampaignID, token.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+	if participant != nil && !req.Forced {
+		current := combat.GetCurrentParticipant()
+		if current == nil || current.CharacterID != participant.CharacterID {
+			return nil, NewServiceError(ErrCodeInvalidState, "token can only move on its own turn (use forced movement otherwise)")
+		}
+		availableSpeed = participant.EnsureActions(availableSpeed).Movement
 	}
 
-	// Check if movement is possible
-	if movementCost > availableSpeed {
-		return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("insufficient movement: need %d feet, have %d feet", movementCost, availableSpeed))
+	// Find the cheapest legal route around walls, closed doors and hostile creatures
+	route, err := movement.FindPath(battleMap, token, req.ToX, req.ToY)
+	if err != nil {
+		if errors.Is(err, movement.ErrInvalidDestination) {
+			return nil, NewServiceError(ErrCodeInvalidState, "destination is blocked by a wall or hostile creature")
+		}
+		return nil, NewServiceError(ErrCodeInvalidState, "movement path is blocked by walls, closed doors or hostile creatures")
 	}
 
-	// Check for blocking walls (simple line check)
-	if s.isPathBlocked(battleMap, token, fromX, fromY, req.ToX, req.ToY) {
-		return nil, NewServiceError(ErrCodeInvalidState, "movement path is blocked by walls")
+	// Check if movement is possible, stopping early along the route when truncation is allowed
+	truncated := false
+	if route.Cost > availableSpeed {
+		if !req.Truncate {
+			return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("insufficient movement: need %d feet, have %d feet", route.Cost, availableSpeed))
+		}
+		route = route.Truncate(availableSpeed)
+		if len(route.Path) < 2 {
+			return nil, NewServiceError(ErrCodeInvalidState, fmt.Sprintf("insufficient movement: have %d feet", availableSpeed))
+		}
+		truncated = true
 	}
 
-	// Check for other tokens blocking destination
-	otherTokens := battleMap.GetTokensAtPosition(req.ToX, req.ToY)
+	// Check for other tokens blocking the square the token stops in
+	end := route.Path[len(route.Path)-1]
+	otherTokens := battleMap.GetTokensAtPosition(end.X, end.Y)
 	for _, other := range otherTokens {
 		if other.ID != token.ID {
 			// Check if can move through (size difference)
@@ -637,122 +745,163 @@ func (s *MapService) MoveToken(ctx context.Context, req *TokenMoveRequest) (*Tok
 		}
 	}
 
-	// Generate simple path (straight line, can be improved with A* later)
-	path := s.generatePath(fromX, fromY, req.ToX, req.ToY)
-
-	// Update token position
-	token.SetPosition(req.ToX, req.ToY)
+	// Opportunity attacks, the turn budget and the new position are saved together, so a
+	// failed write never leaves damage from a move that did not happen or a move that cost
+	// no movement. Their events reach subscribers only once the transaction commits.
+	var opportunities []*OpportunityAttack
+	var movementCost int
+	var destination models.Position
+	err = inTx(ctx, s.transactor, func(ctx context.Context) error {
+		// Leaving a hostile creature's reach provokes an opportunity attack; a creature
+		// dropped to 0 HP by one stops where it was hit
+		// 规则参考: PHB 第9章 - Opportunity Attacks
+		if participant != nil && !req.Forced && !participant.Actions.Disengaged && s.opportunityHandler != nil {
+			var stop int
+			var err error
+			opportunities, stop, err = s.provokeOpportunityAttacks(ctx, combat, battleMap, token, route)
+			if err != nil {
+				return err
+			}
+			if stop >= 0 {
+				route = route.TruncateAt(stop)
+				truncated = true
+			}
+		}
 
-	// Update map
-	if err := s.mapStore.Update(ctx, battleMap); err != nil {
-		return nil, fmt.Errorf("failed to update map: %w", err)
+		movementCost = route.Cost
+		destination = route.Path[len(route.Path)-1]
+		token.SetPosition(destination.X, destination.Y)
+
+		// Consume movement from the combat turn budget before saving the new position
+		if participant != nil && !req.Forced {
+			participant.Actions.UseMovement(movementCost)
+			combat.AddLogEntry(participant.CharacterID, "move", "",
+				fmt.Sprintf("moved %d feet to (%d, %d)", movementCost, destination.X, destination.Y))
+			if err := s.combatStore.Update(ctx, combat); err != nil {
+				return fmt.Errorf("failed to update combat: %w", err)
+			}
+		}
+		if err := s.mapStore.Update(ctx, battleMap); err != nil {
+			return fmt.Errorf("failed to update map: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	remainingSpeed := availableSpeed - movementCost
+	s.emit(ctx, req.CampaignID, models.EventTokenMoved, map[string]interface{}{
+		"map_id":        battleMap.ID,
+		"token_id":      token.ID,
+		"character_id":  token.CharacterID,
+		"from":          models.Position{X: fromX, Y: fromY},
+		"to":            destination,
+		"path":          route.Path,
+		"movement_used": movementCost,
+		"forced":        req.Forced,
+	})
 
 	return &TokenMoveResult{
 		Token:                 token,
 		MovementUsed:          movementCost,
 		RemainingSpeed:        remainingSpeed,
-		Path:                  path,
-		DifficultTerrainCount: difficultCount,
+		Path:                  route.Path,
+		DifficultTerrainCount: route.DifficultCount,
+		Truncated:             truncated,
+		OpportunityAttacks:    opportunities,
 	}, nil
 }
 
-// calculateMovementCost calculates the movement cost for a token
-func (s *MapService) calculateMovementCost(battleMap *models.Map, token *models.Token, fromX, fromY, toX, toY int) (int, int) {
-	dx := abs(toX - fromX)
-	dy := abs(toY - fromY)
-
-	// Manhattan distance for base cost
-	baseSquares := dx + dy
-	cellSize := battleMap.Grid.CellSize
-	if cellSize == 0 {
-		cellSize = 5 // Default 5 feet
-	}
-
-	movementCost := baseSquares * cellSize
-	difficultCount := 0
-
-	// Diagonal movement penalty (every 2nd diagonal = 2 squares, simplified to 1.5x)
-	isDiagonal := (dx > 0 && dy > 0)
-	if isDiagonal {
-		movementCost = (movementCost * 3) / 2
-	}
-
-	// Check for difficult terrain along the path
-	// For simplicity, we check if any square along the direct path is difficult
-	if s.pathHasDifficultTerrain(battleMap, fromX, fromY, toX, toY) {
-		// Double the cost for difficult terrain
-		movementCost *= 2
-		difficultCount = baseSquares
-	}
-
-	return movementCost, difficultCount
+// opportunityExit is the point on a route where a token leaves a hostile creature's reach
+type opportunityExit struct {
+	index   int
+	hostile *models.Token
 }
 
-// pathHasDifficultTerrain checks if the path passes through difficult terrain
-func (s *MapService) pathHasDifficultTerrain(battleMap *models.Map, fromX, fromY, toX, toY int) bool {
-	// Simple check: sample a few points along the path
-	dx := toX - fromX
-	dy := toY - fromY
-	steps := max(abs(dx), abs(dy))
-
-	for i := 0; i <= steps; i++ {
-		if steps == 0 {
-			break
+// provokeOpportunityAttacks offers or resolves opportunity attacks from every hostile
+// participant whose reach the token leaves along the route, in route order.
+// It returns the path index where the token must stop, or -1 to complete the move.
+func (s *MapService) provokeOpportunityAttacks(ctx context.Context, combat *models.Combat, battleMap *models.Map, token *models.Token, route *movement.PathResult) ([]*OpportunityAttack, int, error) {
+	cellSize := 5
+	if battleMap.Grid != nil && battleMap.Grid.CellSize > 0 {
+		cellSize = battleMap.Grid.CellSize
+	}
+	exits := make([]opportunityExit, 0)
+	for i := range battleMap.Tokens {
+		hostile := &battleMap.Tokens[i]
+		if hostile.ID == token.ID || hostile.CharacterID == "" || !movement.IsHostile(token, hostile) {
+			continue
+		}
+		if combat.GetParticipantByCharacterID(hostile.CharacterID) == nil {
+			continue
 		}
-		t := float64(i) / float64(steps)
-		x := fromX + int(float64(dx)*t + 0.5)
-		y := fromY + int(float64(dy)*t + 0.5)
 
-		if battleMap.Grid.IsDifficultTerrain(x, y) {
-			return true
+		reach := s.opportunityHandler.OpportunityAttackReach(ctx, hostile.CharacterID)
+		moved := *token
+		for step := 0; step < len(route.Path)-1; step++ {
+			moved.Position = route.Path[step]
+			before := movement.TokenDistance(&moved, hostile, cellSize)
+			moved.Position = route.Path[step+1]
+			after := movement.TokenDistance(&moved, hostile, cellSize)
+			if before <= reach && after > reach {
+				exits = append(exits, opportunityExit{index: step, hostile: hostile})
+				break
+			}
 		}
 	}
+	sort.SliceStable(exits, func(i, j int) bool { return exits[i].index < exits[j].index })
 
-	return false
-}
-
-// isPathBlocked checks if the path is blocked by walls
-func (s *MapService) isPathBlocked(battleMap *models.Map, token *models.Token, fromX, fromY, toX, toY int) bool {
-	// Simple check: sample points along the path for walls
-	dx := toX - fromX
-	dy := toY - fromY
-	steps := max(abs(dx), abs(dy))
-
-	// Check all points along the path
-	for i := 1; i < steps; i++ {
-		t := float64(i) / float64(steps)
-		x := fromX + int(float64(dx)*t + 0.5)
-		y := fromY + int(float64(dy)*t + 0.5)
-
-		if !battleMap.Grid.IsWalkable(x, y) {
-			return true
+	opportunities := make([]*OpportunityAttack, 0, len(exits))
+	for _, exit := range exits {
+		opportunity, err := s.opportunityHandler.ProvokeOpportunityAttack(ctx, combat, exit.hostile.CharacterID, token.CharacterID)
+		if err != nil {
+			return nil, -1, err
+		}
+		if opportunity == nil {
+			continue
+		}
+		opportunity.Position = route.Path[exit.index]
+		opportunities = append(opportunities, opportunity)
+		if opportunity.Result != nil && opportunity.Result.TargetDown {
+			return opportunities, exit.index, nil
 		}
 	}
+	return opportunities, -1, nil
+}
 
-	return false
+// tokenSpeed returns the walking speed of a token's character, or the default speed
+// for tokens without a character or when the character cannot be loaded
+func (s *MapService) tokenSpeed(ctx context.Context, campaignID string, token *models.Token) int {
+	if s.characterStore == nil || token.CharacterID == "" {
+		return characterSpeed(nil)
+	}
+	character, err := s.characterStore.GetByCampaignAndID(ctx, campaignID, token.CharacterID)
+	if err != nil {
+		return characterSpeed(nil)
+	}
+	return characterSpeed(character)
 }
 
-// generatePath generates a simple path between two positions
-func (s *MapService) generatePath(fromX, fromY, toX, toY int) []models.Position {
-	dx := toX - fromX
-	dy := toY - fromY
-	steps := max(abs(dx), abs(dy))
+// getCombatParticipant returns the active combat and the participant for a character, if any
+func (s *MapService) getCombatParticipant(ctx context.Context, campaignID, characterID string) (*models.Combat, *models.Participant, error) {
+	if s.combatStore == nil || s.gameStateStore == nil || characterID == "" {
+		return nil, nil, nil
+	}
 
-	path := make([]models.Position, 0, steps+1)
-	path = append(path, models.Position{X: fromX, Y: fromY})
+	gameState, err := s.gameStateStore.Get(ctx, campaignID)
+	if err != nil || !gameState.IsInCombat() {
+		return nil, nil, nil
+	}
 
-	for i := 1; i < steps; i++ {
-		t := float64(i) / float64(steps)
-		x := fromX + int(float64(dx)*t + 0.5)
-		y := fromY + int(float64(dy)*t + 0.5)
-		path = append(path, models.Position{X: x, Y: y})
+	combat, err := s.combatStore.Get(ctx, gameState.ActiveCombatID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get combat: %w", err)
+	}
+	if !combat.IsActive() {
+		return nil, nil, nil
 	}
 
-	path = append(path, models.Position{X: toX, Y: toY})
-	return path
+	return combat, combat.GetParticipantByCharacterID(characterID), nil
 }
 
 // canTokenMoveThrough checks if a token can move through another token's space
@@ -792,14 +941,6 @@ func abs(n int) int {
 	return n
 }
 
-// max returns the maximum of two integers
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
 // ============ Map Switching Methods ============
 
 // CharacterStoreForMap defines the character store interface needed for token placement
@@ -811,7 +952,6 @@ type CharacterStoreForMap interface {
 // MapServiceWithCharacters extends MapService with character store for token operations
 type MapServiceWithCharacters struct {
 	*MapService
-	characterStore CharacterStoreForMap
 }
 
 // NewMapServiceWithCharacters creates a new map service with character support
@@ -822,9 +962,9 @@ func NewMapServiceWithCharacters(
 	characterStore CharacterStoreForMap,
 ) *MapServiceWithCharacters {
 	baseService := NewMapService(mapStore, campaignStore, gameStateStore)
+	baseService.characterStore = characterStore
 	return &MapServiceWithCharacters{
-		MapService:     baseService,
-		characterStore: characterStore,
+		MapService: baseService,
 	}
 }
 
@@ -938,6 +1078,13 @@ func (s *MapServiceWithCharacters) EnterBattleMap(ctx context.Context, req *Ente
 	if err := s.gameStateStore.Update(ctx, gameState); err != nil {
 		return nil, fmt.Errorf("failed to update game state: %w", err)
 	}
+	s.emit(ctx, req.CampaignID, models.EventMapEntered, map[string]interface{}{
+		"map_id":        battleMap.ID,
+		"map_type":      models.MapTypeBattle,
+		"name":          battleMap.Name,
+		"location_id":   req.LocationID,
+		"tokens_placed": tokensPlaced,
+	})
 
 	return &EnterBattleMapResult{
 		BattleMap:    battleMap,
@@ -1041,6 +1188,16 @@ func (s *MapService) ExitBattleMap(ctx context.Context, req *ExitBattleMapReques
 	if err := s.gameStateStore.Update(ctx, gameState); err != nil {
 		return nil, fmt.Errorf("failed to update game state: %w", err)
 	}
+	locationID := ""
+	if location != nil {
+		locationID = location.ID
+	}
+	s.emit(ctx, req.CampaignID, models.EventMapEntered, map[string]interface{}{
+		"map_id":      worldMap.ID,
+		"map_type":    models.MapTypeWorld,
+		"name":        worldMap.Name,
+		"location_id": locationID,
+	})
 
 	return &ExitBattleMapResult{
 		GameState: gameState,
@@ -0,0 +1,232 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/models"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+)
+
+// OpportunityAttack 移动引发的借机攻击
+// NPC 自动用反应进行攻击；玩家角色的借机攻击等待玩家通过 opportunity_attack 决定
+type OpportunityAttack struct {
+	AttackerID string                    `json:"attacker_id"`
+	TargetID   string                    `json:"target_id"`
+	Position   models.Position           `json:"position"`         // 目标离开触及前所在的位置
+	Resolved   bool                      `json:"resolved"`         // 是否已自动结算
+	Result     *rulescombat.AttackResult `json:"result,omitempty"` // 自动结算的攻击结果
+}
+
+// OpportunityAttackRequest 执行借机攻击请求
+type OpportunityAttackRequest struct {
+	CombatID   string `json:"combat_id"`
+	AttackerID string `json:"attacker_id"`
+	TargetID   string `json:"target_id"`
+}
+
+// OpportunityAttackResponse 借机攻击响应
+type OpportunityAttackResponse struct {
+	Result              *rulescombat.AttackResult `json:"result"`
+	Combat              *models.Combat            `json:"combat"`
+	ConcentrationChecks []*ConcentrationCheck     `json:"concentration_checks,omitempty"`
+}
+
+// OpportunityAttackReach 返回角色借机攻击的触及（英尺）
+// 规则参考: PHB 第9章 - Opportunity Attacks
+func (s *CombatService) OpportunityAttackReach(ctx context.Context, characterID string) int {
+	character, err := s.characterStore.Get(ctx, characterID)
+	if err != nil {
+		return rulescombat.GetMeleeReach(nil)
+	}
+	return rulescombat.GetMeleeReach(character)
+}
+
+// ProvokeOpportunityAttack 处理目标离开攻击者触及时引发的借机攻击
+// 攻击者需要可用的反应且未失能；NPC 立即进行一次近战攻击，
+// 玩家角色记录为可进行的借机攻击，在本回合结束前通过 OpportunityAttack 执行
+// 返回 nil 表示攻击者无法进行借机攻击；调用方负责保存战斗
+// 规则参考: PHB 第9章 - Opportunity Attacks
+func (s *CombatService) ProvokeOpportunityAttack(ctx context.Context, combat *models.Combat, attackerID, targetID string) (*OpportunityAttack, error) {
+	participant := combat.GetParticipantByCharacterID(attackerID)
+	if participant == nil {
+		return nil, nil
+	}
+	attacker, err := s.characterStore.Get(ctx, attackerID)
+	if err != nil {
+		return nil, nil
+	}
+	if !s.canReact(participant, attacker) {
+		return nil, nil
+	}
+
+	opportunity := &OpportunityAttack{AttackerID: attackerID, TargetID: targetID}
+	if !attacker.IsNPC {
+		participant.OfferOpportunityAttack(targetID)
+		combat.AddLogEntry(attackerID, "opportunity_attack_offered", targetID, "may make an opportunity attack")
+		return opportunity, nil
+	}
+
+	target, err := s.characterStore.Get(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+	result, _, err := s.resolveOpportunityAttack(ctx, combat, participant, attacker, target)
+	if err != nil {
+		return nil, err
+	}
+	opportunity.Resolved = true
+	opportunity.Result = result
+	return opportunity, nil
+}
+
+// OpportunityAttack 执行玩家接受的借机攻击
+// 只能针对本回合移动时离开其触及的目标，消耗攻击者的反应
+// 规则参考: PHB 第9章 - Opportunity Attacks
+func (s *CombatService) OpportunityAttack(ctx context.Context, req *OpportunityAttackRequest) (*OpportunityAttackResponse, error) {
+	if req.CombatID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "combat ID is required")
+	}
+	if req.AttackerID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "attacker ID is required")
+	}
+	if req.TargetID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "target ID is required")
+	}
+
+	combat, err := s.combatStore.Get(ctx, req.CombatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get combat: %w", err)
+	}
+	if !combat.IsActive() {
+		return nil, NewServiceError(ErrCodeInvalidState, "combat is not active")
+	}
+
+	participant := combat.GetParticipantByCharacterID(req.AttackerID)
+	if participant == nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "attacker is not in combat")
+	}
+	attacker, err := s.characterStore.Get(ctx, req.AttackerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attacker: %w", err)
+	}
+	if err := authorizeActor(ctx, attacker); err != nil {
+		return nil, err
+	}
+	target, err := s.characterStore.Get(ctx, req.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+
+	if !participant.TakeOpportunityAttack(req.TargetID) {
+		return nil, NewServiceError(ErrCodeInvalidState,
+			fmt.Sprintf("%s has no opportunity attack against %s this turn", attacker.Name, target.Name))
+	}
+	if !s.canReact(participant, attacker) {
+		return nil, NewServiceError(ErrCodeInvalidState,
+			fmt.Sprintf("%s cannot take a reaction right now", attacker.Name))
+	}
+
+	result, checks, err := s.resolveOpportunityAttack(ctx, combat, participant, attacker, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.combatStore.Update(ctx, combat); err != nil {
+		return nil, fmt.Errorf("failed to update combat: %w", err)
+	}
+
+	return &OpportunityAttackResponse{
+		Result:              result,
+		Combat:              combat,
+		ConcentrationChecks: checks,
+	}, nil
+}
+
+// canReact 检查参战者是否还有反应且未失能
+func (s *CombatService) canReact(participant *models.Participant, character *models.Character) bool {
+	if !participant.EnsureActions(characterSpeed(character)).Has(models.ActionTypeReaction) {
+		return false
+	}
+	if character.HP != nil && character.HP.IsAtZero() {
+		return false
+	}
+	return !rulescombat.IsIncapacitated(rulescombat.CollectConditions(character, participant.Conditions))
+}
+
+// resolveOpportunityAttack 消耗反应，用触及最远的近战武器进行一次攻击并结算伤害
+func (s *CombatService) resolveOpportunityAttack(ctx context.Context, combat *models.Combat, participant *models.Participant, attacker, target *models.Character) (*rulescombat.AttackResult, []*ConcentrationCheck, error) {
+	participant.Actions.Use(models.ActionTypeReaction)
+	participant.TakeOpportunityAttack(target.ID)
+
+	weapon := rulescombat.GetMeleeWeapon(attacker)
+	modifiers := rulescombat.NewRollModifiers(false, false)
+	rulescombat.AddAttackConditionModifiers(modifiers,
+		rulescombat.CollectConditions(attacker, participant.Conditions),
+		rulescombat.CollectConditions(target, participantConditions(combat, target.ID)),
+		movement.DistanceUnknown, false)
+	result := rulescombat.ResolveAttackWithModifiers(attacker, target, weapon, modifiers, s.roller)
+
+	var checks []*ConcentrationCheck
+	if result.Hit && result.Damage > 0 {
+		before := hpSnapshot(target)
+		target.TakeDamage(result.Damage)
+		if check := s.checkConcentration(combat, target, result.Damage); check != nil {
+			checks = append(checks, check)
+		}
+		if err := s.characterStore.Update(ctx, target); err != nil {
+			return nil, nil, fmt.Errorf("failed to update target: %w", err)
+		}
+		s.emitHPChanged(ctx, target, before, "opportunity attack")
+		if target.HP != nil {
+			hpCopy := *target.HP
+			result.TargetHP = &hpCopy
+			result.TargetDown = target.HP.IsAtZero()
+		}
+	}
+
+	s.logAttack(combat, attacker.ID, target.ID, weapon, result)
+	combat.Log[len(combat.Log)-1].Action = "opportunity_attack"
+	return result, checks, nil
+}
+
+// hostileNearby 检查 5 尺内是否有未失能的敌对生物（影响远程攻击）
+// 规则参考: PHB 第9章 - Ranged Attacks in Close Combat
+func (s *CombatService) hostileNearby(ctx context.Context, combat *models.Combat, characterID string) bool {
+	battleMap := s.combatMap(ctx, combat)
+	if battleMap == nil {
+		return false
+	}
+	token := battleMap.GetTokenByCharacterID(characterID)
+	if token == nil {
+		return false
+	}
+
+	cellSize := 5
+	if battleMap.Grid != nil && battleMap.Grid.CellSize > 0 {
+		cellSize = battleMap.Grid.CellSize
+	}
+	for i := range battleMap.Tokens {
+		other := &battleMap.Tokens[i]
+		if other.ID == token.ID || !movement.IsHostile(token, other) {
+			continue
+		}
+		if movement.TokenDistance(token, other, cellSize) > 5 {
+			continue
+		}
+		var character *models.Character
+		if other.CharacterID != "" {
+			character, _ = s.characterStore.Get(ctx, other.CharacterID)
+		}
+		if character != nil && character.HP != nil && character.HP.IsAtZero() {
+			continue
+		}
+		if rulescombat.IsIncapacitated(rulescombat.CollectConditions(character, participantConditions(combat, other.CharacterID))) {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -82,6 +82,9 @@ func (s *RestService) ShortRest(ctx context.Context, req *ShortRestRequest) (*Sh
 	if err != nil {
 		return nil, fmt.Errorf("failed to get character: %w", err)
 	}
+	if err := authorizeActor(ctx, character); err != nil {
+		return nil, err
+	}
 
 	// 3. 计算体质修正
 	conMod := 0
@@ -130,6 +133,9 @@ func (s *RestService) LongRest(ctx context.Context, req *LongRestRequest) (*Long
 	if err != nil {
 		return nil, fmt.Errorf("failed to get character: %w", err)
 	}
+	if err := authorizeActor(ctx, character); err != nil {
+		return nil, err
+	}
 
 	// 3. 计算体质修正
 	conMod := 0
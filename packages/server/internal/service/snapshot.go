@@ -0,0 +1,434 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/archive"
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+)
+
+// MessageStoreForSnapshot defines the interface for message data operations needed by campaign snapshots
+type MessageStoreForSnapshot interface {
+	MessageStoreForArchive
+	DeleteByCampaignAfterDate(ctx context.Context, campaignID string, afterDate time.Time) (int64, error)
+}
+
+// SetSnapshotStores enables campaign snapshots and forks.
+// Snapshots are captured inside one repeatable read transaction of the given transactor.
+func (s *CampaignService) SetSnapshotStores(
+	snapshotStore store.SnapshotStore,
+	characterStore store.CharacterStore,
+	mapStore store.MapStore,
+	combatStore store.CombatStore,
+	messageStore MessageStoreForSnapshot,
+	transactor store.ConsistentTransactor,
+) {
+	s.snapshotStore = snapshotStore
+	s.records = &campaignRecords{
+		campaignStore:  s.campaignStore,
+		gameStateStore: s.gameStateStore,
+		characterStore: characterStore,
+		mapStore:       mapStore,
+		combatStore:    combatStore,
+		messageStore:   messageStore,
+	}
+	s.messageStore = messageStore
+	s.transactor = transactor
+}
+
+// CreateSnapshotRequest 创建存档点请求
+type CreateSnapshotRequest struct {
+	CampaignID  string `json:"campaign_id" jsonschema:"required" description:"The campaign to snapshot"`
+	Name        string `json:"name" description:"A name for the save point (default: the current time)"`
+	Description string `json:"description" description:"What was going on at the save point"`
+}
+
+// RestoreSnapshotRequest 恢复存档点请求
+type RestoreSnapshotRequest struct {
+	CampaignID string `json:"campaign_id" jsonschema:"required" description:"The campaign to restore"`
+	SnapshotID string `json:"snapshot_id" jsonschema:"required" description:"The save point to restore, from list_snapshots"`
+}
+
+// RestoreSnapshotResponse 恢复存档点响应
+type RestoreSnapshotResponse struct {
+	Snapshot          *models.Snapshot  `json:"snapshot"`           // 恢复的存档点
+	GameState         *models.GameState `json:"game_state"`         // 恢复后的游戏状态
+	Characters        int               `json:"characters"`         // 恢复的角色数
+	Maps              int               `json:"maps"`               // 恢复的地图数
+	RemovedCharacters int               `json:"removed_characters"` // 删除的存档后创建的角色数
+	RemovedMaps       int               `json:"removed_maps"`       // 删除的存档后创建的地图数
+	RemovedCombats    int               `json:"removed_combats"`    // 删除的存档后开始的战斗数
+	RemovedMessages   int64             `json:"removed_messages"`   // 删除的存档后的消息数
+}
+
+// ForkCampaignRequest 分支战役请求
+type ForkCampaignRequest struct {
+	CampaignID string `json:"campaign_id" jsonschema:"required" description:"The campaign to fork"`
+	SnapshotID string `json:"snapshot_id" description:"Fork from this save point instead of the current state"`
+	Name       string `json:"name" description:"Name of the new campaign (default: the original name with ' (fork)')"`
+}
+
+// ForkCampaignResponse 分支战役响应
+type ForkCampaignResponse struct {
+	Campaign *models.Campaign `json:"campaign"`           // 新战役
+	Snapshot *models.Snapshot `json:"snapshot,omitempty"` // 分支的存档点（从当前状态分支时为空）
+}
+
+// CreateSnapshot saves the campaign's game state, characters, maps and active combat
+// as a save point. All rows are read in one transaction so the snapshot is consistent.
+func (s *CampaignService) CreateSnapshot(ctx context.Context, req *CreateSnapshotRequest) (*models.Snapshot, error) {
+	if err := s.requireSnapshots(); err != nil {
+		return nil, err
+	}
+	if req.CampaignID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+
+	var snapshot *models.Snapshot
+	err := s.inConsistentTx(ctx, func(ctx context.Context) error {
+		campaign, err := s.getCampaignForDM(ctx, req.CampaignID)
+		if err != nil {
+			return err
+		}
+
+		contents, err := s.captureState(ctx, campaign)
+		if err != nil {
+			return err
+		}
+		messages, err := s.messageStore.ListByCampaign(ctx, campaign.ID, 0)
+		if err != nil {
+			return fmt.Errorf("failed to list messages: %w", err)
+		}
+
+		snapshot = models.NewSnapshot(campaign.ID, req.Name)
+		snapshot.Description = req.Description
+		if snapshot.Name == "" {
+			snapshot.Name = snapshot.CreatedAt.Format("2006-01-02 15:04:05")
+		}
+		if principal, ok := auth.FromContext(ctx); ok {
+			snapshot.CreatedBy = principal.UserID
+		}
+		// 消息只记录游标，不复制内容
+		snapshot.MessageCount = len(messages)
+		if len(messages) > 0 {
+			cursor := messages[len(messages)-1].CreatedAt
+			snapshot.MessageCursor = &cursor
+		}
+
+		var buf bytes.Buffer
+		if _, err := archive.Write(&buf, contents, snapshot.CreatedAt); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+		snapshot.Data = buf.Bytes()
+		snapshot.Size = len(snapshot.Data)
+
+		if err := s.snapshotStore.Create(ctx, snapshot); err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// ListSnapshots lists a campaign's save points, newest first
+func (s *CampaignService) ListSnapshots(ctx context.Context, campaignID string) ([]*models.Snapshot, error) {
+	if err := s.requireSnapshots(); err != nil {
+		return nil, err
+	}
+	if campaignID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+
+	if _, err := s.getCampaignForDM(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshotStore.ListByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot rewinds a campaign to a save point in one transaction.
+// Characters and maps are put back as they were, those created after the snapshot
+// are deleted, combats started after it are deleted and messages after it are removed.
+func (s *CampaignService) RestoreSnapshot(ctx context.Context, req *RestoreSnapshotRequest) (*RestoreSnapshotResponse, error) {
+	if err := s.requireSnapshots(); err != nil {
+		return nil, err
+	}
+	if req.CampaignID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+	if req.SnapshotID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "snapshot ID is required")
+	}
+
+	var resp *RestoreSnapshotResponse
+	err := inTx(ctx, s.transactor, func(ctx context.Context) error {
+		campaign, err := s.getCampaignForDM(ctx, req.CampaignID)
+		if err != nil {
+			return err
+		}
+		snapshot, contents, err := s.readSnapshot(ctx, campaign.ID, req.SnapshotID)
+		if err != nil {
+			return err
+		}
+
+		resp = &RestoreSnapshotResponse{
+			Snapshot:   snapshot,
+			GameState:  contents.GameState,
+			Characters: len(contents.Characters),
+			Maps:       len(contents.Maps),
+		}
+		if err := s.restoreRecords(ctx, campaign.ID, snapshot, contents, resp); err != nil {
+			return err
+		}
+
+		// 存档后的消息不再属于这条时间线
+		var cursor time.Time
+		if snapshot.MessageCursor != nil {
+			cursor = *snapshot.MessageCursor
+		}
+		if resp.RemovedMessages, err = s.messageStore.DeleteByCampaignAfterDate(ctx, campaign.ID, cursor); err != nil {
+			return fmt.Errorf("failed to delete messages: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ForkCampaign copies a campaign, as it is now or as it was at a save point, into a new
+// campaign with new IDs. The original campaign is not changed.
+func (s *CampaignService) ForkCampaign(ctx context.Context, req *ForkCampaignRequest) (*ForkCampaignResponse, error) {
+	if err := s.requireSnapshots(); err != nil {
+		return nil, err
+	}
+	if req.CampaignID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "campaign ID is required")
+	}
+
+	resp := &ForkCampaignResponse{}
+	err := s.inConsistentTx(ctx, func(ctx context.Context) error {
+		campaign, err := s.getCampaignForDM(ctx, req.CampaignID)
+		if err != nil {
+			return err
+		}
+
+		var contents *archive.Contents
+		if req.SnapshotID != "" {
+			resp.Snapshot, contents, err = s.readSnapshot(ctx, campaign.ID, req.SnapshotID)
+		} else {
+			contents, err = s.captureState(ctx, campaign)
+		}
+		if err != nil {
+			return err
+		}
+
+		messages, err := s.messageStore.ListByCampaign(ctx, campaign.ID, 0)
+		if err != nil {
+			return fmt.Errorf("failed to list messages: %w", err)
+		}
+		for _, message := range messages {
+			if resp.Snapshot == nil || resp.Snapshot.Includes(message) {
+				contents.Messages = append(contents.Messages, message)
+			}
+		}
+
+		if _, err := archive.RemapIDs(contents); err != nil {
+			return fmt.Errorf("failed to remap IDs: %w", err)
+		}
+		fork := contents.Campaign
+		fork.Name = req.Name
+		if fork.Name == "" {
+			fork.Name = campaign.Name + " (fork)"
+		}
+		fork.DeletedAt = nil
+		if err := fork.Validate(); err != nil {
+			return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("invalid campaign: %v", err))
+		}
+
+		if err := s.records.create(ctx, contents); err != nil {
+			return err
+		}
+		resp.Campaign = fork
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// requireSnapshots checks that snapshot stores are configured
+func (s *CampaignService) requireSnapshots() error {
+	if s.snapshotStore == nil || s.records == nil {
+		return NewServiceError(ErrCodeInvalidState, "campaign snapshots are not enabled")
+	}
+	return nil
+}
+
+// getCampaignForDM retrieves a campaign run by the caller
+func (s *CampaignService) getCampaignForDM(ctx context.Context, campaignID string) (*models.Campaign, error) {
+	campaign, err := s.campaignStore.Get(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	if err := authorizeCampaignDM(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// captureState reads the campaign's current game state, characters, maps and active combat
+func (s *CampaignService) captureState(ctx context.Context, campaign *models.Campaign) (*archive.Contents, error) {
+	contents, err := s.records.load(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if contents.GameState.ActiveCombatID != "" {
+		combat, err := s.records.combatStore.Get(ctx, contents.GameState.ActiveCombatID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get active combat: %w", err)
+		}
+		contents.Combats = []*models.Combat{combat}
+	}
+	return contents, nil
+}
+
+// readSnapshot retrieves a campaign's snapshot and decodes its records
+func (s *CampaignService) readSnapshot(ctx context.Context, campaignID, snapshotID string) (*models.Snapshot, *archive.Contents, error) {
+	snapshot, err := s.snapshotStore.Get(ctx, snapshotID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	if snapshot.CampaignID != campaignID {
+		return nil, nil, NewServiceError(ErrCodeNotFound, fmt.Sprintf("snapshot %s not found in campaign %s", snapshotID, campaignID))
+	}
+
+	contents, _, err := archive.Read(snapshot.Data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if contents.GameState == nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot: no game state")
+	}
+	return snapshot, contents, nil
+}
+
+// restoreRecords writes a snapshot's records over the campaign's current ones
+func (s *CampaignService) restoreRecords(ctx context.Context, campaignID string, snapshot *models.Snapshot, contents *archive.Contents, resp *RestoreSnapshotResponse) error {
+	characters, err := s.records.characterStore.List(ctx, &store.CharacterFilter{CampaignID: campaignID})
+	if err != nil {
+		return fmt.Errorf("failed to list characters: %w", err)
+	}
+	maps, err := s.records.mapStore.GetByCampaign(ctx, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to list maps: %w", err)
+	}
+	combats, err := s.records.combatStore.GetByCampaign(ctx, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to list combats: %w", err)
+	}
+
+	existing := make(map[string]bool, len(characters)+len(maps)+len(combats))
+	for _, character := range characters {
+		existing[character.ID] = true
+	}
+	for _, gameMap := range maps {
+		existing[gameMap.ID] = true
+	}
+	for _, combat := range combats {
+		existing[combat.ID] = true
+	}
+	kept := make(map[string]bool)
+
+	// 先恢复角色、地图和战斗，游戏状态引用地图和战斗
+	for _, character := range contents.Characters {
+		kept[character.ID] = true
+		if existing[character.ID] {
+			err = s.records.characterStore.Update(ctx, character)
+		} else {
+			err = s.records.characterStore.Create(ctx, character)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore character %s: %w", character.Name, err)
+		}
+	}
+	for _, gameMap := range parentsFirst(contents.Maps) {
+		kept[gameMap.ID] = true
+		if existing[gameMap.ID] {
+			err = s.records.mapStore.Update(ctx, gameMap)
+		} else {
+			err = s.records.mapStore.Create(ctx, gameMap)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore map %s: %w", gameMap.Name, err)
+		}
+	}
+	for _, combat := range contents.Combats {
+		kept[combat.ID] = true
+		if existing[combat.ID] {
+			err = s.records.combatStore.Update(ctx, combat)
+		} else {
+			err = s.records.combatStore.Create(ctx, combat)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore combat: %w", err)
+		}
+	}
+	if err := s.gameStateStore.Update(ctx, contents.GameState); err != nil {
+		return fmt.Errorf("failed to restore game state: %w", err)
+	}
+
+	// 删除存档后创建的记录；存档前已结束的战斗保留为历史
+	for _, combat := range combats {
+		if !kept[combat.ID] && combat.StartedAt.After(snapshot.CreatedAt) {
+			if err := s.records.combatStore.Delete(ctx, combat.ID); err != nil {
+				return fmt.Errorf("failed to delete combat: %w", err)
+			}
+			resp.RemovedCombats++
+		}
+	}
+	for _, gameMap := range maps {
+		if !kept[gameMap.ID] {
+			if err := s.records.mapStore.Delete(ctx, gameMap.ID); err != nil {
+				return fmt.Errorf("failed to delete map %s: %w", gameMap.Name, err)
+			}
+			resp.RemovedMaps++
+		}
+	}
+	for _, character := range characters {
+		if !kept[character.ID] {
+			if err := s.records.characterStore.Delete(ctx, character.ID); err != nil {
+				return fmt.Errorf("failed to delete character %s: %w", character.Name, err)
+			}
+			resp.RemovedCharacters++
+		}
+	}
+	return nil
+}
+
+// inConsistentTx runs fn in a repeatable read transaction when a transactor is configured
+func (s *CampaignService) inConsistentTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.transactor == nil {
+		return fn(ctx)
+	}
+	return s.transactor.InConsistentTx(ctx, fn)
+}
@@ -0,0 +1,343 @@
+// Package service provides business logic layer implementations
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/models"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/dnd-mcp/server/internal/rules/vision"
+)
+
+// MapStoreForVision defines the map store interface needed by vision service
+type MapStoreForVision interface {
+	Get(ctx context.Context, id string) (*models.Map, error)
+}
+
+// CharacterStoreForVision defines the character store interface needed by vision service
+type CharacterStoreForVision interface {
+	Get(ctx context.Context, id string) (*models.Character, error)
+}
+
+// CombatStoreForVision defines the combat store interface needed by vision service
+type CombatStoreForVision interface {
+	GetActive(ctx context.Context, campaignID string) (*models.Combat, error)
+}
+
+// VisionService provides line of sight and visibility business logic
+// 规则参考: PHB 第8章 - Vision and Light
+type VisionService struct {
+	mapStore       MapStoreForVision
+	characterStore CharacterStoreForVision
+	combatStore    CombatStoreForVision
+	diceService    *DiceService
+}
+
+// NewVisionService creates a new vision service
+// combatStore and diceService are optional; without them hidden creatures are not checked
+// against passive Perception
+func NewVisionService(mapStore MapStoreForVision, characterStore CharacterStoreForVision, combatStore CombatStoreForVision, diceService *DiceService) *VisionService {
+	return &VisionService{
+		mapStore:       mapStore,
+		characterStore: characterStore,
+		combatStore:    combatStore,
+		diceService:    diceService,
+	}
+}
+
+// VisibleToken 视野内的其他Token
+type VisibleToken struct {
+	TokenID     string            `json:"token_id"`
+	CharacterID string            `json:"character_id"`
+	Name        string            `json:"name,omitempty"`
+	Position    models.Position   `json:"position"`
+	Distance    int               `json:"distance"` // 英尺
+	Sense       string            `json:"sense"`    // 用于察觉的感官
+	Light       models.LightLevel `json:"light"`
+}
+
+// VisibleArea 某个Token的可见区域
+type VisibleArea struct {
+	MapID   string               `json:"map_id"`
+	TokenID string               `json:"token_id"`
+	Senses  map[string]int       `json:"senses,omitempty"`
+	Blinded bool                 `json:"blinded,omitempty"`
+	Cells   []vision.VisibleCell `json:"cells"`
+	Tokens  []VisibleToken       `json:"tokens"`
+}
+
+// SightCheck 一个Token能否看见另一个Token
+type SightCheck struct {
+	ViewerTokenID string            `json:"viewer_token_id"`
+	TargetTokenID string            `json:"target_token_id"`
+	CanSee        bool              `json:"can_see"`
+	Sense         string            `json:"sense,omitempty"`
+	Light         models.LightLevel `json:"light"`
+	Distance      int               `json:"distance"`
+	Reason        string            `json:"reason,omitempty"`
+}
+
+// PlayerMapView 按角色视野过滤后的战斗地图
+type PlayerMapView struct {
+	Map     *models.Map  `json:"map"`
+	Visible *VisibleArea `json:"visible"`
+}
+
+// viewContext 一次可见性计算所需的数据
+type viewContext struct {
+	battleMap *models.Map
+	field     *vision.Field
+	combat    *models.Combat
+}
+
+// GetVisibleArea 计算Token可以看见的格子和其他Token
+// 规则参考: PHB 第8章 - Vision and Light
+func (s *VisionService) GetVisibleArea(ctx context.Context, mapID, tokenID string) (*VisibleArea, error) {
+	vc, err := s.loadView(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	token := vc.battleMap.GetToken(tokenID)
+	if token == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "token not found on this map")
+	}
+	if err := s.authorizeViewer(ctx, vc.battleMap.CampaignID, token); err != nil {
+		return nil, err
+	}
+
+	return s.visibleArea(ctx, vc, token), nil
+}
+
+// CanSee 检查一个Token能否看见另一个Token
+// 考虑视线、光照、黑暗视觉/盲视/真实视觉、隐形以及躲藏（隐匿对抗被动察觉）
+// 规则参考: PHB 第7章 - Hiding / 第8章 - Vision and Light / 附录A - Invisible
+func (s *VisionService) CanSee(ctx context.Context, mapID, viewerTokenID, targetTokenID string) (*SightCheck, error) {
+	vc, err := s.loadView(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerToken := vc.battleMap.GetToken(viewerTokenID)
+	if viewerToken == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "viewer token not found on this map")
+	}
+	if err := s.authorizeViewer(ctx, vc.battleMap.CampaignID, viewerToken); err != nil {
+		return nil, err
+	}
+	targetToken := vc.battleMap.GetToken(targetTokenID)
+	if targetToken == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "target token not found on this map")
+	}
+
+	viewer, character := s.viewer(ctx, vc, viewerToken)
+	sight := s.seeToken(ctx, vc, viewer, character, targetToken)
+
+	return &SightCheck{
+		ViewerTokenID: viewerTokenID,
+		TargetTokenID: targetTokenID,
+		CanSee:        sight.Visible,
+		Sense:         sight.Sense,
+		Light:         sight.Light,
+		Distance:      sight.Distance,
+		Reason:        sight.Reason,
+	}, nil
+}
+
+// FilterBattleMapForCharacter 返回角色视角下的战斗地图
+// 仅保留自身、同伴以及角色能看见的Token，并隐藏未被发现的暗门，避免向玩家泄露隐藏的敌人
+func (s *VisionService) FilterBattleMapForCharacter(ctx context.Context, battleMap *models.Map, characterID string) (*PlayerMapView, error) {
+	if characterID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "character ID is required")
+	}
+
+	var own *models.Token
+	for i := range battleMap.Tokens {
+		if battleMap.Tokens[i].CharacterID == characterID {
+			own = &battleMap.Tokens[i]
+			break
+		}
+	}
+	if own == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "character has no token on this battle map")
+	}
+	if err := s.authorizeViewer(ctx, battleMap.CampaignID, own); err != nil {
+		return nil, err
+	}
+
+	vc := &viewContext{
+		battleMap: battleMap,
+		field:     vision.NewField(battleMap),
+		combat:    s.activeCombat(ctx, battleMap.CampaignID),
+	}
+	visible := s.visibleArea(ctx, vc, own)
+
+	seen := make(map[string]bool, len(visible.Tokens))
+	for _, t := range visible.Tokens {
+		seen[t.TokenID] = true
+	}
+
+	filtered := *battleMap
+	filtered.Tokens = make([]models.Token, 0, len(battleMap.Tokens))
+	for _, token := range battleMap.Tokens {
+		ally := token.Disposition == models.DispositionFriendly && own.Disposition == models.DispositionFriendly
+		if token.ID == own.ID || (!token.Hidden && (ally || seen[token.ID])) {
+			filtered.Tokens = append(filtered.Tokens, token)
+		}
+	}
+
+	filtered.Walls = make(models.Walls, 0, len(battleMap.Walls))
+	for _, wall := range battleMap.Walls {
+		if !wall.IsSecret() || wall.IsOpen() {
+			filtered.Walls = append(filtered.Walls, wall)
+		}
+	}
+
+	return &PlayerMapView{Map: &filtered, Visible: visible}, nil
+}
+
+// loadView 加载战斗地图及其当前战斗
+func (s *VisionService) loadView(ctx context.Context, mapID string) (*viewContext, error) {
+	if mapID == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "map ID is required")
+	}
+
+	battleMap, err := s.mapStore.Get(ctx, mapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map: %w", err)
+	}
+	if !battleMap.IsBattleMap() {
+		return nil, NewServiceError(ErrCodeInvalidInput, "vision is only supported on battle maps")
+	}
+
+	return &viewContext{
+		battleMap: battleMap,
+		field:     vision.NewField(battleMap),
+		combat:    s.activeCombat(ctx, battleMap.CampaignID),
+	}, nil
+}
+
+// visibleArea 计算Token的可见格子与可见Token
+func (s *VisionService) visibleArea(ctx context.Context, vc *viewContext, token *models.Token) *VisibleArea {
+	viewer, character := s.viewer(ctx, vc, token)
+
+	area := &VisibleArea{
+		MapID:   vc.battleMap.ID,
+		TokenID: token.ID,
+		Senses:  viewer.Senses(),
+		Blinded: viewer.Blinded,
+		Cells:   vc.field.VisibleCells(viewer),
+		Tokens:  make([]VisibleToken, 0),
+	}
+
+	for i := range vc.battleMap.Tokens {
+		other := &vc.battleMap.Tokens[i]
+		if other.ID == token.ID {
+			continue
+		}
+		sight := s.seeToken(ctx, vc, viewer, character, other)
+		if !sight.Visible {
+			continue
+		}
+		area.Tokens = append(area.Tokens, VisibleToken{
+			TokenID:     other.ID,
+			CharacterID: other.CharacterID,
+			Name:        other.Name,
+			Position:    other.Position,
+			Distance:    sight.Distance,
+			Sense:       sight.Sense,
+			Light:       sight.Light,
+		})
+	}
+	return area
+}
+
+// seeToken 检查观察者能否察觉目标Token，叠加隐形与躲藏规则
+func (s *VisionService) seeToken(ctx context.Context, vc *viewContext, viewer *vision.Viewer, character *models.Character, target *models.Token) vision.Sight {
+	sight := vc.field.SeeToken(viewer, target)
+	if !sight.Visible {
+		return sight
+	}
+
+	// 隐形生物只能被盲视或真实视觉察觉
+	// 规则参考: PHB 附录A - Invisible
+	normalSight := sight.Sense == vision.SenseSight || sight.Sense == vision.SenseDarkvision
+	if normalSight && s.isInvisible(ctx, vc, target.CharacterID) {
+		sight.Visible = false
+		sight.Reason = "invisible"
+		return sight
+	}
+
+	// 躲藏的生物：隐匿检定结果高于被动察觉时无法被发现
+	// 微光中依赖视觉的察觉具有劣势（被动察觉 -5）
+	// 规则参考: PHB 第7章 - Hiding / 第8章 - Lightly Obscured
+	if vc.combat == nil || character == nil {
+		return sight
+	}
+	participant := vc.combat.GetParticipantByCharacterID(target.CharacterID)
+	if participant == nil || !participant.Hidden {
+		return sight
+	}
+	passive := s.passivePerception(character)
+	if normalSight && (sight.Light == models.LightDim || sight.Sense == vision.SenseDarkvision) {
+		passive -= 5
+	}
+	if participant.StealthRoll > passive {
+		sight.Visible = false
+		sight.Reason = fmt.Sprintf("hidden (stealth %d vs passive Perception %d)", participant.StealthRoll, passive)
+	}
+	return sight
+}
+
+// viewer 根据Token关联角色的感官和状态构建观察者
+// 找不到角色（如导入的无角色Token）时按普通视觉处理
+func (s *VisionService) viewer(ctx context.Context, vc *viewContext, token *models.Token) (*vision.Viewer, *models.Character) {
+	character, conditions := s.characterConditions(ctx, vc, token.CharacterID)
+	return vision.NewViewer(token, character, conditions), character
+}
+
+// isInvisible 检查角色是否处于隐形状态
+func (s *VisionService) isInvisible(ctx context.Context, vc *viewContext, characterID string) bool {
+	_, conditions := s.characterConditions(ctx, vc, characterID)
+	for _, cond := range conditions {
+		if cond.Type == models.ConditionInvisible {
+			return true
+		}
+	}
+	return false
+}
+
+// characterConditions 获取角色及其全部状态（包括战斗中的临时状态）
+func (s *VisionService) characterConditions(ctx context.Context, vc *viewContext, characterID string) (*models.Character, []models.Condition) {
+	character, err := s.characterStore.Get(ctx, characterID)
+	if err != nil {
+		character = nil
+	}
+	var extra []models.Condition
+	if vc.combat != nil {
+		extra = participantConditions(vc.combat, characterID)
+	}
+	return character, rulescombat.CollectConditions(character, extra)
+}
+
+// passivePerception 计算被动察觉：10 + 察觉检定调整值
+// 规则参考: PHB 第7章 - Passive Checks
+func (s *VisionService) passivePerception(character *models.Character) int {
+	if s.diceService == nil {
+		return 10
+	}
+	return 10 + s.diceService.calculateCheckModifier(character, "wisdom", "perception")
+}
+
+// activeCombat 获取战役当前进行中的战斗，没有时返回 nil
+func (s *VisionService) activeCombat(ctx context.Context, campaignID string) *models.Combat {
+	if s.combatStore == nil || campaignID == "" {
+		return nil
+	}
+	combat, err := s.combatStore.GetActive(ctx, campaignID)
+	if err != nil || combat == nil || !combat.IsActive() {
+		return nil
+	}
+	return combat
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"sync"
+)
+
+// commitHooks collects the functions to run once a transaction commits
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+type commitHooksCtxKey struct{}
+
+// WithCommitHooks returns a context for a new transaction whose AfterCommit hooks are
+// collected, and a function that runs them. Transactors call it after a successful commit;
+// the hooks of a rolled back transaction are dropped.
+func WithCommitHooks(ctx context.Context) (context.Context, func()) {
+	hooks := &commitHooks{}
+	return context.WithValue(ctx, commitHooksCtxKey{}, hooks), hooks.run
+}
+
+// AfterCommit runs fn once the transaction of ctx commits, or right away outside a transaction
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(commitHooksCtxKey{}).(*commitHooks)
+	if !ok {
+		fn()
+		return
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+}
+
+// run calls the collected hooks in the order they were added
+func (h *commitHooks) run() {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
@@ -3,10 +3,15 @@ package store
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/dnd-mcp/server/internal/models"
 )
 
+// ErrNotFound is returned by stores when a requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
 // CampaignStore campaign storage interface
 type CampaignStore interface {
 	// Create creates a new campaign
@@ -162,3 +167,83 @@ type MapStore interface {
 	// GetByParent retrieves battle maps by parent location
 	GetByParent(ctx context.Context, parentID string) ([]*models.Map, error)
 }
+
+// EventStore game event storage interface
+type EventStore interface {
+	// Append stores an event and assigns its cursor
+	Append(ctx context.Context, event *models.GameEvent) error
+
+	// List lists a campaign's events after a cursor in cursor order
+	List(ctx context.Context, filter *EventFilter) ([]*models.GameEvent, error)
+
+	// Latest returns the cursor of a campaign's most recent event, or 0 when it has none
+	Latest(ctx context.Context, campaignID string) (int64, error)
+}
+
+// EventFilter event list filter
+type EventFilter struct {
+	// CampaignID filter by campaign ID (required)
+	CampaignID string
+
+	// After only return events with a cursor greater than this
+	After int64
+
+	// Types filter by event types (optional)
+	Types []models.EventType
+
+	// Limit max number of results
+	Limit int
+}
+
+// Transactor runs a function in a database transaction
+type Transactor interface {
+	// InTx runs fn in a transaction that stores called with fn's context take part in.
+	// It commits when fn returns nil and rolls back otherwise; functions passed to
+	// AfterCommit with fn's context run only after a commit.
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ConsistentTransactor runs a function in a transaction that reads one point-in-time view of the database
+type ConsistentTransactor interface {
+	Transactor
+
+	// InConsistentTx is InTx at repeatable read isolation: every query in fn sees the
+	// database as it was when the transaction started
+	InConsistentTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// AuditStore audit log storage interface
+type AuditStore interface {
+	// Append stores an audit entry and assigns its ID
+	Append(ctx context.Context, entry *models.AuditEntry) error
+
+	// List lists a campaign's audit entries, newest first
+	List(ctx context.Context, filter *AuditFilter) ([]*models.AuditEntry, error)
+
+	// MarkUndone marks entries as undone. It fails if any of them is missing or already undone.
+	MarkUndone(ctx context.Context, ids []int64, undoneAt time.Time) error
+}
+
+// AuditFilter audit log list filter
+type AuditFilter struct {
+	// CampaignID filter by campaign ID (required)
+	CampaignID string
+
+	// IncludeUndone include entries that have been undone
+	IncludeUndone bool
+
+	// Limit max number of results
+	Limit int
+}
+
+// SnapshotStore campaign snapshot storage interface
+type SnapshotStore interface {
+	// Create stores a snapshot
+	Create(ctx context.Context, snapshot *models.Snapshot) error
+
+	// Get retrieves a snapshot with its data
+	Get(ctx context.Context, id string) (*models.Snapshot, error)
+
+	// ListByCampaign lists a campaign's snapshots without their data, newest first
+	ListByCampaign(ctx context.Context, campaignID string) ([]*models.Snapshot, error)
+}
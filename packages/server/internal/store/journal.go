@@ -0,0 +1,392 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// Revision is the state of one record before and after the writes made while a journal was active.
+// Before is nil when the record was created and After is nil when it was deleted.
+type Revision struct {
+	Entity     Entity
+	ID         string
+	CampaignID string
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
+// Journal collects the revisions of records written with its context.
+// Only the first state seen before a write is kept, so a record written several
+// times yields one revision from its original to its final state.
+type Journal struct {
+	mu        sync.Mutex
+	revisions []*Revision
+	index     map[journalKey]*Revision
+}
+
+type journalKey struct {
+	entity Entity
+	id     string
+}
+
+type journalCtxKey struct{}
+
+// NewJournal creates an empty journal
+func NewJournal() *Journal {
+	return &Journal{index: make(map[journalKey]*Revision)}
+}
+
+// WithJournal returns a context whose writes through journaled stores are recorded in j
+func WithJournal(ctx context.Context, j *Journal) context.Context {
+	return context.WithValue(ctx, journalCtxKey{}, j)
+}
+
+// JournalFromContext returns the journal recording writes for ctx, if any
+func JournalFromContext(ctx context.Context) *Journal {
+	j, _ := ctx.Value(journalCtxKey{}).(*Journal)
+	return j
+}
+
+// Revisions returns the recorded revisions in the order records were first written.
+// Records that were created and deleted again, or written back unchanged, are left out.
+func (j *Journal) Revisions() []Revision {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	revisions := make([]Revision, 0, len(j.revisions))
+	for _, rev := range j.revisions {
+		if rev.Before == nil && rev.After == nil {
+			continue
+		}
+		if rev.Before != nil && rev.After != nil && models.SameJSON(rev.Before, rev.After) {
+			continue
+		}
+		revisions = append(revisions, *rev)
+	}
+	return revisions
+}
+
+// tracked reports whether the record's original state has been captured
+func (j *Journal) tracked(entity Entity, id string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, ok := j.index[journalKey{entity, id}]
+	return ok
+}
+
+// begin captures the record's state before its first write
+func (j *Journal) begin(entity Entity, id, campaignID string, before interface{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key := journalKey{entity, id}
+	if _, ok := j.index[key]; ok {
+		return
+	}
+	rev := &Revision{Entity: entity, ID: id, CampaignID: campaignID, Before: marshalState(before)}
+	j.index[key] = rev
+	j.revisions = append(j.revisions, rev)
+}
+
+// commit records the record's state after a successful write; nil means deleted
+func (j *Journal) commit(entity Entity, id, campaignID string, after interface{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key := journalKey{entity, id}
+	rev, ok := j.index[key]
+	if !ok {
+		rev = &Revision{Entity: entity, ID: id}
+		j.index[key] = rev
+		j.revisions = append(j.revisions, rev)
+	}
+	if campaignID != "" {
+		rev.CampaignID = campaignID
+	}
+	rev.After = marshalState(after)
+}
+
+// marshalState encodes a record, keeping nil for missing ones
+func marshalState(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+// JournalCharacterStore wraps a character store so that writes are recorded in the context's journal
+func JournalCharacterStore(inner CharacterStore) CharacterStore {
+	return &journaledCharacterStore{CharacterStore: inner}
+}
+
+type journaledCharacterStore struct {
+	CharacterStore
+}
+
+// before captures a character's state before its first write in the journal
+func (s *journaledCharacterStore) before(ctx context.Context, j *Journal, id string) {
+	if id == "" || j.tracked(EntityCharacter, id) {
+		return
+	}
+	if character, err := s.CharacterStore.Get(ctx, id); err == nil {
+		j.begin(EntityCharacter, id, character.CampaignID, character)
+		return
+	}
+	j.begin(EntityCharacter, id, "", nil)
+}
+
+// stored re-reads a written character so its state matches what later reads return
+func (s *journaledCharacterStore) stored(ctx context.Context, id string, written *models.Character) interface{} {
+	if character, err := s.CharacterStore.Get(ctx, id); err == nil {
+		return character
+	}
+	return written
+}
+
+func (s *journaledCharacterStore) Create(ctx context.Context, character *models.Character) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.CharacterStore.Create(ctx, character)
+	}
+	s.before(ctx, j, character.ID)
+	if err := s.CharacterStore.Create(ctx, character); err != nil {
+		return err
+	}
+	j.commit(EntityCharacter, character.ID, character.CampaignID, s.stored(ctx, character.ID, character))
+	return nil
+}
+
+func (s *journaledCharacterStore) Update(ctx context.Context, character *models.Character) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.CharacterStore.Update(ctx, character)
+	}
+	s.before(ctx, j, character.ID)
+	if err := s.CharacterStore.Update(ctx, character); err != nil {
+		return err
+	}
+	j.commit(EntityCharacter, character.ID, character.CampaignID, s.stored(ctx, character.ID, character))
+	return nil
+}
+
+func (s *journaledCharacterStore) Delete(ctx context.Context, id string) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.CharacterStore.Delete(ctx, id)
+	}
+	s.before(ctx, j, id)
+	if err := s.CharacterStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	j.commit(EntityCharacter, id, "", nil)
+	return nil
+}
+
+// JournalCombatStore wraps a combat store so that writes are recorded in the context's journal
+func JournalCombatStore(inner CombatStore) CombatStore {
+	return &journaledCombatStore{CombatStore: inner}
+}
+
+type journaledCombatStore struct {
+	CombatStore
+}
+
+func (s *journaledCombatStore) before(ctx context.Context, j *Journal, id string) {
+	if id == "" || j.tracked(EntityCombat, id) {
+		return
+	}
+	if combat, err := s.CombatStore.Get(ctx, id); err == nil {
+		j.begin(EntityCombat, id, combat.CampaignID, combat)
+		return
+	}
+	j.begin(EntityCombat, id, "", nil)
+}
+
+// stored re-reads a written combat so its state matches what later reads return
+func (s *journaledCombatStore) stored(ctx context.Context, id string, written *models.Combat) interface{} {
+	if combat, err := s.CombatStore.Get(ctx, id); err == nil {
+		return combat
+	}
+	return written
+}
+
+func (s *journaledCombatStore) Create(ctx context.Context, combat *models.Combat) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.CombatStore.Create(ctx, combat)
+	}
+	s.before(ctx, j, combat.ID)
+	if err := s.CombatStore.Create(ctx, combat); err != nil {
+		return err
+	}
+	j.commit(EntityCombat, combat.ID, combat.CampaignID, s.stored(ctx, combat.ID, combat))
+	return nil
+}
+
+func (s *journaledCombatStore) Update(ctx context.Context, combat *models.Combat) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.CombatStore.Update(ctx, combat)
+	}
+	s.before(ctx, j, combat.ID)
+	if err := s.CombatStore.Update(ctx, combat); err != nil {
+		return err
+	}
+	j.commit(EntityCombat, combat.ID, combat.CampaignID, s.stored(ctx, combat.ID, combat))
+	return nil
+}
+
+func (s *journaledCombatStore) Delete(ctx context.Context, id string) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.CombatStore.Delete(ctx, id)
+	}
+	s.before(ctx, j, id)
+	if err := s.CombatStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	j.commit(EntityCombat, id, "", nil)
+	return nil
+}
+
+// JournalMapStore wraps a map store so that writes are recorded in the context's journal
+func JournalMapStore(inner MapStore) MapStore {
+	return &journaledMapStore{MapStore: inner}
+}
+
+type journaledMapStore struct {
+	MapStore
+}
+
+func (s *journaledMapStore) before(ctx context.Context, j *Journal, id string) {
+	if id == "" || j.tracked(EntityMap, id) {
+		return
+	}
+	if gameMap, err := s.MapStore.Get(ctx, id); err == nil {
+		j.begin(EntityMap, id, gameMap.CampaignID, gameMap)
+		return
+	}
+	j.begin(EntityMap, id, "", nil)
+}
+
+// stored re-reads a written map so its state matches what later reads return
+func (s *journaledMapStore) stored(ctx context.Context, id string, written *models.Map) interface{} {
+	if gameMap, err := s.MapStore.Get(ctx, id); err == nil {
+		return gameMap
+	}
+	return written
+}
+
+func (s *journaledMapStore) Create(ctx context.Context, gameMap *models.Map) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.MapStore.Create(ctx, gameMap)
+	}
+	s.before(ctx, j, gameMap.ID)
+	if err := s.MapStore.Create(ctx, gameMap); err != nil {
+		return err
+	}
+	j.commit(EntityMap, gameMap.ID, gameMap.CampaignID, s.stored(ctx, gameMap.ID, gameMap))
+	return nil
+}
+
+func (s *journaledMapStore) Update(ctx context.Context, gameMap *models.Map) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.MapStore.Update(ctx, gameMap)
+	}
+	s.before(ctx, j, gameMap.ID)
+	if err := s.MapStore.Update(ctx, gameMap); err != nil {
+		return err
+	}
+	j.commit(EntityMap, gameMap.ID, gameMap.CampaignID, s.stored(ctx, gameMap.ID, gameMap))
+	return nil
+}
+
+func (s *journaledMapStore) Delete(ctx context.Context, id string) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.MapStore.Delete(ctx, id)
+	}
+	s.before(ctx, j, id)
+	if err := s.MapStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	j.commit(EntityMap, id, "", nil)
+	return nil
+}
+
+// JournalGameStateStore wraps a game state store so that writes are recorded in the context's journal.
+// Game states are keyed by their campaign ID.
+func JournalGameStateStore(inner GameStateStore) GameStateStore {
+	return &journaledGameStateStore{GameStateStore: inner}
+}
+
+type journaledGameStateStore struct {
+	GameStateStore
+}
+
+func (s *journaledGameStateStore) before(ctx context.Context, j *Journal, campaignID string) {
+	if campaignID == "" || j.tracked(EntityGameState, campaignID) {
+		return
+	}
+	if gameState, err := s.GameStateStore.Get(ctx, campaignID); err == nil {
+		j.begin(EntityGameState, campaignID, campaignID, gameState)
+		return
+	}
+	j.begin(EntityGameState, campaignID, campaignID, nil)
+}
+
+// stored re-reads a written game state so its state matches what later reads return
+func (s *journaledGameStateStore) stored(ctx context.Context, written *models.GameState) interface{} {
+	if gameState, err := s.GameStateStore.Get(ctx, written.CampaignID); err == nil {
+		return gameState
+	}
+	return written
+}
+
+func (s *journaledGameStateStore) Create(ctx context.Context, gameState *models.GameState) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.GameStateStore.Create(ctx, gameState)
+	}
+	s.before(ctx, j, gameState.CampaignID)
+	if err := s.GameStateStore.Create(ctx, gameState); err != nil {
+		return err
+	}
+	j.commit(EntityGameState, gameState.CampaignID, gameState.CampaignID, s.stored(ctx, gameState))
+	return nil
+}
+
+func (s *journaledGameStateStore) Update(ctx context.Context, gameState *models.GameState) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.GameStateStore.Update(ctx, gameState)
+	}
+	s.before(ctx, j, gameState.CampaignID)
+	if err := s.GameStateStore.Update(ctx, gameState); err != nil {
+		return err
+	}
+	j.commit(EntityGameState, gameState.CampaignID, gameState.CampaignID, s.stored(ctx, gameState))
+	return nil
+}
+
+func (s *journaledGameStateStore) Delete(ctx context.Context, campaignID string) error {
+	j := JournalFromContext(ctx)
+	if j == nil {
+		return s.GameStateStore.Delete(ctx, campaignID)
+	}
+	s.before(ctx, j, campaignID)
+	if err := s.GameStateStore.Delete(ctx, campaignID); err != nil {
+		return err
+	}
+	j.commit(EntityGameState, campaignID, campaignID, nil)
+	return nil
+}
@@ -0,0 +1,268 @@
+// Package store provides storage interface definitions
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dnd-mcp/server/internal/models"
+)
+
+// Entity identifies the kind of record a change applies to
+type Entity string
+
+const (
+	// EntityCampaign campaign records
+	EntityCampaign Entity = "campaign"
+	// EntityGameState game state records
+	EntityGameState Entity = "game_state"
+	// EntityCharacter character records
+	EntityCharacter Entity = "character"
+	// EntityCombat combat records
+	EntityCombat Entity = "combat"
+	// EntityMap map records
+	EntityMap Entity = "map"
+)
+
+// ChangeOp identifies the kind of write
+type ChangeOp string
+
+const (
+	// ChangeCreated a record was created
+	ChangeCreated ChangeOp = "created"
+	// ChangeUpdated a record was updated
+	ChangeUpdated ChangeOp = "updated"
+	// ChangeDeleted a record was deleted
+	ChangeDeleted ChangeOp = "deleted"
+)
+
+// Change describes a successful write to a store
+type Change struct {
+	Entity     Entity   `json:"entity"`
+	Op         ChangeOp `json:"op"`
+	ID         string   `json:"id"`
+	CampaignID string   `json:"campaign_id,omitempty"`
+}
+
+// ChangeObserver is called after every successful write
+type ChangeObserver func(ctx context.Context, change Change)
+
+// ChangeFeed fans out store changes to observers
+type ChangeFeed struct {
+	mu        sync.RWMutex
+	observers []ChangeObserver
+}
+
+// NewChangeFeed creates an empty change feed
+func NewChangeFeed() *ChangeFeed {
+	return &ChangeFeed{}
+}
+
+// Subscribe registers an observer for every subsequent change
+func (f *ChangeFeed) Subscribe(observer ChangeObserver) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.observers = append(f.observers, observer)
+}
+
+// Publish delivers a change to every observer in registration order
+func (f *ChangeFeed) Publish(ctx context.Context, change Change) {
+	f.mu.RLock()
+	observers := append([]ChangeObserver(nil), f.observers...)
+	f.mu.RUnlock()
+
+	for _, observer := range observers {
+		observer(ctx, change)
+	}
+}
+
+// ObserveCampaignStore wraps a campaign store so that writes are published to the feed
+func ObserveCampaignStore(inner CampaignStore, feed *ChangeFeed) CampaignStore {
+	return &observedCampaignStore{CampaignStore: inner, feed: feed}
+}
+
+type observedCampaignStore struct {
+	CampaignStore
+	feed *ChangeFeed
+}
+
+func (s *observedCampaignStore) Create(ctx context.Context, campaign *models.Campaign) error {
+	if err := s.CampaignStore.Create(ctx, campaign); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCampaign, Op: ChangeCreated, ID: campaign.ID, CampaignID: campaign.ID})
+	return nil
+}
+
+func (s *observedCampaignStore) Update(ctx context.Context, campaign *models.Campaign) error {
+	if err := s.CampaignStore.Update(ctx, campaign); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCampaign, Op: ChangeUpdated, ID: campaign.ID, CampaignID: campaign.ID})
+	return nil
+}
+
+func (s *observedCampaignStore) Delete(ctx context.Context, id string) error {
+	if err := s.CampaignStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCampaign, Op: ChangeDeleted, ID: id, CampaignID: id})
+	return nil
+}
+
+func (s *observedCampaignStore) HardDelete(ctx context.Context, id string) error {
+	if err := s.CampaignStore.HardDelete(ctx, id); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCampaign, Op: ChangeDeleted, ID: id, CampaignID: id})
+	return nil
+}
+
+// ObserveGameStateStore wraps a game state store so that writes are published to the feed
+func ObserveGameStateStore(inner GameStateStore, feed *ChangeFeed) GameStateStore {
+	return &observedGameStateStore{GameStateStore: inner, feed: feed}
+}
+
+type observedGameStateStore struct {
+	GameStateStore
+	feed *ChangeFeed
+}
+
+func (s *observedGameStateStore) Create(ctx context.Context, gameState *models.GameState) error {
+	if err := s.GameStateStore.Create(ctx, gameState); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityGameState, Op: ChangeCreated, ID: gameState.ID, CampaignID: gameState.CampaignID})
+	return nil
+}
+
+func (s *observedGameStateStore) Update(ctx context.Context, gameState *models.GameState) error {
+	if err := s.GameStateStore.Update(ctx, gameState); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityGameState, Op: ChangeUpdated, ID: gameState.ID, CampaignID: gameState.CampaignID})
+	return nil
+}
+
+func (s *observedGameStateStore) Delete(ctx context.Context, campaignID string) error {
+	if err := s.GameStateStore.Delete(ctx, campaignID); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityGameState, Op: ChangeDeleted, CampaignID: campaignID})
+	return nil
+}
+
+// ObserveCharacterStore wraps a character store so that writes are published to the feed
+func ObserveCharacterStore(inner CharacterStore, feed *ChangeFeed) CharacterStore {
+	return &observedCharacterStore{CharacterStore: inner, feed: feed}
+}
+
+type observedCharacterStore struct {
+	CharacterStore
+	feed *ChangeFeed
+}
+
+func (s *observedCharacterStore) Create(ctx context.Context, character *models.Character) error {
+	if err := s.CharacterStore.Create(ctx, character); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCharacter, Op: ChangeCreated, ID: character.ID, CampaignID: character.CampaignID})
+	return nil
+}
+
+func (s *observedCharacterStore) Update(ctx context.Context, character *models.Character) error {
+	if err := s.CharacterStore.Update(ctx, character); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCharacter, Op: ChangeUpdated, ID: character.ID, CampaignID: character.CampaignID})
+	return nil
+}
+
+func (s *observedCharacterStore) Delete(ctx context.Context, id string) error {
+	// The campaign is only known before the row is gone
+	var campaignID string
+	if character, err := s.CharacterStore.Get(ctx, id); err == nil {
+		campaignID = character.CampaignID
+	}
+	if err := s.CharacterStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCharacter, Op: ChangeDeleted, ID: id, CampaignID: campaignID})
+	return nil
+}
+
+// ObserveCombatStore wraps a combat store so that writes are published to the feed
+func ObserveCombatStore(inner CombatStore, feed *ChangeFeed) CombatStore {
+	return &observedCombatStore{CombatStore: inner, feed: feed}
+}
+
+type observedCombatStore struct {
+	CombatStore
+	feed *ChangeFeed
+}
+
+func (s *observedCombatStore) Create(ctx context.Context, combat *models.Combat) error {
+	if err := s.CombatStore.Create(ctx, combat); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCombat, Op: ChangeCreated, ID: combat.ID, CampaignID: combat.CampaignID})
+	return nil
+}
+
+func (s *observedCombatStore) Update(ctx context.Context, combat *models.Combat) error {
+	if err := s.CombatStore.Update(ctx, combat); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCombat, Op: ChangeUpdated, ID: combat.ID, CampaignID: combat.CampaignID})
+	return nil
+}
+
+func (s *observedCombatStore) Delete(ctx context.Context, id string) error {
+	var campaignID string
+	if combat, err := s.CombatStore.Get(ctx, id); err == nil {
+		campaignID = combat.CampaignID
+	}
+	if err := s.CombatStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityCombat, Op: ChangeDeleted, ID: id, CampaignID: campaignID})
+	return nil
+}
+
+// ObserveMapStore wraps a map store so that writes are published to the feed
+func ObserveMapStore(inner MapStore, feed *ChangeFeed) MapStore {
+	return &observedMapStore{MapStore: inner, feed: feed}
+}
+
+type observedMapStore struct {
+	MapStore
+	feed *ChangeFeed
+}
+
+func (s *observedMapStore) Create(ctx context.Context, gameMap *models.Map) error {
+	if err := s.MapStore.Create(ctx, gameMap); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityMap, Op: ChangeCreated, ID: gameMap.ID, CampaignID: gameMap.CampaignID})
+	return nil
+}
+
+func (s *observedMapStore) Update(ctx context.Context, gameMap *models.Map) error {
+	if err := s.MapStore.Update(ctx, gameMap); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityMap, Op: ChangeUpdated, ID: gameMap.ID, CampaignID: gameMap.CampaignID})
+	return nil
+}
+
+func (s *observedMapStore) Delete(ctx context.Context, id string) error {
+	var campaignID string
+	if gameMap, err := s.MapStore.Get(ctx, id); err == nil {
+		campaignID = gameMap.CampaignID
+	}
+	if err := s.MapStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.feed.Publish(ctx, Change{Entity: EntityMap, Op: ChangeDeleted, ID: id, CampaignID: campaignID})
+	return nil
+}
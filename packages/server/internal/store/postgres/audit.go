@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// defaultAuditLimit caps audit listings without an explicit limit
+const defaultAuditLimit = 50
+
+// AuditStore implements audit log storage using PostgreSQL
+type AuditStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewAuditStore creates a new audit store
+func NewAuditStore(client *Client) *AuditStore {
+	return &AuditStore{pool: client.Pool()}
+}
+
+// Append stores an audit entry and assigns its ID
+func (s *AuditStore) Append(ctx context.Context, entry *models.AuditEntry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	argumentsJSON, err := json.Marshal(entry.Arguments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal arguments: %w", err)
+	}
+	changesJSON, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (campaign_id, actor_id, actor_role, tool, arguments, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err = conn(ctx, s.pool).QueryRow(ctx, query,
+		entry.CampaignID,
+		entry.ActorID,
+		entry.ActorRole,
+		entry.Tool,
+		argumentsJSON,
+		changesJSON,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List lists a campaign's audit entries, newest first
+func (s *AuditStore) List(ctx context.Context, filter *store.AuditFilter) ([]*models.AuditEntry, error) {
+	if filter == nil || filter.CampaignID == "" {
+		return nil, fmt.Errorf("campaign ID is required")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	query := `
+		SELECT id, campaign_id, actor_id, actor_role, tool, arguments, changes, created_at, undone_at
+		FROM audit_log
+		WHERE campaign_id = $1
+	`
+	if !filter.IncludeUndone {
+		query += " AND undone_at IS NULL"
+	}
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT %d", limit)
+
+	rows, err := conn(ctx, s.pool).Query(ctx, query, filter.CampaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0)
+	for rows.Next() {
+		var (
+			entry         models.AuditEntry
+			argumentsJSON []byte
+			changesJSON   []byte
+		)
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CampaignID,
+			&entry.ActorID,
+			&entry.ActorRole,
+			&entry.Tool,
+			&argumentsJSON,
+			&changesJSON,
+			&entry.CreatedAt,
+			&entry.UndoneAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if err := json.Unmarshal(argumentsJSON, &entry.Arguments); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+		}
+		if err := json.Unmarshal(changesJSON, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal changes: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkUndone marks entries as undone. It fails if any of them is missing or already undone,
+// which rolls back an undo racing with another one.
+func (s *AuditStore) MarkUndone(ctx context.Context, ids []int64, undoneAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `UPDATE audit_log SET undone_at = $1 WHERE id = ANY($2) AND undone_at IS NULL`
+
+	result, err := conn(ctx, s.pool).Exec(ctx, query, undoneAt, ids)
+	if err != nil {
+		return fmt.Errorf("failed to mark audit entries undone: %w", err)
+	}
+
+	if result.RowsAffected() != int64(len(ids)) {
+		return fmt.Errorf("audit entries already undone")
+	}
+
+	return nil
+}
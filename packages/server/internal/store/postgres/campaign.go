@@ -50,7 +50,7 @@ func (s *CampaignStore) Create(ctx context.Context, campaign *models.Campaign) e
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err = s.pool.Exec(ctx, query,
+	_, err = conn(ctx, s.pool).Exec(ctx, query,
 		campaign.ID,
 		campaign.Name,
 		nullString(campaign.Description),
@@ -133,7 +133,7 @@ func (s *CampaignStore) List(ctx context.Context, filter *store.CampaignFilter)
 		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
 	}
 
-	rows, err := s.pool.Query(ctx, query, args...)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list campaigns: %w", err)
 	}
@@ -171,7 +171,7 @@ func (s *CampaignStore) Update(ctx context.Context, campaign *models.Campaign) e
 		WHERE id = $7 AND deleted_at IS NULL
 	`
 
-	result, err := s.pool.Exec(ctx, query,
+	result, err := conn(ctx, s.pool).Exec(ctx, query,
 		campaign.Name,
 		nullString(campaign.Description),
 		campaign.DMID,
@@ -202,7 +202,7 @@ func (s *CampaignStore) Delete(ctx context.Context, id string) error {
 		WHERE id = $4 AND deleted_at IS NULL
 	`
 
-	result, err := s.pool.Exec(ctx, query, now, string(models.CampaignStatusArchived), now, id)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, now, string(models.CampaignStatusArchived), now, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete campaign: %w", err)
 	}
@@ -218,7 +218,7 @@ func (s *CampaignStore) Delete(ctx context.Context, id string) error {
 func (s *CampaignStore) HardDelete(ctx context.Context, id string) error {
 	query := `DELETE FROM campaigns WHERE id = $1`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to hard delete campaign: %w", err)
 	}
@@ -258,7 +258,7 @@ func (s *CampaignStore) Count(ctx context.Context, filter *store.CampaignFilter)
 	}
 
 	var count int64
-	err := s.pool.QueryRow(ctx, query, args...).Scan(&count)
+	err := conn(ctx, s.pool).QueryRow(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count campaigns: %w", err)
 	}
@@ -268,7 +268,7 @@ func (s *CampaignStore) Count(ctx context.Context, filter *store.CampaignFilter)
 
 // scanCampaign scans a single campaign using the provided query
 func (s *CampaignStore) scanCampaign(ctx context.Context, query string, args ...interface{}) (*models.Campaign, error) {
-	row := s.pool.QueryRow(ctx, query, args...)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, args...)
 	return scanCampaignFromRow(row)
 }
 
@@ -342,5 +342,5 @@ func nullString(s string) interface{} {
 // Store errors
 var (
 	// ErrNotFound record not found error
-	ErrNotFound = errors.New("record not found")
+	ErrNotFound = store.ErrNotFound
 )
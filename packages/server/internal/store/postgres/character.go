@@ -151,7 +151,7 @@ func (s *CharacterStore) Create(ctx context.Context, character *models.Character
 		        $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38)
 	`
 
-	_, err = s.pool.Exec(ctx, query,
+	_, err = conn(ctx, s.pool).Exec(ctx, query,
 		character.ID,
 		character.CampaignID,
 		character.Name,
@@ -296,7 +296,7 @@ func (s *CharacterStore) List(ctx context.Context, filter *store.CharacterFilter
 		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
 	}
 
-	rows, err := s.pool.Query(ctx, query, args...)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list characters: %w", err)
 	}
@@ -433,7 +433,7 @@ func (s *CharacterStore) Update(ctx context.Context, character *models.Character
 		WHERE id = $36
 	`
 
-	result, err := s.pool.Exec(ctx, query,
+	result, err := conn(ctx, s.pool).Exec(ctx, query,
 		character.Name,
 		character.IsNPC,
 		nullString(string(character.NPCType)),
@@ -487,7 +487,7 @@ func (s *CharacterStore) Update(ctx context.Context, character *models.Character
 func (s *CharacterStore) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM characters WHERE id = $1`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete character: %w", err)
 	}
@@ -535,7 +535,7 @@ func (s *CharacterStore) Count(ctx context.Context, filter *store.CharacterFilte
 	}
 
 	var count int64
-	err := s.pool.QueryRow(ctx, query, args...).Scan(&count)
+	err := conn(ctx, s.pool).QueryRow(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count characters: %w", err)
 	}
@@ -545,7 +545,7 @@ func (s *CharacterStore) Count(ctx context.Context, filter *store.CharacterFilte
 
 // scanCharacter scans a single character using the provided query
 func (s *CharacterStore) scanCharacter(ctx context.Context, query string, args ...interface{}) (*models.Character, error) {
-	row := s.pool.QueryRow(ctx, query, args...)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, args...)
 	return scanCharacterFromRow(row)
 }
 
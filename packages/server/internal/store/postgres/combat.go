@@ -52,7 +52,7 @@ func (s *CombatStore) Create(ctx context.Context, combat *models.Combat) error {
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
-	_, err = s.pool.Exec(ctx, query,
+	_, err = conn(ctx, s.pool).Exec(ctx, query,
 		combat.ID,
 		combat.CampaignID,
 		string(combat.Status),
@@ -92,7 +92,7 @@ func (s *CombatStore) GetByCampaign(ctx context.Context, campaignID string) ([]*
 		ORDER BY started_at DESC
 	`
 
-	rows, err := s.pool.Query(ctx, query, campaignID)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, campaignID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get combats by campaign: %w", err)
 	}
@@ -146,7 +146,7 @@ func (s *CombatStore) Update(ctx context.Context, combat *models.Combat) error {
 		WHERE id = $8
 	`
 
-	result, err := s.pool.Exec(ctx, query,
+	result, err := conn(ctx, s.pool).Exec(ctx, query,
 		string(combat.Status),
 		combat.Round,
 		combat.TurnIndex,
@@ -172,7 +172,7 @@ func (s *CombatStore) Update(ctx context.Context, combat *models.Combat) error {
 func (s *CombatStore) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM combats WHERE id = $1`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete combat: %w", err)
 	}
@@ -186,7 +186,7 @@ func (s *CombatStore) Delete(ctx context.Context, id string) error {
 
 // scanCombat scans a single combat using the provided query
 func (s *CombatStore) scanCombat(ctx context.Context, query string, args ...interface{}) (*models.Combat, error) {
-	row := s.pool.QueryRow(ctx, query, args...)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, args...)
 	return scanCombatFromRow(row)
 }
 
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// defaultEventLimit caps event listings without an explicit limit
+const defaultEventLimit = 100
+
+// EventStore implements game event storage using PostgreSQL
+type EventStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewEventStore creates a new event store
+func NewEventStore(client *Client) *EventStore {
+	return &EventStore{pool: client.Pool()}
+}
+
+// Append stores an event and assigns its cursor
+func (s *EventStore) Append(ctx context.Context, event *models.GameEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	dataJSON, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	query := `
+		INSERT INTO game_events (campaign_id, type, data, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING cursor
+	`
+
+	err = conn(ctx, s.pool).QueryRow(ctx, query,
+		event.CampaignID,
+		string(event.Type),
+		dataJSON,
+		event.CreatedAt,
+	).Scan(&event.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to append event: %w", err)
+	}
+
+	return nil
+}
+
+// List lists a campaign's events after a cursor in cursor order
+func (s *EventStore) List(ctx context.Context, filter *store.EventFilter) ([]*models.GameEvent, error) {
+	if filter == nil || filter.CampaignID == "" {
+		return nil, fmt.Errorf("campaign ID is required")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+
+	query := `
+		SELECT cursor, campaign_id, type, data, created_at
+		FROM game_events
+		WHERE campaign_id = $1 AND cursor > $2
+	`
+	args := []interface{}{filter.CampaignID, filter.After}
+
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		query += " AND type = ANY($3)"
+		args = append(args, types)
+	}
+
+	query += fmt.Sprintf(" ORDER BY cursor ASC LIMIT %d", limit)
+
+	rows, err := conn(ctx, s.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.GameEvent, 0)
+	for rows.Next() {
+		var (
+			event     models.GameEvent
+			eventType string
+			dataJSON  []byte
+		)
+		if err := rows.Scan(&event.Cursor, &event.CampaignID, &eventType, &dataJSON, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		event.Type = models.EventType(eventType)
+		if err := json.Unmarshal(dataJSON, &event.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Latest returns the cursor of a campaign's most recent event, or 0 when it has none
+func (s *EventStore) Latest(ctx context.Context, campaignID string) (int64, error) {
+	query := `SELECT COALESCE(MAX(cursor), 0) FROM game_events WHERE campaign_id = $1`
+
+	var cursor int64
+	if err := conn(ctx, s.pool).QueryRow(ctx, query, campaignID).Scan(&cursor); err != nil {
+		return 0, fmt.Errorf("failed to get latest event: %w", err)
+	}
+
+	return cursor, nil
+}
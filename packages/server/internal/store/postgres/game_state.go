@@ -60,7 +60,7 @@ func (s *GameStateStore) Create(ctx context.Context, gameState *models.GameState
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
-	_, err = s.pool.Exec(ctx, query,
+	_, err = conn(ctx, s.pool).Exec(ctx, query,
 		gameState.ID,
 		gameState.CampaignID,
 		gameTimeJSON,
@@ -134,7 +134,7 @@ func (s *GameStateStore) Update(ctx context.Context, gameState *models.GameState
 		WHERE campaign_id = $9
 	`
 
-	result, err := s.pool.Exec(ctx, query,
+	result, err := conn(ctx, s.pool).Exec(ctx, query,
 		gameTimeJSON,
 		nullJSON(partyPositionJSON),
 		nullString(gameState.CurrentMapID),
@@ -161,7 +161,7 @@ func (s *GameStateStore) Update(ctx context.Context, gameState *models.GameState
 func (s *GameStateStore) Delete(ctx context.Context, campaignID string) error {
 	query := `DELETE FROM game_states WHERE campaign_id = $1`
 
-	result, err := s.pool.Exec(ctx, query, campaignID)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, campaignID)
 	if err != nil {
 		return fmt.Errorf("failed to delete game state: %w", err)
 	}
@@ -175,7 +175,7 @@ func (s *GameStateStore) Delete(ctx context.Context, campaignID string) error {
 
 // scanGameState scans a single game state using the provided query
 func (s *GameStateStore) scanGameState(ctx context.Context, query string, args ...interface{}) (*models.GameState, error) {
-	row := s.pool.QueryRow(ctx, query, args...)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, args...)
 	return scanGameStateFromRow(row)
 }
 
@@ -94,7 +94,7 @@ func (s *MapStore) Create(ctx context.Context, gameMap *models.Map) error {
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
-	_, err = s.pool.Exec(ctx, query,
+	_, err = conn(ctx, s.pool).Exec(ctx, query,
 		gameMap.ID,
 		gameMap.CampaignID,
 		gameMap.Name,
@@ -139,7 +139,7 @@ func (s *MapStore) GetByCampaign(ctx context.Context, campaignID string) ([]*mod
 		ORDER BY created_at DESC
 	`
 
-	rows, err := s.pool.Query(ctx, query, campaignID)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, campaignID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list maps: %w", err)
 	}
@@ -222,7 +222,7 @@ func (s *MapStore) Update(ctx context.Context, gameMap *models.Map) error {
 		WHERE id = $13
 	`
 
-	result, err := s.pool.Exec(ctx, query,
+	result, err := conn(ctx, s.pool).Exec(ctx, query,
 		gameMap.Name,
 		string(gameMap.Type),
 		string(gameMap.Mode),
@@ -253,7 +253,7 @@ func (s *MapStore) Update(ctx context.Context, gameMap *models.Map) error {
 func (s *MapStore) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM maps WHERE id = $1`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete map: %w", err)
 	}
@@ -297,7 +297,7 @@ func (s *MapStore) GetByParent(ctx context.Context, parentID string) ([]*models.
 		ORDER BY created_at DESC
 	`
 
-	rows, err := s.pool.Query(ctx, query, parentID)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, parentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list maps by parent: %w", err)
 	}
@@ -321,7 +321,7 @@ func (s *MapStore) GetByParent(ctx context.Context, parentID string) ([]*models.
 
 // scanMap scans a single map using the provided query
 func (s *MapStore) scanMap(ctx context.Context, query string, args ...interface{}) (*models.Map, error) {
-	row := s.pool.QueryRow(ctx, query, args...)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, args...)
 	return scanMapFromRow(row)
 }
 
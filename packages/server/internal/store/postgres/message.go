@@ -41,17 +41,18 @@ func (s *MessageStore) Create(ctx context.Context, message *models.Message) erro
 	}
 
 	query := `
-		INSERT INTO messages (id, campaign_id, role, content, player_id, tool_calls, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO messages (id, campaign_id, role, content, player_id, tool_calls, tool_call_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err = s.pool.Exec(ctx, query,
+	_, err = conn(ctx, s.pool).Exec(ctx, query,
 		message.ID,
 		message.CampaignID,
 		string(message.Role),
 		message.Content,
 		nullString(message.PlayerID),
 		nullJSON(toolCallsJSON),
+		nullString(message.ToolCallID),
 		message.CreatedAt,
 	)
 
@@ -65,7 +66,7 @@ func (s *MessageStore) Create(ctx context.Context, message *models.Message) erro
 // Get retrieves a message by ID
 func (s *MessageStore) Get(ctx context.Context, id string) (*models.Message, error) {
 	query := `
-		SELECT id, campaign_id, role, content, player_id, tool_calls, created_at
+		SELECT id, campaign_id, role, content, player_id, tool_calls, tool_call_id, created_at
 		FROM messages
 		WHERE id = $1
 	`
@@ -76,7 +77,7 @@ func (s *MessageStore) Get(ctx context.Context, id string) (*models.Message, err
 // GetByCampaignID retrieves a message by campaign ID and message ID
 func (s *MessageStore) GetByCampaignID(ctx context.Context, campaignID, id string) (*models.Message, error) {
 	query := `
-		SELECT id, campaign_id, role, content, player_id, tool_calls, created_at
+		SELECT id, campaign_id, role, content, player_id, tool_calls, tool_call_id, created_at
 		FROM messages
 		WHERE campaign_id = $1 AND id = $2
 	`
@@ -87,7 +88,7 @@ func (s *MessageStore) GetByCampaignID(ctx context.Context, campaignID, id strin
 // ListByCampaign retrieves messages for a campaign, ordered by created_at
 func (s *MessageStore) ListByCampaign(ctx context.Context, campaignID string, limit int) ([]*models.Message, error) {
 	query := `
-		SELECT id, campaign_id, role, content, player_id, tool_calls, created_at
+		SELECT id, campaign_id, role, content, player_id, tool_calls, tool_call_id, created_at
 		FROM messages
 		WHERE campaign_id = $1
 		ORDER BY created_at ASC
@@ -97,7 +98,7 @@ func (s *MessageStore) ListByCampaign(ctx context.Context, campaignID string, li
 		query += fmt.Sprintf(" LIMIT %d", limit)
 	}
 
-	rows, err := s.pool.Query(ctx, query, campaignID)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, campaignID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list messages: %w", err)
 	}
@@ -122,14 +123,14 @@ func (s *MessageStore) ListByCampaign(ctx context.Context, campaignID string, li
 // ListByCampaignWithOffset retrieves messages with pagination
 func (s *MessageStore) ListByCampaignWithOffset(ctx context.Context, campaignID string, limit, offset int) ([]*models.Message, error) {
 	query := `
-		SELECT id, campaign_id, role, content, player_id, tool_calls, created_at
+		SELECT id, campaign_id, role, content, player_id, tool_calls, tool_call_id, created_at
 		FROM messages
 		WHERE campaign_id = $1
 		ORDER BY created_at ASC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := s.pool.Query(ctx, query, campaignID, limit, offset)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, campaignID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list messages: %w", err)
 	}
@@ -156,7 +157,7 @@ func (s *MessageStore) CountByCampaign(ctx context.Context, campaignID string) (
 	query := `SELECT COUNT(*) FROM messages WHERE campaign_id = $1`
 
 	var count int
-	err := s.pool.QueryRow(ctx, query, campaignID).Scan(&count)
+	err := conn(ctx, s.pool).QueryRow(ctx, query, campaignID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count messages: %w", err)
 	}
@@ -168,7 +169,7 @@ func (s *MessageStore) CountByCampaign(ctx context.Context, campaignID string) (
 func (s *MessageStore) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM messages WHERE id = $1`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete message: %w", err)
 	}
@@ -184,7 +185,7 @@ func (s *MessageStore) Delete(ctx context.Context, id string) error {
 func (s *MessageStore) DeleteByCampaign(ctx context.Context, campaignID string) error {
 	query := `DELETE FROM messages WHERE campaign_id = $1`
 
-	_, err := s.pool.Exec(ctx, query, campaignID)
+	_, err := conn(ctx, s.pool).Exec(ctx, query, campaignID)
 	if err != nil {
 		return fmt.Errorf("failed to delete campaign messages: %w", err)
 	}
@@ -196,7 +197,7 @@ func (s *MessageStore) DeleteByCampaign(ctx context.Context, campaignID string)
 func (s *MessageStore) DeleteByCampaignBeforeDate(ctx context.Context, campaignID string, beforeDate time.Time) (int64, error) {
 	query := `DELETE FROM messages WHERE campaign_id = $1 AND created_at < $2`
 
-	result, err := s.pool.Exec(ctx, query, campaignID, beforeDate)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, campaignID, beforeDate)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete old messages: %w", err)
 	}
@@ -204,9 +205,21 @@ func (s *MessageStore) DeleteByCampaignBeforeDate(ctx context.Context, campaignI
 	return result.RowsAffected(), nil
 }
 
+// DeleteByCampaignAfterDate deletes messages after a specific date
+func (s *MessageStore) DeleteByCampaignAfterDate(ctx context.Context, campaignID string, afterDate time.Time) (int64, error) {
+	query := `DELETE FROM messages WHERE campaign_id = $1 AND created_at > $2`
+
+	result, err := conn(ctx, s.pool).Exec(ctx, query, campaignID, afterDate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete later messages: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 // scanMessage scans a single message using the provided query
 func (s *MessageStore) scanMessage(ctx context.Context, query string, args ...interface{}) (*models.Message, error) {
-	row := s.pool.QueryRow(ctx, query, args...)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, args...)
 	return scanMessageFromRow(row)
 }
 
@@ -219,6 +232,7 @@ func scanMessageFromRow(row pgx.Row) (*models.Message, error) {
 		content       string
 		playerID      sql.NullString
 		toolCallsJSON []byte
+		toolCallID    sql.NullString
 		createdAt     time.Time
 	)
 
@@ -229,6 +243,7 @@ func scanMessageFromRow(row pgx.Row) (*models.Message, error) {
 		&content,
 		&playerID,
 		&toolCallsJSON,
+		&toolCallID,
 		&createdAt,
 	)
 
@@ -254,6 +269,7 @@ func scanMessageFromRow(row pgx.Row) (*models.Message, error) {
 		Content:    content,
 		PlayerID:   playerID.String,
 		ToolCalls:  toolCalls,
+		ToolCallID: toolCallID.String,
 		CreatedAt:  createdAt,
 	}
 
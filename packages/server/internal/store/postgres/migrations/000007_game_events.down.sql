@@ -0,0 +1,4 @@
+-- 007_game_events.down.sql
+-- Rollback game events
+
+DROP TABLE IF EXISTS game_events;
@@ -0,0 +1,15 @@
+-- 007_game_events.up.sql
+-- Game events pushed to clients; the cursor is a monotonic sequence clients resume from
+
+CREATE TABLE IF NOT EXISTS game_events (
+    cursor BIGSERIAL PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_events_campaign_cursor ON game_events(campaign_id, cursor);
+
+COMMENT ON TABLE game_events IS 'Domain events (HP, conditions, turns, movement, dice, maps) streamed to clients';
+COMMENT ON COLUMN game_events.cursor IS 'Monotonic event cursor used for SSE Last-Event-ID and polling';
@@ -0,0 +1,4 @@
+-- 008_audit_log.down.sql
+-- Rollback audit log
+
+DROP TABLE IF EXISTS audit_log;
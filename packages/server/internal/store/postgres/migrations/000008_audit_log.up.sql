@@ -0,0 +1,20 @@
+-- 008_audit_log.up.sql
+-- Audit log of state-mutating tool calls; each entry keeps the before and after state of the records it changed so it can be undone
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    actor_role VARCHAR(20) NOT NULL DEFAULT '',
+    tool VARCHAR(100) NOT NULL,
+    arguments JSONB NOT NULL DEFAULT '{}',
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    undone_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_campaign_id ON audit_log(campaign_id, id DESC);
+
+COMMENT ON TABLE audit_log IS 'State-mutating tool calls with before/after record states, used by undo_last_action';
+COMMENT ON COLUMN audit_log.changes IS 'Array of {entity, entity_id, before, after}; null before means created, null after means deleted';
+COMMENT ON COLUMN audit_log.undone_at IS 'Set when the entry has been reverted';
@@ -0,0 +1,4 @@
+-- 009_campaign_snapshots.down.sql
+-- Rollback campaign snapshots
+
+DROP TABLE IF EXISTS campaign_snapshots;
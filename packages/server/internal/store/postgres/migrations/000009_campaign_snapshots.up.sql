@@ -0,0 +1,20 @@
+-- 009_campaign_snapshots.up.sql
+-- Campaign save points; each snapshot keeps a compressed copy of the campaign's records
+
+CREATE TABLE IF NOT EXISTS campaign_snapshots (
+    id UUID PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    message_count INTEGER NOT NULL DEFAULT 0,
+    message_cursor TIMESTAMP WITH TIME ZONE,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_snapshots_campaign_id ON campaign_snapshots(campaign_id, created_at DESC);
+
+COMMENT ON TABLE campaign_snapshots IS 'Point-in-time copies of a campaign used by restore_snapshot and fork_campaign';
+COMMENT ON COLUMN campaign_snapshots.message_cursor IS 'Creation time of the last message included in the snapshot';
+COMMENT ON COLUMN campaign_snapshots.data IS 'Zip archive (campaign archive format) of the game state, characters, maps and active combat';
@@ -0,0 +1,5 @@
+-- 010_message_tool_call_id.down.sql
+-- Rollback tool result message linkage
+
+DELETE FROM messages WHERE role = 'tool';
+ALTER TABLE messages DROP COLUMN IF EXISTS tool_call_id;
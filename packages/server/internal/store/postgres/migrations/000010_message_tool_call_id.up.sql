@@ -0,0 +1,6 @@
+-- 010_message_tool_call_id.up.sql
+-- Link tool result messages to the assistant tool call they answer
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS tool_call_id VARCHAR(255);
+
+COMMENT ON COLUMN messages.tool_call_id IS 'ID of the assistant tool call answered by a tool message';
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SnapshotStore implements campaign snapshot storage using PostgreSQL
+type SnapshotStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewSnapshotStore creates a new snapshot store
+func NewSnapshotStore(client *Client) *SnapshotStore {
+	return &SnapshotStore{pool: client.Pool()}
+}
+
+// Create stores a snapshot
+func (s *SnapshotStore) Create(ctx context.Context, snapshot *models.Snapshot) error {
+	if snapshot.CreatedAt.IsZero() {
+		snapshot.CreatedAt = time.Now()
+	}
+	snapshot.Size = len(snapshot.Data)
+
+	query := `
+		INSERT INTO campaign_snapshots (id, campaign_id, name, description, created_by, message_count, message_cursor, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := conn(ctx, s.pool).Exec(ctx, query,
+		snapshot.ID,
+		snapshot.CampaignID,
+		snapshot.Name,
+		snapshot.Description,
+		snapshot.CreatedBy,
+		snapshot.MessageCount,
+		snapshot.MessageCursor,
+		snapshot.Data,
+		snapshot.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a snapshot with its data
+func (s *SnapshotStore) Get(ctx context.Context, id string) (*models.Snapshot, error) {
+	query := `
+		SELECT id, campaign_id, name, description, created_by, message_count, message_cursor, created_at, data
+		FROM campaign_snapshots
+		WHERE id = $1
+	`
+
+	var snapshot models.Snapshot
+	err := conn(ctx, s.pool).QueryRow(ctx, query, id).Scan(
+		&snapshot.ID,
+		&snapshot.CampaignID,
+		&snapshot.Name,
+		&snapshot.Description,
+		&snapshot.CreatedBy,
+		&snapshot.MessageCount,
+		&snapshot.MessageCursor,
+		&snapshot.CreatedAt,
+		&snapshot.Data,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	snapshot.Size = len(snapshot.Data)
+
+	return &snapshot, nil
+}
+
+// ListByCampaign lists a campaign's snapshots without their data, newest first
+func (s *SnapshotStore) ListByCampaign(ctx context.Context, campaignID string) ([]*models.Snapshot, error) {
+	query := `
+		SELECT id, campaign_id, name, description, created_by, message_count, message_cursor, created_at, length(data)
+		FROM campaign_snapshots
+		WHERE campaign_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := conn(ctx, s.pool).Query(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]*models.Snapshot, 0)
+	for rows.Next() {
+		var snapshot models.Snapshot
+		if err := rows.Scan(
+			&snapshot.ID,
+			&snapshot.CampaignID,
+			&snapshot.Name,
+			&snapshot.Description,
+			&snapshot.CreatedBy,
+			&snapshot.MessageCount,
+			&snapshot.MessageCursor,
+			&snapshot.CreatedAt,
+			&snapshot.Size,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
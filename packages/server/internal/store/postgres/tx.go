@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier is implemented by both the pool and a transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// conn returns the transaction started by InTx for this context, or the pool
+func conn(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// InTx runs fn in a transaction. Stores called with the context passed to fn
+// take part in it; the transaction commits when fn returns nil and rolls back otherwise.
+// Functions passed to store.AfterCommit run after the commit. Nested calls join the outer transaction.
+func (c *Client) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return c.inTx(ctx, pgx.TxOptions{}, fn)
+}
+
+// InConsistentTx runs fn like InTx at repeatable read isolation, so every query
+// in fn sees the database as it was when the transaction started.
+// Nested calls join the outer transaction at its isolation level.
+func (c *Client) InConsistentTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return c.inTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead}, fn)
+}
+
+func (c *Client) inTx(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := c.pool.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// Rollback after a successful commit is a no-op
+		_ = tx.Rollback(ctx)
+	}()
+
+	txCtx, committed := store.WithCommitHooks(context.WithValue(ctx, txKey{}, tx))
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed()
+	return nil
+}
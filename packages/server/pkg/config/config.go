@@ -17,6 +17,7 @@ type Config struct {
 	HTTP     HTTPConfig     `json:"http"`
 	Log      LogConfig      `json:"log"`
 	RAG      RAGConfig      `json:"rag"`
+	Auth     AuthConfig     `json:"auth"`
 }
 
 // PostgresConfig PostgreSQL configuration
@@ -51,6 +52,7 @@ type HTTPConfig struct {
 	ReadTimeout     int    `json:"read_timeout" env:"HTTP_READ_TIMEOUT"`           // seconds
 	WriteTimeout    int    `json:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`         // seconds
 	ShutdownTimeout int    `json:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT"`   // seconds
+	SessionTTL      int    `json:"session_ttl" env:"HTTP_SESSION_TTL"`             // seconds an idle MCP session is kept; 0 keeps sessions until deleted
 	EnableCORS      bool   `json:"enable_cors" env:"HTTP_ENABLE_CORS"`
 }
 
@@ -61,6 +63,14 @@ type RAGConfig struct {
 	Timeout int    `json:"timeout" env:"RAG_TIMEOUT"` // seconds
 }
 
+// AuthConfig authentication configuration
+type AuthConfig struct {
+	Enabled bool `json:"enabled" env:"AUTH_ENABLED"`
+	// APIKeys is a comma separated list of "key:role:user_id" entries
+	APIKeys   string `json:"-" env:"AUTH_API_KEYS"`
+	JWTSecret string `json:"-" env:"AUTH_JWT_SECRET"` // HS256 signing secret; hide in JSON output
+}
+
 // Load loads configuration from environment variables and .env file
 // Priority: environment variables > .env file > default values
 func Load() (*Config, error) {
@@ -89,6 +99,7 @@ func Load() (*Config, error) {
 			ReadTimeout:     getEnvInt("HTTP_READ_TIMEOUT", 30),
 			WriteTimeout:    getEnvInt("HTTP_WRITE_TIMEOUT", 30),
 			ShutdownTimeout: getEnvInt("HTTP_SHUTDOWN_TIMEOUT", 10),
+			SessionTTL:      getEnvInt("HTTP_SESSION_TTL", 1800),
 			EnableCORS:      getEnvBool("HTTP_ENABLE_CORS", true),
 		},
 		RAG: RAGConfig{
@@ -96,6 +107,11 @@ func Load() (*Config, error) {
 			URL:     getEnv("RAG_URL", ""),
 			Timeout: getEnvInt("RAG_TIMEOUT", 30),
 		},
+		Auth: AuthConfig{
+			Enabled:   getEnvBool("AUTH_ENABLED", false),
+			APIKeys:   getEnv("AUTH_API_KEYS", ""),
+			JWTSecret: getEnv("AUTH_JWT_SECRET", ""),
+		},
 	}
 
 	// Validate configuration
@@ -156,6 +172,9 @@ func (c *Config) Validate() error {
 	if c.HTTP.ShutdownTimeout <= 0 {
 		return fmt.Errorf("HTTP shutdown timeout must be greater than 0")
 	}
+	if c.HTTP.SessionTTL < 0 {
+		return fmt.Errorf("HTTP session TTL must not be negative")
+	}
 
 	// Validate RAG configuration (if enabled)
 	if c.RAG.Enabled && c.RAG.URL == "" {
@@ -165,6 +184,11 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("RAG timeout must be greater than 0")
 	}
 
+	// Validate auth configuration (if enabled)
+	if c.Auth.Enabled && c.Auth.APIKeys == "" && c.Auth.JWTSecret == "" {
+		return fmt.Errorf("auth requires API keys or a JWT secret when enabled")
+	}
+
 	return nil
 }
 
@@ -16,7 +16,7 @@ func TestLoad_DefaultValues(t *testing.T) {
 		"POSTGRES_MAX_CONN_LIFETIME", "POSTGRES_MAX_CONN_IDLETIME",
 		"LOG_LEVEL", "LOG_FORMAT",
 		"HTTP_HOST", "HTTP_PORT", "HTTP_READ_TIMEOUT", "HTTP_WRITE_TIMEOUT",
-		"HTTP_SHUTDOWN_TIMEOUT", "HTTP_ENABLE_CORS",
+		"HTTP_SHUTDOWN_TIMEOUT", "HTTP_SESSION_TTL", "HTTP_ENABLE_CORS",
 		"RAG_ENABLED", "RAG_URL", "RAG_TIMEOUT",
 	}
 	for _, v := range envVars {
@@ -48,6 +48,7 @@ func TestLoad_DefaultValues(t *testing.T) {
 	assert.Equal(t, 30, cfg.HTTP.ReadTimeout)
 	assert.Equal(t, 30, cfg.HTTP.WriteTimeout)
 	assert.Equal(t, 10, cfg.HTTP.ShutdownTimeout)
+	assert.Equal(t, 1800, cfg.HTTP.SessionTTL)
 	assert.True(t, cfg.HTTP.EnableCORS)
 
 	// Verify RAG defaults
@@ -1,4 +1,25 @@
 // Package logger provides structured logging
 package logger
 
-// Placeholder - structured logging will be implemented here
+import (
+	"log/slog"
+	"os"
+)
+
+// std writes to stderr; stdout carries the stdio MCP transport
+var std = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+// Info logs a message at info level with key-value attributes
+func Info(msg string, args ...any) {
+	std.Info(msg, args...)
+}
+
+// Warn logs a message at warn level with key-value attributes
+func Warn(msg string, args ...any) {
+	std.Warn(msg, args...)
+}
+
+// Error logs a message at error level with key-value attributes
+func Error(msg string, args ...any) {
+	std.Error(msg, args...)
+}
@@ -0,0 +1,127 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "integration-secret"
+
+// setupAuthServer starts a server with API keys for a DM and an observer, and a JWT secret
+func setupAuthServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := newTestConfig()
+	cfg.Auth = config.AuthConfig{
+		Enabled:   true,
+		APIKeys:   "dm-key:dm:alice,observer-key:observer:carol",
+		JWTSecret: testJWTSecret,
+	}
+	authenticator, err := auth.NewAuthenticator(&cfg.Auth)
+	require.NoError(t, err)
+
+	server := mcp.NewServer(cfg)
+	server.SetAuthenticator(authenticator)
+	for _, name := range []string{"start_combat", "get_combat_state"} {
+		server.RegisterTool(mcp.NewTool(name, name, mcp.NewObjectSchema(nil, nil)), func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+			principal, _ := auth.FromContext(ctx)
+			return mcp.NewTextResponse(principal.UserID)
+		})
+	}
+	server.Registry().SetAccess("get_combat_state", mcp.AccessRead)
+
+	testServer := httptest.NewServer(server.Handler())
+	t.Cleanup(testServer.Close)
+	return testServer
+}
+
+func authRequest(t *testing.T, method, url, body string, headers map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestAuth_RejectsMissingAndInvalidCredentials(t *testing.T) {
+	testServer := setupAuthServer(t)
+
+	resp := authRequest(t, http.MethodGet, testServer.URL+"/health", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "health checks are not authenticated")
+
+	resp = authRequest(t, http.MethodGet, testServer.URL+"/mcp/tools", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+
+	resp = authRequest(t, http.MethodGet, testServer.URL+"/mcp/tools", "", map[string]string{"X-API-Key": "nope"})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	expired, err := auth.IssueToken([]byte(testJWTSecret), auth.Principal{UserID: "bob", Role: auth.RolePlayer}, -time.Minute)
+	require.NoError(t, err)
+	resp = authRequest(t, http.MethodGet, testServer.URL+"/mcp/tools", "", map[string]string{"Authorization": "Bearer " + expired})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestAuth_ToolsFilteredByRole(t *testing.T) {
+	testServer := setupAuthServer(t)
+
+	listTools := func(headers map[string]string) []string {
+		resp := authRequest(t, http.MethodGet, testServer.URL+"/mcp/tools", "", headers)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result mcp.ListToolsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		names := make([]string, 0, len(result.Tools))
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"start_combat", "get_combat_state"}, listTools(map[string]string{"X-API-Key": "dm-key"}))
+	assert.ElementsMatch(t, []string{"get_combat_state"}, listTools(map[string]string{"Authorization": "Bearer observer-key"}))
+
+	token, err := auth.IssueToken([]byte(testJWTSecret), auth.Principal{UserID: "bob", Role: auth.RolePlayer}, time.Hour)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"get_combat_state"}, listTools(map[string]string{"Authorization": "Bearer " + token}))
+}
+
+func TestAuth_CallToolCarriesPrincipal(t *testing.T) {
+	testServer := setupAuthServer(t)
+
+	callTool := func(name, apiKey string) mcp.ToolResponse {
+		body, _ := json.Marshal(mcp.CallToolRequest{Name: name})
+		resp := authRequest(t, http.MethodPost, testServer.URL+"/mcp/tools/call", string(body), map[string]string{"X-API-Key": apiKey})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result mcp.ToolResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result
+	}
+
+	result := callTool("start_combat", "dm-key")
+	assert.False(t, result.IsError)
+	assert.Equal(t, "alice", result.Content[0].Text)
+
+	result = callTool("start_combat", "observer-key")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "forbidden")
+
+	result = callTool("get_combat_state", "observer-key")
+	assert.False(t, result.IsError)
+	assert.Equal(t, "carol", result.Content[0].Text)
+}
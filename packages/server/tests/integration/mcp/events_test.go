@@ -0,0 +1,341 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/api/events"
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/dnd-mcp/server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEventStore is an in-memory EventStore assigning sequential cursors
+type memoryEventStore struct {
+	mu     sync.Mutex
+	events []*models.GameEvent
+}
+
+func (m *memoryEventStore) Append(ctx context.Context, event *models.GameEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	event.Cursor = int64(len(m.events) + 1)
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memoryEventStore) List(ctx context.Context, filter *store.EventFilter) ([]*models.GameEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*models.GameEvent, 0)
+	for _, event := range m.events {
+		if event.CampaignID != filter.CampaignID || event.Cursor <= filter.After {
+			continue
+		}
+		if len(filter.Types) > 0 && filter.Types[0] != event.Type {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (m *memoryEventStore) Latest(ctx context.Context, campaignID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var cursor int64
+	for _, event := range m.events {
+		if event.CampaignID == campaignID {
+			cursor = event.Cursor
+		}
+	}
+	return cursor, nil
+}
+
+// newEventServer creates a server serving the events of an event bus
+func newEventServer() (*mcp.Server, *service.EventBus) {
+	bus := service.NewEventBus(&memoryEventStore{})
+	server := newEchoServer()
+	server.SetEventSource(events.NewSource(bus))
+	return server, bus
+}
+
+// getEvents polls the events endpoint
+func getEvents(t *testing.T, url string) mcp.EventsResponse {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result mcp.EventsResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+// publish publishes a game event on the bus
+func publish(t *testing.T, bus *service.EventBus, campaignID string, eventType models.EventType) {
+	t.Helper()
+	require.NoError(t, bus.Publish(context.Background(), models.NewGameEvent(campaignID, eventType, map[string]interface{}{"n": 1})))
+}
+
+func TestEvents_Poll(t *testing.T) {
+	server, bus := newEventServer()
+	testServer := setupTestServer(newTestConfig(), server)
+	defer testServer.Close()
+
+	publish(t, bus, "c1", models.EventDiceRolled)
+	publish(t, bus, "c2", models.EventDiceRolled)
+
+	// Without a cursor the client learns where to start from
+	result := getEvents(t, testServer.URL+"/mcp/events?session_id=c1")
+	assert.Empty(t, result.Events)
+	assert.Equal(t, int64(1), result.Cursor)
+
+	publish(t, bus, "c1", models.EventHPChanged)
+	publish(t, bus, "c1", models.EventTokenMoved)
+
+	result = getEvents(t, testServer.URL+"/mcp/events?session_id=c1&after=1")
+	require.Len(t, result.Events, 2)
+	assert.Equal(t, "hp_changed", result.Events[0].Type)
+	assert.Equal(t, "c1", result.Events[0].SessionID)
+	assert.Equal(t, int64(4), result.Cursor)
+
+	result = getEvents(t, testServer.URL+"/mcp/events?session_id=c1&after=0&types=token_moved")
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, "token_moved", result.Events[0].Type)
+
+	// Caught up: the cursor stays put
+	result = getEvents(t, testServer.URL+"/mcp/events?session_id=c1&after=4")
+	assert.Empty(t, result.Events)
+	assert.Equal(t, int64(4), result.Cursor)
+}
+
+func TestEvents_PollErrors(t *testing.T) {
+	server, _ := newEventServer()
+	testServer := setupTestServer(newTestConfig(), server)
+	defer testServer.Close()
+
+	for _, query := range []string{"", "?session_id=c1&after=abc", "?session_id=c1&after=-1", "?session_id=c1&limit=0"} {
+		resp, err := http.Get(testServer.URL + "/mcp/events" + query)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	// Servers without an event source do not serve events
+	disabled := setupTestServer(newTestConfig(), newEchoServer())
+	defer disabled.Close()
+	resp, err := http.Get(disabled.URL + "/mcp/events?session_id=c1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// sseEvent is one parsed server-sent event
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// readSSEEvent reads the next event from an SSE stream, skipping comments
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && ev.data != "":
+			return ev
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// openEventStream opens the SSE event stream, optionally resuming after a cursor
+func openEventStream(t *testing.T, url, lastEventID string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set(mcp.LastEventIDHeader, lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return resp
+}
+
+func TestEvents_StreamLiveAndResume(t *testing.T) {
+	server, bus := newEventServer()
+	testServer := setupTestServer(newTestConfig(), server)
+	defer testServer.Close()
+
+	publish(t, bus, "c1", models.EventDiceRolled)
+
+	resp := openEventStream(t, testServer.URL+"/mcp/events/stream?session_id=c1", "")
+	reader := bufio.NewReader(resp.Body)
+
+	// The subscription is registered before the headers are flushed
+	publish(t, bus, "c2", models.EventDiceRolled)
+	publish(t, bus, "c1", models.EventTurnAdvanced)
+
+	ev := readSSEEvent(t, reader)
+	assert.Equal(t, "3", ev.id)
+	assert.Equal(t, "turn_advanced", ev.event)
+	var event mcp.Event
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &event))
+	assert.Equal(t, "c1", event.SessionID)
+	assert.Equal(t, int64(3), event.Cursor)
+	resp.Body.Close()
+
+	// Events published while disconnected are replayed on reconnect, then the stream continues live
+	publish(t, bus, "c1", models.EventHPChanged)
+	resumed := openEventStream(t, testServer.URL+"/mcp/events/stream?session_id=c1", "3")
+	defer resumed.Body.Close()
+	reader = bufio.NewReader(resumed.Body)
+
+	ev = readSSEEvent(t, reader)
+	assert.Equal(t, "4", ev.id)
+	assert.Equal(t, "hp_changed", ev.event)
+
+	done := make(chan sseEvent, 1)
+	go func() { done <- readSSEEvent(t, reader) }()
+	publish(t, bus, "c1", models.EventMapEntered)
+	select {
+	case ev = <-done:
+		assert.Equal(t, "5", ev.id)
+		assert.Equal(t, "map_entered", ev.event)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for live event")
+	}
+}
+
+// eventRecords is an in-memory character and map store for event access checks
+type eventRecords struct {
+	characters map[string]*models.Character
+	maps       map[string]*models.Map
+}
+
+func (r *eventRecords) Get(ctx context.Context, id string) (*models.Character, error) {
+	if character, ok := r.characters[id]; ok {
+		return character, nil
+	}
+	return nil, errors.New("character not found")
+}
+
+func (r *eventRecords) List(ctx context.Context, filter *store.CharacterFilter) ([]*models.Character, error) {
+	var result []*models.Character
+	for _, character := range r.characters {
+		if character.CampaignID == filter.CampaignID && character.PlayerID == filter.PlayerID && !character.IsNPC {
+			result = append(result, character)
+		}
+	}
+	return result, nil
+}
+
+// eventMaps adapts eventRecords to the map store
+type eventMaps struct{ *eventRecords }
+
+func (m eventMaps) Get(ctx context.Context, id string) (*models.Map, error) {
+	if gameMap, ok := m.maps[id]; ok {
+		return gameMap, nil
+	}
+	return nil, errors.New("map not found")
+}
+
+func TestEvents_AccessAndRedaction(t *testing.T) {
+	hero := models.NewCharacter("c1", "Hero", false)
+	hero.ID = "hero"
+	hero.PlayerID = "bob"
+	orc := models.NewCharacter("c1", "Orc", true)
+	orc.ID = "orc"
+	cave := models.NewBattleMap("c1", "Cave", 10, 10, 5)
+	cave.ID = "cave"
+	cave.Tokens = []models.Token{{ID: "token-hero", CharacterID: "hero", Visible: true}, {ID: "token-assassin", Hidden: true}}
+	records := &eventRecords{characters: map[string]*models.Character{"hero": hero, "orc": orc}, maps: map[string]*models.Map{"cave": cave}}
+
+	cfg := newTestConfig()
+	cfg.Auth = config.AuthConfig{Enabled: true, APIKeys: "dm-key:dm:alice,dm2-key:dm:dave,player-key:player:bob,observer-key:observer:carol"}
+	authenticator, err := auth.NewAuthenticator(&cfg.Auth)
+	require.NoError(t, err)
+	bus := service.NewEventBus(&memoryEventStore{})
+	source := events.NewSource(bus)
+	source.SetAccess(service.NewEventAccess(records, eventMaps{records}))
+	server := mcp.NewServer(cfg)
+	server.SetAuthenticator(authenticator)
+	server.SetCampaignDMs(func(ctx context.Context, campaignID string) (string, error) {
+		return map[string]string{"c1": "alice", "c2": "dave"}[campaignID], nil
+	})
+	server.SetEventSource(source)
+	testServer := setupTestServer(cfg, server)
+	defer testServer.Close()
+
+	ctx := context.Background()
+	require.NoError(t, bus.Publish(ctx, models.NewGameEvent("c1", models.EventHPChanged, map[string]interface{}{"character_id": "orc", "current": 3, "max": 15, "down": false})))
+	require.NoError(t, bus.Publish(ctx, models.NewGameEvent("c1", models.EventHPChanged, map[string]interface{}{"character_id": "hero", "current": 3, "max": 15, "down": false})))
+	require.NoError(t, bus.Publish(ctx, models.NewGameEvent("c1", models.EventTokenMoved, map[string]interface{}{"map_id": "cave", "token_id": "token-assassin"})))
+	require.NoError(t, bus.Publish(ctx, models.NewGameEvent("c1", models.EventTokenMoved, map[string]interface{}{"map_id": "cave", "token_id": "token-hero"})))
+
+	poll := func(key, campaignID string) (*http.Response, mcp.EventsResponse) {
+		req, _ := http.NewRequest(http.MethodGet, testServer.URL+"/mcp/events?after=0&session_id="+campaignID, nil)
+		req.Header.Set("X-API-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result mcp.EventsResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		}
+		return resp, result
+	}
+
+	_, result := poll("dm-key", "c1")
+	require.Len(t, result.Events, 4)
+	assert.Equal(t, float64(3), result.Events[0].Data["current"])
+
+	// Players see their campaign without NPC HP or hidden tokens
+	_, result = poll("player-key", "c1")
+	require.Len(t, result.Events, 3)
+	assert.Equal(t, "orc", result.Events[0].Data["character_id"])
+	assert.NotContains(t, result.Events[0].Data, "current")
+	assert.Equal(t, float64(3), result.Events[1].Data["current"])
+	assert.Equal(t, "token-hero", result.Events[2].Data["token_id"])
+	assert.Equal(t, int64(4), result.Cursor)
+
+	// Only members of the campaign may follow it
+	for _, denied := range []struct{ key, campaign string }{
+		{"player-key", "c2"}, {"observer-key", "c1"}, {"dm2-key", "c1"},
+	} {
+		resp, _ := poll(denied.key, denied.campaign)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, denied)
+	}
+	req, _ := http.NewRequest(http.MethodGet, testServer.URL+"/mcp/events/stream?session_id=c1", nil)
+	req.Header.Set("X-API-Key", "observer-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
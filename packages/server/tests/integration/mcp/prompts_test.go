@@ -0,0 +1,66 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPromptServer creates a server with a single greeting prompt
+func newPromptServer() *mcp.Server {
+	server := newEchoServer()
+	server.Prompts().MustRegister(mcp.Prompt{
+		Name:      "greet",
+		Arguments: []mcp.PromptArgument{{Name: "name", Required: true}},
+	}, func(ctx context.Context, args map[string]string) (*mcp.GetPromptResponse, error) {
+		if args["name"] == "error" {
+			return nil, errors.New("boom")
+		}
+		return mcp.NewUserPrompt("greeting", "Hello "+args["name"]), nil
+	})
+	return server
+}
+
+func TestPrompts_Capability(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newPromptServer())
+	defer testServer.Close()
+
+	resp := postRPC(t, testServer.URL, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	result := decodeRPC(t, resp)
+	capabilities := result["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	assert.NotNil(t, capabilities["prompts"])
+	assert.Nil(t, capabilities["resources"])
+}
+
+func TestPrompts_ListAndGet(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newPromptServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	result := decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`))
+	prompts := result["result"].(map[string]interface{})["prompts"].([]interface{})
+	require.Len(t, prompts, 1)
+	assert.Equal(t, "greet", prompts[0].(map[string]interface{})["name"])
+
+	result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"greet","arguments":{"name":"Mira"}}}`))
+	messages := result["result"].(map[string]interface{})["messages"].([]interface{})
+	message := messages[0].(map[string]interface{})
+	assert.Equal(t, "user", message["role"])
+	assert.Equal(t, "Hello Mira", message["content"].(map[string]interface{})["text"])
+
+	for _, body := range []string{
+		`{"jsonrpc":"2.0","id":4,"method":"prompts/get","params":{"name":"greet"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"prompts/get","params":{"name":"farewell"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"prompts/get","params":{}}`,
+	} {
+		result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, body))
+		assert.Equal(t, float64(mcp.ErrCodeInvalidParams), result["error"].(map[string]interface{})["code"], body)
+	}
+
+	result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":7,"method":"prompts/get","params":{"name":"greet","arguments":{"name":"error"}}}`))
+	assert.Equal(t, float64(mcp.ErrCodeInternal), result["error"].(map[string]interface{})["code"])
+}
@@ -0,0 +1,106 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newResourceServer creates a server with a character resource template
+func newResourceServer() *mcp.Server {
+	server := newEchoServer()
+	server.Resources().MustRegisterTemplate(mcp.ResourceTemplate{
+		URITemplate: "dnd://campaign/{id}/character/{cid}",
+		Name:        "character",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, uri string, params map[string]string) ([]mcp.ResourceContents, error) {
+		if params["cid"] != "hero" {
+			return nil, mcp.ErrResourceNotFound
+		}
+		return []mcp.ResourceContents{{URI: uri, MIMEType: "application/json", Text: `{"name":"Hero"}`}}, nil
+	}, func(ctx context.Context) ([]mcp.Resource, error) {
+		return []mcp.Resource{{URI: "dnd://campaign/c1/character/hero", Name: "Hero"}}, nil
+	})
+	return server
+}
+
+func TestResources_Capability(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newResourceServer())
+	defer testServer.Close()
+
+	resp := postRPC(t, testServer.URL, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	result := decodeRPC(t, resp)
+	capabilities := result["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	assert.Equal(t, true, capabilities["resources"].(map[string]interface{})["subscribe"])
+}
+
+func TestResources_ListAndRead(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newResourceServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	result := decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"resources/list"}`))
+	resources := result["result"].(map[string]interface{})["resources"].([]interface{})
+	require.Len(t, resources, 1)
+	assert.Equal(t, "dnd://campaign/c1/character/hero", resources[0].(map[string]interface{})["uri"])
+
+	result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":3,"method":"resources/templates/list"}`))
+	templates := result["result"].(map[string]interface{})["resourceTemplates"].([]interface{})
+	assert.Equal(t, "dnd://campaign/{id}/character/{cid}", templates[0].(map[string]interface{})["uriTemplate"])
+
+	result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"dnd://campaign/c1/character/hero"}}`))
+	contents := result["result"].(map[string]interface{})["contents"].([]interface{})
+	assert.Equal(t, `{"name":"Hero"}`, contents[0].(map[string]interface{})["text"])
+
+	result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":5,"method":"resources/read","params":{"uri":"dnd://campaign/c1/character/villain"}}`))
+	assert.Equal(t, float64(mcp.ErrCodeResourceNotFound), result["error"].(map[string]interface{})["code"])
+
+	result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":6,"method":"resources/read","params":{}}`))
+	assert.Equal(t, float64(mcp.ErrCodeInvalidParams), result["error"].(map[string]interface{})["code"])
+}
+
+func TestResources_SubscribeNotifiesUpdates(t *testing.T) {
+	server := newResourceServer()
+	testServer := setupTestServer(newTestConfig(), server)
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+	uri := "dnd://campaign/c1/character/hero"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, testServer.URL+mcp.StreamableEndpoint, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(mcp.SessionHeader, sessionID)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+
+	// Not subscribed yet
+	assert.Equal(t, 0, server.NotifyResourceUpdated(uri))
+
+	result := decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`))
+	require.Nil(t, result["error"])
+	assert.Equal(t, 0, server.NotifyResourceUpdated("dnd://campaign/c1/character/other"))
+	assert.Equal(t, 1, server.NotifyResourceUpdated(uri))
+
+	reader := bufio.NewReader(stream.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, "data: ") {
+			assert.Contains(t, line, `"method":"notifications/resources/updated"`)
+			assert.Contains(t, line, uri)
+			break
+		}
+	}
+
+	decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":3,"method":"resources/unsubscribe","params":{"uri":"`+uri+`"}}`))
+	assert.Equal(t, 0, server.NotifyResourceUpdated(uri))
+}
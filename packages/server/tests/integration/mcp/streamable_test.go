@@ -0,0 +1,285 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEchoServer creates a server with an echo tool registered
+func newEchoServer() *mcp.Server {
+	server := mcp.NewServer(newTestConfig())
+	server.RegisterTool(mcp.NewTool("echo", "Echo tool", mcp.NewObjectSchema(
+		map[string]mcp.Property{"message": mcp.StringProp("Message to echo")},
+		mcp.Required("message"),
+	)), func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		var args struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return mcp.NewErrorResponse(err)
+		}
+		return mcp.NewTextResponse(args.Message)
+	})
+	return server
+}
+
+// postRPC posts a JSON-RPC body to the Streamable HTTP endpoint
+func postRPC(t *testing.T, url, sessionID, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url+mcp.StreamableEndpoint, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(mcp.SessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+// decodeRPC decodes a single JSON-RPC response
+func decodeRPC(t *testing.T, resp *http.Response) map[string]interface{} {
+	defer resp.Body.Close()
+	var result map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+// initializeSession runs the initialize handshake and returns the session id
+func initializeSession(t *testing.T, url string) string {
+	resp := postRPC(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+	sessionID := resp.Header.Get(mcp.SessionHeader)
+	result := decodeRPC(t, resp)
+	require.NotEmpty(t, sessionID)
+	require.Nil(t, result["error"])
+
+	resp = postRPC(t, url, sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	return sessionID
+}
+
+func TestStreamable_Initialize(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+
+	resp := postRPC(t, testServer.URL, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(mcp.SessionHeader))
+
+	result := decodeRPC(t, resp)
+	assert.Equal(t, "2.0", result["jsonrpc"])
+	assert.Equal(t, float64(1), result["id"])
+	init := result["result"].(map[string]interface{})
+	assert.Equal(t, "2025-03-26", init["protocolVersion"])
+	assert.Equal(t, "dnd-mcp-server", init["serverInfo"].(map[string]interface{})["name"])
+	assert.NotNil(t, init["capabilities"].(map[string]interface{})["tools"])
+}
+
+func TestStreamable_InitializeUnsupportedVersion(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+
+	resp := postRPC(t, testServer.URL, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	result := decodeRPC(t, resp)
+	assert.Equal(t, mcp.LatestProtocolVersion, result["result"].(map[string]interface{})["protocolVersion"])
+}
+
+func TestStreamable_ListAndCallTools(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	result := decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+	tools := result["result"].(map[string]interface{})["tools"].([]interface{})
+	require.Len(t, tools, 1)
+	assert.Equal(t, "echo", tools[0].(map[string]interface{})["name"])
+
+	result = decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"echo","arguments":{"message":"Hello"}}}`))
+	assert.Equal(t, "call-1", result["id"])
+	call := result["result"].(map[string]interface{})
+	assert.Nil(t, call["isError"])
+	assert.Equal(t, "Hello", call["content"].([]interface{})[0].(map[string]interface{})["text"])
+}
+
+func TestStreamable_Errors(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	tests := []struct {
+		name string
+		body string
+		code float64
+	}{
+		{name: "parse error", body: `{"jsonrpc":`, code: mcp.ErrCodeParse},
+		{name: "invalid request", body: `{"jsonrpc":"1.0","id":3,"method":"ping"}`, code: mcp.ErrCodeInvalidRequest},
+		{name: "method not found", body: `{"jsonrpc":"2.0","id":3,"method":"resources/unknown"}`, code: mcp.ErrCodeMethodNotFound},
+		{name: "unknown tool", body: `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"missing"}}`, code: mcp.ErrCodeInvalidParams},
+		{name: "invalid params", body: `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":"oops"}`, code: mcp.ErrCodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := decodeRPC(t, postRPC(t, testServer.URL, sessionID, tt.body))
+			require.NotNil(t, result["error"])
+			assert.Equal(t, tt.code, result["error"].(map[string]interface{})["code"])
+		})
+	}
+}
+
+func TestStreamable_ToolErrorIsResult(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	result := decodeRPC(t, postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{"message":5}}}`))
+	assert.Nil(t, result["error"])
+	assert.Equal(t, true, result["result"].(map[string]interface{})["isError"])
+}
+
+func TestStreamable_Batch(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	resp := postRPC(t, testServer.URL, sessionID, `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/cancelled"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`)
+	defer resp.Body.Close()
+	var results []map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	require.Len(t, results, 2)
+	assert.Equal(t, float64(1), results[0]["id"])
+	assert.Equal(t, float64(2), results[1]["id"])
+}
+
+func TestStreamable_SessionRequired(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+
+	resp := postRPC(t, testServer.URL, "", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = postRPC(t, testServer.URL, "unknown-session", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStreamable_DeleteSession(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	req, _ := http.NewRequest(http.MethodDelete, testServer.URL+mcp.StreamableEndpoint, nil)
+	req.Header.Set(mcp.SessionHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = postRPC(t, testServer.URL, sessionID, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStreamable_IdleSessionExpires(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.HTTP.SessionTTL = 1
+	server := mcp.NewServer(cfg)
+	testServer := setupTestServer(cfg, server)
+	defer testServer.Close()
+	defer server.Shutdown(context.Background())
+	idleID := initializeSession(t, testServer.URL)
+	streamingID := initializeSession(t, testServer.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, testServer.URL+mcp.StreamableEndpoint, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(mcp.SessionHeader, streamingID)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	time.Sleep(2500 * time.Millisecond)
+
+	// A session without requests or an open stream expires
+	resp := postRPC(t, testServer.URL, idleID, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// An open stream keeps its session alive
+	resp = postRPC(t, testServer.URL, streamingID, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestStreamable_SessionOwner(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Auth = config.AuthConfig{Enabled: true, APIKeys: "dm-key:dm:alice,player-key:player:bob"}
+	authenticator, err := auth.NewAuthenticator(&cfg.Auth)
+	require.NoError(t, err)
+	server := newEchoServer()
+	server.SetAuthenticator(authenticator)
+	testServer := setupTestServer(cfg, server)
+	defer testServer.Close()
+
+	request := func(method, key, sessionID, body string) int {
+		req, _ := http.NewRequest(method, testServer.URL+mcp.StreamableEndpoint, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set(mcp.SessionHeader, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, testServer.URL+mcp.StreamableEndpoint, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "dm-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(mcp.SessionHeader)
+	require.NotEmpty(t, sessionID)
+
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "player-key", sessionID, ping))
+	assert.Equal(t, http.StatusForbidden, request(http.MethodGet, "player-key", sessionID, ""))
+	assert.Equal(t, http.StatusForbidden, request(http.MethodDelete, "player-key", sessionID, ""))
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "dm-key", sessionID, ping))
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "dm-key", sessionID, ""))
+}
+
+func TestStreamable_SSEResponse(t *testing.T) {
+	testServer := setupTestServer(newTestConfig(), newEchoServer())
+	defer testServer.Close()
+	sessionID := initializeSession(t, testServer.URL)
+
+	req, _ := http.NewRequest(http.MethodPost, testServer.URL+mcp.StreamableEndpoint, bytes.NewBufferString(`{"jsonrpc":"2.0","id":7,"method":"ping"}`))
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(mcp.SessionHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), `data: {"jsonrpc":"2.0","id":7,"result":{}}`)
+}
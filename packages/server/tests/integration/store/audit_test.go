@@ -0,0 +1,116 @@
+// Package store_test contains integration tests for the audit store and transactions
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/dnd-mcp/server/internal/store/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAuditTestDB sets up the test database and returns the client, stores and a cleanup function
+func setupAuditTestDB(t *testing.T) (*postgres.Client, *postgres.CampaignStore, *postgres.AuditStore, func()) {
+	t.Helper()
+
+	client, err := postgres.NewClient(getTestConfig())
+	require.NoError(t, err, "Failed to create client")
+	skipIfNoDatabase(t, client)
+
+	migrator := postgres.NewMigratorWithPath(client, getMigrationsPath())
+	require.NoError(t, migrator.Up(context.Background()), "Failed to run migrations")
+
+	cleanup := func() {
+		ctx := context.Background()
+		client.Pool().Exec(ctx, "DELETE FROM audit_log")
+		client.Pool().Exec(ctx, "DELETE FROM characters")
+		client.Pool().Exec(ctx, "DELETE FROM campaigns")
+		client.Close()
+	}
+
+	return client, postgres.NewCampaignStore(client), postgres.NewAuditStore(client), cleanup
+}
+
+func TestAuditStore_AppendListMarkUndone(t *testing.T) {
+	_, campaignStore, auditStore, cleanup := setupAuditTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	campaign := models.NewCampaign("Audit Test Campaign", "dm-audit-001", "")
+	require.NoError(t, campaignStore.Create(ctx, campaign))
+
+	first := &models.AuditEntry{
+		CampaignID: campaign.ID,
+		ActorID:    "alice",
+		ActorRole:  "dm",
+		Tool:       "attack",
+		Arguments:  map[string]interface{}{"attacker_id": "hero"},
+		Changes: []models.AuditChange{{
+			Entity:   "character",
+			EntityID: "orc",
+			Before:   json.RawMessage(`{"hp":{"current":10}}`),
+			After:    json.RawMessage(`{"hp":{"current":3}}`),
+		}},
+	}
+	second := &models.AuditEntry{CampaignID: campaign.ID, Tool: "end_turn", Arguments: map[string]interface{}{}, Changes: []models.AuditChange{}}
+	require.NoError(t, auditStore.Append(ctx, first))
+	require.NoError(t, auditStore.Append(ctx, second))
+	assert.Greater(t, second.ID, first.ID)
+
+	entries, err := auditStore.List(ctx, &store.AuditFilter{CampaignID: campaign.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, second.ID, entries[0].ID, "newest first")
+	assert.Equal(t, "alice", entries[1].ActorID)
+	assert.Equal(t, "hero", entries[1].Arguments["attacker_id"])
+	require.Len(t, entries[1].Changes, 1)
+	assert.JSONEq(t, `{"hp":{"current":3}}`, string(entries[1].Changes[0].After))
+
+	require.NoError(t, auditStore.MarkUndone(ctx, []int64{second.ID}, time.Now()))
+	assert.Error(t, auditStore.MarkUndone(ctx, []int64{second.ID}, time.Now()), "entries cannot be undone twice")
+
+	entries, err = auditStore.List(ctx, &store.AuditFilter{CampaignID: campaign.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, first.ID, entries[0].ID)
+
+	entries, err = auditStore.List(ctx, &store.AuditFilter{CampaignID: campaign.ID, IncludeUndone: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.True(t, entries[0].IsUndone())
+}
+
+func TestClient_InTxRollsBack(t *testing.T) {
+	client, campaignStore, _, cleanup := setupAuditTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	campaign := models.NewCampaign("Tx Test Campaign", "dm-tx-001", "")
+	require.NoError(t, campaignStore.Create(ctx, campaign))
+	characterStore := postgres.NewCharacterStore(client)
+
+	rolledBack := models.NewCharacter(campaign.ID, "Rolled Back", false)
+	errAbort := errors.New("abort")
+	err := client.InTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, characterStore.Create(ctx, rolledBack))
+		_, err := characterStore.Get(ctx, rolledBack.ID)
+		require.NoError(t, err, "writes are visible inside the transaction")
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	_, err = characterStore.Get(ctx, rolledBack.ID)
+	assert.Error(t, err, "writes are rolled back")
+
+	committed := models.NewCharacter(campaign.ID, "Committed", false)
+	require.NoError(t, client.InTx(ctx, func(ctx context.Context) error {
+		return characterStore.Create(ctx, committed)
+	}))
+	_, err = characterStore.Get(ctx, committed.ID)
+	assert.NoError(t, err)
+}
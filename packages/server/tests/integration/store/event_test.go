@@ -0,0 +1,82 @@
+// Package store_test contains integration tests for the event store
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/dnd-mcp/server/internal/store/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEventTestDB sets up the test database and returns the stores and a cleanup function
+func setupEventTestDB(t *testing.T) (*postgres.CampaignStore, *postgres.EventStore, func()) {
+	t.Helper()
+
+	client, err := postgres.NewClient(getTestConfig())
+	require.NoError(t, err, "Failed to create client")
+	skipIfNoDatabase(t, client)
+
+	migrator := postgres.NewMigratorWithPath(client, getMigrationsPath())
+	require.NoError(t, migrator.Up(context.Background()), "Failed to run migrations")
+
+	cleanup := func() {
+		ctx := context.Background()
+		client.Pool().Exec(ctx, "DELETE FROM game_events")
+		client.Pool().Exec(ctx, "DELETE FROM campaigns")
+		client.Close()
+	}
+
+	return postgres.NewCampaignStore(client), postgres.NewEventStore(client), cleanup
+}
+
+func TestEventStore_AppendAndList(t *testing.T) {
+	campaignStore, eventStore, cleanup := setupEventTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	campaign := models.NewCampaign("Event Test Campaign", "dm-event-001", "")
+	require.NoError(t, campaignStore.Create(ctx, campaign))
+
+	first := models.NewGameEvent(campaign.ID, models.EventHPChanged, map[string]interface{}{"character_id": "char-1", "current": 7})
+	second := models.NewGameEvent(campaign.ID, models.EventDiceRolled, map[string]interface{}{"total": 18})
+	require.NoError(t, eventStore.Append(ctx, first))
+	require.NoError(t, eventStore.Append(ctx, second))
+	assert.Greater(t, second.Cursor, first.Cursor, "cursors increase monotonically")
+
+	events, err := eventStore.List(ctx, &store.EventFilter{CampaignID: campaign.ID})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.EventHPChanged, events[0].Type)
+	assert.Equal(t, "char-1", events[0].Data["character_id"])
+	assert.Equal(t, float64(7), events[0].Data["current"])
+
+	events, err = eventStore.List(ctx, &store.EventFilter{CampaignID: campaign.ID, After: first.Cursor})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, second.Cursor, events[0].Cursor)
+
+	events, err = eventStore.List(ctx, &store.EventFilter{CampaignID: campaign.ID, Types: []models.EventType{models.EventHPChanged}})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	latest, err := eventStore.Latest(ctx, campaign.ID)
+	require.NoError(t, err)
+	assert.Equal(t, second.Cursor, latest)
+}
+
+func TestEventStore_Latest_NoEvents(t *testing.T) {
+	campaignStore, eventStore, cleanup := setupEventTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	campaign := models.NewCampaign("Quiet Campaign", "dm-event-002", "")
+	require.NoError(t, campaignStore.Create(ctx, campaign))
+
+	latest, err := eventStore.Latest(ctx, campaign.ID)
+	require.NoError(t, err)
+	assert.Zero(t, latest)
+}
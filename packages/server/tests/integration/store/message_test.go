@@ -178,6 +178,28 @@ func TestMessageStore_Create_SystemMessage(t *testing.T) {
 	assert.Empty(t, retrieved.PlayerID)
 }
 
+func TestMessageStore_Create_ToolMessage(t *testing.T) {
+	_, campaignStore, messageStore, cleanup := setupMessageTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	campaign := models.NewCampaign("Tool Message Test", "dm-message-004", "")
+	err := campaignStore.Create(ctx, campaign)
+	require.NoError(t, err)
+
+	message := models.NewToolMessage(campaign.ID, "call_001", `{"total":18}`)
+
+	err = messageStore.Create(ctx, message)
+	require.NoError(t, err, "Failed to create tool message")
+
+	retrieved, err := messageStore.Get(ctx, message.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.MessageRoleTool, retrieved.Role)
+	assert.Equal(t, "call_001", retrieved.ToolCallID)
+}
+
 func TestMessageStore_Get(t *testing.T) {
 	_, campaignStore, messageStore, cleanup := setupMessageTestDB(t)
 	defer cleanup()
@@ -0,0 +1,72 @@
+// Package store_test contains integration tests for the snapshot store
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSnapshotTestDB sets up the test database and returns the client, stores and a cleanup function
+func setupSnapshotTestDB(t *testing.T) (*postgres.Client, *postgres.CampaignStore, *postgres.SnapshotStore, func()) {
+	t.Helper()
+
+	client, err := postgres.NewClient(getTestConfig())
+	require.NoError(t, err, "Failed to create client")
+	skipIfNoDatabase(t, client)
+
+	migrator := postgres.NewMigratorWithPath(client, getMigrationsPath())
+	require.NoError(t, migrator.Up(context.Background()), "Failed to run migrations")
+
+	cleanup := func() {
+		ctx := context.Background()
+		client.Pool().Exec(ctx, "DELETE FROM campaign_snapshots")
+		client.Pool().Exec(ctx, "DELETE FROM campaigns")
+		client.Close()
+	}
+
+	return client, postgres.NewCampaignStore(client), postgres.NewSnapshotStore(client), cleanup
+}
+
+func TestSnapshotStore_CreateGetList(t *testing.T) {
+	_, campaignStore, snapshotStore, cleanup := setupSnapshotTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	campaign := models.NewCampaign("Snapshot Test Campaign", "dm-snapshot-001", "")
+	require.NoError(t, campaignStore.Create(ctx, campaign))
+
+	cursor := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+	first := models.NewSnapshot(campaign.ID, "Before the ambush")
+	first.MessageCount = 3
+	first.MessageCursor = &cursor
+	first.Data = []byte("first archive")
+	first.CreatedAt = time.Now().Add(-time.Second)
+	second := models.NewSnapshot(campaign.ID, "After the ambush")
+	second.Data = []byte("second")
+	require.NoError(t, snapshotStore.Create(ctx, first))
+	require.NoError(t, snapshotStore.Create(ctx, second))
+
+	got, err := snapshotStore.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Before the ambush", got.Name)
+	assert.Equal(t, []byte("first archive"), got.Data)
+	assert.Equal(t, 3, got.MessageCount)
+	require.NotNil(t, got.MessageCursor)
+	assert.True(t, cursor.Equal(*got.MessageCursor))
+
+	snapshots, err := snapshotStore.ListByCampaign(ctx, campaign.ID)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, second.ID, snapshots[0].ID, "newest first")
+	assert.Nil(t, snapshots[1].Data, "listing leaves out the data")
+	assert.Equal(t, len("first archive"), snapshots[1].Size)
+
+	_, err = snapshotStore.Get(ctx, "00000000-0000-0000-0000-000000000000")
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+}
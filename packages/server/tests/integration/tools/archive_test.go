@@ -0,0 +1,149 @@
+// Package tools contains integration tests for campaign archive tools
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/api/tools"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type archiveFixture struct {
+	registry   *mcp.Registry
+	campaigns  *MockCampaignStore
+	gameStates *MockGameStateStore
+	characters *MockCharacterStore
+	maps       *MockMapStore
+	combats    *MockCombatStore
+	messages   *MockMessageStore
+}
+
+func newArchiveFixture() *archiveFixture {
+	return &archiveFixture{
+		registry:   mcp.NewRegistry(),
+		campaigns:  NewMockCampaignStore(),
+		gameStates: NewMockGameStateStore(),
+		characters: NewMockCharacterStore(),
+		maps:       NewMockMapStore(),
+		combats:    NewMockCombatStore(),
+		messages:   NewMockMessageStore(),
+	}
+}
+
+func setupArchiveTools() *archiveFixture {
+	f := newArchiveFixture()
+	archiveService := service.NewArchiveService(f.campaigns, f.gameStates, f.characters, f.maps, f.combats, f.messages, nil)
+	tools.NewArchiveTools(archiveService).Register(f.registry)
+	return f
+}
+
+// seedCampaign stores a campaign with a character on a battle map in combat
+func (f *archiveFixture) seedCampaign(ctx context.Context) *models.Campaign {
+	campaign := models.NewCampaign("Lost Mine", "dm-001", "Phandelver")
+	campaign.ID = "aaaaaaaa-0000-0000-0000-000000000001"
+	f.campaigns.Create(ctx, campaign)
+
+	hero := models.NewCharacter(campaign.ID, "Hero", false)
+	hero.ID = "aaaaaaaa-0000-0000-0000-000000000002"
+	hero.PlayerID = "player-001"
+	f.characters.Create(ctx, hero)
+
+	cave := models.NewBattleMap(campaign.ID, "Cragmaw Cave", 10, 10, 5)
+	cave.ID = "aaaaaaaa-0000-0000-0000-000000000003"
+	cave.Tokens = append(cave.Tokens, *models.NewToken(hero.ID, 1, 1, models.TokenSizeMedium))
+	f.maps.Create(ctx, cave)
+
+	combat := models.NewCombat(campaign.ID, []string{hero.ID})
+	combat.ID = "aaaaaaaa-0000-0000-0000-000000000004"
+	combat.MapID = cave.ID
+	f.combats.Create(ctx, combat)
+
+	gameState := models.NewGameState(campaign.ID)
+	gameState.SetCurrentMap(cave.ID, models.MapTypeBattle)
+	gameState.SetCombat(combat.ID)
+	f.gameStates.Create(ctx, gameState)
+
+	f.messages.Create(ctx, models.NewUserMessage(campaign.ID, "player-001", "We enter the cave"))
+	return campaign
+}
+
+func callJSON(t *testing.T, registry *mcp.Registry, tool string, args map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	data, _ := json.Marshal(args)
+	resp := registry.Call(context.Background(), mcp.ToolRequest{ToolName: tool, Arguments: data})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(resp.Content[0].Text), &result))
+	return result
+}
+
+func TestArchiveTools_Register(t *testing.T) {
+	f := setupArchiveTools()
+
+	assert.Equal(t, 2, f.registry.Count())
+	for _, name := range tools.ArchiveToolNames {
+		assert.True(t, f.registry.Has(name), "Tool %s should be registered", name)
+	}
+}
+
+func TestArchiveTools_ExportImportTwice(t *testing.T) {
+	f := setupArchiveTools()
+	ctx := context.Background()
+	original := f.seedCampaign(ctx)
+
+	exported := callJSON(t, f.registry, "export_campaign", map[string]interface{}{"campaign_id": original.ID})
+	assert.Contains(t, exported["message"], "1 characters, 1 maps, 1 combats")
+	data := exported["archive"].(string)
+
+	importedIDs := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		imported := callJSON(t, f.registry, "import_campaign", map[string]interface{}{"data": data, "name": "Lost Mine (copy)"})
+		campaignID := imported["campaign"].(map[string]interface{})["id"].(string)
+		assert.NotEqual(t, original.ID, campaignID)
+		assert.False(t, importedIDs[campaignID], "each import creates a new campaign")
+		importedIDs[campaignID] = true
+
+		campaign, err := f.campaigns.Get(ctx, campaignID)
+		require.NoError(t, err)
+		assert.Equal(t, "Lost Mine (copy)", campaign.Name)
+		assert.Equal(t, "dm-001", campaign.DMID)
+
+		maps, _ := f.maps.GetByCampaign(ctx, campaignID)
+		require.Len(t, maps, 1)
+		combats, _ := f.combats.GetByCampaign(ctx, campaignID)
+		require.Len(t, combats, 1)
+		gameState, err := f.gameStates.Get(ctx, campaignID)
+		require.NoError(t, err)
+		assert.Equal(t, maps[0].ID, gameState.CurrentMapID)
+		assert.Equal(t, combats[0].ID, gameState.ActiveCombatID)
+
+		characterID := maps[0].Tokens[0].CharacterID
+		character, err := f.characters.GetByCampaignAndID(ctx, campaignID, characterID)
+		require.NoError(t, err, "tokens point at the imported character")
+		assert.Equal(t, "Hero", character.Name)
+		assert.Equal(t, characterID, combats[0].Participants[0].CharacterID)
+
+		messages, _ := f.messages.ListByCampaign(ctx, campaignID, 0)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "We enter the cave", messages[0].Content)
+	}
+
+	assert.Len(t, f.campaigns.campaigns, 3)
+	assert.Len(t, f.characters.characters, 3)
+}
+
+func TestArchiveTools_ImportInvalidArchive(t *testing.T) {
+	f := setupArchiveTools()
+
+	args, _ := json.Marshal(map[string]interface{}{"data": "aGVsbG8="})
+	resp := f.registry.Call(context.Background(), mcp.ToolRequest{ToolName: "import_campaign", Arguments: args})
+	assert.True(t, resp.IsError)
+	assert.Empty(t, f.campaigns.campaigns)
+}
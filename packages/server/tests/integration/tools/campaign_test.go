@@ -32,7 +32,7 @@ func TestCampaignTools_Register(t *testing.T) {
 	campaignTools.Register(registry)
 
 	// Verify all tools are registered
-	assert.Equal(t, 5, registry.Count())
+	assert.Equal(t, 10, registry.Count())
 
 	for _, name := range tools.CampaignToolNames {
 		assert.True(t, registry.Has(name), "Tool %s should be registered", name)
@@ -89,7 +89,7 @@ func TestCampaignTools_CreateCampaign_MissingRequired(t *testing.T) {
 
 	resp := registry.Call(ctx, req)
 	assert.True(t, resp.IsError)
-	assert.Contains(t, resp.Content[0].Text, "campaign name is required")
+	assert.Contains(t, resp.Content[0].Text, "name is required")
 }
 
 func TestCampaignTools_GetCampaign(t *testing.T) {
@@ -206,6 +206,44 @@ func TestCampaignTools_DeleteCampaign(t *testing.T) {
 	assert.True(t, result["success"].(bool))
 }
 
+func TestCampaignTools_UpdateCampaign(t *testing.T) {
+	campaignTools, registry, cStore := setupCampaignTools()
+	campaignTools.Register(registry)
+
+	ctx := context.Background()
+
+	campaign := models.NewCampaign("Update Test", "dm-001", "Original description")
+	campaign.ID = "update-id-001"
+	cStore.Create(ctx, campaign)
+
+	args, _ := json.Marshal(map[string]interface{}{
+		"campaign_id": "update-id-001",
+		"name":        "Renamed Campaign",
+		"status":      "paused",
+		"settings": map[string]interface{}{
+			"max_players": 6,
+		},
+	})
+
+	resp := registry.Call(ctx, mcp.ToolRequest{ToolName: "update_campaign", Arguments: args})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	updated, err := cStore.Get(ctx, "update-id-001")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed Campaign", updated.Name)
+	assert.Equal(t, "Original description", updated.Description, "omitted fields are unchanged")
+	assert.Equal(t, models.CampaignStatusPaused, updated.Status)
+	assert.Equal(t, 6, updated.Settings.MaxPlayers)
+
+	// Unknown statuses are rejected by the schema
+	args, _ = json.Marshal(map[string]interface{}{
+		"campaign_id": "update-id-001",
+		"status":      "exploded",
+	})
+	resp = registry.Call(ctx, mcp.ToolRequest{ToolName: "update_campaign", Arguments: args})
+	assert.True(t, resp.IsError)
+}
+
 func TestCampaignTools_GetCampaignSummary(t *testing.T) {
 	campaignTools, registry, cStore := setupCampaignTools()
 	campaignTools.Register(registry)
@@ -277,7 +315,7 @@ func TestCampaignTools_ListTools(t *testing.T) {
 	campaignTools.Register(registry)
 
 	toolList := registry.List()
-	assert.Len(t, toolList, 5)
+	assert.Len(t, toolList, 10)
 
 	// Verify tool definitions
 	toolNames := make(map[string]bool)
@@ -290,6 +328,7 @@ func TestCampaignTools_ListTools(t *testing.T) {
 	assert.True(t, toolNames["create_campaign"])
 	assert.True(t, toolNames["get_campaign"])
 	assert.True(t, toolNames["list_campaigns"])
+	assert.True(t, toolNames["update_campaign"])
 	assert.True(t, toolNames["delete_campaign"])
 	assert.True(t, toolNames["get_campaign_summary"])
 }
@@ -31,7 +31,7 @@ func TestCharacterTools_Register(t *testing.T) {
 	characterTools.Register(registry)
 
 	// Verify all tools are registered
-	assert.Equal(t, 5, registry.Count())
+	assert.Equal(t, 8, registry.Count())
 
 	for _, name := range tools.CharacterToolNames {
 		assert.True(t, registry.Has(name), "Tool %s should be registered", name)
@@ -132,7 +132,7 @@ func TestCharacterTools_CreateCharacter_MissingRequired(t *testing.T) {
 
 	resp := registry.Call(ctx, req)
 	assert.True(t, resp.IsError)
-	assert.Contains(t, resp.Content[0].Text, "character name is required")
+	assert.Contains(t, resp.Content[0].Text, "name is required")
 }
 
 func TestCharacterTools_CreateCharacter_PlayerRequiresPlayerID(t *testing.T) {
@@ -484,6 +484,68 @@ func TestCharacterTools_DeleteCharacter_NotFound(t *testing.T) {
 	assert.True(t, resp.IsError)
 }
 
+func TestCharacterTools_DeathSave(t *testing.T) {
+	characterTools, registry, cStore := setupCharacterTools()
+	characterTools.Register(registry)
+
+	ctx := context.Background()
+
+	character := models.NewCharacter("campaign-001", "Dying Hero", false)
+	character.ID = "dying-id-001"
+	character.HP = &models.HP{Current: 0, Max: 12}
+	cStore.Create(ctx, character)
+
+	args, _ := json.Marshal(map[string]interface{}{
+		"character_id": "dying-id-001",
+		"roll":         1,
+	})
+
+	resp := registry.Call(ctx, mcp.ToolRequest{ToolName: "death_save", Arguments: args})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	var result map[string]interface{}
+	err := json.Unmarshal([]byte(resp.Content[0].Text), &result)
+	require.NoError(t, err)
+
+	deathSave := result["death_save"].(map[string]interface{})
+	assert.Equal(t, "critical_failure", deathSave["result"])
+	assert.Equal(t, float64(2), deathSave["failures"])
+	assert.Contains(t, result["message"], "Dying Hero rolled 1")
+	assert.Equal(t, 2, cStore.characters["dying-id-001"].GetDeathSaves().Failures)
+
+	// Rolls outside 0-20 are rejected by the schema
+	args, _ = json.Marshal(map[string]interface{}{
+		"character_id": "dying-id-001",
+		"roll":         21,
+	})
+	resp = registry.Call(ctx, mcp.ToolRequest{ToolName: "death_save", Arguments: args})
+	assert.True(t, resp.IsError)
+}
+
+func TestCharacterTools_Stabilize(t *testing.T) {
+	characterTools, registry, cStore := setupCharacterTools()
+	characterTools.Register(registry)
+
+	ctx := context.Background()
+
+	character := models.NewCharacter("campaign-001", "Dying Hero", false)
+	character.ID = "dying-id-002"
+	character.HP = &models.HP{Current: 0, Max: 12}
+	cStore.Create(ctx, character)
+
+	args, _ := json.Marshal(map[string]interface{}{
+		"character_id": "dying-id-002",
+	})
+
+	resp := registry.Call(ctx, mcp.ToolRequest{ToolName: "stabilize", Arguments: args})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.True(t, cStore.characters["dying-id-002"].IsStable())
+
+	// A stable character is no longer dying
+	resp = registry.Call(ctx, mcp.ToolRequest{ToolName: "stabilize", Arguments: args})
+	assert.True(t, resp.IsError)
+}
+
 func TestCharacterTools_InvalidJSON(t *testing.T) {
 	characterTools, registry, _ := setupCharacterTools()
 	characterTools.Register(registry)
@@ -522,7 +584,7 @@ func TestCharacterTools_ListTools(t *testing.T) {
 	characterTools.Register(registry)
 
 	toolList := registry.List()
-	assert.Len(t, toolList, 5)
+	assert.Len(t, toolList, 8)
 
 	// Verify tool definitions
 	toolNames := make(map[string]bool)
@@ -537,4 +599,7 @@ func TestCharacterTools_ListTools(t *testing.T) {
 	assert.True(t, toolNames["update_character"])
 	assert.True(t, toolNames["list_characters"])
 	assert.True(t, toolNames["delete_character"])
+	assert.True(t, toolNames["death_save"])
+	assert.True(t, toolNames["stabilize"])
+	assert.True(t, toolNames["get_encumbrance"])
 }
@@ -147,7 +147,7 @@ func TestCombatTools_Register(t *testing.T) {
 	combatTools.Register(registry)
 
 	// Verify all tools are registered
-	assert.Equal(t, 6, registry.Count())
+	assert.Equal(t, len(tools.CombatToolNames), registry.Count())
 
 	for _, name := range tools.CombatToolNames {
 		assert.True(t, registry.Has(name), "Tool %s should be registered", name)
@@ -723,7 +723,7 @@ func TestCombatTools_ListTools(t *testing.T) {
 	combatTools.Register(registry)
 
 	toolList := registry.List()
-	assert.Len(t, toolList, 6)
+	assert.Len(t, toolList, 13)
 
 	// Verify tool definitions
 	toolNames := make(map[string]bool)
@@ -738,7 +738,14 @@ func TestCombatTools_ListTools(t *testing.T) {
 	assert.True(t, toolNames["attack"])
 	assert.True(t, toolNames["cast_spell"])
 	assert.True(t, toolNames["end_turn"])
+	assert.True(t, toolNames["dash"])
+	assert.True(t, toolNames["dodge"])
+	assert.True(t, toolNames["disengage"])
+	assert.True(t, toolNames["help"])
+	assert.True(t, toolNames["hide"])
+	assert.True(t, toolNames["ready"])
 	assert.True(t, toolNames["end_combat"])
+	assert.True(t, toolNames["opportunity_attack"])
 }
 
 func TestCombatTools_StartCombat_MissingRequired(t *testing.T) {
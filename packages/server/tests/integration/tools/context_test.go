@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/dnd-mcp/server/internal/api/tools"
+	"github.com/dnd-mcp/server/internal/auth"
 	"github.com/dnd-mcp/server/internal/mcp"
 	"github.com/dnd-mcp/server/internal/models"
 	"github.com/dnd-mcp/server/internal/service"
@@ -305,7 +306,7 @@ func TestContextTools_GetContext_MissingCampaignID(t *testing.T) {
 
 	resp := registry.Call(ctx, req)
 	assert.True(t, resp.IsError)
-	assert.Contains(t, resp.Content[0].Text, "campaign ID is required")
+	assert.Contains(t, resp.Content[0].Text, "campaign_id is required")
 }
 
 func TestContextTools_GetRawContext_Success(t *testing.T) {
@@ -343,6 +344,50 @@ func TestContextTools_GetRawContext_Success(t *testing.T) {
 	assert.Equal(t, float64(5), result["message_count"])
 }
 
+func TestContextTools_GetRawContext_RedactedForPlayers(t *testing.T) {
+	contextTools, registry, charStore, _, gsStore, combatStore, mapStore := setupContextTools()
+	contextTools.Register(registry)
+	tools.RegisterAccess(registry)
+
+	campaignID := "campaign-001"
+	createTestCampaignData(charStore, gsStore, combatStore, mapStore, campaignID)
+	gameMap, _ := mapStore.Get(context.Background(), "map-001")
+	gameMap.Tokens = []models.Token{
+		{ID: "token-hero", CharacterID: "char-001", Visible: true},
+		{ID: "token-orc", CharacterID: "orc-001", Visible: true, Bar1: &models.TokenBar{Value: 15, Max: 15}},
+		{ID: "token-assassin", CharacterID: "assassin-001", Hidden: true},
+	}
+	secretDoor := models.NewDoor("door-secret", 3, 3, models.WallDirectionLeft)
+	secretDoor.Door.Secret = true
+	gameMap.Walls = models.Walls{models.NewWall("wall-001", models.WallTypeWall, 0, 0, 0, 5, 1, 1), secretDoor}
+
+	args, _ := json.Marshal(map[string]interface{}{"campaign_id": campaignID})
+	call := func(role auth.Role, userID string) string {
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID, Role: role})
+		ctx = auth.WithCampaignDMs(ctx, func(ctx context.Context, campaignID string) (string, error) {
+			return "dm-001", nil
+		})
+		resp := registry.Call(ctx, mcp.ToolRequest{ToolName: "get_raw_context", Arguments: args})
+		require.False(t, resp.IsError, resp.Content[0].Text)
+		return resp.Content[0].Text
+	}
+
+	player := call(auth.RolePlayer, "player-001")
+	assert.Contains(t, player, "token-hero")
+	assert.Contains(t, player, "wall-001")
+	assert.NotContains(t, player, "token-assassin")
+	assert.NotContains(t, player, "door-secret")
+
+	var result models.GetRawContextResponse
+	require.NoError(t, json.Unmarshal([]byte(player), &result))
+	require.Len(t, result.Map.Tokens, 2)
+	assert.Nil(t, result.Map.Tokens[1].Bar1, "hidden HP bars must not be returned")
+
+	dm := call(auth.RoleDM, "dm-001")
+	assert.Contains(t, dm, "token-assassin")
+	assert.Contains(t, dm, "door-secret")
+}
+
 func TestContextTools_GetRawContext_EmptyCampaign(t *testing.T) {
 	contextTools, registry, _, _, gsStore, _, _ := setupContextTools()
 	contextTools.Register(registry)
@@ -390,7 +435,7 @@ func TestContextTools_GetRawContext_MissingCampaignID(t *testing.T) {
 
 	resp := registry.Call(ctx, req)
 	assert.True(t, resp.IsError)
-	assert.Contains(t, resp.Content[0].Text, "campaign ID is required")
+	assert.Contains(t, resp.Content[0].Text, "campaign_id is required")
 }
 
 func TestContextTools_SaveMessage_UserMessage(t *testing.T) {
@@ -504,6 +549,60 @@ func TestContextTools_SaveMessage_SystemMessage(t *testing.T) {
 	assert.Len(t, messages, 1)
 }
 
+func TestContextTools_SaveMessage_ToolCallsWithoutContent(t *testing.T) {
+	contextTools, registry, _, msgStore, _, _, _ := setupContextTools()
+	contextTools.Register(registry)
+
+	ctx := context.Background()
+
+	args, _ := json.Marshal(map[string]interface{}{
+		"campaign_id": "campaign-001",
+		"role":        "assistant",
+		"content":     "",
+		"tool_calls": []map[string]interface{}{
+			{"id": "call-001", "name": "roll_dice", "arguments": map[string]interface{}{"formula": "1d20"}},
+		},
+	})
+
+	resp := registry.Call(ctx, mcp.ToolRequest{ToolName: "save_message", Arguments: args})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	messages, _ := msgStore.ListByCampaign(ctx, "campaign-001", 0)
+	assert.Len(t, messages, 1)
+}
+
+func TestContextTools_SaveMessage_ToolMessage(t *testing.T) {
+	contextTools, registry, _, msgStore, _, _, _ := setupContextTools()
+	contextTools.Register(registry)
+
+	ctx := context.Background()
+
+	args, _ := json.Marshal(map[string]interface{}{
+		"campaign_id":  "campaign-001",
+		"role":         "tool",
+		"content":      `{"total":18}`,
+		"tool_call_id": "call-001",
+	})
+
+	resp := registry.Call(ctx, mcp.ToolRequest{ToolName: "save_message", Arguments: args})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	messages, _ := msgStore.ListByCampaign(ctx, "campaign-001", 0)
+	require.Len(t, messages, 1)
+	assert.Equal(t, models.MessageRoleTool, messages[0].Role)
+	assert.Equal(t, "call-001", messages[0].ToolCallID)
+
+	// Tool messages must say which tool call they answer
+	args, _ = json.Marshal(map[string]interface{}{
+		"campaign_id": "campaign-001",
+		"role":        "tool",
+		"content":     `{"total":18}`,
+	})
+	resp = registry.Call(ctx, mcp.ToolRequest{ToolName: "save_message", Arguments: args})
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "tool_call_id")
+}
+
 func TestContextTools_SaveMessage_InvalidRole(t *testing.T) {
 	contextTools, registry, _, _, _, _, _ := setupContextTools()
 	contextTools.Register(registry)
@@ -129,7 +129,7 @@ func TestDiceTools_Register(t *testing.T) {
 	diceTools.Register(registry)
 
 	// Verify all tools are registered
-	assert.Equal(t, 3, registry.Count())
+	assert.Equal(t, 5, registry.Count())
 
 	for _, name := range tools.DiceToolNames {
 		assert.True(t, registry.Has(name), "Tool %s should be registered", name)
@@ -598,6 +598,49 @@ func TestDiceTools_RollSave_MissingRequired(t *testing.T) {
 	assert.Contains(t, resp.Content[0].Text, "character_id is required")
 }
 
+func TestDiceTools_RollAttack(t *testing.T) {
+	diceTools, registry, _ := setupDiceTools()
+	diceTools.Register(registry)
+
+	args, _ := json.Marshal(map[string]interface{}{
+		"attack_bonus": 5,
+		"target_ac":    15,
+	})
+
+	resp := registry.Call(context.Background(), mcp.ToolRequest{ToolName: "roll_attack", Arguments: args})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	var result map[string]interface{}
+	err := json.Unmarshal([]byte(resp.Content[0].Text), &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(15), result["target_ac"])
+	assert.Contains(t, result, "hit")
+	assert.Contains(t, result["message"], "vs AC 15")
+}
+
+func TestDiceTools_RollDamage(t *testing.T) {
+	diceTools, registry, _ := setupDiceTools()
+	diceTools.Register(registry)
+
+	args, _ := json.Marshal(map[string]interface{}{
+		"formula": "2d6+3",
+		"crit":    true,
+	})
+
+	resp := registry.Call(context.Background(), mcp.ToolRequest{ToolName: "roll_damage", Arguments: args})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	var result map[string]interface{}
+	err := json.Unmarshal([]byte(resp.Content[0].Text), &result)
+	require.NoError(t, err)
+
+	total := result["result"].(map[string]interface{})["total"].(float64)
+	assert.GreaterOrEqual(t, total, float64(7), "critical hits roll 4d6+3")
+	assert.LessOrEqual(t, total, float64(27))
+	assert.Contains(t, result["message"], "[CRITICAL]")
+}
+
 func TestDiceTools_InvalidJSON(t *testing.T) {
 	diceTools, registry, _ := setupDiceTools()
 	diceTools.Register(registry)
@@ -636,7 +679,7 @@ func TestDiceTools_ListTools(t *testing.T) {
 	diceTools.Register(registry)
 
 	toolList := registry.List()
-	assert.Len(t, toolList, 3)
+	assert.Len(t, toolList, 5)
 
 	// Verify tool definitions
 	toolNames := make(map[string]bool)
@@ -649,4 +692,6 @@ func TestDiceTools_ListTools(t *testing.T) {
 	assert.True(t, toolNames["roll_dice"])
 	assert.True(t, toolNames["roll_check"])
 	assert.True(t, toolNames["roll_save"])
+	assert.True(t, toolNames["roll_attack"])
+	assert.True(t, toolNames["roll_damage"])
 }
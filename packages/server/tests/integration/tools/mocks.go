@@ -348,3 +348,48 @@ func (m *MockMessageStore) CountByCampaign(ctx context.Context, campaignID strin
 	}
 	return len(msgs), nil
 }
+
+func (m *MockMessageStore) DeleteByCampaignAfterDate(ctx context.Context, campaignID string, afterDate time.Time) (int64, error) {
+	kept := []*models.Message{}
+	for _, msg := range m.messages[campaignID] {
+		if !msg.CreatedAt.After(afterDate) {
+			kept = append(kept, msg)
+		}
+	}
+	removed := int64(len(m.messages[campaignID]) - len(kept))
+	m.messages[campaignID] = kept
+	return removed, nil
+}
+
+// MockSnapshotStore for testing
+type MockSnapshotStore struct {
+	snapshots []*models.Snapshot
+}
+
+func NewMockSnapshotStore() *MockSnapshotStore {
+	return &MockSnapshotStore{}
+}
+
+func (m *MockSnapshotStore) Create(ctx context.Context, snapshot *models.Snapshot) error {
+	m.snapshots = append(m.snapshots, snapshot)
+	return nil
+}
+
+func (m *MockSnapshotStore) Get(ctx context.Context, id string) (*models.Snapshot, error) {
+	for _, snapshot := range m.snapshots {
+		if snapshot.ID == id {
+			return snapshot, nil
+		}
+	}
+	return nil, service.NewServiceError(service.ErrCodeNotFound, "snapshot not found")
+}
+
+func (m *MockSnapshotStore) ListByCampaign(ctx context.Context, campaignID string) ([]*models.Snapshot, error) {
+	result := []*models.Snapshot{}
+	for i := len(m.snapshots) - 1; i >= 0; i-- {
+		if m.snapshots[i].CampaignID == campaignID {
+			result = append(result, m.snapshots[i])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,189 @@
+// Package tools contains integration tests for MCP prompts
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/api/prompts"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type promptFixture struct {
+	registry     *mcp.PromptRegistry
+	campaign     *models.Campaign
+	combatStore  *MockCombatStore
+	messageStore *MockMessageStore
+	fighter      *models.Character
+	innkeeper    *models.Character
+}
+
+// setupPrompts registers the game prompts over a campaign with a fighter and an NPC innkeeper
+func setupPrompts(t *testing.T) *promptFixture {
+	ctx := context.Background()
+	campaignStore := NewMockCampaignStore()
+	gameStateStore := NewMockGameStateStore()
+	characterStore := NewMockCharacterStore()
+	combatStore := NewMockCombatStore()
+	messageStore := NewMockMessageStore()
+	mapStore := NewMockMapStore()
+
+	campaign := models.NewCampaign("Lost Mine", "dm-001", "")
+	campaign.ID = "campaign-001"
+	require.NoError(t, campaignStore.Create(ctx, campaign))
+	gameState := models.NewGameState(campaign.ID)
+	gameState.Weather = "rain"
+	require.NoError(t, gameStateStore.Create(ctx, gameState))
+
+	fighter := models.NewCharacter(campaign.ID, "Brienne", false)
+	fighter.ID = "fighter"
+	fighter.Race, fighter.Class, fighter.AC = "Human", "Fighter", 18
+	fighter.HP = models.NewHP(30)
+	require.NoError(t, characterStore.Create(ctx, fighter))
+
+	innkeeper := models.NewCharacter(campaign.ID, "Toblen", true)
+	innkeeper.ID = "innkeeper"
+	innkeeper.Race, innkeeper.Class = "Human", "Commoner"
+	innkeeper.HP = models.NewHP(4)
+	innkeeper.Biography = &models.Biography{PersonalityTraits: "Friendly but nosy"}
+	require.NoError(t, characterStore.Create(ctx, innkeeper))
+
+	campaignService := service.NewCampaignService(campaignStore, gameStateStore)
+	characterService := service.NewCharacterService(characterStore)
+	mapService := service.NewMapService(mapStore, campaignStore, gameStateStore)
+	diceService := service.NewDiceService(characterStore)
+	combatService := service.NewCombatService(combatStore, characterStore, campaignStore, gameStateStore, diceService)
+	contextService := service.NewContextService(messageStore, characterStore, gameStateStore, combatStore, mapStore)
+
+	registry := mcp.NewPromptRegistry()
+	prompts.NewGamePrompts(campaignService, characterService, mapService, combatService, contextService).Register(registry)
+
+	return &promptFixture{
+		registry:     registry,
+		campaign:     campaign,
+		combatStore:  combatStore,
+		messageStore: messageStore,
+		fighter:      fighter,
+		innkeeper:    innkeeper,
+	}
+}
+
+// promptText renders a prompt and returns its single message
+func promptText(t *testing.T, f *promptFixture, name string, args map[string]string) string {
+	result, err := f.registry.Get(context.Background(), name, args)
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, "user", result.Messages[0].Role)
+	return result.Messages[0].Content.Text
+}
+
+func TestPrompts_List(t *testing.T) {
+	f := setupPrompts(t)
+
+	names := make([]string, 0)
+	for _, prompt := range f.registry.List() {
+		names = append(names, prompt.Name)
+		assert.Equal(t, "campaign_id", prompt.Arguments[0].Name)
+		assert.True(t, prompt.Arguments[0].Required)
+	}
+	assert.Equal(t, []string{"describe_location", "npc_dialogue", "run_combat_turn", "session_recap"}, names)
+}
+
+func TestPrompts_RunCombatTurn(t *testing.T) {
+	f := setupPrompts(t)
+
+	// Without an active combat the arguments do not address a turn
+	_, err := f.registry.Get(context.Background(), prompts.RunCombatTurn, map[string]string{"campaign_id": f.campaign.ID})
+	assert.True(t, errors.Is(err, mcp.ErrInvalidPromptArguments))
+
+	combat := models.NewCombat(f.campaign.ID, []string{f.innkeeper.ID, f.fighter.ID})
+	combat.ID = "combat-001"
+	combat.Round = 2
+	combat.Participants[0].Initiative = 15
+	combat.Participants[1].Initiative = 9
+	require.NoError(t, f.combatStore.Create(context.Background(), combat))
+
+	text := promptText(t, f, prompts.RunCombatTurn, map[string]string{"campaign_id": f.campaign.ID})
+	assert.Contains(t, text, "Round 2")
+	assert.Contains(t, text, "> Toblen (NPC): initiative 15, HP 4/4")
+	assert.Contains(t, text, "- Brienne: initiative 9, HP 30/30, AC 18")
+	assert.Contains(t, text, "It is Toblen's turn.")
+	assert.Contains(t, text, "Choose Toblen's action")
+}
+
+func TestPrompts_DescribeLocation(t *testing.T) {
+	f := setupPrompts(t)
+
+	text := promptText(t, f, prompts.DescribeLocation, map[string]string{"campaign_id": f.campaign.ID, "location": "the Stonehill Inn"})
+	assert.Contains(t, text, "Describe the Stonehill Inn")
+	assert.Contains(t, text, "Weather: rain")
+	assert.Contains(t, text, "Party: Brienne")
+}
+
+func TestPrompts_NPCDialogue(t *testing.T) {
+	f := setupPrompts(t)
+	args := map[string]string{
+		"campaign_id":  f.campaign.ID,
+		"character_id": f.innkeeper.ID,
+		"speaker_id":   f.fighter.ID,
+		"topic":        "the missing dwarves",
+	}
+
+	text := promptText(t, f, prompts.NPCDialogue, args)
+	assert.Contains(t, text, "Speak as Toblen")
+	assert.Contains(t, text, "Personality: Friendly but nosy")
+	assert.Contains(t, text, "Speaking with: Brienne")
+	assert.Contains(t, text, "Topic: the missing dwarves")
+
+	// Player characters cannot be voiced as NPCs
+	args["character_id"] = f.fighter.ID
+	_, err := f.registry.Get(context.Background(), prompts.NPCDialogue, args)
+	assert.True(t, errors.Is(err, mcp.ErrInvalidPromptArguments))
+
+	// character_id is required
+	_, err = f.registry.Get(context.Background(), prompts.NPCDialogue, map[string]string{"campaign_id": f.campaign.ID})
+	assert.True(t, errors.Is(err, mcp.ErrInvalidPromptArguments))
+}
+
+func TestPrompts_SessionRecap(t *testing.T) {
+	f := setupPrompts(t)
+	ctx := context.Background()
+	require.NoError(t, f.messageStore.Create(ctx, models.NewMessage(f.campaign.ID, models.MessageRoleUser, "We search the cave")))
+	require.NoError(t, f.messageStore.Create(ctx, models.NewMessage(f.campaign.ID, models.MessageRoleAssistant, "You find a goblin trail")))
+
+	text := promptText(t, f, prompts.SessionRecap, map[string]string{"campaign_id": f.campaign.ID})
+	assert.Contains(t, text, "[user] We search the cave")
+	assert.Contains(t, text, "[assistant] You find a goblin trail")
+
+	text = promptText(t, f, prompts.SessionRecap, map[string]string{"campaign_id": f.campaign.ID, "message_limit": "1"})
+	assert.NotContains(t, text, "We search the cave")
+
+	_, err := f.registry.Get(ctx, prompts.SessionRecap, map[string]string{"campaign_id": f.campaign.ID, "message_limit": "many"})
+	assert.True(t, errors.Is(err, mcp.ErrInvalidPromptArguments))
+}
+
+func TestPrompts_CampaignOverride(t *testing.T) {
+	f := setupPrompts(t)
+	f.campaign.Settings.Prompts = map[string]string{
+		prompts.DescribeLocation: `Narrate like a bard: {{.Args.location}} in the {{.Summary.Weather}}.`,
+	}
+
+	text := promptText(t, f, prompts.DescribeLocation, map[string]string{"campaign_id": f.campaign.ID, "location": "the market"})
+	assert.Equal(t, "Narrate like a bard: the market in the rain.", text)
+
+	// Other prompts keep their default template
+	text = promptText(t, f, prompts.SessionRecap, map[string]string{"campaign_id": f.campaign.ID})
+	assert.Contains(t, text, "Write a recap of the story so far")
+}
+
+func TestPrompts_UnknownCampaign(t *testing.T) {
+	f := setupPrompts(t)
+
+	_, err := f.registry.Get(context.Background(), prompts.SessionRecap, map[string]string{"campaign_id": "missing"})
+	assert.True(t, errors.Is(err, mcp.ErrInvalidPromptArguments))
+}
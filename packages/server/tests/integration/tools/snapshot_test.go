@@ -0,0 +1,187 @@
+// Package tools contains integration tests for campaign snapshot tools
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/api/tools"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const heroID = "aaaaaaaa-0000-0000-0000-000000000002"
+
+func setupSnapshotTools() *archiveFixture {
+	f := newArchiveFixture()
+	campaignService := service.NewCampaignService(f.campaigns, f.gameStates)
+	campaignService.SetSnapshotStores(NewMockSnapshotStore(), f.characters, f.maps, f.combats, f.messages, nil)
+	tools.NewCampaignTools(campaignService).Register(f.registry)
+	return f
+}
+
+// playOn changes the campaign after a snapshot: the hero is hurt, an NPC and a
+// map are added, a new combat starts and a message is sent
+func (f *archiveFixture) playOn(ctx context.Context, campaignID string) {
+	hero, _ := f.characters.Get(ctx, heroID)
+	hero.HP = models.NewHP(12)
+	hero.HP.Current = 3
+
+	goblin := models.NewCharacter(campaignID, "Goblin", true)
+	f.characters.Create(ctx, goblin)
+	f.maps.Create(ctx, models.NewBattleMap(campaignID, "Goblin Den", 8, 8, 5))
+
+	gameState, _ := f.gameStates.Get(ctx, campaignID)
+	combat := models.NewCombat(campaignID, []string{heroID, goblin.ID})
+	f.combats.Create(ctx, combat)
+	gameState.SetCombat(combat.ID)
+
+	f.messages.Create(ctx, models.NewUserMessage(campaignID, "player-001", "I charge the goblin"))
+}
+
+func TestCampaignTools_CreateAndListSnapshots(t *testing.T) {
+	f := setupSnapshotTools()
+	original := f.seedCampaign(context.Background())
+
+	created := callJSON(t, f.registry, "create_snapshot", map[string]interface{}{
+		"campaign_id": original.ID,
+		"name":        "Before the ambush",
+	})
+	snapshot := created["snapshot"].(map[string]interface{})
+	assert.Equal(t, "Before the ambush", snapshot["name"])
+	assert.Equal(t, float64(1), snapshot["message_count"])
+	assert.Greater(t, snapshot["size"], float64(0))
+
+	listed := callJSON(t, f.registry, "list_snapshots", map[string]interface{}{"campaign_id": original.ID})
+	assert.Equal(t, float64(1), listed["count"])
+}
+
+func TestCampaignTools_RestoreSnapshot(t *testing.T) {
+	f := setupSnapshotTools()
+	ctx := context.Background()
+	original := f.seedCampaign(ctx)
+	hero, _ := f.characters.Get(ctx, heroID)
+	hero.HP = models.NewHP(12)
+
+	created := callJSON(t, f.registry, "create_snapshot", map[string]interface{}{"campaign_id": original.ID})
+	snapshotID := created["snapshot"].(map[string]interface{})["id"].(string)
+	f.playOn(ctx, original.ID)
+
+	restored := callJSON(t, f.registry, "restore_snapshot", map[string]interface{}{
+		"campaign_id": original.ID,
+		"snapshot_id": snapshotID,
+	})
+	restore := restored["restore"].(map[string]interface{})
+	assert.Equal(t, float64(1), restore["removed_characters"])
+	assert.Equal(t, float64(1), restore["removed_maps"])
+	assert.Equal(t, float64(1), restore["removed_combats"])
+	assert.Equal(t, float64(1), restore["removed_messages"])
+
+	hero, err := f.characters.Get(ctx, heroID)
+	require.NoError(t, err)
+	assert.Equal(t, 12, hero.HP.Current)
+
+	characters, _ := f.characters.List(ctx, nil)
+	assert.Len(t, characters, 1)
+	maps, _ := f.maps.GetByCampaign(ctx, original.ID)
+	assert.Len(t, maps, 1)
+	combats, _ := f.combats.GetByCampaign(ctx, original.ID)
+	require.Len(t, combats, 1)
+
+	gameState, _ := f.gameStates.Get(ctx, original.ID)
+	assert.Equal(t, combats[0].ID, gameState.ActiveCombatID)
+
+	messages, _ := f.messages.ListByCampaign(ctx, original.ID, 0)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "We enter the cave", messages[0].Content)
+}
+
+func TestCampaignTools_ForkCampaign(t *testing.T) {
+	f := setupSnapshotTools()
+	ctx := context.Background()
+	original := f.seedCampaign(ctx)
+	hero, _ := f.characters.Get(ctx, heroID)
+	hero.HP = models.NewHP(12)
+
+	created := callJSON(t, f.registry, "create_snapshot", map[string]interface{}{"campaign_id": original.ID})
+	snapshotID := created["snapshot"].(map[string]interface{})["id"].(string)
+	f.playOn(ctx, original.ID)
+
+	t.Run("from snapshot", func(t *testing.T) {
+		forked := callJSON(t, f.registry, "fork_campaign", map[string]interface{}{
+			"campaign_id": original.ID,
+			"snapshot_id": snapshotID,
+			"name":        "What if we sneak",
+		})
+		forkID := forked["campaign"].(map[string]interface{})["id"].(string)
+		assert.NotEqual(t, original.ID, forkID)
+
+		fork, err := f.campaigns.Get(ctx, forkID)
+		require.NoError(t, err)
+		assert.Equal(t, "What if we sneak", fork.Name)
+
+		characters, _ := f.characters.List(ctx, &store.CharacterFilter{CampaignID: forkID})
+		require.Len(t, characters, 1)
+		assert.NotEqual(t, heroID, characters[0].ID)
+		assert.Equal(t, 12, characters[0].HP.Current)
+
+		messages, _ := f.messages.ListByCampaign(ctx, forkID, 0)
+		assert.Len(t, messages, 1, "only messages up to the snapshot are copied")
+	})
+
+	t.Run("from current state", func(t *testing.T) {
+		forked := callJSON(t, f.registry, "fork_campaign", map[string]interface{}{"campaign_id": original.ID})
+		fork := forked["campaign"].(map[string]interface{})
+		assert.Equal(t, "Lost Mine (fork)", fork["name"])
+		forkID := fork["id"].(string)
+
+		characters, _ := f.characters.List(ctx, &store.CharacterFilter{CampaignID: forkID})
+		assert.Len(t, characters, 2)
+		combats, _ := f.combats.GetByCampaign(ctx, forkID)
+		require.Len(t, combats, 1, "only the active combat is copied")
+		gameState, err := f.gameStates.Get(ctx, forkID)
+		require.NoError(t, err)
+		assert.Equal(t, combats[0].ID, gameState.ActiveCombatID)
+
+		messages, _ := f.messages.ListByCampaign(ctx, forkID, 0)
+		assert.Len(t, messages, 2)
+	})
+
+	// The original campaign is not changed
+	hero, _ = f.characters.Get(ctx, heroID)
+	assert.Equal(t, 3, hero.HP.Current)
+	messages, _ := f.messages.ListByCampaign(ctx, original.ID, 0)
+	assert.Len(t, messages, 2)
+}
+
+func TestCampaignTools_RestoreSnapshotFromOtherCampaign(t *testing.T) {
+	f := setupSnapshotTools()
+	ctx := context.Background()
+	original := f.seedCampaign(ctx)
+
+	created := callJSON(t, f.registry, "create_snapshot", map[string]interface{}{"campaign_id": original.ID})
+	snapshotID := created["snapshot"].(map[string]interface{})["id"].(string)
+	other := models.NewCampaign("Other", "dm-001", "")
+	other.ID = "bbbbbbbb-0000-0000-0000-000000000001"
+	f.campaigns.Create(ctx, other)
+
+	args, _ := json.Marshal(map[string]interface{}{"campaign_id": other.ID, "snapshot_id": snapshotID})
+	resp := f.registry.Call(ctx, mcp.ToolRequest{ToolName: "restore_snapshot", Arguments: args})
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "not found")
+}
+
+func TestCampaignTools_SnapshotsNotEnabled(t *testing.T) {
+	campaignTools, registry, _ := setupCampaignTools()
+	campaignTools.Register(registry)
+
+	args, _ := json.Marshal(map[string]interface{}{"campaign_id": "c1"})
+	resp := registry.Call(context.Background(), mcp.ToolRequest{ToolName: "create_snapshot", Arguments: args})
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "not enabled")
+}
@@ -0,0 +1,95 @@
+// Package tools contains integration tests for vision tools
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/api/tools"
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupVisionTools creates a lit battle map with a player's hero and a goblin NPC
+func setupVisionTools() *mcp.Registry {
+	mapStore := NewMockMapStore()
+	characterStore := NewMockCharacterStore()
+
+	hero := models.NewCharacter("campaign-001", "Hero", false)
+	hero.ID = "hero"
+	hero.PlayerID = "player-001"
+	characterStore.Create(context.Background(), hero)
+
+	goblin := models.NewCharacter("campaign-001", "Goblin", true)
+	goblin.ID = "goblin"
+	characterStore.Create(context.Background(), goblin)
+
+	battleMap := models.NewBattleMap("campaign-001", "Cave", 10, 10, 5)
+	battleMap.ID = "map-001"
+	for _, id := range []string{"hero", "goblin"} {
+		token := models.NewToken(id, len(battleMap.Tokens)*4, 0, models.TokenSizeMedium)
+		token.ID = "token-" + id
+		battleMap.Tokens = append(battleMap.Tokens, *token)
+	}
+	battleMap.Tokens = append(battleMap.Tokens, models.Token{ID: "token-prop", Position: models.Position{X: 2, Y: 2}, Size: models.TokenSizeMedium, Visible: true})
+	mapStore.Create(context.Background(), battleMap)
+
+	visionService := service.NewVisionService(mapStore, characterStore, NewMockCombatStore(), nil)
+	registry := mcp.NewRegistry()
+	tools.NewVisionTools(visionService).Register(registry)
+	tools.RegisterAccess(registry)
+	return registry
+}
+
+func callAs(registry *mcp.Registry, role auth.Role, userID, tool string, args map[string]interface{}) mcp.ToolResponse {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID, Role: role})
+	ctx = auth.WithCampaignDMs(ctx, func(ctx context.Context, campaignID string) (string, error) {
+		return "dm-001", nil
+	})
+	data, _ := json.Marshal(args)
+	return registry.Call(ctx, mcp.ToolRequest{ToolName: tool, Arguments: data})
+}
+
+func TestVisionTools_GetVisibleArea_PlayerOwnTokenOnly(t *testing.T) {
+	registry := setupVisionTools()
+
+	resp := callAs(registry, auth.RolePlayer, "player-001", "get_visible_area", map[string]interface{}{"map_id": "map-001", "token_id": "token-hero"})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Contains(t, resp.Content[0].Text, "token-goblin")
+
+	resp = callAs(registry, auth.RolePlayer, "player-001", "get_visible_area", map[string]interface{}{"map_id": "map-001", "token_id": "token-goblin"})
+	assert.True(t, resp.IsError, "a player must not view from an NPC's token")
+	assert.Contains(t, resp.Content[0].Text, "may not act as Goblin")
+
+	resp = callAs(registry, auth.RolePlayer, "player-001", "get_visible_area", map[string]interface{}{"map_id": "map-001", "token_id": "token-prop"})
+	assert.True(t, resp.IsError, "a player must not view from a token without a character")
+	assert.Contains(t, resp.Content[0].Text, "only the DM may view from this token")
+
+	resp = callAs(registry, auth.RolePlayer, "player-002", "get_visible_area", map[string]interface{}{"map_id": "map-001", "token_id": "token-hero"})
+	assert.True(t, resp.IsError, "a player must not view from another player's token")
+
+	resp = callAs(registry, auth.RoleDM, "dm-001", "get_visible_area", map[string]interface{}{"map_id": "map-001", "token_id": "token-goblin"})
+	assert.False(t, resp.IsError, resp.Content[0].Text)
+}
+
+func TestVisionTools_CanSee_PlayerOwnTokenOnly(t *testing.T) {
+	registry := setupVisionTools()
+
+	resp := callAs(registry, auth.RolePlayer, "player-001", "can_see", map[string]interface{}{"map_id": "map-001", "viewer_token_id": "token-hero", "target_token_id": "token-goblin"})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+
+	resp = callAs(registry, auth.RolePlayer, "player-001", "can_see", map[string]interface{}{"map_id": "map-001", "viewer_token_id": "token-goblin", "target_token_id": "token-hero"})
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "may not act as Goblin")
+
+	resp = callAs(registry, auth.RoleObserver, "watcher", "can_see", map[string]interface{}{"map_id": "map-001", "viewer_token_id": "token-hero", "target_token_id": "token-goblin"})
+	assert.True(t, resp.IsError, "observers do not view from any token")
+
+	resp = callAs(registry, auth.RoleDM, "dm-001", "can_see", map[string]interface{}{"map_id": "map-001", "viewer_token_id": "token-goblin", "target_token_id": "token-hero"})
+	assert.False(t, resp.IsError, resp.Content[0].Text)
+}
@@ -0,0 +1,178 @@
+package archive_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/archive"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleContents builds a campaign whose records reference each other
+func sampleContents() *archive.Contents {
+	campaign := models.NewCampaign("Lost Mine", "dm-1", "Phandelver")
+	campaign.ID = "11111111-1111-1111-1111-111111111111"
+
+	hero := models.NewCharacter(campaign.ID, "Hero", false)
+	hero.ID = "22222222-2222-2222-2222-222222222222"
+	hero.PlayerID = "player-1"
+
+	world := models.NewWorldMap(campaign.ID, "Sword Coast", 20, 20)
+	world.ID = "33333333-3333-3333-3333-333333333333"
+	cave := models.NewBattleMap(campaign.ID, "Cragmaw Cave", 10, 10, 5)
+	cave.ID = "44444444-4444-4444-4444-444444444444"
+	cave.ParentID = world.ID
+	token := models.NewToken(hero.ID, 2, 3, models.TokenSizeMedium)
+	cave.Tokens = append(cave.Tokens, *token)
+
+	combat := models.NewCombat(campaign.ID, []string{hero.ID})
+	combat.ID = "55555555-5555-5555-5555-555555555555"
+	combat.MapID = cave.ID
+	combat.AddLogEntry(hero.ID, "attack", "", "Hero attacks")
+
+	gameState := models.NewGameState(campaign.ID)
+	gameState.SetCurrentMap(cave.ID, models.MapTypeBattle)
+	gameState.SetCombat(combat.ID)
+
+	return &archive.Contents{
+		Campaign:   campaign,
+		GameState:  gameState,
+		Characters: []*models.Character{hero},
+		Maps:       []*models.Map{world, cave},
+		Combats:    []*models.Combat{combat},
+		Messages:   []*models.Message{models.NewUserMessage(campaign.ID, "player-1", "I attack the goblin")},
+	}
+}
+
+func writeArchive(t *testing.T, contents *archive.Contents) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := archive.Write(&buf, contents, time.Now())
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+// zipWith builds a zip from raw files
+func zipWith(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	original := sampleContents()
+	data := writeArchive(t, original)
+
+	contents, manifest, err := archive.Read(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, archive.Format, manifest.Format)
+	assert.Equal(t, archive.Version, manifest.Version)
+	assert.Equal(t, "Lost Mine", manifest.CampaignName)
+	assert.Equal(t, 1, manifest.Count(archive.KindCharacter))
+	assert.Equal(t, 2, manifest.Count(archive.KindMap))
+
+	assert.Equal(t, original.Campaign.ID, contents.Campaign.ID)
+	assert.Equal(t, original.GameState.ActiveCombatID, contents.GameState.ActiveCombatID)
+	require.Len(t, contents.Characters, 1)
+	assert.Equal(t, "Hero", contents.Characters[0].Name)
+	require.Len(t, contents.Maps, 2)
+	assert.Len(t, contents.Maps[1].Tokens, 1)
+	require.Len(t, contents.Combats, 1)
+	assert.Len(t, contents.Combats[0].Log, 1)
+	require.Len(t, contents.Messages, 1)
+	assert.Equal(t, "I attack the goblin", contents.Messages[0].Content)
+}
+
+func TestRead_RejectsUnreadableArchives(t *testing.T) {
+	manifest := func(format string, version int) string {
+		data, _ := json.Marshal(archive.Manifest{Format: format, Version: version})
+		return string(data)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not a zip", []byte("hello")},
+		{"missing manifest", zipWith(t, map[string]string{"campaign.json": "{}"})},
+		{"unknown format", zipWith(t, map[string]string{archive.ManifestFile: manifest("something-else", 1)})},
+		{"missing version", zipWith(t, map[string]string{archive.ManifestFile: manifest(archive.Format, 0)})},
+		{"newer version", zipWith(t, map[string]string{archive.ManifestFile: manifest(archive.Format, archive.Version+1)})},
+		{"no campaign", zipWith(t, map[string]string{archive.ManifestFile: manifest(archive.Format, archive.Version)})},
+		{"missing file", zipWith(t, map[string]string{archive.ManifestFile: `{"format":"dnd-mcp-campaign","version":1,"entries":[{"kind":"campaign","path":"campaign.json"}]}`})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := archive.Read(tt.data)
+			assert.ErrorIs(t, err, archive.ErrInvalidArchive)
+		})
+	}
+}
+
+func TestRead_IgnoresUnknownFields(t *testing.T) {
+	data := zipWith(t, map[string]string{
+		archive.ManifestFile: `{"format":"dnd-mcp-campaign","version":1,"entries":[{"kind":"campaign","path":"campaign.json"}]}`,
+		"campaign.json":      `{"id":"c1","name":"Old Campaign","dm_id":"dm-1","retired_field":true}`,
+	})
+
+	contents, _, err := archive.Read(data)
+	require.NoError(t, err)
+	assert.Equal(t, "Old Campaign", contents.Campaign.Name)
+	assert.Empty(t, contents.Characters)
+	assert.Empty(t, contents.Messages)
+}
+
+func TestRemapIDs_FollowsReferences(t *testing.T) {
+	contents := sampleContents()
+	tokenID := contents.Maps[1].Tokens[0].ID
+	messageID := contents.Messages[0].ID
+
+	ids, err := archive.RemapIDs(contents)
+	require.NoError(t, err)
+
+	newCampaignID := ids["11111111-1111-1111-1111-111111111111"]
+	newHeroID := ids["22222222-2222-2222-2222-222222222222"]
+	newWorldID := ids["33333333-3333-3333-3333-333333333333"]
+	newCaveID := ids["44444444-4444-4444-4444-444444444444"]
+	newCombatID := ids["55555555-5555-5555-5555-555555555555"]
+	for _, id := range []string{newCampaignID, newHeroID, newWorldID, newCaveID, newCombatID, ids[tokenID], ids[messageID]} {
+		assert.NotEmpty(t, id)
+	}
+
+	assert.Equal(t, newCampaignID, contents.Campaign.ID)
+	assert.Equal(t, newCampaignID, contents.GameState.ID)
+	assert.Equal(t, newCampaignID, contents.GameState.CampaignID)
+	assert.Equal(t, newCaveID, contents.GameState.CurrentMapID)
+	assert.Equal(t, newCombatID, contents.GameState.ActiveCombatID)
+
+	assert.Equal(t, newHeroID, contents.Characters[0].ID)
+	assert.Equal(t, newCampaignID, contents.Characters[0].CampaignID)
+	assert.Equal(t, "player-1", contents.Characters[0].PlayerID, "user IDs are kept")
+	assert.Equal(t, "dm-1", contents.Campaign.DMID)
+
+	assert.Equal(t, newWorldID, contents.Maps[1].ParentID)
+	assert.Equal(t, newHeroID, contents.Maps[1].Tokens[0].CharacterID)
+	assert.Equal(t, ids[tokenID], contents.Maps[1].Tokens[0].ID)
+
+	assert.Equal(t, newCaveID, contents.Combats[0].MapID)
+	assert.Equal(t, newHeroID, contents.Combats[0].Participants[0].CharacterID)
+	assert.Equal(t, newHeroID, contents.Combats[0].Log[0].ActorID)
+
+	assert.Equal(t, newCampaignID, contents.Messages[0].CampaignID)
+	assert.Equal(t, ids[messageID], contents.Messages[0].ID)
+}
@@ -0,0 +1,95 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticator_APIKeys(t *testing.T) {
+	a, err := auth.NewAuthenticator(&config.AuthConfig{APIKeys: "dm-key:dm:alice, player-key:player:bob"})
+	require.NoError(t, err)
+
+	p, err := a.Authenticate("dm-key")
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{UserID: "alice", Role: auth.RoleDM}, p)
+
+	p, err = a.Authenticate("player-key")
+	require.NoError(t, err)
+	assert.Equal(t, auth.RolePlayer, p.Role)
+	assert.False(t, p.IsDM())
+	assert.True(t, p.CanWrite())
+
+	_, err = a.Authenticate("wrong-key")
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+	_, err = a.Authenticate("")
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+}
+
+func TestNewAuthenticator_InvalidConfig(t *testing.T) {
+	_, err := auth.NewAuthenticator(&config.AuthConfig{})
+	assert.Error(t, err)
+
+	_, err = auth.NewAuthenticator(&config.AuthConfig{APIKeys: "key:wizard:alice"})
+	assert.ErrorContains(t, err, "unknown role")
+
+	_, err = auth.NewAuthenticator(&config.AuthConfig{APIKeys: "key:dm"})
+	assert.ErrorContains(t, err, "key:role:user_id")
+}
+
+func TestAuthenticator_JWT(t *testing.T) {
+	secret := []byte("test-secret")
+	a, err := auth.NewAuthenticator(&config.AuthConfig{JWTSecret: string(secret)})
+	require.NoError(t, err)
+
+	token, err := auth.IssueToken(secret, auth.Principal{UserID: "carol", Role: auth.RoleObserver}, time.Hour)
+	require.NoError(t, err)
+
+	p, err := a.Authenticate(token)
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{UserID: "carol", Role: auth.RoleObserver}, p)
+	assert.False(t, p.CanWrite())
+
+	// Tokens signed with another secret are rejected
+	forged, err := auth.IssueToken([]byte("other-secret"), auth.Principal{UserID: "carol", Role: auth.RoleDM}, time.Hour)
+	require.NoError(t, err)
+	_, err = a.Authenticate(forged)
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+
+	// Tampering with the payload invalidates the signature
+	parts := strings.Split(token, ".")
+	_, err = a.Authenticate(parts[0] + "." + parts[1] + "x." + parts[2])
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+}
+
+func TestAuthenticator_JWTExpired(t *testing.T) {
+	secret := []byte("test-secret")
+	a, err := auth.NewAuthenticator(&config.AuthConfig{JWTSecret: string(secret)})
+	require.NoError(t, err)
+
+	token, err := auth.IssueToken(secret, auth.Principal{UserID: "dave", Role: auth.RolePlayer}, -time.Minute)
+	require.NoError(t, err)
+	_, err = a.Authenticate(token)
+	assert.ErrorIs(t, err, auth.ErrTokenExpired)
+}
+
+func TestCredential(t *testing.T) {
+	assert.Equal(t, "abc", auth.Credential("Bearer abc", ""))
+	assert.Equal(t, "key", auth.Credential("", " key "))
+	assert.Equal(t, "abc", auth.Credential("Bearer abc", "key"))
+	assert.Equal(t, "", auth.Credential("Basic xyz", ""))
+}
+
+func TestParseRole(t *testing.T) {
+	role, err := auth.ParseRole(" DM ")
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleDM, role)
+
+	_, err = auth.ParseRole("admin")
+	assert.Error(t, err)
+}
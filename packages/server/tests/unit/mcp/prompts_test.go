@@ -0,0 +1,61 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// greetPrompt renders a greeting from the name argument
+func greetPrompt(ctx context.Context, args map[string]string) (*mcp.GetPromptResponse, error) {
+	return mcp.NewUserPrompt("greeting", "Hello "+args["name"]+args["title"]), nil
+}
+
+func TestPromptRegistry_Register(t *testing.T) {
+	r := mcp.NewPromptRegistry()
+	prompt := mcp.Prompt{Name: "greet"}
+
+	require.NoError(t, r.Register(prompt, greetPrompt))
+	assert.Error(t, r.Register(prompt, greetPrompt))
+	assert.Error(t, r.Register(mcp.Prompt{}, greetPrompt))
+	assert.Error(t, r.Register(mcp.Prompt{Name: "nil"}, nil))
+	assert.Equal(t, 1, r.Count())
+}
+
+func TestPromptRegistry_List(t *testing.T) {
+	r := mcp.NewPromptRegistry()
+	r.MustRegister(mcp.Prompt{Name: "b"}, greetPrompt)
+	r.MustRegister(mcp.Prompt{Name: "a"}, greetPrompt)
+
+	prompts := r.List()
+	require.Len(t, prompts, 2)
+	assert.Equal(t, "a", prompts[0].Name)
+	assert.Equal(t, "b", prompts[1].Name)
+}
+
+func TestPromptRegistry_Get(t *testing.T) {
+	r := mcp.NewPromptRegistry()
+	r.MustRegister(mcp.Prompt{
+		Name: "greet",
+		Arguments: []mcp.PromptArgument{
+			{Name: "name", Required: true},
+			{Name: "title"},
+		},
+	}, greetPrompt)
+
+	result, err := r.Get(context.Background(), "greet", map[string]string{"name": "Mira"})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, "user", result.Messages[0].Role)
+	assert.Equal(t, mcp.Content{Type: "text", Text: "Hello Mira"}, result.Messages[0].Content)
+
+	_, err = r.Get(context.Background(), "greet", nil)
+	assert.True(t, errors.Is(err, mcp.ErrInvalidPromptArguments))
+
+	_, err = r.Get(context.Background(), "farewell", nil)
+	assert.True(t, errors.Is(err, mcp.ErrPromptNotFound))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/dnd-mcp/server/internal/auth"
 	"github.com/dnd-mcp/server/internal/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -297,3 +298,113 @@ func TestPropWithDefault(t *testing.T) {
 	assert.Equal(t, "count", prop.Description)
 	assert.Equal(t, 10, prop.Default)
 }
+
+func TestRegistry_AccessByRole(t *testing.T) {
+	r := mcp.NewRegistry()
+	for _, name := range []string{"start_combat", "attack", "get_combat_state"} {
+		r.MustRegister(mcp.NewTool(name, name, mcp.NewObjectSchema(nil, nil)), func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+			return mcp.NewTextResponse(req.ToolName)
+		})
+	}
+	r.SetAccess("attack", mcp.AccessPlayer)
+	r.SetAccess("get_combat_state", mcp.AccessRead)
+
+	as := func(role auth.Role) context.Context {
+		return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "u1", Role: role})
+	}
+	names := func(ctx context.Context) []string {
+		var result []string
+		for _, tool := range r.ListFor(ctx) {
+			result = append(result, tool.Name)
+		}
+		return result
+	}
+
+	assert.ElementsMatch(t, []string{"start_combat", "attack", "get_combat_state"}, names(context.Background()))
+	assert.ElementsMatch(t, []string{"start_combat", "attack", "get_combat_state"}, names(as(auth.RoleDM)))
+	assert.ElementsMatch(t, []string{"attack", "get_combat_state"}, names(as(auth.RolePlayer)))
+	assert.ElementsMatch(t, []string{"get_combat_state"}, names(as(auth.RoleObserver)))
+
+	resp := r.Call(as(auth.RolePlayer), mcp.ToolRequest{ToolName: "start_combat"})
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, `forbidden: role "player" may not use tool start_combat`)
+
+	resp = r.Call(as(auth.RoleObserver), mcp.ToolRequest{ToolName: "attack"})
+	assert.True(t, resp.IsError)
+
+	resp = r.Call(as(auth.RolePlayer), mcp.ToolRequest{ToolName: "attack"})
+	assert.False(t, resp.IsError)
+	resp = r.Call(as(auth.RoleSystem), mcp.ToolRequest{ToolName: "start_combat"})
+	assert.False(t, resp.IsError)
+}
+
+func TestRegistry_DMLimitedToOwnCampaigns(t *testing.T) {
+	r := mcp.NewRegistry()
+	schema := mcp.NewObjectSchema(map[string]mcp.Property{"campaign_id": mcp.StringProp("campaign")}, nil)
+	for _, name := range []string{"start_combat", "get_combat_state", "create_campaign"} {
+		r.MustRegister(mcp.NewTool(name, name, schema), func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+			return mcp.NewTextResponse(req.ToolName)
+		})
+	}
+	r.SetAccess("get_combat_state", mcp.AccessRead)
+	r.SetCampaignResolver(func(ctx context.Context, arguments json.RawMessage) ([]string, error) {
+		var args struct {
+			CampaignID string `json:"campaign_id"`
+		}
+		err := json.Unmarshal(arguments, &args)
+		return []string{args.CampaignID}, err
+	})
+
+	as := func(role auth.Role, userID string) context.Context {
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID, Role: role})
+		return auth.WithCampaignDMs(ctx, func(ctx context.Context, campaignID string) (string, error) {
+			return map[string]string{"campaign1": "dm1", "campaign2": "dm2"}[campaignID], nil
+		})
+	}
+	call := func(ctx context.Context, name, campaignID string) mcp.ToolResponse {
+		return r.Call(ctx, mcp.ToolRequest{ToolName: name, Arguments: json.RawMessage(`{"campaign_id":"` + campaignID + `"}`)})
+	}
+
+	assert.False(t, call(as(auth.RoleDM, "dm1"), "start_combat", "campaign1").IsError)
+
+	resp := call(as(auth.RoleDM, "dm1"), "start_combat", "campaign2")
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "forbidden: dm1 does not run campaign campaign2")
+
+	// Read tools stay open; the services redact what other campaigns' DMs see
+	assert.False(t, call(as(auth.RoleDM, "dm1"), "get_combat_state", "campaign2").IsError)
+	// System services run every campaign
+	assert.False(t, call(as(auth.RoleSystem, "client"), "start_combat", "campaign2").IsError)
+	// Calls that refer to no campaign are not limited
+	assert.False(t, r.Call(as(auth.RoleDM, "dm1"), mcp.ToolRequest{ToolName: "create_campaign", Arguments: json.RawMessage(`{}`)}).IsError)
+}
+
+func TestRegistry_Middleware(t *testing.T) {
+	r := mcp.NewRegistry()
+	r.MustRegister(mcp.NewTool("attack", "attack", mcp.NewObjectSchema(map[string]mcp.Property{
+		"target": mcp.StringProp("target"),
+	}, mcp.Required("target"))), func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		return mcp.NewTextResponse("hit")
+	})
+
+	var calls []string
+	for _, name := range []string{"outer", "inner"} {
+		name := name
+		r.Use(func(ctx context.Context, req mcp.ToolRequest, next mcp.ToolHandler) mcp.ToolResponse {
+			calls = append(calls, name+":"+req.ToolName)
+			resp := next(ctx, req)
+			calls = append(calls, name+":"+resp.Content[0].Text)
+			return resp
+		})
+	}
+
+	resp := r.Call(context.Background(), mcp.ToolRequest{ToolName: "attack", Arguments: json.RawMessage(`{"target":"orc"}`)})
+	assert.False(t, resp.IsError)
+	assert.Equal(t, []string{"outer:attack", "inner:attack", "inner:hit", "outer:hit"}, calls)
+
+	// Calls rejected by validation never reach the middleware
+	calls = nil
+	resp = r.Call(context.Background(), mcp.ToolRequest{ToolName: "attack", Arguments: json.RawMessage(`{}`)})
+	assert.True(t, resp.IsError)
+	assert.Empty(t, calls)
+}
@@ -0,0 +1,65 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoResource returns the URI variables as the resource text
+func echoResource(ctx context.Context, uri string, params map[string]string) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{{URI: uri, Text: params["id"] + "/" + params["cid"]}}, nil
+}
+
+func TestResourceRegistry_Read(t *testing.T) {
+	r := mcp.NewResourceRegistry()
+	require.NoError(t, r.RegisterTemplate(mcp.ResourceTemplate{URITemplate: "dnd://campaign/{id}", Name: "campaign"}, echoResource, nil))
+	require.NoError(t, r.RegisterTemplate(mcp.ResourceTemplate{URITemplate: "dnd://campaign/{id}/character/{cid}", Name: "character"}, echoResource, nil))
+
+	contents, err := r.Read(context.Background(), "dnd://campaign/c1/character/hero")
+	require.NoError(t, err)
+	assert.Equal(t, "c1/hero", contents[0].Text)
+
+	contents, err = r.Read(context.Background(), "dnd://campaign/c1")
+	require.NoError(t, err)
+	assert.Equal(t, "c1/", contents[0].Text)
+
+	_, err = r.Read(context.Background(), "dnd://campaign/c1/unknown")
+	assert.True(t, errors.Is(err, mcp.ErrResourceNotFound))
+}
+
+func TestResourceRegistry_Register(t *testing.T) {
+	r := mcp.NewResourceRegistry()
+	template := mcp.ResourceTemplate{URITemplate: "dnd://combat/{id}/log", Name: "combat_log"}
+
+	require.NoError(t, r.RegisterTemplate(template, echoResource, nil))
+	assert.Error(t, r.RegisterTemplate(template, echoResource, nil))
+	assert.Error(t, r.RegisterTemplate(mcp.ResourceTemplate{Name: "empty"}, echoResource, nil))
+	assert.Error(t, r.RegisterTemplate(mcp.ResourceTemplate{URITemplate: "dnd://x"}, nil, nil))
+	assert.Equal(t, 1, r.Count())
+	assert.Equal(t, []mcp.ResourceTemplate{template}, r.Templates())
+}
+
+func TestResourceRegistry_List(t *testing.T) {
+	r := mcp.NewResourceRegistry()
+	r.MustRegisterTemplate(mcp.ResourceTemplate{URITemplate: "dnd://b/{id}", Name: "b"}, echoResource,
+		func(ctx context.Context) ([]mcp.Resource, error) {
+			return []mcp.Resource{{URI: "dnd://b/2", Name: "two"}, {URI: "dnd://b/1", Name: "one"}}, nil
+		})
+	r.MustRegisterTemplate(mcp.ResourceTemplate{URITemplate: "dnd://a/{id}", Name: "a"}, echoResource, nil)
+
+	resources, err := r.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "dnd://b/1", resources[0].URI)
+	assert.Equal(t, "dnd://b/2", resources[1].URI)
+}
+
+func TestExpandURITemplate(t *testing.T) {
+	uri := mcp.ExpandURITemplate("dnd://campaign/{id}/map/{mid}", map[string]string{"id": "c1", "mid": "m9"})
+	assert.Equal(t, "dnd://campaign/c1/map/m9", uri)
+}
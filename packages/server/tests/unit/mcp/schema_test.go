@@ -0,0 +1,207 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSchema exercises nested objects, arrays, bounds, patterns and oneOf
+func testSchema() mcp.InputSchema {
+	return mcp.NewObjectSchema(
+		map[string]mcp.Property{
+			"name":  {Type: "string", Pattern: "^[a-z]+$"},
+			"level": mcp.IntRange("Level", 1, 20),
+			"mode":  mcp.PropWithEnum("Mode", "fast", "slow"),
+			"ids":   mcp.ArrayOf("IDs", mcp.StringProp("ID")),
+			"position": mcp.ObjectOf("Position", map[string]mcp.Property{
+				"x": mcp.NumberRange("X", 0, 1),
+				"y": mcp.NumberRange("Y", 0, 1),
+			}, mcp.Required("x", "y")),
+			"target": mcp.OneOf("Target",
+				mcp.StringProp("a character ID"),
+				mcp.ObjectOf("a grid square", map[string]mcp.Property{"x": mcp.IntProp("X"), "y": mcp.IntProp("Y")}, mcp.Required("x", "y")),
+			),
+		},
+		mcp.Required("name", "level"),
+	)
+}
+
+func validate(t *testing.T, args string) []mcp.FieldError {
+	t.Helper()
+	return mcp.ValidateArguments(testSchema(), json.RawMessage(args))
+}
+
+func TestValidateArguments_Valid(t *testing.T) {
+	assert.Empty(t, validate(t, `{"name":"bob","level":3}`))
+	assert.Empty(t, validate(t, `{"name":"bob","level":3.0,"mode":"fast","ids":["a","b"],"position":{"x":0.5,"y":0},"target":"goblin-1"}`))
+	assert.Empty(t, validate(t, `{"name":"bob","level":20,"target":{"x":3,"y":4}}`))
+	// Null optional properties and undeclared properties are ignored
+	assert.Empty(t, validate(t, `{"name":"bob","level":1,"mode":null,"extra":true}`))
+}
+
+func TestValidateArguments_ListsEveryError(t *testing.T) {
+	errs := validate(t, `{"name":"Bob","level":2.5,"mode":"medium","ids":["a",7],"position":{"x":2},"target":true}`)
+
+	fields := make(map[string]string, len(errs))
+	for _, fe := range errs {
+		fields[fe.Field] = fe.Message
+	}
+	assert.Len(t, errs, 7)
+	assert.Equal(t, "must match pattern ^[a-z]+$", fields["name"])
+	assert.Equal(t, "must be an integer, got number", fields["level"])
+	assert.Contains(t, fields["mode"], "must be one of: fast, slow")
+	assert.Equal(t, "must be a string, got integer", fields["ids[1]"])
+	assert.Equal(t, "is required", fields["position.y"])
+	assert.Equal(t, "must be between 0 and 1 (got 2)", fields["position.x"])
+	assert.Contains(t, fields["target"], "must match one of: a character ID; a grid square")
+}
+
+func TestValidateArguments_RequiredAndShape(t *testing.T) {
+	errs := validate(t, ``)
+	require.Len(t, errs, 2)
+	assert.Equal(t, mcp.FieldError{Field: "name", Message: "is required"}, errs[0])
+	assert.Equal(t, mcp.FieldError{Field: "level", Message: "is required"}, errs[1])
+
+	errs = validate(t, `{"name":null,"level":0}`)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "name", errs[0].Field)
+	assert.Equal(t, "must be between 1 and 20 (got 0)", errs[1].Message)
+
+	errs = validate(t, `[1,2]`)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "arguments must be a JSON object", errs[0].Message)
+
+	errs = validate(t, `{"name":`)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Message, "valid JSON")
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &mcp.ValidationError{Tool: "attack", Errors: []mcp.FieldError{
+		{Field: "combat_id", Message: "is required"},
+		{Field: "count", Message: "must be at least 0 (got -1)"},
+	}}
+	assert.Equal(t, "invalid arguments for tool \"attack\":\n- combat_id is required\n- count must be at least 0 (got -1)", err.Error())
+}
+
+func TestRegistry_Call_ValidatesArguments(t *testing.T) {
+	r := mcp.NewRegistry()
+	called := false
+	r.MustRegister(mcp.NewTool("level_up", "Level up", testSchema()), func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+		called = true
+		return mcp.NewTextResponse("ok")
+	})
+
+	resp := r.Call(context.Background(), mcp.ToolRequest{ToolName: "level_up", Arguments: json.RawMessage(`{"level":"high"}`)})
+	assert.True(t, resp.IsError)
+	assert.False(t, called, "handler must not run with invalid arguments")
+	assert.Contains(t, resp.Content[0].Text, "- name is required")
+	assert.Contains(t, resp.Content[0].Text, "- level must be an integer, got string")
+
+	resp = r.Call(context.Background(), mcp.ToolRequest{ToolName: "level_up", Arguments: json.RawMessage(`{"name":"bob","level":2}`)})
+	assert.False(t, resp.IsError)
+	assert.True(t, called)
+}
+
+// spellRequest is a request struct whose schema is derived by SchemaFor
+type spellRequest struct {
+	CasterID  string            `json:"caster_id" jsonschema:"required" description:"The caster"`
+	Level     int               `json:"level" jsonschema:"minimum=1,maximum=9"`
+	School    string            `json:"school,omitempty" jsonschema:"enum=evocation|illusion"`
+	TargetIDs []string          `json:"target_ids" jsonschema:"minItems=1"`
+	Center    *spellPoint       `json:"center"`
+	Power     float64           `json:"power"`
+	Extra     map[string]string `json:"extra"`
+	Ignored   string            `json:"-"`
+	spellBase
+}
+
+type spellBase struct {
+	Ritual bool `json:"ritual"`
+}
+
+type spellPoint struct {
+	X int `json:"x" jsonschema:"required"`
+	Y int `json:"y" jsonschema:"required"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	schema := mcp.SchemaFor[spellRequest]()
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"caster_id"}, schema.Required)
+	assert.Equal(t, "The caster", schema.Properties["caster_id"].Description)
+
+	level := schema.Properties["level"]
+	assert.Equal(t, "integer", level.Type)
+	assert.Equal(t, 1.0, *level.Minimum)
+	assert.Equal(t, 9.0, *level.Maximum)
+
+	assert.Equal(t, []string{"evocation", "illusion"}, schema.Properties["school"].Enum)
+	assert.Equal(t, "string", schema.Properties["target_ids"].Items.Type)
+	assert.Equal(t, 1, *schema.Properties["target_ids"].MinItems)
+	assert.Equal(t, []string{"x", "y"}, schema.Properties["center"].Required)
+	assert.Equal(t, "number", schema.Properties["power"].Type)
+	assert.Equal(t, "object", schema.Properties["extra"].Type)
+	assert.Equal(t, "boolean", schema.Properties["ritual"].Type, "embedded structs are flattened")
+	assert.NotContains(t, schema.Properties, "Ignored")
+	assert.NotContains(t, schema.Properties, "-")
+
+	errs := mcp.ValidateArguments(schema, json.RawMessage(`{"level":10,"target_ids":[],"center":{"x":1}}`))
+	assert.Len(t, errs, 4)
+}
+
+func TestSchemaFor_BadTagPanics(t *testing.T) {
+	type badRequest struct {
+		Level int `json:"level" jsonschema:"minimum=low"`
+	}
+	assert.Panics(t, func() { mcp.SchemaFor[badRequest]() })
+
+	type unknownOption struct {
+		Level int `json:"level" jsonschema:"multipleOf=2"`
+	}
+	assert.Panics(t, func() { mcp.SchemaFor[unknownOption]() })
+}
+
+func TestTypedTool(t *testing.T) {
+	r := mcp.NewRegistry()
+	var got *spellRequest
+	r.MustRegister(mcp.TypedTool("cast", "Cast a spell", func(ctx context.Context, input *spellRequest) mcp.ToolResponse {
+		got = input
+		return mcp.NewTextResponse("cast")
+	}))
+
+	info, ok := r.Get("cast")
+	require.True(t, ok)
+	assert.Equal(t, []string{"caster_id"}, info.Tool.InputSchema.Required)
+
+	resp := r.Call(context.Background(), mcp.ToolRequest{ToolName: "cast", Arguments: json.RawMessage(`{"level":3}`)})
+	assert.True(t, resp.IsError)
+	assert.Nil(t, got)
+
+	resp = r.Call(context.Background(), mcp.ToolRequest{ToolName: "cast", Arguments: json.RawMessage(`{"caster_id":"wizard","level":3,"target_ids":["orc"],"ritual":true}`)})
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	require.NotNil(t, got)
+	assert.Equal(t, "wizard", got.CasterID)
+	assert.Equal(t, 3, got.Level)
+	assert.Equal(t, []string{"orc"}, got.TargetIDs)
+	assert.True(t, got.Ritual)
+}
+
+func TestDecodeArguments(t *testing.T) {
+	input, err := mcp.DecodeArguments[spellRequest]("cast", nil)
+	require.NoError(t, err)
+	assert.Equal(t, &spellRequest{}, input)
+
+	_, err = mcp.DecodeArguments[spellRequest]("cast", json.RawMessage(`{"level":"three"}`))
+	var validationErr *mcp.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Errors, 1)
+	assert.Equal(t, "level", validationErr.Errors[0].Field)
+	assert.Equal(t, "must be an integer, got string", validationErr.Errors[0].Message)
+}
@@ -0,0 +1,66 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/mcp"
+	"github.com/dnd-mcp/server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ServeStdio(t *testing.T) {
+	server := mcp.NewServer(&config.Config{})
+	server.RegisterTool(mcp.NewTool("greet", "Greets", mcp.NewObjectSchema(nil, nil)),
+		func(ctx context.Context, req mcp.ToolRequest) mcp.ToolResponse {
+			return mcp.NewTextResponse("hello")
+		})
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"greet"}}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	}, "\n")
+
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.ServeStdio(context.Background(), strings.NewReader(input), writer)
+		writer.Close()
+	}()
+
+	var responses []map[string]interface{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &resp))
+		responses = append(responses, resp)
+	}
+	require.NoError(t, <-done)
+
+	require.Len(t, responses, 4)
+	assert.Equal(t, "2024-11-05", responses[0]["result"].(map[string]interface{})["protocolVersion"])
+	content := responses[1]["result"].(map[string]interface{})["content"].([]interface{})
+	assert.Equal(t, "hello", content[0].(map[string]interface{})["text"])
+	assert.Equal(t, float64(mcp.ErrCodeParse), responses[2]["error"].(map[string]interface{})["code"])
+	assert.Equal(t, float64(3), responses[3]["id"])
+}
+
+func TestServer_ServeStdio_Cancelled(t *testing.T) {
+	server := mcp.NewServer(&config.Config{})
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := server.ServeStdio(ctx, reader, io.Discard)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,38 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffJSON(t *testing.T) {
+	before := json.RawMessage(`{"name":"Hero","hp":{"current":10,"max":10},"conditions":["prone"],"updated_at":"2024-01-01"}`)
+	after := json.RawMessage(`{"name":"Hero","hp":{"current":4,"max":10},"conditions":[],"updated_at":"2024-01-02","level":2}`)
+
+	diffs := models.DiffJSON(before, after)
+	require.Len(t, diffs, 3)
+	assert.Equal(t, models.FieldDiff{Path: "conditions", Before: []interface{}{"prone"}, After: []interface{}{}}, diffs[0])
+	assert.Equal(t, models.FieldDiff{Path: "hp.current", Before: float64(10), After: float64(4)}, diffs[1])
+	assert.Equal(t, models.FieldDiff{Path: "level", After: float64(2)}, diffs[2])
+}
+
+func TestSameJSON(t *testing.T) {
+	assert.True(t, models.SameJSON(json.RawMessage(`{"a":1,"updated_at":"x"}`), json.RawMessage(`{"updated_at":"y","a":1}`)))
+	assert.False(t, models.SameJSON(json.RawMessage(`{"a":1}`), json.RawMessage(`{"a":2}`)))
+	assert.False(t, models.SameJSON(json.RawMessage(`{"a":1}`), nil))
+	assert.True(t, models.SameJSON(nil, json.RawMessage(`null`)))
+}
+
+func TestAuditChange_CreatedDeleted(t *testing.T) {
+	created := models.AuditChange{Entity: "character", EntityID: "hero", After: json.RawMessage(`{}`)}
+	assert.True(t, created.Created())
+	assert.False(t, created.Deleted())
+
+	deleted := models.AuditChange{Entity: "character", EntityID: "hero", Before: json.RawMessage(`{}`), After: json.RawMessage(`null`)}
+	assert.True(t, deleted.Deleted())
+	assert.False(t, deleted.Created())
+}
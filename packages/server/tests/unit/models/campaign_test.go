@@ -94,6 +94,29 @@ func TestCampaignSettings_Validate(t *testing.T) {
 			wantErr:  true,
 			errField: "context_window",
 		},
+		{
+			name: "valid prompt override",
+			settings: &models.CampaignSettings{
+				MaxPlayers:    4,
+				StartLevel:    1,
+				Ruleset:       "dnd5e",
+				ContextWindow: 20,
+				Prompts:       map[string]string{"session_recap": "Recap {{.Campaign.Name}}"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unparsable prompt override",
+			settings: &models.CampaignSettings{
+				MaxPlayers:    4,
+				StartLevel:    1,
+				Ruleset:       "dnd5e",
+				ContextWindow: 20,
+				Prompts:       map[string]string{"session_recap": "Recap {{.Campaign.Name"},
+			},
+			wantErr:  true,
+			errField: "prompts.session_recap",
+		},
 	}
 
 	for _, tt := range tests {
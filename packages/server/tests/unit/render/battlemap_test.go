@@ -0,0 +1,66 @@
+package render_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapPNG_Decodes(t *testing.T) {
+	m := models.NewBattleMap("campaign1", "Crypt", 10, 8, 5)
+
+	data, err := render.MapPNG(m)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 10*32+1, img.Bounds().Dx())
+	assert.Equal(t, 8*32+1, img.Bounds().Dy())
+}
+
+func TestMapImage_TokenColoredByDisposition(t *testing.T) {
+	m := models.NewBattleMap("campaign1", "Crypt", 10, 10, 5)
+	token := models.NewToken("orc", 2, 3, models.TokenSizeMedium)
+	token.Disposition = models.DispositionHostile
+	m.Tokens = append(m.Tokens, *token)
+
+	img, err := render.MapImage(m)
+	require.NoError(t, err)
+
+	// Center of cell (2, 3)
+	assert.Equal(t, color.RGBA{R: 200, G: 40, B: 40, A: 255}, img.RGBAAt(2*32+16, 3*32+16))
+	// An empty cell keeps the floor color
+	assert.Equal(t, color.RGBA{R: 245, G: 240, B: 225, A: 255}, img.RGBAAt(7*32+16, 7*32+16))
+}
+
+func TestMapImage_Walls(t *testing.T) {
+	m := models.NewBattleMap("campaign1", "Crypt", 4, 4, 5)
+	m.Walls = models.Walls{models.NewWall("w1", models.WallTypeWall, 2, 0, 2, 4, 1, 1)}
+
+	img, err := render.MapImage(m)
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 40, G: 40, B: 40, A: 255}, img.RGBAAt(2*32, 16))
+}
+
+func TestMapImage_LargeMapIsCapped(t *testing.T) {
+	m := models.NewWorldMap("campaign1", "Realm", 1000, 200)
+
+	img, err := render.MapImage(m)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, img.Bounds().Dx(), 4*1000+1)
+	assert.Equal(t, 4*200+1, img.Bounds().Dy())
+}
+
+func TestMapImage_NoGrid(t *testing.T) {
+	m := models.NewBattleMap("campaign1", "Scene", 4, 4, 5)
+	m.Grid = nil
+
+	_, err := render.MapImage(m)
+	assert.ErrorIs(t, err, render.ErrNoGrid)
+}
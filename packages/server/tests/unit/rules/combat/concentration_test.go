@@ -0,0 +1,56 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcentrationSaveDC(t *testing.T) {
+	assert.Equal(t, 10, combat.ConcentrationSaveDC(1))
+	assert.Equal(t, 10, combat.ConcentrationSaveDC(21))
+	assert.Equal(t, 11, combat.ConcentrationSaveDC(22))
+	assert.Equal(t, 25, combat.ConcentrationSaveDC(50))
+}
+
+func TestParseDurationRounds(t *testing.T) {
+	tests := []struct {
+		duration string
+		expected int
+	}{
+		{"Concentration, up to 1 minute", 10},
+		{"Concentration, up to 10 minutes", 100},
+		{"Concentration, up to 1 hour", 600},
+		{"1 round", 1},
+		{"Instantaneous", 0},
+		{"Until dispelled", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			assert.Equal(t, tt.expected, combat.ParseDurationRounds(tt.duration))
+		})
+	}
+}
+
+func TestLosesConcentration(t *testing.T) {
+	character := newTarget(nil)
+
+	lost, _ := combat.LosesConcentration(character, nil)
+	assert.False(t, lost)
+
+	lost, reason := combat.LosesConcentration(character, conditions(models.ConditionStunned))
+	assert.True(t, lost)
+	assert.Contains(t, reason, "stunned")
+
+	lost, _ = combat.LosesConcentration(character, conditions(models.ConditionPoisoned))
+	assert.False(t, lost)
+
+	character.HP.Current = 0
+	lost, reason = combat.LosesConcentration(character, nil)
+	assert.True(t, lost)
+	assert.Equal(t, "dropped to 0 hit points", reason)
+}
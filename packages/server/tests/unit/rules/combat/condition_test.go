@@ -0,0 +1,129 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func conditions(types ...string) []models.Condition {
+	result := make([]models.Condition, len(types))
+	for i, t := range types {
+		result[i] = models.Condition{Type: t, Duration: -1, Source: t}
+	}
+	return result
+}
+
+func TestAddAttackConditionModifiers(t *testing.T) {
+	tests := []struct {
+		name         string
+		attacker     []models.Condition
+		target       []models.Condition
+		distance     int
+		ranged       bool
+		advantage    bool
+		disadvantage bool
+		autoCrit     bool
+	}{
+		{"no conditions", nil, nil, 5, false, false, false, false},
+		{"blinded attacker", conditions(models.ConditionBlinded), nil, 5, false, false, true, false},
+		{"invisible attacker", conditions(models.ConditionInvisible), nil, 5, false, true, false, false},
+		{"restrained target", nil, conditions(models.ConditionRestrained), 30, true, true, false, false},
+		{"invisible target", nil, conditions(models.ConditionInvisible), 5, false, false, true, false},
+		{"prone target adjacent", nil, conditions(models.ConditionProne), 5, false, true, false, false},
+		{"prone target at range", nil, conditions(models.ConditionProne), 30, true, false, true, false},
+		{"prone target unknown distance melee", nil, conditions(models.ConditionProne), movement.DistanceUnknown, false, true, false, false},
+		{"paralyzed target adjacent", nil, conditions(models.ConditionParalyzed), 5, false, true, false, true},
+		{"paralyzed target at range", nil, conditions(models.ConditionParalyzed), 30, true, true, false, false},
+		{"poisoned attacker vs stunned target", conditions(models.ConditionPoisoned), conditions(models.ConditionStunned), 5, false, true, true, false},
+		{"exhaustion level 3", []models.Condition{{Type: models.ConditionExhaustion, Source: "march (Level 3)"}}, nil, 5, false, false, true, false},
+		{"exhaustion level 2", []models.Condition{{Type: models.ConditionExhaustion, Source: "march (Level 2)"}}, nil, 5, false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := combat.NewRollModifiers(false, false)
+			combat.AddAttackConditionModifiers(m, tt.attacker, tt.target, tt.distance, tt.ranged)
+
+			assert.Equal(t, tt.advantage, m.Advantage)
+			assert.Equal(t, tt.disadvantage, m.Disadvantage)
+			assert.Equal(t, tt.autoCrit, m.AutoCrit)
+			if tt.advantage || tt.disadvantage {
+				assert.NotEmpty(t, m.Reasons)
+			}
+		})
+	}
+}
+
+func TestAddSaveConditionModifiers(t *testing.T) {
+	m := combat.NewRollModifiers(false, false)
+	combat.AddSaveConditionModifiers(m, conditions(models.ConditionParalyzed), "Dexterity")
+	assert.True(t, m.AutoFail)
+
+	m = combat.NewRollModifiers(false, false)
+	combat.AddSaveConditionModifiers(m, conditions(models.ConditionStunned), "wisdom")
+	assert.False(t, m.AutoFail)
+	assert.False(t, m.Disadvantage)
+
+	m = combat.NewRollModifiers(false, false)
+	combat.AddSaveConditionModifiers(m, conditions(models.ConditionRestrained), "dexterity")
+	assert.False(t, m.AutoFail)
+	assert.True(t, m.Disadvantage)
+
+	m = combat.NewRollModifiers(false, false)
+	combat.AddSaveConditionModifiers(m, []models.Condition{{Type: models.ConditionExhaustion, Source: "exhaustion (Level 4)"}}, "wisdom")
+	assert.True(t, m.Disadvantage)
+}
+
+func TestCollectConditions(t *testing.T) {
+	character := &models.Character{
+		Conditions: []models.Condition{
+			{Type: models.ConditionPoisoned, Source: "poison"},
+			{Type: models.ConditionExhaustion, Source: "march (Level 1)"},
+		},
+	}
+	extra := []models.Condition{
+		{Type: models.ConditionPoisoned, Source: "spell"},
+		{Type: models.ConditionExhaustion, Source: "spell (Level 3)"},
+		{Type: models.ConditionProne, Source: "shove"},
+	}
+
+	merged := combat.CollectConditions(character, extra)
+
+	require.Len(t, merged, 3)
+	assert.Equal(t, "spell (Level 3)", merged[1].Source)
+	assert.Equal(t, models.ConditionProne, merged[2].Type)
+}
+
+func TestResolveAttack_ConditionsAutoCrit(t *testing.T) {
+	attacker := &models.Character{ID: "attacker", Level: 1, Abilities: &models.Abilities{Strength: 10}}
+	target := newTarget(nil)
+	target.Conditions = conditions(models.ConditionUnconscious)
+	weapon := &models.EquipmentItem{Name: "Dagger", Damage: "1d4", DamageType: "piercing"}
+	// Advantage: 5 and 12 keep 12 (+2 vs AC 10 hits), 1d4 doubled on crit: 2 + 3
+	roller := dice.NewRollerWithSource(&mockRandomSource{values: []int{4, 11, 1, 2}})
+
+	result := combat.ResolveAttack(attacker, target, weapon, false, false, roller)
+
+	require.True(t, result.Hit)
+	assert.True(t, result.Crit)
+	assert.True(t, result.Modifiers.Advantage)
+	assert.Equal(t, 5, result.Damage)
+}
+
+func TestRollSavingThrow_AutoFail(t *testing.T) {
+	character := newTarget(nil)
+	character.Abilities = &models.Abilities{Dexterity: 10}
+	character.Conditions = conditions(models.ConditionStunned)
+	roller := dice.NewRollerWithSource(&mockRandomSource{values: []int{19}})
+
+	result := combat.RollSavingThrow(character, "dexterity", 10, false, false, roller)
+
+	assert.Equal(t, 20, result.DiceResult.Total)
+	assert.False(t, result.IsSuccess())
+}
@@ -0,0 +1,126 @@
+package combat_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/stretchr/testify/assert"
+)
+
+// coverMap creates a 10x10 battle map with an attacker, a target and any number of bystanders
+func coverMap(attacker, target models.Position, walls models.Walls, bystanders ...models.Position) (*models.Map, *models.Token, *models.Token) {
+	m := models.NewBattleMap("campaign-1", "Cover Test", 10, 10, 5)
+	m.Tokens = []models.Token{
+		*models.NewToken("attacker", attacker.X, attacker.Y, models.TokenSizeMedium),
+		*models.NewToken("target", target.X, target.Y, models.TokenSizeMedium),
+	}
+	for i, pos := range bystanders {
+		m.Tokens = append(m.Tokens, *models.NewToken(fmt.Sprintf("bystander-%d", i), pos.X, pos.Y, models.TokenSizeMedium))
+	}
+	m.Walls = walls
+	return m, &m.Tokens[0], &m.Tokens[1]
+}
+
+func TestCalculateCover(t *testing.T) {
+	closedDoor := func(state models.WallDoorState) models.Walls {
+		door := models.NewWall("door", models.WallTypeDoor, 2, 0, 2, 10, 0, 0)
+		door.Door = &models.WallDoor{State: state}
+		return models.Walls{door}
+	}
+
+	tests := []struct {
+		name       string
+		attacker   models.Position
+		target     models.Position
+		walls      models.Walls
+		bystanders []models.Position
+		expected   combat.CoverLevel
+	}{
+		{
+			name:     "open field",
+			attacker: models.Position{X: 0, Y: 0},
+			target:   models.Position{X: 4, Y: 0},
+			expected: combat.CoverNone,
+		},
+		{
+			name:       "creature in between",
+			attacker:   models.Position{X: 0, Y: 0},
+			target:     models.Position{X: 2, Y: 0},
+			bystanders: []models.Position{{X: 1, Y: 0}},
+			expected:   combat.CoverHalf,
+		},
+		{
+			name:     "wall covering part of the target",
+			attacker: models.Position{X: 0, Y: 0},
+			target:   models.Position{X: 4, Y: 2},
+			walls:    models.Walls{models.NewWall("low", models.WallTypeWall, 4, 2, 4, 3, 0, 0)},
+			expected: combat.CoverThreeQuarters,
+		},
+		{
+			name:     "wall between",
+			attacker: models.Position{X: 0, Y: 0},
+			target:   models.Position{X: 4, Y: 0},
+			walls:    models.Walls{models.NewWall("wall", models.WallTypeWall, 2, 0, 2, 10, 0, 0)},
+			expected: combat.CoverTotal,
+		},
+		{
+			name:     "line running along a wall",
+			attacker: models.Position{X: 0, Y: 0},
+			target:   models.Position{X: 4, Y: 0},
+			walls:    models.Walls{models.NewWall("wall", models.WallTypeWall, 0, 1, 10, 1, 0, 0)},
+			expected: combat.CoverNone,
+		},
+		{
+			name:     "terrain wall",
+			attacker: models.Position{X: 0, Y: 0},
+			target:   models.Position{X: 4, Y: 0},
+			walls:    models.Walls{models.NewWall("rubble", models.WallTypeTerrain, 2, 0, 2, 10, 1, 1)},
+			expected: combat.CoverNone,
+		},
+		{
+			name:     "closed door",
+			attacker: models.Position{X: 0, Y: 0},
+			target:   models.Position{X: 4, Y: 0},
+			walls:    closedDoor(models.DoorStateClosed),
+			expected: combat.CoverTotal,
+		},
+		{
+			name:     "open door",
+			attacker: models.Position{X: 0, Y: 0},
+			target:   models.Position{X: 4, Y: 0},
+			walls:    closedDoor(models.DoorStateOpen),
+			expected: combat.CoverNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, attacker, target := coverMap(tt.attacker, tt.target, tt.walls, tt.bystanders...)
+			assert.Equal(t, tt.expected, combat.CalculateCover(m, attacker, target))
+		})
+	}
+}
+
+func TestCoverLevel_Bonus(t *testing.T) {
+	assert.Equal(t, 0, combat.CoverNone.Bonus())
+	assert.Equal(t, 2, combat.CoverHalf.Bonus())
+	assert.Equal(t, 5, combat.CoverThreeQuarters.Bonus())
+	assert.True(t, combat.CoverTotal.IsTotal())
+}
+
+func TestResolveAttackWithCover(t *testing.T) {
+	attacker := &models.Character{ID: "attacker", Level: 1, Abilities: &models.Abilities{Strength: 10}}
+	target := newTarget(nil)
+	// Attack roll 11 + 2 = 13 would hit AC 10, but not AC 10 + 5
+	roller := dice.NewRollerWithSource(&mockRandomSource{values: []int{10}})
+
+	result := combat.ResolveAttackWithCover(attacker, target, nil, combat.NewRollModifiers(false, false), combat.CoverThreeQuarters, roller)
+
+	assert.Equal(t, 15, result.TargetAC)
+	assert.Equal(t, 5, result.CoverBonus)
+	assert.Equal(t, combat.CoverThreeQuarters, result.Cover)
+	assert.False(t, result.Hit)
+}
@@ -0,0 +1,130 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRandomSource returns predetermined values (modulo n)
+type mockRandomSource struct {
+	values []int
+	index  int
+}
+
+func (m *mockRandomSource) Intn(n int) int {
+	if m.index >= len(m.values) {
+		return 0
+	}
+	val := m.values[m.index] % n
+	m.index++
+	return val
+}
+
+func newTarget(traits *models.Traits) *models.Character {
+	return &models.Character{
+		ID:     "target",
+		Name:   "Target",
+		Level:  1,
+		AC:     10,
+		HP:     models.NewHP(50),
+		Traits: traits,
+	}
+}
+
+func TestAdjustDamage(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int
+		modifier combat.DamageModifier
+		expected int
+	}{
+		{"none", 7, combat.DamageModifierNone, 7},
+		{"immune", 7, combat.DamageModifierImmune, 0},
+		{"resistant rounds down", 7, combat.DamageModifierResistant, 3},
+		{"vulnerable", 7, combat.DamageModifierVulnerable, 14},
+		{"resistant then vulnerable", 7, combat.DamageModifierResistantVulnerable, 6},
+		{"negative amount", -3, combat.DamageModifierVulnerable, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, combat.AdjustDamage(tt.amount, tt.modifier))
+		})
+	}
+}
+
+func TestGetDamageModifier(t *testing.T) {
+	traits := models.NewTraits()
+	traits.AddResistance("Piercing")
+	traits.AddImmunity("poison")
+	traits.AddResistance("poison")
+	traits.AddVulnerability("bludgeoning")
+	target := newTarget(traits)
+
+	assert.Equal(t, combat.DamageModifierResistant, combat.GetDamageModifier(target, "piercing"))
+	assert.Equal(t, combat.DamageModifierImmune, combat.GetDamageModifier(target, "poison"))
+	assert.Equal(t, combat.DamageModifierVulnerable, combat.GetDamageModifier(target, "bludgeoning"))
+	assert.Equal(t, combat.DamageModifierNone, combat.GetDamageModifier(target, "fire"))
+	assert.Equal(t, combat.DamageModifierNone, combat.GetDamageModifier(newTarget(nil), "fire"))
+}
+
+func TestApplyDamageModifiers_MultipleTypes(t *testing.T) {
+	traits := models.NewTraits()
+	traits.AddResistance("slashing")
+	traits.AddImmunity("fire")
+	target := newTarget(traits)
+
+	result := combat.ApplyDamageModifiers(target, []combat.DamageInstance{
+		{DamageType: "slashing", Amount: 5},
+		{DamageType: "fire", Amount: 4},
+		{DamageType: "slashing", Amount: 2},
+		{DamageType: "cold", Amount: 3},
+	})
+
+	assert.Equal(t, 14, result.Before)
+	// slashing 7 -> 3, fire 4 -> 0, cold 3 -> 3
+	assert.Equal(t, 6, result.After)
+	require.Len(t, result.Breakdown, 3)
+	assert.Equal(t, "slashing", result.Breakdown[0].DamageType)
+	assert.Equal(t, 7, result.Breakdown[0].Before)
+	assert.Equal(t, 3, result.Breakdown[0].After)
+	assert.Equal(t, combat.DamageModifierImmune, result.Breakdown[1].Modifier)
+	assert.Equal(t, 0, result.Breakdown[1].After)
+}
+
+func TestResolveAttack_ExtraDamageAndResistance(t *testing.T) {
+	attacker := &models.Character{
+		ID:        "attacker",
+		Level:     1,
+		Abilities: &models.Abilities{Strength: 10},
+	}
+	traits := models.NewTraits()
+	traits.AddResistance("slashing")
+	target := newTarget(traits)
+	weapon := &models.EquipmentItem{
+		ID:          "flame_tongue",
+		Name:        "Flame Tongue",
+		Type:        models.EquipmentTypeWeapon,
+		Damage:      "1d8",
+		DamageType:  "slashing",
+		ExtraDamage: []models.DamagePart{{Damage: "1d6", DamageType: "fire"}},
+	}
+	// Attack roll 15, slashing 1d8 = 7, fire 1d6 = 4
+	roller := dice.NewRollerWithSource(&mockRandomSource{values: []int{14, 6, 3}})
+
+	result := combat.ResolveAttack(attacker, target, weapon, false, false, roller)
+
+	require.True(t, result.Hit)
+	require.Len(t, result.ExtraDamageRolls, 1)
+	assert.Equal(t, 11, result.RawDamage)
+	// slashing halved to 3, fire 4
+	assert.Equal(t, 7, result.Damage)
+	assert.Equal(t, 43, result.TargetHP.Current)
+	require.Len(t, result.DamageBreakdown, 2)
+	assert.Equal(t, combat.DamageModifierResistant, result.DamageBreakdown[0].Modifier)
+}
@@ -0,0 +1,70 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAttacksPerAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		features []*models.Feature
+		expected int
+	}{
+		{"no features", nil, 1},
+		{"unrelated feature", []*models.Feature{{Name: "Second Wind"}}, 1},
+		{"extra attack", []*models.Feature{{Name: "Extra Attack"}}, 2},
+		{"extra attack (2)", []*models.Feature{{Name: "Extra Attack"}, {Name: "Extra Attack (2)"}}, 3},
+		{"extra attack (3)", []*models.Feature{{Name: "extra attack (3)"}}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			character := &models.Character{Features: tt.features}
+			assert.Equal(t, tt.expected, combat.GetAttacksPerAction(character))
+		})
+	}
+}
+
+func TestBuildMultiattack(t *testing.T) {
+	owlbear := &models.Character{
+		Name: "Owlbear",
+		NaturalAttacks: []*models.EquipmentItem{
+			{ID: "beak", Name: "Beak", Damage: "1d10+5", DamageType: "piercing"},
+			{ID: "claws", Name: "Claws", Damage: "2d8+5", DamageType: "slashing"},
+		},
+		Multiattack: &models.Multiattack{
+			Attacks: []models.MultiattackEntry{
+				{AttackID: "beak", Count: 1},
+				{AttackID: "Claws", Count: 1},
+				{AttackID: "tail", Count: 1},
+			},
+		},
+	}
+
+	sequence := combat.BuildMultiattack(owlbear)
+
+	require.Len(t, sequence, 2)
+	assert.Equal(t, "beak", sequence[0].ID)
+	assert.Equal(t, "claws", sequence[1].ID)
+	assert.Nil(t, combat.BuildMultiattack(&models.Character{}))
+}
+
+func TestFindAttack(t *testing.T) {
+	dagger := &models.EquipmentItem{ID: "dagger1", Name: "Dagger", Properties: []string{"finesse", "light"}}
+	shortsword := &models.EquipmentItem{ID: "shortsword1", Name: "Shortsword", Properties: []string{"Light"}}
+	character := &models.Character{
+		EquipmentSlots: &models.EquipmentSlots{MainHand: shortsword, OffHand: dagger},
+	}
+
+	assert.Equal(t, dagger, combat.FindAttack(character, "dagger1"))
+	assert.Equal(t, shortsword, combat.FindAttack(character, "shortsword"))
+	assert.Nil(t, combat.FindAttack(character, "greataxe"))
+	assert.True(t, combat.IsLightWeapon(dagger))
+	assert.True(t, combat.IsLightWeapon(shortsword))
+	assert.False(t, combat.IsLightWeapon(&models.EquipmentItem{Name: "Longsword"}))
+}
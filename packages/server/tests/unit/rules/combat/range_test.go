@@ -0,0 +1,132 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWeaponRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		weapon   *models.EquipmentItem
+		expected combat.WeaponRange
+	}{
+		{
+			name:     "unarmed",
+			weapon:   nil,
+			expected: combat.WeaponRange{Reach: 5},
+		},
+		{
+			name:     "longsword",
+			weapon:   &models.EquipmentItem{Name: "Longsword", Damage: "1d8", Properties: []string{"versatile"}},
+			expected: combat.WeaponRange{Reach: 5},
+		},
+		{
+			name:     "glaive has reach",
+			weapon:   &models.EquipmentItem{Name: "Glaive", Damage: "1d10", Properties: []string{"Heavy", "Reach", "Two-Handed"}},
+			expected: combat.WeaponRange{Reach: 10},
+		},
+		{
+			name:     "longbow",
+			weapon:   &models.EquipmentItem{Name: "Longbow", Damage: "1d8", Range: "150/600", Properties: []string{"ammunition"}},
+			expected: combat.WeaponRange{Reach: 5, Normal: 150, Long: 600, Ranged: true},
+		},
+		{
+			name:     "thrown handaxe",
+			weapon:   &models.EquipmentItem{Name: "Handaxe", Damage: "1d6", Range: "20/60", Properties: []string{"light", "thrown"}},
+			expected: combat.WeaponRange{Reach: 5, Normal: 20, Long: 60, Thrown: true},
+		},
+		{
+			name:     "monster reach",
+			weapon:   &models.EquipmentItem{Name: "Tentacle", Damage: "2d6", Range: "reach 15 ft."},
+			expected: combat.WeaponRange{Reach: 15},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, combat.GetWeaponRange(tt.weapon))
+		})
+	}
+}
+
+func TestCheckAttackRange(t *testing.T) {
+	longsword := &models.EquipmentItem{Name: "Longsword", Damage: "1d8"}
+	longbow := &models.EquipmentItem{Name: "Longbow", Damage: "1d8", Range: "150/600", Properties: []string{"ammunition"}}
+	handaxe := &models.EquipmentItem{Name: "Handaxe", Damage: "1d6", Range: "20/60", Properties: []string{"thrown"}}
+
+	t.Run("melee within reach", func(t *testing.T) {
+		check := combat.CheckAttackRange(longsword, 5)
+		assert.True(t, check.InRange)
+		assert.False(t, check.Ranged)
+	})
+
+	t.Run("melee beyond reach", func(t *testing.T) {
+		check := combat.CheckAttackRange(longsword, 10)
+		assert.False(t, check.InRange)
+		assert.Contains(t, check.Reason, "beyond 5 feet reach")
+	})
+
+	t.Run("unknown distance", func(t *testing.T) {
+		assert.True(t, combat.CheckAttackRange(longsword, movement.DistanceUnknown).InRange)
+	})
+
+	t.Run("ranged long range", func(t *testing.T) {
+		check := combat.CheckAttackRange(longbow, 200)
+		assert.True(t, check.InRange)
+		assert.True(t, check.LongRange)
+	})
+
+	t.Run("ranged beyond long range", func(t *testing.T) {
+		check := combat.CheckAttackRange(longbow, 605)
+		assert.False(t, check.InRange)
+		assert.Contains(t, check.Reason, "beyond 600 feet long range")
+	})
+
+	t.Run("thrown weapon in melee", func(t *testing.T) {
+		check := combat.CheckAttackRange(handaxe, 5)
+		assert.True(t, check.InRange)
+		assert.False(t, check.Ranged)
+	})
+
+	t.Run("thrown weapon beyond reach", func(t *testing.T) {
+		check := combat.CheckAttackRange(handaxe, 30)
+		assert.True(t, check.InRange)
+		assert.True(t, check.Ranged)
+		assert.True(t, check.LongRange)
+	})
+}
+
+func TestAddRangeModifiers(t *testing.T) {
+	t.Run("ranged attack with adjacent hostile", func(t *testing.T) {
+		m := combat.NewRollModifiers(false, false)
+		combat.AddRangeModifiers(m, combat.RangeCheck{Distance: 30, InRange: true, Ranged: true}, true)
+		assert.True(t, m.Disadvantage)
+	})
+
+	t.Run("melee attack with adjacent hostile", func(t *testing.T) {
+		m := combat.NewRollModifiers(false, false)
+		combat.AddRangeModifiers(m, combat.RangeCheck{Distance: 5, InRange: true}, true)
+		assert.False(t, m.Disadvantage)
+	})
+}
+
+func TestGetMeleeReach(t *testing.T) {
+	assert.Equal(t, 5, combat.GetMeleeReach(nil))
+
+	character := &models.Character{
+		EquipmentSlots: &models.EquipmentSlots{
+			MainHand: &models.EquipmentItem{Name: "Longbow", Damage: "1d8", Range: "150/600", Properties: []string{"ammunition"}},
+		},
+		NaturalAttacks: []*models.EquipmentItem{
+			{Name: "Bite", Damage: "1d6"},
+			{Name: "Tail", Damage: "1d8", Properties: []string{"reach"}},
+		},
+	}
+	assert.Equal(t, 10, combat.GetMeleeReach(character))
+	assert.Equal(t, "Tail", combat.GetMeleeWeapon(character).Name)
+}
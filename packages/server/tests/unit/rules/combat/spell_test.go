@@ -0,0 +1,107 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaleSpellDice(t *testing.T) {
+	tests := []struct {
+		name           string
+		base           string
+		levelScale     []string
+		higherLevels   string
+		spellLevel     int
+		castLevel      int
+		characterLevel int
+		expected       []string
+	}{
+		{
+			name:       "cast at base level",
+			base:       "8d6",
+			spellLevel: 3,
+			castLevel:  3,
+			expected:   []string{"8d6"},
+		},
+		{
+			name:       "upcast with level scale",
+			base:       "8d6",
+			levelScale: []string{"1d6"},
+			spellLevel: 3,
+			castLevel:  5,
+			expected:   []string{"10d6"},
+		},
+		{
+			name:         "upcast parsed from higher levels text",
+			base:         "3d8",
+			higherLevels: "The damage increases by 1d8 for each slot level above 1st.",
+			spellLevel:   1,
+			castLevel:    3,
+			expected:     []string{"5d8"},
+		},
+		{
+			name:       "upcast with different dice",
+			base:       "2d8",
+			levelScale: []string{"1d6"},
+			spellLevel: 1,
+			castLevel:  2,
+			expected:   []string{"2d8", "1d6"},
+		},
+		{
+			name:       "upcast without scaling info",
+			base:       "1d4+1",
+			spellLevel: 1,
+			castLevel:  3,
+			expected:   []string{"1d4+1"},
+		},
+		{
+			name:           "cantrip at level 4",
+			base:           "1d10",
+			characterLevel: 4,
+			expected:       []string{"1d10"},
+		},
+		{
+			name:           "cantrip at level 11",
+			base:           "1d10",
+			characterLevel: 11,
+			expected:       []string{"3d10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := combat.ScaleSpellDice(tt.base, tt.levelScale, tt.higherLevels, tt.spellLevel, tt.castLevel, tt.characterLevel)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGetSpellSaveDCAndAttackBonus(t *testing.T) {
+	cleric := &models.Character{
+		Class:     "Cleric",
+		Level:     9,
+		Abilities: &models.Abilities{Wisdom: 18, Charisma: 10},
+	}
+	// 8 + 4 (proficiency) + 4 (WIS)
+	assert.Equal(t, 16, combat.GetSpellSaveDC(cleric))
+	assert.Equal(t, 8, combat.GetSpellAttackBonus(cleric))
+
+	// Spellbook ability overrides class default
+	cleric.Spellbook = &models.Spellbook{SpellcastingAbility: "charisma"}
+	assert.Equal(t, 12, combat.GetSpellSaveDC(cleric))
+}
+
+func TestGetSaveModifier(t *testing.T) {
+	character := &models.Character{
+		Level:     5,
+		Abilities: &models.Abilities{Dexterity: 14, Constitution: 12},
+		SavesDetail: map[string]*models.Save{
+			"dexterity": {Proficient: true},
+		},
+	}
+	assert.Equal(t, 5, combat.GetSaveModifier(character, "dexterity"))
+	assert.Equal(t, 1, combat.GetSaveModifier(character, "Constitution"))
+}
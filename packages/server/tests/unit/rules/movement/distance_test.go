@@ -0,0 +1,30 @@
+package movement_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        *models.Token
+		b        *models.Token
+		expected int
+	}{
+		{"adjacent", models.NewToken("a", 0, 0, models.TokenSizeMedium), models.NewToken("b", 1, 0, models.TokenSizeMedium), 5},
+		{"diagonal", models.NewToken("a", 0, 0, models.TokenSizeMedium), models.NewToken("b", 1, 1, models.TokenSizeMedium), 5},
+		{"far", models.NewToken("a", 0, 0, models.TokenSizeMedium), models.NewToken("b", 6, 2, models.TokenSizeMedium), 30},
+		{"large token edge", models.NewToken("a", 0, 0, models.TokenSizeLarge), models.NewToken("b", 3, 0, models.TokenSizeMedium), 10},
+		{"missing token", models.NewToken("a", 0, 0, models.TokenSizeMedium), nil, movement.DistanceUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, movement.TokenDistance(tt.a, tt.b, 5))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package movement_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/movement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPathMap(width, height int) *models.Map {
+	return models.NewBattleMap("campaign-1", "Path Test", width, height, 5)
+}
+
+func addPathToken(m *models.Map, id string, x, y int, size models.TokenSize, disposition models.TokenDisposition) *models.Token {
+	token := models.NewToken("char-"+id, x, y, size)
+	token.ID = id
+	token.Disposition = disposition
+	m.Tokens = append(m.Tokens, *token)
+	return &m.Tokens[len(m.Tokens)-1]
+}
+
+func TestFindPath_Straight(t *testing.T) {
+	m := newPathMap(10, 10)
+	token := addPathToken(m, "mover", 0, 0, models.TokenSizeMedium, models.DispositionFriendly)
+
+	result, err := movement.FindPath(m, token, 5, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 25, result.Cost)
+	assert.Len(t, result.Path, 6)
+	assert.Equal(t, models.Position{X: 5, Y: 0}, result.Path[5])
+}
+
+func TestFindPath_DiagonalsAlternate(t *testing.T) {
+	m := newPathMap(10, 10)
+	token := addPathToken(m, "mover", 0, 0, models.TokenSizeMedium, models.DispositionFriendly)
+
+	// 5 + 10 + 5 + 10
+	result, err := movement.FindPath(m, token, 4, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 30, result.Cost)
+	assert.Len(t, result.Path, 5)
+}
+
+func TestFindPath_RoutesAroundWall(t *testing.T) {
+	m := newPathMap(10, 10)
+	token := addPathToken(m, "mover", 0, 1, models.TokenSizeMedium, models.DispositionFriendly)
+	m.Walls = append(m.Walls, models.NewWall("wall-1", models.WallTypeWall, 2, 0, 2, 3, 0, 0))
+
+	result, err := movement.FindPath(m, token, 4, 1)
+	require.NoError(t, err)
+	assert.Greater(t, result.Cost, 20)
+
+	crossedBelow := false
+	for i := 1; i < len(result.Path); i++ {
+		prev, cur := result.Path[i-1], result.Path[i]
+		if prev.X < 2 && cur.X >= 2 {
+			crossedBelow = cur.Y >= 3 && prev.Y >= 3
+		}
+	}
+	assert.True(t, crossedBelow, "path should pass below the end of the wall: %v", result.Path)
+}
+
+func TestFindPath_Doors(t *testing.T) {
+	newRoom := func() (*models.Map, *models.Token, *models.Wall) {
+		m := newPathMap(10, 10)
+		token := addPathToken(m, "mover", 0, 4, models.TokenSizeMedium, models.DispositionFriendly)
+		door := models.NewWall("door-1", models.WallTypeDoor, 2, 4, 2, 5, 0, 1)
+		door.Door = &models.WallDoor{State: models.DoorStateClosed}
+		m.Walls = append(m.Walls,
+			models.NewWall("wall-top", models.WallTypeWall, 2, 0, 2, 4, 0, 0),
+			models.NewWall("wall-bottom", models.WallTypeWall, 2, 5, 2, 10, 0, 0),
+			door,
+		)
+		return m, token, door
+	}
+
+	t.Run("closed door blocks", func(t *testing.T) {
+		m, token, _ := newRoom()
+		_, err := movement.FindPath(m, token, 4, 4)
+		assert.ErrorIs(t, err, movement.ErrNoPath)
+	})
+
+	t.Run("locked door blocks", func(t *testing.T) {
+		m, token, door := newRoom()
+		door.Door.State = models.DoorStateLocked
+		_, err := movement.FindPath(m, token, 4, 4)
+		assert.ErrorIs(t, err, movement.ErrNoPath)
+	})
+
+	t.Run("open door allows movement", func(t *testing.T) {
+		m, token, door := newRoom()
+		require.NoError(t, door.Open())
+		result, err := movement.FindPath(m, token, 4, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 20, result.Cost)
+	})
+}
+
+func TestFindPath_DifficultTerrain(t *testing.T) {
+	m := newPathMap(5, 1)
+	token := addPathToken(m, "mover", 0, 0, models.TokenSizeMedium, models.DispositionFriendly)
+	m.Grid.SetCell(1, 0, models.CellTypeDifficult)
+	m.Grid.SetCell(2, 0, models.CellTypeDifficult)
+
+	result, err := movement.FindPath(m, token, 4, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 30, result.Cost) // 10 + 10 + 5 + 5
+	assert.Equal(t, 2, result.DifficultCount)
+}
+
+func TestFindPath_TerrainWall(t *testing.T) {
+	m := newPathMap(5, 1)
+	token := addPathToken(m, "mover", 0, 0, models.TokenSizeMedium, models.DispositionFriendly)
+	m.Walls = append(m.Walls, models.NewWall("terrain-1", models.WallTypeTerrain, 1, 0, 1, 1, 1, 2))
+
+	result, err := movement.FindPath(m, token, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 15, result.Cost) // crossing the terrain wall costs double
+	assert.Equal(t, 1, result.DifficultCount)
+}
+
+func TestFindPath_Creatures(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        models.TokenSize
+		disposition models.TokenDisposition
+		expectCost  int
+		expectError bool
+	}{
+		{"hostile creature blocks", models.TokenSizeMedium, models.DispositionHostile, 0, true},
+		{"ally space is difficult terrain", models.TokenSizeMedium, models.DispositionFriendly, 25, false},
+		{"neutral space is difficult terrain", models.TokenSizeMedium, models.DispositionNeutral, 25, false},
+		{"tiny hostile can be passed", models.TokenSizeTiny, models.DispositionHostile, 25, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newPathMap(5, 1)
+			token := addPathToken(m, "mover", 0, 0, models.TokenSizeMedium, models.DispositionFriendly)
+			addPathToken(m, "other", 2, 0, tt.size, tt.disposition)
+
+			result, err := movement.FindPath(m, token, 4, 0)
+			if tt.expectError {
+				assert.ErrorIs(t, err, movement.ErrNoPath)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectCost, result.Cost)
+		})
+	}
+}
+
+func TestFindPath_RoutesAroundHostile(t *testing.T) {
+	m := newPathMap(10, 10)
+	token := addPathToken(m, "mover", 0, 0, models.TokenSizeMedium, models.DispositionHostile)
+	addPathToken(m, "guard", 2, 0, models.TokenSizeMedium, models.DispositionFriendly)
+
+	result, err := movement.FindPath(m, token, 4, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 25, result.Cost) // sidestep with one diagonal at 5 and one at 10
+	for _, pos := range result.Path {
+		assert.NotEqual(t, models.Position{X: 2, Y: 0}, pos)
+	}
+}
+
+func TestFindPath_InvalidDestination(t *testing.T) {
+	m := newPathMap(10, 10)
+	token := addPathToken(m, "mover", 0, 0, models.TokenSizeLarge, models.DispositionFriendly)
+	m.Grid.SetCell(5, 5, models.CellTypeWall)
+
+	_, err := movement.FindPath(m, token, 9, 0)
+	assert.ErrorIs(t, err, movement.ErrInvalidDestination)
+
+	_, err = movement.FindPath(m, token, 4, 4)
+	assert.ErrorIs(t, err, movement.ErrInvalidDestination)
+}
+
+func TestPathResult_Truncate(t *testing.T) {
+	m := newPathMap(10, 1)
+	token := addPathToken(m, "mover", 0, 0, models.TokenSizeMedium, models.DispositionFriendly)
+
+	result, err := movement.FindPath(m, token, 6, 0)
+	require.NoError(t, err)
+
+	truncated := result.Truncate(20)
+	assert.Equal(t, 20, truncated.Cost)
+	assert.Equal(t, models.Position{X: 4, Y: 0}, truncated.Path[len(truncated.Path)-1])
+
+	// Cannot stop in another creature's space
+	addPathToken(m, "ally", 3, 0, models.TokenSizeMedium, models.DispositionFriendly)
+	result, err = movement.FindPath(m, token, 6, 0)
+	require.NoError(t, err)
+
+	truncated = result.Truncate(20)
+	assert.Equal(t, 10, truncated.Cost)
+	assert.Equal(t, models.Position{X: 2, Y: 0}, truncated.Path[len(truncated.Path)-1])
+
+	assert.Len(t, result.Truncate(0).Path, 1)
+}
@@ -0,0 +1,187 @@
+package vision_test
+
+import (
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/vision"
+	"github.com/stretchr/testify/assert"
+)
+
+func newVisionMap() *models.Map {
+	return models.NewBattleMap("campaign-1", "Vision Test", 10, 10, 5)
+}
+
+func newViewer(x, y int) *vision.Viewer {
+	return &vision.Viewer{Token: models.NewToken("viewer", x, y, models.TokenSizeMedium)}
+}
+
+func TestField_HasLineOfSight(t *testing.T) {
+	m := newVisionMap()
+	m.Walls = append(m.Walls, models.NewWall("wall-1", models.WallTypeWall, 3, 0, 3, 10, 0, 0))
+	field := vision.NewField(m)
+
+	assert.True(t, field.HasLineOfSight(0, 0, 2, 5))
+	assert.False(t, field.HasLineOfSight(0, 0, 5, 0))
+}
+
+func TestField_Doors(t *testing.T) {
+	newRoom := func() (*models.Map, *models.Wall) {
+		m := newVisionMap()
+		door := models.NewWall("door-1", models.WallTypeDoor, 3, 4, 3, 5, 0, 0)
+		door.Door = &models.WallDoor{State: models.DoorStateClosed}
+		m.Walls = models.Walls{
+			models.NewWall("wall-top", models.WallTypeWall, 3, 0, 3, 4, 0, 0),
+			models.NewWall("wall-bottom", models.WallTypeWall, 3, 5, 3, 10, 0, 0),
+			door,
+		}
+		return m, door
+	}
+
+	m, _ := newRoom()
+	assert.False(t, vision.NewField(m).HasLineOfSight(0, 4, 6, 4), "closed door blocks sight")
+
+	m, door := newRoom()
+	assert.NoError(t, door.Open())
+	assert.True(t, vision.NewField(m).HasLineOfSight(0, 4, 6, 4), "open door allows sight")
+}
+
+func TestField_LimitedWalls(t *testing.T) {
+	m := newVisionMap()
+	m.Walls = models.Walls{models.NewWall("limited-1", models.WallTypeTerrain, 2, 0, 2, 10, 1, 1)}
+	assert.True(t, vision.NewField(m).HasLineOfSight(0, 0, 5, 0), "one limited wall does not block")
+
+	m.Walls = append(m.Walls, models.NewWall("limited-2", models.WallTypeTerrain, 4, 0, 4, 10, 1, 1))
+	assert.False(t, vision.NewField(m).HasLineOfSight(0, 0, 5, 0), "two limited walls block")
+}
+
+func TestField_LightAt(t *testing.T) {
+	m := newVisionMap()
+	m.AmbientLight = models.LightDarkness
+	m.Lights = []models.LightSource{{ID: "torch", Position: models.Position{X: 0, Y: 0}, Bright: 20, Dim: 40}}
+	m.Walls = models.Walls{models.NewWall("wall-1", models.WallTypeWall, 0, 6, 10, 6, 0, 0)}
+	field := vision.NewField(m)
+
+	assert.Equal(t, models.LightBright, field.LightAt(4, 0))
+	assert.Equal(t, models.LightDim, field.LightAt(6, 3))
+	assert.Equal(t, models.LightDarkness, field.LightAt(9, 0))
+	assert.Equal(t, models.LightDarkness, field.LightAt(0, 7), "wall blocks the light")
+
+	m.AmbientLight = ""
+	assert.Equal(t, models.LightBright, vision.NewField(m).LightAt(9, 9))
+}
+
+func TestField_SeeCell(t *testing.T) {
+	dark := newVisionMap()
+	dark.AmbientLight = models.LightDarkness
+
+	tests := []struct {
+		name          string
+		viewer        func() *vision.Viewer
+		x, y          int
+		expectVisible bool
+		expectSense   string
+		expectReason  string
+	}{
+		{
+			name:         "darkness without darkvision",
+			viewer:       func() *vision.Viewer { return newViewer(0, 0) },
+			x:            2,
+			y:            0,
+			expectReason: "darkness",
+		},
+		{
+			name: "darkvision in range",
+			viewer: func() *vision.Viewer {
+				v := newViewer(0, 0)
+				v.Darkvision = 60
+				return v
+			},
+			x:             5,
+			y:             0,
+			expectVisible: true,
+			expectSense:   vision.SenseDarkvision,
+		},
+		{
+			name: "darkvision out of range",
+			viewer: func() *vision.Viewer {
+				v := newViewer(0, 0)
+				v.Darkvision = 10
+				return v
+			},
+			x:            5,
+			y:            0,
+			expectReason: "darkness",
+		},
+		{
+			name: "blinded with blindsight",
+			viewer: func() *vision.Viewer {
+				v := newViewer(0, 0)
+				v.Blinded = true
+				v.Blindsight = 10
+				return v
+			},
+			x:             2,
+			y:             0,
+			expectVisible: true,
+			expectSense:   vision.SenseBlindsight,
+		},
+		{
+			name: "blinded beyond blindsight",
+			viewer: func() *vision.Viewer {
+				v := newViewer(0, 0)
+				v.Blinded = true
+				v.Blindsight = 10
+				v.Darkvision = 60
+				return v
+			},
+			x:            5,
+			y:            0,
+			expectReason: "blinded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sight := vision.NewField(dark).SeeCell(tt.viewer(), tt.x, tt.y)
+			assert.Equal(t, tt.expectVisible, sight.Visible)
+			assert.Equal(t, tt.expectSense, sight.Sense)
+			assert.Equal(t, tt.expectReason, sight.Reason)
+		})
+	}
+}
+
+func TestField_SeeTokenAndVisibleCells(t *testing.T) {
+	m := newVisionMap()
+	m.Walls = models.Walls{models.NewWall("wall-1", models.WallTypeWall, 5, 0, 5, 10, 0, 0)}
+	field := vision.NewField(m)
+	viewer := newViewer(0, 0)
+
+	near := models.NewToken("near", 3, 3, models.TokenSizeMedium)
+	behindWall := models.NewToken("far", 7, 0, models.TokenSizeMedium)
+
+	sight := field.SeeToken(viewer, near)
+	assert.True(t, sight.Visible)
+	assert.Equal(t, vision.SenseSight, sight.Sense)
+	assert.Equal(t, 15, sight.Distance)
+
+	sight = field.SeeToken(viewer, behindWall)
+	assert.False(t, sight.Visible)
+	assert.Equal(t, "no line of sight", sight.Reason)
+
+	cells := field.VisibleCells(viewer)
+	assert.Len(t, cells, 50) // the five columns west of the wall
+}
+
+func TestNewViewer(t *testing.T) {
+	character := models.NewCharacter("campaign-1", "Drow", false)
+	character.Traits = models.NewTraits()
+	character.Traits.AddSense("darkvision", 120)
+
+	viewer := vision.NewViewer(models.NewToken("drow", 0, 0, models.TokenSizeMedium), character,
+		[]models.Condition{{Type: models.ConditionBlinded}})
+
+	assert.Equal(t, 120, viewer.Darkvision)
+	assert.True(t, viewer.Blinded)
+	assert.Equal(t, map[string]int{"darkvision": 120}, viewer.Senses())
+}
@@ -0,0 +1,298 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupActionTest wires a combat service with a fighter whose turn it is and an orc
+func setupActionTest(values []int) (*service.CombatService, *models.Combat, *models.Character, *models.Character) {
+	mockCombatStore := NewMockCombatStore()
+	mockCampaignStore := new(MockCampaignStoreForCombat)
+	mockCharacterStore := new(MockCharacterStoreForCombat)
+	mockGameStateStore := NewMockGameStateStore()
+	roller := dice.NewRollerWithSource(&MockRandomSourceForCombat{values: values})
+	diceSvc := service.NewDiceServiceWithRoller(new(MockCharacterStoreForDice), roller)
+
+	fighter := createTestCharacter("fighter", "Fighter", "campaign1", 40, 16)
+	orc := createTestCharacter("orc", "Orc", "campaign1", 30, 13)
+	fighter.EquipmentSlots = &models.EquipmentSlots{
+		MainHand: createTestWeapon(),
+	}
+
+	combat := models.NewCombat("campaign1", []string{"fighter", "orc"})
+	combat.ID = "combat1"
+	combat.Participants = []models.Participant{
+		{CharacterID: "fighter", Initiative: 20},
+		{CharacterID: "orc", Initiative: 10},
+	}
+
+	mockCombatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	mockCombatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockCharacterStore.On("Get", mock.Anything, "fighter").Return(fighter, nil)
+	mockCharacterStore.On("Get", mock.Anything, "orc").Return(orc, nil)
+	mockCharacterStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	svc := service.NewCombatServiceWithRoller(
+		mockCombatStore,
+		mockCharacterStore,
+		mockCampaignStore,
+		mockGameStateStore,
+		diceSvc,
+		roller,
+	)
+	return svc, combat, fighter, orc
+}
+
+// TestAttack_ConsumesAction tests that a second attack in the same turn is rejected
+func TestAttack_ConsumesAction(t *testing.T) {
+	svc, combat, _, _ := setupActionTest([]int{14, 4})
+	req := &service.AttackRequest{CombatID: "combat1", AttackerID: "fighter", TargetID: "orc"}
+
+	_, err := svc.Attack(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, combat.Participants[0].Actions.Action)
+
+	_, err = svc.Attack(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already used their action")
+}
+
+// TestAdvanceTurn_ResetsActions tests that the next participant's budget is refreshed
+func TestAdvanceTurn_ResetsActions(t *testing.T) {
+	svc, combat, _, _ := setupActionTest(nil)
+	combat.Participants[1].Actions = &models.ActionBudget{Speed: 30, Dodging: true}
+
+	_, err := svc.AdvanceTurn(context.Background(), "combat1")
+	require.NoError(t, err)
+
+	budget := combat.Participants[1].Actions
+	require.NotNil(t, budget)
+	assert.True(t, budget.Action)
+	assert.True(t, budget.BonusAction)
+	assert.True(t, budget.Reaction)
+	assert.Equal(t, 30, budget.Movement)
+	assert.False(t, budget.Dodging)
+}
+
+// TestDash tests that Dash doubles movement and can use the bonus action
+func TestDash(t *testing.T) {
+	svc, _, _, _ := setupActionTest(nil)
+
+	resp, err := svc.Dash(context.Background(), &service.TurnActionRequest{
+		CombatID:       "combat1",
+		CharacterID:    "fighter",
+		UseBonusAction: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 60, resp.Actions.Movement)
+	assert.False(t, resp.Actions.BonusAction)
+	assert.True(t, resp.Actions.Action)
+}
+
+// TestDodge_ImposesDisadvantage tests that attacks against a dodging target roll with disadvantage
+func TestDodge_ImposesDisadvantage(t *testing.T) {
+	// Disadvantage: 19 and 2, lower is 2 + 6 = 8 vs AC 13 (miss)
+	svc, combat, _, _ := setupActionTest([]int{18, 1})
+	combat.Participants[1].Actions = models.NewActionBudget(30)
+	combat.Participants[1].Actions.Dodging = true
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, resp.Result.AttackRoll.Rolls)
+	assert.False(t, resp.Result.Hit)
+}
+
+// TestHelp_GrantsAdvantageOnce tests that the Help action grants advantage to the ally's next attack
+func TestHelp_GrantsAdvantageOnce(t *testing.T) {
+	// Advantage: 2 and 15, higher is 15 + 6 = 21 vs AC 13 (hit)
+	svc, combat, _, _ := setupActionTest([]int{1, 14, 4})
+	combat.Participants[1].Actions = &models.ActionBudget{HelpingID: "fighter", HelpAgainstID: "orc"}
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{15}, resp.Result.AttackRoll.Rolls)
+	assert.True(t, resp.Result.Hit)
+	assert.Empty(t, combat.Participants[1].Actions.HelpingID)
+}
+
+// TestHelp_RequiresAllyInCombat tests Help validation
+func TestHelp_RequiresAllyInCombat(t *testing.T) {
+	svc, combat, _, _ := setupActionTest(nil)
+
+	_, err := svc.Help(context.Background(), &service.HelpRequest{
+		CombatID:    "combat1",
+		CharacterID: "fighter",
+		AllyID:      "stranger",
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, combat.Participants[0].Actions)
+}
+
+// TestHide tests the stealth check against a DC
+func TestHide(t *testing.T) {
+	// Stealth roll 15 + 2 (DEX) = 17 vs DC 14
+	svc, combat, _, _ := setupActionTest([]int{14})
+
+	resp, err := svc.Hide(context.Background(), &service.HideRequest{
+		CombatID:    "combat1",
+		CharacterID: "fighter",
+		DC:          14,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Hidden)
+	assert.Equal(t, 17, resp.Check.DiceResult.Total)
+	assert.True(t, combat.Participants[0].Hidden)
+	assert.False(t, resp.Actions.Action)
+}
+
+// TestReady tests that Ready records the trigger and consumes the action
+func TestReady(t *testing.T) {
+	svc, _, _, _ := setupActionTest(nil)
+
+	_, err := svc.Ready(context.Background(), &service.ReadyRequest{
+		CombatID:    "combat1",
+		CharacterID: "fighter",
+	})
+	require.Error(t, err)
+
+	resp, err := svc.Ready(context.Background(), &service.ReadyRequest{
+		CombatID:    "combat1",
+		CharacterID: "fighter",
+		Trigger:     "the orc steps through the door",
+		Action:      "attack the orc",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Actions.Readied)
+	assert.Equal(t, "attack the orc", resp.Actions.Readied.Action)
+	assert.False(t, resp.Actions.Action)
+	assert.True(t, resp.Actions.Reaction)
+}
+
+// TestTurnAction_NotYourTurn tests that actions are rejected out of turn
+func TestTurnAction_NotYourTurn(t *testing.T) {
+	svc, _, _, _ := setupActionTest(nil)
+
+	_, err := svc.Dodge(context.Background(), &service.TurnActionRequest{
+		CombatID:    "combat1",
+		CharacterID: "orc",
+	})
+
+	require.Error(t, err)
+}
+
+// TestMapService_MoveToken_InCombat tests that token movement consumes the turn's movement
+func TestMapService_MoveToken_InCombat(t *testing.T) {
+	mapStore := new(MockMapStore)
+	campaignStore := new(MockCampaignStoreForMap)
+	gameStateStore := new(MockGameStateStoreForMap)
+	combatStore := NewMockCombatStore()
+
+	battleMap := models.NewBattleMap("campaign-123", "Test Battle", 20, 20, 5)
+	battleMap.ID = "map-001"
+	token := models.NewToken("fighter", 0, 0, models.TokenSizeMedium)
+	token.ID = "token-001"
+	battleMap.AddToken(*token)
+
+	combat := models.NewCombat("campaign-123", []string{"fighter", "orc"})
+	combat.ID = "combat1"
+	combat.Participants[0].Actions = models.NewActionBudget(30)
+
+	gameState := models.NewGameState("campaign-123")
+	gameState.SetCombat("combat1")
+
+	mapStore.On("Get", mock.Anything, "map-001").Return(battleMap, nil)
+	mapStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Map")).Return(nil)
+	gameStateStore.On("Get", mock.Anything, "campaign-123").Return(gameState, nil)
+	combatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	combatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	svc := service.NewMapService(mapStore, campaignStore, gameStateStore)
+	svc.SetCombatStore(combatStore)
+
+	req := &service.TokenMoveRequest{
+		CampaignID: "campaign-123",
+		MapID:      "map-001",
+		TokenID:    "token-001",
+		ToX:        4,
+		ToY:        0,
+	}
+	result, err := svc.MoveToken(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 20, result.MovementUsed)
+	assert.Equal(t, 10, result.RemainingSpeed)
+	assert.Equal(t, 10, combat.Participants[0].Actions.Movement)
+
+	// Not enough movement left for another 20 feet
+	req.ToX = 8
+	_, err = svc.MoveToken(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient movement")
+}
+
+// TestMapService_MoveToken_CombatUpdateFails tests that a move whose movement cannot be
+// spent is neither saved nor announced, and that both writes share one transaction
+func TestMapService_MoveToken_CombatUpdateFails(t *testing.T) {
+	mapStore := new(MockMapStore)
+	gameStateStore := new(MockGameStateStoreForMap)
+	combatStore := NewMockCombatStore()
+
+	battleMap := models.NewBattleMap("campaign-123", "Test Battle", 20, 20, 5)
+	battleMap.ID = "map-001"
+	token := models.NewToken("fighter", 0, 0, models.TokenSizeMedium)
+	token.ID = "token-001"
+	battleMap.AddToken(*token)
+
+	combat := models.NewCombat("campaign-123", []string{"fighter", "orc"})
+	combat.ID = "combat1"
+	combat.Participants[0].Actions = models.NewActionBudget(30)
+
+	gameState := models.NewGameState("campaign-123")
+	gameState.SetCombat("combat1")
+
+	mapStore.On("Get", mock.Anything, "map-001").Return(battleMap, nil)
+	gameStateStore.On("Get", mock.Anything, "campaign-123").Return(gameState, nil)
+	combatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	combatStore.On("Update", mock.Anything, mock.Anything).Return(errors.New("database unavailable"))
+
+	events := &memoryEventStore{}
+	tx := &fakeTransactor{}
+	svc := service.NewMapService(mapStore, new(MockCampaignStoreForMap), gameStateStore)
+	svc.SetCombatStore(combatStore)
+	svc.SetTransactor(tx)
+	svc.SetEventPublisher(service.NewEventBus(events))
+
+	_, err := svc.MoveToken(context.Background(), &service.TokenMoveRequest{
+		CampaignID: "campaign-123",
+		MapID:      "map-001",
+		TokenID:    "token-001",
+		ToX:        4,
+		ToY:        0,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update combat")
+	assert.Equal(t, 1, tx.calls)
+	mapStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	assert.Empty(t, events.ofType(models.EventTokenMoved))
+}
@@ -0,0 +1,267 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAuditStore is an in-memory AuditStore assigning sequential IDs
+type memoryAuditStore struct {
+	mu      sync.Mutex
+	entries []*models.AuditEntry
+}
+
+func (m *memoryAuditStore) Append(ctx context.Context, entry *models.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry.ID = int64(len(m.entries) + 1)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memoryAuditStore) List(ctx context.Context, filter *store.AuditFilter) ([]*models.AuditEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*models.AuditEntry, 0)
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		entry := m.entries[i]
+		if entry.CampaignID != filter.CampaignID || (entry.IsUndone() && !filter.IncludeUndone) {
+			continue
+		}
+		result = append(result, entry)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryAuditStore) MarkUndone(ctx context.Context, ids []int64, undoneAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		entry := m.entries[id-1]
+		if entry.IsUndone() {
+			return errors.New("already undone")
+		}
+		entry.UndoneAt = &undoneAt
+	}
+	return nil
+}
+
+// fakeTransactor runs functions directly and counts transactions.
+// Like a real transactor it runs AfterCommit hooks only when fn succeeds.
+type fakeTransactor struct {
+	calls int
+}
+
+func (f *fakeTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	txCtx, committed := store.WithCommitHooks(ctx)
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+	committed()
+	return nil
+}
+
+// failingReadStore fails reads with err while it is set
+type failingReadStore struct {
+	*mockCharacterStore
+	err error
+}
+
+func (s *failingReadStore) Get(ctx context.Context, id string) (*models.Character, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.mockCharacterStore.Get(ctx, id)
+}
+
+type auditTest struct {
+	svc        *service.AuditService
+	audit      *memoryAuditStore
+	tx         *fakeTransactor
+	inner      *mockCharacterStore
+	reads      *failingReadStore
+	characters store.CharacterStore
+}
+
+func setupAuditTest() *auditTest {
+	campaignStore := NewMockCampaignStore()
+	campaignStore.campaigns["campaign1"] = &models.Campaign{ID: "campaign1", Name: "Lost Mine", DMID: "dm1"}
+
+	test := &auditTest{
+		audit: &memoryAuditStore{},
+		tx:    &fakeTransactor{},
+		inner: newMockCharacterStore(),
+	}
+	test.inner.characters["hero"] = heroWithHP(10)
+	test.reads = &failingReadStore{mockCharacterStore: test.inner}
+	test.characters = store.JournalCharacterStore(test.reads)
+	test.svc = service.NewAuditService(test.audit, test.tx, campaignStore, test.characters, nil, nil, nil)
+	return test
+}
+
+func heroWithHP(current int) *models.Character {
+	return &models.Character{ID: "hero", CampaignID: "campaign1", Name: "Hero", HP: &models.HP{Current: current, Max: 10}}
+}
+
+// act performs writes as one audited tool call
+func (a *auditTest) act(t *testing.T, tool string, write func(ctx context.Context)) *models.AuditEntry {
+	t.Helper()
+	journal := store.NewJournal()
+	write(store.WithJournal(context.Background(), journal))
+	entry, err := a.svc.Record(context.Background(), tool, []byte(`{"campaign_id":"campaign1"}`), journal.Revisions())
+	require.NoError(t, err)
+	return entry
+}
+
+func assertServiceCode(t *testing.T, code string, err error) {
+	t.Helper()
+	require.Error(t, err)
+	se := service.GetServiceError(err)
+	require.NotNil(t, se, "expected a service error, got %v", err)
+	assert.Equal(t, code, se.Code)
+}
+
+// TestAuditService_RecordAndUndo tests that undoing an action restores the record
+func TestAuditService_RecordAndUndo(t *testing.T) {
+	a := setupAuditTest()
+	entry := a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(4)))
+	})
+	require.NotNil(t, entry)
+	assert.Equal(t, "campaign1", entry.CampaignID)
+	require.Len(t, entry.Changes, 1)
+	diff := entry.Changes[0].Diff()
+	require.Len(t, diff, 1)
+	assert.Equal(t, "hp.current", diff[0].Path)
+
+	resp, err := a.svc.UndoActions(context.Background(), &service.UndoActionRequest{CampaignID: "campaign1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Undone, 1)
+	assert.True(t, resp.Undone[0].IsUndone())
+	assert.Equal(t, 10, a.inner.characters["hero"].HP.Current)
+	assert.Equal(t, 1, a.tx.calls, "undo runs in a transaction")
+
+	_, err = a.svc.UndoActions(context.Background(), &service.UndoActionRequest{CampaignID: "campaign1"})
+	assertServiceCode(t, service.ErrCodeInvalidState, err)
+}
+
+// TestAuditService_RecordSkipsNoChanges tests that calls which wrote nothing are not recorded
+func TestAuditService_RecordSkipsNoChanges(t *testing.T) {
+	a := setupAuditTest()
+	entry := a.act(t, "update_character", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(10)))
+	})
+	assert.Nil(t, entry)
+	assert.Empty(t, a.audit.entries)
+}
+
+// TestAuditService_UndoSeveralActions tests that undoing several actions restores the oldest state
+func TestAuditService_UndoSeveralActions(t *testing.T) {
+	a := setupAuditTest()
+	a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(6)))
+	})
+	a.act(t, "create_character", func(ctx context.Context) {
+		require.NoError(t, a.characters.Create(ctx, &models.Character{ID: "goblin", CampaignID: "campaign1", Name: "Goblin"}))
+	})
+	a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(1)))
+	})
+
+	actions, err := a.svc.ListActions(context.Background(), &service.ListActionsRequest{CampaignID: "campaign1"})
+	require.NoError(t, err)
+	require.Len(t, actions, 3)
+	assert.Equal(t, int64(3), actions[0].ID, "newest first")
+
+	resp, err := a.svc.UndoActions(context.Background(), &service.UndoActionRequest{CampaignID: "campaign1", Count: 3})
+	require.NoError(t, err)
+	assert.Len(t, resp.Undone, 3)
+	assert.Equal(t, 10, a.inner.characters["hero"].HP.Current)
+	assert.NotContains(t, a.inner.characters, "goblin", "created characters are deleted")
+}
+
+// TestAuditService_UndoRefusesLaterConflicts tests that an action cannot be undone under a later one
+func TestAuditService_UndoRefusesLaterConflicts(t *testing.T) {
+	a := setupAuditTest()
+	first := a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(6)))
+	})
+	a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(2)))
+	})
+
+	_, err := a.svc.UndoActions(context.Background(), &service.UndoActionRequest{CampaignID: "campaign1", ActionID: first.ID})
+	assertServiceCode(t, service.ErrCodeConflict, err)
+	assert.Equal(t, 2, a.inner.characters["hero"].HP.Current, "nothing is restored")
+
+	// An action touching other records can be undone on its own
+	goblin := a.act(t, "create_character", func(ctx context.Context) {
+		require.NoError(t, a.characters.Create(ctx, &models.Character{ID: "goblin", CampaignID: "campaign1", Name: "Goblin"}))
+	})
+	a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(1)))
+	})
+	_, err = a.svc.UndoActions(context.Background(), &service.UndoActionRequest{CampaignID: "campaign1", ActionID: goblin.ID})
+	require.NoError(t, err)
+	assert.NotContains(t, a.inner.characters, "goblin")
+}
+
+// TestAuditService_UndoRefusesOutsideEdits tests that records edited outside audited calls are not overwritten
+func TestAuditService_UndoRefusesOutsideEdits(t *testing.T) {
+	a := setupAuditTest()
+	a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(6)))
+	})
+	require.NoError(t, a.characters.Update(context.Background(), heroWithHP(8)))
+
+	_, err := a.svc.UndoActions(context.Background(), &service.UndoActionRequest{CampaignID: "campaign1"})
+	assertServiceCode(t, service.ErrCodeConflict, err)
+	assert.Equal(t, 8, a.inner.characters["hero"].HP.Current)
+}
+
+// TestAuditService_UndoReportsReadErrors tests that a failure to read a record fails the undo
+// instead of treating the record as missing
+func TestAuditService_UndoReportsReadErrors(t *testing.T) {
+	a := setupAuditTest()
+	a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(6)))
+	})
+	readErr := errors.New("connection lost")
+	a.reads.err = readErr
+
+	_, err := a.svc.UndoActions(context.Background(), &service.UndoActionRequest{CampaignID: "campaign1"})
+	assert.ErrorIs(t, err, readErr)
+	assert.Nil(t, service.GetServiceError(err), "a read failure is not a conflict")
+	assert.Equal(t, 6, a.inner.characters["hero"].HP.Current)
+}
+
+// TestAuditService_RequiresCampaignDM tests that only the campaign's DM may list or undo actions
+func TestAuditService_RequiresCampaignDM(t *testing.T) {
+	a := setupAuditTest()
+	a.act(t, "attack", func(ctx context.Context) {
+		require.NoError(t, a.characters.Update(ctx, heroWithHP(6)))
+	})
+
+	_, err := a.svc.UndoActions(asPrincipal(auth.RoleDM, "dm2"), &service.UndoActionRequest{CampaignID: "campaign1"})
+	assertServiceCode(t, service.ErrCodeForbidden, err)
+
+	_, err = a.svc.ListActions(asPrincipal(auth.RolePlayer, "player1"), &service.ListActionsRequest{CampaignID: "campaign1"})
+	assertServiceCode(t, service.ErrCodeForbidden, err)
+
+	_, err = a.svc.UndoActions(asPrincipal(auth.RoleDM, "dm1"), &service.UndoActionRequest{CampaignID: "campaign1"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,212 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/auth"
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// asPrincipal returns a request context for the principal; dm1 runs every test campaign
+func asPrincipal(role auth.Role, userID string) context.Context {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID, Role: role})
+	return auth.WithCampaignDMs(ctx, func(ctx context.Context, campaignID string) (string, error) {
+		return "dm1", nil
+	})
+}
+
+func assertForbidden(t *testing.T, err error) {
+	t.Helper()
+	require.Error(t, err)
+	se := service.GetServiceError(err)
+	require.NotNil(t, se, "expected a service error, got %v", err)
+	assert.Equal(t, service.ErrCodeForbidden, se.Code)
+}
+
+// TestAuthz_PlayerActsOnlyThroughOwnCharacter tests that players may only attack with their own character
+func TestAuthz_PlayerActsOnlyThroughOwnCharacter(t *testing.T) {
+	req := &service.AttackRequest{CombatID: "combat1", AttackerID: "fighter", TargetID: "orc"}
+
+	svc, _, _, _ := setupActionTest([]int{14, 4})
+	_, err := svc.Attack(asPrincipal(auth.RolePlayer, "player2"), req)
+	assertForbidden(t, err)
+
+	_, err = svc.Attack(asPrincipal(auth.RoleObserver, "player1"), req)
+	assertForbidden(t, err)
+
+	_, err = svc.Attack(asPrincipal(auth.RolePlayer, "player1"), req)
+	assert.NoError(t, err)
+
+	svc, _, _, _ = setupActionTest([]int{14, 4})
+	_, err = svc.Attack(asPrincipal(auth.RoleDM, "dm1"), req)
+	assert.NoError(t, err)
+}
+
+// TestAuthz_OtherCampaignDM tests that a DM has no DM rights in campaigns they do not run
+func TestAuthz_OtherCampaignDM(t *testing.T) {
+	svc, _, _, _ := setupActionTest([]int{14, 4})
+	_, err := svc.Attack(asPrincipal(auth.RoleDM, "dm2"), &service.AttackRequest{CombatID: "combat1", AttackerID: "fighter", TargetID: "orc"})
+	assertForbidden(t, err)
+
+	characters := service.NewCharacterService(new(MockCharacterStore))
+	_, err = characters.CreateCharacter(asPrincipal(auth.RoleDM, "dm2"), &service.CreateCharacterRequest{CampaignID: "campaign1", Name: "Goblin", IsNPC: true})
+	assertForbidden(t, err)
+
+	npc := createTestCharacter("orc", "Orc", "campaign1", 30, 13)
+	npc.IsNPC = true
+	assert.Nil(t, service.RedactCharacter(asPrincipal(auth.RoleDM, "dm2"), npc).HP)
+
+	// Without a campaign DM lookup a DM runs no campaign
+	bare := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "dm1", Role: auth.RoleDM})
+	assert.Nil(t, service.RedactCharacter(bare, npc).HP)
+}
+
+// TestAuthz_PlayerCannotActAsNPC tests that players may not control NPCs even when listed as their player
+func TestAuthz_PlayerCannotActAsNPC(t *testing.T) {
+	svc, combat, _, orc := setupActionTest([]int{14, 4})
+	orc.IsNPC = true
+	combat.TurnIndex = 1
+
+	_, err := svc.Dodge(asPrincipal(auth.RolePlayer, "player1"), &service.TurnActionRequest{CombatID: "combat1", CharacterID: "orc"})
+	assertForbidden(t, err)
+}
+
+// TestAuthz_PlayerActsOnlyOnOwnTurn tests that players may not act with their character on another turn
+func TestAuthz_PlayerActsOnlyOnOwnTurn(t *testing.T) {
+	svc, combat, _, _ := setupActionTest([]int{14, 4})
+	combat.TurnIndex = 1
+
+	_, err := svc.Attack(asPrincipal(auth.RolePlayer, "player1"), &service.AttackRequest{CombatID: "combat1", AttackerID: "fighter", TargetID: "orc"})
+	assertForbidden(t, err)
+
+	_, err = svc.Dash(asPrincipal(auth.RolePlayer, "player1"), &service.TurnActionRequest{CombatID: "combat1", CharacterID: "fighter"})
+	assertForbidden(t, err)
+
+	_, err = svc.Dash(asPrincipal(auth.RoleDM, "dm1"), &service.TurnActionRequest{CombatID: "combat1", CharacterID: "fighter"})
+	require.Error(t, err)
+	assert.Equal(t, service.ErrCodeInvalidState, service.GetServiceError(err).Code)
+}
+
+// TestAuthz_EndTurn tests that players may only end their own character's turn
+func TestAuthz_EndTurn(t *testing.T) {
+	svc, _, _, _ := setupActionTest(nil)
+
+	_, err := svc.AdvanceTurn(asPrincipal(auth.RolePlayer, "player2"), "combat1")
+	assertForbidden(t, err)
+
+	resp, err := svc.AdvanceTurn(asPrincipal(auth.RolePlayer, "player1"), "combat1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Combat.TurnIndex)
+}
+
+// TestAuthz_EndCombatRequiresCampaignDM tests that only the campaign's DM may end its combat
+func TestAuthz_EndCombatRequiresCampaignDM(t *testing.T) {
+	mockCombatStore := NewMockCombatStore()
+	mockCampaignStore := new(MockCampaignStoreForCombat)
+	combat := models.NewCombat("campaign1", []string{"fighter"})
+	combat.ID = "combat1"
+	combat.Participants = []models.Participant{{CharacterID: "fighter", Initiative: 10}}
+
+	mockCombatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	mockCombatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockCampaignStore.On("Get", mock.Anything, "campaign1").Return(&models.Campaign{ID: "campaign1", DMID: "dm1"}, nil)
+
+	svc := service.NewCombatService(mockCombatStore, new(MockCharacterStoreForCombat), mockCampaignStore, nil, nil)
+
+	_, err := svc.EndCombat(asPrincipal(auth.RoleDM, "dm2"), "combat1")
+	assertForbidden(t, err)
+
+	ended, err := svc.EndCombat(asPrincipal(auth.RoleDM, "dm1"), "combat1")
+	require.NoError(t, err)
+	assert.False(t, ended.IsActive())
+}
+
+// TestAuthz_CreateCharacter tests that players may only create their own player characters
+func TestAuthz_CreateCharacter(t *testing.T) {
+	mockStore := new(MockCharacterStore)
+	mockStore.On("Create", mock.Anything, mock.Anything).Return(nil)
+	svc := service.NewCharacterService(mockStore)
+	ctx := asPrincipal(auth.RolePlayer, "player1")
+
+	_, err := svc.CreateCharacter(ctx, &service.CreateCharacterRequest{CampaignID: "campaign1", Name: "Goblin", IsNPC: true})
+	assertForbidden(t, err)
+
+	_, err = svc.CreateCharacter(ctx, &service.CreateCharacterRequest{CampaignID: "campaign1", Name: "Thief", PlayerID: "player2"})
+	assertForbidden(t, err)
+
+	character, err := svc.CreateCharacter(ctx, &service.CreateCharacterRequest{CampaignID: "campaign1", Name: "Aragorn", Race: "Human", Class: "Ranger"})
+	require.NoError(t, err)
+	assert.Equal(t, "player1", character.PlayerID)
+}
+
+// TestAuthz_CampaignDM tests that DMs create and manage only their own campaigns
+func TestAuthz_CampaignDM(t *testing.T) {
+	svc, _, _ := setupCampaignService()
+
+	campaign, err := svc.CreateCampaign(asPrincipal(auth.RoleDM, "dm1"), &service.CreateCampaignRequest{Name: "Lost Mine"})
+	require.NoError(t, err)
+	assert.Equal(t, "dm1", campaign.DMID)
+
+	_, err = svc.CreateCampaign(asPrincipal(auth.RoleDM, "dm1"), &service.CreateCampaignRequest{Name: "Stolen", DMID: "dm2"})
+	assertForbidden(t, err)
+
+	name := "Renamed"
+	_, err = svc.UpdateCampaign(asPrincipal(auth.RoleDM, "dm2"), campaign.ID, &service.UpdateCampaignRequest{Name: &name})
+	assertForbidden(t, err)
+
+	err = svc.DeleteCampaign(asPrincipal(auth.RolePlayer, "player1"), campaign.ID)
+	assertForbidden(t, err)
+
+	// System services act as the DM of every campaign
+	_, err = svc.UpdateCampaign(asPrincipal(auth.RoleSystem, "client"), campaign.ID, &service.UpdateCampaignRequest{Name: &name})
+	assert.NoError(t, err)
+}
+
+// TestRedactCharacter tests that players and observers see only an NPC's appearance
+func TestRedactCharacter(t *testing.T) {
+	npc := createTestCharacter("orc", "Orc", "campaign1", 30, 13)
+	npc.IsNPC = true
+	npc.Race = "Orc"
+	pc := createTestCharacter("fighter", "Fighter", "campaign1", 40, 16)
+
+	assert.Same(t, npc, service.RedactCharacter(context.Background(), npc))
+	assert.Same(t, npc, service.RedactCharacter(asPrincipal(auth.RoleDM, "dm1"), npc))
+	assert.Same(t, pc, service.RedactCharacter(asPrincipal(auth.RolePlayer, "player2"), pc))
+
+	redacted := service.RedactCharacter(asPrincipal(auth.RoleObserver, "watcher"), npc)
+	assert.Equal(t, "Orc", redacted.Name)
+	assert.Equal(t, "Orc", redacted.Race)
+	assert.Nil(t, redacted.HP)
+	assert.Nil(t, redacted.Abilities)
+	assert.Zero(t, redacted.AC)
+	assert.Equal(t, 30, npc.HP.Current, "the original character must not be modified")
+}
+
+// TestRedactMap tests that players do not see hidden tokens, secret bars or undiscovered secret doors
+func TestRedactMap(t *testing.T) {
+	battleMap := models.NewBattleMap("campaign1", "Cave", 10, 10, 5)
+	battleMap.Tokens = []models.Token{
+		{ID: "fighter", CharacterID: "fighter", Visible: true, Bar1: &models.TokenBar{Value: 40, Visible: true}},
+		{ID: "orc", CharacterID: "orc", Visible: true, Bar1: &models.TokenBar{Value: 30, Visible: false}},
+		{ID: "assassin", CharacterID: "assassin", Hidden: true},
+	}
+	secretDoor := models.NewDoor("door", 3, 3, models.WallDirectionLeft)
+	secretDoor.Door.Secret = true
+	battleMap.Walls = models.Walls{models.NewWall("wall", models.WallTypeWall, 0, 0, 0, 5, 1, 1), secretDoor}
+
+	assert.Same(t, battleMap, service.RedactMap(asPrincipal(auth.RoleDM, "dm1"), battleMap))
+
+	view := service.RedactMap(asPrincipal(auth.RolePlayer, "player1"), battleMap)
+	require.Len(t, view.Tokens, 2)
+	assert.NotNil(t, view.Tokens[0].Bar1)
+	assert.Nil(t, view.Tokens[1].Bar1)
+	require.Len(t, view.Walls, 1)
+	assert.Equal(t, "wall", view.Walls[0].ID)
+	assert.Len(t, battleMap.Tokens, 3, "the original map must not be modified")
+	assert.NotNil(t, battleMap.Tokens[1].Bar1)
+}
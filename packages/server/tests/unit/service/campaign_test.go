@@ -349,6 +349,32 @@ func TestCampaignService_UpdateCampaign(t *testing.T) {
 	assert.Equal(t, "Updated description", updated.Description)
 }
 
+func TestCampaignService_UpdateCampaign_PromptOverrides(t *testing.T) {
+	svc, cStore, _ := setupCampaignService()
+	ctx := context.Background()
+
+	campaign := models.NewCampaign("Prompted", "dm-001", "")
+	campaign.ID = uuid.New().String()
+	cStore.Create(ctx, campaign)
+
+	// Overrides are merged per prompt
+	_, err := svc.UpdateCampaign(ctx, campaign.ID, &service.UpdateCampaignRequest{
+		Settings: &service.CampaignSettingsInput{Prompts: map[string]string{"session_recap": "Recap", "npc_dialogue": "Talk"}},
+	})
+	require.NoError(t, err)
+	updated, err := svc.UpdateCampaign(ctx, campaign.ID, &service.UpdateCampaignRequest{
+		Settings: &service.CampaignSettingsInput{Prompts: map[string]string{"npc_dialogue": ""}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"session_recap": "Recap"}, updated.Settings.Prompts)
+
+	// Templates that do not parse are rejected
+	_, err = svc.UpdateCampaign(ctx, campaign.ID, &service.UpdateCampaignRequest{
+		Settings: &service.CampaignSettingsInput{Prompts: map[string]string{"session_recap": "{{.Campaign"}},
+	})
+	assert.Error(t, err)
+}
+
 func TestCampaignService_UpdateCampaign_Status(t *testing.T) {
 	svc, cStore, _ := setupCampaignService()
 	ctx := context.Background()
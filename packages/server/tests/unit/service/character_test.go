@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/dice"
 	"github.com/dnd-mcp/server/internal/service"
 	"github.com/dnd-mcp/server/internal/store"
 	"github.com/stretchr/testify/assert"
@@ -1165,3 +1166,57 @@ func TestCountCharacters(t *testing.T) {
 		mockStore.AssertExpectations(t)
 	})
 }
+
+// TestMakeDeathSave tests death saving throws for a dying character
+func TestMakeDeathSave(t *testing.T) {
+	dying := func() *models.Character {
+		return &models.Character{ID: "char-1", Name: "Aria", HP: &models.HP{Current: 0, Max: 20}}
+	}
+
+	t.Run("rolls automatically when no roll is given", func(t *testing.T) {
+		mockStore := new(MockCharacterStore)
+		roller := dice.NewRollerWithSource(&MockRandomSourceForService{values: []int{13}}) // d20 = 14
+		charService := service.NewCharacterServiceWithRoller(mockStore, roller)
+
+		mockStore.On("Get", mock.Anything, "char-1").Return(dying(), nil)
+		mockStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		resp, err := charService.MakeDeathSave(context.Background(), "char-1", &service.DeathSaveRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 14, resp.Roll)
+		assert.Equal(t, "success", resp.Result)
+		assert.Equal(t, 1, resp.Successes)
+		assert.Contains(t, resp.Summary(), "Aria rolled 14")
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("natural 20 restores 1 HP", func(t *testing.T) {
+		mockStore := new(MockCharacterStore)
+		charService := service.NewCharacterService(mockStore)
+
+		mockStore.On("Get", mock.Anything, "char-1").Return(dying(), nil)
+		mockStore.On("Update", mock.Anything, mock.MatchedBy(func(c *models.Character) bool {
+			return c.HP.Current == 1
+		})).Return(nil)
+
+		resp, err := charService.MakeDeathSave(context.Background(), "char-1", &service.DeathSaveRequest{Roll: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "critical_success", resp.Result)
+		assert.Equal(t, 1, resp.HealedHP)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("fail when character is conscious", func(t *testing.T) {
+		mockStore := new(MockCharacterStore)
+		charService := service.NewCharacterService(mockStore)
+
+		mockStore.On("Get", mock.Anything, "char-1").Return(&models.Character{ID: "char-1", HP: &models.HP{Current: 5, Max: 20}}, nil)
+
+		_, err := charService.MakeDeathSave(context.Background(), "char-1", &service.DeathSaveRequest{Roll: 12})
+
+		assert.Error(t, err)
+		mockStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAttack_BreaksConcentration tests that a failed Constitution save ends the target's spell
+func TestAttack_BreaksConcentration(t *testing.T) {
+	// Hit (15 + 6 vs AC 13) for 5 damage; concentration save 1 + 2 = 3 vs DC 10
+	svc, combat, _, orc := setupActionTest([]int{14, 4, 0})
+	orc.Spellbook = models.NewSpellbook()
+	orc.Spellbook.StartConcentration("hold-person", 10)
+	combat.Participants[0].AddConcentrationCondition(models.ConditionRestrained, "Hold Person", "orc", "hold-person")
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.ConcentrationChecks, 1)
+	check := resp.ConcentrationChecks[0]
+	assert.False(t, check.Maintained)
+	assert.Equal(t, 10, check.DC)
+	assert.Equal(t, 5, check.Damage)
+	require.Len(t, check.RemovedConditions, 1)
+	assert.Equal(t, "fighter", check.RemovedConditions[0].CharacterID)
+	assert.False(t, orc.Spellbook.IsConcentrating())
+	assert.False(t, combat.Participants[0].HasCondition(models.ConditionRestrained))
+}
+
+// TestAttack_MaintainsConcentration tests a successful concentration save
+func TestAttack_MaintainsConcentration(t *testing.T) {
+	// Hit for 5 damage; concentration save 15 + 2 = 17 vs DC 10
+	svc, _, _, orc := setupActionTest([]int{14, 4, 14})
+	orc.Spellbook = models.NewSpellbook()
+	orc.Spellbook.StartConcentration("bless", 10)
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.ConcentrationChecks, 1)
+	assert.True(t, resp.ConcentrationChecks[0].Maintained)
+	assert.True(t, orc.Spellbook.IsConcentrating())
+}
+
+// TestAttack_ZeroHPEndsConcentration tests that dropping to 0 HP ends concentration without a save
+func TestAttack_ZeroHPEndsConcentration(t *testing.T) {
+	svc, _, _, orc := setupActionTest([]int{14, 4})
+	orc.HP.Current = 3
+	orc.Spellbook = models.NewSpellbook()
+	orc.Spellbook.StartConcentration("bless", 10)
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.ConcentrationChecks, 1)
+	assert.Nil(t, resp.ConcentrationChecks[0].Save)
+	assert.Equal(t, "dropped to 0 hit points", resp.ConcentrationChecks[0].Reason)
+	assert.False(t, orc.Spellbook.IsConcentrating())
+}
+
+// TestCastSpell_SecondConcentrationSpellEndsFirst tests one-concentration-spell enforcement
+func TestCastSpell_SecondConcentrationSpellEndsFirst(t *testing.T) {
+	// Hold Person: orc's Wisdom save 1 + 1 = 2 fails
+	svc, combat, fighter, _ := setupActionTest([]int{0})
+
+	resp, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:      "combat1",
+		CasterID:      "fighter",
+		SpellID:       "hold-person",
+		SpellName:     "Hold Person",
+		TargetIDs:     []string{"orc"},
+		SaveAbility:   "wisdom",
+		Concentration: true,
+		Conditions:    []string{models.ConditionParalyzed},
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.ConcentrationChecks)
+	assert.Equal(t, []string{models.ConditionParalyzed}, resp.Result.Results[0].ConditionsApplied)
+	assert.True(t, combat.Participants[1].HasCondition(models.ConditionParalyzed))
+	require.NotNil(t, fighter.Spellbook)
+	assert.Equal(t, "hold-person", fighter.Spellbook.ConcentrationSpell)
+
+	// Next turn: casting Bless ends Hold Person
+	combat.Participants[0].Actions = nil
+	resp, err = svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:      "combat1",
+		CasterID:      "fighter",
+		SpellID:       "bless",
+		SpellName:     "Bless",
+		TargetIDs:     []string{"fighter"},
+		Concentration: true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.ConcentrationChecks, 1)
+	assert.Equal(t, "hold-person", resp.ConcentrationChecks[0].SpellID)
+	assert.Equal(t, "cast Bless", resp.ConcentrationChecks[0].Reason)
+	assert.False(t, combat.Participants[1].HasCondition(models.ConditionParalyzed))
+	assert.Equal(t, "bless", fighter.Spellbook.ConcentrationSpell)
+}
+
+// TestAdvanceTurn_ReleasesOrphanedConditions tests cleanup of conditions whose caster stopped concentrating
+func TestAdvanceTurn_ReleasesOrphanedConditions(t *testing.T) {
+	svc, combat, _, _ := setupActionTest(nil)
+	combat.Participants[1].AddConcentrationCondition(models.ConditionParalyzed, "Hold Person", "fighter", "hold-person")
+	combat.Participants[1].AddCondition(models.ConditionPoisoned, 3, "poison")
+
+	_, err := svc.AdvanceTurn(context.Background(), "combat1")
+
+	require.NoError(t, err)
+	assert.False(t, combat.Participants[1].HasCondition(models.ConditionParalyzed))
+	assert.True(t, combat.Participants[1].HasCondition(models.ConditionPoisoned))
+}
@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAttack_ProneTargetUsesTokenDistance tests that a prone target 30 feet away imposes disadvantage
+func TestAttack_ProneTargetUsesTokenDistance(t *testing.T) {
+	// Disadvantage: 15 and 3, keep 3 + 5 = 8 vs AC 13 (miss)
+	svc, combat, fighter, orc := setupActionTest([]int{14, 2})
+	fighter.EquipmentSlots.MainHand = shortbow()
+	orc.AddCondition(models.ConditionProne, -1, "shove")
+
+	battleMap := models.NewBattleMap("campaign1", "Arena", 20, 20, 5)
+	battleMap.ID = "map1"
+	battleMap.AddToken(*models.NewToken("fighter", 0, 0, models.TokenSizeMedium))
+	battleMap.AddToken(*models.NewToken("orc", 6, 0, models.TokenSizeMedium))
+	combat.MapID = "map1"
+
+	mapStore := new(MockMapStore)
+	mapStore.On("Get", mock.Anything, "map1").Return(battleMap, nil)
+	svc.SetMapStore(mapStore)
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, resp.Result.AttackRoll.Rolls)
+	assert.False(t, resp.Result.Hit)
+	assert.True(t, resp.Result.Modifiers.Disadvantage)
+	assert.Contains(t, resp.Result.Modifiers.Reasons, "disadvantage: target is prone and more than 5 feet away")
+}
+
+// TestAttack_ParalyzedParticipantAutoCrit tests combat-level conditions and auto-crit within 5 feet
+func TestAttack_ParalyzedParticipantAutoCrit(t *testing.T) {
+	// Advantage: 3 and 12, keep 12 + 6 = 18 vs AC 13 (hit, auto-crit); 2d8 = 5 + 3
+	svc, combat, _, orc := setupActionTest([]int{2, 11, 4, 2})
+	combat.Participants[1].AddCondition(models.ConditionParalyzed, 1, "hold person")
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Result.Hit)
+	assert.True(t, resp.Result.Crit)
+	assert.True(t, resp.Result.Modifiers.AutoCrit)
+	assert.Equal(t, 30-resp.Result.Damage, orc.HP.Current)
+}
+
+// TestAttack_DodgeReason tests that turn action effects are reported as reasons
+func TestAttack_DodgeReason(t *testing.T) {
+	svc, combat, _, _ := setupActionTest([]int{18, 1})
+	combat.Participants[1].Actions = models.NewActionBudget(30)
+	combat.Participants[1].Actions.Dodging = true
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+		Advantage:  true,
+	})
+
+	require.NoError(t, err)
+	// Requested advantage and dodging cancel out: a single straight roll
+	assert.True(t, resp.Result.Modifiers.Advantage)
+	assert.True(t, resp.Result.Modifiers.Disadvantage)
+	assert.Equal(t, []int{19}, resp.Result.AttackRoll.Rolls)
+	assert.Contains(t, resp.Result.Modifiers.Reasons, "disadvantage: target is dodging")
+}
+
+// TestDiceService_RollSave_AutoFail tests that paralyzed characters fail Dexterity saves
+func TestDiceService_RollSave_AutoFail(t *testing.T) {
+	mockStore := new(MockCharacterStoreForDice)
+	character := createTestCharacter("char1", "Rogue", "campaign1", 20, 14)
+	character.AddCondition(models.ConditionParalyzed, -1, "hold person")
+	mockStore.On("Get", mock.Anything, "char1").Return(character, nil)
+
+	roller := dice.NewRollerWithSource(&MockRandomSourceForService{values: []int{19}})
+	svc := service.NewDiceServiceWithRoller(mockStore, roller)
+
+	resp, err := svc.RollSave(context.Background(), &service.RollSaveRequest{
+		CharacterID: "char1",
+		Ability:     "dexterity",
+		DC:          10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 20, resp.Result.DiceResult.Rolls[0])
+	assert.False(t, resp.Result.Success)
+	assert.True(t, resp.Modifiers.AutoFail)
+}
+
+// TestDiceService_RollCheck_PoisonedDisadvantage tests that poison imposes disadvantage on checks
+func TestDiceService_RollCheck_PoisonedDisadvantage(t *testing.T) {
+	mockStore := new(MockCharacterStoreForDice)
+	character := createTestCharacter("char1", "Rogue", "campaign1", 20, 14)
+	character.AddCondition(models.ConditionPoisoned, 3, "spider bite")
+	mockStore.On("Get", mock.Anything, "char1").Return(character, nil)
+
+	// Disadvantage: 18 and 4, keep 4
+	roller := dice.NewRollerWithSource(&MockRandomSourceForService{values: []int{17, 3}})
+	svc := service.NewDiceServiceWithRoller(mockStore, roller)
+
+	resp, err := svc.RollCheck(context.Background(), &service.RollCheckRequest{
+		CharacterID: "char1",
+		Ability:     "strength",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, resp.Result.DiceResult.Rolls)
+	assert.True(t, resp.Modifiers.Disadvantage)
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	rulescombat "github.com/dnd-mcp/server/internal/rules/combat"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCoverMap places the fighter and orc on a battle map attached to the combat
+func setupCoverMap(svc *service.CombatService, combat *models.Combat, orcX int) *models.Map {
+	battleMap := models.NewBattleMap("campaign1", "Arena", 20, 20, 5)
+	battleMap.ID = "map1"
+	battleMap.AddToken(*models.NewToken("fighter", 0, 0, models.TokenSizeMedium))
+	battleMap.AddToken(*models.NewToken("orc", orcX, 0, models.TokenSizeMedium))
+	combat.MapID = "map1"
+
+	mapStore := new(MockMapStore)
+	mapStore.On("Get", mock.Anything, "map1").Return(battleMap, nil)
+	svc.SetMapStore(mapStore)
+	return battleMap
+}
+
+// TestAttack_HalfCoverFromCreature tests that an intervening creature adds +2 to the target's AC
+func TestAttack_HalfCoverFromCreature(t *testing.T) {
+	// 8 + 5 = 13 would hit AC 13, but not AC 13 + 2
+	svc, combat, fighter, _ := setupActionTest([]int{7})
+	fighter.EquipmentSlots.MainHand = shortbow()
+	battleMap := setupCoverMap(svc, combat, 2)
+	battleMap.AddToken(*models.NewToken("goblin", 1, 0, models.TokenSizeMedium))
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, rulescombat.CoverHalf, resp.Result.Cover)
+	assert.Equal(t, 15, resp.Result.TargetAC)
+	assert.False(t, resp.Result.Hit)
+	assert.Contains(t, combat.Log[len(combat.Log)-1].Result, "half cover")
+}
+
+// TestAttack_TotalCoverRefused tests that a target behind a wall cannot be attacked and no action is spent
+func TestAttack_TotalCoverRefused(t *testing.T) {
+	svc, combat, _, _ := setupActionTest([]int{19})
+	battleMap := setupCoverMap(svc, combat, 4)
+	battleMap.Walls = models.Walls{models.NewWall("wall", models.WallTypeWall, 2, 0, 2, 20, 0, 0)}
+
+	_, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "total cover")
+	assert.True(t, combat.Participants[0].Actions == nil || combat.Participants[0].Actions.Action)
+}
+
+// TestCastSpell_CoverAddsToDexteritySave tests that cover adds its bonus to DEX saving throws
+func TestCastSpell_CoverAddsToDexteritySave(t *testing.T) {
+	// Save roll 8 + 2 (DEX) + 2 (half cover) = 12 meets DC 12; without cover it would fail
+	svc, combat, fighter, orc := setupActionTest([]int{7, 3, 3, 4})
+	battleMap := setupCoverMap(svc, combat, 2)
+	battleMap.AddToken(*models.NewToken("goblin", 1, 0, models.TokenSizeMedium))
+	fighter.Class = "cleric" // DC 8 + 3 + 1 (WIS)
+
+	resp, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:    "combat1",
+		CasterID:    "fighter",
+		SpellName:   "Thunder Shard",
+		TargetIDs:   []string{"orc"},
+		Damage:      "3d6",
+		DamageType:  "thunder",
+		SaveAbility: "dexterity",
+	})
+
+	require.NoError(t, err)
+	result := resp.Result.Results[0]
+	assert.Equal(t, rulescombat.CoverHalf, result.Cover)
+	assert.Equal(t, 12, result.SaveRoll.DiceResult.Total)
+	assert.True(t, result.Saved)
+	assert.Equal(t, 30-result.Damage, orc.HP.Current)
+}
@@ -433,6 +433,32 @@ func TestDiceService_RollSave(t *testing.T) {
 	}
 }
 
+// TestDiceService_ParticipantConditions tests that checks and saves apply the conditions a
+// character has as a participant of the active combat
+func TestDiceService_ParticipantConditions(t *testing.T) {
+	character := models.NewCharacter("campaign-1", "Test Character", false)
+	character.ID = "char-1"
+	character.Abilities = &models.Abilities{Strength: 16, Dexterity: 14}
+
+	combat := models.NewCombat("campaign-1", []string{"char-1"})
+	combat.Participants[0].Conditions = []models.Condition{{Type: "paralyzed", Duration: 1}}
+
+	mockStore := new(MockCharacterStoreForDice)
+	mockStore.On("Get", mock.Anything, "char-1").Return(character, nil)
+	combatStore := NewMockCombatStore()
+	combatStore.On("GetActive", mock.Anything, "campaign-1").Return(combat, nil)
+
+	roller := dice.NewRollerWithSource(&MockRandomSourceForService{values: []int{19, 19}})
+	svc := service.NewDiceServiceWithRoller(mockStore, roller)
+	svc.SetCombatStore(combatStore)
+
+	// Paralyzed creatures automatically fail Strength and Dexterity saves
+	resp, err := svc.RollSave(context.Background(), &service.RollSaveRequest{CharacterID: "char-1", Ability: "strength", DC: 10})
+	assert.NoError(t, err)
+	assert.True(t, resp.Modifiers.AutoFail)
+	assert.False(t, resp.Result.Success)
+}
+
 func TestDiceService_RollAttack(t *testing.T) {
 	mockRandom := &MockRandomSourceForService{values: []int{9}} // roll 10
 	roller := dice.NewRollerWithSource(mockRandom)
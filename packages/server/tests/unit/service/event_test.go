@@ -0,0 +1,321 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEventStore is an in-memory EventStore assigning sequential cursors
+type memoryEventStore struct {
+	mu     sync.Mutex
+	events []*models.GameEvent
+}
+
+func (m *memoryEventStore) Append(ctx context.Context, event *models.GameEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	event.Cursor = int64(len(m.events) + 1)
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memoryEventStore) List(ctx context.Context, filter *store.EventFilter) ([]*models.GameEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*models.GameEvent, 0)
+	for _, event := range m.events {
+		if event.CampaignID != filter.CampaignID || event.Cursor <= filter.After {
+			continue
+		}
+		if len(filter.Types) > 0 && !containsEventType(filter.Types, event.Type) {
+			continue
+		}
+		result = append(result, event)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryEventStore) Latest(ctx context.Context, campaignID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var cursor int64
+	for _, event := range m.events {
+		if event.CampaignID == campaignID {
+			cursor = event.Cursor
+		}
+	}
+	return cursor, nil
+}
+
+// ofType returns the stored events of one type
+func (m *memoryEventStore) ofType(eventType models.EventType) []*models.GameEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.GameEvent
+	for _, event := range m.events {
+		if event.Type == eventType {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func containsEventType(types []models.EventType, eventType models.EventType) bool {
+	for _, t := range types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEventBus_PublishAssignsCursorsAndDelivers(t *testing.T) {
+	ctx := context.Background()
+	bus := service.NewEventBus(&memoryEventStore{})
+
+	sub := bus.Subscribe("campaign-1", nil)
+	defer sub.Close()
+
+	first := models.NewGameEvent("campaign-1", models.EventDiceRolled, map[string]interface{}{"total": 12})
+	second := models.NewGameEvent("campaign-2", models.EventDiceRolled, nil)
+	third := models.NewGameEvent("campaign-1", models.EventTurnAdvanced, nil)
+	require.NoError(t, bus.Publish(ctx, first))
+	require.NoError(t, bus.Publish(ctx, second))
+	require.NoError(t, bus.Publish(ctx, third))
+
+	assert.Equal(t, int64(1), first.Cursor)
+	assert.Equal(t, int64(3), third.Cursor)
+
+	// Only the subscribed campaign's events are delivered, in cursor order
+	assert.Equal(t, first, <-sub.Events())
+	assert.Equal(t, third, <-sub.Events())
+	assert.Empty(t, sub.Events())
+}
+
+func TestEventBus_SubscribeFiltersTypes(t *testing.T) {
+	ctx := context.Background()
+	bus := service.NewEventBus(&memoryEventStore{})
+
+	sub := bus.Subscribe("campaign-1", []models.EventType{models.EventHPChanged})
+	defer sub.Close()
+
+	require.NoError(t, bus.Publish(ctx, models.NewGameEvent("campaign-1", models.EventDiceRolled, nil)))
+	require.NoError(t, bus.Publish(ctx, models.NewGameEvent("campaign-1", models.EventHPChanged, nil)))
+
+	event := <-sub.Events()
+	assert.Equal(t, models.EventHPChanged, event.Type)
+	assert.Empty(t, sub.Events())
+}
+
+func TestEventBus_SlowSubscriberIsDisconnected(t *testing.T) {
+	ctx := context.Background()
+	bus := service.NewEventBus(&memoryEventStore{})
+	sub := bus.Subscribe("campaign-1", nil)
+
+	// Never read: the buffer fills and the subscription is closed
+	for i := 0; i < 100; i++ {
+		require.NoError(t, bus.Publish(ctx, models.NewGameEvent("campaign-1", models.EventDiceRolled, nil)))
+	}
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	assert.Less(t, received, 100)
+
+	// The missed events can be read back from the last delivered cursor
+	missed, err := bus.Since(ctx, "campaign-1", int64(received), nil, 0)
+	require.NoError(t, err)
+	assert.Len(t, missed, 100-received)
+
+	// Closing again is harmless
+	sub.Close()
+}
+
+func TestEventBus_DeliversAfterCommit(t *testing.T) {
+	bus := service.NewEventBus(&memoryEventStore{})
+	sub := bus.Subscribe("campaign-1", nil)
+	defer sub.Close()
+	tx := &fakeTransactor{}
+
+	// Events of a rolled back transaction never reach subscribers
+	err := tx.InTx(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, bus.Publish(ctx, models.NewGameEvent("campaign-1", models.EventHPChanged, nil)))
+		return errors.New("update failed")
+	})
+	require.Error(t, err)
+	assert.Empty(t, sub.Events())
+
+	// Events of a committed transaction are delivered once it commits
+	err = tx.InTx(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, bus.Publish(ctx, models.NewGameEvent("campaign-1", models.EventTokenMoved, nil)))
+		assert.Empty(t, sub.Events())
+		return nil
+	})
+	require.NoError(t, err)
+	event := <-sub.Events()
+	assert.Equal(t, models.EventTokenMoved, event.Type)
+}
+
+func TestEventBus_SinceAndLatest(t *testing.T) {
+	ctx := context.Background()
+	bus := service.NewEventBus(&memoryEventStore{})
+
+	for _, eventType := range []models.EventType{models.EventTokenMoved, models.EventDiceRolled, models.EventTokenMoved} {
+		require.NoError(t, bus.Publish(ctx, models.NewGameEvent("campaign-1", eventType, nil)))
+	}
+
+	events, err := bus.Since(ctx, "campaign-1", 1, []models.EventType{models.EventTokenMoved}, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(3), events[0].Cursor)
+
+	latest, err := bus.Latest(ctx, "campaign-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), latest)
+
+	latest, err = bus.Latest(ctx, "campaign-2")
+	require.NoError(t, err)
+	assert.Zero(t, latest)
+
+	_, err = bus.Since(ctx, "", 0, nil, 10)
+	assert.Error(t, err)
+	_, err = bus.Since(ctx, "campaign-1", -1, nil, 10)
+	assert.Error(t, err)
+	assert.Error(t, bus.Publish(ctx, models.NewGameEvent("", models.EventDiceRolled, nil)))
+}
+
+func TestCharacterService_ChangeHP_PublishesEvent(t *testing.T) {
+	events := &memoryEventStore{}
+	mockStore := new(MockCharacterStore)
+	charService := service.NewCharacterService(mockStore)
+	charService.SetEventPublisher(service.NewEventBus(events))
+
+	character := &models.Character{
+		ID:         "char-1",
+		CampaignID: "campaign-1",
+		Name:       "Test",
+		HP:         &models.HP{Current: 20, Max: 20},
+	}
+	mockStore.On("Get", mock.Anything, "char-1").Return(character, nil)
+	mockStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Character")).Return(nil)
+
+	_, err := charService.ChangeHP(context.Background(), "char-1", &service.HPChangeRequest{Damage: 7})
+	require.NoError(t, err)
+
+	published := events.ofType(models.EventHPChanged)
+	require.Len(t, published, 1)
+	assert.Equal(t, "campaign-1", published[0].CampaignID)
+	assert.Equal(t, "char-1", published[0].Data["character_id"])
+	assert.Equal(t, 20, published[0].Data["before"])
+	assert.Equal(t, 13, published[0].Data["current"])
+	assert.Equal(t, -7, published[0].Data["change"])
+
+	// A change that leaves HP untouched publishes nothing
+	_, err = charService.ChangeHP(context.Background(), "char-1", &service.HPChangeRequest{})
+	require.NoError(t, err)
+	assert.Len(t, events.ofType(models.EventHPChanged), 1)
+}
+
+func TestConditionService_PublishesConditionEvents(t *testing.T) {
+	events := &memoryEventStore{}
+	mockStore := new(MockCharacterStoreForCondition)
+	conditionService := service.NewConditionService(mockStore)
+	conditionService.SetEventPublisher(service.NewEventBus(events))
+
+	character := models.NewCharacter("campaign-1", "Test Hero", false)
+	character.ID = "char-1"
+	mockStore.On("Get", mock.Anything, "char-1").Return(character, nil)
+	mockStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	ctx := context.Background()
+	_, err := conditionService.ApplyCondition(ctx, &service.ApplyConditionRequest{
+		CharacterID:   "char-1",
+		ConditionType: models.ConditionPoisoned,
+		Source:        "spider bite",
+	})
+	require.NoError(t, err)
+	_, err = conditionService.RemoveCondition(ctx, &service.RemoveConditionRequest{
+		CharacterID: "char-1",
+		RemoveAll:   true,
+	})
+	require.NoError(t, err)
+
+	applied := events.ofType(models.EventConditionApplied)
+	require.Len(t, applied, 1)
+	assert.Equal(t, []string{models.ConditionPoisoned}, applied[0].Data["conditions"])
+	assert.Equal(t, "spider bite", applied[0].Data["source"])
+
+	removed := events.ofType(models.EventConditionRemoved)
+	require.Len(t, removed, 1)
+	assert.Equal(t, []string{models.ConditionPoisoned}, removed[0].Data["conditions"])
+}
+
+func TestDiceService_RollDice_PublishesEventForCampaign(t *testing.T) {
+	events := &memoryEventStore{}
+	diceService := service.NewDiceServiceWithRoller(new(MockCharacterStoreForDice), dice.NewRoller())
+	diceService.SetEventPublisher(service.NewEventBus(events))
+
+	ctx := context.Background()
+	_, err := diceService.RollDice(ctx, &service.RollDiceRequest{Formula: "1d20"})
+	require.NoError(t, err)
+	assert.Empty(t, events.ofType(models.EventDiceRolled), "rolls without a campaign are private")
+
+	resp, err := diceService.RollDice(ctx, &service.RollDiceRequest{Formula: "2d6+1", CampaignID: "campaign-1", Reason: "fall damage"})
+	require.NoError(t, err)
+
+	published := events.ofType(models.EventDiceRolled)
+	require.Len(t, published, 1)
+	assert.Equal(t, "campaign-1", published[0].CampaignID)
+	assert.Equal(t, resp.Result.Total, published[0].Data["total"])
+	assert.Equal(t, "fall damage", published[0].Data["reason"])
+}
+
+func TestCombatService_AdvanceTurn_PublishesEvent(t *testing.T) {
+	events := &memoryEventStore{}
+	mockCombatStore := NewMockCombatStore()
+	mockCharacterStore := new(MockCharacterStoreForCombat)
+	roller := dice.NewRoller()
+	diceSvc := service.NewDiceServiceWithRoller(new(MockCharacterStoreForDice), roller)
+
+	combat := models.NewCombat("campaign1", []string{"char1", "char2"})
+	combat.ID = "combat1"
+	combat.Participants = []models.Participant{
+		{CharacterID: "char1", Initiative: 20},
+		{CharacterID: "char2", Initiative: 10, Conditions: []models.Condition{{Type: models.ConditionBlinded, Duration: 1}}},
+	}
+	char2 := createTestCharacter("char2", "Rogue", "campaign1", 30, 14)
+
+	mockCombatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	mockCharacterStore.On("Get", mock.Anything, "char2").Return(char2, nil)
+	mockCombatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	svc := service.NewCombatServiceWithRoller(mockCombatStore, mockCharacterStore, new(MockCampaignStoreForCombat), NewMockGameStateStore(), diceSvc, roller)
+	svc.SetEventPublisher(service.NewEventBus(events))
+
+	_, err := svc.AdvanceTurn(context.Background(), "combat1")
+	require.NoError(t, err)
+
+	turns := events.ofType(models.EventTurnAdvanced)
+	require.Len(t, turns, 1)
+	assert.Equal(t, "campaign1", turns[0].CampaignID)
+	assert.Equal(t, "char2", turns[0].Data["character_id"])
+	assert.Equal(t, "Rogue", turns[0].Data["name"])
+
+	expired := events.ofType(models.EventConditionRemoved)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "expired", expired[0].Data["source"])
+}
@@ -892,9 +892,9 @@ func TestMapService_MoveToken(t *testing.T) {
 			},
 			expectError: false,
 			validateResult: func(t *testing.T, result *service.TokenMoveResult) {
-				// Diagonal: 2+2=4 squares, with 1.5x multiplier = 6 squares * 5 = 30 feet
-				assert.GreaterOrEqual(t, result.MovementUsed, 20) // Should cost more than straight 4 squares
-				assert.LessOrEqual(t, result.MovementUsed, 30)     // But not more than available
+				// Two diagonals: the second counts as 2 squares = 5 + 10 = 15 feet
+				assert.Equal(t, 15, result.MovementUsed)
+				assert.Len(t, result.Path, 3)
 			},
 		},
 		{
@@ -1137,6 +1137,160 @@ func TestMapService_MoveToken_DifficultTerrain(t *testing.T) {
 	assert.GreaterOrEqual(t, result.MovementUsed, 15)
 }
 
+func TestMapService_MoveToken_Walls(t *testing.T) {
+	newWalledMap := func() *models.Map {
+		battleMap := models.NewBattleMap("campaign-123", "Test Battle", 10, 10, 5)
+		battleMap.ID = "map-001"
+		door := models.NewWall("door-1", models.WallTypeDoor, 2, 4, 2, 5, 0, 1)
+		door.Door = &models.WallDoor{State: models.DoorStateClosed}
+		battleMap.Walls = models.Walls{
+			models.NewWall("wall-top", models.WallTypeWall, 2, 0, 2, 4, 0, 0),
+			models.NewWall("wall-bottom", models.WallTypeWall, 2, 5, 2, 10, 0, 0),
+			door,
+		}
+		token := models.NewToken("char-001", 0, 4, models.TokenSizeMedium)
+		token.ID = "token-001"
+		battleMap.AddToken(*token)
+		return battleMap
+	}
+
+	req := &service.TokenMoveRequest{
+		CampaignID: "campaign-123",
+		MapID:      "map-001",
+		TokenID:    "token-001",
+		ToX:        4,
+		ToY:        4,
+	}
+
+	t.Run("closed door blocks movement", func(t *testing.T) {
+		mapStore := new(MockMapStore)
+		mapStore.On("Get", mock.Anything, "map-001").Return(newWalledMap(), nil)
+
+		svc := service.NewMapService(mapStore, new(MockCampaignStoreForMap), new(MockGameStateStoreForMap))
+		_, err := svc.MoveToken(context.Background(), req)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "blocked")
+		mapStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("open door allows movement", func(t *testing.T) {
+		battleMap := newWalledMap()
+		assert.NoError(t, battleMap.Walls.Get("door-1").Open())
+
+		mapStore := new(MockMapStore)
+		mapStore.On("Get", mock.Anything, "map-001").Return(battleMap, nil)
+		mapStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Map")).Return(nil)
+
+		svc := service.NewMapService(mapStore, new(MockCampaignStoreForMap), new(MockGameStateStoreForMap))
+		result, err := svc.MoveToken(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, 20, result.MovementUsed)
+		assert.Len(t, result.Path, 5)
+	})
+}
+
+func TestMapService_MoveToken_Truncate(t *testing.T) {
+	mapStore := new(MockMapStore)
+	battleMap := models.NewBattleMap("campaign-123", "Test Battle", 20, 20, 5)
+	battleMap.ID = "map-001"
+	token := models.NewToken("char-001", 0, 0, models.TokenSizeMedium)
+	token.ID = "token-001"
+	battleMap.AddToken(*token)
+
+	mapStore.On("Get", mock.Anything, "map-001").Return(battleMap, nil)
+	mapStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Map")).Return(nil)
+
+	svc := service.NewMapService(mapStore, new(MockCampaignStoreForMap), new(MockGameStateStoreForMap))
+	result, err := svc.MoveToken(context.Background(), &service.TokenMoveRequest{
+		CampaignID: "campaign-123",
+		MapID:      "map-001",
+		TokenID:    "token-001",
+		ToX:        10,
+		ToY:        0,
+		Speed:      intPtr(20),
+		Truncate:   true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Truncated)
+	assert.Equal(t, 4, result.Token.Position.X)
+	assert.Equal(t, 20, result.MovementUsed)
+	assert.Equal(t, 0, result.RemainingSpeed)
+}
+
+// TestMapService_MoveToken_TruncateShortOfOccupiedDestination tests that a truncated move is not
+// refused because another creature occupies the requested square it never reaches
+func TestMapService_MoveToken_TruncateShortOfOccupiedDestination(t *testing.T) {
+	mapStore := new(MockMapStore)
+	battleMap := models.NewBattleMap("campaign-123", "Test Battle", 20, 20, 5)
+	battleMap.ID = "map-001"
+	token := models.NewToken("char-001", 0, 0, models.TokenSizeMedium)
+	token.ID = "token-001"
+	battleMap.AddToken(*token)
+	ally := models.NewToken("char-002", 10, 0, models.TokenSizeMedium)
+	ally.ID = "token-002"
+	battleMap.AddToken(*ally)
+
+	mapStore.On("Get", mock.Anything, "map-001").Return(battleMap, nil)
+	mapStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Map")).Return(nil)
+
+	svc := service.NewMapService(mapStore, new(MockCampaignStoreForMap), new(MockGameStateStoreForMap))
+	req := &service.TokenMoveRequest{
+		CampaignID: "campaign-123",
+		MapID:      "map-001",
+		TokenID:    "token-001",
+		ToX:        10,
+		ToY:        0,
+		Speed:      intPtr(60),
+	}
+
+	// A move that stops in the ally's space is refused
+	_, err := svc.MoveToken(context.Background(), req)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "occupied")
+
+	req.Speed = intPtr(20)
+	req.Truncate = true
+	result, err := svc.MoveToken(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, result.Truncated)
+	assert.Equal(t, 4, result.Token.Position.X)
+}
+
+// TestMapService_MoveToken_CharacterSpeed tests that a move without a speed uses the character's walking speed
+func TestMapService_MoveToken_CharacterSpeed(t *testing.T) {
+	mapStore := new(MockMapStore)
+	characterStore := new(MockCharacterStore)
+	battleMap := models.NewBattleMap("campaign-123", "Test Battle", 20, 20, 5)
+	battleMap.ID = "map-001"
+	token := models.NewToken("char-001", 0, 0, models.TokenSizeMedium)
+	token.ID = "token-001"
+	battleMap.AddToken(*token)
+
+	mapStore.On("Get", mock.Anything, "map-001").Return(battleMap, nil)
+	mapStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Map")).Return(nil)
+	characterStore.On("GetByCampaignAndID", mock.Anything, "campaign-123", "char-001").
+		Return(&models.Character{ID: "char-001", CampaignID: "campaign-123", Speed: 25}, nil)
+
+	svc := service.NewMapServiceWithCharacters(mapStore, new(MockCampaignStoreForMap), new(MockGameStateStoreForMap), characterStore)
+	req := &service.TokenMoveRequest{
+		CampaignID: "campaign-123",
+		MapID:      "map-001",
+		TokenID:    "token-001",
+		ToX:        6,
+		ToY:        0,
+	}
+
+	_, err := svc.MoveToken(context.Background(), req)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "have 25 feet")
+
+	req.ToX = 5
+	result, err := svc.MoveToken(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 25, result.MovementUsed)
+	assert.Equal(t, 0, result.RemainingSpeed)
+}
+
 func TestMapService_MoveToken_LargeToken(t *testing.T) {
 	mapStore := new(MockMapStore)
 	campaignStore := new(MockCampaignStoreForMap)
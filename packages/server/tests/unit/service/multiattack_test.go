@@ -0,0 +1,156 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAttack_ExtraAttack tests that a fighter with Extra Attack (2) makes three attacks in one call
+func TestAttack_ExtraAttack(t *testing.T) {
+	// Three hits (15 + 6 vs AC 13), each 1d8 = 5 damage
+	svc, combat, fighter, orc := setupActionTest([]int{14, 4, 14, 4, 14, 4})
+	fighter.Features = []*models.Feature{{Name: "Extra Attack"}, {Name: "Extra Attack (2)"}}
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, resp.Attacks, 3)
+	assert.Equal(t, 15, resp.TotalDamage)
+	assert.Equal(t, 15, orc.HP.Current)
+	assert.Equal(t, 0, resp.AttacksRemaining)
+	assert.False(t, combat.Participants[0].Actions.Action)
+}
+
+// TestAttack_SplitExtraAttack tests splitting Extra Attack across two calls
+func TestAttack_SplitExtraAttack(t *testing.T) {
+	svc, combat, fighter, _ := setupActionTest([]int{14, 4, 14, 4})
+	fighter.Features = []*models.Feature{{Name: "Extra Attack"}}
+	req := &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+		Count:      1,
+	}
+
+	resp, err := svc.Attack(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, resp.Attacks, 1)
+	assert.Equal(t, 1, resp.AttacksRemaining)
+
+	// Second attack continues the same Attack action
+	resp, err = svc.Attack(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, resp.Attacks, 1)
+	assert.Equal(t, 0, combat.Participants[0].Actions.AttacksRemaining)
+
+	// No attacks left
+	_, err = svc.Attack(context.Background(), req)
+	require.Error(t, err)
+}
+
+// TestAttack_TooManyAttacks tests that count cannot exceed the attacks per action
+func TestAttack_TooManyAttacks(t *testing.T) {
+	svc, combat, _, _ := setupActionTest(nil)
+
+	_, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+		Count:      2,
+	})
+
+	require.Error(t, err)
+	assert.True(t, combat.Participants[0].Actions.Action)
+}
+
+// TestAttack_Multiattack tests a monster's Multiattack routine with natural attacks
+func TestAttack_Multiattack(t *testing.T) {
+	// Beak: hit 15 + 6, 1d10+5 = 10; Claws: hit 15 + 6, 2d8+5 = 4 + 4 + 5 = 13
+	svc, _, fighter, orc := setupActionTest([]int{14, 4, 14, 3, 3})
+	fighter.EquipmentSlots = nil
+	fighter.NaturalAttacks = []*models.EquipmentItem{
+		{ID: "beak", Name: "Beak", Damage: "1d10+5", DamageType: "piercing"},
+		{ID: "claws", Name: "Claws", Damage: "2d8+5", DamageType: "slashing"},
+	}
+	fighter.Multiattack = &models.Multiattack{
+		Attacks: []models.MultiattackEntry{{AttackID: "beak", Count: 1}, {AttackID: "claws", Count: 1}},
+	}
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Attacks, 2)
+	assert.Equal(t, "piercing", resp.Attacks[0].DamageType)
+	assert.Equal(t, "slashing", resp.Attacks[1].DamageType)
+	assert.Equal(t, 23, resp.TotalDamage)
+	assert.Equal(t, 7, orc.HP.Current)
+}
+
+// TestAttack_WeaponByID tests choosing a natural attack by ID
+func TestAttack_WeaponByID(t *testing.T) {
+	svc, _, fighter, _ := setupActionTest([]int{14, 4})
+	fighter.NaturalAttacks = []*models.EquipmentItem{
+		{ID: "bite", Name: "Bite", Damage: "1d6", DamageType: "piercing"},
+	}
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+		WeaponID:   "bite",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "piercing", resp.Result.DamageType)
+
+	_, err = svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+		WeaponID:   "tail",
+	})
+	require.Error(t, err)
+}
+
+// TestAttack_TwoWeaponFighting tests the off-hand bonus action attack
+func TestAttack_TwoWeaponFighting(t *testing.T) {
+	svc, combat, fighter, _ := setupActionTest([]int{14, 4, 14, 2})
+	fighter.EquipmentSlots = &models.EquipmentSlots{
+		MainHand: &models.EquipmentItem{ID: "shortsword", Name: "Shortsword", Type: models.EquipmentTypeWeapon, Damage: "1d6", DamageType: "piercing", Properties: []string{"finesse", "light"}},
+		OffHand:  &models.EquipmentItem{ID: "handaxe", Name: "Handaxe", Type: models.EquipmentTypeWeapon, Damage: "1d6", DamageType: "slashing", Properties: []string{"light", "thrown"}},
+	}
+	offHandReq := &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+		OffHand:    true,
+	}
+
+	// Off-hand attack requires the Attack action first
+	_, err := svc.Attack(context.Background(), offHandReq)
+	require.Error(t, err)
+
+	_, err = svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+	require.NoError(t, err)
+
+	resp, err := svc.Attack(context.Background(), offHandReq)
+	require.NoError(t, err)
+	assert.Equal(t, "slashing", resp.Result.DamageType)
+	assert.False(t, combat.Participants[0].Actions.BonusAction)
+}
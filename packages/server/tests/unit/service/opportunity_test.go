@@ -0,0 +1,230 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// shortbow returns a ranged weapon with an 80/320 foot range
+func shortbow() *models.EquipmentItem {
+	return &models.EquipmentItem{
+		ID:         "shortbow",
+		Name:       "Shortbow",
+		Type:       models.EquipmentTypeWeapon,
+		Damage:     "1d6",
+		DamageType: "piercing",
+		Range:      "80/320",
+		Properties: []string{"ammunition", "two-handed"},
+	}
+}
+
+// setupOpportunityMap places the friendly fighter and hostile orc on a battle map and
+// returns a map service that moves tokens within the same combat
+func setupOpportunityMap(svc *service.CombatService, combat *models.Combat, fighterPos, orcPos models.Position) (*service.MapService, *models.Map) {
+	battleMap := setupCoverMap(svc, combat, orcPos.X)
+	battleMap.Tokens[0].Position = fighterPos
+	battleMap.Tokens[0].Disposition = models.DispositionFriendly
+	battleMap.Tokens[1].Position = orcPos
+	battleMap.Tokens[1].Disposition = models.DispositionHostile
+
+	mapStore := new(MockMapStore)
+	mapStore.On("Get", mock.Anything, "map1").Return(battleMap, nil)
+	mapStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+	gameStateStore := new(MockGameStateStoreForMap)
+	gameState := models.NewGameState("campaign1")
+	gameState.SetCombat("combat1")
+	gameStateStore.On("Get", mock.Anything, "campaign1").Return(gameState, nil)
+	combatStore := NewMockCombatStore()
+	combatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	combatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	mapSvc := service.NewMapService(mapStore, new(MockCampaignStoreForMap), gameStateStore)
+	mapSvc.SetCombatStore(combatStore)
+	mapSvc.SetOpportunityAttackHandler(svc)
+	return mapSvc, battleMap
+}
+
+// moveToken moves a character's token on the opportunity test map
+func moveToken(mapSvc *service.MapService, battleMap *models.Map, characterID string, toX, toY int) (*service.TokenMoveResult, error) {
+	return mapSvc.MoveToken(context.Background(), &service.TokenMoveRequest{
+		CampaignID: "campaign1",
+		MapID:      "map1",
+		TokenID:    battleMap.GetTokenByCharacterID(characterID).ID,
+		ToX:        toX,
+		ToY:        toY,
+	})
+}
+
+// TestAttack_OutOfReachRefused tests that a melee attack beyond reach is refused without spending the action
+func TestAttack_OutOfReachRefused(t *testing.T) {
+	svc, combat, _, _ := setupActionTest([]int{19})
+	setupCoverMap(svc, combat, 3)
+
+	_, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "out of range")
+	assert.Contains(t, err.Error(), "beyond 5 feet reach")
+	assert.True(t, combat.Participants[0].Actions.Action)
+}
+
+// TestAttack_LongRangeDisadvantage tests that a ranged attack beyond normal range has disadvantage
+func TestAttack_LongRangeDisadvantage(t *testing.T) {
+	svc, combat, fighter, _ := setupActionTest([]int{15, 5, 3})
+	fighter.EquipmentSlots.MainHand = shortbow()
+	setupCoverMap(svc, combat, 18)
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Result.Modifiers.Disadvantage)
+	assert.Contains(t, resp.Result.Modifiers.Reasons, "disadvantage: target is 90 feet away, beyond normal range")
+	assert.Equal(t, 6, resp.Result.AttackRoll.Rolls[0])
+}
+
+// TestAttack_RangedWithAdjacentHostile tests that a ranged attack has disadvantage with a hostile creature adjacent
+func TestAttack_RangedWithAdjacentHostile(t *testing.T) {
+	svc, combat, fighter, _ := setupActionTest([]int{15, 5, 3})
+	fighter.EquipmentSlots.MainHand = shortbow()
+	setupOpportunityMap(svc, combat, models.Position{X: 0, Y: 0}, models.Position{X: 1, Y: 0})
+
+	resp, err := svc.Attack(context.Background(), &service.AttackRequest{
+		CombatID:   "combat1",
+		AttackerID: "fighter",
+		TargetID:   "orc",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Result.Modifiers.Disadvantage)
+	assert.Contains(t, resp.Result.Modifiers.Reasons, "disadvantage: hostile creature within 5 feet of a ranged attacker")
+}
+
+// TestMoveToken_NPCOpportunityAttack tests that leaving an NPC's reach triggers an immediate opportunity attack
+func TestMoveToken_NPCOpportunityAttack(t *testing.T) {
+	// Orc rolls 20 to hit, then damage
+	svc, combat, fighter, orc := setupActionTest([]int{19, 0, 0})
+	orc.IsNPC = true
+	mapSvc, battleMap := setupOpportunityMap(svc, combat, models.Position{X: 0, Y: 0}, models.Position{X: 1, Y: 0})
+
+	result, err := moveToken(mapSvc, battleMap, "fighter", 0, 4)
+
+	require.NoError(t, err)
+	require.Len(t, result.OpportunityAttacks, 1)
+	opportunity := result.OpportunityAttacks[0]
+	assert.Equal(t, "orc", opportunity.AttackerID)
+	assert.True(t, opportunity.Resolved)
+	assert.True(t, opportunity.Result.Hit)
+	assert.Less(t, fighter.HP.Current, 40)
+	assert.False(t, combat.Participants[1].Actions.Reaction)
+	assert.Equal(t, "opportunity_attack", combat.Log[len(combat.Log)-2].Action)
+	assert.Equal(t, models.Position{X: 0, Y: 4}, battleMap.GetTokenByCharacterID("fighter").Position)
+}
+
+// TestMoveToken_OpportunityAttackDropsMover tests that a mover dropped to 0 HP stops where it was hit
+func TestMoveToken_OpportunityAttackDropsMover(t *testing.T) {
+	svc, combat, fighter, orc := setupActionTest([]int{19, 0, 0})
+	orc.IsNPC = true
+	fighter.HP.Current = 1
+	mapSvc, battleMap := setupOpportunityMap(svc, combat, models.Position{X: 0, Y: 0}, models.Position{X: 1, Y: 0})
+
+	result, err := moveToken(mapSvc, battleMap, "fighter", 0, 4)
+
+	require.NoError(t, err)
+	require.Len(t, result.OpportunityAttacks, 1)
+	assert.True(t, result.OpportunityAttacks[0].Result.TargetDown)
+	assert.True(t, result.Truncated)
+	assert.Equal(t, result.OpportunityAttacks[0].Position, battleMap.GetTokenByCharacterID("fighter").Position)
+}
+
+// TestMoveToken_OpportunityAttackRolledBackWithMove tests that an opportunity attack is saved in
+// the move's transaction and its events are not delivered when the move fails
+func TestMoveToken_OpportunityAttackRolledBackWithMove(t *testing.T) {
+	svc, combat, _, orc := setupActionTest([]int{19, 0, 0})
+	orc.IsNPC = true
+	bus := service.NewEventBus(&memoryEventStore{})
+	svc.SetEventPublisher(bus)
+	sub := bus.Subscribe("campaign1", nil)
+	defer sub.Close()
+	_, battleMap := setupOpportunityMap(svc, combat, models.Position{X: 0, Y: 0}, models.Position{X: 1, Y: 0})
+
+	mapStore := new(MockMapStore)
+	mapStore.On("Get", mock.Anything, "map1").Return(battleMap, nil)
+	mapStore.On("Update", mock.Anything, mock.Anything).Return(errors.New("database unavailable"))
+	gameStateStore := new(MockGameStateStoreForMap)
+	gameState := models.NewGameState("campaign1")
+	gameState.SetCombat("combat1")
+	gameStateStore.On("Get", mock.Anything, "campaign1").Return(gameState, nil)
+	combatStore := NewMockCombatStore()
+	combatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	combatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	tx := &fakeTransactor{}
+	mapSvc := service.NewMapService(mapStore, new(MockCampaignStoreForMap), gameStateStore)
+	mapSvc.SetCombatStore(combatStore)
+	mapSvc.SetOpportunityAttackHandler(svc)
+	mapSvc.SetTransactor(tx)
+
+	_, err := moveToken(mapSvc, battleMap, "fighter", 0, 4)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update map")
+	assert.Equal(t, 1, tx.calls)
+	assert.False(t, combat.Participants[1].Actions.Reaction, "the opportunity attack ran inside the transaction")
+	assert.Empty(t, sub.Events())
+}
+
+// TestMoveToken_DisengageSuppressesOpportunityAttacks tests that Disengage prevents opportunity attacks
+func TestMoveToken_DisengageSuppressesOpportunityAttacks(t *testing.T) {
+	svc, combat, _, orc := setupActionTest([]int{19, 0, 0})
+	orc.IsNPC = true
+	mapSvc, battleMap := setupOpportunityMap(svc, combat, models.Position{X: 0, Y: 0}, models.Position{X: 1, Y: 0})
+
+	_, err := svc.Disengage(context.Background(), &service.TurnActionRequest{CombatID: "combat1", CharacterID: "fighter"})
+	require.NoError(t, err)
+
+	result, err := moveToken(mapSvc, battleMap, "fighter", 0, 4)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.OpportunityAttacks)
+	assert.True(t, combat.Participants[1].Actions == nil || combat.Participants[1].Actions.Reaction)
+}
+
+// TestOpportunityAttack_PlayerOffer tests that a player character is offered an opportunity attack to take once
+func TestOpportunityAttack_PlayerOffer(t *testing.T) {
+	svc, combat, _, orc := setupActionTest([]int{19, 0, 0})
+	orc.IsNPC = true
+	combat.TurnIndex = 1
+	mapSvc, battleMap := setupOpportunityMap(svc, combat, models.Position{X: 0, Y: 0}, models.Position{X: 1, Y: 0})
+
+	result, err := moveToken(mapSvc, battleMap, "orc", 5, 0)
+	require.NoError(t, err)
+	require.Len(t, result.OpportunityAttacks, 1)
+	assert.Equal(t, "fighter", result.OpportunityAttacks[0].AttackerID)
+	assert.False(t, result.OpportunityAttacks[0].Resolved)
+
+	req := &service.OpportunityAttackRequest{CombatID: "combat1", AttackerID: "fighter", TargetID: "orc"}
+	resp, err := svc.OpportunityAttack(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, resp.Result.Hit)
+	assert.Less(t, orc.HP.Current, 30)
+	assert.False(t, combat.Participants[0].Actions.Reaction)
+
+	_, err = svc.OpportunityAttack(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no opportunity attack")
+}
@@ -4,6 +4,7 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/dnd-mcp/server/internal/models"
@@ -14,7 +15,7 @@ import (
 )
 
 var (
-	ErrCharacterNotFound = errors.New("character not found")
+	ErrCharacterNotFound = fmt.Errorf("character: %w", store.ErrNotFound)
 	ErrGameStateNotFound  = errors.New("game state not found")
 )
 
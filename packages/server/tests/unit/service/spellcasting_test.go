@@ -0,0 +1,356 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/rules/dice"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestWizard creates a level 5 wizard (INT 16, spell save DC 14, spell attack +6)
+// with Fireball and Fire Bolt in the spellbook
+func createTestWizard() *models.Character {
+	wizard := createTestCharacter("caster", "Wizard", "campaign1", 28, 12)
+	wizard.Class = "Wizard"
+	wizard.Abilities.Intelligence = 16
+
+	spellbook := models.NewSpellbook()
+	spellbook.SpellcastingAbility = "intelligence"
+	spellbook.Slots[3] = models.NewSpellSlots(2)
+	spellbook.Slots[4] = models.NewSpellSlots(1)
+	spellbook.AddSpell(&models.Spell{
+		ID:    "fireball",
+		Name:  "Fireball",
+		Level: 3,
+		Damage: &models.SpellDamage{
+			DamageType: "fire",
+			BaseDamage: "8d6",
+		},
+		Save:         &models.SpellSave{Ability: "dexterity", DamageHalf: true},
+		HigherLevels: "When you cast this spell using a spell slot of 4th level or higher, the damage increases by 1d6 for each slot level above 3rd.",
+		AreaOfEffect: &models.AreaOfEffect{Type: "sphere", Size: 20},
+	})
+	spellbook.AddSpell(&models.Spell{
+		ID:         "fire_bolt",
+		Name:       "Fire Bolt",
+		Level:      0,
+		AttackType: models.SpellAttackRanged,
+		Damage: &models.SpellDamage{
+			DamageType: "fire",
+			BaseDamage: "1d10",
+		},
+	})
+	wizard.Spellbook = spellbook
+	return wizard
+}
+
+// setupSpellcastingTest wires a combat service with the given caster and target
+func setupSpellcastingTest(values []int, caster, target *models.Character) (*service.CombatService, *MockCombatStore, *MockCharacterStoreForCombat) {
+	mockCombatStore := NewMockCombatStore()
+	mockCampaignStore := new(MockCampaignStoreForCombat)
+	mockCharacterStore := new(MockCharacterStoreForCombat)
+	mockGameStateStore := NewMockGameStateStore()
+	mockDiceStore := new(MockCharacterStoreForDice)
+	roller := dice.NewRollerWithSource(&MockRandomSourceForCombat{values: values})
+	diceSvc := service.NewDiceServiceWithRoller(mockDiceStore, roller)
+
+	combat := models.NewCombat("campaign1", []string{caster.ID, target.ID})
+	combat.ID = "combat1"
+	combat.Participants = []models.Participant{
+		{CharacterID: caster.ID, Initiative: 20},
+		{CharacterID: target.ID, Initiative: 10},
+	}
+
+	mockCombatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	mockCombatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockCharacterStore.On("Get", mock.Anything, caster.ID).Return(caster, nil)
+	mockCharacterStore.On("Get", mock.Anything, target.ID).Return(target, nil)
+	mockCharacterStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	svc := service.NewCombatServiceWithRoller(
+		mockCombatStore,
+		mockCharacterStore,
+		mockCampaignStore,
+		mockGameStateStore,
+		diceSvc,
+		roller,
+	)
+	return svc, mockCombatStore, mockCharacterStore
+}
+
+// TestCastSpell_SaveForHalfDamage tests that a successful DEX save halves Fireball damage
+// and that a level 3 slot is spent
+func TestCastSpell_SaveForHalfDamage(t *testing.T) {
+	caster := createTestWizard()
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	// 8d6 all rolling 3 = 24 damage, then DEX save roll 18 + 2 = 20 vs DC 14 (success)
+	values := []int{2, 2, 2, 2, 2, 2, 2, 2, 17}
+	svc, _, _ := setupSpellcastingTest(values, caster, target)
+
+	resp, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fireball",
+		TargetIDs: []string{"target"},
+	})
+
+	require.NoError(t, err)
+	result := resp.Result
+	assert.Equal(t, 3, result.Level)
+	assert.True(t, result.SlotUsed)
+	assert.Equal(t, 1, result.SlotsRemaining)
+	assert.Equal(t, 14, result.SaveDC)
+	assert.Equal(t, "dexterity", result.SaveAbility)
+	assert.Equal(t, "fire", result.DamageType)
+	require.Len(t, result.Results, 1)
+	assert.True(t, result.Results[0].Saved)
+	assert.Equal(t, 12, result.Results[0].Damage)
+	assert.Equal(t, 18, target.HP.Current)
+	assert.Equal(t, 1, caster.Spellbook.Slots[3].Used)
+}
+
+// TestCastSpell_Upcast tests that casting Fireball with a level 4 slot adds 1d6
+func TestCastSpell_Upcast(t *testing.T) {
+	caster := createTestWizard()
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	// 9d6 all rolling 1 = 9 damage, then DEX save roll 1 + 2 = 3 (failure)
+	values := []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	svc, _, _ := setupSpellcastingTest(values, caster, target)
+
+	resp, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellName: "fireball",
+		TargetIDs: []string{"target"},
+		Level:     4,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Result.Upcast)
+	assert.Equal(t, "9d6", resp.Result.Formula)
+	assert.False(t, resp.Result.Results[0].Saved)
+	assert.Equal(t, 9, resp.Result.Results[0].Damage)
+	assert.Equal(t, 0, resp.Result.SlotsRemaining)
+	assert.Equal(t, 0, caster.Spellbook.Slots[3].Used)
+	assert.Equal(t, 1, caster.Spellbook.Slots[4].Used)
+}
+
+// TestCastSpell_NoSlotsRemaining tests that casting fails when slots are exhausted
+func TestCastSpell_NoSlotsRemaining(t *testing.T) {
+	caster := createTestWizard()
+	caster.Spellbook.Slots[3].Used = 2
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	svc, mockCombatStore, _ := setupSpellcastingTest(nil, caster, target)
+
+	_, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fireball",
+		TargetIDs: []string{"target"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no level 3 spell slots remaining")
+	assert.Equal(t, 30, target.HP.Current)
+	mockCombatStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestCastSpell_BelowSpellLevel tests that a spell cannot be cast with a lower slot
+func TestCastSpell_BelowSpellLevel(t *testing.T) {
+	caster := createTestWizard()
+	caster.Spellbook.Slots[1] = models.NewSpellSlots(4)
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	svc, _, _ := setupSpellcastingTest(nil, caster, target)
+
+	_, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fireball",
+		TargetIDs: []string{"target"},
+		Level:     1,
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 0, caster.Spellbook.Slots[1].Used)
+}
+
+// TestCastSpell_UnknownSpell tests that a caster cannot cast a spell outside their spellbook
+func TestCastSpell_UnknownSpell(t *testing.T) {
+	caster := createTestWizard()
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	svc, _, _ := setupSpellcastingTest(nil, caster, target)
+
+	_, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "meteor_swarm",
+		TargetIDs: []string{"target"},
+		Level:     9,
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not know")
+}
+
+// TestCastSpell_NotPrepared tests that preparing casters must prepare leveled spells
+func TestCastSpell_NotPrepared(t *testing.T) {
+	caster := createTestWizard()
+	caster.Spellbook.PrepareSpell("shield", 1)
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	svc, _, _ := setupSpellcastingTest(nil, caster, target)
+
+	_, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fireball",
+		TargetIDs: []string{"target"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has not prepared")
+	assert.Equal(t, 0, caster.Spellbook.Slots[3].Used)
+}
+
+// TestCastSpell_SpellAttackMiss tests a cantrip spell attack that misses and spends no slot
+func TestCastSpell_SpellAttackMiss(t *testing.T) {
+	caster := createTestWizard()
+	target := createTestCharacter("target", "Knight", "campaign1", 30, 18)
+	// Attack roll 2 + 6 = 8 vs AC 18 (miss)
+	values := []int{1}
+	svc, _, _ := setupSpellcastingTest(values, caster, target)
+
+	resp, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fire_bolt",
+		TargetIDs: []string{"target"},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, resp.Result.SlotUsed)
+	assert.Equal(t, 6, resp.Result.AttackBonus)
+	// Level 5 caster: cantrip scales to 2d10
+	assert.Equal(t, "2d10", resp.Result.Formula)
+	require.Len(t, resp.Result.Results, 1)
+	assert.False(t, resp.Result.Results[0].Hit)
+	assert.Equal(t, 0, resp.Result.Results[0].Damage)
+	assert.Equal(t, 30, target.HP.Current)
+}
+
+// TestCastSpell_DamageImmunity tests that an immune target takes no damage from a spell
+func TestCastSpell_DamageImmunity(t *testing.T) {
+	caster := createTestWizard()
+	target := createTestCharacter("target", "Fire Elemental", "campaign1", 30, 13)
+	target.Traits = models.NewTraits()
+	target.Traits.AddImmunity("fire")
+	// 8d6 all rolling 3 = 24 damage, then DEX save roll 1 + 2 = 3 (failure)
+	values := []int{2, 2, 2, 2, 2, 2, 2, 2, 0}
+	svc, _, _ := setupSpellcastingTest(values, caster, target)
+
+	resp, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fireball",
+		TargetIDs: []string{"target"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Result.Results, 1)
+	assert.Equal(t, 24, resp.Result.Results[0].RawDamage)
+	assert.Equal(t, 0, resp.Result.Results[0].Damage)
+	assert.Equal(t, 30, target.HP.Current)
+}
+
+// TestCastSpell_UnknownTarget tests that an unknown target is refused before a slot is spent
+func TestCastSpell_UnknownTarget(t *testing.T) {
+	caster := createTestWizard()
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	svc, combatStore, characterStore := setupSpellcastingTest(nil, caster, target)
+	characterStore.On("Get", mock.Anything, "ghost").Return(nil, errors.New("character not found"))
+
+	_, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fireball",
+		TargetIDs: []string{"target", "ghost"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ghost")
+	assert.Equal(t, 0, caster.Spellbook.Slots[3].Used)
+	characterStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	combatStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestCastSpell_KnownSpellWithoutDataNeedsLevel tests that a known spell without spellbook
+// data is not cast as a free cantrip
+func TestCastSpell_KnownSpellWithoutDataNeedsLevel(t *testing.T) {
+	caster := createTestWizard()
+	caster.Spellbook.KnownSpells = map[int][]string{3: {"lightning_bolt"}}
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	svc, _, _ := setupSpellcastingTest([]int{2, 2, 2, 2, 2, 2, 2, 2, 0}, caster, target)
+
+	req := &service.CastSpellRequest{
+		CombatID:    "combat1",
+		CasterID:    "caster",
+		SpellID:     "lightning_bolt",
+		TargetIDs:   []string{"target"},
+		Damage:      "8d6",
+		DamageType:  "lightning",
+		SaveAbility: "dexterity",
+	}
+	_, err := svc.CastSpell(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spell level")
+	assert.Equal(t, 0, caster.Spellbook.Slots[3].Used)
+
+	req.Level = 3
+	resp, err := svc.CastSpell(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, resp.Result.SlotUsed)
+	assert.Equal(t, 1, caster.Spellbook.Slots[3].Used)
+}
+
+// TestCastSpell_TargetUpdateFails tests that a failed target update fails the cast in its transaction
+func TestCastSpell_TargetUpdateFails(t *testing.T) {
+	caster := createTestWizard()
+	target := createTestCharacter("target", "Orc", "campaign1", 30, 13)
+	roller := dice.NewRollerWithSource(&MockRandomSourceForCombat{values: []int{2, 2, 2, 2, 2, 2, 2, 2, 0}})
+
+	combat := models.NewCombat("campaign1", []string{caster.ID, target.ID})
+	combat.ID = "combat1"
+	combat.Participants = []models.Participant{
+		{CharacterID: caster.ID, Initiative: 20},
+		{CharacterID: target.ID, Initiative: 10},
+	}
+	combatStore := NewMockCombatStore()
+	combatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	characterStore := new(MockCharacterStoreForCombat)
+	characterStore.On("Get", mock.Anything, caster.ID).Return(caster, nil)
+	characterStore.On("Get", mock.Anything, target.ID).Return(target, nil)
+	characterStore.On("Update", mock.Anything, caster).Return(nil)
+	characterStore.On("Update", mock.Anything, target).Return(errors.New("database unavailable"))
+
+	tx := &fakeTransactor{}
+	svc := service.NewCombatServiceWithRoller(combatStore, characterStore, new(MockCampaignStoreForCombat), NewMockGameStateStore(),
+		service.NewDiceServiceWithRoller(new(MockCharacterStoreForDice), roller), roller)
+	svc.SetTransactor(tx)
+
+	_, err := svc.CastSpell(context.Background(), &service.CastSpellRequest{
+		CombatID:  "combat1",
+		CasterID:  "caster",
+		SpellID:   "fireball",
+		TargetIDs: []string{"target"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update target")
+	assert.Equal(t, 1, tx.calls)
+	combatStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -450,3 +450,58 @@ func TestCombatService_EndTurn_LogEntry(t *testing.T) {
 	}
 	assert.True(t, foundNewRoundLog, "should have log entry for new round")
 }
+
+// TestCombatService_EndTurn_DeathSave tests that a dying player character rolls a death save when their turn starts
+func TestCombatService_EndTurn_DeathSave(t *testing.T) {
+	mockCombatStore := NewMockCombatStore()
+	mockCampaignStore := new(MockCampaignStoreForCombat)
+	mockCharacterStore := new(MockCharacterStoreForCombat)
+	mockDiceStore := new(MockCharacterStoreForDice)
+	roller := dice.NewRollerWithSource(&MockRandomSourceForCombat{values: []int{4}}) // d20 = 5
+	diceSvc := service.NewDiceServiceWithRoller(mockDiceStore, roller)
+
+	combat := models.NewCombat("campaign1", []string{"char1", "char2"})
+	combat.ID = "combat1"
+	combat.Participants = []models.Participant{
+		{CharacterID: "char1", Initiative: 20, HasActed: false},
+		{CharacterID: "char2", Initiative: 10, HasActed: false},
+	}
+
+	dying := createTestCharacter("char2", "Rogue", "campaign1", 30, 14)
+	dying.HP.Current = 0
+
+	mockCombatStore.On("Get", mock.Anything, "combat1").Return(combat, nil)
+	mockCharacterStore.On("Get", mock.Anything, "char2").Return(dying, nil)
+	mockCharacterStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockCombatStore.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	svc := service.NewCombatServiceWithRoller(
+		mockCombatStore,
+		mockCharacterStore,
+		mockCampaignStore,
+		NewMockGameStateStore(),
+		diceSvc,
+		roller,
+	)
+
+	resp, err := svc.AdvanceTurn(context.Background(), "combat1")
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp.DeathSave) {
+		assert.Equal(t, 5, resp.DeathSave.Roll)
+		assert.Equal(t, "failure", resp.DeathSave.Result)
+		assert.Equal(t, 1, resp.DeathSave.Failures)
+	}
+	assert.Equal(t, 1, dying.GetDeathSaves().Failures)
+	mockCharacterStore.AssertCalled(t, "Update", mock.Anything, dying)
+
+	var foundDeathSaveLog bool
+	for _, entry := range resp.Combat.Log {
+		if entry.Action == "death_save" {
+			foundDeathSaveLog = true
+			assert.Equal(t, "char2", entry.ActorID)
+			assert.Contains(t, entry.Result, "Rogue rolled 5 on a death save")
+		}
+	}
+	assert.True(t, foundDeathSaveLog, "should have log entry for the death save")
+}
@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupVisionTest creates a lit 10x10 map with a friendly hero at (0,0), a hostile goblin
+// at (4,0), a hostile rogue at (6,0) that hides in combat, a DM-hidden ambusher and a secret door
+func setupVisionTest(stealthRoll int) (*service.VisionService, *models.Map, *models.Character) {
+	battleMap := models.NewBattleMap("campaign-1", "Crypt", 10, 10, 5)
+	battleMap.ID = "map-1"
+
+	addToken := func(id string, x, y int, disposition models.TokenDisposition) *models.Token {
+		token := models.NewToken(id, x, y, models.TokenSizeMedium)
+		token.ID = "token-" + id
+		token.Disposition = disposition
+		battleMap.Tokens = append(battleMap.Tokens, *token)
+		return &battleMap.Tokens[len(battleMap.Tokens)-1]
+	}
+	addToken("hero", 0, 0, models.DispositionFriendly)
+	addToken("goblin", 4, 0, models.DispositionHostile)
+	addToken("rogue", 6, 0, models.DispositionHostile)
+	addToken("ambusher", 2, 2, models.DispositionHostile).Hidden = true
+
+	secret := models.NewWall("secret-door", models.WallTypeDoor, 9, 0, 9, 1, 0, 0)
+	secret.Door = &models.WallDoor{State: models.DoorStateClosed, Secret: true}
+	battleMap.Walls = models.Walls{secret}
+
+	mapStore := new(MockMapStore)
+	mapStore.On("Get", mock.Anything, "map-1").Return(battleMap, nil)
+
+	characterStore := new(MockCharacterStoreForCombat)
+	hero := createTestCharacter("hero", "Hero", "campaign-1", 30, 15)
+	characterStore.On("Get", mock.Anything, "hero").Return(hero, nil)
+	characterStore.On("Get", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+
+	combat := models.NewCombat("campaign-1", []string{"hero", "goblin", "rogue"})
+	rogue := combat.GetParticipantByCharacterID("rogue")
+	rogue.Hidden = true
+	rogue.StealthRoll = stealthRoll
+
+	combatStore := NewMockCombatStore()
+	combatStore.On("GetActive", mock.Anything, "campaign-1").Return(combat, nil)
+
+	svc := service.NewVisionService(mapStore, characterStore, combatStore, service.NewDiceService(characterStore))
+	return svc, battleMap, hero
+}
+
+func TestVisionService_CanSee(t *testing.T) {
+	svc, _, _ := setupVisionTest(15)
+
+	check, err := svc.CanSee(context.Background(), "map-1", "token-hero", "token-goblin")
+	require.NoError(t, err)
+	assert.True(t, check.CanSee)
+	assert.Equal(t, "sight", check.Sense)
+	assert.Equal(t, 20, check.Distance)
+
+	// Stealth 15 beats passive Perception 11
+	check, err = svc.CanSee(context.Background(), "map-1", "token-hero", "token-rogue")
+	require.NoError(t, err)
+	assert.False(t, check.CanSee)
+	assert.Contains(t, check.Reason, "hidden")
+
+	_, err = svc.CanSee(context.Background(), "map-1", "token-hero", "missing")
+	assert.Error(t, err)
+}
+
+func TestVisionService_CanSee_PoorStealth(t *testing.T) {
+	svc, _, _ := setupVisionTest(8)
+
+	check, err := svc.CanSee(context.Background(), "map-1", "token-hero", "token-rogue")
+	require.NoError(t, err)
+	assert.True(t, check.CanSee)
+}
+
+func TestVisionService_CanSee_Invisible(t *testing.T) {
+	svc, _, hero := setupVisionTest(0)
+
+	check, err := svc.CanSee(context.Background(), "map-1", "token-goblin", "token-hero")
+	require.NoError(t, err)
+	assert.True(t, check.CanSee)
+
+	hero.AddCondition(models.ConditionInvisible, 10, "spell")
+	check, err = svc.CanSee(context.Background(), "map-1", "token-goblin", "token-hero")
+	require.NoError(t, err)
+	assert.False(t, check.CanSee)
+	assert.Equal(t, "invisible", check.Reason)
+
+	area, err := svc.GetVisibleArea(context.Background(), "map-1", "token-hero")
+	require.NoError(t, err)
+	assert.Len(t, area.Cells, 99, "an invisible creature still sees normally; the secret door hides one cell")
+}
+
+func TestVisionService_GetVisibleArea_Errors(t *testing.T) {
+	svc, _, _ := setupVisionTest(0)
+
+	_, err := svc.GetVisibleArea(context.Background(), "", "token-hero")
+	assert.Error(t, err)
+
+	_, err = svc.GetVisibleArea(context.Background(), "map-1", "missing")
+	assert.Error(t, err)
+}
+
+func TestVisionService_GetVisibleArea_Darkness(t *testing.T) {
+	svc, battleMap, _ := setupVisionTest(0)
+	battleMap.AmbientLight = models.LightDarkness
+	battleMap.Lights = []models.LightSource{{ID: "torch", Position: models.Position{X: 0, Y: 0}, Bright: 10, Dim: 20}}
+
+	area, err := svc.GetVisibleArea(context.Background(), "map-1", "token-hero")
+	require.NoError(t, err)
+
+	ids := make([]string, 0)
+	for _, token := range area.Tokens {
+		ids = append(ids, token.TokenID)
+	}
+	// The goblin stands at the edge of the torch's dim light; the rogue is in darkness
+	assert.Contains(t, ids, "token-goblin")
+	assert.NotContains(t, ids, "token-rogue")
+	assert.Len(t, area.Cells, 25)
+}
+
+func TestVisionService_FilterBattleMapForCharacter(t *testing.T) {
+	svc, battleMap, _ := setupVisionTest(15)
+
+	view, err := svc.FilterBattleMapForCharacter(context.Background(), battleMap, "hero")
+	require.NoError(t, err)
+
+	ids := make([]string, 0)
+	for _, token := range view.Map.Tokens {
+		ids = append(ids, token.ID)
+	}
+	assert.ElementsMatch(t, []string{"token-hero", "token-goblin"}, ids)
+	assert.Empty(t, view.Map.Walls, "secret doors are not revealed")
+	assert.Len(t, battleMap.Tokens, 4, "the stored map is not modified")
+
+	_, err = svc.FilterBattleMapForCharacter(context.Background(), battleMap, "stranger")
+	assert.Error(t, err)
+}
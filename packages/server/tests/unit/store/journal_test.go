@@ -0,0 +1,93 @@
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *fakeCharacterStore) Create(ctx context.Context, character *models.Character) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.characters[character.ID] = character
+	return nil
+}
+
+func characterState(t *testing.T, raw json.RawMessage) *models.Character {
+	t.Helper()
+	var character models.Character
+	require.NoError(t, json.Unmarshal(raw, &character))
+	return &character
+}
+
+func TestJournalCharacterStore_RecordsFirstAndLastState(t *testing.T) {
+	inner := &fakeCharacterStore{characters: map[string]*models.Character{
+		"hero": {ID: "hero", CampaignID: "campaign1", Name: "Hero", Level: 1},
+	}}
+	characters := store.JournalCharacterStore(inner)
+	journal := store.NewJournal()
+	ctx := store.WithJournal(context.Background(), journal)
+
+	require.NoError(t, characters.Update(ctx, &models.Character{ID: "hero", CampaignID: "campaign1", Name: "Hero", Level: 2}))
+	require.NoError(t, characters.Update(ctx, &models.Character{ID: "hero", CampaignID: "campaign1", Name: "Hero", Level: 3}))
+	require.NoError(t, characters.Create(ctx, &models.Character{ID: "goblin", CampaignID: "campaign1", Name: "Goblin"}))
+
+	revisions := journal.Revisions()
+	require.Len(t, revisions, 2)
+
+	assert.Equal(t, store.EntityCharacter, revisions[0].Entity)
+	assert.Equal(t, "hero", revisions[0].ID)
+	assert.Equal(t, "campaign1", revisions[0].CampaignID)
+	assert.Equal(t, 1, characterState(t, revisions[0].Before).Level)
+	assert.Equal(t, 3, characterState(t, revisions[0].After).Level)
+
+	assert.Equal(t, "goblin", revisions[1].ID)
+	assert.Nil(t, revisions[1].Before, "created records have no previous state")
+	assert.Equal(t, "Goblin", characterState(t, revisions[1].After).Name)
+}
+
+func TestJournalCharacterStore_SkipsUnchangedAndTransientRecords(t *testing.T) {
+	inner := &fakeCharacterStore{characters: map[string]*models.Character{
+		"hero": {ID: "hero", CampaignID: "campaign1", Name: "Hero"},
+	}}
+	characters := store.JournalCharacterStore(inner)
+	journal := store.NewJournal()
+	ctx := store.WithJournal(context.Background(), journal)
+
+	require.NoError(t, characters.Update(ctx, &models.Character{ID: "hero", CampaignID: "campaign1", Name: "Hero"}))
+	require.NoError(t, characters.Create(ctx, &models.Character{ID: "summon", CampaignID: "campaign1"}))
+	require.NoError(t, characters.Delete(ctx, "summon"))
+
+	assert.Empty(t, journal.Revisions())
+}
+
+func TestJournalCharacterStore_RecordsDeletes(t *testing.T) {
+	inner := &fakeCharacterStore{characters: map[string]*models.Character{
+		"hero": {ID: "hero", CampaignID: "campaign1", Name: "Hero"},
+	}}
+	characters := store.JournalCharacterStore(inner)
+	journal := store.NewJournal()
+
+	require.NoError(t, characters.Delete(store.WithJournal(context.Background(), journal), "hero"))
+
+	revisions := journal.Revisions()
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "campaign1", revisions[0].CampaignID)
+	assert.Equal(t, "Hero", characterState(t, revisions[0].Before).Name)
+	assert.Nil(t, revisions[0].After)
+}
+
+func TestJournalCharacterStore_NoJournalPassesThrough(t *testing.T) {
+	inner := &fakeCharacterStore{characters: map[string]*models.Character{}}
+	characters := store.JournalCharacterStore(inner)
+
+	require.NoError(t, characters.Create(context.Background(), &models.Character{ID: "hero"}))
+	assert.Contains(t, inner.characters, "hero")
+	assert.Nil(t, store.JournalFromContext(context.Background()))
+}
@@ -0,0 +1,69 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dnd-mcp/server/internal/models"
+	"github.com/dnd-mcp/server/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCharacterStore implements the character store methods the wrapper calls
+type fakeCharacterStore struct {
+	store.CharacterStore
+	characters map[string]*models.Character
+	err        error
+}
+
+func (s *fakeCharacterStore) Get(ctx context.Context, id string) (*models.Character, error) {
+	if c, ok := s.characters[id]; ok {
+		return c, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (s *fakeCharacterStore) Update(ctx context.Context, character *models.Character) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.characters[character.ID] = character
+	return nil
+}
+
+func (s *fakeCharacterStore) Delete(ctx context.Context, id string) error {
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.characters, id)
+	return nil
+}
+
+func TestObserveCharacterStore_PublishesWrites(t *testing.T) {
+	inner := &fakeCharacterStore{characters: map[string]*models.Character{}}
+	feed := store.NewChangeFeed()
+	var changes []store.Change
+	feed.Subscribe(func(ctx context.Context, change store.Change) { changes = append(changes, change) })
+	characters := store.ObserveCharacterStore(inner, feed)
+
+	character := &models.Character{ID: "hero", CampaignID: "campaign1"}
+	assert.NoError(t, characters.Update(context.Background(), character))
+	assert.NoError(t, characters.Delete(context.Background(), "hero"))
+
+	assert.Equal(t, []store.Change{
+		{Entity: store.EntityCharacter, Op: store.ChangeUpdated, ID: "hero", CampaignID: "campaign1"},
+		{Entity: store.EntityCharacter, Op: store.ChangeDeleted, ID: "hero", CampaignID: "campaign1"},
+	}, changes)
+}
+
+func TestObserveCharacterStore_FailedWriteNotPublished(t *testing.T) {
+	inner := &fakeCharacterStore{characters: map[string]*models.Character{}, err: errors.New("disk full")}
+	feed := store.NewChangeFeed()
+	published := 0
+	feed.Subscribe(func(ctx context.Context, change store.Change) { published++ })
+	characters := store.ObserveCharacterStore(inner, feed)
+
+	assert.Error(t, characters.Update(context.Background(), &models.Character{ID: "hero", CampaignID: "campaign1"}))
+	assert.Equal(t, 0, published)
+}